@@ -67,6 +67,17 @@ func FakeSeal(act Envelope, pubk crypto.PublicKey) SealedEnvelope {
 	return sealed
 }
 
+// SealInBatch seals a sub-action of a batch, which has no signature of its own as the batch is signed by the sender
+// as a whole
+func SealInBatch(act Envelope, pubk crypto.PublicKey) SealedEnvelope {
+	sealed := SealedEnvelope{
+		Envelope:  act,
+		srcPubkey: pubk,
+	}
+	sealed.payload.SetEnvelopeContext(sealed)
+	return sealed
+}
+
 // AssembleSealedEnvelope assembles a SealedEnvelope use Envelope, Sender Address and Signature.
 // This method should be only used in tests.
 func AssembleSealedEnvelope(act Envelope, pk crypto.PublicKey, sig []byte) SealedEnvelope {
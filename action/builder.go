@@ -8,6 +8,7 @@ package action
 
 import (
 	"math/big"
+	"time"
 
 	"github.com/iotexproject/go-pkgs/crypto"
 
@@ -117,6 +118,12 @@ func (b *EnvelopeBuilder) SetGasPriceByBytes(buf []byte) *EnvelopeBuilder {
 	return b
 }
 
+// SetDeadline sets the deadline hint of the action for the Envelope Builder is building.
+func (b *EnvelopeBuilder) SetDeadline(t time.Time) *EnvelopeBuilder {
+	b.elp.deadline = t
+	return b
+}
+
 // SetAction sets the action payload for the Envelope Builder is building.
 func (b *EnvelopeBuilder) SetAction(action actionPayload) *EnvelopeBuilder {
 	b.elp.payload = action
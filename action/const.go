@@ -23,6 +23,8 @@ var (
 	ErrNonce = errors.New("invalid nonce")
 	// ErrBalance indicates the error of balance
 	ErrBalance = errors.New("invalid balance")
+	// ErrBalanceReservation indicates the action leaves the sender below the minimum balance reservation
+	ErrBalanceReservation = errors.New("balance below minimum reservation")
 	// ErrGasPrice indicates the error of gas price
 	ErrGasPrice = errors.New("invalid gas price")
 	// ErrVotee indicates the error of votee
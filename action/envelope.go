@@ -2,6 +2,7 @@ package action
 
 import (
 	"math/big"
+	"time"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/iotexproject/go-pkgs/hash"
@@ -19,6 +20,9 @@ type Envelope struct {
 	gasLimit uint64
 	payload  actionPayload
 	gasPrice *big.Int
+	// deadline is the hint of the sender that the action is useless after the time, which is not part of the action
+	// proto, so it is kept by the node receiving the action only
+	deadline time.Time
 }
 
 // Version returns the version
@@ -49,6 +53,17 @@ func (elp *Envelope) GasPrice() *big.Int {
 	return p.Set(elp.gasPrice)
 }
 
+// Deadline returns the deadline of the action, zero if the action has no deadline
+func (elp *Envelope) Deadline() time.Time { return elp.deadline }
+
+// SetDeadline sets the deadline of the action
+func (elp *Envelope) SetDeadline(t time.Time) { elp.deadline = t }
+
+// Expired returns true if the action has a deadline earlier than the time
+func (elp *Envelope) Expired(t time.Time) bool {
+	return !elp.deadline.IsZero() && t.After(elp.deadline)
+}
+
 // Cost returns cost of actions
 func (elp *Envelope) Cost() (*big.Int, error) {
 	return elp.payload.Cost()
@@ -17,6 +17,7 @@ import (
 	"github.com/iotexproject/iotex-address/address"
 	"github.com/iotexproject/iotex-core/action"
 	"github.com/iotexproject/iotex-core/action/protocol"
+	"github.com/iotexproject/iotex-core/config"
 	"github.com/iotexproject/iotex-core/pkg/log"
 	"github.com/iotexproject/iotex-core/state"
 )
@@ -77,6 +78,27 @@ func (p *Protocol) Validate(ctx context.Context, act action.Action, sr protocol.
 		if err := p.validateTransfer(ctx, act); err != nil {
 			return errors.Wrap(err, "error when validating transfer action")
 		}
+		bcCtx, ok := protocol.GetBlockchainCtx(ctx)
+		if !ok {
+			return nil
+		}
+		blkCtx, ok := protocol.GetBlockCtx(ctx)
+		if !ok {
+			return nil
+		}
+		hu := config.NewHeightUpgrade(&bcCtx.Genesis)
+		if hu.IsPre(config.Kamchatka, blkCtx.BlockHeight) {
+			return nil
+		}
+		if minBalance := bcCtx.Genesis.MinBalanceReservation(); minBalance.Sign() > 0 {
+			actionCtx, ok := protocol.GetActionCtx(ctx)
+			if !ok {
+				return errors.New("failed to get action context to validate balance reservation")
+			}
+			if err := validateReservation(sr, actionCtx.Caller, act, minBalance); err != nil {
+				return errors.Wrap(err, "error when validating transfer action")
+			}
+		}
 	}
 	return nil
 }
@@ -0,0 +1,82 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package account
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/iotexproject/iotex-address/address"
+	"github.com/pkg/errors"
+
+	"github.com/iotexproject/iotex-core/action"
+	"github.com/iotexproject/iotex-core/action/protocol"
+	accountutil "github.com/iotexproject/iotex-core/action/protocol/account/util"
+	"github.com/iotexproject/iotex-core/blockchain/genesis"
+	"github.com/iotexproject/iotex-core/config"
+)
+
+// ReservationValidator rejects the transfers leaving the sender below the minimum balance reservation before they
+// enter the actpool, since the reservation is enforced on the transfers in the blocks
+type ReservationValidator struct {
+	sr         protocol.StateReader
+	hu         config.HeightUpgrade
+	minBalance *big.Int
+}
+
+// NewReservationValidator constructs a new ReservationValidator
+func NewReservationValidator(sr protocol.StateReader, g *genesis.Genesis) *ReservationValidator {
+	return &ReservationValidator{
+		sr:         sr,
+		hu:         config.NewHeightUpgrade(g),
+		minBalance: g.MinBalanceReservation(),
+	}
+}
+
+// Validate validates the transfer against the minimum balance reservation
+func (v *ReservationValidator) Validate(_ context.Context, selp action.SealedEnvelope) error {
+	tsf, ok := selp.Action().(*action.Transfer)
+	if !ok || v.minBalance == nil || v.minBalance.Sign() == 0 {
+		return nil
+	}
+	// the transfer is run in the block next to the state
+	height, err := v.sr.Height()
+	if err != nil {
+		return err
+	}
+	if v.hu.IsPre(config.Kamchatka, height+1) {
+		return nil
+	}
+	caller, err := address.FromBytes(selp.SrcPubkey().Hash())
+	if err != nil {
+		return err
+	}
+	return validateReservation(v.sr, caller, tsf, v.minBalance)
+}
+
+// validateReservation returns ErrBalanceReservation if the balance of the sender after the amount and the gas fee of
+// the transfer is below the minimum balance reservation
+func validateReservation(sr protocol.StateReader, caller address.Address, tsf *action.Transfer, minBalance *big.Int) error {
+	sender, err := accountutil.AccountState(sr, caller.String())
+	if err != nil {
+		return errors.Wrapf(err, "invalid state of account %s", caller.String())
+	}
+	cost, err := tsf.Cost()
+	if err != nil {
+		return err
+	}
+	if left := new(big.Int).Sub(sender.Balance, cost); left.Cmp(minBalance) < 0 {
+		return errors.Wrapf(
+			action.ErrBalanceReservation,
+			"sender %s balance %s after the transfer is below the minimum reservation %s",
+			caller.String(),
+			left,
+			minBalance,
+		)
+	}
+	return nil
+}
@@ -41,6 +41,44 @@ func TestProtocol_ValidateTransfer(t *testing.T) {
 		require.NoError(err)
 		require.Equal(action.ErrActPool, errors.Cause(p.Validate(context.Background(), tsf, nil)))
 	})
+	t.Run("Min balance reservation", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		sm := testdb.NewMockStateManager(ctrl)
+		alfa := identityset.Address(28)
+		require.NoError(accountutil.StoreAccount(sm, alfa, &state.Account{
+			Balance: big.NewInt(100000),
+		}))
+		g := config.Default.Genesis
+		g.MinBalanceReservationStr = "50000"
+		g.KamchatkaBlockHeight = 10
+		ctx := protocol.WithBlockchainCtx(context.Background(), protocol.BlockchainCtx{Genesis: g})
+		ctx = protocol.WithActionCtx(ctx, protocol.ActionCtx{Caller: alfa})
+		ctx = protocol.WithBlockCtx(ctx, protocol.BlockCtx{BlockHeight: 10})
+
+		// the transfer leaves 100000 - 40000 - 10000 = 50000 to the sender
+		tsf, err := action.NewTransfer(uint64(1), big.NewInt(40000), identityset.Address(29).String(), nil, uint64(10000), big.NewInt(1))
+		require.NoError(err)
+		require.NoError(p.Validate(ctx, tsf, sm))
+		selp := action.FakeSeal((&action.EnvelopeBuilder{}).SetAction(tsf).SetGasPrice(big.NewInt(1)).Build(), identityset.PrivateKey(28).PublicKey())
+		v := NewReservationValidator(sm, &g)
+		require.NoError(v.Validate(context.Background(), selp))
+
+		tsf, err = action.NewTransfer(uint64(1), big.NewInt(40001), identityset.Address(29).String(), nil, uint64(10000), big.NewInt(1))
+		require.NoError(err)
+		require.Equal(action.ErrBalanceReservation, errors.Cause(p.Validate(ctx, tsf, sm)))
+		// no reservation before Kamchatka height
+		require.NoError(p.Validate(protocol.WithBlockCtx(ctx, protocol.BlockCtx{BlockHeight: 9}), tsf, sm))
+		selp = action.FakeSeal((&action.EnvelopeBuilder{}).SetAction(tsf).SetGasPrice(big.NewInt(1)).Build(), identityset.PrivateKey(28).PublicKey())
+		// the validator checks the transfer in the block next to the state of height 0
+		require.NoError(v.Validate(context.Background(), selp))
+		g.KamchatkaBlockHeight = 1
+		v = NewReservationValidator(sm, &g)
+		require.Equal(action.ErrBalanceReservation, errors.Cause(v.Validate(context.Background(), selp)))
+
+		// no reservation by default
+		require.NoError(NewReservationValidator(sm, &config.Default.Genesis).Validate(context.Background(), selp))
+	})
 }
 
 func TestProtocol_HandleTransfer(t *testing.T) {
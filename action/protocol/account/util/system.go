@@ -0,0 +1,119 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package accountutil
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/iotexproject/iotex-address/address"
+	"github.com/iotexproject/iotex-proto/golang/iotextypes"
+	"github.com/pkg/errors"
+
+	"github.com/iotexproject/iotex-core/action"
+	"github.com/iotexproject/iotex-core/action/protocol"
+	"github.com/iotexproject/iotex-core/config"
+)
+
+// helpers of the protocols handling the executions to their own addresses since Kamchatka height
+
+// IsSystemExecutionActive returns whether the executions to the protocol addresses are handled by the protocols at the
+// height of the block
+func IsSystemExecutionActive(ctx context.Context) bool {
+	bcCtx := protocol.MustGetBlockchainCtx(ctx)
+	blkCtx := protocol.MustGetBlockCtx(ctx)
+	hu := config.NewHeightUpgrade(&bcCtx.Genesis)
+	return hu.IsPost(config.Kamchatka, blkCtx.BlockHeight)
+}
+
+// SettleSystemExecution settles an execution to the protocol address at the intrinsic gas
+func SettleSystemExecution(
+	ctx context.Context,
+	sm protocol.StateManager,
+	depositGas protocol.DepositGas,
+	protocolAddr address.Address,
+	status uint64,
+	si int,
+	logs []*action.Log,
+	tLogs ...*action.TransactionLog,
+) (*action.Receipt, error) {
+	gas := protocol.MustGetActionCtx(ctx).IntrinsicGas
+	return SettleSystemExecutionWithGas(ctx, sm, depositGas, protocolAddr, status, si, gas, logs, tLogs...)
+}
+
+// SettleSystemExecutionWithGas settles an execution to the protocol address. It reverts the state to the snapshot si
+// if the execution fails, charges the gas fee and updates the nonce of the caller, and returns the receipt with the
+// logs of the execution followed by the log of the gas fee
+func SettleSystemExecutionWithGas(
+	ctx context.Context,
+	sm protocol.StateManager,
+	depositGas protocol.DepositGas,
+	protocolAddr address.Address,
+	status uint64,
+	si int,
+	gas uint64,
+	logs []*action.Log,
+	tLogs ...*action.TransactionLog,
+) (*action.Receipt, error) {
+	actionCtx := protocol.MustGetActionCtx(ctx)
+	blkCtx := protocol.MustGetBlockCtx(ctx)
+	if status != uint64(iotextypes.ReceiptStatus_Success) {
+		if err := sm.Revert(si); err != nil {
+			return nil, err
+		}
+	}
+	gasFee := new(big.Int).Mul(actionCtx.GasPrice, new(big.Int).SetUint64(gas))
+	depositLog, err := depositGas(ctx, sm, gasFee)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to deposit gas")
+	}
+	acc, err := LoadOrCreateAccount(sm, actionCtx.Caller.String())
+	if err != nil {
+		return nil, err
+	}
+	// TODO: this check shouldn't be necessary
+	if actionCtx.Nonce > acc.Nonce {
+		acc.Nonce = actionCtx.Nonce
+	}
+	if err := StoreAccount(sm, actionCtx.Caller, acc); err != nil {
+		return nil, errors.Wrap(err, "failed to update nonce")
+	}
+	r := action.Receipt{
+		Status:          status,
+		BlockHeight:     blkCtx.BlockHeight,
+		ActionHash:      actionCtx.ActionHash,
+		GasConsumed:     gas,
+		ContractAddress: protocolAddr.String(),
+	}
+	r.AddLogs(logs...).AddTransactionLogs(tLogs...).AddTransactionLogs(depositLog)
+	return &r, nil
+}
+
+// Transfer moves the amount from the sender account to the recipient account
+func Transfer(sm protocol.StateManager, sender, recipient address.Address, amount *big.Int) error {
+	if amount.Sign() == 0 {
+		return nil
+	}
+	from, err := LoadOrCreateAccount(sm, sender.String())
+	if err != nil {
+		return err
+	}
+	if err := from.SubBalance(amount); err != nil {
+		return errors.Wrapf(err, "failed to transfer from %s", sender.String())
+	}
+	if err := StoreAccount(sm, sender, from); err != nil {
+		return err
+	}
+	to, err := LoadOrCreateAccount(sm, recipient.String())
+	if err != nil {
+		return err
+	}
+	if err := to.AddBalance(amount); err != nil {
+		return err
+	}
+	return StoreAccount(sm, recipient, to)
+}
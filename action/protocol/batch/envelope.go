@@ -0,0 +1,84 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package batch
+
+import (
+	"encoding/binary"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/iotexproject/iotex-proto/golang/iotextypes"
+	"github.com/pkg/errors"
+
+	"github.com/iotexproject/iotex-core/action"
+)
+
+const (
+	_lengthSize = 4
+	// MaxActions is the max number of the sub-actions in an envelope
+	MaxActions = 16
+)
+
+var (
+	// ErrInvalidEnvelope indicates the data of the execution is not a valid envelope
+	ErrInvalidEnvelope = errors.New("invalid batch envelope")
+)
+
+// Envelope is the ordered list of the sub-actions from the sender of the batch, carried in the data of the execution
+// to the protocol address. The sub-actions share the nonce and the gas price of the execution, and are executed
+// atomically
+type Envelope struct {
+	Actions []action.Envelope
+}
+
+// Serialize serializes the envelope, each sub-action is the length in 4 bytes big endian followed by the sub-action
+// in ActionCore proto
+func (e *Envelope) Serialize() ([]byte, error) {
+	var data []byte
+	for i := range e.Actions {
+		b, err := proto.Marshal(e.Actions[i].Proto())
+		if err != nil {
+			return nil, err
+		}
+		length := make([]byte, _lengthSize)
+		binary.BigEndian.PutUint32(length, uint32(len(b)))
+		data = append(data, length...)
+		data = append(data, b...)
+	}
+	return data, nil
+}
+
+// Deserialize deserializes the envelope
+func (e *Envelope) Deserialize(data []byte) error {
+	e.Actions = nil
+	for len(data) > 0 {
+		if len(e.Actions) == MaxActions {
+			return errors.Wrapf(ErrInvalidEnvelope, "more than %d sub-actions", MaxActions)
+		}
+		if len(data) < _lengthSize {
+			return errors.Wrap(ErrInvalidEnvelope, "truncated length")
+		}
+		length := binary.BigEndian.Uint32(data[:_lengthSize])
+		data = data[_lengthSize:]
+		if uint64(len(data)) < uint64(length) {
+			return errors.Wrap(ErrInvalidEnvelope, "truncated sub-action")
+		}
+		pb := &iotextypes.ActionCore{}
+		if err := proto.Unmarshal(data[:length], pb); err != nil {
+			return errors.Wrap(ErrInvalidEnvelope, err.Error())
+		}
+		elp := action.Envelope{}
+		if err := elp.LoadProto(pb); err != nil {
+			return errors.Wrap(ErrInvalidEnvelope, err.Error())
+		}
+		e.Actions = append(e.Actions, elp)
+		data = data[length:]
+	}
+	if len(e.Actions) == 0 {
+		return errors.Wrap(ErrInvalidEnvelope, "no sub-action")
+	}
+	return nil
+}
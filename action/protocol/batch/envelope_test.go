@@ -0,0 +1,64 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package batch
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/action"
+	"github.com/iotexproject/iotex-core/test/identityset"
+)
+
+func TestEnvelope(t *testing.T) {
+	require := require.New(t)
+
+	bd := &action.EnvelopeBuilder{}
+	tsf, err := action.NewTransfer(1, big.NewInt(10), identityset.Address(2).String(), nil, 10000, big.NewInt(1))
+	require.NoError(err)
+	exec, err := action.NewExecution(identityset.Address(3).String(), 1, big.NewInt(0), 100000, big.NewInt(1), []byte{1, 2})
+	require.NoError(err)
+	e := &Envelope{
+		Actions: []action.Envelope{
+			bd.SetNonce(1).SetGasLimit(10000).SetGasPrice(big.NewInt(1)).SetAction(tsf).Build(),
+			bd.SetNonce(1).SetGasLimit(100000).SetGasPrice(big.NewInt(1)).SetAction(exec).Build(),
+		},
+	}
+	data, err := e.Serialize()
+	require.NoError(err)
+	e1 := &Envelope{}
+	require.NoError(e1.Deserialize(data))
+	require.Equal(len(e.Actions), len(e1.Actions))
+	for i := range e.Actions {
+		require.Equal(e.Actions[i].Hash(), e1.Actions[i].Hash())
+	}
+
+	// the sub-actions share the signature of the batch
+	selp := action.SealInBatch(e1.Actions[1], identityset.PrivateKey(1).PublicKey())
+	require.Equal(identityset.PrivateKey(1).PublicKey(), selp.SrcPubkey())
+	require.Equal(uint64(100000), selp.Action().(*action.Execution).GasLimit())
+
+	// invalid envelopes
+	for _, d := range [][]byte{
+		nil,
+		data[:2],
+		data[:len(data)-1],
+		append(append([]byte{}, data...), 0, 0, 0, 1, 0xff),
+	} {
+		require.Equal(ErrInvalidEnvelope, errors.Cause(e1.Deserialize(d)))
+	}
+	tooMany := &Envelope{}
+	for i := 0; i <= MaxActions; i++ {
+		tooMany.Actions = append(tooMany.Actions, e.Actions[0])
+	}
+	data, err = tooMany.Serialize()
+	require.NoError(err)
+	require.Equal(ErrInvalidEnvelope, errors.Cause(e1.Deserialize(data)))
+}
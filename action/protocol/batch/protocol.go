@@ -0,0 +1,272 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package batch
+
+import (
+	"context"
+
+	"github.com/iotexproject/go-pkgs/hash"
+	"github.com/iotexproject/iotex-address/address"
+	"github.com/iotexproject/iotex-proto/golang/iotextypes"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/iotexproject/iotex-core/action"
+	"github.com/iotexproject/iotex-core/action/protocol"
+	accountutil "github.com/iotexproject/iotex-core/action/protocol/account/util"
+	"github.com/iotexproject/iotex-core/pkg/log"
+)
+
+// TODO: it works only for one instance per protocol definition now
+const protocolID = "batch"
+
+type (
+	// Protocol executes the sub-actions in the envelope carried by the execution to the protocol address atomically.
+	// If any sub-action fails, the states are reverted and the sender pays the gas consumed until the failure
+	Protocol struct {
+		addr       address.Address
+		depositGas protocol.DepositGas
+	}
+)
+
+// NewProtocol instantiates the protocol of action batch
+func NewProtocol(depositGas protocol.DepositGas) *Protocol {
+	h := hash.Hash160b([]byte(protocolID))
+	addr, err := address.FromBytes(h[:])
+	if err != nil {
+		log.L().Panic("Error when constructing the address of batch protocol", zap.Error(err))
+	}
+	return &Protocol{
+		addr:       addr,
+		depositGas: depositGas,
+	}
+}
+
+// EnvelopeOf returns the envelope carried by the action, if it is an execution to the protocol address
+func EnvelopeOf(act action.Action) (*Envelope, bool) {
+	exec, ok := act.(*action.Execution)
+	if !ok {
+		return nil, false
+	}
+	h := hash.Hash160b([]byte(protocolID))
+	addr, err := address.FromBytes(h[:])
+	if err != nil || exec.Contract() != addr.String() {
+		return nil, false
+	}
+	e := &Envelope{}
+	if err := e.Deserialize(exec.Data()); err != nil {
+		return nil, false
+	}
+	return e, true
+}
+
+// Address returns the address of the protocol, which is the contract of the executions carrying the envelopes
+func (p *Protocol) Address() address.Address {
+	return p.addr
+}
+
+// Handle handles the envelope of the sub-actions
+func (p *Protocol) Handle(ctx context.Context, act action.Action, sm protocol.StateManager) (*action.Receipt, error) {
+	exec, ok := act.(*action.Execution)
+	if !ok || exec.Contract() != p.addr.String() || !accountutil.IsSystemExecutionActive(ctx) {
+		return nil, nil
+	}
+	actionCtx := protocol.MustGetActionCtx(ctx)
+	si := sm.Snapshot()
+	gasConsumed := actionCtx.IntrinsicGas
+	selps, err := p.subActions(ctx, exec)
+	if err != nil {
+		log.L().Debug("Error when loading batch envelope", zap.Error(err))
+		return p.settleAction(ctx, sm, uint64(iotextypes.ReceiptStatus_Failure), si, gasConsumed, nil)
+	}
+	receipt := &action.Receipt{}
+	for _, selp := range selps {
+		r, err := p.handleSubAction(ctx, sm, selp)
+		if err != nil {
+			log.L().Debug("Error when handling sub-action", zap.Error(err))
+			return p.settleAction(ctx, sm, uint64(iotextypes.ReceiptStatus_Failure), si, gasConsumed, nil)
+		}
+		gasConsumed += r.GasConsumed
+		if r.Status != uint64(iotextypes.ReceiptStatus_Success) {
+			return p.settleAction(ctx, sm, r.Status, si, gasConsumed, r)
+		}
+		for _, l := range r.Logs() {
+			l.Index = uint(len(receipt.Logs()))
+			receipt.AddLogs(l)
+		}
+		receipt.AddTransactionLogs(r.TransactionLogs()...)
+	}
+	return p.settleAction(ctx, sm, uint64(iotextypes.ReceiptStatus_Success), si, gasConsumed, receipt)
+}
+
+// Validate validates the envelope of the sub-actions, and the sub-actions by the validators of the protocols
+// registered, as if they were sent by the sender of the batch
+func (p *Protocol) Validate(ctx context.Context, act action.Action, sr protocol.StateReader) error {
+	exec, ok := act.(*action.Execution)
+	if !ok || exec.Contract() != p.addr.String() || !accountutil.IsSystemExecutionActive(ctx) {
+		return nil
+	}
+	selps, err := p.subActions(ctx, exec)
+	if err != nil {
+		return err
+	}
+	reg := protocol.MustGetRegistry(ctx)
+	for _, selp := range selps {
+		subCtx, err := withSubActionCtx(ctx, selp)
+		if err != nil {
+			return err
+		}
+		for _, v := range reg.All() {
+			if v == p {
+				continue
+			}
+			validator, ok := v.(protocol.ActionValidator)
+			if !ok {
+				continue
+			}
+			if err := validator.Validate(subCtx, selp.Action(), sr); err != nil {
+				return errors.Wrapf(err, "invalid sub-action %T", selp.Action())
+			}
+		}
+	}
+	return nil
+}
+
+// ReadState is not supported by the protocol
+func (p *Protocol) ReadState(context.Context, protocol.StateReader, []byte, ...[]byte) ([]byte, uint64, error) {
+	return nil, uint64(0), protocol.ErrUnimplemented
+}
+
+// Register registers the protocol with a unique ID
+func (p *Protocol) Register(r *protocol.Registry) error {
+	return r.Register(protocolID, p)
+}
+
+// ForceRegister registers the protocol with a unique ID and force replacing the previous protocol if it exists
+func (p *Protocol) ForceRegister(r *protocol.Registry) error {
+	return r.ForceRegister(protocolID, p)
+}
+
+// Name returns the name of protocol
+func (p *Protocol) Name() string {
+	return protocolID
+}
+
+// subActions returns the sub-actions in the envelope sealed with the public key of the sender
+func (p *Protocol) subActions(ctx context.Context, exec *action.Execution) ([]action.SealedEnvelope, error) {
+	if exec.Amount() != nil && exec.Amount().Sign() != 0 {
+		return nil, errors.Wrap(ErrInvalidEnvelope, "batch does not accept amount")
+	}
+	e := &Envelope{}
+	if err := e.Deserialize(exec.Data()); err != nil {
+		return nil, err
+	}
+	gasLimit, err := exec.IntrinsicGas()
+	if err != nil {
+		return nil, err
+	}
+	selps := make([]action.SealedEnvelope, 0, len(e.Actions))
+	for _, elp := range e.Actions {
+		if elp.Nonce() != exec.Nonce() {
+			return nil, errors.Wrapf(ErrInvalidEnvelope, "sub-action nonce %d is not the batch nonce", elp.Nonce())
+		}
+		if elp.GasPrice().Cmp(exec.GasPrice()) != 0 {
+			return nil, errors.Wrapf(ErrInvalidEnvelope, "sub-action gas price %s is not the batch gas price", elp.GasPrice().String())
+		}
+		switch sub := elp.Action().(type) {
+		case *action.GrantReward, *action.PutPollResult:
+			return nil, errors.Wrapf(ErrInvalidEnvelope, "system action %T in batch", sub)
+		case *action.Execution:
+			if sub.Contract() == p.addr.String() {
+				return nil, errors.Wrap(ErrInvalidEnvelope, "nested batch")
+			}
+		}
+		selp := action.SealInBatch(elp, exec.SrcPubkey())
+		if err := selp.Action().SanityCheck(); err != nil {
+			return nil, err
+		}
+		gasLimit += selp.GasLimit()
+		selps = append(selps, selp)
+	}
+	if gasLimit > exec.GasLimit() {
+		return nil, errors.Wrapf(action.ErrHitGasLimit, "gas limit %d of the sub-actions exceeds the batch gas limit %d", gasLimit, exec.GasLimit())
+	}
+	return selps, nil
+}
+
+// handleSubAction handles the sub-action by the protocols registered, in the context of the batch
+func (p *Protocol) handleSubAction(
+	ctx context.Context,
+	sm protocol.StateManager,
+	selp action.SealedEnvelope,
+) (*action.Receipt, error) {
+	ctx, err := withSubActionCtx(ctx, selp)
+	if err != nil {
+		return nil, err
+	}
+	for _, h := range protocol.MustGetRegistry(ctx).All() {
+		if h == p {
+			continue
+		}
+		receipt, err := h.Handle(ctx, selp.Action(), sm)
+		if err != nil {
+			return nil, err
+		}
+		if receipt != nil {
+			return receipt, nil
+		}
+	}
+	return nil, errors.Errorf("no protocol handles sub-action %T", selp.Action())
+}
+
+// withSubActionCtx returns the context of the sub-action, which is sent by the sender of the batch in the same action
+func withSubActionCtx(ctx context.Context, selp action.SealedEnvelope) (context.Context, error) {
+	actionCtx := protocol.MustGetActionCtx(ctx)
+	intrinsicGas, err := selp.IntrinsicGas()
+	if err != nil {
+		return nil, err
+	}
+	return protocol.WithActionCtx(ctx, protocol.ActionCtx{
+		Caller:       actionCtx.Caller,
+		ActionHash:   actionCtx.ActionHash,
+		GasPrice:     selp.GasPrice(),
+		IntrinsicGas: intrinsicGas,
+		Nonce:        selp.Nonce(),
+	}), nil
+}
+
+// settleAction charges the gas consumed by the sub-actions if the batch fails, since their states are reverted, or the
+// intrinsic gas of the envelope on success, while the sub-actions are charged by their own protocols
+func (p *Protocol) settleAction(
+	ctx context.Context,
+	sm protocol.StateManager,
+	status uint64,
+	si int,
+	gasConsumed uint64,
+	logs *action.Receipt,
+) (*action.Receipt, error) {
+	gas := protocol.MustGetActionCtx(ctx).IntrinsicGas
+	if status != uint64(iotextypes.ReceiptStatus_Success) {
+		gas = gasConsumed
+	}
+	var (
+		receiptLogs []*action.Log
+		tLogs       []*action.TransactionLog
+	)
+	if logs != nil && status == uint64(iotextypes.ReceiptStatus_Success) {
+		receiptLogs, tLogs = logs.Logs(), logs.TransactionLogs()
+	}
+	r, err := accountutil.SettleSystemExecutionWithGas(ctx, sm, p.depositGas, p.addr, status, si, gas, receiptLogs, tLogs...)
+	if err != nil {
+		return nil, err
+	}
+	r.GasConsumed = gasConsumed
+	if logs != nil {
+		r.SetExecutionRevertMsg(logs.ExecutionRevertMsg())
+	}
+	return r, nil
+}
@@ -0,0 +1,61 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package batch
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/iotexproject/go-pkgs/hash"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/action"
+	"github.com/iotexproject/iotex-core/action/protocol"
+	"github.com/iotexproject/iotex-core/action/protocol/execution"
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/test/identityset"
+)
+
+func TestValidateSubActions(t *testing.T) {
+	require := require.New(t)
+
+	p := NewProtocol(nil)
+	registry := protocol.NewRegistry()
+	require.NoError(p.Register(registry))
+	ep := execution.NewProtocol(func(uint64) (hash.Hash256, error) { return hash.ZeroHash256, nil }, nil)
+	require.NoError(ep.Register(registry))
+	g := config.Default.Genesis
+	g.KamchatkaBlockHeight = 1
+	g.ContractDeployerAllowlist = []string{identityset.Address(1).String()}
+	ctx := protocol.WithBlockchainCtx(protocol.WithRegistry(context.Background(), registry), protocol.BlockchainCtx{Genesis: g})
+	ctx = protocol.WithBlockCtx(ctx, protocol.BlockCtx{BlockHeight: 1})
+
+	deploy, err := action.NewExecution(action.EmptyAddress, 1, big.NewInt(0), 100000, big.NewInt(0), []byte{1})
+	require.NoError(err)
+	bd := &action.EnvelopeBuilder{}
+	e := &Envelope{
+		Actions: []action.Envelope{
+			bd.SetNonce(1).SetGasLimit(100000).SetGasPrice(big.NewInt(0)).SetAction(deploy).Build(),
+		},
+	}
+	data, err := e.Serialize()
+	require.NoError(err)
+	exec, err := action.NewExecution(p.Address().String(), 1, big.NewInt(0), 1000000, big.NewInt(0), data)
+	require.NoError(err)
+	validate := func(sender int) error {
+		elp := (&action.EnvelopeBuilder{}).SetNonce(1).SetGasLimit(1000000).SetGasPrice(big.NewInt(0)).SetAction(exec).Build()
+		selp := action.FakeSeal(elp, identityset.PrivateKey(sender).PublicKey())
+		ctx := protocol.WithActionCtx(ctx, protocol.ActionCtx{Caller: identityset.Address(sender)})
+		return p.Validate(ctx, selp.Action(), nil)
+	}
+
+	// the deployer allowlist applies to the contract creation in the batch
+	require.NoError(validate(1))
+	require.Equal(action.ErrAddress, errors.Cause(validate(2)))
+}
@@ -26,6 +26,8 @@ type (
 
 	registryContextKey struct{}
 
+	deferredDepositsContextKey struct{}
+
 	// TipInfo contains the tip block information
 	TipInfo struct {
 		Height    uint64
@@ -66,6 +68,14 @@ type (
 		// Nonce is the nonce of the action
 		Nonce uint64
 	}
+
+	// DeferredDeposits buffers the deposits into the rewarding fund made by an action which is run speculatively
+	// together with other actions, so that the actions do not conflict on the fund. The deposits are credited into
+	// the fund after the action is accepted
+	DeferredDeposits struct {
+		amount *big.Int
+		credit func(context.Context, StateManager, *big.Int) error
+	}
 )
 
 // WithRegistry adds registry to context
@@ -150,3 +160,36 @@ func MustGetActionCtx(ctx context.Context) ActionCtx {
 	}
 	return ac
 }
+
+// WithDeferredDeposits adds DeferredDeposits into context
+func WithDeferredDeposits(ctx context.Context, dd *DeferredDeposits) context.Context {
+	return context.WithValue(ctx, deferredDepositsContextKey{}, dd)
+}
+
+// GetDeferredDeposits gets DeferredDeposits
+func GetDeferredDeposits(ctx context.Context) (*DeferredDeposits, bool) {
+	dd, ok := ctx.Value(deferredDepositsContextKey{}).(*DeferredDeposits)
+	return dd, ok
+}
+
+// Defer buffers the amount to be credited into the fund by the credit function
+func (dd *DeferredDeposits) Defer(amount *big.Int, credit func(context.Context, StateManager, *big.Int) error) {
+	if dd.amount == nil {
+		dd.amount = big.NewInt(0)
+	}
+	dd.amount.Add(dd.amount, amount)
+	dd.credit = credit
+}
+
+// Pending returns whether there is any amount not credited yet
+func (dd *DeferredDeposits) Pending() bool {
+	return dd.amount != nil && dd.amount.Sign() > 0
+}
+
+// Settle credits the buffered amount into the fund
+func (dd *DeferredDeposits) Settle(ctx context.Context, sm StateManager) error {
+	if dd.credit == nil || !dd.Pending() {
+		return nil
+	}
+	return dd.credit(ctx, sm, dd.amount)
+}
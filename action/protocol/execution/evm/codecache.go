@@ -0,0 +1,51 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package evm
+
+import (
+	"github.com/iotexproject/go-pkgs/cache"
+	"github.com/iotexproject/go-pkgs/hash"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var codeCacheMtc = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "iotex_evm_code_cache",
+		Help: "IoTeX EVM code cache counter.",
+	},
+	[]string{"result"},
+)
+
+func init() {
+	prometheus.MustRegister(codeCacheMtc)
+}
+
+// CodeCache caches contract byte-code by code hash. Code is content-addressed, so the cache can be shared by all
+// executions across blocks, saving the trie lookups of hot contracts
+type CodeCache struct {
+	lru *cache.ThreadSafeLruCache
+}
+
+// NewCodeCache creates a code cache holding at most size contracts
+func NewCodeCache(size int) *CodeCache {
+	return &CodeCache{lru: cache.NewThreadSafeLruCache(size)}
+}
+
+// Get returns the code of code hash
+func (c *CodeCache) Get(codeHash hash.Hash256) ([]byte, bool) {
+	if data, ok := c.lru.Get(codeHash); ok {
+		codeCacheMtc.WithLabelValues("hit").Inc()
+		return data.([]byte), true
+	}
+	codeCacheMtc.WithLabelValues("miss").Inc()
+	return nil, false
+}
+
+// Put adds the code of code hash into cache
+func (c *CodeCache) Put(codeHash hash.Hash256, code []byte) {
+	c.lru.Add(codeHash, code)
+}
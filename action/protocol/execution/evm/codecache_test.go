@@ -0,0 +1,39 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package evm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/go-pkgs/hash"
+)
+
+func TestCodeCache(t *testing.T) {
+	require := require.New(t)
+
+	c := NewCodeCache(2)
+	code1, code2, code3 := []byte("code1"), []byte("code2"), []byte("code3")
+	h1, h2, h3 := hash.Hash256b(code1), hash.Hash256b(code2), hash.Hash256b(code3)
+	_, ok := c.Get(h1)
+	require.False(ok)
+
+	c.Put(h1, code1)
+	c.Put(h2, code2)
+	code, ok := c.Get(h1)
+	require.True(ok)
+	require.Equal(code1, code)
+
+	// least recently used code is evicted
+	c.Put(h3, code3)
+	_, ok = c.Get(h2)
+	require.False(ok)
+	code, ok = c.Get(h3)
+	require.True(ok)
+	require.Equal(code3, code)
+}
@@ -175,6 +175,7 @@ func ExecuteContract(
 	execution *action.Execution,
 	getBlockHash GetBlockHash,
 	depositGasFunc DepositGas,
+	opts ...StateDBOption,
 ) ([]byte, *action.Receipt, error) {
 	actionCtx := protocol.MustGetActionCtx(ctx)
 	blkCtx := protocol.MustGetBlockCtx(ctx)
@@ -186,6 +187,7 @@ func ExecuteContract(
 		hu.IsPre(config.Aleutian, blkCtx.BlockHeight),
 		hu.IsPost(config.Greenland, blkCtx.BlockHeight),
 		execution.Hash(),
+		opts...,
 	)
 	ps, err := newParams(ctx, execution, stateDB, getBlockHash)
 	if err != nil {
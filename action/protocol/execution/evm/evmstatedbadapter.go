@@ -64,12 +64,21 @@ type (
 		preimageSnapshot   map[int]preimageMap
 		notFixTopicCopyBug bool
 		asyncContractTrie  bool
+		codeCache          *CodeCache
 	}
 )
 
 // StateDBOption set StateDBAdapter construction param
 type StateDBOption func(*StateDBAdapter) error
 
+// CodeCacheOption sets the code cache shared across executions
+func CodeCacheOption(c *CodeCache) StateDBOption {
+	return func(adapter *StateDBAdapter) error {
+		adapter.codeCache = c
+		return nil
+	}
+}
+
 // NewStateDBAdapter creates a new state db with iotex blockchain
 func NewStateDBAdapter(
 	sm protocol.StateManager,
@@ -555,12 +564,21 @@ func (stateDB *StateDBAdapter) GetCode(evmAddr common.Address) []byte {
 		log.L().Error("Failed to load account state for address.", log.Hex("addrHash", addr[:]))
 		return nil
 	}
+	codeHash := hash.BytesToHash256(account.CodeHash)
+	if stateDB.codeCache != nil {
+		if code, ok := stateDB.codeCache.Get(codeHash); ok {
+			return code
+		}
+	}
 	var code SerializableBytes
 	if _, err = stateDB.sm.State(&code, protocol.NamespaceOption(CodeKVNameSpace), protocol.KeyOption(account.CodeHash[:])); err != nil {
 		// TODO: Suppress the as it's too much now
 		//log.L().Error("Failed to get code from trie.", zap.Error(err))
 		return nil
 	}
+	if stateDB.codeCache != nil {
+		stateDB.codeCache.Put(codeHash, code[:])
+	}
 	return code[:]
 }
 
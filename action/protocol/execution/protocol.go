@@ -8,6 +8,7 @@ package execution
 
 import (
 	"context"
+	"math/big"
 
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
@@ -17,6 +18,7 @@ import (
 	"github.com/iotexproject/iotex-core/action"
 	"github.com/iotexproject/iotex-core/action/protocol"
 	"github.com/iotexproject/iotex-core/action/protocol/execution/evm"
+	"github.com/iotexproject/iotex-core/action/protocol/subsidy"
 	"github.com/iotexproject/iotex-core/pkg/log"
 )
 
@@ -25,6 +27,8 @@ const (
 	ExecutionSizeLimit = 32 * 1024
 	// TODO: it works only for one instance per protocol definition now
 	protocolID = "smart_contract"
+	// _codeCacheSize is the number of contracts whose code is cached
+	_codeCacheSize = 1024
 )
 
 // Protocol defines the protocol of handling executions
@@ -32,6 +36,7 @@ type Protocol struct {
 	getBlockHash evm.GetBlockHash
 	depositGas   evm.DepositGas
 	addr         address.Address
+	codeCache    *evm.CodeCache
 }
 
 // NewProtocol instantiates the protocol of exeuction
@@ -41,7 +46,12 @@ func NewProtocol(getBlockHash evm.GetBlockHash, depostGas evm.DepositGas) *Proto
 	if err != nil {
 		log.L().Panic("Error when constructing the address of vote protocol", zap.Error(err))
 	}
-	return &Protocol{getBlockHash: getBlockHash, depositGas: depostGas, addr: addr}
+	return &Protocol{
+		getBlockHash: getBlockHash,
+		depositGas:   depostGas,
+		addr:         addr,
+		codeCache:    evm.NewCodeCache(_codeCacheSize),
+	}
 }
 
 // FindProtocol finds the registered protocol from registry
@@ -66,17 +76,36 @@ func (p *Protocol) Handle(ctx context.Context, act action.Action, sm protocol.St
 	if !ok {
 		return nil, nil
 	}
-	_, receipt, err := evm.ExecuteContract(ctx, sm, exec, p.getBlockHash, p.depositGas)
+	// the gas fee of the calls to the subsidized contracts is credited from the subsidy
+	var (
+		registry, _ = protocol.GetRegistry(ctx)
+		sp          = subsidy.FindProtocol(registry)
+		credit      *big.Int
+		err         error
+	)
+	if sp != nil {
+		if credit, err = sp.Prefund(ctx, sm, exec); err != nil {
+			return nil, errors.Wrap(err, "failed to prefund gas from subsidy")
+		}
+	}
+	_, receipt, err := evm.ExecuteContract(ctx, sm, exec, p.getBlockHash, p.depositGas, evm.CodeCacheOption(p.codeCache))
 
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to execute contract")
 	}
+	if credit != nil {
+		subsidyLog, err := sp.Settle(ctx, sm, exec, credit, receipt.GasConsumed)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to settle gas subsidy")
+		}
+		receipt.AddTransactionLogs(subsidyLog)
+	}
 
 	return receipt, nil
 }
 
 // Validate validates an execution
-func (p *Protocol) Validate(_ context.Context, act action.Action, _ protocol.StateReader) error {
+func (p *Protocol) Validate(ctx context.Context, act action.Action, _ protocol.StateReader) error {
 	exec, ok := act.(*action.Execution)
 	if !ok {
 		return nil
@@ -85,9 +114,31 @@ func (p *Protocol) Validate(_ context.Context, act action.Action, _ protocol.Sta
 	if exec.TotalSize() > ExecutionSizeLimit {
 		return errors.Wrap(action.ErrActPool, "oversized data")
 	}
+	// Reject contract creation of the deployers not in the allowlist
+	if exec.Contract() == action.EmptyAddress {
+		return validateDeployer(ctx)
+	}
 	return nil
 }
 
+func validateDeployer(ctx context.Context) error {
+	bcCtx, ok := protocol.GetBlockchainCtx(ctx)
+	if !ok || len(bcCtx.Genesis.ContractDeployerAllowlist) == 0 {
+		return nil
+	}
+	actionCtx, ok := protocol.GetActionCtx(ctx)
+	if !ok {
+		return errors.New("failed to get action context to validate contract deployer")
+	}
+	deployer := actionCtx.Caller.String()
+	for _, addr := range bcCtx.Genesis.ContractDeployerAllowlist {
+		if addr == deployer {
+			return nil
+		}
+	}
+	return errors.Wrapf(action.ErrAddress, "%s is not allowed to deploy contracts", deployer)
+}
+
 // ReadState read the state on blockchain via protocol
 func (p *Protocol) ReadState(context.Context, protocol.StateReader, []byte, ...[]byte) ([]byte, uint64, error) {
 	return nil, uint64(0), protocol.ErrUnimplemented
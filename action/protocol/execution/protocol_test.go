@@ -529,6 +529,25 @@ func TestProtocol_Validate(t *testing.T) {
 		require.NoError(err)
 		require.Equal(action.ErrActPool, errors.Cause(p.Validate(context.Background(), ex, nil)))
 	})
+
+	t.Run("Contract deployer allowlist", func(t *testing.T) {
+		g := config.Default.Genesis
+		g.ContractDeployerAllowlist = []string{identityset.Address(27).String()}
+		ctx := protocol.WithBlockchainCtx(context.Background(), protocol.BlockchainCtx{Genesis: g})
+		deploy, err := action.NewExecution(action.EmptyAddress, uint64(1), big.NewInt(0), uint64(0), big.NewInt(0), []byte{})
+		require.NoError(err)
+		call, err := action.NewExecution(identityset.Address(28).String(), uint64(1), big.NewInt(0), uint64(0), big.NewInt(0), []byte{})
+		require.NoError(err)
+
+		allowed := protocol.WithActionCtx(ctx, protocol.ActionCtx{Caller: identityset.Address(27)})
+		require.NoError(p.Validate(allowed, deploy, nil))
+		denied := protocol.WithActionCtx(ctx, protocol.ActionCtx{Caller: identityset.Address(29)})
+		require.Equal(action.ErrAddress, errors.Cause(p.Validate(denied, deploy, nil)))
+		// calling the contracts is not restricted
+		require.NoError(p.Validate(denied, call, nil))
+		// any address deploys contracts without the allowlist
+		require.NoError(p.Validate(protocol.WithActionCtx(context.Background(), protocol.ActionCtx{Caller: identityset.Address(29)}), deploy, nil))
+	})
 }
 
 func TestProtocol_Handle(t *testing.T) {
@@ -0,0 +1,156 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package insurance
+
+import (
+	"encoding/json"
+	"math/big"
+
+	"github.com/iotexproject/iotex-address/address"
+	"github.com/pkg/errors"
+)
+
+const (
+	_addressLength = 20
+	_amountLength  = 32
+	_opLength      = 1
+)
+
+// operations of the insurance pools, the first byte of the data of the execution to the protocol address
+const (
+	// OpJoin opts the caller in the insurance by creating its pool, depositing the amount of the execution. The caller
+	// is the operator address of the delegate, which is the address on the probation list. Data is op
+	OpJoin byte = iota + 1
+	// OpContribute contributes the amount of the execution to the pool of the delegate. Data is op || delegate
+	// (20 bytes)
+	OpContribute
+	// OpWithdraw withdraws from the pool of the caller, which is refused while the caller is on probation. Data is
+	// op || amount (32 bytes)
+	OpWithdraw
+)
+
+var (
+	// ErrInvalidOperation indicates the data of the execution is not a valid operation
+	ErrInvalidOperation = errors.New("invalid insurance operation")
+	// ErrOnProbation indicates the delegate is on the probation list of the current or next epoch
+	ErrOnProbation = errors.New("delegate is on probation")
+)
+
+type (
+	// Pool is the fund contributed to a delegate, which compensates the voters of the delegate when it is on probation
+	Pool struct {
+		// Balance is the fund left to compensate the voters
+		Balance *big.Int
+		// Paid is the total amount compensated to the voters
+		Paid *big.Int
+	}
+
+	// Operation is an operation on the insurance pools
+	Operation struct {
+		Op byte
+		// Delegate is the delegate of the pool OpContribute contributes to
+		Delegate address.Address
+		// Amount is the amount of OpWithdraw
+		Amount *big.Int
+	}
+
+	// payout is the compensation paid from the pool of a delegate to its voters in a block
+	payout struct {
+		Delegate string
+		Amount   *big.Int
+		EpochNum uint64
+	}
+
+	// payouts are the compensations paid in a block, buffered in the working set till the system logs are created
+	payouts struct {
+		Payouts []*payout
+	}
+)
+
+// Serialize serializes the pool
+func (p *Pool) Serialize() ([]byte, error) {
+	data := make([]byte, 0, 2*_amountLength)
+	data = append(data, amountBytes(p.Balance)...)
+	return append(data, amountBytes(p.Paid)...), nil
+}
+
+// Deserialize deserializes the pool
+func (p *Pool) Deserialize(data []byte) error {
+	if len(data) != 2*_amountLength {
+		return errors.Errorf("invalid pool length %d", len(data))
+	}
+	p.Balance = new(big.Int).SetBytes(data[:_amountLength])
+	p.Paid = new(big.Int).SetBytes(data[_amountLength:])
+	return nil
+}
+
+// Serialize serializes the operation into the data of the execution to the protocol address
+func (o *Operation) Serialize() []byte {
+	data := []byte{o.Op}
+	switch o.Op {
+	case OpContribute:
+		data = append(data, o.Delegate.Bytes()...)
+	case OpWithdraw:
+		data = append(data, amountBytes(o.Amount)...)
+	}
+	return data
+}
+
+// Deserialize deserializes the operation from the data of the execution to the protocol address
+func (o *Operation) Deserialize(data []byte) error {
+	if len(data) < _opLength {
+		return errors.Wrapf(ErrInvalidOperation, "invalid data length %d", len(data))
+	}
+	o.Op = data[0]
+	length := _opLength
+	switch o.Op {
+	case OpJoin:
+	case OpContribute:
+		length += _addressLength
+	case OpWithdraw:
+		length += _amountLength
+	default:
+		return errors.Wrapf(ErrInvalidOperation, "unknown operation %d", o.Op)
+	}
+	if len(data) != length {
+		return errors.Wrapf(ErrInvalidOperation, "invalid data length %d of operation %d", len(data), o.Op)
+	}
+	o.Delegate = nil
+	o.Amount = nil
+	switch o.Op {
+	case OpContribute:
+		delegate, err := address.FromBytes(data[_opLength:])
+		if err != nil {
+			return errors.Wrap(ErrInvalidOperation, err.Error())
+		}
+		o.Delegate = delegate
+	case OpWithdraw:
+		o.Amount = new(big.Int).SetBytes(data[_opLength:])
+	}
+	return nil
+}
+
+// Serialize serializes the payouts
+func (ps *payouts) Serialize() ([]byte, error) {
+	return json.Marshal(ps)
+}
+
+// Deserialize deserializes the payouts
+func (ps *payouts) Deserialize(data []byte) error {
+	return json.Unmarshal(data, ps)
+}
+
+// amountBytes returns the amount in 32 bytes big endian
+func amountBytes(amount *big.Int) []byte {
+	b := make([]byte, _amountLength)
+	if amount == nil {
+		return b
+	}
+	v := amount.Bytes()
+	copy(b[_amountLength-len(v):], v)
+	return b
+}
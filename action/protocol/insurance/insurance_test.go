@@ -0,0 +1,76 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package insurance
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/test/identityset"
+)
+
+func TestPoolSerialization(t *testing.T) {
+	require := require.New(t)
+
+	p := &Pool{
+		Balance: big.NewInt(1000000),
+		Paid:    big.NewInt(1000),
+	}
+	data, err := p.Serialize()
+	require.NoError(err)
+	p1 := &Pool{}
+	require.NoError(p1.Deserialize(data))
+	require.Equal(p.Balance, p1.Balance)
+	require.Equal(p.Paid, p1.Paid)
+	require.Error(p1.Deserialize(data[1:]))
+
+	ps := &payouts{Payouts: []*payout{{
+		Delegate: identityset.Address(1).String(),
+		Amount:   big.NewInt(100),
+		EpochNum: 3,
+	}}}
+	data, err = ps.Serialize()
+	require.NoError(err)
+	ps1 := &payouts{}
+	require.NoError(ps1.Deserialize(data))
+	require.Equal(ps, ps1)
+}
+
+func TestOperation(t *testing.T) {
+	require := require.New(t)
+
+	for _, op := range []*Operation{
+		{Op: OpJoin},
+		{Op: OpContribute, Delegate: identityset.Address(2)},
+		{Op: OpWithdraw, Amount: big.NewInt(500)},
+	} {
+		op1 := &Operation{}
+		require.NoError(op1.Deserialize(op.Serialize()))
+		require.Equal(op.Op, op1.Op)
+		if op.Delegate != nil {
+			require.Equal(op.Delegate.String(), op1.Delegate.String())
+		} else {
+			require.Nil(op1.Delegate)
+		}
+		require.Equal(op.Amount, op1.Amount)
+	}
+
+	// invalid operations
+	data := (&Operation{Op: OpContribute, Delegate: identityset.Address(2)}).Serialize()
+	for _, d := range [][]byte{
+		nil,
+		data[:_opLength],
+		append([]byte{OpJoin}, data[_opLength:]...),
+		append([]byte{OpWithdraw + 1}, data[_opLength:]...),
+		append(append([]byte{}, data...), 0),
+	} {
+		require.Equal(ErrInvalidOperation, errors.Cause((&Operation{}).Deserialize(d)))
+	}
+}
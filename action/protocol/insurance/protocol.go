@@ -0,0 +1,387 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package insurance
+
+import (
+	"context"
+	"math/big"
+	"sort"
+
+	"github.com/iotexproject/go-pkgs/hash"
+	"github.com/iotexproject/iotex-address/address"
+	"github.com/iotexproject/iotex-proto/golang/iotextypes"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/iotexproject/iotex-core/action"
+	"github.com/iotexproject/iotex-core/action/protocol"
+	accountutil "github.com/iotexproject/iotex-core/action/protocol/account/util"
+	"github.com/iotexproject/iotex-core/action/protocol/poll"
+	"github.com/iotexproject/iotex-core/action/protocol/rolldpos"
+	"github.com/iotexproject/iotex-core/action/protocol/staking"
+	"github.com/iotexproject/iotex-core/action/protocol/vote/candidatesutil"
+	"github.com/iotexproject/iotex-core/pkg/log"
+	"github.com/iotexproject/iotex-core/pkg/util/byteutil"
+	"github.com/iotexproject/iotex-core/state"
+)
+
+const (
+	// TODO: it works only for one instance per protocol definition now
+	protocolID = "insurance"
+	// Namespace is the namespace to store the insurance pools of the delegates
+	Namespace = "Insurance"
+
+	_payoutsKey = "payouts"
+)
+
+// PayoutTopic is the first topic of the system log of compensating the voters of a delegate on probation, followed by
+// the address of the delegate. The data is the amount paid followed by the epoch number of the probation
+var PayoutTopic = hash.Hash256b([]byte("Insurance.Payout"))
+
+type (
+	// Protocol is the opt-in insurance of the delegates against the probation. The delegates join by creating their
+	// pools, to which anyone may contribute. When a delegate is put on the probation list of the next epoch, its pool
+	// compensates the voters for the reduced rewards at the end of the epoch, paying the intensity of the probation in
+	// percentage of the pool in proportion to the staked amount of their buckets. The operations on the pools are the
+	// executions to the protocol address, and the pools are held by the account of the protocol address
+	Protocol struct {
+		addr       address.Address
+		depositGas protocol.DepositGas
+	}
+)
+
+// NewProtocol instantiates the protocol of probation insurance
+func NewProtocol(depositGas protocol.DepositGas) *Protocol {
+	h := hash.Hash160b([]byte(protocolID))
+	addr, err := address.FromBytes(h[:])
+	if err != nil {
+		log.L().Panic("Error when constructing the address of insurance protocol", zap.Error(err))
+	}
+	return &Protocol{
+		addr:       addr,
+		depositGas: depositGas,
+	}
+}
+
+// Address returns the address of the protocol, which is the contract of the executions operating the pools
+func (p *Protocol) Address() address.Address {
+	return p.addr
+}
+
+// CreatePreStates compensates the voters of the delegates on the probation list of the next epoch in the last block of
+// the epoch, which is written by the poll protocol registered before
+func (p *Protocol) CreatePreStates(ctx context.Context, sm protocol.StateManager) error {
+	if !accountutil.IsSystemExecutionActive(ctx) {
+		return nil
+	}
+	bcCtx := protocol.MustGetBlockchainCtx(ctx)
+	blkCtx := protocol.MustGetBlockCtx(ctx)
+	registry := protocol.MustGetRegistry(ctx)
+	rp := rolldpos.FindProtocol(registry)
+	if rp == nil {
+		return nil
+	}
+	if _, ok := registry.Find(staking.ProtocolID); !ok {
+		return nil
+	}
+	epochNum := rp.GetEpochNum(blkCtx.BlockHeight)
+	if blkCtx.BlockHeight != rp.GetEpochLastBlockHeight(epochNum) {
+		return nil
+	}
+	// the probation list of the next epoch is not shifted yet
+	pl, _, err := candidatesutil.ProbationListFromDB(sm, true)
+	switch errors.Cause(err) {
+	case nil:
+	case state.ErrStateNotExist:
+		return nil
+	default:
+		return err
+	}
+	delegates := make([]string, 0, len(pl.ProbationInfo))
+	for delegate := range pl.ProbationInfo {
+		delegates = append(delegates, delegate)
+	}
+	sort.Strings(delegates)
+	schedule := poll.ProbationIntensitySchedule(&bcCtx.Genesis, rp.GetEpochHeight(epochNum+1))
+	ps := &payouts{}
+	for _, delegate := range delegates {
+		addr, err := address.FromString(delegate)
+		if err != nil {
+			return err
+		}
+		pool, _, err := p.pool(sm, addr)
+		switch errors.Cause(err) {
+		case nil:
+		case state.ErrStateNotExist:
+			continue
+		default:
+			return err
+		}
+		intensity := poll.ProbationIntensity(pl.IntensityRate, schedule, pl.ProbationInfo[delegate])
+		paid, err := p.compensate(sm, addr, pool, intensity)
+		if err != nil {
+			return errors.Wrapf(err, "failed to compensate the voters of %s", delegate)
+		}
+		if paid.Sign() > 0 {
+			ps.Payouts = append(ps.Payouts, &payout{
+				Delegate: delegate,
+				Amount:   paid,
+				EpochNum: epochNum + 1,
+			})
+		}
+	}
+	if len(ps.Payouts) == 0 {
+		return nil
+	}
+	return sm.Load(protocolID, _payoutsKey, ps)
+}
+
+// CreateSystemLogs emits an event of each compensation paid in the block
+func (p *Protocol) CreateSystemLogs(ctx context.Context, sm protocol.StateManager) ([]*action.Log, error) {
+	ps := &payouts{}
+	if err := sm.Unload(protocolID, _payoutsKey, ps); err != nil {
+		if errors.Cause(err) == protocol.ErrNoName {
+			return nil, nil
+		}
+		return nil, err
+	}
+	blkCtx := protocol.MustGetBlockCtx(ctx)
+	logs := make([]*action.Log, 0, len(ps.Payouts))
+	for _, po := range ps.Payouts {
+		delegate, err := address.FromString(po.Delegate)
+		if err != nil {
+			return nil, err
+		}
+		logs = append(logs, &action.Log{
+			Address: p.addr.String(),
+			Topics: action.Topics{
+				PayoutTopic,
+				hash.BytesToHash256(delegate.Bytes()),
+			},
+			Data:        append(amountBytes(po.Amount), byteutil.Uint64ToBytesBigEndian(po.EpochNum)...),
+			BlockHeight: blkCtx.BlockHeight,
+		})
+	}
+	return logs, nil
+}
+
+// Handle handles the operations on the pools
+func (p *Protocol) Handle(ctx context.Context, act action.Action, sm protocol.StateManager) (*action.Receipt, error) {
+	exec, ok := act.(*action.Execution)
+	if !ok || exec.Contract() != p.addr.String() || !accountutil.IsSystemExecutionActive(ctx) {
+		return nil, nil
+	}
+	si := sm.Snapshot()
+	tLog, err := p.handleOperation(ctx, exec, sm)
+	if err != nil {
+		log.L().Debug("Error when handling insurance operation", zap.Error(err))
+		return accountutil.SettleSystemExecution(ctx, sm, p.depositGas, p.addr, uint64(iotextypes.ReceiptStatus_Failure), si, nil)
+	}
+	return accountutil.SettleSystemExecution(ctx, sm, p.depositGas, p.addr, uint64(iotextypes.ReceiptStatus_Success), si, nil, tLog)
+}
+
+// ReadState reads the insurance pool of a delegate
+func (p *Protocol) ReadState(
+	ctx context.Context,
+	sr protocol.StateReader,
+	method []byte,
+	args ...[]byte,
+) ([]byte, uint64, error) {
+	switch string(method) {
+	case "Pool":
+		if len(args) != 1 {
+			return nil, uint64(0), errors.Wrapf(protocol.ErrInvalidArgument, "invalid number of arguments %d", len(args))
+		}
+		delegate, err := address.FromString(string(args[0]))
+		if err != nil {
+			return nil, uint64(0), errors.Wrap(protocol.ErrInvalidArgument, err.Error())
+		}
+		pool, height, err := p.pool(sr, delegate)
+		if err != nil {
+			return nil, uint64(0), err
+		}
+		data, err := pool.Serialize()
+		return data, height, err
+	default:
+		return nil, uint64(0), errors.Wrapf(protocol.ErrNotFound, "unknown method %s", string(method))
+	}
+}
+
+// Register registers the protocol with a unique ID
+func (p *Protocol) Register(r *protocol.Registry) error {
+	return r.Register(protocolID, p)
+}
+
+// ForceRegister registers the protocol with a unique ID and force replacing the previous protocol if it exists
+func (p *Protocol) ForceRegister(r *protocol.Registry) error {
+	return r.ForceRegister(protocolID, p)
+}
+
+// Name returns the name of protocol
+func (p *Protocol) Name() string {
+	return protocolID
+}
+
+func (p *Protocol) handleOperation(
+	ctx context.Context,
+	exec *action.Execution,
+	sm protocol.StateManager,
+) (*action.TransactionLog, error) {
+	actionCtx := protocol.MustGetActionCtx(ctx)
+	op := &Operation{}
+	if err := op.Deserialize(exec.Data()); err != nil {
+		return nil, err
+	}
+	amount := exec.Amount()
+	if amount == nil {
+		amount = big.NewInt(0)
+	}
+	caller, err := accountutil.LoadAccount(sm, hash.BytesToHash160(actionCtx.Caller.Bytes()))
+	if err != nil {
+		return nil, err
+	}
+	gasFee := new(big.Int).Mul(actionCtx.GasPrice, new(big.Int).SetUint64(actionCtx.IntrinsicGas))
+	if new(big.Int).Add(amount, gasFee).Cmp(caller.Balance) > 0 {
+		return nil, errors.Wrapf(state.ErrNotEnoughBalance, "caller %s balance not enough", actionCtx.Caller.String())
+	}
+	delegate := actionCtx.Caller
+	if op.Op == OpContribute {
+		delegate = op.Delegate
+	}
+	pool, _, err := p.pool(sm, delegate)
+	switch errors.Cause(err) {
+	case nil:
+		if op.Op == OpJoin {
+			return nil, errors.Wrapf(ErrInvalidOperation, "%s has already joined", delegate.String())
+		}
+	case state.ErrStateNotExist:
+		if op.Op != OpJoin {
+			return nil, errors.Wrapf(err, "%s has not joined", delegate.String())
+		}
+		pool = &Pool{
+			Balance: big.NewInt(0),
+			Paid:    big.NewInt(0),
+		}
+	default:
+		return nil, err
+	}
+
+	switch op.Op {
+	case OpJoin, OpContribute:
+		pool.Balance.Add(pool.Balance, amount)
+		if err := p.putPool(sm, delegate, pool); err != nil {
+			return nil, err
+		}
+		if amount.Sign() == 0 {
+			return nil, nil
+		}
+		if err := accountutil.Transfer(sm, actionCtx.Caller, p.addr, amount); err != nil {
+			return nil, err
+		}
+		return &action.TransactionLog{
+			Type:      iotextypes.TransactionLogType_NATIVE_TRANSFER,
+			Sender:    actionCtx.Caller.String(),
+			Recipient: p.addr.String(),
+			Amount:    amount,
+		}, nil
+	case OpWithdraw:
+		if amount.Sign() != 0 {
+			return nil, errors.New("withdrawing from pool does not accept amount")
+		}
+		if op.Amount.Cmp(pool.Balance) > 0 {
+			return nil, errors.Errorf("withdraw amount %s is more than pool %s", op.Amount.String(), pool.Balance.String())
+		}
+		if err := checkNotOnProbation(sm, delegate); err != nil {
+			return nil, err
+		}
+		pool.Balance.Sub(pool.Balance, op.Amount)
+		if err := p.putPool(sm, delegate, pool); err != nil {
+			return nil, err
+		}
+		if err := accountutil.Transfer(sm, p.addr, actionCtx.Caller, op.Amount); err != nil {
+			return nil, err
+		}
+		return &action.TransactionLog{
+			Type:      iotextypes.TransactionLogType_NATIVE_TRANSFER,
+			Sender:    p.addr.String(),
+			Recipient: actionCtx.Caller.String(),
+			Amount:    op.Amount,
+		}, nil
+	}
+	return nil, errors.Wrapf(ErrInvalidOperation, "unknown operation %d", op.Op)
+}
+
+// compensate pays the intensity in percentage of the pool to the voters of the delegate, in proportion to the staked
+// amount of their buckets, and returns the amount paid. The remainder of the division stays in the pool
+func (p *Protocol) compensate(sm protocol.StateManager, delegate address.Address, pool *Pool, intensity uint32) (*big.Int, error) {
+	paid := big.NewInt(0)
+	if pool.Balance.Sign() == 0 || intensity == 0 {
+		return paid, nil
+	}
+	buckets, err := staking.VoterBuckets(sm, delegate)
+	if err != nil {
+		return nil, err
+	}
+	total := big.NewInt(0)
+	for _, b := range buckets {
+		total.Add(total, b.StakedAmount)
+	}
+	if total.Sign() == 0 {
+		return paid, nil
+	}
+	amount := new(big.Int).Mul(pool.Balance, new(big.Int).SetUint64(uint64(intensity)))
+	amount.Div(amount, big.NewInt(100))
+	if amount.Cmp(pool.Balance) > 0 {
+		amount.Set(pool.Balance)
+	}
+	for _, b := range buckets {
+		share := new(big.Int).Mul(amount, b.StakedAmount)
+		share.Div(share, total)
+		if err := accountutil.Transfer(sm, p.addr, b.Owner, share); err != nil {
+			return nil, err
+		}
+		paid.Add(paid, share)
+	}
+	pool.Balance.Sub(pool.Balance, paid)
+	pool.Paid.Add(pool.Paid, paid)
+	if err := p.putPool(sm, delegate, pool); err != nil {
+		return nil, err
+	}
+	return paid, nil
+}
+
+func (p *Protocol) pool(sr protocol.StateReader, delegate address.Address) (*Pool, uint64, error) {
+	pool := &Pool{}
+	height, err := sr.State(pool, protocol.NamespaceOption(Namespace), protocol.KeyOption(delegate.Bytes()))
+	if err != nil {
+		return nil, height, err
+	}
+	return pool, height, nil
+}
+
+func (p *Protocol) putPool(sm protocol.StateManager, delegate address.Address, pool *Pool) error {
+	_, err := sm.PutState(pool, protocol.NamespaceOption(Namespace), protocol.KeyOption(delegate.Bytes()))
+	return err
+}
+
+// checkNotOnProbation returns ErrOnProbation if the delegate is on the probation list of the current or next epoch
+func checkNotOnProbation(sr protocol.StateReader, delegate address.Address) error {
+	for _, epochStartPoint := range []bool{true, false} {
+		pl, _, err := candidatesutil.ProbationListFromDB(sr, epochStartPoint)
+		switch errors.Cause(err) {
+		case nil:
+		case state.ErrStateNotExist:
+			continue
+		default:
+			return err
+		}
+		if _, ok := pl.ProbationInfo[delegate.String()]; ok {
+			return errors.Wrapf(ErrOnProbation, "%s cannot withdraw", delegate.String())
+		}
+	}
+	return nil
+}
@@ -0,0 +1,221 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package parameter
+
+import (
+	"github.com/iotexproject/go-pkgs/hash"
+	"github.com/iotexproject/iotex-address/address"
+	"github.com/pkg/errors"
+
+	"github.com/iotexproject/iotex-core/pkg/util/byteutil"
+)
+
+const (
+	_addressLength = 20
+	_valueLength   = 8
+	_heightLength  = 8
+	_hashLength    = 32
+	_paramLength   = 1
+	_settingLength = _valueLength + _heightLength + _hashLength
+)
+
+// parameters changeable by the governors
+const (
+	// ProductivityThreshold is the productivity in percentage, below which a delegate is unproductive in an epoch
+	ProductivityThreshold byte = iota + 1
+	// ProbationIntensity is the percentage the votes of the delegates in probation are reduced by
+	ProbationIntensity
+	// AddProbationExemption adds the address to the probation exemptions, whose delegate is never put into probation
+	AddProbationExemption
+	// RemoveProbationExemption removes the address from the probation exemptions
+	RemoveProbationExemption
+)
+
+var (
+	// ErrInvalidOperation indicates the data of the execution is not a valid operation
+	ErrInvalidOperation = errors.New("invalid parameter operation")
+	// ErrNotGovernor indicates the caller is not a governor of the parameters
+	ErrNotGovernor = errors.New("caller is not a parameter governor")
+)
+
+type (
+	// Setting is the value of a parameter set by the governors
+	Setting struct {
+		Value      uint64
+		Height     uint64
+		ActionHash hash.Hash256
+	}
+
+	// Proposal is a pending change of a parameter to a value, applied once enough governors approve it
+	Proposal struct {
+		Approvals []address.Address
+	}
+
+	// Operation approves changing the parameter to the value. Data is param (1 byte) || value (8 bytes), or
+	// param (1 byte) || address (20 bytes) of the operations on the probation exemptions
+	Operation struct {
+		Param   byte
+		Value   uint64
+		Address address.Address
+	}
+
+	// Exemptions are the addresses exempted from probation, e.g., the boot nodes of the foundation during a network
+	// incident, in the order they are added
+	Exemptions struct {
+		Addresses []address.Address
+	}
+)
+
+// Serialize serializes the setting
+func (s *Setting) Serialize() ([]byte, error) {
+	data := make([]byte, 0, _settingLength)
+	data = append(data, byteutil.Uint64ToBytesBigEndian(s.Value)...)
+	data = append(data, byteutil.Uint64ToBytesBigEndian(s.Height)...)
+	return append(data, s.ActionHash[:]...), nil
+}
+
+// Deserialize deserializes the setting
+func (s *Setting) Deserialize(data []byte) error {
+	if len(data) != _settingLength {
+		return errors.Errorf("invalid setting length %d", len(data))
+	}
+	s.Value = byteutil.BytesToUint64BigEndian(data[:_valueLength])
+	s.Height = byteutil.BytesToUint64BigEndian(data[_valueLength : _valueLength+_heightLength])
+	s.ActionHash = hash.BytesToHash256(data[_valueLength+_heightLength:])
+	return nil
+}
+
+// Approved returns true if the proposal is approved by the governor
+func (p *Proposal) Approved(addr address.Address) bool {
+	return contains(p.Approvals, addr)
+}
+
+// Serialize serializes the proposal
+func (p *Proposal) Serialize() ([]byte, error) {
+	return addressesBytes(p.Approvals), nil
+}
+
+// Deserialize deserializes the proposal
+func (p *Proposal) Deserialize(data []byte) error {
+	approvals, err := bytesAddresses(data)
+	if err != nil {
+		return errors.Wrap(err, "invalid approvals")
+	}
+	p.Approvals = approvals
+	return nil
+}
+
+// Exempted returns true if the address is exempted from probation
+func (e *Exemptions) Exempted(addr string) bool {
+	for _, a := range e.Addresses {
+		if a.String() == addr {
+			return true
+		}
+	}
+	return false
+}
+
+// Serialize serializes the exemptions
+func (e *Exemptions) Serialize() ([]byte, error) {
+	return addressesBytes(e.Addresses), nil
+}
+
+// Deserialize deserializes the exemptions
+func (e *Exemptions) Deserialize(data []byte) error {
+	addrs, err := bytesAddresses(data)
+	if err != nil {
+		return errors.Wrap(err, "invalid exemptions")
+	}
+	e.Addresses = addrs
+	return nil
+}
+
+// Serialize serializes the operation into the data of the execution to the protocol address
+func (o *Operation) Serialize() []byte {
+	if o.exemption() {
+		return append([]byte{o.Param}, o.Address.Bytes()...)
+	}
+	return append([]byte{o.Param}, byteutil.Uint64ToBytesBigEndian(o.Value)...)
+}
+
+// Deserialize deserializes the operation from the data of the execution to the protocol address
+func (o *Operation) Deserialize(data []byte) error {
+	if len(data) < _paramLength {
+		return errors.Wrap(ErrInvalidOperation, "empty data")
+	}
+	*o = Operation{Param: data[0]}
+	data = data[_paramLength:]
+	switch o.Param {
+	case ProductivityThreshold, ProbationIntensity:
+		if len(data) != _valueLength {
+			return errors.Wrapf(ErrInvalidOperation, "invalid data length %d of parameter %d", len(data), o.Param)
+		}
+		o.Value = byteutil.BytesToUint64BigEndian(data)
+		if o.Value > 100 {
+			return errors.Wrapf(ErrInvalidOperation, "percentage %d of parameter %d is larger than 100", o.Value, o.Param)
+		}
+	case AddProbationExemption, RemoveProbationExemption:
+		if len(data) != _addressLength {
+			return errors.Wrapf(ErrInvalidOperation, "invalid data length %d of parameter %d", len(data), o.Param)
+		}
+		addr, err := address.FromBytes(data)
+		if err != nil {
+			return errors.Wrap(ErrInvalidOperation, err.Error())
+		}
+		o.Address = addr
+	default:
+		return errors.Wrapf(ErrInvalidOperation, "unknown parameter %d", o.Param)
+	}
+	return nil
+}
+
+// exemption returns true if the operation is on the probation exemptions
+func (o *Operation) exemption() bool {
+	return o.Param == AddProbationExemption || o.Param == RemoveProbationExemption
+}
+
+func contains(addrs []address.Address, addr address.Address) bool {
+	for _, a := range addrs {
+		if address.Equal(a, addr) {
+			return true
+		}
+	}
+	return false
+}
+
+func remove(addrs []address.Address, addr address.Address) []address.Address {
+	remained := make([]address.Address, 0, len(addrs))
+	for _, a := range addrs {
+		if !address.Equal(a, addr) {
+			remained = append(remained, a)
+		}
+	}
+	return remained
+}
+
+func addressesBytes(addrs []address.Address) []byte {
+	data := make([]byte, 0, len(addrs)*_addressLength)
+	for _, addr := range addrs {
+		data = append(data, addr.Bytes()...)
+	}
+	return data
+}
+
+func bytesAddresses(data []byte) ([]address.Address, error) {
+	if len(data)%_addressLength != 0 {
+		return nil, errors.Errorf("invalid addresses length %d", len(data))
+	}
+	var addrs []address.Address
+	for ; len(data) > 0; data = data[_addressLength:] {
+		addr, err := address.FromBytes(data[:_addressLength])
+		if err != nil {
+			return nil, err
+		}
+		addrs = append(addrs, addr)
+	}
+	return addrs, nil
+}
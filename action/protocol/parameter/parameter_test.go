@@ -0,0 +1,184 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package parameter
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/iotexproject/go-pkgs/hash"
+	"github.com/iotexproject/iotex-address/address"
+	"github.com/iotexproject/iotex-proto/golang/iotextypes"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/action"
+	"github.com/iotexproject/iotex-core/action/protocol"
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/test/identityset"
+	"github.com/iotexproject/iotex-core/testutil/testdb"
+)
+
+func TestSettingAndProposal(t *testing.T) {
+	require := require.New(t)
+
+	s := &Setting{Value: 80, Height: 100, ActionHash: hash.Hash256b([]byte("change"))}
+	data, err := s.Serialize()
+	require.NoError(err)
+	s1 := &Setting{}
+	require.NoError(s1.Deserialize(data))
+	require.Equal(s, s1)
+	require.Error(s1.Deserialize(data[1:]))
+
+	p := &Proposal{Approvals: []address.Address{identityset.Address(1)}}
+	data, err = p.Serialize()
+	require.NoError(err)
+	p1 := &Proposal{}
+	require.NoError(p1.Deserialize(data))
+	require.True(p1.Approved(identityset.Address(1)))
+	require.False(p1.Approved(identityset.Address(2)))
+
+	for _, op := range []*Operation{
+		{Param: ProbationIntensity, Value: 50},
+		{Param: AddProbationExemption, Address: identityset.Address(3)},
+	} {
+		op1 := &Operation{}
+		require.NoError(op1.Deserialize(op.Serialize()))
+		require.Equal(op, op1)
+	}
+	for _, op := range []*Operation{
+		{Param: 0, Value: 1},
+		{Param: ProductivityThreshold, Value: 101},
+	} {
+		require.Equal(ErrInvalidOperation, errors.Cause((&Operation{}).Deserialize(op.Serialize())))
+	}
+	for _, data := range [][]byte{
+		{},
+		{ProductivityThreshold},
+		{RemoveProbationExemption, 1, 2, 3, 4, 5, 6, 7, 8},
+		append([]byte{ProbationIntensity}, identityset.Address(3).Bytes()...),
+	} {
+		require.Equal(ErrInvalidOperation, errors.Cause((&Operation{}).Deserialize(data)))
+	}
+
+	e := &Exemptions{Addresses: []address.Address{identityset.Address(3), identityset.Address(4)}}
+	data, err = e.Serialize()
+	require.NoError(err)
+	e1 := &Exemptions{}
+	require.NoError(e1.Deserialize(data))
+	require.Equal(e, e1)
+	require.True(e1.Exempted(identityset.Address(4).String()))
+	require.False(e1.Exempted(identityset.Address(5).String()))
+	require.Error(e1.Deserialize(data[1:]))
+}
+
+func TestProtocol(t *testing.T) {
+	require := require.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	sm := testdb.NewMockStateManager(ctrl)
+	p := NewProtocol(func(context.Context, protocol.StateManager, *big.Int) (*action.TransactionLog, error) {
+		return nil, nil
+	})
+	g := config.Default.Genesis
+	g.ParameterGovernors = []string{identityset.Address(1).String(), identityset.Address(2).String()}
+	g.ParameterApprovals = 2
+	ctx := protocol.WithBlockchainCtx(context.Background(), protocol.BlockchainCtx{Genesis: g})
+	ctx = protocol.WithBlockCtx(ctx, protocol.BlockCtx{BlockHeight: g.KamchatkaBlockHeight})
+
+	operate := func(caller int, op *Operation) *action.Receipt {
+		exec, err := action.NewExecution(p.Address().String(), 1, big.NewInt(0), 100000, big.NewInt(0), op.Serialize())
+		require.NoError(err)
+		ctx := protocol.WithActionCtx(ctx, protocol.ActionCtx{Caller: identityset.Address(caller), GasPrice: big.NewInt(0)})
+		r, err := p.Handle(ctx, exec, sm)
+		require.NoError(err)
+		return r
+	}
+
+	_, ok, err := Get(sm, ProductivityThreshold)
+	require.NoError(err)
+	require.False(ok)
+	// only the governors can approve a change
+	require.EqualValues(iotextypes.ReceiptStatus_Failure, operate(3, &Operation{Param: ProductivityThreshold, Value: 80}).Status)
+	// a change is applied once both governors approve the same value
+	require.EqualValues(iotextypes.ReceiptStatus_Success, operate(1, &Operation{Param: ProductivityThreshold, Value: 80}).Status)
+	require.EqualValues(iotextypes.ReceiptStatus_Failure, operate(1, &Operation{Param: ProductivityThreshold, Value: 80}).Status)
+	require.EqualValues(iotextypes.ReceiptStatus_Success, operate(2, &Operation{Param: ProductivityThreshold, Value: 70}).Status)
+	_, ok, err = Get(sm, ProductivityThreshold)
+	require.NoError(err)
+	require.False(ok)
+	r := operate(2, &Operation{Param: ProductivityThreshold, Value: 80})
+	require.EqualValues(iotextypes.ReceiptStatus_Success, r.Status)
+	require.Equal(ParameterChangedTopic, r.Logs()[0].Topics[0])
+	value, ok, err := Get(sm, ProductivityThreshold)
+	require.NoError(err)
+	require.True(ok)
+	require.Equal(uint64(80), value)
+
+	data, _, err := p.ReadState(ctx, sm, []byte("Setting"), []byte("1"))
+	require.NoError(err)
+	s := &Setting{}
+	require.NoError(s.Deserialize(data))
+	require.Equal(uint64(80), s.Value)
+	require.Equal(g.KamchatkaBlockHeight, s.Height)
+	_, _, err = p.ReadState(ctx, sm, []byte("Setting"), []byte("x"))
+	require.Equal(protocol.ErrInvalidArgument, errors.Cause(err))
+}
+
+func TestExemptions(t *testing.T) {
+	require := require.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	sm := testdb.NewMockStateManager(ctrl)
+	p := NewProtocol(func(context.Context, protocol.StateManager, *big.Int) (*action.TransactionLog, error) {
+		return nil, nil
+	})
+	g := config.Default.Genesis
+	g.ParameterGovernors = []string{identityset.Address(1).String(), identityset.Address(2).String()}
+	g.ParameterApprovals = 2
+	ctx := protocol.WithBlockchainCtx(context.Background(), protocol.BlockchainCtx{Genesis: g})
+	ctx = protocol.WithBlockCtx(ctx, protocol.BlockCtx{BlockHeight: g.KamchatkaBlockHeight})
+
+	operate := func(caller int, op *Operation) *action.Receipt {
+		exec, err := action.NewExecution(p.Address().String(), 1, big.NewInt(0), 100000, big.NewInt(0), op.Serialize())
+		require.NoError(err)
+		ctx := protocol.WithActionCtx(ctx, protocol.ActionCtx{Caller: identityset.Address(caller), GasPrice: big.NewInt(0)})
+		r, err := p.Handle(ctx, exec, sm)
+		require.NoError(err)
+		return r
+	}
+	exempted := func() []address.Address {
+		e, err := GetExemptions(sm)
+		require.NoError(err)
+		return e.Addresses
+	}
+
+	require.Empty(exempted())
+	add := func(i int) *Operation {
+		return &Operation{Param: AddProbationExemption, Address: identityset.Address(i)}
+	}
+	// removing an address not exempted changes nothing
+	require.EqualValues(iotextypes.ReceiptStatus_Failure, operate(1, &Operation{Param: RemoveProbationExemption, Address: identityset.Address(3)}).Status)
+	for _, i := range []int{3, 4} {
+		require.EqualValues(iotextypes.ReceiptStatus_Success, operate(1, add(i)).Status)
+		r := operate(2, add(i))
+		require.EqualValues(iotextypes.ReceiptStatus_Success, r.Status)
+		require.Equal(hash.BytesToHash256(identityset.Address(i).Bytes()), r.Logs()[0].Topics[2])
+	}
+	require.Equal([]address.Address{identityset.Address(3), identityset.Address(4)}, exempted())
+	require.EqualValues(iotextypes.ReceiptStatus_Failure, operate(1, add(3)).Status)
+
+	remove := &Operation{Param: RemoveProbationExemption, Address: identityset.Address(3)}
+	require.EqualValues(iotextypes.ReceiptStatus_Success, operate(2, remove).Status)
+	require.Equal([]address.Address{identityset.Address(3), identityset.Address(4)}, exempted())
+	require.EqualValues(iotextypes.ReceiptStatus_Success, operate(1, remove).Status)
+	require.Equal([]address.Address{identityset.Address(4)}, exempted())
+}
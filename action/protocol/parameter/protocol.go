@@ -0,0 +1,297 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package parameter
+
+import (
+	"context"
+	"math/big"
+	"strconv"
+
+	"github.com/iotexproject/go-pkgs/hash"
+	"github.com/iotexproject/iotex-address/address"
+	"github.com/iotexproject/iotex-proto/golang/iotextypes"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/iotexproject/iotex-core/action"
+	"github.com/iotexproject/iotex-core/action/protocol"
+	accountutil "github.com/iotexproject/iotex-core/action/protocol/account/util"
+	"github.com/iotexproject/iotex-core/pkg/log"
+	"github.com/iotexproject/iotex-core/pkg/util/byteutil"
+	"github.com/iotexproject/iotex-core/state"
+)
+
+const (
+	// TODO: it works only for one instance per protocol definition now
+	protocolID = "parameter"
+	// Namespace is the namespace to store the parameters and the pending changes of them
+	Namespace = "Parameter"
+)
+
+// prefixes of the keys of the setting and the pending proposals of a parameter, and the probation exemptions
+const (
+	_settingPrefix byte = iota
+	_proposalPrefix
+	_exemptionsPrefix
+)
+
+// ParameterChangedTopic is the first topic of the receipt log of changing a parameter, followed by the parameter and
+// the value, or the address of the operations on the probation exemptions
+var ParameterChangedTopic = hash.Hash256b([]byte("Parameter.Changed"))
+
+type (
+	// Protocol is the chain parameters managed by the governors in genesis, so that they can be changed without a
+	// hard fork. A change of a parameter is applied once enough governors approve it, and the protocols read the value
+	// in effect from state. The operations are the executions to the protocol address
+	Protocol struct {
+		addr       address.Address
+		depositGas protocol.DepositGas
+	}
+)
+
+// NewProtocol instantiates the protocol of chain parameters
+func NewProtocol(depositGas protocol.DepositGas) *Protocol {
+	h := hash.Hash160b([]byte(protocolID))
+	addr, err := address.FromBytes(h[:])
+	if err != nil {
+		log.L().Panic("Error when constructing the address of parameter protocol", zap.Error(err))
+	}
+	return &Protocol{
+		addr:       addr,
+		depositGas: depositGas,
+	}
+}
+
+// FindProtocol finds the registered protocol from registry
+func FindProtocol(registry *protocol.Registry) *Protocol {
+	if registry == nil {
+		return nil
+	}
+	p, ok := registry.Find(protocolID)
+	if !ok {
+		return nil
+	}
+	pp, ok := p.(*Protocol)
+	if !ok {
+		log.S().Panic("fail to cast parameter protocol")
+	}
+	return pp
+}
+
+// Get returns the value of the parameter set by the governors, false if the parameter is never changed
+func Get(sr protocol.StateReader, param byte) (uint64, bool, error) {
+	s := &Setting{}
+	_, err := sr.State(s, protocol.NamespaceOption(Namespace), protocol.KeyOption(settingKey(param)))
+	switch errors.Cause(err) {
+	case nil:
+		return s.Value, true, nil
+	case state.ErrStateNotExist:
+		return 0, false, nil
+	default:
+		return 0, false, errors.Wrapf(err, "failed to get parameter %d", param)
+	}
+}
+
+// GetExemptions returns the addresses exempted from probation by the governors
+func GetExemptions(sr protocol.StateReader) (*Exemptions, error) {
+	e := &Exemptions{}
+	_, err := sr.State(e, protocol.NamespaceOption(Namespace), protocol.KeyOption(exemptionsKey()))
+	switch errors.Cause(err) {
+	case nil, state.ErrStateNotExist:
+		return e, nil
+	default:
+		return nil, errors.Wrap(err, "failed to get probation exemptions")
+	}
+}
+
+// Address returns the address of the protocol, which is the contract of the executions changing the parameters
+func (p *Protocol) Address() address.Address {
+	return p.addr
+}
+
+// Handle handles the operations of the parameters
+func (p *Protocol) Handle(ctx context.Context, act action.Action, sm protocol.StateManager) (*action.Receipt, error) {
+	exec, ok := act.(*action.Execution)
+	if !ok || exec.Contract() != p.addr.String() || !accountutil.IsSystemExecutionActive(ctx) {
+		return nil, nil
+	}
+	si := sm.Snapshot()
+	l, err := p.handleOperation(ctx, exec, sm)
+	if err != nil {
+		log.L().Debug("Error when handling parameter operation", zap.Error(err))
+		return accountutil.SettleSystemExecution(ctx, sm, p.depositGas, p.addr, uint64(iotextypes.ReceiptStatus_Failure), si, nil)
+	}
+	return accountutil.SettleSystemExecution(ctx, sm, p.depositGas, p.addr, uint64(iotextypes.ReceiptStatus_Success), si, []*action.Log{l})
+}
+
+// ReadState reads the setting of the parameter, whose number is the argument
+func (p *Protocol) ReadState(
+	ctx context.Context,
+	sr protocol.StateReader,
+	method []byte,
+	args ...[]byte,
+) ([]byte, uint64, error) {
+	if len(args) != 1 {
+		return nil, uint64(0), errors.Wrapf(protocol.ErrInvalidArgument, "invalid number of arguments %d", len(args))
+	}
+	param, err := strconv.ParseUint(string(args[0]), 10, 8)
+	if err != nil {
+		return nil, uint64(0), errors.Wrap(protocol.ErrInvalidArgument, err.Error())
+	}
+	switch method := string(method); method {
+	case "Setting":
+		s := &Setting{}
+		height, err := sr.State(s, protocol.NamespaceOption(Namespace), protocol.KeyOption(settingKey(byte(param))))
+		if err != nil {
+			return nil, height, err
+		}
+		data, err := s.Serialize()
+		return data, height, err
+	default:
+		return nil, uint64(0), errors.Wrapf(protocol.ErrNotFound, "unknown method %s", method)
+	}
+}
+
+// Register registers the protocol with a unique ID
+func (p *Protocol) Register(r *protocol.Registry) error {
+	return r.Register(protocolID, p)
+}
+
+// ForceRegister registers the protocol with a unique ID and force replacing the previous protocol if it exists
+func (p *Protocol) ForceRegister(r *protocol.Registry) error {
+	return r.ForceRegister(protocolID, p)
+}
+
+// Name returns the name of protocol
+func (p *Protocol) Name() string {
+	return protocolID
+}
+
+func (p *Protocol) handleOperation(
+	ctx context.Context,
+	exec *action.Execution,
+	sm protocol.StateManager,
+) (*action.Log, error) {
+	actionCtx := protocol.MustGetActionCtx(ctx)
+	blkCtx := protocol.MustGetBlockCtx(ctx)
+	bcCtx := protocol.MustGetBlockchainCtx(ctx)
+	op := &Operation{}
+	if err := op.Deserialize(exec.Data()); err != nil {
+		return nil, err
+	}
+	if exec.Amount() != nil && exec.Amount().Sign() != 0 {
+		return nil, errors.Wrapf(ErrInvalidOperation, "operation on parameter %d does not accept amount", op.Param)
+	}
+	caller, err := accountutil.LoadAccount(sm, hash.BytesToHash160(actionCtx.Caller.Bytes()))
+	if err != nil {
+		return nil, err
+	}
+	gasFee := new(big.Int).Mul(actionCtx.GasPrice, new(big.Int).SetUint64(actionCtx.IntrinsicGas))
+	if gasFee.Cmp(caller.Balance) > 0 {
+		return nil, errors.Wrapf(state.ErrNotEnoughBalance, "caller %s balance not enough", actionCtx.Caller.String())
+	}
+	if !isGovernor(bcCtx.Genesis.ParameterGovernors, actionCtx.Caller) {
+		return nil, errors.Wrap(ErrNotGovernor, actionCtx.Caller.String())
+	}
+	var exemptions *Exemptions
+	if op.exemption() {
+		if exemptions, err = GetExemptions(sm); err != nil {
+			return nil, err
+		}
+		if contains(exemptions.Addresses, op.Address) == (op.Param == AddProbationExemption) {
+			return nil, errors.Wrapf(ErrInvalidOperation, "operation %d does not change the exemption of %s", op.Param, op.Address.String())
+		}
+	}
+
+	pKey := proposalKey(op)
+	proposal := &Proposal{}
+	if err := p.get(sm, pKey, proposal); err != nil && errors.Cause(err) != state.ErrStateNotExist {
+		return nil, err
+	}
+	if proposal.Approved(actionCtx.Caller) {
+		return nil, errors.Wrapf(ErrInvalidOperation, "%s already approved", actionCtx.Caller.String())
+	}
+	proposal.Approvals = append(proposal.Approvals, actionCtx.Caller)
+	if uint64(len(proposal.Approvals)) < bcCtx.Genesis.ParameterApprovals {
+		return nil, p.put(sm, pKey, proposal)
+	}
+
+	// the change is approved, apply it to the parameter
+	if err := p.del(sm, pKey); err != nil {
+		return nil, err
+	}
+	value := byteutil.Uint64ToBytesBigEndian(op.Value)
+	if op.exemption() {
+		if op.Param == AddProbationExemption {
+			exemptions.Addresses = append(exemptions.Addresses, op.Address)
+		} else {
+			exemptions.Addresses = remove(exemptions.Addresses, op.Address)
+		}
+		if err := p.put(sm, exemptionsKey(), exemptions); err != nil {
+			return nil, err
+		}
+		value = op.Address.Bytes()
+	} else if err := p.put(sm, settingKey(op.Param), &Setting{
+		Value:      op.Value,
+		Height:     blkCtx.BlockHeight,
+		ActionHash: actionCtx.ActionHash,
+	}); err != nil {
+		return nil, err
+	}
+	return &action.Log{
+		Address: p.addr.String(),
+		Topics: action.Topics{
+			ParameterChangedTopic,
+			hash.BytesToHash256([]byte{op.Param}),
+			hash.BytesToHash256(value),
+		},
+		BlockHeight: blkCtx.BlockHeight,
+		ActionHash:  actionCtx.ActionHash,
+	}, nil
+}
+
+func (p *Protocol) get(sr protocol.StateReader, key []byte, s state.Deserializer) error {
+	_, err := sr.State(s, protocol.NamespaceOption(Namespace), protocol.KeyOption(key))
+	return err
+}
+
+func (p *Protocol) put(sm protocol.StateManager, key []byte, s state.Serializer) error {
+	_, err := sm.PutState(s, protocol.NamespaceOption(Namespace), protocol.KeyOption(key))
+	return err
+}
+
+func (p *Protocol) del(sm protocol.StateManager, key []byte) error {
+	_, err := sm.DelState(protocol.NamespaceOption(Namespace), protocol.KeyOption(key))
+	if errors.Cause(err) == state.ErrStateNotExist {
+		return nil
+	}
+	return err
+}
+
+func isGovernor(governors []string, addr address.Address) bool {
+	for _, g := range governors {
+		if g == addr.String() {
+			return true
+		}
+	}
+	return false
+}
+
+// settingKey returns the key of the setting of the parameter
+func settingKey(param byte) []byte {
+	return []byte{_settingPrefix, param}
+}
+
+// exemptionsKey returns the key of the probation exemptions
+func exemptionsKey() []byte {
+	return []byte{_exemptionsPrefix}
+}
+
+// proposalKey returns the key of the pending proposal of changing the parameter to the value
+func proposalKey(op *Operation) []byte {
+	return append([]byte{_proposalPrefix}, op.Serialize()...)
+}
@@ -0,0 +1,253 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package paymentchannel
+
+import (
+	"math/big"
+
+	"github.com/iotexproject/go-pkgs/crypto"
+	"github.com/iotexproject/go-pkgs/hash"
+	"github.com/iotexproject/iotex-address/address"
+	"github.com/pkg/errors"
+
+	"github.com/iotexproject/iotex-core/pkg/util/byteutil"
+)
+
+const (
+	_addressLength   = 20
+	_amountLength    = 32
+	_heightLength    = 8
+	_opLength        = 1
+	_signatureLength = 65
+	_updateLength    = _heightLength + _amountLength + _signatureLength
+	_channelLength   = 2*_addressLength + 2*_amountLength + 3*_heightLength
+)
+
+// operations of the payment channels, the first byte of the data of the execution to the protocol address
+const (
+	// OpOpen opens a channel from the caller to the payee, depositing the amount of the execution. Data is
+	// op || payee (20 bytes) || settle timeout in blocks (8 bytes)
+	OpOpen byte = iota + 1
+	// OpClose closes the channel. With an update signed by the counterparty of the caller, the channel is settled
+	// with the update immediately. Without an update, the payee settles the channel with the latest amount on chain,
+	// while the payer starts the challenge period, in which the payee could settle with a newer update. Data is
+	// op || channel ID (32 bytes) || optional update (nonce 8 bytes || amount 32 bytes || signature 65 bytes)
+	OpClose
+	// OpSettle settles the channel after the challenge period. Data is op || channel ID (32 bytes)
+	OpSettle
+)
+
+var (
+	// ErrInvalidOperation indicates the data of the execution is not a valid operation
+	ErrInvalidOperation = errors.New("invalid payment channel operation")
+	// ErrInvalidUpdate indicates the update is not signed by the counterparty, or is older or larger than the channel
+	ErrInvalidUpdate = errors.New("invalid payment channel update")
+)
+
+type (
+	// Channel is a unidirectional payment channel from the payer to the payee
+	Channel struct {
+		Payer address.Address
+		Payee address.Address
+		// Deposit is the fund locked by the payer
+		Deposit *big.Int
+		// Paid is the cumulative amount paid to the payee in the latest update submitted
+		Paid *big.Int
+		// Nonce is the nonce of the latest update submitted
+		Nonce uint64
+		// SettleTimeout is the number of blocks of the challenge period
+		SettleTimeout uint64
+		// SettleHeight is the height the channel could be settled after the payer closes it, 0 if it is open
+		SettleHeight uint64
+	}
+
+	// Update is an off-chain update of the channel, paying the cumulative amount to the payee
+	Update struct {
+		ChannelID hash.Hash256
+		Nonce     uint64
+		Amount    *big.Int
+		Signature []byte
+	}
+
+	// Operation is an operation on the payment channels
+	Operation struct {
+		Op            byte
+		Payee         address.Address
+		SettleTimeout uint64
+		ChannelID     hash.Hash256
+		Update        *Update
+	}
+)
+
+// ChannelID returns the ID of the channel opened by the payer with the nonce of the execution
+func ChannelID(payer address.Address, nonce uint64) hash.Hash256 {
+	return hash.Hash256b(append(payer.Bytes(), byteutil.Uint64ToBytesBigEndian(nonce)...))
+}
+
+// Closing returns true if the payer has closed the channel and the challenge period has started
+func (c *Channel) Closing() bool {
+	return c.SettleHeight != 0
+}
+
+// Serialize serializes the channel
+func (c *Channel) Serialize() ([]byte, error) {
+	data := make([]byte, 0, _channelLength)
+	data = append(data, c.Payer.Bytes()...)
+	data = append(data, c.Payee.Bytes()...)
+	data = append(data, amountBytes(c.Deposit)...)
+	data = append(data, amountBytes(c.Paid)...)
+	data = append(data, byteutil.Uint64ToBytesBigEndian(c.Nonce)...)
+	data = append(data, byteutil.Uint64ToBytesBigEndian(c.SettleTimeout)...)
+	return append(data, byteutil.Uint64ToBytesBigEndian(c.SettleHeight)...), nil
+}
+
+// Deserialize deserializes the channel
+func (c *Channel) Deserialize(data []byte) error {
+	if len(data) != _channelLength {
+		return errors.Errorf("invalid channel length %d", len(data))
+	}
+	payer, err := address.FromBytes(data[:_addressLength])
+	if err != nil {
+		return err
+	}
+	data = data[_addressLength:]
+	payee, err := address.FromBytes(data[:_addressLength])
+	if err != nil {
+		return err
+	}
+	data = data[_addressLength:]
+	c.Payer = payer
+	c.Payee = payee
+	c.Deposit = new(big.Int).SetBytes(data[:_amountLength])
+	data = data[_amountLength:]
+	c.Paid = new(big.Int).SetBytes(data[:_amountLength])
+	data = data[_amountLength:]
+	c.Nonce = byteutil.BytesToUint64BigEndian(data[:_heightLength])
+	c.SettleTimeout = byteutil.BytesToUint64BigEndian(data[_heightLength : 2*_heightLength])
+	c.SettleHeight = byteutil.BytesToUint64BigEndian(data[2*_heightLength:])
+	return nil
+}
+
+// apply applies the update signed by the signer to the channel
+func (c *Channel) apply(u *Update, signer address.Address) error {
+	addr, err := u.Signer()
+	if err != nil {
+		return err
+	}
+	if !address.Equal(addr, signer) {
+		return errors.Wrapf(ErrInvalidUpdate, "update is signed by %s rather than %s", addr.String(), signer.String())
+	}
+	if u.Nonce < c.Nonce {
+		return errors.Wrapf(ErrInvalidUpdate, "update nonce %d is older than %d", u.Nonce, c.Nonce)
+	}
+	if u.Amount.Cmp(c.Deposit) > 0 {
+		return errors.Wrapf(ErrInvalidUpdate, "update amount %s is more than deposit %s", u.Amount.String(), c.Deposit.String())
+	}
+	c.Nonce = u.Nonce
+	c.Paid = u.Amount
+	return nil
+}
+
+// Hash returns the hash of the update to sign
+func (u *Update) Hash() hash.Hash256 {
+	data := make([]byte, 0, len(u.ChannelID)+_heightLength+_amountLength)
+	data = append(data, u.ChannelID[:]...)
+	data = append(data, byteutil.Uint64ToBytesBigEndian(u.Nonce)...)
+	return hash.Hash256b(append(data, amountBytes(u.Amount)...))
+}
+
+// Sign signs the update with the private key
+func (u *Update) Sign(sk crypto.PrivateKey) error {
+	h := u.Hash()
+	sig, err := sk.Sign(h[:])
+	if err != nil {
+		return err
+	}
+	u.Signature = sig
+	return nil
+}
+
+// Signer returns the address signing the update
+func (u *Update) Signer() (address.Address, error) {
+	h := u.Hash()
+	pk, err := crypto.RecoverPubkey(h[:], u.Signature)
+	if err != nil {
+		return nil, errors.Wrap(ErrInvalidUpdate, err.Error())
+	}
+	return address.FromBytes(pk.Hash())
+}
+
+// Serialize serializes the operation into the data of the execution to the protocol address
+func (o *Operation) Serialize() []byte {
+	data := []byte{o.Op}
+	switch o.Op {
+	case OpOpen:
+		data = append(data, o.Payee.Bytes()...)
+		return append(data, byteutil.Uint64ToBytesBigEndian(o.SettleTimeout)...)
+	case OpClose:
+		data = append(data, o.ChannelID[:]...)
+		if o.Update == nil {
+			return data
+		}
+		data = append(data, byteutil.Uint64ToBytesBigEndian(o.Update.Nonce)...)
+		data = append(data, amountBytes(o.Update.Amount)...)
+		return append(data, o.Update.Signature...)
+	default:
+		return append(data, o.ChannelID[:]...)
+	}
+}
+
+// Deserialize deserializes the operation from the data of the execution to the protocol address
+func (o *Operation) Deserialize(data []byte) error {
+	if len(data) < _opLength {
+		return errors.Wrap(ErrInvalidOperation, "empty data")
+	}
+	*o = Operation{Op: data[0]}
+	data = data[_opLength:]
+	switch o.Op {
+	case OpOpen:
+		if len(data) != _addressLength+_heightLength {
+			return errors.Wrapf(ErrInvalidOperation, "invalid data length %d of operation %d", len(data), o.Op)
+		}
+		payee, err := address.FromBytes(data[:_addressLength])
+		if err != nil {
+			return errors.Wrap(ErrInvalidOperation, err.Error())
+		}
+		o.Payee = payee
+		o.SettleTimeout = byteutil.BytesToUint64BigEndian(data[_addressLength:])
+		return nil
+	case OpClose, OpSettle:
+		if len(data) != len(o.ChannelID) && (o.Op == OpSettle || len(data) != len(o.ChannelID)+_updateLength) {
+			return errors.Wrapf(ErrInvalidOperation, "invalid data length %d of operation %d", len(data), o.Op)
+		}
+		o.ChannelID = hash.BytesToHash256(data[:len(o.ChannelID)])
+		data = data[len(o.ChannelID):]
+		if len(data) == 0 {
+			return nil
+		}
+		o.Update = &Update{
+			ChannelID: o.ChannelID,
+			Nonce:     byteutil.BytesToUint64BigEndian(data[:_heightLength]),
+			Amount:    new(big.Int).SetBytes(data[_heightLength : _heightLength+_amountLength]),
+			Signature: append([]byte{}, data[_heightLength+_amountLength:]...),
+		}
+		return nil
+	default:
+		return errors.Wrapf(ErrInvalidOperation, "unknown operation %d", o.Op)
+	}
+}
+
+// amountBytes returns the amount in 32 bytes big endian
+func amountBytes(amount *big.Int) []byte {
+	b := make([]byte, _amountLength)
+	if amount == nil {
+		return b
+	}
+	v := amount.Bytes()
+	copy(b[_amountLength-len(v):], v)
+	return b
+}
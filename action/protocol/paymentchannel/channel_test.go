@@ -0,0 +1,117 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package paymentchannel
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/test/identityset"
+)
+
+func TestChannelSerialization(t *testing.T) {
+	require := require.New(t)
+
+	c := &Channel{
+		Payer:         identityset.Address(1),
+		Payee:         identityset.Address(2),
+		Deposit:       big.NewInt(1000),
+		Paid:          big.NewInt(10),
+		Nonce:         3,
+		SettleTimeout: 100,
+		SettleHeight:  150,
+	}
+	data, err := c.Serialize()
+	require.NoError(err)
+	c1 := &Channel{}
+	require.NoError(c1.Deserialize(data))
+	require.Equal(c.Payer.String(), c1.Payer.String())
+	require.Equal(c.Payee.String(), c1.Payee.String())
+	require.Equal(c.Deposit, c1.Deposit)
+	require.Equal(c.Paid, c1.Paid)
+	require.Equal(c.Nonce, c1.Nonce)
+	require.Equal(c.SettleTimeout, c1.SettleTimeout)
+	require.Equal(c.SettleHeight, c1.SettleHeight)
+	require.True(c1.Closing())
+	require.Error(c1.Deserialize(data[1:]))
+}
+
+func TestChannelApply(t *testing.T) {
+	require := require.New(t)
+
+	payer, payee := identityset.Address(1), identityset.Address(2)
+	id := ChannelID(payer, 1)
+	c := &Channel{
+		Payer:   payer,
+		Payee:   payee,
+		Deposit: big.NewInt(1000),
+		Paid:    big.NewInt(0),
+		Nonce:   2,
+	}
+
+	u := &Update{ChannelID: id, Nonce: 3, Amount: big.NewInt(20)}
+	require.NoError(u.Sign(identityset.PrivateKey(1)))
+	signer, err := u.Signer()
+	require.NoError(err)
+	require.Equal(payer.String(), signer.String())
+
+	// the update must be signed by the counterparty
+	require.Equal(ErrInvalidUpdate, errors.Cause(c.apply(u, payee)))
+	// the update must not be older than the channel
+	old := &Update{ChannelID: id, Nonce: 1, Amount: big.NewInt(30)}
+	require.NoError(old.Sign(identityset.PrivateKey(1)))
+	require.Equal(ErrInvalidUpdate, errors.Cause(c.apply(old, payer)))
+	// the update must not pay more than the deposit
+	large := &Update{ChannelID: id, Nonce: 4, Amount: big.NewInt(1001)}
+	require.NoError(large.Sign(identityset.PrivateKey(1)))
+	require.Equal(ErrInvalidUpdate, errors.Cause(c.apply(large, payer)))
+	// the update signed for another channel is rejected
+	other := &Update{ChannelID: ChannelID(payer, 2), Nonce: 3, Amount: big.NewInt(20), Signature: u.Signature}
+	require.Error(c.apply(other, payer))
+
+	require.NoError(c.apply(u, payer))
+	require.Equal(uint64(3), c.Nonce)
+	require.Equal(big.NewInt(20), c.Paid)
+}
+
+func TestOperation(t *testing.T) {
+	require := require.New(t)
+
+	id := ChannelID(identityset.Address(1), 1)
+	u := &Update{ChannelID: id, Nonce: 3, Amount: big.NewInt(20)}
+	require.NoError(u.Sign(identityset.PrivateKey(1)))
+	for _, op := range []*Operation{
+		{Op: OpOpen, Payee: identityset.Address(2), SettleTimeout: 100},
+		{Op: OpClose, ChannelID: id},
+		{Op: OpClose, ChannelID: id, Update: u},
+		{Op: OpSettle, ChannelID: id},
+	} {
+		op1 := &Operation{}
+		require.NoError(op1.Deserialize(op.Serialize()))
+		require.Equal(op.Op, op1.Op)
+		require.Equal(op.SettleTimeout, op1.SettleTimeout)
+		require.Equal(op.ChannelID, op1.ChannelID)
+		if op.Payee != nil {
+			require.Equal(op.Payee.String(), op1.Payee.String())
+		}
+		require.Equal(op.Update, op1.Update)
+	}
+
+	// invalid operations
+	data := (&Operation{Op: OpClose, ChannelID: id, Update: u}).Serialize()
+	for _, d := range [][]byte{
+		nil,
+		data[:len(data)-1],
+		append([]byte{OpSettle}, data[_opLength:]...),
+		append([]byte{OpSettle + 1}, data[_opLength:]...),
+	} {
+		require.Equal(ErrInvalidOperation, errors.Cause((&Operation{}).Deserialize(d)))
+	}
+}
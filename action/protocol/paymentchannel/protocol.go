@@ -0,0 +1,271 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package paymentchannel
+
+import (
+	"context"
+	"encoding/hex"
+	"math/big"
+
+	"github.com/iotexproject/go-pkgs/hash"
+	"github.com/iotexproject/iotex-address/address"
+	"github.com/iotexproject/iotex-proto/golang/iotextypes"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/iotexproject/iotex-core/action"
+	"github.com/iotexproject/iotex-core/action/protocol"
+	accountutil "github.com/iotexproject/iotex-core/action/protocol/account/util"
+	"github.com/iotexproject/iotex-core/pkg/log"
+	"github.com/iotexproject/iotex-core/state"
+)
+
+const (
+	// TODO: it works only for one instance per protocol definition now
+	protocolID = "paymentchannel"
+	// Namespace is the namespace to store the payment channels
+	Namespace = "PaymentChannel"
+)
+
+type (
+	// Protocol is the payment channels for the micro payments between devices, which are paid by the off-chain
+	// updates signed by the payer and settled on chain once. The operations on the channels are the executions to the
+	// protocol address, and the deposits are held by the account of the protocol address
+	Protocol struct {
+		addr       address.Address
+		depositGas protocol.DepositGas
+	}
+)
+
+// NewProtocol instantiates the protocol of payment channel
+func NewProtocol(depositGas protocol.DepositGas) *Protocol {
+	h := hash.Hash160b([]byte(protocolID))
+	addr, err := address.FromBytes(h[:])
+	if err != nil {
+		log.L().Panic("Error when constructing the address of payment channel protocol", zap.Error(err))
+	}
+	return &Protocol{
+		addr:       addr,
+		depositGas: depositGas,
+	}
+}
+
+// FindProtocol finds the registered protocol from registry
+func FindProtocol(registry *protocol.Registry) *Protocol {
+	if registry == nil {
+		return nil
+	}
+	p, ok := registry.Find(protocolID)
+	if !ok {
+		return nil
+	}
+	pp, ok := p.(*Protocol)
+	if !ok {
+		log.S().Panic("fail to cast payment channel protocol")
+	}
+	return pp
+}
+
+// Address returns the address of the protocol, which is the contract of the executions operating the channels
+func (p *Protocol) Address() address.Address {
+	return p.addr
+}
+
+// Handle handles the operations on the payment channels
+func (p *Protocol) Handle(ctx context.Context, act action.Action, sm protocol.StateManager) (*action.Receipt, error) {
+	exec, ok := act.(*action.Execution)
+	if !ok || exec.Contract() != p.addr.String() || !accountutil.IsSystemExecutionActive(ctx) {
+		return nil, nil
+	}
+	si := sm.Snapshot()
+	tLogs, err := p.handleOperation(ctx, exec, sm)
+	if err != nil {
+		log.L().Debug("Error when handling payment channel operation", zap.Error(err))
+		return accountutil.SettleSystemExecution(ctx, sm, p.depositGas, p.addr, uint64(iotextypes.ReceiptStatus_Failure), si, nil)
+	}
+	return accountutil.SettleSystemExecution(ctx, sm, p.depositGas, p.addr, uint64(iotextypes.ReceiptStatus_Success), si, nil, tLogs...)
+}
+
+// ReadState reads the payment channel of the hex encoded channel ID
+func (p *Protocol) ReadState(
+	ctx context.Context,
+	sr protocol.StateReader,
+	method []byte,
+	args ...[]byte,
+) ([]byte, uint64, error) {
+	switch string(method) {
+	case "Channel":
+		if len(args) != 1 {
+			return nil, uint64(0), errors.Wrapf(protocol.ErrInvalidArgument, "invalid number of arguments %d", len(args))
+		}
+		id, err := hex.DecodeString(string(args[0]))
+		if err != nil {
+			return nil, uint64(0), errors.Wrap(protocol.ErrInvalidArgument, err.Error())
+		}
+		c, height, err := p.channel(sr, hash.BytesToHash256(id))
+		if err != nil {
+			return nil, uint64(0), err
+		}
+		data, err := c.Serialize()
+		return data, height, err
+	default:
+		return nil, uint64(0), errors.Wrapf(protocol.ErrNotFound, "unknown method %s", string(method))
+	}
+}
+
+// Register registers the protocol with a unique ID
+func (p *Protocol) Register(r *protocol.Registry) error {
+	return r.Register(protocolID, p)
+}
+
+// ForceRegister registers the protocol with a unique ID and force replacing the previous protocol if it exists
+func (p *Protocol) ForceRegister(r *protocol.Registry) error {
+	return r.ForceRegister(protocolID, p)
+}
+
+// Name returns the name of protocol
+func (p *Protocol) Name() string {
+	return protocolID
+}
+
+func (p *Protocol) handleOperation(
+	ctx context.Context,
+	exec *action.Execution,
+	sm protocol.StateManager,
+) ([]*action.TransactionLog, error) {
+	actionCtx := protocol.MustGetActionCtx(ctx)
+	blkCtx := protocol.MustGetBlockCtx(ctx)
+	op := &Operation{}
+	if err := op.Deserialize(exec.Data()); err != nil {
+		return nil, err
+	}
+	amount := exec.Amount()
+	if amount == nil {
+		amount = big.NewInt(0)
+	}
+	if op.Op != OpOpen && amount.Sign() != 0 {
+		return nil, errors.Wrapf(ErrInvalidOperation, "operation %d does not accept amount", op.Op)
+	}
+	caller, err := accountutil.LoadAccount(sm, hash.BytesToHash160(actionCtx.Caller.Bytes()))
+	if err != nil {
+		return nil, err
+	}
+	gasFee := new(big.Int).Mul(actionCtx.GasPrice, new(big.Int).SetUint64(actionCtx.IntrinsicGas))
+	if new(big.Int).Add(amount, gasFee).Cmp(caller.Balance) > 0 {
+		return nil, errors.Wrapf(state.ErrNotEnoughBalance, "caller %s balance not enough", actionCtx.Caller.String())
+	}
+
+	if op.Op == OpOpen {
+		if amount.Sign() == 0 {
+			return nil, errors.Wrap(ErrInvalidOperation, "channel deposit is 0")
+		}
+		if op.SettleTimeout == 0 {
+			return nil, errors.Wrap(ErrInvalidOperation, "settle timeout is 0")
+		}
+		if address.Equal(op.Payee, actionCtx.Caller) {
+			return nil, errors.Wrap(ErrInvalidOperation, "payee is the payer")
+		}
+		c := &Channel{
+			Payer:         actionCtx.Caller,
+			Payee:         op.Payee,
+			Deposit:       amount,
+			Paid:          big.NewInt(0),
+			SettleTimeout: op.SettleTimeout,
+		}
+		if err := p.putChannel(sm, ChannelID(actionCtx.Caller, actionCtx.Nonce), c); err != nil {
+			return nil, err
+		}
+		if err := accountutil.Transfer(sm, actionCtx.Caller, p.addr, amount); err != nil {
+			return nil, err
+		}
+		return []*action.TransactionLog{{
+			Type:      iotextypes.TransactionLogType_NATIVE_TRANSFER,
+			Sender:    actionCtx.Caller.String(),
+			Recipient: p.addr.String(),
+			Amount:    amount,
+		}}, nil
+	}
+
+	c, _, err := p.channel(sm, op.ChannelID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get channel %x", op.ChannelID)
+	}
+	isPayer, isPayee := address.Equal(c.Payer, actionCtx.Caller), address.Equal(c.Payee, actionCtx.Caller)
+	if !isPayer && !isPayee {
+		return nil, errors.Wrapf(ErrInvalidOperation, "%s is not a party of channel %x", actionCtx.Caller.String(), op.ChannelID)
+	}
+	switch op.Op {
+	case OpClose:
+		if op.Update != nil {
+			counterparty := c.Payer
+			if isPayer {
+				counterparty = c.Payee
+			}
+			if err := c.apply(op.Update, counterparty); err != nil {
+				return nil, err
+			}
+			return p.settle(sm, op.ChannelID, c)
+		}
+		if isPayee {
+			return p.settle(sm, op.ChannelID, c)
+		}
+		if c.Closing() {
+			return nil, errors.Wrapf(ErrInvalidOperation, "channel %x is already closing", op.ChannelID)
+		}
+		c.SettleHeight = blkCtx.BlockHeight + c.SettleTimeout
+		return nil, p.putChannel(sm, op.ChannelID, c)
+	case OpSettle:
+		if !c.Closing() || blkCtx.BlockHeight < c.SettleHeight {
+			return nil, errors.Wrapf(ErrInvalidOperation, "channel %x is in challenge period", op.ChannelID)
+		}
+		return p.settle(sm, op.ChannelID, c)
+	}
+	return nil, errors.Wrapf(ErrInvalidOperation, "unknown operation %d", op.Op)
+}
+
+// settle pays the paid amount to the payee and the rest of the deposit back to the payer, and deletes the channel
+func (p *Protocol) settle(sm protocol.StateManager, id hash.Hash256, c *Channel) ([]*action.TransactionLog, error) {
+	if _, err := sm.DelState(protocol.NamespaceOption(Namespace), protocol.KeyOption(id[:])); err != nil {
+		return nil, err
+	}
+	var tLogs []*action.TransactionLog
+	for _, payout := range []struct {
+		recipient address.Address
+		amount    *big.Int
+	}{
+		{c.Payee, c.Paid},
+		{c.Payer, new(big.Int).Sub(c.Deposit, c.Paid)},
+	} {
+		if payout.amount.Sign() == 0 {
+			continue
+		}
+		if err := accountutil.Transfer(sm, p.addr, payout.recipient, payout.amount); err != nil {
+			return nil, err
+		}
+		tLogs = append(tLogs, &action.TransactionLog{
+			Type:      iotextypes.TransactionLogType_NATIVE_TRANSFER,
+			Sender:    p.addr.String(),
+			Recipient: payout.recipient.String(),
+			Amount:    payout.amount,
+		})
+	}
+	return tLogs, nil
+}
+
+func (p *Protocol) channel(sr protocol.StateReader, id hash.Hash256) (*Channel, uint64, error) {
+	c := &Channel{}
+	height, err := sr.State(c, protocol.NamespaceOption(Namespace), protocol.KeyOption(id[:]))
+	if err != nil {
+		return nil, height, err
+	}
+	return c, height, nil
+}
+
+func (p *Protocol) putChannel(sm protocol.StateManager, id hash.Hash256, c *Channel) error {
+	_, err := sm.PutState(c, protocol.NamespaceOption(Namespace), protocol.KeyOption(id[:]))
+	return err
+}
@@ -7,14 +7,20 @@
 package poll
 
 import (
+	"bytes"
 	"context"
+	"encoding/binary"
+	"encoding/json"
+	"math"
 	"sync"
 
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
 
+	"github.com/iotexproject/iotex-core/action/protocol"
 	"github.com/iotexproject/iotex-core/action/protocol/vote"
 	"github.com/iotexproject/iotex-core/db"
+	"github.com/iotexproject/iotex-core/db/batch"
 	"github.com/iotexproject/iotex-core/pkg/log"
 	"github.com/iotexproject/iotex-core/pkg/util/byteutil"
 	"github.com/iotexproject/iotex-core/state"
@@ -25,10 +31,17 @@ var (
 	CandidateNamespace = "candidates"
 	// ProbationNamespace is a namespace to store probationlist
 	ProbationNamespace = "kickout"
+	// ProbationHistoryNamespace is a namespace to store the probation count of each delegate, keyed by the delegate
+	// address followed by the epoch start height
+	ProbationHistoryNamespace = "kickoutHistory"
 	// ErrIndexerNotExist is an error that shows not exist in candidate indexer DB
 	ErrIndexerNotExist = errors.New("not exist in DB")
 )
 
+// candidateIndexerBuffer is the key of the lists buffered in the working set, which are flushed into the indexer
+// when the block is committed
+const candidateIndexerBuffer = "candidateIndexerBuffer"
+
 // CandidateIndexer is an indexer to store candidate/probationList by given height
 type CandidateIndexer struct {
 	mutex   sync.RWMutex
@@ -60,7 +73,7 @@ func (cd *CandidateIndexer) Stop(ctx context.Context) error {
 func (cd *CandidateIndexer) PutCandidateList(height uint64, candidates *state.CandidateList) error {
 	cd.mutex.Lock()
 	defer cd.mutex.Unlock()
-	candidatesByte, err := candidates.Serialize()
+	candidatesByte, err := candidates.SerializeVersioned()
 	if err != nil {
 		return err
 	}
@@ -72,12 +85,46 @@ func (cd *CandidateIndexer) PutCandidateList(height uint64, candidates *state.Ca
 func (cd *CandidateIndexer) PutProbationList(height uint64, probationList *vote.ProbationList) error {
 	cd.mutex.Lock()
 	defer cd.mutex.Unlock()
-	probationListByte, err := probationList.Serialize()
+	probationListByte, err := probationList.SerializeVersioned()
 	if err != nil {
 		return err
 	}
 	log.L().Debug("put probation list into candidate indexer", zap.Uint64("height", height))
-	return cd.kvStore.Put(ProbationNamespace, byteutil.Uint64ToBytes(height), probationListByte)
+	b := batch.NewBatch()
+	b.Put(ProbationNamespace, byteutil.Uint64ToBytes(height), probationListByte, "failed to put probationlist at height %d", height)
+	putProbationHistory(b, height, probationList)
+	return cd.kvStore.WriteBatch(b)
+}
+
+// Flush writes the candidate/probation lists buffered in the working set into the indexer in a single batch, so that
+// the lists of an epoch are never partially visible to the readers
+func (cd *CandidateIndexer) Flush(sm protocol.StateManager) error {
+	buf := newIndexerBuffer()
+	if err := sm.Unload(protocolID, candidateIndexerBuffer, buf); err != nil {
+		if errors.Cause(err) == protocol.ErrNoName {
+			return nil
+		}
+		return err
+	}
+	b := batch.NewBatch()
+	for height, data := range buf.Candidates {
+		b.Put(CandidateNamespace, byteutil.Uint64ToBytes(height), data, "failed to put candidatelist at height %d", height)
+	}
+	for height, data := range buf.Probations {
+		b.Put(ProbationNamespace, byteutil.Uint64ToBytes(height), data, "failed to put probationlist at height %d", height)
+		pl := &vote.ProbationList{}
+		if err := pl.Deserialize(data); err != nil {
+			return errors.Wrapf(err, "failed to deserialize probationlist at height %d", height)
+		}
+		putProbationHistory(b, height, pl)
+	}
+	if b.Size() == 0 {
+		return nil
+	}
+	cd.mutex.Lock()
+	defer cd.mutex.Unlock()
+	log.L().Debug("flush candidate indexer", zap.Int("entries", b.Size()))
+	return cd.kvStore.WriteBatch(b)
 }
 
 // CandidateList gets candidate list from indexer given epoch start height
@@ -125,3 +172,193 @@ func (cd *CandidateIndexer) ProbationList(height uint64) (*vote.ProbationList, e
 	}
 	return bl, nil
 }
+
+// ProbationHistory returns the probation counts of the delegate, keyed by the start heights of the epochs it has been
+// on probation
+func (cd *CandidateIndexer) ProbationHistory(addr string) (map[uint64]uint32, error) {
+	cd.mutex.RLock()
+	defer cd.mutex.RUnlock()
+	prefix := []byte(addr)
+	keys, values, err := cd.kvStore.Filter(ProbationHistoryNamespace, func(k, v []byte) bool {
+		return len(k) == len(prefix)+8 && bytes.HasPrefix(k, prefix)
+	}, probationHistoryKey(addr, 0), probationHistoryKey(addr, math.MaxUint64))
+	if err != nil {
+		if cause := errors.Cause(err); cause == db.ErrNotExist || cause == db.ErrBucketNotExist {
+			return map[uint64]uint32{}, nil
+		}
+		return nil, err
+	}
+	history := make(map[uint64]uint32, len(keys))
+	for i := range keys {
+		history[byteutil.BytesToUint64BigEndian(keys[i][len(prefix):])] = binary.BigEndian.Uint32(values[i])
+	}
+	return history, nil
+}
+
+// BackfillProbationHistory writes the probation counts of the delegates in the probation lists indexed before the
+// probation history was recorded, and returns the number of entries written
+func (cd *CandidateIndexer) BackfillProbationHistory() (int, error) {
+	cd.mutex.Lock()
+	defer cd.mutex.Unlock()
+	existing := make(map[string]struct{})
+	keys, _, err := cd.kvStore.Filter(ProbationHistoryNamespace, func(k, v []byte) bool {
+		return true
+	}, nil, nil)
+	if err != nil {
+		if cause := errors.Cause(err); cause != db.ErrNotExist && cause != db.ErrBucketNotExist {
+			return 0, err
+		}
+	}
+	for _, k := range keys {
+		existing[string(k)] = struct{}{}
+	}
+	keys, values, err := cd.kvStore.Filter(ProbationNamespace, func(k, v []byte) bool {
+		return true
+	}, nil, nil)
+	if err != nil {
+		if cause := errors.Cause(err); cause == db.ErrNotExist || cause == db.ErrBucketNotExist {
+			return 0, nil
+		}
+		return 0, err
+	}
+	b := batch.NewBatch()
+	for i := range keys {
+		height := byteutil.BytesToUint64(keys[i])
+		pl := &vote.ProbationList{}
+		if err := pl.Deserialize(values[i]); err != nil {
+			return 0, errors.Wrapf(err, "failed to deserialize probationlist at height %d", height)
+		}
+		for addr, count := range pl.ProbationInfo {
+			key := probationHistoryKey(addr, height)
+			if _, ok := existing[string(key)]; ok {
+				continue
+			}
+			b.Put(ProbationHistoryNamespace, key, byteutil.Uint32ToBytesBigEndian(count), "failed to put probation history of %s", addr)
+		}
+	}
+	if b.Size() == 0 {
+		return 0, nil
+	}
+	log.L().Info("backfill probation history of candidate indexer", zap.Int("entries", b.Size()))
+	return b.Size(), cd.kvStore.WriteBatch(b)
+}
+
+// Migrate rewrites the candidate/probation lists stored in legacy encoding into versioned encoding, and returns the
+// number of entries migrated
+func (cd *CandidateIndexer) Migrate() (int, error) {
+	cd.mutex.Lock()
+	defer cd.mutex.Unlock()
+	b := batch.NewBatch()
+	for _, entry := range []struct {
+		ns        string
+		serialize func([]byte) ([]byte, error)
+	}{
+		{
+			CandidateNamespace,
+			func(buf []byte) ([]byte, error) {
+				candidates := &state.CandidateList{}
+				if err := candidates.Deserialize(buf); err != nil {
+					return nil, err
+				}
+				return candidates.SerializeVersioned()
+			},
+		},
+		{
+			ProbationNamespace,
+			func(buf []byte) ([]byte, error) {
+				pl := &vote.ProbationList{}
+				if err := pl.Deserialize(buf); err != nil {
+					return nil, err
+				}
+				return pl.SerializeVersioned()
+			},
+		},
+	} {
+		keys, values, err := cd.kvStore.Filter(entry.ns, func(k, v []byte) bool {
+			return !state.IsVersioned(v)
+		}, nil, nil)
+		if err != nil {
+			if cause := errors.Cause(err); cause == db.ErrNotExist || cause == db.ErrBucketNotExist {
+				continue
+			}
+			return 0, err
+		}
+		for i := range keys {
+			data, err := entry.serialize(values[i])
+			if err != nil {
+				return 0, errors.Wrapf(err, "failed to migrate %s at height %d", entry.ns, byteutil.BytesToUint64(keys[i]))
+			}
+			b.Put(entry.ns, keys[i], data, "failed to migrate %s", entry.ns)
+		}
+	}
+	if b.Size() == 0 {
+		return 0, nil
+	}
+	log.L().Info("migrate candidate indexer to versioned encoding", zap.Int("entries", b.Size()))
+	return b.Size(), cd.kvStore.WriteBatch(b)
+}
+
+// putProbationHistory adds the probation count of each delegate in the probation list into the batch
+func putProbationHistory(b batch.KVStoreBatch, height uint64, pl *vote.ProbationList) {
+	for addr, count := range pl.ProbationInfo {
+		b.Put(ProbationHistoryNamespace, probationHistoryKey(addr, height), byteutil.Uint32ToBytesBigEndian(count), "failed to put probation history of %s", addr)
+	}
+}
+
+func probationHistoryKey(addr string, height uint64) []byte {
+	return append([]byte(addr), byteutil.Uint64ToBytesBigEndian(height)...)
+}
+
+// indexerBuffer is the candidate/probation lists written in a block, keyed by epoch start height
+type indexerBuffer struct {
+	Candidates map[uint64][]byte `json:"candidates"`
+	Probations map[uint64][]byte `json:"probations"`
+}
+
+func newIndexerBuffer() *indexerBuffer {
+	return &indexerBuffer{
+		Candidates: map[uint64][]byte{},
+		Probations: map[uint64][]byte{},
+	}
+}
+
+// Serialize serializes the buffer into bytes
+func (buf *indexerBuffer) Serialize() ([]byte, error) {
+	return json.Marshal(buf)
+}
+
+// Deserialize deserializes bytes into the buffer
+func (buf *indexerBuffer) Deserialize(data []byte) error {
+	return json.Unmarshal(data, buf)
+}
+
+// bufferIndex adds a serialized list to the buffer in the working set, which is flushed into the indexer once the
+// block is committed
+func bufferIndex(sm protocol.StateManager, add func(*indexerBuffer)) error {
+	buf := newIndexerBuffer()
+	if err := sm.Unload(protocolID, candidateIndexerBuffer, buf); err != nil && errors.Cause(err) != protocol.ErrNoName {
+		return err
+	}
+	add(buf)
+	return sm.Load(protocolID, candidateIndexerBuffer, buf)
+}
+
+func bufferCandidateList(sm protocol.StateManager, height uint64, candidates *state.CandidateList) error {
+	data, err := candidates.SerializeVersioned()
+	if err != nil {
+		return err
+	}
+	return bufferIndex(sm, func(buf *indexerBuffer) {
+		buf.Candidates[height] = data
+	})
+}
+
+func bufferProbationList(sm protocol.StateManager, height uint64, probationList *vote.ProbationList) error {
+	data, err := probationList.SerializeVersioned()
+	if err != nil {
+		return err
+	}
+	return bufferIndex(sm, func(buf *indexerBuffer) {
+		buf.Probations[height] = data
+	})
+}
@@ -0,0 +1,194 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package poll
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"hash/crc32"
+	"io"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/iotexproject/iotex-core/action/protocol/vote"
+	"github.com/iotexproject/iotex-core/db"
+	"github.com/iotexproject/iotex-core/db/batch"
+	"github.com/iotexproject/iotex-core/pkg/log"
+	"github.com/iotexproject/iotex-core/pkg/util/byteutil"
+	"github.com/iotexproject/iotex-core/state"
+)
+
+// The export file consists of a header, the records of all epochs, an end marker, and the sha256 checksum of all the
+// bytes before it. A record is laid out as kind (1 byte), epoch start height (8 bytes), data length (4 bytes), data,
+// and the crc32 of all the previous fields, with integers in big endian
+const (
+	_exportVersion = byte(1)
+	_exportEnd     = byte(0)
+	_exportCand    = byte(1)
+	_exportProb    = byte(2)
+	// _maxExportRecordSize guards against a corrupted data length
+	_maxExportRecordSize = 64 << 20
+)
+
+var (
+	_exportMagic = []byte("IOCI")
+
+	// ErrInvalidExport indicates the export file is malformed or corrupted
+	ErrInvalidExport = errors.New("invalid candidate indexer export")
+)
+
+// Export writes the candidate/probation lists of all epochs into w, and returns the number of entries exported
+func (cd *CandidateIndexer) Export(w io.Writer) (int, error) {
+	cd.mutex.RLock()
+	defer cd.mutex.RUnlock()
+
+	h := sha256.New()
+	bw := bufio.NewWriter(io.MultiWriter(w, h))
+	if _, err := bw.Write(_exportMagic); err != nil {
+		return 0, err
+	}
+	if err := bw.WriteByte(_exportVersion); err != nil {
+		return 0, err
+	}
+	var n int
+	for _, entry := range []struct {
+		kind byte
+		ns   string
+	}{
+		{_exportCand, CandidateNamespace},
+		{_exportProb, ProbationNamespace},
+	} {
+		keys, values, err := cd.kvStore.Filter(entry.ns, func(k, v []byte) bool {
+			return true
+		}, nil, nil)
+		if err != nil {
+			if cause := errors.Cause(err); cause == db.ErrNotExist || cause == db.ErrBucketNotExist {
+				continue
+			}
+			return 0, err
+		}
+		for i := range keys {
+			if err := writeExportRecord(bw, entry.kind, byteutil.BytesToUint64(keys[i]), values[i]); err != nil {
+				return 0, err
+			}
+		}
+		n += len(keys)
+	}
+	if err := bw.WriteByte(_exportEnd); err != nil {
+		return 0, err
+	}
+	if err := bw.Flush(); err != nil {
+		return 0, err
+	}
+	if _, err := w.Write(h.Sum(nil)); err != nil {
+		return 0, err
+	}
+	log.L().Info("export candidate indexer", zap.Int("entries", n))
+	return n, nil
+}
+
+// Import verifies the file exported by Export and writes all its entries into the indexer in a single batch, and
+// returns the number of entries imported. Nothing is written if the file fails the verification
+func (cd *CandidateIndexer) Import(r io.Reader) (int, error) {
+	br := bufio.NewReader(r)
+	h := sha256.New()
+	tr := io.TeeReader(br, h)
+
+	header := make([]byte, len(_exportMagic)+1)
+	if _, err := io.ReadFull(tr, header); err != nil {
+		return 0, errors.Wrap(ErrInvalidExport, "failed to read header")
+	}
+	if !bytes.Equal(header[:len(_exportMagic)], _exportMagic) {
+		return 0, errors.Wrap(ErrInvalidExport, "unknown file format")
+	}
+	if header[len(_exportMagic)] != _exportVersion {
+		return 0, errors.Wrapf(ErrInvalidExport, "unsupported version %d", header[len(_exportMagic)])
+	}
+	b := batch.NewBatch()
+	for {
+		kind, height, data, err := readExportRecord(tr)
+		if err != nil {
+			return 0, err
+		}
+		if kind == _exportEnd {
+			break
+		}
+		switch kind {
+		case _exportCand:
+			if err := (&state.CandidateList{}).Deserialize(data); err != nil {
+				return 0, errors.Wrapf(ErrInvalidExport, "invalid candidatelist at height %d: %v", height, err)
+			}
+			b.Put(CandidateNamespace, byteutil.Uint64ToBytes(height), data, "failed to import candidatelist at height %d", height)
+		case _exportProb:
+			if err := (&vote.ProbationList{}).Deserialize(data); err != nil {
+				return 0, errors.Wrapf(ErrInvalidExport, "invalid probationlist at height %d: %v", height, err)
+			}
+			b.Put(ProbationNamespace, byteutil.Uint64ToBytes(height), data, "failed to import probationlist at height %d", height)
+		default:
+			return 0, errors.Wrapf(ErrInvalidExport, "unknown record kind %d", kind)
+		}
+	}
+	checksum := make([]byte, sha256.Size)
+	if _, err := io.ReadFull(br, checksum); err != nil {
+		return 0, errors.Wrap(ErrInvalidExport, "failed to read checksum")
+	}
+	if !bytes.Equal(checksum, h.Sum(nil)) {
+		return 0, errors.Wrap(ErrInvalidExport, "checksum mismatch")
+	}
+	if b.Size() == 0 {
+		return 0, nil
+	}
+
+	cd.mutex.Lock()
+	defer cd.mutex.Unlock()
+	log.L().Info("import candidate indexer", zap.Int("entries", b.Size()))
+	return b.Size(), cd.kvStore.WriteBatch(b)
+}
+
+func writeExportRecord(w io.Writer, kind byte, height uint64, data []byte) error {
+	buf := make([]byte, 13, 13+len(data)+4)
+	buf[0] = kind
+	binary.BigEndian.PutUint64(buf[1:9], height)
+	binary.BigEndian.PutUint32(buf[9:13], uint32(len(data)))
+	buf = append(buf, data...)
+	buf = append(buf, make([]byte, 4)...)
+	binary.BigEndian.PutUint32(buf[len(buf)-4:], crc32.ChecksumIEEE(buf[:len(buf)-4]))
+	_, err := w.Write(buf)
+	return err
+}
+
+func readExportRecord(r io.Reader) (byte, uint64, []byte, error) {
+	head := make([]byte, 13)
+	if _, err := io.ReadFull(r, head[:1]); err != nil {
+		return 0, 0, nil, errors.Wrap(ErrInvalidExport, "unexpected end of file")
+	}
+	if head[0] == _exportEnd {
+		return _exportEnd, 0, nil, nil
+	}
+	if _, err := io.ReadFull(r, head[1:]); err != nil {
+		return 0, 0, nil, errors.Wrap(ErrInvalidExport, "unexpected end of file")
+	}
+	height := binary.BigEndian.Uint64(head[1:9])
+	size := binary.BigEndian.Uint32(head[9:13])
+	if size > _maxExportRecordSize {
+		return 0, 0, nil, errors.Wrapf(ErrInvalidExport, "record size %d at height %d is too large", size, height)
+	}
+	body := make([]byte, size+4)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return 0, 0, nil, errors.Wrap(ErrInvalidExport, "unexpected end of file")
+	}
+	crc := crc32.NewIEEE()
+	crc.Write(head)
+	crc.Write(body[:size])
+	if crc.Sum32() != binary.BigEndian.Uint32(body[size:]) {
+		return 0, 0, nil, errors.Wrapf(ErrInvalidExport, "crc mismatch at height %d", height)
+	}
+	return head[0], height, body[:size], nil
+}
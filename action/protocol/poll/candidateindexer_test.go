@@ -7,16 +7,23 @@
 package poll
 
 import (
+	"bytes"
 	"context"
 	"math/big"
 	"testing"
 
+	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/require"
 
 	"github.com/iotexproject/iotex-core/action/protocol/vote"
+	"github.com/iotexproject/iotex-core/config"
 	"github.com/iotexproject/iotex-core/db"
+	"github.com/iotexproject/iotex-core/pkg/util/byteutil"
 	"github.com/iotexproject/iotex-core/state"
 	"github.com/iotexproject/iotex-core/test/identityset"
+	"github.com/iotexproject/iotex-core/testutil"
+	"github.com/iotexproject/iotex-core/testutil/testdb"
 )
 
 func TestCandidateIndexer(t *testing.T) {
@@ -105,3 +112,255 @@ func TestCandidateIndexer(t *testing.T) {
 		require.Equal(probationList2.ProbationInfo[str], count)
 	}
 }
+
+func TestCandidateIndexerMigrate(t *testing.T) {
+	require := require.New(t)
+	testPath, err := testutil.PathOfTempFile("test-candidate-indexer")
+	require.NoError(err)
+	defer testutil.CleanupPath(t, testPath)
+	cfg := config.Default.DB
+	cfg.DbPath = testPath
+	kv := db.NewBoltDB(cfg)
+	indexer, err := NewCandidateIndexer(kv)
+	require.NoError(err)
+	ctx := context.Background()
+	require.NoError(indexer.Start(ctx))
+	defer func() {
+		require.NoError(indexer.Stop(ctx))
+	}()
+
+	// nothing to migrate
+	n, err := indexer.Migrate()
+	require.NoError(err)
+	require.Zero(n)
+
+	// legacy entries written by older version
+	candidates := state.CandidateList{
+		{
+			Address:       identityset.Address(1).String(),
+			Votes:         big.NewInt(30),
+			RewardAddress: "rewardAddress1",
+		},
+	}
+	candidatesByte, err := candidates.Serialize()
+	require.NoError(err)
+	require.NoError(kv.Put(CandidateNamespace, byteutil.Uint64ToBytes(1), candidatesByte))
+	probationList := vote.NewProbationList(50)
+	probationList.ProbationInfo[identityset.Address(1).String()] = 2
+	probationListByte, err := probationList.Serialize()
+	require.NoError(err)
+	require.NoError(kv.Put(ProbationNamespace, byteutil.Uint64ToBytes(1), probationListByte))
+	// entry already in versioned encoding
+	require.NoError(indexer.PutCandidateList(2, &candidates))
+
+	n, err = indexer.Migrate()
+	require.NoError(err)
+	require.Equal(2, n)
+	for _, ns := range []string{CandidateNamespace, ProbationNamespace} {
+		data, err := kv.Get(ns, byteutil.Uint64ToBytes(1))
+		require.NoError(err)
+		require.True(state.IsVersioned(data))
+	}
+	candidatesFromDB, err := indexer.CandidateList(1)
+	require.NoError(err)
+	require.Equal(1, len(candidatesFromDB))
+	require.True(candidates[0].Equal(candidatesFromDB[0]))
+	probationListFromDB, err := indexer.ProbationList(1)
+	require.NoError(err)
+	require.Equal(probationList, probationListFromDB)
+
+	// migration is idempotent
+	n, err = indexer.Migrate()
+	require.NoError(err)
+	require.Zero(n)
+}
+
+func TestCandidateIndexerFlush(t *testing.T) {
+	require := require.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	indexer, err := NewCandidateIndexer(db.NewMemKVStore())
+	require.NoError(err)
+	require.NoError(indexer.Start(context.Background()))
+	sm := testdb.NewMockStateManager(ctrl)
+
+	// nothing buffered
+	require.NoError(indexer.Flush(sm))
+
+	candidates := state.CandidateList{
+		{
+			Address:       identityset.Address(1).String(),
+			Votes:         big.NewInt(30),
+			RewardAddress: "rewardAddress1",
+		},
+	}
+	probationList := vote.NewProbationList(50)
+	probationList.ProbationInfo[identityset.Address(1).String()] = 1
+	require.NoError(bufferCandidateList(sm, 1, &candidates))
+	require.NoError(bufferCandidateList(sm, 721, &candidates))
+	require.NoError(bufferProbationList(sm, 721, probationList))
+
+	// the buffered lists are invisible until flushed
+	_, err = indexer.CandidateList(721)
+	require.Equal(ErrIndexerNotExist, err)
+	_, err = indexer.ProbationList(721)
+	require.Equal(ErrIndexerNotExist, err)
+
+	require.NoError(indexer.Flush(sm))
+	for _, height := range []uint64{1, 721} {
+		candidatesFromDB, err := indexer.CandidateList(height)
+		require.NoError(err)
+		require.Equal(1, len(candidatesFromDB))
+		require.True(candidates[0].Equal(candidatesFromDB[0]))
+	}
+	probationListFromDB, err := indexer.ProbationList(721)
+	require.NoError(err)
+	require.Equal(probationList, probationListFromDB)
+	_, err = indexer.ProbationList(1)
+	require.Equal(ErrIndexerNotExist, err)
+}
+
+func TestCandidateIndexerProbationHistory(t *testing.T) {
+	require := require.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	testPath, err := testutil.PathOfTempFile("test-candidate-indexer")
+	require.NoError(err)
+	defer testutil.CleanupPath(t, testPath)
+	cfg := config.Default.DB
+	cfg.DbPath = testPath
+	kv := db.NewBoltDB(cfg)
+	indexer, err := NewCandidateIndexer(kv)
+	require.NoError(err)
+	ctx := context.Background()
+	require.NoError(indexer.Start(ctx))
+	defer func() {
+		require.NoError(indexer.Stop(ctx))
+	}()
+	addr1, addr2 := identityset.Address(1).String(), identityset.Address(2).String()
+
+	// nothing indexed
+	n, err := indexer.BackfillProbationHistory()
+	require.NoError(err)
+	require.Zero(n)
+	history, err := indexer.ProbationHistory(addr1)
+	require.NoError(err)
+	require.Empty(history)
+
+	// probation list indexed before the history is recorded
+	probationList := vote.NewProbationList(50)
+	probationList.ProbationInfo[addr1] = 1
+	probationList.ProbationInfo[addr2] = 1
+	probationListByte, err := probationList.SerializeVersioned()
+	require.NoError(err)
+	require.NoError(kv.Put(ProbationNamespace, byteutil.Uint64ToBytes(1), probationListByte))
+	n, err = indexer.BackfillProbationHistory()
+	require.NoError(err)
+	require.Equal(2, n)
+
+	probationList = vote.NewProbationList(50)
+	probationList.ProbationInfo[addr1] = 2
+	require.NoError(indexer.PutProbationList(721, probationList))
+	sm := testdb.NewMockStateManager(ctrl)
+	probationList = vote.NewProbationList(50)
+	probationList.ProbationInfo[addr1] = 3
+	require.NoError(bufferProbationList(sm, 1441, probationList))
+	require.NoError(indexer.Flush(sm))
+
+	history, err = indexer.ProbationHistory(addr1)
+	require.NoError(err)
+	require.Equal(map[uint64]uint32{1: 1, 721: 2, 1441: 3}, history)
+	history, err = indexer.ProbationHistory(addr2)
+	require.NoError(err)
+	require.Equal(map[uint64]uint32{1: 1}, history)
+	history, err = indexer.ProbationHistory(identityset.Address(3).String())
+	require.NoError(err)
+	require.Empty(history)
+
+	// backfill is idempotent
+	n, err = indexer.BackfillProbationHistory()
+	require.NoError(err)
+	require.Zero(n)
+}
+
+func TestCandidateIndexerExport(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+	newIndexer := func() (*CandidateIndexer, func()) {
+		testPath, err := testutil.PathOfTempFile("test-candidate-indexer")
+		require.NoError(err)
+		cfg := config.Default.DB
+		cfg.DbPath = testPath
+		indexer, err := NewCandidateIndexer(db.NewBoltDB(cfg))
+		require.NoError(err)
+		require.NoError(indexer.Start(ctx))
+		return indexer, func() {
+			require.NoError(indexer.Stop(ctx))
+			testutil.CleanupPath(t, testPath)
+		}
+	}
+
+	candidates := state.CandidateList{
+		{
+			Address:       identityset.Address(1).String(),
+			Votes:         big.NewInt(30),
+			RewardAddress: "rewardAddress1",
+		},
+	}
+	probationList := vote.NewProbationList(50)
+	probationList.ProbationInfo[identityset.Address(1).String()] = 1
+	src, cleanup := newIndexer()
+	defer cleanup()
+	require.NoError(src.PutCandidateList(1, &candidates))
+	require.NoError(src.PutCandidateList(721, &candidates))
+	require.NoError(src.PutProbationList(721, probationList))
+
+	var buf bytes.Buffer
+	n, err := src.Export(&buf)
+	require.NoError(err)
+	require.Equal(3, n)
+	data := buf.Bytes()
+
+	dst, cleanup := newIndexer()
+	defer cleanup()
+	for _, invalid := range [][]byte{
+		nil,
+		data[:len(data)-1],
+		append([]byte("ABCD"), data[4:]...),
+		// flip a byte of the first record
+		append(append(append([]byte{}, data[:20]...), data[20]^0xff), data[21:]...),
+		// flip a byte of the checksum
+		append(append([]byte{}, data[:len(data)-1]...), data[len(data)-1]^0xff),
+	} {
+		_, err := dst.Import(bytes.NewReader(invalid))
+		require.Equal(ErrInvalidExport, errors.Cause(err))
+	}
+	// nothing is imported from an invalid file
+	_, err = dst.CandidateList(1)
+	require.Equal(ErrIndexerNotExist, err)
+
+	n, err = dst.Import(bytes.NewReader(data))
+	require.NoError(err)
+	require.Equal(3, n)
+	for _, height := range []uint64{1, 721} {
+		candidatesFromDB, err := dst.CandidateList(height)
+		require.NoError(err)
+		require.Equal(1, len(candidatesFromDB))
+		require.True(candidates[0].Equal(candidatesFromDB[0]))
+	}
+	probationListFromDB, err := dst.ProbationList(721)
+	require.NoError(err)
+	require.Equal(probationList, probationListFromDB)
+
+	// an empty indexer exports a valid file with no entries
+	empty, cleanup := newIndexer()
+	defer cleanup()
+	buf.Reset()
+	n, err = empty.Export(&buf)
+	require.NoError(err)
+	require.Zero(n)
+	n, err = dst.Import(&buf)
+	require.NoError(err)
+	require.Zero(n)
+}
@@ -0,0 +1,94 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package poll
+
+import (
+	"strconv"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+
+	"github.com/iotexproject/iotex-core/action/protocol"
+	"github.com/iotexproject/iotex-core/action/protocol/poll/candidatepagepb"
+	"github.com/iotexproject/iotex-core/state"
+)
+
+// MaxCandidatesPageSize is the max number of candidates read in a page
+const MaxCandidatesPageSize = 1000
+
+// CandidatePage is a page of the candidate list, with the total number of the candidates in the list
+type CandidatePage struct {
+	Candidates state.CandidateList
+	Total      uint64
+}
+
+// NewCandidatePage returns the page of at most limit candidates from offset
+func NewCandidatePage(candidates state.CandidateList, offset, limit uint64) *CandidatePage {
+	total := uint64(len(candidates))
+	if offset > total {
+		offset = total
+	}
+	end := offset + limit
+	if end > total {
+		end = total
+	}
+	return &CandidatePage{
+		Candidates: candidates[offset:end],
+		Total:      total,
+	}
+}
+
+// Serialize serializes the candidate page into bytes
+func (cp *CandidatePage) Serialize() ([]byte, error) {
+	data, err := cp.Candidates.Serialize()
+	if err != nil {
+		return nil, err
+	}
+	return proto.Marshal(&candidatepagepb.CandidatePage{
+		Candidates: data,
+		Total:      cp.Total,
+	})
+}
+
+// Deserialize deserializes bytes into the candidate page
+func (cp *CandidatePage) Deserialize(buf []byte) error {
+	pagepb := &candidatepagepb.CandidatePage{}
+	if err := proto.Unmarshal(buf, pagepb); err != nil {
+		return errors.Wrap(err, "failed to unmarshal candidate page")
+	}
+	candidates := state.CandidateList{}
+	if err := candidates.Deserialize(pagepb.Candidates); err != nil {
+		return err
+	}
+	cp.Candidates = candidates
+	cp.Total = pagepb.Total
+	return nil
+}
+
+// candidatePageArgs parses the offset and limit arguments following the epoch number, returns false if the candidates
+// are not requested by page
+func candidatePageArgs(args [][]byte) (uint64, uint64, bool, error) {
+	switch len(args) {
+	case 0, 1:
+		return 0, 0, false, nil
+	case 3:
+	default:
+		return 0, 0, false, errors.Wrap(protocol.ErrInvalidArgument, "both offset and limit are required")
+	}
+	offset, err := strconv.ParseUint(string(args[1]), 10, 64)
+	if err != nil {
+		return 0, 0, false, errors.Wrap(protocol.ErrInvalidArgument, err.Error())
+	}
+	limit, err := strconv.ParseUint(string(args[2]), 10, 64)
+	if err != nil {
+		return 0, 0, false, errors.Wrap(protocol.ErrInvalidArgument, err.Error())
+	}
+	if limit == 0 || limit > MaxCandidatesPageSize {
+		return 0, 0, false, errors.Wrapf(protocol.ErrInvalidArgument, "limit should be in range [1, %d]", MaxCandidatesPageSize)
+	}
+	return offset, limit, true, nil
+}
@@ -0,0 +1,74 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package poll
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/action/protocol"
+	"github.com/iotexproject/iotex-core/state"
+	"github.com/iotexproject/iotex-core/test/identityset"
+)
+
+func TestCandidatePage(t *testing.T) {
+	require := require.New(t)
+
+	candidates := state.CandidateList{}
+	for i := 0; i < 5; i++ {
+		candidates = append(candidates, &state.Candidate{
+			Address:       identityset.Address(i).String(),
+			Votes:         big.NewInt(int64(10 - i)),
+			RewardAddress: identityset.Address(i).String(),
+		})
+	}
+	for _, test := range []struct {
+		offset, limit uint64
+		expected      state.CandidateList
+	}{
+		{0, 2, candidates[:2]},
+		{3, 5, candidates[3:]},
+		{5, 1, state.CandidateList{}},
+		{10, 1, state.CandidateList{}},
+	} {
+		page := NewCandidatePage(candidates, test.offset, test.limit)
+		data, err := page.Serialize()
+		require.NoError(err)
+		page = &CandidatePage{}
+		require.NoError(page.Deserialize(data))
+		require.Equal(uint64(5), page.Total)
+		require.Equal(len(test.expected), len(page.Candidates))
+		for i, c := range test.expected {
+			require.True(c.Equal(page.Candidates[i]))
+		}
+	}
+
+	_, _, paged, err := candidatePageArgs([][]byte{[]byte("1")})
+	require.NoError(err)
+	require.False(paged)
+	offset, limit, paged, err := candidatePageArgs([][]byte{[]byte("1"), []byte("20"), []byte("10")})
+	require.NoError(err)
+	require.True(paged)
+	require.Equal(uint64(20), offset)
+	require.Equal(uint64(10), limit)
+	for _, args := range [][]string{
+		{"1", "0"},
+		{"1", "0", "0"},
+		{"1", "0", "1001"},
+		{"1", "a", "10"},
+	} {
+		bytesArgs := make([][]byte, len(args))
+		for i, arg := range args {
+			bytesArgs[i] = []byte(arg)
+		}
+		_, _, _, err := candidatePageArgs(bytesArgs)
+		require.Equal(protocol.ErrInvalidArgument, errors.Cause(err))
+	}
+}
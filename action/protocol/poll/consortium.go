@@ -162,6 +162,11 @@ func (cc *consortiumCommittee) Handle(ctx context.Context, act action.Action, sm
 	return handle(ctx, act, sm, cc.indexer, cc.addr.String())
 }
 
+// Commit flushes the candidate list written in the block into the indexer
+func (cc *consortiumCommittee) Commit(ctx context.Context, sm protocol.StateManager) error {
+	return cc.indexer.Flush(sm)
+}
+
 func (cc *consortiumCommittee) Validate(ctx context.Context, act action.Action, sr protocol.StateReader) error {
 	return validate(ctx, sr, cc, act)
 }
@@ -190,6 +195,11 @@ func (cc *consortiumCommittee) Name() string {
 	return protocolID
 }
 
+// Dependencies returns the IDs of the protocols which the protocol depends on
+func (cc *consortiumCommittee) Dependencies() []string {
+	return []string{rolldpos.ProtocolID}
+}
+
 func (cc *consortiumCommittee) CalculateCandidatesByHeight(ctx context.Context, _ protocol.StateReader, _ uint64) (state.CandidateList, error) {
 	return cc.readDelegates(ctx)
 }
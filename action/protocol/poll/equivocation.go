@@ -0,0 +1,339 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package poll
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/iotexproject/go-pkgs/crypto"
+	"github.com/iotexproject/go-pkgs/hash"
+	"github.com/iotexproject/iotex-address/address"
+	"github.com/iotexproject/iotex-proto/golang/iotextypes"
+	blake2b "github.com/minio/blake2b-simd"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/iotexproject/iotex-core/action"
+	"github.com/iotexproject/iotex-core/action/protocol"
+	accountutil "github.com/iotexproject/iotex-core/action/protocol/account/util"
+	"github.com/iotexproject/iotex-core/action/protocol/poll/evidencepb"
+	"github.com/iotexproject/iotex-core/action/protocol/rolldpos"
+	"github.com/iotexproject/iotex-core/action/protocol/vote/candidatesutil"
+	"github.com/iotexproject/iotex-core/blockchain/block"
+	"github.com/iotexproject/iotex-core/endorsement"
+	"github.com/iotexproject/iotex-core/pkg/log"
+	"github.com/iotexproject/iotex-core/pkg/util/byteutil"
+	"github.com/iotexproject/iotex-core/state"
+)
+
+// _maxVoteTopic is the largest topic of the consensus votes, which are PROPOSAL, LOCK and COMMIT
+const _maxVoteTopic = uint32(iotextypes.ConsensusVote_COMMIT)
+
+// ErrInvalidEvidence indicates the evidence does not prove a delegate double signing
+var ErrInvalidEvidence = errors.New("invalid equivocation evidence")
+
+// DelegateJailedTopic is the first topic of the receipt log of jailing a delegate, followed by the address of the
+// delegate and the last epoch it is jailed in
+var DelegateJailedTopic = hash.Hash256b([]byte("Poll.DelegateJailed"))
+
+type (
+	// SignedVote is a block signed by its producer if the endorsement is nil, otherwise it is a consensus vote of the
+	// topic on the block endorsed by a delegate
+	SignedVote struct {
+		Header      *block.Header
+		Topic       uint32
+		Endorsement *endorsement.Endorsement
+	}
+
+	// Equivocation is the evidence of a delegate double signing, which is either proposing two different blocks of
+	// the same height and round, or endorsing both of them on the same topic. The blocks proposed in a round are
+	// timestamped at the start time of the round, so the round is told by the timestamps of the block headers
+	Equivocation struct {
+		First  *SignedVote
+		Second *SignedVote
+	}
+
+	// consensusVote is the document the delegates endorse in consensus
+	consensusVote struct {
+		blkHash hash.Hash256
+		topic   uint32
+	}
+)
+
+// HandleEquivocation handles the execution to the poll protocol address carrying the evidence of a delegate double
+// signing, which jails the delegate with zero voting power from the next epoch till EquivocationJailPeriod epochs since
+// the double signing. It only takes effect since kamchatka height if the jail period is set in genesis
+func (sh *Slasher) HandleEquivocation(
+	ctx context.Context,
+	act action.Action,
+	sm protocol.StateManager,
+	protocolAddr address.Address,
+) (*action.Receipt, error) {
+	exec, ok := act.(*action.Execution)
+	if !ok || exec.Contract() != protocolAddr.String() || !sh.jailEnabled(protocol.MustGetBlockCtx(ctx).BlockHeight) {
+		return nil, nil
+	}
+	si := sm.Snapshot()
+	l, err := sh.jailOffender(ctx, exec, sm, protocolAddr)
+	if err != nil {
+		log.L().Debug("Error when handling equivocation evidence", zap.Error(err))
+		return sh.settleEvidence(ctx, sm, uint64(iotextypes.ReceiptStatus_Failure), si, protocolAddr, nil)
+	}
+	return sh.settleEvidence(ctx, sm, uint64(iotextypes.ReceiptStatus_Success), si, protocolAddr, l)
+}
+
+func (sh *Slasher) jailOffender(
+	ctx context.Context,
+	exec *action.Execution,
+	sm protocol.StateManager,
+	protocolAddr address.Address,
+) (*action.Log, error) {
+	actionCtx := protocol.MustGetActionCtx(ctx)
+	blkCtx := protocol.MustGetBlockCtx(ctx)
+	rp := rolldpos.MustGetProtocol(protocol.MustGetRegistry(ctx))
+	if exec.Amount() != nil && exec.Amount().Sign() != 0 {
+		return nil, errors.Wrap(ErrInvalidEvidence, "evidence does not accept amount")
+	}
+	caller, err := accountutil.LoadAccount(sm, hash.BytesToHash160(actionCtx.Caller.Bytes()))
+	if err != nil {
+		return nil, err
+	}
+	gasFee := new(big.Int).Mul(actionCtx.GasPrice, new(big.Int).SetUint64(actionCtx.IntrinsicGas))
+	if gasFee.Cmp(caller.Balance) > 0 {
+		return nil, errors.Wrapf(state.ErrNotEnoughBalance, "caller %s balance not enough", actionCtx.Caller.String())
+	}
+	e := &Equivocation{}
+	if err := e.Deserialize(exec.Data()); err != nil {
+		return nil, err
+	}
+	offender, err := e.Offender()
+	if err != nil {
+		return nil, err
+	}
+	if e.Height() >= blkCtx.BlockHeight {
+		return nil, errors.Wrapf(ErrInvalidEvidence, "evidence of future height %d", e.Height())
+	}
+
+	epochNum := rp.GetEpochNum(blkCtx.BlockHeight)
+	to := rp.GetEpochNum(e.Height()) + sh.jailPeriod
+	if to <= epochNum {
+		return nil, errors.Wrapf(ErrInvalidEvidence, "jail period of the double signing at height %d has passed", e.Height())
+	}
+	jl, err := candidatesutil.JailListFromDB(sm)
+	if err != nil {
+		return nil, err
+	}
+	jl.Release(epochNum)
+	if !jl.Jail(offender.String(), epochNum+1, to) {
+		return nil, errors.Wrapf(ErrInvalidEvidence, "%s is already jailed till epoch %d", offender.String(), to)
+	}
+	if err := setJailList(sm, jl); err != nil {
+		return nil, err
+	}
+	if err := sh.slashStake(ctx, sm, offender); err != nil {
+		return nil, err
+	}
+	return &action.Log{
+		Address: protocolAddr.String(),
+		Topics: action.Topics{
+			DelegateJailedTopic,
+			hash.BytesToHash256(offender.Bytes()),
+			hash.BytesToHash256(byteutil.Uint64ToBytesBigEndian(to)),
+		},
+		BlockHeight: blkCtx.BlockHeight,
+		ActionHash:  actionCtx.ActionHash,
+	}, nil
+}
+
+func (sh *Slasher) settleEvidence(
+	ctx context.Context,
+	sm protocol.StateManager,
+	status uint64,
+	si int,
+	protocolAddr address.Address,
+	l *action.Log,
+) (*action.Receipt, error) {
+	actionCtx := protocol.MustGetActionCtx(ctx)
+	blkCtx := protocol.MustGetBlockCtx(ctx)
+	if status == uint64(iotextypes.ReceiptStatus_Failure) {
+		if err := sm.Revert(si); err != nil {
+			return nil, err
+		}
+	}
+	gasFee := new(big.Int).Mul(actionCtx.GasPrice, new(big.Int).SetUint64(actionCtx.IntrinsicGas))
+	depositLog, err := sh.depositGas(ctx, sm, gasFee)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to deposit gas")
+	}
+	acc, err := accountutil.LoadOrCreateAccount(sm, actionCtx.Caller.String())
+	if err != nil {
+		return nil, err
+	}
+	// TODO: this check shouldn't be necessary
+	if actionCtx.Nonce > acc.Nonce {
+		acc.Nonce = actionCtx.Nonce
+	}
+	if err := accountutil.StoreAccount(sm, actionCtx.Caller, acc); err != nil {
+		return nil, errors.Wrap(err, "failed to update nonce")
+	}
+	r := action.Receipt{
+		Status:          status,
+		BlockHeight:     blkCtx.BlockHeight,
+		ActionHash:      actionCtx.ActionHash,
+		GasConsumed:     actionCtx.IntrinsicGas,
+		ContractAddress: protocolAddr.String(),
+	}
+	r.AddLogs(l).AddTransactionLogs(depositLog)
+	return &r, nil
+}
+
+// Offender verifies the evidence and returns the address of the delegate double signing
+func (e *Equivocation) Offender() (address.Address, error) {
+	if e.First == nil || e.Second == nil || e.First.Header == nil || e.Second.Header == nil {
+		return nil, errors.Wrap(ErrInvalidEvidence, "missing signed vote")
+	}
+	h1, h2 := e.First.Header, e.Second.Header
+	if h1.Height() != h2.Height() || !h1.Timestamp().Equal(h2.Timestamp()) {
+		return nil, errors.Wrap(ErrInvalidEvidence, "blocks are not of the same height and round")
+	}
+	if h1.HashBlock() == h2.HashBlock() {
+		return nil, errors.Wrap(ErrInvalidEvidence, "votes are on the same block")
+	}
+	if (e.First.Endorsement == nil) != (e.Second.Endorsement == nil) || e.First.Topic != e.Second.Topic {
+		return nil, errors.Wrap(ErrInvalidEvidence, "votes are not of the same topic")
+	}
+	first, err := e.First.signer()
+	if err != nil {
+		return nil, err
+	}
+	second, err := e.Second.signer()
+	if err != nil {
+		return nil, err
+	}
+	if !address.Equal(first, second) {
+		return nil, errors.Wrap(ErrInvalidEvidence, "votes are signed by different delegates")
+	}
+	return first, nil
+}
+
+// Height returns the height of the blocks double signed
+func (e *Equivocation) Height() uint64 {
+	return e.First.Header.Height()
+}
+
+// Serialize serializes the evidence into the data of the execution to the poll protocol address
+func (e *Equivocation) Serialize() ([]byte, error) {
+	first, err := e.First.toProto()
+	if err != nil {
+		return nil, err
+	}
+	second, err := e.Second.toProto()
+	if err != nil {
+		return nil, err
+	}
+	return proto.Marshal(&evidencepb.Equivocation{
+		First:  first,
+		Second: second,
+	})
+}
+
+// Deserialize deserializes the evidence from the data of the execution to the poll protocol address
+func (e *Equivocation) Deserialize(data []byte) error {
+	epb := &evidencepb.Equivocation{}
+	if err := proto.Unmarshal(data, epb); err != nil {
+		return errors.Wrap(ErrInvalidEvidence, err.Error())
+	}
+	if epb.First == nil || epb.Second == nil {
+		return errors.Wrap(ErrInvalidEvidence, "missing signed vote")
+	}
+	e.First, e.Second = &SignedVote{}, &SignedVote{}
+	if err := e.First.loadProto(epb.First); err != nil {
+		return err
+	}
+	return e.Second.loadProto(epb.Second)
+}
+
+// signer verifies the signature of the vote and returns the address of the signer
+func (v *SignedVote) signer() (address.Address, error) {
+	var pk crypto.PublicKey
+	if v.Endorsement == nil {
+		if !v.Header.VerifySignature() {
+			return nil, errors.Wrap(ErrInvalidEvidence, "invalid block signature")
+		}
+		pk = v.Header.PublicKey()
+	} else {
+		if v.Topic > _maxVoteTopic {
+			return nil, errors.Wrapf(ErrInvalidEvidence, "invalid topic %d", v.Topic)
+		}
+		doc := &consensusVote{
+			blkHash: v.Header.HashBlock(),
+			topic:   v.Topic,
+		}
+		if !endorsement.VerifyEndorsement(doc, v.Endorsement) {
+			return nil, errors.Wrap(ErrInvalidEvidence, "invalid endorsement")
+		}
+		pk = v.Endorsement.Endorser()
+	}
+	return address.FromBytes(pk.Hash())
+}
+
+func (v *SignedVote) toProto() (*evidencepb.SignedVote, error) {
+	header, err := v.Header.Serialize()
+	if err != nil {
+		return nil, err
+	}
+	vpb := &evidencepb.SignedVote{
+		Header: header,
+		Topic:  v.Topic,
+	}
+	if v.Endorsement != nil {
+		enpb, err := v.Endorsement.Proto()
+		if err != nil {
+			return nil, err
+		}
+		if vpb.Endorsement, err = proto.Marshal(enpb); err != nil {
+			return nil, err
+		}
+	}
+	return vpb, nil
+}
+
+func (v *SignedVote) loadProto(vpb *evidencepb.SignedVote) error {
+	v.Header = &block.Header{}
+	if err := v.Header.Deserialize(vpb.Header); err != nil {
+		return errors.Wrap(ErrInvalidEvidence, err.Error())
+	}
+	v.Topic = vpb.Topic
+	v.Endorsement = nil
+	if len(vpb.Endorsement) == 0 {
+		return nil
+	}
+	enpb := &iotextypes.Endorsement{}
+	if err := proto.Unmarshal(vpb.Endorsement, enpb); err != nil {
+		return errors.Wrap(ErrInvalidEvidence, err.Error())
+	}
+	v.Endorsement = &endorsement.Endorsement{}
+	if err := v.Endorsement.LoadProto(enpb); err != nil {
+		return errors.Wrap(ErrInvalidEvidence, err.Error())
+	}
+	return nil
+}
+
+// Hash returns the hash of the vote, which is the same as the one of rolldpos.ConsensusVote
+func (v *consensusVote) Hash() ([]byte, error) {
+	ser, err := proto.Marshal(&iotextypes.ConsensusVote{
+		BlockHash: v.blkHash[:],
+		Topic:     iotextypes.ConsensusVote_Topic(v.topic),
+	})
+	if err != nil {
+		return nil, err
+	}
+	h := blake2b.Sum256(ser)
+	return h[:], nil
+}
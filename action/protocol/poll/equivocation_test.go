@@ -0,0 +1,198 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package poll
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/iotexproject/go-pkgs/crypto"
+	"github.com/iotexproject/go-pkgs/hash"
+	"github.com/iotexproject/iotex-proto/golang/iotextypes"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/action"
+	"github.com/iotexproject/iotex-core/action/protocol"
+	"github.com/iotexproject/iotex-core/action/protocol/rolldpos"
+	"github.com/iotexproject/iotex-core/action/protocol/vote"
+	"github.com/iotexproject/iotex-core/action/protocol/vote/candidatesutil"
+	"github.com/iotexproject/iotex-core/blockchain/block"
+	"github.com/iotexproject/iotex-core/blockchain/genesis"
+	"github.com/iotexproject/iotex-core/endorsement"
+	"github.com/iotexproject/iotex-core/state"
+	"github.com/iotexproject/iotex-core/test/identityset"
+	"github.com/iotexproject/iotex-core/testutil/testdb"
+)
+
+func testHeader(t *testing.T, height uint64, ts time.Time, prev hash.Hash256, sk crypto.PrivateKey) *block.Header {
+	blk, err := block.NewTestingBuilder().
+		SetHeight(height).
+		SetTimeStamp(ts).
+		SetPrevBlockHash(prev).
+		SignAndBuild(sk)
+	require.NoError(t, err)
+	return &blk.Header
+}
+
+func testVote(t *testing.T, h *block.Header, topic uint32, sk crypto.PrivateKey) *SignedVote {
+	en, err := endorsement.Endorse(sk, &consensusVote{blkHash: h.HashBlock(), topic: topic}, h.Timestamp())
+	require.NoError(t, err)
+	return &SignedVote{Header: h, Topic: topic, Endorsement: en}
+}
+
+func TestEquivocation(t *testing.T) {
+	require := require.New(t)
+	ts := time.Unix(1600000000, 0)
+	proposer, endorser := identityset.PrivateKey(1), identityset.PrivateKey(2)
+	h1 := testHeader(t, 10, ts, hash.Hash256b([]byte("a")), proposer)
+	h2 := testHeader(t, 10, ts, hash.Hash256b([]byte("b")), proposer)
+
+	// the proposer signs two blocks
+	e := &Equivocation{First: &SignedVote{Header: h1}, Second: &SignedVote{Header: h2}}
+	offender, err := e.Offender()
+	require.NoError(err)
+	require.Equal(identityset.Address(1).String(), offender.String())
+	data, err := e.Serialize()
+	require.NoError(err)
+	e2 := &Equivocation{}
+	require.NoError(e2.Deserialize(data))
+	offender, err = e2.Offender()
+	require.NoError(err)
+	require.Equal(identityset.Address(1).String(), offender.String())
+
+	// the endorser votes on both of them
+	e = &Equivocation{First: testVote(t, h1, 1, endorser), Second: testVote(t, h2, 1, endorser)}
+	offender, err = e.Offender()
+	require.NoError(err)
+	require.Equal(identityset.Address(2).String(), offender.String())
+	data, err = e.Serialize()
+	require.NoError(err)
+	require.NoError(e2.Deserialize(data))
+	offender, err = e2.Offender()
+	require.NoError(err)
+	require.Equal(identityset.Address(2).String(), offender.String())
+
+	h3 := testHeader(t, 10, ts.Add(time.Second), hash.Hash256b([]byte("c")), proposer)
+	h4 := testHeader(t, 11, ts, hash.Hash256b([]byte("d")), proposer)
+	for _, e := range []*Equivocation{
+		// same block
+		{First: testVote(t, h1, 1, endorser), Second: testVote(t, h1, 1, endorser)},
+		// different rounds
+		{First: testVote(t, h1, 1, endorser), Second: testVote(t, h3, 1, endorser)},
+		// different heights
+		{First: &SignedVote{Header: h1}, Second: &SignedVote{Header: h4}},
+		// different topics
+		{First: testVote(t, h1, 1, endorser), Second: testVote(t, h2, 2, endorser)},
+		// different delegates
+		{First: testVote(t, h1, 1, endorser), Second: testVote(t, h2, 1, identityset.PrivateKey(3))},
+		// invalid topic
+		{First: testVote(t, h1, 3, endorser), Second: testVote(t, h2, 3, endorser)},
+		// a proposal and a vote
+		{First: &SignedVote{Header: h1}, Second: testVote(t, h2, 0, proposer)},
+		{First: &SignedVote{Header: h1}},
+	} {
+		_, err := e.Offender()
+		require.Equal(ErrInvalidEvidence, errors.Cause(err))
+	}
+	require.Equal(ErrInvalidEvidence, errors.Cause(e2.Deserialize([]byte{1, 2, 3})))
+}
+
+func TestHandleEquivocation(t *testing.T) {
+	require := require.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	g := genesis.Default
+	g.EquivocationJailPeriod = 2
+	g.KamchatkaBlockHeight = 5
+	sh, err := NewSlasher(&g, nil, nil, nil, nil, nil, 6, 4, 1, 85, 2, 4, 90)
+	require.NoError(err)
+	sh.depositGas = func(context.Context, protocol.StateManager, *big.Int) (*action.TransactionLog, error) {
+		return nil, nil
+	}
+	registry := protocol.NewRegistry()
+	rp := rolldpos.NewProtocol(36, 6, 5)
+	require.NoError(registry.Register("rolldpos", rp))
+	sm := testdb.NewMockStateManager(ctrl)
+	addr := identityset.Address(31)
+
+	ts := time.Unix(1600000000, 0)
+	h1 := testHeader(t, 10, ts, hash.Hash256b([]byte("a")), identityset.PrivateKey(1))
+	h2 := testHeader(t, 10, ts, hash.Hash256b([]byte("b")), identityset.PrivateKey(1))
+	e := &Equivocation{
+		First:  testVote(t, h1, 2, identityset.PrivateKey(2)),
+		Second: testVote(t, h2, 2, identityset.PrivateKey(2)),
+	}
+	data, err := e.Serialize()
+	require.NoError(err)
+	submit := func(height uint64, data []byte) *action.Receipt {
+		exec, err := action.NewExecution(addr.String(), 1, big.NewInt(0), 100000, big.NewInt(0), data)
+		require.NoError(err)
+		ctx := protocol.WithRegistry(context.Background(), registry)
+		ctx = protocol.WithBlockCtx(ctx, protocol.BlockCtx{BlockHeight: height})
+		ctx = protocol.WithActionCtx(ctx, protocol.ActionCtx{
+			Caller:   identityset.Address(27),
+			GasPrice: big.NewInt(0),
+		})
+		r, err := sh.HandleEquivocation(ctx, exec, sm, addr)
+		require.NoError(err)
+		return r
+	}
+
+	// the evidence is not handled before kamchatka height
+	require.Nil(submit(4, data))
+	require.False(sh.jailEnabled(4))
+	require.True(sh.jailEnabled(5))
+
+	// epoch 1 is of height [1, 30], the delegate is jailed in epochs [2, 3]
+	r := submit(20, data)
+	require.Equal(uint64(iotextypes.ReceiptStatus_Success), r.Status)
+	require.Equal(1, len(r.Logs()))
+	require.Equal(DelegateJailedTopic, r.Logs()[0].Topics[0])
+	jl, err := candidatesutil.JailListFromDB(sm)
+	require.NoError(err)
+	require.Equal(vote.JailTerm{From: 2, To: 3}, jl.Terms[identityset.Address(2).String()])
+
+	// the evidence is accepted once
+	require.Equal(uint64(iotextypes.ReceiptStatus_Failure), submit(21, data).Status)
+	// invalid evidence
+	require.Equal(uint64(iotextypes.ReceiptStatus_Failure), submit(21, []byte{1}).Status)
+	// the evidence is not of the past
+	require.Equal(uint64(iotextypes.ReceiptStatus_Failure), submit(10, data).Status)
+
+	// the jailed delegate has zero voting power in epochs [2, 3]
+	candidates := state.CandidateList{
+		{Address: identityset.Address(2).String(), Votes: big.NewInt(30)},
+		{Address: identityset.Address(3).String(), Votes: big.NewInt(20)},
+	}
+	for _, c := range []struct {
+		epochNum uint64
+		jailed   bool
+	}{
+		{1, false},
+		{2, true},
+		{3, true},
+		{4, false},
+	} {
+		filtered := jailCandidates(candidates, jl, c.epochNum)
+		if c.jailed {
+			require.Equal(identityset.Address(3).String(), filtered[0].Address)
+			require.Equal(0, filtered[1].Votes.Sign())
+		} else {
+			require.Equal(candidates, filtered)
+		}
+	}
+
+	// the evidence is not handled if the jail period is not set
+	sh.jailPeriod = 0
+	require.Nil(submit(21, data))
+	require.False(sh.jailEnabled(21))
+}
@@ -21,6 +21,7 @@ import (
 	"github.com/iotexproject/iotex-core/action"
 	"github.com/iotexproject/iotex-core/action/protocol"
 	"github.com/iotexproject/iotex-core/action/protocol/rolldpos"
+	"github.com/iotexproject/iotex-core/action/protocol/vote"
 	"github.com/iotexproject/iotex-core/pkg/log"
 	"github.com/iotexproject/iotex-core/state"
 )
@@ -107,10 +108,22 @@ func (p *governanceChainCommitteeProtocol) CreatePreStates(ctx context.Context,
 	return p.sh.CreatePreStates(ctx, sm, p.indexer)
 }
 
+func (p *governanceChainCommitteeProtocol) CreateSystemLogs(ctx context.Context, sm protocol.StateManager) ([]*action.Log, error) {
+	return p.sh.CreateSystemLogs(ctx, sm, p.addr)
+}
+
 func (p *governanceChainCommitteeProtocol) Handle(ctx context.Context, act action.Action, sm protocol.StateManager) (*action.Receipt, error) {
+	if r, err := p.sh.HandleEquivocation(ctx, act, sm, p.addr); r != nil || err != nil {
+		return r, err
+	}
 	return handle(ctx, act, sm, p.indexer, p.addr.String())
 }
 
+// Commit flushes the candidate/probation lists written in the block into the indexer
+func (p *governanceChainCommitteeProtocol) Commit(ctx context.Context, sm protocol.StateManager) error {
+	return p.indexer.Flush(sm)
+}
+
 func (p *governanceChainCommitteeProtocol) Validate(ctx context.Context, act action.Action, sr protocol.StateReader) error {
 	return validate(ctx, sr, p, act)
 }
@@ -171,6 +184,22 @@ func (p *governanceChainCommitteeProtocol) CalculateUnproductiveDelegates(
 	return p.sh.calculateUnproductiveDelegates(ctx, sr)
 }
 
+func (p *governanceChainCommitteeProtocol) SimulateProbationList(
+	ctx context.Context,
+	sr protocol.StateReader,
+	produce map[string]uint64,
+) (*vote.ProbationList, error) {
+	return p.sh.SimulateProbationList(ctx, sr, produce)
+}
+
+func (p *governanceChainCommitteeProtocol) ProbationList(
+	ctx context.Context,
+	sr protocol.StateReader,
+) (*vote.ProbationList, error) {
+	probationList, _, err := p.sh.GetProbationList(ctx, sr, false)
+	return probationList, err
+}
+
 func (p *governanceChainCommitteeProtocol) Delegates(ctx context.Context, sr protocol.StateReader) (state.CandidateList, error) {
 	delegates, _, err := p.sh.GetActiveBlockProducers(ctx, sr, false)
 	return delegates, err
@@ -200,11 +229,11 @@ func (p *governanceChainCommitteeProtocol) ReadState(
 	switch string(method) {
 	case "GetGravityChainStartHeight":
 		if len(args) != 1 {
-			return nil, uint64(0), errors.Errorf("invalid number of arguments %d", len(args))
+			return nil, uint64(0), errors.Wrapf(protocol.ErrInvalidArgument, "invalid number of arguments %d", len(args))
 		}
 		nativeHeight, err := strconv.ParseUint(string(args[0]), 10, 64)
 		if err != nil {
-			return nil, uint64(0), err
+			return nil, uint64(0), errors.Wrap(protocol.ErrInvalidArgument, err.Error())
 		}
 		gravityStartheight, err := p.getGravityHeight(ctx, nativeHeight)
 		if err != nil {
@@ -9,11 +9,13 @@ package poll
 import (
 	"context"
 	"math/big"
+	"sort"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/require"
 
 	"github.com/iotexproject/go-pkgs/hash"
@@ -29,6 +31,7 @@ import (
 	"github.com/iotexproject/iotex-core/config"
 	"github.com/iotexproject/iotex-core/db"
 	"github.com/iotexproject/iotex-core/db/batch"
+	"github.com/iotexproject/iotex-core/pkg/util/byteutil"
 	"github.com/iotexproject/iotex-core/state"
 	"github.com/iotexproject/iotex-core/test/identityset"
 	"github.com/iotexproject/iotex-core/test/mock/mock_chainmanager"
@@ -104,6 +107,15 @@ func initConstruct(ctrl *gomock.Controller) (Protocol, context.Context, protocol
 			cb.Delete(cfg.Namespace, cfg.Key, "failed to delete state")
 			return 0, nil
 		}).AnyTimes()
+	dk := protocol.NewDock()
+	sm.EXPECT().Load(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ns, key string, v interface{}) error {
+			return dk.Load(ns, key, v)
+		}).AnyTimes()
+	sm.EXPECT().Unload(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ns, key string, v interface{}) error {
+			return dk.Unload(ns, key, v)
+		}).AnyTimes()
 	sm.EXPECT().Snapshot().Return(1).AnyTimes()
 	sm.EXPECT().Height().Return(epochStartHeight-1, nil).AnyTimes()
 	r := types.NewElectionResultForTest(time.Now())
@@ -355,6 +367,128 @@ func TestCreatePreStates(t *testing.T) {
 	}
 }
 
+func TestCreateSystemLogs(t *testing.T) {
+	require := require.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	p, ctx, sm, _, err := initConstruct(ctrl)
+	require.NoError(err)
+
+	slc, ok := p.(protocol.SystemLogsCreator)
+	require.True(ok)
+	bcCtx := protocol.MustGetBlockchainCtx(ctx)
+	psc, ok := p.(protocol.PreStatesCreator)
+	require.True(ok)
+	rp := rolldpos.MustGetProtocol(protocol.MustGetRegistry(ctx))
+	bcCtx.Tip.Height = 0
+	ctx = protocol.WithBlockchainCtx(ctx, bcCtx)
+	ctx = protocol.WithBlockCtx(
+		ctx,
+		protocol.BlockCtx{
+			BlockHeight: 1,
+			Producer:    identityset.Address(1),
+		},
+	)
+	require.NoError(psc.CreatePreStates(ctx, sm))
+	candidates, err := p.Candidates(ctx, sm)
+	require.NoError(err)
+	require.NoError(setCandidates(ctx, sm, nil, candidates, rp.GetEpochHeight(2)))
+
+	// at last of epoch, the probation list of next epoch is written
+	epochLastHeight := rp.GetEpochLastBlockHeight(1)
+	bcCtx.Tip.Height = epochLastHeight - 1
+	ctx = protocol.WithBlockchainCtx(ctx, bcCtx)
+	ctx = protocol.WithBlockCtx(
+		ctx,
+		protocol.BlockCtx{
+			BlockHeight: epochLastHeight,
+			Producer:    identityset.Address(1),
+		},
+	)
+	require.NoError(psc.CreatePreStates(ctx, sm))
+
+	// no event before the activation height
+	logs, err := slc.CreateSystemLogs(ctx, sm)
+	require.NoError(err)
+	require.Empty(logs)
+
+	p.(*governanceChainCommitteeProtocol).sh.probationEventHeight = epochLastHeight
+	logs, err = slc.CreateSystemLogs(ctx, sm)
+	require.NoError(err)
+	// A, B and C are on the probation list of epoch 2
+	expected := []string{
+		identityset.Address(1).String(),
+		identityset.Address(2).String(),
+		identityset.Address(3).String(),
+	}
+	sort.Strings(expected)
+	require.Len(logs, len(expected))
+	for i, l := range logs {
+		addr, err := address.FromString(expected[i])
+		require.NoError(err)
+		require.Equal(p.(*governanceChainCommitteeProtocol).addr.String(), l.Address)
+		require.Equal(action.Topics{DelegateProbationTopic, hash.BytesToHash256(addr.Bytes())}, l.Topics)
+		require.Equal(append(byteutil.Uint32ToBytesBigEndian(90), byteutil.Uint64ToBytesBigEndian(2)...), l.Data)
+		require.Equal(epochLastHeight, l.BlockHeight)
+	}
+
+	// no event in the middle of the epoch
+	ctx = protocol.WithBlockCtx(
+		ctx,
+		protocol.BlockCtx{
+			BlockHeight: epochLastHeight - 1,
+			Producer:    identityset.Address(1),
+		},
+	)
+	p.(*governanceChainCommitteeProtocol).sh.probationEventHeight = 1
+	logs, err = slc.CreateSystemLogs(ctx, sm)
+	require.NoError(err)
+	require.Empty(logs)
+}
+
+func TestReadNextProbationList(t *testing.T) {
+	require := require.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	p, ctx, sm, _, err := initConstruct(ctrl)
+	require.NoError(err)
+
+	// the blocks produced in epoch 1 till the tip put A, B and C on the probation list of epoch 2
+	data, height, err := p.ReadState(ctx, sm, []byte("NextProbationList"))
+	require.NoError(err)
+	require.Equal(uint64(30), height)
+	pl := &vote.ProbationList{}
+	require.NoError(pl.Deserialize(data))
+	require.Equal(uint32(90), pl.IntensityRate)
+	require.Equal(map[string]uint32{
+		identityset.Address(1).String(): 1,
+		identityset.Address(2).String(): 1,
+		identityset.Address(3).String(): 1,
+	}, pl.ProbationInfo)
+
+	// nothing is written into state
+	key := candidatesutil.ConstructKey(candidatesutil.UnproductiveDelegateKey)
+	_, err = sm.State(&vote.UnproductiveDelegate{}, protocol.KeyOption(key[:]), protocol.NamespaceOption(protocol.SystemNamespace))
+	require.Equal(state.ErrStateNotExist, err)
+
+	// only the current epoch can be simulated
+	_, _, err = p.ReadState(ctx, sm, []byte("NextProbationList"), []byte("2"))
+	require.Equal(protocol.ErrInvalidArgument, errors.Cause(err))
+}
+
+func TestReadProbationHistory(t *testing.T) {
+	require := require.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	p, ctx, sm, _, err := initConstruct(ctrl)
+	require.NoError(err)
+
+	_, _, err = p.ReadState(ctx, sm, []byte("ProbationHistory"))
+	require.Equal(protocol.ErrInvalidArgument, errors.Cause(err))
+	_, _, err = p.ReadState(ctx, sm, []byte("ProbationHistory"), []byte("1"))
+	require.Equal(protocol.ErrInvalidArgument, errors.Cause(err))
+}
+
 func TestHandle(t *testing.T) {
 	require := require.New(t)
 	ctrl := gomock.NewController(t)
@@ -113,7 +113,16 @@ func (p *lifeLongDelegatesProtocol) ReadState(
 	}
 	switch string(method) {
 	case "CandidatesByEpoch":
-		fallthrough
+		offset, limit, paged, err := candidatePageArgs(args)
+		if err != nil {
+			return nil, uint64(0), err
+		}
+		if paged {
+			data, err := NewCandidatePage(p.delegates, offset, limit).Serialize()
+			return data, height, err
+		}
+		bp, err := p.readBlockProducers()
+		return bp, height, err
 	case "BlockProducersByEpoch":
 		fallthrough
 	case "ActiveBlockProducersByEpoch":
@@ -121,11 +130,11 @@ func (p *lifeLongDelegatesProtocol) ReadState(
 		return bp, height, err
 	case "GetGravityChainStartHeight":
 		if len(args) != 1 {
-			return nil, uint64(0), errors.Errorf("invalid number of arguments %d", len(args))
+			return nil, uint64(0), errors.Wrapf(protocol.ErrInvalidArgument, "invalid number of arguments %d", len(args))
 		}
 		return args[0], height, nil
 	default:
-		return nil, uint64(0), errors.New("corresponding method isn't found")
+		return nil, uint64(0), errors.Wrapf(protocol.ErrNotFound, "corresponding method %s isn't found", method)
 	}
 }
 
@@ -144,6 +153,11 @@ func (p *lifeLongDelegatesProtocol) Name() string {
 	return protocolID
 }
 
+// Dependencies returns the IDs of the protocols which the protocol depends on
+func (p *lifeLongDelegatesProtocol) Dependencies() []string {
+	return []string{rolldpos.ProtocolID}
+}
+
 func (p *lifeLongDelegatesProtocol) readBlockProducers() ([]byte, error) {
 	return p.delegates.Serialize()
 }
@@ -10,6 +10,7 @@ import (
 	"github.com/iotexproject/iotex-core/action"
 	"github.com/iotexproject/iotex-core/action/protocol"
 	"github.com/iotexproject/iotex-core/action/protocol/staking"
+	"github.com/iotexproject/iotex-core/action/protocol/vote"
 	"github.com/iotexproject/iotex-core/state"
 	"github.com/iotexproject/iotex-election/util"
 )
@@ -64,14 +65,26 @@ func (ns *nativeStakingV2) CreatePreStates(ctx context.Context, sm protocol.Stat
 	return ns.slasher.CreatePreStates(ctx, sm, ns.candIndexer)
 }
 
+func (ns *nativeStakingV2) CreateSystemLogs(ctx context.Context, sm protocol.StateManager) ([]*action.Log, error) {
+	return ns.slasher.CreateSystemLogs(ctx, sm, ns.addr)
+}
+
 func (ns *nativeStakingV2) CreatePostSystemActions(ctx context.Context, sr protocol.StateReader) ([]action.Envelope, error) {
 	return createPostSystemActions(ctx, sr, ns)
 }
 
 func (ns *nativeStakingV2) Handle(ctx context.Context, act action.Action, sm protocol.StateManager) (*action.Receipt, error) {
+	if r, err := ns.slasher.HandleEquivocation(ctx, act, sm, ns.addr); r != nil || err != nil {
+		return r, err
+	}
 	return handle(ctx, act, sm, ns.candIndexer, ns.addr.String())
 }
 
+// Commit flushes the candidate/probation lists written in the block into the indexer
+func (ns *nativeStakingV2) Commit(ctx context.Context, sm protocol.StateManager) error {
+	return ns.candIndexer.Flush(sm)
+}
+
 func (ns *nativeStakingV2) Validate(ctx context.Context, act action.Action, sr protocol.StateReader) error {
 	return validate(ctx, sr, ns, act)
 }
@@ -93,6 +106,22 @@ func (ns *nativeStakingV2) CalculateUnproductiveDelegates(
 	return ns.slasher.calculateUnproductiveDelegates(ctx, sr)
 }
 
+func (ns *nativeStakingV2) SimulateProbationList(
+	ctx context.Context,
+	sr protocol.StateReader,
+	produce map[string]uint64,
+) (*vote.ProbationList, error) {
+	return ns.slasher.SimulateProbationList(ctx, sr, produce)
+}
+
+func (ns *nativeStakingV2) ProbationList(
+	ctx context.Context,
+	sr protocol.StateReader,
+) (*vote.ProbationList, error) {
+	probationList, _, err := ns.slasher.GetProbationList(ctx, sr, false)
+	return probationList, err
+}
+
 // Delegates returns exact number of delegates of current epoch
 func (ns *nativeStakingV2) Delegates(ctx context.Context, sr protocol.StateReader) (state.CandidateList, error) {
 	delegates, _, err := ns.slasher.GetActiveBlockProducers(ctx, sr, false)
@@ -14,6 +14,7 @@ import (
 	"github.com/iotexproject/iotex-election/committee"
 	"github.com/pkg/errors"
 
+	"github.com/iotexproject/iotex-core/action"
 	"github.com/iotexproject/iotex-core/action/protocol"
 	"github.com/iotexproject/iotex-core/action/protocol/execution/evm"
 	"github.com/iotexproject/iotex-core/action/protocol/staking"
@@ -68,6 +69,12 @@ type (
 	// Productivity returns the number of produced blocks per producer
 	Productivity func(uint64, uint64) (map[string]uint64, error)
 
+	// DepositGas deposits gas to some pool
+	DepositGas func(ctx context.Context, sm protocol.StateManager, amount *big.Int) (*action.TransactionLog, error)
+
+	// CreditFund credits the amount taken out of the staking bucket pool into the rewarding fund
+	CreditFund func(ctx context.Context, sm protocol.StateManager, amount *big.Int) error
+
 	// Protocol defines the protocol of handling votes
 	Protocol interface {
 		protocol.Protocol
@@ -83,6 +90,19 @@ type (
 		// CalculateUnproductiveDelegates calculates unproductive delegate on current epoch
 		CalculateUnproductiveDelegates(context.Context, protocol.StateReader) ([]string, error)
 	}
+
+	// ProbationSimulator is implemented by the protocols putting unproductive delegates on probation
+	ProbationSimulator interface {
+		// SimulateProbationList calculates the probation list of the next epoch from the given productivity of the
+		// current epoch, without writing into state DB
+		SimulateProbationList(context.Context, protocol.StateReader, map[string]uint64) (*vote.ProbationList, error)
+	}
+
+	// ProbationReader is implemented by the protocols putting unproductive delegates on probation
+	ProbationReader interface {
+		// ProbationList returns the probation list of the current epoch
+		ProbationList(context.Context, protocol.StateReader) (*vote.ProbationList, error)
+	}
 )
 
 // FindProtocol finds the registered protocol from registry
@@ -132,6 +152,8 @@ func NewProtocol(
 	getBlockTimeFunc GetBlockTime,
 	productivity Productivity,
 	getBlockHash evm.GetBlockHash,
+	depositGas DepositGas,
+	creditFund CreditFund,
 ) (Protocol, error) {
 	genesisConfig := cfg.Genesis
 	if cfg.Consensus.Scheme != config.RollDPoSScheme {
@@ -165,6 +187,12 @@ func NewProtocol(
 		if err != nil {
 			return nil, err
 		}
+		slasher.enableShadowRead = cfg.Chain.EnablePollShadowRead
+		slasher.depositGas = depositGas
+		slasher.creditFund = creditFund
+		if stakingProto != nil {
+			slasher.stakeSlasher = stakingProto
+		}
 		scoreThreshold, ok = new(big.Int).SetString(cfg.Genesis.ScoreThreshold, 10)
 		if !ok {
 			return nil, errors.Errorf("failed to parse score threshold %s", cfg.Genesis.ScoreThreshold)
@@ -0,0 +1,90 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package poll
+
+import (
+	"context"
+
+	"github.com/iotexproject/iotex-core/action/protocol"
+	"github.com/iotexproject/iotex-core/action/protocol/rolldpos"
+	"github.com/iotexproject/iotex-core/blockchain/genesis"
+	"github.com/iotexproject/iotex-core/state"
+)
+
+// shadowForkProtocol replaces the delegates of the poll protocol with the local ones after the fork height, so that a
+// devnet forked from the state of another chain is run by the local keys, while the blocks before the fork height are
+// still replayed with the original delegates
+type shadowForkProtocol struct {
+	Protocol
+	forkHeight uint64
+	local      Protocol
+}
+
+// NewShadowForkProtocol wraps the poll protocol to replace the delegates after the fork height
+func NewShadowForkProtocol(p Protocol, forkHeight uint64, delegates []genesis.Delegate) Protocol {
+	return &shadowForkProtocol{
+		Protocol:   p,
+		forkHeight: forkHeight,
+		local:      NewLifeLongDelegatesProtocol(delegates),
+	}
+}
+
+// Register registers the protocol with a unique ID
+func (p *shadowForkProtocol) Register(r *protocol.Registry) error {
+	return r.Register(protocolID, p)
+}
+
+// ForceRegister registers the protocol with a unique ID and force replacing the previous protocol if it exists
+func (p *shadowForkProtocol) ForceRegister(r *protocol.Registry) error {
+	return r.ForceRegister(protocolID, p)
+}
+
+// Dependencies returns the IDs of the protocols which the wrapped protocol depends on
+func (p *shadowForkProtocol) Dependencies() []string {
+	if d, ok := p.Protocol.(protocol.Dependent); ok {
+		return d.Dependencies()
+	}
+	return []string{rolldpos.ProtocolID}
+}
+
+func (p *shadowForkProtocol) Delegates(ctx context.Context, sr protocol.StateReader) (state.CandidateList, error) {
+	return p.pick(ctx, sr).Delegates(ctx, sr)
+}
+
+func (p *shadowForkProtocol) NextDelegates(ctx context.Context, sr protocol.StateReader) (state.CandidateList, error) {
+	return p.pick(ctx, sr).NextDelegates(ctx, sr)
+}
+
+func (p *shadowForkProtocol) Candidates(ctx context.Context, sr protocol.StateReader) (state.CandidateList, error) {
+	return p.pick(ctx, sr).Candidates(ctx, sr)
+}
+
+func (p *shadowForkProtocol) NextCandidates(ctx context.Context, sr protocol.StateReader) (state.CandidateList, error) {
+	return p.pick(ctx, sr).NextCandidates(ctx, sr)
+}
+
+func (p *shadowForkProtocol) CalculateUnproductiveDelegates(
+	ctx context.Context,
+	sr protocol.StateReader,
+) ([]string, error) {
+	return p.pick(ctx, sr).CalculateUnproductiveDelegates(ctx, sr)
+}
+
+// pick returns the local delegates protocol if the block being processed, or the tip if no block is being processed,
+// is after the fork height
+func (p *shadowForkProtocol) pick(ctx context.Context, sr protocol.StateReader) Protocol {
+	if blkCtx, ok := protocol.GetBlockCtx(ctx); ok {
+		if blkCtx.BlockHeight > p.forkHeight {
+			return p.local
+		}
+		return p.Protocol
+	}
+	if height, err := sr.Height(); err == nil && height >= p.forkHeight {
+		return p.local
+	}
+	return p.Protocol
+}
@@ -0,0 +1,59 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package poll
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/action/protocol"
+	"github.com/iotexproject/iotex-core/blockchain/genesis"
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/test/identityset"
+	"github.com/iotexproject/iotex-core/test/mock/mock_chainmanager"
+)
+
+func TestShadowForkProtocol(t *testing.T) {
+	require := require.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	local := identityset.Address(0).String()
+	p := NewShadowForkProtocol(
+		NewLifeLongDelegatesProtocol(config.Default.Genesis.Delegates),
+		10,
+		[]genesis.Delegate{{OperatorAddrStr: local, RewardAddrStr: local, VotesStr: "10"}},
+	)
+	sr := mock_chainmanager.NewMockStateReader(ctrl)
+
+	for _, test := range []struct {
+		blkHeight, tipHeight uint64
+		withBlkCtx, isLocal  bool
+	}{
+		{10, 9, true, false},
+		{11, 10, true, true},
+		{0, 9, false, false},
+		{0, 10, false, true},
+	} {
+		ctx := context.Background()
+		if test.withBlkCtx {
+			ctx = protocol.WithBlockCtx(ctx, protocol.BlockCtx{BlockHeight: test.blkHeight})
+		}
+		sr.EXPECT().Height().Return(test.tipHeight, nil).MaxTimes(1)
+		candidates, err := p.Candidates(ctx, sr)
+		require.NoError(err)
+		if test.isLocal {
+			require.Len(candidates, 1)
+			require.Equal(local, candidates[0].Address)
+		} else {
+			require.Len(candidates, len(config.Default.Genesis.Delegates))
+		}
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package poll
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+
+	"github.com/iotexproject/iotex-core/action/protocol"
+	"github.com/iotexproject/iotex-core/pkg/log"
+	"github.com/iotexproject/iotex-core/state"
+)
+
+var shadowReadMtc = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "iotex_poll_shadow_read",
+		Help: "IoTeX poll candidates shadow read counter.",
+	},
+	[]string{"result"},
+)
+
+func init() {
+	prometheus.MustRegister(shadowReadMtc)
+}
+
+// shadowRead reads the candidates via the other path of the Easter switch, i.e., the legacy candidates keyed by height
+// if the candidates are read from the current/next candidates key and vice versa, and compares them with the ones
+// read. The result is only logged and counted, it never affects the candidates returned
+func (sh *Slasher) shadowRead(
+	sr protocol.StateReader,
+	height uint64,
+	beforeEaster bool,
+	readFromNext bool,
+	candidates state.CandidateList,
+) {
+	shadow, _, err := sh.getCandidates(sr, height, !beforeEaster, readFromNext)
+	if err != nil {
+		shadowReadMtc.WithLabelValues("error").Inc()
+		log.L().Debug(
+			"Failed to shadow read candidates.",
+			zap.Uint64("height", height),
+			zap.Bool("beforeEaster", beforeEaster),
+			zap.Error(err),
+		)
+		return
+	}
+	missing, unexpected, changed := diffCandidates(candidates, shadow)
+	if len(missing) == 0 && len(unexpected) == 0 && len(changed) == 0 {
+		shadowReadMtc.WithLabelValues("match").Inc()
+		return
+	}
+	shadowReadMtc.WithLabelValues("mismatch").Inc()
+	log.L().Warn(
+		"Shadow read candidates mismatch.",
+		zap.Uint64("height", height),
+		zap.Bool("beforeEaster", beforeEaster),
+		zap.Bool("readFromNext", readFromNext),
+		zap.Strings("missing", missing),
+		zap.Strings("unexpected", unexpected),
+		zap.Strings("changed", changed),
+	)
+}
+
+// diffCandidates returns the addresses of the candidates missing in the shadow list, the ones only in the shadow list,
+// and the ones whose votes, reward address or order differ
+func diffCandidates(candidates, shadow state.CandidateList) (missing, unexpected, changed []string) {
+	shadowIndex := make(map[string]int, len(shadow))
+	for i, cand := range shadow {
+		shadowIndex[cand.Address] = i
+	}
+	found := make(map[string]bool, len(candidates))
+	for i, cand := range candidates {
+		j, ok := shadowIndex[cand.Address]
+		if !ok {
+			missing = append(missing, cand.Address)
+			continue
+		}
+		found[cand.Address] = true
+		if i != j || !cand.Equal(shadow[j]) {
+			changed = append(changed, cand.Address)
+		}
+	}
+	for _, cand := range shadow {
+		if !found[cand.Address] {
+			unexpected = append(unexpected, cand.Address)
+		}
+	}
+	return
+}
@@ -0,0 +1,62 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package poll
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/action/protocol"
+	"github.com/iotexproject/iotex-core/blockchain/genesis"
+	"github.com/iotexproject/iotex-core/state"
+	"github.com/iotexproject/iotex-core/test/identityset"
+)
+
+func TestDiffCandidates(t *testing.T) {
+	require := require.New(t)
+
+	cand := func(i int, votes int64) *state.Candidate {
+		return &state.Candidate{
+			Address:       identityset.Address(i).String(),
+			Votes:         big.NewInt(votes),
+			RewardAddress: identityset.Address(i).String(),
+		}
+	}
+	candidates := state.CandidateList{cand(0, 30), cand(1, 20), cand(2, 10)}
+	missing, unexpected, changed := diffCandidates(candidates, state.CandidateList{cand(0, 30), cand(1, 20), cand(2, 10)})
+	require.Empty(missing)
+	require.Empty(unexpected)
+	require.Empty(changed)
+
+	missing, unexpected, changed = diffCandidates(candidates, state.CandidateList{cand(0, 30), cand(2, 15), cand(3, 5)})
+	require.Equal([]string{identityset.Address(1).String()}, missing)
+	require.Equal([]string{identityset.Address(3).String()}, unexpected)
+	require.Equal([]string{identityset.Address(2).String()}, changed)
+
+	// order differs
+	_, _, changed = diffCandidates(candidates, state.CandidateList{cand(1, 20), cand(0, 30), cand(2, 10)})
+	require.Equal([]string{identityset.Address(0).String(), identityset.Address(1).String()}, changed)
+}
+
+func TestShadowRead(t *testing.T) {
+	require := require.New(t)
+
+	var reads []bool
+	getCandidates := func(_ protocol.StateReader, height uint64, beforeEaster bool, _ bool) ([]*state.Candidate, uint64, error) {
+		reads = append(reads, beforeEaster)
+		return nil, height, state.ErrStateNotExist
+	}
+	g := genesis.Default
+	sh, err := NewSlasher(&g, nil, getCandidates, nil, nil, nil, 36, 24, 1, 85, 2, 4, 90)
+	require.NoError(err)
+	// the shadow read failure does not panic nor return
+	sh.shadowRead(nil, 721, true, false, state.CandidateList{})
+	sh.shadowRead(nil, 721, false, true, state.CandidateList{})
+	require.Equal([]bool{false, true}, reads)
+}
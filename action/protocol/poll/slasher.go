@@ -9,22 +9,68 @@ package poll
 import (
 	"context"
 	"math/big"
+	"sort"
 	"strconv"
 
+	"github.com/iotexproject/go-pkgs/hash"
+	"github.com/iotexproject/iotex-address/address"
 	"github.com/iotexproject/iotex-election/util"
 	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/zap"
 
+	"github.com/iotexproject/iotex-core/action"
 	"github.com/iotexproject/iotex-core/action/protocol"
+	"github.com/iotexproject/iotex-core/action/protocol/parameter"
 	"github.com/iotexproject/iotex-core/action/protocol/rolldpos"
 	"github.com/iotexproject/iotex-core/action/protocol/vote"
+	"github.com/iotexproject/iotex-core/action/protocol/vote/candidatesutil"
 	"github.com/iotexproject/iotex-core/blockchain/genesis"
 	"github.com/iotexproject/iotex-core/config"
 	"github.com/iotexproject/iotex-core/crypto"
 	"github.com/iotexproject/iotex-core/pkg/log"
+	"github.com/iotexproject/iotex-core/pkg/util/byteutil"
 	"github.com/iotexproject/iotex-core/state"
 )
 
+// MaxProbationListEpochRange is the max number of epochs of which the probation lists are read at once
+const MaxProbationListEpochRange = 500
+
+// DelegateProbationTopic is the first topic of the system log of a delegate on the probation list of the next epoch,
+// followed by the address of the delegate. The data is the intensity rate followed by the epoch number
+var DelegateProbationTopic = hash.Hash256b([]byte("Poll.DelegateProbation"))
+
+// _bpsDenominator is the denominator of rates in basis points
+var _bpsDenominator = big.NewInt(10000)
+
+var (
+	probationListSizeMtc = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "iotex_poll_probation_list_size",
+			Help: "Number of delegates in the probation list of the next epoch.",
+		},
+	)
+	probationCountMtc = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "iotex_poll_probation_count",
+			Help: "Number of the recent epochs in which the delegate is unproductive, as counted in the probation list of the next epoch.",
+		},
+		[]string{"delegate"},
+	)
+	probationIntensityMtc = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "iotex_poll_probation_intensity",
+			Help: "Intensity rate in percentage applied to the votes of the delegates in the probation list of the next epoch.",
+		},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(probationListSizeMtc)
+	prometheus.MustRegister(probationCountMtc)
+	prometheus.MustRegister(probationIntensityMtc)
+}
+
 // Slasher is the module to slash candidates
 type Slasher struct {
 	hu                    config.HeightUpgrade
@@ -35,11 +81,23 @@ type Slasher struct {
 	indexer               *CandidateIndexer
 	numCandidateDelegates uint64
 	numDelegates          uint64
+	minActiveDelegates    uint64
 	numOfBlocksByEpoch    uint64
 	prodThreshold         uint64
 	probationEpochPeriod  uint64
 	maxProbationPeriod    uint64
 	probationIntensity    uint32
+	intensitySchedule     []uint32
+	intensityScheduleAt   uint64
+	probationGracePeriod  uint64
+	jailPeriod            uint64
+	probationEventHeight  uint64
+	stakeSlashRate        uint64
+	slashToRewardingPool  bool
+	stakeSlasher          StakeSlasher
+	depositGas            DepositGas
+	creditFund            CreditFund
+	enableShadowRead      bool
 }
 
 // NewSlasher returns a new Slasher
@@ -62,11 +120,19 @@ func NewSlasher(
 		indexer:               indexer,
 		numCandidateDelegates: numCandidateDelegates,
 		numDelegates:          numDelegates,
+		minActiveDelegates:    gen.MinActiveDelegates,
 		numOfBlocksByEpoch:    numDelegates * dardanellesNumSubEpochs,
 		prodThreshold:         thres,
 		probationEpochPeriod:  koPeriod,
 		maxProbationPeriod:    maxKoPeriod,
 		probationIntensity:    koIntensity,
+		intensitySchedule:     gen.ProbationIntensitySchedule,
+		intensityScheduleAt:   gen.ProbationIntensityScheduleBlockHeight,
+		probationGracePeriod:  gen.ProbationGracePeriod,
+		jailPeriod:            gen.EquivocationJailPeriod,
+		probationEventHeight:  gen.ProbationEventBlockHeight,
+		stakeSlashRate:        gen.StakeSlashRate,
+		slashToRewardingPool:  gen.StakeSlashToRewardingPool,
 	}, nil
 }
 
@@ -106,7 +172,11 @@ func (sh *Slasher) CreatePreStates(ctx context.Context, sm protocol.StateManager
 		if err != nil {
 			return err
 		}
-		return setNextEpochProbationList(sm, indexer, nextEpochStartHeight, unqualifiedList)
+		reportProbationList(unqualifiedList)
+		if err := setNextEpochProbationList(sm, indexer, nextEpochStartHeight, unqualifiedList); err != nil {
+			return err
+		}
+		return sh.slashProbation(ctx, sm, unqualifiedList)
 	}
 	if blkCtx.BlockHeight == epochStartHeight && hu.IsPost(config.Easter, epochStartHeight) {
 		prevHeight, err := shiftCandidates(sm)
@@ -124,6 +194,61 @@ func (sh *Slasher) CreatePreStates(ctx context.Context, sm protocol.StateManager
 	return nil
 }
 
+// CreateSystemLogs emits an event of each delegate on the probation list of the next epoch written in the last block
+// of the epoch, and of each self-stake slashed in the block
+func (sh *Slasher) CreateSystemLogs(ctx context.Context, sm protocol.StateManager, protocolAddr address.Address) ([]*action.Log, error) {
+	logs, err := sh.probationLogs(ctx, sm, protocolAddr)
+	if err != nil {
+		return nil, err
+	}
+	slashLogs, err := stakeSlashLogs(ctx, sm, protocolAddr)
+	if err != nil {
+		return nil, err
+	}
+	return append(logs, slashLogs...), nil
+}
+
+func (sh *Slasher) probationLogs(ctx context.Context, sm protocol.StateManager, protocolAddr address.Address) ([]*action.Log, error) {
+	blkCtx := protocol.MustGetBlockCtx(ctx)
+	if sh.probationEventHeight == 0 || blkCtx.BlockHeight < sh.probationEventHeight {
+		return nil, nil
+	}
+	rp := rolldpos.MustGetProtocol(protocol.MustGetRegistry(ctx))
+	epochNum := rp.GetEpochNum(blkCtx.BlockHeight)
+	hu := config.NewHeightUpgrade(&protocol.MustGetBlockchainCtx(ctx).Genesis)
+	if blkCtx.BlockHeight != rp.GetEpochLastBlockHeight(epochNum) || hu.IsPre(config.Easter, rp.GetEpochHeight(epochNum+1)) {
+		return nil, nil
+	}
+	probationList, _, err := sh.getProbationList(sm, true)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get the probation list of next epoch")
+	}
+	delegates := make([]string, 0, len(probationList.ProbationInfo))
+	for delegate := range probationList.ProbationInfo {
+		delegates = append(delegates, delegate)
+	}
+	sort.Strings(delegates)
+	schedule := sh.probationIntensitySchedule(rp.GetEpochHeight(epochNum + 1))
+	logs := make([]*action.Log, 0, len(delegates))
+	for _, delegate := range delegates {
+		addr, err := address.FromString(delegate)
+		if err != nil {
+			return nil, err
+		}
+		intensity := ProbationIntensity(probationList.IntensityRate, schedule, probationList.ProbationInfo[delegate])
+		logs = append(logs, &action.Log{
+			Address: protocolAddr.String(),
+			Topics: action.Topics{
+				DelegateProbationTopic,
+				hash.BytesToHash256(addr.Bytes()),
+			},
+			Data:        append(byteutil.Uint32ToBytesBigEndian(intensity), byteutil.Uint64ToBytesBigEndian(epochNum+1)...),
+			BlockHeight: blkCtx.BlockHeight,
+		})
+	}
+	return logs, nil
+}
+
 // ReadState defines slasher's read methods.
 func (sh *Slasher) ReadState(
 	ctx context.Context,
@@ -137,27 +262,43 @@ func (sh *Slasher) ReadState(
 	if err != nil {
 		return nil, uint64(0), err
 	}
+	if string(method) == "ProbationHistory" {
+		// the arg is the delegate address instead of the epoch number
+		return sh.readProbationHistory(rp, indexer, targetHeight, args...)
+	}
 	epochNum := rp.GetEpochNum(targetHeight)
 	epochStartHeight := rp.GetEpochHeight(epochNum)
+	futureEpoch := false
 	if len(args) != 0 {
 		epochNumArg, err := strconv.ParseUint(string(args[0]), 10, 64)
 		if err != nil {
-			return nil, uint64(0), err
+			return nil, uint64(0), errors.Wrap(protocol.ErrInvalidArgument, err.Error())
 		}
 		if indexer == nil {
 			// consistency check between sr.height and epochNumArg in case of using state reader(not indexer)
 			if epochNum != epochNumArg {
-				return nil, uint64(0), errors.New("Slasher ReadState arg epochNumber should be same as state reader height, need to set argument/height consistently")
+				return nil, uint64(0), errors.Wrap(protocol.ErrInvalidArgument, "Slasher ReadState arg epochNumber should be same as state reader height, need to set argument/height consistently")
 			}
 		}
 		epochStartHeight = rp.GetEpochHeight(epochNumArg)
+		futureEpoch = epochNumArg > epochNum
 	}
 	switch string(method) {
 	case "CandidatesByEpoch":
+		offset, limit, paged, err := candidatePageArgs(args)
+		if err != nil {
+			return nil, uint64(0), err
+		}
+		serialize := func(candidates state.CandidateList) ([]byte, error) {
+			if paged {
+				return NewCandidatePage(candidates, offset, limit).Serialize()
+			}
+			return candidates.Serialize()
+		}
 		if indexer != nil {
 			candidates, err := sh.GetCandidatesFromIndexer(ctx, epochStartHeight)
 			if err == nil {
-				data, err := candidates.Serialize()
+				data, err := serialize(candidates)
 				if err != nil {
 					return nil, uint64(0), err
 				}
@@ -167,11 +308,14 @@ func (sh *Slasher) ReadState(
 				return nil, uint64(0), err
 			}
 		}
+		if futureEpoch {
+			return nil, uint64(0), errors.Wrapf(protocol.ErrFutureEpoch, "epoch start height %d", epochStartHeight)
+		}
 		candidates, height, err := sh.GetCandidates(ctx, sr, false)
 		if err != nil {
 			return nil, uint64(0), err
 		}
-		data, err := candidates.Serialize()
+		data, err := serialize(candidates)
 		if err != nil {
 			return nil, uint64(0), err
 		}
@@ -190,6 +334,9 @@ func (sh *Slasher) ReadState(
 				return nil, uint64(0), err
 			}
 		}
+		if futureEpoch {
+			return nil, uint64(0), errors.Wrapf(protocol.ErrFutureEpoch, "epoch start height %d", epochStartHeight)
+		}
 		bp, height, err := sh.GetBlockProducers(ctx, sr, false)
 		if err != nil {
 			return nil, uint64(0), err
@@ -213,6 +360,9 @@ func (sh *Slasher) ReadState(
 				return nil, uint64(0), err
 			}
 		}
+		if futureEpoch {
+			return nil, uint64(0), errors.Wrapf(protocol.ErrFutureEpoch, "epoch start height %d", epochStartHeight)
+		}
 		abp, height, err := sh.GetActiveBlockProducers(ctx, sr, false)
 		if err != nil {
 			return nil, uint64(0), err
@@ -236,6 +386,9 @@ func (sh *Slasher) ReadState(
 				return nil, uint64(0), err
 			}
 		}
+		if futureEpoch {
+			return nil, uint64(0), errors.Wrapf(protocol.ErrFutureEpoch, "epoch start height %d", epochStartHeight)
+		}
 		probationList, height, err := sh.GetProbationList(ctx, sr, false)
 		if err != nil {
 			return nil, uint64(0), err
@@ -245,11 +398,135 @@ func (sh *Slasher) ReadState(
 			return nil, uint64(0), err
 		}
 		return data, height, nil
+	case "ProbationListByEpochRange":
+		if len(args) != 2 {
+			return nil, uint64(0), errors.Wrap(protocol.ErrInvalidArgument, "start and end epoch numbers are required")
+		}
+		startEpoch := rp.GetEpochNum(epochStartHeight)
+		endEpoch, err := strconv.ParseUint(string(args[1]), 10, 64)
+		if err != nil {
+			return nil, uint64(0), errors.Wrap(protocol.ErrInvalidArgument, err.Error())
+		}
+		if endEpoch < startEpoch {
+			return nil, uint64(0), errors.Wrapf(protocol.ErrInvalidArgument, "end epoch %d is before start epoch %d", endEpoch, startEpoch)
+		}
+		if endEpoch-startEpoch >= MaxProbationListEpochRange {
+			return nil, uint64(0), errors.Wrapf(protocol.ErrInvalidArgument, "range exceeds the limit of %d epochs", MaxProbationListEpochRange)
+		}
+		if indexer == nil && endEpoch != epochNum {
+			return nil, uint64(0), errors.Wrap(protocol.ErrInvalidArgument, "only the probation list of current epoch is available without indexer")
+		}
+		probationLists := make(vote.ProbationListsByEpoch, endEpoch-startEpoch+1)
+		for e := startEpoch; e <= endEpoch; e++ {
+			probationList, err := sh.probationListOfEpoch(ctx, sr, indexer, e, epochNum)
+			if err != nil {
+				return nil, uint64(0), err
+			}
+			probationLists[e] = probationList
+		}
+		data, err := probationLists.Serialize()
+		if err != nil {
+			return nil, uint64(0), err
+		}
+		return data, rp.GetEpochHeight(endEpoch), nil
+	case "ProbationExemptions":
+		exemptions, err := parameter.GetExemptions(sr)
+		if err != nil {
+			return nil, uint64(0), err
+		}
+		data, err := exemptions.Serialize()
+		if err != nil {
+			return nil, uint64(0), err
+		}
+		return data, targetHeight, nil
+	case "NextProbationList":
+		if rp.GetEpochNum(epochStartHeight) != epochNum {
+			return nil, uint64(0), errors.Wrapf(protocol.ErrInvalidArgument, "only the next probation list of current epoch %d can be simulated", epochNum)
+		}
+		probationList, err := sh.simulateNextProbationList(ctx, sr, targetHeight)
+		if err != nil {
+			return nil, uint64(0), err
+		}
+		data, err := probationList.Serialize()
+		if err != nil {
+			return nil, uint64(0), err
+		}
+		return data, targetHeight, nil
 	default:
-		return nil, uint64(0), errors.New("corresponding method isn't found")
+		return nil, uint64(0), errors.Wrapf(protocol.ErrNotFound, "corresponding method %s isn't found", method)
 	}
 }
 
+// readProbationHistory returns the epochs the delegate has been on probation up to the target height, from the
+// indexer
+func (sh *Slasher) readProbationHistory(
+	rp *rolldpos.Protocol,
+	indexer *CandidateIndexer,
+	targetHeight uint64,
+	args ...[]byte,
+) ([]byte, uint64, error) {
+	if len(args) != 1 {
+		return nil, uint64(0), errors.Wrap(protocol.ErrInvalidArgument, "delegate address is required")
+	}
+	addr, err := address.FromString(string(args[0]))
+	if err != nil {
+		return nil, uint64(0), errors.Wrap(protocol.ErrInvalidArgument, err.Error())
+	}
+	if indexer == nil {
+		return nil, uint64(0), errors.Wrap(ErrIndexerNotExist, "probation history is only available with candidate indexer")
+	}
+	counts, err := indexer.ProbationHistory(addr.String())
+	if err != nil {
+		return nil, uint64(0), err
+	}
+	history := make(vote.ProbationHistory, 0, len(counts))
+	for height, count := range counts {
+		if height > targetHeight {
+			continue
+		}
+		history = append(history, vote.ProbationRecord{
+			EpochNum: rp.GetEpochNum(height),
+			Count:    count,
+		})
+	}
+	sort.Slice(history, func(i, j int) bool { return history[i].EpochNum < history[j].EpochNum })
+	data, err := history.Serialize()
+	if err != nil {
+		return nil, uint64(0), err
+	}
+	return data, targetHeight, nil
+}
+
+// probationListOfEpoch returns the probation list of the epoch from the indexer, falling back to the state for the
+// current epoch which is yet to be indexed
+func (sh *Slasher) probationListOfEpoch(
+	ctx context.Context,
+	sr protocol.StateReader,
+	indexer *CandidateIndexer,
+	epochNum uint64,
+	currentEpochNum uint64,
+) (*vote.ProbationList, error) {
+	rp := rolldpos.MustGetProtocol(protocol.MustGetRegistry(ctx))
+	epochStartHeight := rp.GetEpochHeight(epochNum)
+	if indexer != nil {
+		probationList, err := indexer.ProbationList(epochStartHeight)
+		if err == nil {
+			return probationList, nil
+		}
+		if errors.Cause(err) != ErrIndexerNotExist {
+			return nil, err
+		}
+	}
+	if epochNum > currentEpochNum {
+		return nil, errors.Wrapf(protocol.ErrFutureEpoch, "epoch start height %d", epochStartHeight)
+	}
+	if epochNum < currentEpochNum {
+		return nil, errors.Wrapf(ErrIndexerNotExist, "probation list of epoch %d", epochNum)
+	}
+	probationList, _, err := sh.GetProbationList(ctx, sr, false)
+	return probationList, err
+}
+
 // GetCandidates returns filtered candidate list
 func (sh *Slasher) GetCandidates(ctx context.Context, sr protocol.StateReader, readFromNext bool) (state.CandidateList, uint64, error) {
 	rp := rolldpos.MustGetProtocol(protocol.MustGetRegistry(ctx))
@@ -268,6 +545,9 @@ func (sh *Slasher) GetCandidates(ctx context.Context, sr protocol.StateReader, r
 	if err != nil {
 		return nil, uint64(0), errors.Wrapf(err, "failed to get candidates at height %d", targetEpochStartHeight)
 	}
+	if sh.enableShadowRead {
+		sh.shadowRead(sr, targetEpochStartHeight, beforeEaster, readFromNext, candidates)
+	}
 	// to catch the corner case that since the new block is committed, shift occurs in the middle of processing the request
 	if rp.GetEpochNum(targetEpochStartHeight) < rp.GetEpochNum(stateHeight) {
 		return nil, uint64(0), errors.Wrap(ErrInconsistentHeight, "state factory epoch number became larger than target epoch number")
@@ -281,20 +561,39 @@ func (sh *Slasher) GetCandidates(ctx context.Context, sr protocol.StateReader, r
 		return nil, uint64(0), errors.Wrapf(err, "failed to get probation list at height %d", targetEpochStartHeight)
 	}
 	// recalculate the voting power for probationlist delegates
-	filteredCandidate, err := filterCandidates(candidates, unqualifiedList, targetEpochStartHeight)
+	schedule := sh.probationIntensitySchedule(targetEpochStartHeight)
+	filteredCandidate, err := filterCandidates(candidates, unqualifiedList, schedule, targetEpochStartHeight, sh.hu.IsPost(config.Iceland, targetEpochStartHeight))
 	if err != nil {
 		return nil, uint64(0), err
 	}
+	if sh.jailEnabled(targetEpochStartHeight) {
+		jl, err := candidatesutil.JailListFromDB(sr)
+		if err != nil {
+			return nil, uint64(0), err
+		}
+		filteredCandidate = jailCandidates(filteredCandidate, jl, rp.GetEpochNum(targetEpochStartHeight))
+	}
 	return filteredCandidate, stateHeight, nil
 }
 
 // GetBlockProducers returns BP list
 func (sh *Slasher) GetBlockProducers(ctx context.Context, sr protocol.StateReader, readFromNext bool) (state.CandidateList, uint64, error) {
+	rp := rolldpos.MustGetProtocol(protocol.MustGetRegistry(ctx))
+	targetHeight, err := sr.Height()
+	if err != nil {
+		return nil, uint64(0), err
+	}
+	// make sure it's epochStartHeight
+	targetEpochStartHeight := rp.GetEpochHeight(rp.GetEpochNum(targetHeight))
+	if readFromNext {
+		targetEpochNum := rp.GetEpochNum(targetEpochStartHeight) + 1
+		targetEpochStartHeight = rp.GetEpochHeight(targetEpochNum) // next epoch start height
+	}
 	candidates, height, err := sh.GetCandidates(ctx, sr, readFromNext)
 	if err != nil {
 		return nil, uint64(0), err
 	}
-	bp, err := sh.calculateBlockProducer(candidates)
+	bp, err := sh.calculateBlockProducer(candidates, targetEpochStartHeight)
 	if err != nil {
 		return nil, uint64(0), err
 	}
@@ -340,7 +639,8 @@ func (sh *Slasher) GetCandidatesFromIndexer(ctx context.Context, epochStartHeigh
 		return nil, err
 	}
 	// recalculate the voting power for probationlist delegates
-	return filterCandidates(candidates, probationList, epochStartHeight)
+	schedule := sh.probationIntensitySchedule(epochStartHeight)
+	return filterCandidates(candidates, probationList, schedule, epochStartHeight, sh.hu.IsPost(config.Iceland, epochStartHeight))
 }
 
 // GetBPFromIndexer returns BP list from indexer
@@ -349,7 +649,7 @@ func (sh *Slasher) GetBPFromIndexer(ctx context.Context, epochStartHeight uint64
 	if err != nil {
 		return nil, err
 	}
-	return sh.calculateBlockProducer(candidates)
+	return sh.calculateBlockProducer(candidates, epochStartHeight)
 }
 
 // GetABPFromIndexer returns active BP list from indexer
@@ -375,7 +675,7 @@ func (sh *Slasher) GetProbationList(ctx context.Context, sr protocol.StateReader
 		targetEpochStartHeight = rp.GetEpochHeight(targetEpochNum) // next epoch start height
 	}
 	if sh.hu.IsPre(config.Easter, targetEpochStartHeight) {
-		return nil, uint64(0), errors.New("Before Easter, there is no probation list in stateDB")
+		return nil, uint64(0), errors.Wrap(protocol.ErrPreActivation, "Before Easter, there is no probation list in stateDB")
 	}
 	unqualifiedList, stateHeight, err := sh.getProbationList(sr, readFromNext)
 	if err != nil {
@@ -394,22 +694,128 @@ func (sh *Slasher) CalculateProbationList(
 	sm protocol.StateManager,
 	epochNum uint64,
 ) (*vote.ProbationList, error) {
+	uq, err := sh.calculateUnproductiveDelegates(ctx, sm)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to calculate current epoch upd %d", epochNum-1)
+	}
+	_, intensity, err := sh.slashingParams(sm)
+	if err != nil {
+		return nil, err
+	}
+	nextProbationlist, upd, err := sh.nextProbationList(ctx, sm, epochNum, uq, intensity)
+	if err != nil {
+		return nil, err
+	}
+	return nextProbationlist, setUnproductiveDelegates(sm, upd)
+}
+
+// SimulateProbationList calculates the probation list of the next epoch in the same way as CalculateProbationList,
+// from the given productivity of the current epoch instead of the blocks produced, without writing into state DB
+func (sh *Slasher) SimulateProbationList(
+	ctx context.Context,
+	sr protocol.StateReader,
+	produce map[string]uint64,
+) (*vote.ProbationList, error) {
+	rp := rolldpos.MustGetProtocol(protocol.MustGetRegistry(ctx))
+	tipHeight, err := sr.Height()
+	if err != nil {
+		return nil, err
+	}
+	epochNum := rp.GetEpochNum(tipHeight)
+	if sh.hu.IsPre(config.Easter, rp.GetEpochHeight(epochNum+1)) {
+		return nil, errors.Wrap(protocol.ErrPreActivation, "Before Easter, there is no probation list")
+	}
+	delegates, _, err := sh.GetActiveBlockProducers(ctx, sr, false)
+	if err != nil {
+		return nil, err
+	}
+	simulated := make(map[string]uint64, len(produce))
+	for addr, n := range produce {
+		simulated[addr] = n
+	}
+	for _, abp := range delegates {
+		if _, ok := simulated[abp.Address]; !ok {
+			simulated[abp.Address] = 0
+		}
+	}
+	if len(simulated) == 0 {
+		return nil, errors.Wrap(protocol.ErrInvalidArgument, "no delegate to evaluate the productivity of")
+	}
+	var numBlks uint64
+	for _, n := range simulated {
+		numBlks += n
+	}
+	if numBlks < uint64(len(simulated)) {
+		return nil, errors.Wrapf(
+			protocol.ErrInvalidArgument,
+			"%d blocks are too few to evaluate the productivity of %d delegates",
+			numBlks,
+			len(simulated),
+		)
+	}
+	thres, intensity, err := sh.slashingParams(sr)
+	if err != nil {
+		return nil, err
+	}
+	uq := unproductiveDelegates(numBlks, simulated, thres)
+	nextProbationlist, _, err := sh.nextProbationList(ctx, sr, epochNum+1, uq, intensity)
+	return nextProbationlist, err
+}
+
+// simulateNextProbationList calculates the probation list of the next epoch from the blocks produced in the current
+// epoch till the tip, which is the probation list the delegates would get if the epoch ended at the tip
+func (sh *Slasher) simulateNextProbationList(
+	ctx context.Context,
+	sr protocol.StateReader,
+	tipHeight uint64,
+) (*vote.ProbationList, error) {
+	rp := rolldpos.MustGetProtocol(protocol.MustGetRegistry(ctx))
+	productivityFunc := sh.productivity
+	if sh.hu.IsPost(config.Greenland, tipHeight) {
+		productivityFunc = func(start, end uint64) (map[string]uint64, error) {
+			return currentEpochProductivity(sr, start, end, sh.numOfBlocksByEpoch)
+		}
+	}
+	_, produce, err := rp.ProductivityByEpoch(rp.GetEpochNum(tipHeight), tipHeight, productivityFunc)
+	if err != nil {
+		return nil, err
+	}
+	return sh.SimulateProbationList(ctx, sr, produce)
+}
+
+// nextProbationList calculates the probation list of the epoch from the unproductive delegates of the previous epoch,
+// and returns it with the updated unproductive delegates to be written into state DB
+func (sh *Slasher) nextProbationList(
+	ctx context.Context,
+	sr protocol.StateReader,
+	epochNum uint64,
+	uq []string,
+	intensity uint32,
+) (*vote.ProbationList, *vote.UnproductiveDelegate, error) {
 	rp := rolldpos.MustGetProtocol(protocol.MustGetRegistry(ctx))
 	easterEpochNum := rp.GetEpochNum(sh.hu.EasterBlockHeight())
 
 	nextProbationlist := &vote.ProbationList{
-		IntensityRate: sh.probationIntensity,
+		IntensityRate: intensity,
 	}
-	upd, err := sh.getUnprodDelegate(sm)
+	upd, err := sh.getUnprodDelegate(sr)
 	if err != nil {
 		if errors.Cause(err) == state.ErrStateNotExist {
 			if upd, err = vote.NewUnproductiveDelegate(sh.probationEpochPeriod, sh.maxProbationPeriod); err != nil {
-				return nil, errors.Wrap(err, "failed to make new upd")
+				return nil, nil, errors.Wrap(err, "failed to make new upd")
 			}
 		} else {
-			return nil, errors.Wrapf(err, "failed to read upd struct from state DB at epoch number %d", epochNum)
+			return nil, nil, errors.Wrapf(err, "failed to read upd struct from state DB at epoch number %d", epochNum)
 		}
 	}
+	if sh.gracePeriodEnabled(rp.GetEpochHeight(epochNum)) {
+		if uq, err = sh.excludeGracePeriod(ctx, sr, upd, epochNum-1, uq); err != nil {
+			return nil, nil, err
+		}
+	}
+	if uq, err = excludeExemptions(sr, uq); err != nil {
+		return nil, nil, err
+	}
 	unqualifiedDelegates := make(map[string]uint32)
 	if epochNum <= easterEpochNum+sh.probationEpochPeriod {
 		// if epoch number is smaller than easterEpochNum+K(probation period), calculate it one-by-one (initialize).
@@ -428,11 +834,7 @@ func (sh *Slasher) CalculateProbationList(
 				}
 			}
 		}
-		// calculate upd of epochNum-1 (latest)
-		uq, err := sh.calculateUnproductiveDelegates(ctx, sm)
-		if err != nil {
-			return nil, errors.Wrapf(err, "failed to calculate current epoch upd %d", epochNum-1)
-		}
+		// add upd of epochNum-1 (latest)
 		for _, addr := range uq {
 			if _, ok := unqualifiedDelegates[addr]; !ok {
 				unqualifiedDelegates[addr] = 1
@@ -441,10 +843,10 @@ func (sh *Slasher) CalculateProbationList(
 			}
 		}
 		if err := upd.AddRecentUPD(uq); err != nil {
-			return nil, errors.Wrap(err, "failed to add recent upd")
+			return nil, nil, errors.Wrap(err, "failed to add recent upd")
 		}
 		nextProbationlist.ProbationInfo = unqualifiedDelegates
-		return nextProbationlist, setUnproductiveDelegates(sm, upd)
+		return nextProbationlist, upd, nil
 	}
 	// ProbationList[N] = ProbationList[N-1] - Low-productivity-list[N-K-1] + Low-productivity-list[N-1]
 	log.L().Debug("Using probationList",
@@ -452,9 +854,9 @@ func (sh *Slasher) CalculateProbationList(
 		zap.Uint64("easterEpochNum", easterEpochNum),
 		zap.Uint64("probationEpochPeriod", sh.probationEpochPeriod),
 	)
-	prevProbationlist, _, err := sh.getProbationList(sm, false)
+	prevProbationlist, _, err := sh.getProbationList(sr, false)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to read latest probation list")
+		return nil, nil, errors.Wrap(err, "failed to read latest probation list")
 	}
 	probationMap := prevProbationlist.ProbationInfo
 	if probationMap == nil {
@@ -468,14 +870,10 @@ func (sh *Slasher) CalculateProbationList(
 		}
 		probationMap[addr]--
 	}
-	addList, err := sh.calculateUnproductiveDelegates(ctx, sm)
-	if err != nil {
-		return nil, errors.Wrapf(err, "failed to calculate current epoch upd %d", epochNum-1)
+	if err := upd.AddRecentUPD(uq); err != nil {
+		return nil, nil, errors.Wrap(err, "failed to add recent upd")
 	}
-	if err := upd.AddRecentUPD(addList); err != nil {
-		return nil, errors.Wrap(err, "failed to add recent upd")
-	}
-	for _, addr := range addList {
+	for _, addr := range uq {
 		if _, ok := probationMap[addr]; ok {
 			probationMap[addr]++
 			continue
@@ -489,7 +887,95 @@ func (sh *Slasher) CalculateProbationList(
 		}
 	}
 	nextProbationlist.ProbationInfo = probationMap
-	return nextProbationlist, setUnproductiveDelegates(sm, upd)
+	return nextProbationlist, upd, nil
+}
+
+// gracePeriodEnabled returns whether the grace period applies to the probation list of the epoch starting at the
+// height, which records the first epochs of the delegates into upd as well
+func (sh *Slasher) gracePeriodEnabled(epochStartHeight uint64) bool {
+	return sh.probationGracePeriod > 0 && sh.hu.IsPost(config.Kamchatka, epochStartHeight)
+}
+
+// excludeGracePeriod excludes the delegates still in their grace period from the unproductive delegates of the epoch
+func (sh *Slasher) excludeGracePeriod(
+	ctx context.Context,
+	sr protocol.StateReader,
+	upd *vote.UnproductiveDelegate,
+	epochNum uint64,
+	uq []string,
+) ([]string, error) {
+	delegates, _, err := sh.GetActiveBlockProducers(ctx, sr, false)
+	if err != nil {
+		return nil, err
+	}
+	abps := make([]string, 0, len(delegates))
+	for _, abp := range delegates {
+		abps = append(abps, abp.Address)
+	}
+	return excludeNewDelegates(upd, abps, epochNum, sh.probationGracePeriod, uq), nil
+}
+
+// excludeNewDelegates records the first appearance of the active block producers of the epoch in upd, and returns the
+// unproductive delegates which first appear at least gracePeriod epochs ago
+func excludeNewDelegates(
+	upd *vote.UnproductiveDelegate,
+	abps []string,
+	epochNum uint64,
+	gracePeriod uint64,
+	uq []string,
+) []string {
+	if upd.NumFirstEpochs() == 0 {
+		// the delegates already producing blocks when the tracking starts are not newly registered
+		upd.AddFirstEpochs(abps, 0)
+	} else {
+		upd.AddFirstEpochs(abps, epochNum)
+	}
+	unqualified := make([]string, 0, len(uq))
+	for _, addr := range uq {
+		if first, ok := upd.FirstEpoch(addr); ok && epochNum < first+gracePeriod {
+			log.L().Debug("Delegate in grace period is not unproductive",
+				zap.String("delegate", addr),
+				zap.Uint64("firstEpoch", first),
+				zap.Uint64("epochNum", epochNum),
+			)
+			continue
+		}
+		unqualified = append(unqualified, addr)
+	}
+	return unqualified
+}
+
+// reportProbationList exposes the probation list of the next epoch in the metrics, so that the operators can tell
+// how close their delegates are to being kicked out
+func reportProbationList(pl *vote.ProbationList) {
+	probationListSizeMtc.Set(float64(len(pl.ProbationInfo)))
+	probationIntensityMtc.Set(float64(pl.IntensityRate))
+	// the delegates released from probation are dropped
+	probationCountMtc.Reset()
+	for addr, count := range pl.ProbationInfo {
+		probationCountMtc.WithLabelValues(addr).Set(float64(count))
+	}
+}
+
+// slashingParams returns the productivity threshold and the probation intensity in effect, which are the ones changed
+// by the parameter governors in state if any, otherwise the ones the slasher is constructed with
+func (sh *Slasher) slashingParams(sr protocol.StateReader) (uint64, uint32, error) {
+	thres, intensity := sh.prodThreshold, sh.probationIntensity
+	value, ok, err := parameter.Get(sr, parameter.ProductivityThreshold)
+	if err != nil {
+		return 0, 0, err
+	}
+	if ok {
+		thres = value
+	}
+	value, ok, err = parameter.Get(sr, parameter.ProbationIntensity)
+	if err != nil {
+		return 0, 0, err
+	}
+	if ok {
+		intensity = uint32(value)
+	}
+	return thres, intensity, nil
 }
 
 func (sh *Slasher) calculateUnproductiveDelegates(ctx context.Context, sr protocol.StateReader) ([]string, error) {
@@ -529,14 +1015,44 @@ func (sh *Slasher) calculateUnproductiveDelegates(ctx context.Context, sr protoc
 			produce[abp.Address] = 0
 		}
 	}
+	thres, _, err := sh.slashingParams(sr)
+	if err != nil {
+		return nil, err
+	}
+	return unproductiveDelegates(numBlks, produce, thres), nil
+}
+
+// excludeExemptions excludes the delegates exempted from probation by the parameter governors from the unproductive
+// delegates. An exempted delegate already in probation is released once its unproductive epochs slide out
+func excludeExemptions(sr protocol.StateReader, uq []string) ([]string, error) {
+	exemptions, err := parameter.GetExemptions(sr)
+	if err != nil {
+		return nil, err
+	}
+	if len(exemptions.Addresses) == 0 {
+		return uq, nil
+	}
+	filtered := make([]string, 0, len(uq))
+	for _, addr := range uq {
+		if exemptions.Exempted(addr) {
+			log.L().Debug("Exempt delegate from probation", zap.String("address", addr))
+			continue
+		}
+		filtered = append(filtered, addr)
+	}
+	return filtered, nil
+}
+
+// unproductiveDelegates returns the delegates whose productivity is lower than the threshold
+func unproductiveDelegates(numBlks uint64, produce map[string]uint64, thres uint64) []string {
 	unqualified := make([]string, 0)
 	expectedNumBlks := numBlks / uint64(len(produce))
 	for addr, actualNumBlks := range produce {
-		if actualNumBlks*100/expectedNumBlks < sh.prodThreshold {
+		if actualNumBlks*100/expectedNumBlks < thres {
 			unqualified = append(unqualified, addr)
 		}
 	}
-	return unqualified, nil
+	return unqualified
 }
 
 func (sh *Slasher) updateCurrentBlockMeta(ctx context.Context, sm protocol.StateManager) error {
@@ -546,7 +1062,20 @@ func (sh *Slasher) updateCurrentBlockMeta(ctx context.Context, sm protocol.State
 }
 
 // calculateBlockProducer calculates block producer by given candidate list
-func (sh *Slasher) calculateBlockProducer(candidates state.CandidateList) (state.CandidateList, error) {
+func (sh *Slasher) calculateBlockProducer(candidates state.CandidateList, epochStartHeight uint64) (state.CandidateList, error) {
+	if sh.hu.IsPost(config.Jutland, epochStartHeight) {
+		// the candidates with voting power are qualified, up to the configured number of candidate delegates
+		var qualified state.CandidateList
+		for _, candidate := range candidates {
+			if uint64(len(qualified)) >= sh.numCandidateDelegates {
+				break
+			}
+			if candidate.Votes.Sign() > 0 {
+				qualified = append(qualified, candidate)
+			}
+		}
+		return qualified, nil
+	}
 	var blockProducers state.CandidateList
 	for i, candidate := range candidates {
 		if uint64(i) >= sh.numCandidateDelegates {
@@ -576,6 +1105,10 @@ func (sh *Slasher) calculateActiveBlockProducer(
 	crypto.SortCandidates(blockProducerList, epochStartHeight, crypto.CryptoSeed)
 
 	length := int(sh.numDelegates)
+	if sh.hu.IsPost(config.Jutland, epochStartHeight) {
+		_, numDelegates := sh.resize(uint64(len(blockProducerList)))
+		length = int(numDelegates)
+	}
 	if len(blockProducerList) < length {
 		// TODO: if the number of delegates is smaller than expected, should it return error or not?
 		length = len(blockProducerList)
@@ -592,21 +1125,54 @@ func (sh *Slasher) calculateActiveBlockProducer(
 	return activeBlockProducers, nil
 }
 
+// resize returns the numbers of block producers and active block producers given the number of qualified
+// candidates. They are capped by the configured numbers, and the number of active block producers scales down with
+// the block producers keeping their ratio, so that a small network still rotates the active block producers when
+// candidates drop out, but it is no less than minActiveDelegates unless there are not enough block producers
+func (sh *Slasher) resize(numQualified uint64) (uint64, uint64) {
+	numCandidateDelegates := sh.numCandidateDelegates
+	if numQualified < numCandidateDelegates {
+		numCandidateDelegates = numQualified
+	}
+	numDelegates := sh.numDelegates
+	if sh.numCandidateDelegates > 0 {
+		// round up
+		numDelegates = (numCandidateDelegates*sh.numDelegates + sh.numCandidateDelegates - 1) / sh.numCandidateDelegates
+	}
+	if numDelegates < sh.minActiveDelegates {
+		numDelegates = sh.minActiveDelegates
+	}
+	if numDelegates > sh.numDelegates {
+		numDelegates = sh.numDelegates
+	}
+	if numDelegates > numCandidateDelegates {
+		numDelegates = numCandidateDelegates
+	}
+	return numCandidateDelegates, numDelegates
+}
+
 // filterCandidates returns filtered candidate list by given raw candidate/ probation list
 func filterCandidates(
 	candidates state.CandidateList,
 	unqualifiedList *vote.ProbationList,
+	schedule []uint32,
 	epochStartHeight uint64,
+	integerMath bool,
 ) (state.CandidateList, error) {
 	candidatesMap := make(map[string]*state.Candidate)
 	updatedVotingPower := make(map[string]*big.Int)
-	intensityRate := float64(uint32(100)-unqualifiedList.IntensityRate) / float64(100)
 	for _, cand := range candidates {
 		filterCand := cand.Clone()
-		if _, ok := unqualifiedList.ProbationInfo[cand.Address]; ok {
+		if count, ok := unqualifiedList.ProbationInfo[cand.Address]; ok {
 			// if it is an unqualified delegate, multiply the voting power with probation intensity rate
-			votingPower := new(big.Float).SetInt(filterCand.Votes)
-			filterCand.Votes, _ = votingPower.Mul(votingPower, big.NewFloat(intensityRate)).Int(nil)
+			intensity := ProbationIntensity(unqualifiedList.IntensityRate, schedule, count)
+			if integerMath {
+				filterCand.Votes = applyBps(filterCand.Votes, big.NewInt(int64(uint32(100)-intensity)*100))
+			} else {
+				intensityRate := float64(uint32(100)-intensity) / float64(100)
+				votingPower := new(big.Float).SetInt(filterCand.Votes)
+				filterCand.Votes, _ = votingPower.Mul(votingPower, big.NewFloat(intensityRate)).Int(nil)
+			}
 		}
 		updatedVotingPower[filterCand.Address] = filterCand.Votes
 		candidatesMap[filterCand.Address] = filterCand
@@ -620,6 +1186,63 @@ func filterCandidates(
 	return verifiedCandidates, nil
 }
 
+// ProbationIntensity returns the intensity applied to a delegate on the probation list for count epochs. It follows
+// the schedule if any, whose last intensity applies to the longer probation, otherwise the intensity of the list is
+// applied to all the delegates
+func ProbationIntensity(intensity uint32, schedule []uint32, count uint32) uint32 {
+	if len(schedule) == 0 || count == 0 {
+		return intensity
+	}
+	if int(count) > len(schedule) {
+		return schedule[len(schedule)-1]
+	}
+	return schedule[count-1]
+}
+
+// ProbationIntensitySchedule returns the probation intensity schedule of the genesis applied to the epoch starting at
+// the height, which is nil before the schedule is activated
+func ProbationIntensitySchedule(g *genesis.Genesis, epochStartHeight uint64) []uint32 {
+	return intensityScheduleAt(g.ProbationIntensitySchedule, g.ProbationIntensityScheduleBlockHeight, epochStartHeight)
+}
+
+func (sh *Slasher) probationIntensitySchedule(epochStartHeight uint64) []uint32 {
+	return intensityScheduleAt(sh.intensitySchedule, sh.intensityScheduleAt, epochStartHeight)
+}
+
+func intensityScheduleAt(schedule []uint32, activeHeight, epochStartHeight uint64) []uint32 {
+	if activeHeight == 0 || epochStartHeight < activeHeight {
+		return nil
+	}
+	return schedule
+}
+
+// jailEnabled returns whether the evidence of double signing is accepted and the jailed delegates are filtered at the
+// height
+func (sh *Slasher) jailEnabled(height uint64) bool {
+	return sh.jailPeriod > 0 && sh.hu.IsPost(config.Kamchatka, height)
+}
+
+// jailCandidates zeroes the voting power of the candidates jailed in the epoch, and moves them to the end of the list
+func jailCandidates(candidates state.CandidateList, jl *vote.JailList, epochNum uint64) state.CandidateList {
+	var free, jailed state.CandidateList
+	for _, cand := range candidates {
+		if !jl.Jailed(cand.Address, epochNum) {
+			free = append(free, cand)
+			continue
+		}
+		jailedCand := cand.Clone()
+		jailedCand.Votes = big.NewInt(0)
+		jailed = append(jailed, jailedCand)
+	}
+	return append(free, jailed...)
+}
+
+// applyBps returns value * bps / 10000, rounded toward zero
+func applyBps(value *big.Int, bps *big.Int) *big.Int {
+	v := new(big.Int).Mul(value, bps)
+	return v.Quo(v, _bpsDenominator)
+}
+
 // currentEpochProductivity returns the map of the number of blocks produced per delegate of current epoch
 func currentEpochProductivity(sr protocol.StateReader, start uint64, end uint64, numOfBlocksByEpoch uint64) (map[string]uint64, error) {
 	log.L().Debug("Read current epoch productivity",
@@ -0,0 +1,387 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package poll
+
+import (
+	"context"
+	"math/big"
+	"strconv"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/iotexproject/iotex-address/address"
+	"github.com/pkg/errors"
+	promtestutil "github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/action/protocol"
+	"github.com/iotexproject/iotex-core/action/protocol/parameter"
+	"github.com/iotexproject/iotex-core/action/protocol/rolldpos"
+	"github.com/iotexproject/iotex-core/action/protocol/vote"
+	"github.com/iotexproject/iotex-core/blockchain/genesis"
+	"github.com/iotexproject/iotex-core/db"
+	"github.com/iotexproject/iotex-core/state"
+	"github.com/iotexproject/iotex-core/test/identityset"
+	"github.com/iotexproject/iotex-core/testutil/testdb"
+)
+
+func TestFilterCandidates(t *testing.T) {
+	require := require.New(t)
+
+	votes, ok := new(big.Int).SetString("1234567890123456789012345", 10)
+	require.True(ok)
+	candidates := state.CandidateList{
+		{Address: identityset.Address(1).String(), Votes: votes},
+		{Address: identityset.Address(2).String(), Votes: big.NewInt(1000)},
+	}
+	probationList := vote.NewProbationList(90)
+	probationList.ProbationInfo[identityset.Address(1).String()] = 1
+
+	for _, integerMath := range []bool{false, true} {
+		filtered, err := filterCandidates(candidates, probationList, nil, 1, integerMath)
+		require.NoError(err)
+		require.Equal(2, len(filtered))
+		require.Equal(identityset.Address(1).String(), filtered[0].Address)
+		if integerMath {
+			// exactly 10% of the votes
+			require.Equal("123456789012345678901234", filtered[0].Votes.String())
+		}
+		require.Equal(big.NewInt(1000), filtered[1].Votes)
+	}
+	// candidate list is not modified
+	require.Equal(votes, candidates[0].Votes)
+
+	// the intensity escalates with the epochs on probation following the schedule
+	candidates = append(candidates, &state.Candidate{Address: identityset.Address(3).String(), Votes: big.NewInt(1000)})
+	probationList.ProbationInfo[identityset.Address(1).String()] = 3
+	probationList.ProbationInfo[identityset.Address(2).String()] = 1
+	filtered, err := filterCandidates(candidates, probationList, []uint32{50, 80}, 1, true)
+	require.NoError(err)
+	require.Equal(3, len(filtered))
+	require.Equal("246913578024691357802469", filtered[0].Votes.String())
+	require.Equal(identityset.Address(3).String(), filtered[1].Address)
+	require.Equal(big.NewInt(1000), filtered[1].Votes)
+	require.Equal(identityset.Address(2).String(), filtered[2].Address)
+	require.Equal(big.NewInt(500), filtered[2].Votes)
+
+	require.Equal(uint32(90), ProbationIntensity(90, nil, 2))
+	require.Equal(uint32(50), ProbationIntensity(90, []uint32{50, 80}, 1))
+	require.Equal(uint32(80), ProbationIntensity(90, []uint32{50, 80}, 2))
+	require.Equal(uint32(80), ProbationIntensity(90, []uint32{50, 80}, 5))
+
+	// the schedule applies to the epochs starting since its activation height
+	g := genesis.Default
+	g.ProbationIntensitySchedule = []uint32{50, 80}
+	require.Nil(ProbationIntensitySchedule(&g, 100))
+	g.ProbationIntensityScheduleBlockHeight = 101
+	require.Nil(ProbationIntensitySchedule(&g, 100))
+	require.Equal([]uint32{50, 80}, ProbationIntensitySchedule(&g, 101))
+	require.Equal([]uint32{50, 80}, ProbationIntensitySchedule(&g, 200))
+}
+
+func TestReportProbationList(t *testing.T) {
+	require := require.New(t)
+
+	pl := vote.NewProbationList(90)
+	pl.ProbationInfo[identityset.Address(1).String()] = 1
+	pl.ProbationInfo[identityset.Address(2).String()] = 3
+	reportProbationList(pl)
+	require.Equal(float64(2), promtestutil.ToFloat64(probationListSizeMtc))
+	require.Equal(float64(90), promtestutil.ToFloat64(probationIntensityMtc))
+	require.Equal(float64(3), promtestutil.ToFloat64(probationCountMtc.WithLabelValues(identityset.Address(2).String())))
+
+	// delegate 2 is released from probation
+	pl = vote.NewProbationList(90)
+	pl.ProbationInfo[identityset.Address(1).String()] = 2
+	reportProbationList(pl)
+	require.Equal(float64(1), promtestutil.ToFloat64(probationListSizeMtc))
+	require.Equal(float64(2), promtestutil.ToFloat64(probationCountMtc.WithLabelValues(identityset.Address(1).String())))
+	require.Zero(promtestutil.ToFloat64(probationCountMtc.WithLabelValues(identityset.Address(2).String())))
+}
+
+func TestApplyBps(t *testing.T) {
+	require := require.New(t)
+
+	require.Zero(applyBps(big.NewInt(9), big.NewInt(1000)).Sign())
+	require.Equal(big.NewInt(1), applyBps(big.NewInt(19), big.NewInt(1000)))
+	require.Equal(big.NewInt(12345), applyBps(big.NewInt(12345), big.NewInt(10000)))
+}
+
+func TestResizeBlockProducers(t *testing.T) {
+	require := require.New(t)
+
+	g := genesis.Default
+	g.JutlandBlockHeight = 100
+	g.MinActiveDelegates = 2
+	sh, err := NewSlasher(&g, nil, nil, nil, nil, nil, 6, 4, 1, 85, 2, 4, 90)
+	require.NoError(err)
+
+	for _, c := range []struct {
+		numQualified, numCandidateDelegates, numDelegates uint64
+	}{
+		{10, 6, 4},
+		{6, 6, 4},
+		{5, 5, 4},
+		{4, 4, 3},
+		{3, 3, 2},
+		{2, 2, 2},
+		{1, 1, 1},
+		{0, 0, 0},
+	} {
+		numCandidateDelegates, numDelegates := sh.resize(c.numQualified)
+		require.Equal(c.numCandidateDelegates, numCandidateDelegates)
+		require.Equal(c.numDelegates, numDelegates)
+	}
+	g.MinActiveDelegates = 4
+	sh4, err := NewSlasher(&g, nil, nil, nil, nil, nil, 6, 4, 1, 85, 2, 4, 90)
+	require.NoError(err)
+	_, numDelegates := sh4.resize(3)
+	require.EqualValues(3, numDelegates)
+
+	// 4 of the top 6 candidates are qualified
+	var candidates state.CandidateList
+	for i := 0; i < 8; i++ {
+		votes := big.NewInt(int64(100 - i))
+		if i == 1 || i == 3 || i > 5 {
+			votes = big.NewInt(0)
+		}
+		candidates = append(candidates, &state.Candidate{Address: identityset.Address(i).String(), Votes: votes})
+	}
+	ctx := context.Background()
+	for _, c := range []struct {
+		epochStartHeight uint64
+		numDelegates     int
+	}{
+		{1, 4},
+		{100, 3},
+	} {
+		bps, err := sh.calculateBlockProducer(candidates, c.epochStartHeight)
+		require.NoError(err)
+		require.Equal(4, len(bps))
+		for _, bp := range bps {
+			require.Equal(1, bp.Votes.Sign())
+		}
+		abps, err := sh.calculateActiveBlockProducer(ctx, bps, c.epochStartHeight)
+		require.NoError(err)
+		require.Equal(c.numDelegates, len(abps))
+	}
+
+	// all the 8 candidates are qualified, capped at the 6 candidate delegates
+	for i, c := range candidates {
+		c.Votes = big.NewInt(int64(100 - i))
+	}
+	bps, err := sh.calculateBlockProducer(candidates, 100)
+	require.NoError(err)
+	require.Equal(6, len(bps))
+	abps, err := sh.calculateActiveBlockProducer(ctx, bps, 100)
+	require.NoError(err)
+	require.Equal(4, len(abps))
+}
+
+func TestNextProbationList(t *testing.T) {
+	require := require.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	g := genesis.Default
+	g.EasterBlockHeight = 1
+	a, b, c := identityset.Address(1).String(), identityset.Address(2).String(), identityset.Address(3).String()
+	getUnprodDelegate := func(protocol.StateReader) (*vote.UnproductiveDelegate, error) {
+		upd, err := vote.NewUnproductiveDelegate(2, 4)
+		if err != nil {
+			return nil, err
+		}
+		if err := upd.AddRecentUPD([]string{b}); err != nil {
+			return nil, err
+		}
+		return upd, upd.AddRecentUPD([]string{a})
+	}
+	getProbationList := func(protocol.StateReader, bool) (*vote.ProbationList, uint64, error) {
+		return &vote.ProbationList{
+			ProbationInfo: map[string]uint32{a: 1, b: 1},
+			IntensityRate: 90,
+		}, 0, nil
+	}
+	sh, err := NewSlasher(&g, nil, nil, getProbationList, getUnprodDelegate, nil, 6, 4, 1, 85, 2, 4, 90)
+	require.NoError(err)
+	registry := protocol.NewRegistry()
+	require.NoError(rolldpos.NewProtocol(6, 4, 1).Register(registry))
+	ctx := protocol.WithRegistry(context.Background(), registry)
+	sm := testdb.NewMockStateManager(ctrl)
+
+	uq := unproductiveDelegates(20, map[string]uint64{a: 1, c: 3, "d": 8, "e": 8}, 85)
+	require.ElementsMatch([]string{a, c}, uq)
+
+	// within the probation period since Easter, the probation list is counted from the unproductive delegates
+	pl, upd, err := sh.nextProbationList(ctx, sm, 2, uq, 90)
+	require.NoError(err)
+	require.Equal(uint32(90), pl.IntensityRate)
+	require.Equal(map[string]uint32{a: 2, b: 1, c: 1}, pl.ProbationInfo)
+	require.Len(upd.DelegateList(), 2)
+	require.ElementsMatch([]string{a, c}, upd.DelegateList()[0])
+	require.Equal([]string{a}, upd.DelegateList()[1])
+
+	// otherwise the oldest unproductive delegates are removed from the previous probation list
+	pl, upd, err = sh.nextProbationList(ctx, sm, 5, uq, 90)
+	require.NoError(err)
+	require.Equal(map[string]uint32{a: 2, c: 1}, pl.ProbationInfo)
+	require.ElementsMatch([]string{a, c}, upd.DelegateList()[0])
+
+	// the delegates exempted by the governors are skipped
+	_, err = sm.PutState(
+		&parameter.Exemptions{Addresses: []address.Address{identityset.Address(3)}},
+		protocol.NamespaceOption(parameter.Namespace),
+		protocol.KeyOption([]byte{2}),
+	)
+	require.NoError(err)
+	pl, upd, err = sh.nextProbationList(ctx, sm, 5, uq, 90)
+	require.NoError(err)
+	require.Equal(map[string]uint32{a: 2}, pl.ProbationInfo)
+	require.Equal([]string{a}, upd.DelegateList()[0])
+
+	data, _, err := sh.ReadState(ctx, sm, nil, []byte("ProbationExemptions"))
+	require.NoError(err)
+	exemptions := &parameter.Exemptions{}
+	require.NoError(exemptions.Deserialize(data))
+	require.Equal([]address.Address{identityset.Address(3)}, exemptions.Addresses)
+}
+
+func TestExcludeNewDelegates(t *testing.T) {
+	require := require.New(t)
+
+	a, b, c := identityset.Address(1).String(), identityset.Address(2).String(), identityset.Address(3).String()
+	upd, err := vote.NewUnproductiveDelegate(2, 4)
+	require.NoError(err)
+
+	// the delegates active when the tracking starts have no grace period
+	require.Equal([]string{a}, excludeNewDelegates(upd, []string{a, b}, 10, 3, []string{a}))
+	first, ok := upd.FirstEpoch(b)
+	require.True(ok)
+	require.Zero(first)
+
+	// a newly active delegate is not unproductive within the grace period
+	require.Equal([]string{a}, excludeNewDelegates(upd, []string{a, b, c}, 11, 3, []string{a, c}))
+	require.Equal([]string{a}, excludeNewDelegates(upd, []string{a, c}, 13, 3, []string{a, c}))
+	first, ok = upd.FirstEpoch(c)
+	require.True(ok)
+	require.Equal(uint64(11), first)
+	require.Equal([]string{a, c}, excludeNewDelegates(upd, []string{a, c}, 14, 3, []string{a, c}))
+
+	// the grace period applies since kamchatka height
+	g := genesis.Default
+	g.KamchatkaBlockHeight = 100
+	sh, err := NewSlasher(&g, nil, nil, nil, nil, nil, 36, 24, 1, 85, 2, 4, 90)
+	require.NoError(err)
+	require.False(sh.gracePeriodEnabled(100))
+	g.ProbationGracePeriod = 3
+	sh, err = NewSlasher(&g, nil, nil, nil, nil, nil, 36, 24, 1, 85, 2, 4, 90)
+	require.NoError(err)
+	require.False(sh.gracePeriodEnabled(99))
+	require.True(sh.gracePeriodEnabled(100))
+}
+
+func TestSlashingParams(t *testing.T) {
+	require := require.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	g := genesis.Default
+	sh, err := NewSlasher(&g, nil, nil, nil, nil, nil, 6, 4, 1, 85, 2, 4, 90)
+	require.NoError(err)
+	sm := testdb.NewMockStateManager(ctrl)
+	thres, intensity, err := sh.slashingParams(sm)
+	require.NoError(err)
+	require.Equal(uint64(85), thres)
+	require.Equal(uint32(90), intensity)
+
+	// the parameters changed by the governors take effect
+	_, err = sm.PutState(
+		&parameter.Setting{Value: 50},
+		protocol.NamespaceOption(parameter.Namespace),
+		protocol.KeyOption([]byte{0, parameter.ProbationIntensity}),
+	)
+	require.NoError(err)
+	thres, intensity, err = sh.slashingParams(sm)
+	require.NoError(err)
+	require.Equal(uint64(85), thres)
+	require.Equal(uint32(50), intensity)
+}
+
+func TestProbationListByEpochRange(t *testing.T) {
+	require := require.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	registry := protocol.NewRegistry()
+	rp := rolldpos.NewProtocol(36, 6, 5)
+	require.NoError(registry.Register("rolldpos", rp))
+	ctx := protocol.WithRegistry(context.Background(), registry)
+	indexer, err := NewCandidateIndexer(db.NewMemKVStore())
+	require.NoError(err)
+	require.NoError(indexer.Start(ctx))
+	for e := uint64(1); e <= 3; e++ {
+		pl := vote.NewProbationList(90)
+		pl.ProbationInfo[identityset.Address(int(e)).String()] = uint32(e)
+		require.NoError(indexer.PutProbationList(rp.GetEpochHeight(e), pl))
+	}
+	g := genesis.Default
+	sh, err := NewSlasher(&g, nil, nil, nil, nil, indexer, 6, 4, 1, 85, 2, 4, 90)
+	require.NoError(err)
+	sm := testdb.NewMockStateManager(ctrl)
+
+	readRange := func(args ...string) ([]byte, uint64, error) {
+		bargs := make([][]byte, len(args))
+		for i, arg := range args {
+			bargs[i] = []byte(arg)
+		}
+		return sh.ReadState(ctx, sm, indexer, []byte("ProbationListByEpochRange"), bargs...)
+	}
+	data, height, err := readRange("1", "3")
+	require.NoError(err)
+	require.Equal(rp.GetEpochHeight(3), height)
+	pls := make(vote.ProbationListsByEpoch)
+	require.NoError(pls.Deserialize(data))
+	require.Equal(3, len(pls))
+	for e := uint64(1); e <= 3; e++ {
+		require.Equal(uint32(e), pls[e].ProbationInfo[identityset.Address(int(e)).String()])
+		require.Equal(uint32(90), pls[e].IntensityRate)
+	}
+
+	_, _, err = readRange("2", "4")
+	require.Equal(protocol.ErrFutureEpoch, errors.Cause(err))
+	for _, args := range [][]string{
+		{"1"},
+		{"3", "1"},
+		{"1", "x"},
+		{"1", strconv.Itoa(MaxProbationListEpochRange + 1)},
+	} {
+		_, _, err = readRange(args...)
+		require.Equal(protocol.ErrInvalidArgument, errors.Cause(err))
+	}
+}
+
+func TestSimulateProbationListWithoutDelegates(t *testing.T) {
+	require := require.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	registry := protocol.NewRegistry()
+	rp := rolldpos.NewProtocol(36, 6, 5)
+	require.NoError(registry.Register("rolldpos", rp))
+	ctx := protocol.WithRegistry(context.Background(), registry)
+	g := genesis.Default
+	g.EasterBlockHeight = 1
+	getCandidates := func(protocol.StateReader, uint64, bool, bool) ([]*state.Candidate, uint64, error) {
+		return nil, 0, nil
+	}
+	sh, err := NewSlasher(&g, nil, getCandidates, nil, nil, nil, 6, 4, 1, 85, 2, 4, 90)
+	require.NoError(err)
+	sm := testdb.NewMockStateManager(ctrl)
+
+	_, err = sh.SimulateProbationList(ctx, sm, map[string]uint64{})
+	require.Equal(protocol.ErrInvalidArgument, errors.Cause(err))
+}
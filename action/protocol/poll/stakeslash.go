@@ -0,0 +1,182 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package poll
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"sort"
+
+	"github.com/iotexproject/go-pkgs/hash"
+	"github.com/iotexproject/iotex-address/address"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/iotexproject/iotex-core/action"
+	"github.com/iotexproject/iotex-core/action/protocol"
+	"github.com/iotexproject/iotex-core/action/protocol/vote"
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/pkg/log"
+	"github.com/iotexproject/iotex-core/state"
+)
+
+const (
+	_stakeSlashesKey = "stakeSlashes"
+	_amountLength    = 32
+)
+
+// DelegateSlashedTopic is the first topic of the system log of slashing the self-stake of a delegate, followed by the
+// address of the delegate. The data is the amount slashed in 32 bytes big endian
+var DelegateSlashedTopic = hash.Hash256b([]byte("Poll.DelegateSlashed"))
+
+type (
+	// StakeSlasher slashes the self-stake of the candidate operated by the address, at the rate in basis points, and
+	// returns the amount slashed
+	StakeSlasher interface {
+		SlashSelfStake(context.Context, protocol.StateManager, address.Address, uint64) (*big.Int, error)
+	}
+
+	// stakeSlash is the self-stake slashed from a delegate in a block
+	stakeSlash struct {
+		Delegate string
+		Amount   *big.Int
+	}
+
+	// stakeSlashes are the slashes in a block, buffered in the working set till the system logs are created
+	stakeSlashes struct {
+		Slashes []*stakeSlash
+	}
+)
+
+// Serialize serializes the slashes
+func (ss *stakeSlashes) Serialize() ([]byte, error) {
+	return json.Marshal(ss)
+}
+
+// Deserialize deserializes the slashes
+func (ss *stakeSlashes) Deserialize(data []byte) error {
+	return json.Unmarshal(data, ss)
+}
+
+// stakeSlashActive returns true if the stake slash rate is set in genesis and the block is since Kamchatka height
+func (sh *Slasher) stakeSlashActive(ctx context.Context) bool {
+	if sh.stakeSlashRate == 0 || sh.stakeSlasher == nil {
+		return false
+	}
+	blkCtx := protocol.MustGetBlockCtx(ctx)
+	return sh.hu.IsPost(config.Kamchatka, blkCtx.BlockHeight)
+}
+
+// slashProbation slashes the self-stake of the delegates entering the probation list of the next epoch. A delegate
+// staying on probation in the following epochs is not slashed again, its voting power is reduced as before
+func (sh *Slasher) slashProbation(ctx context.Context, sm protocol.StateManager, nextList *vote.ProbationList) error {
+	if !sh.stakeSlashActive(ctx) {
+		return nil
+	}
+	curList, _, err := sh.getProbationList(sm, false)
+	switch errors.Cause(err) {
+	case nil:
+	case state.ErrStateNotExist:
+		curList = &vote.ProbationList{}
+	default:
+		return errors.Wrap(err, "failed to get the probation list of current epoch")
+	}
+	delegates := make([]string, 0, len(nextList.ProbationInfo))
+	for delegate := range nextList.ProbationInfo {
+		if _, ok := curList.ProbationInfo[delegate]; !ok {
+			delegates = append(delegates, delegate)
+		}
+	}
+	sort.Strings(delegates)
+	for _, delegate := range delegates {
+		addr, err := address.FromString(delegate)
+		if err != nil {
+			return err
+		}
+		if err := sh.slashStake(ctx, sm, addr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// slashStake slashes the self-stake of the delegate on top of the reduced voting power, if the stake slash rate is set
+// in genesis. The amount slashed is deposited into the rewarding pool if StakeSlashToRewardingPool is set in genesis,
+// otherwise it is burned
+func (sh *Slasher) slashStake(ctx context.Context, sm protocol.StateManager, delegate address.Address) error {
+	if !sh.stakeSlashActive(ctx) {
+		return nil
+	}
+	amount, err := sh.stakeSlasher.SlashSelfStake(ctx, sm, delegate, sh.stakeSlashRate)
+	if err != nil {
+		return errors.Wrapf(err, "failed to slash the self-stake of %s", delegate.String())
+	}
+	if amount.Sign() == 0 {
+		return nil
+	}
+	if sh.slashToRewardingPool {
+		if sh.creditFund == nil {
+			return errors.New("failed to credit the slashed stake without rewarding protocol")
+		}
+		if err := sh.creditFund(ctx, sm, amount); err != nil {
+			return errors.Wrap(err, "failed to credit the slashed stake into the rewarding pool")
+		}
+		log.L().Info(
+			"Slashed the self-stake of delegate into the rewarding pool.",
+			zap.String("delegate", delegate.String()),
+			zap.String("amount", amount.String()),
+		)
+	} else {
+		log.L().Info(
+			"Slashed and burned the self-stake of delegate.",
+			zap.String("delegate", delegate.String()),
+			zap.String("amount", amount.String()),
+		)
+	}
+	ss := &stakeSlashes{}
+	if err := sm.Unload(protocolID, _stakeSlashesKey, ss); err != nil && errors.Cause(err) != protocol.ErrNoName {
+		return err
+	}
+	ss.Slashes = append(ss.Slashes, &stakeSlash{
+		Delegate: delegate.String(),
+		Amount:   amount,
+	})
+	return sm.Load(protocolID, _stakeSlashesKey, ss)
+}
+
+// stakeSlashLogs returns the logs of the slashes in the block
+func stakeSlashLogs(ctx context.Context, sm protocol.StateManager, protocolAddr address.Address) ([]*action.Log, error) {
+	ss := &stakeSlashes{}
+	if err := sm.Unload(protocolID, _stakeSlashesKey, ss); err != nil {
+		if errors.Cause(err) == protocol.ErrNoName {
+			return nil, nil
+		}
+		return nil, err
+	}
+	blkCtx := protocol.MustGetBlockCtx(ctx)
+	logs := make([]*action.Log, 0, len(ss.Slashes))
+	for _, s := range ss.Slashes {
+		delegate, err := address.FromString(s.Delegate)
+		if err != nil {
+			return nil, err
+		}
+		data := make([]byte, _amountLength)
+		v := s.Amount.Bytes()
+		copy(data[_amountLength-len(v):], v)
+		logs = append(logs, &action.Log{
+			Address: protocolAddr.String(),
+			Topics: action.Topics{
+				DelegateSlashedTopic,
+				hash.BytesToHash256(delegate.Bytes()),
+			},
+			Data:        data,
+			BlockHeight: blkCtx.BlockHeight,
+		})
+	}
+	return logs, nil
+}
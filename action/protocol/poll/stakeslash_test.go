@@ -0,0 +1,155 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package poll
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/iotexproject/go-pkgs/hash"
+	"github.com/iotexproject/iotex-address/address"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/action/protocol"
+	"github.com/iotexproject/iotex-core/action/protocol/vote"
+	"github.com/iotexproject/iotex-core/blockchain/genesis"
+	"github.com/iotexproject/iotex-core/state"
+	"github.com/iotexproject/iotex-core/test/identityset"
+	"github.com/iotexproject/iotex-core/testutil/testdb"
+)
+
+type fakeStakeSlasher struct {
+	amount *big.Int
+	rates  map[string]uint64
+}
+
+func (f *fakeStakeSlasher) SlashSelfStake(
+	_ context.Context,
+	_ protocol.StateManager,
+	operator address.Address,
+	rate uint64,
+) (*big.Int, error) {
+	f.rates[operator.String()] = rate
+	return new(big.Int).Set(f.amount), nil
+}
+
+func TestSlashStake(t *testing.T) {
+	require := require.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := protocol.WithBlockCtx(context.Background(), protocol.BlockCtx{BlockHeight: 10})
+	addr := identityset.Address(1)
+	protocolAddr := identityset.Address(30)
+	g := genesis.Default
+	g.KamchatkaBlockHeight = 10
+	sh, err := NewSlasher(&g, nil, nil, nil, nil, nil, 6, 4, 1, 85, 2, 4, 90)
+	require.NoError(err)
+	ss := &fakeStakeSlasher{amount: big.NewInt(100), rates: map[string]uint64{}}
+	sh.stakeSlasher = ss
+	deposited := big.NewInt(0)
+	sh.creditFund = func(_ context.Context, _ protocol.StateManager, amount *big.Int) error {
+		deposited.Add(deposited, amount)
+		return nil
+	}
+
+	// disabled by default
+	sm := testdb.NewMockStateManager(ctrl)
+	require.NoError(sh.slashStake(ctx, sm, addr))
+	require.Empty(ss.rates)
+	logs, err := stakeSlashLogs(ctx, sm, protocolAddr)
+	require.NoError(err)
+	require.Empty(logs)
+
+	// not slashed before Kamchatka height
+	sh.stakeSlashRate = 500
+	require.NoError(sh.slashStake(protocol.WithBlockCtx(ctx, protocol.BlockCtx{BlockHeight: 9}), sm, addr))
+	require.Empty(ss.rates)
+
+	// burned
+	require.NoError(sh.slashStake(ctx, sm, addr))
+	require.Equal(uint64(500), ss.rates[addr.String()])
+	require.Zero(deposited.Sign())
+
+	// deposited into the rewarding pool
+	sh.slashToRewardingPool = true
+	require.NoError(sh.slashStake(ctx, sm, identityset.Address(2)))
+	require.Equal(big.NewInt(100), deposited)
+
+	// nothing to slash
+	ss.amount = big.NewInt(0)
+	require.NoError(sh.slashStake(ctx, sm, identityset.Address(3)))
+	require.Equal(big.NewInt(100), deposited)
+
+	// no rewarding protocol to deposit into
+	ss.amount = big.NewInt(100)
+	creditFund := sh.creditFund
+	sh.creditFund = nil
+	require.Error(sh.slashStake(ctx, sm, identityset.Address(4)))
+	sh.creditFund = creditFund
+	ss.amount = big.NewInt(0)
+
+	logs, err = stakeSlashLogs(ctx, sm, protocolAddr)
+	require.NoError(err)
+	require.Equal(2, len(logs))
+	for i, l := range logs {
+		require.Equal(protocolAddr.String(), l.Address)
+		require.Equal(DelegateSlashedTopic, l.Topics[0])
+		require.Equal(hash.BytesToHash256(identityset.Address(i+1).Bytes()), l.Topics[1])
+		require.Equal(_amountLength, len(l.Data))
+		require.Equal(big.NewInt(100), new(big.Int).SetBytes(l.Data))
+		require.Equal(uint64(10), l.BlockHeight)
+	}
+}
+
+func TestSlashProbation(t *testing.T) {
+	require := require.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := protocol.WithBlockCtx(context.Background(), protocol.BlockCtx{BlockHeight: 10})
+	a, b, c := identityset.Address(1).String(), identityset.Address(2).String(), identityset.Address(3).String()
+	g := genesis.Default
+	g.KamchatkaBlockHeight = 1
+	g.StakeSlashRate = 500
+	curList := vote.NewProbationList(90)
+	getProbationList := func(_ protocol.StateReader, readFromNext bool) (*vote.ProbationList, uint64, error) {
+		require.False(readFromNext)
+		if curList == nil {
+			return nil, 0, state.ErrStateNotExist
+		}
+		return curList, 0, nil
+	}
+	sh, err := NewSlasher(&g, nil, nil, getProbationList, nil, nil, 6, 4, 1, 85, 2, 4, 90)
+	require.NoError(err)
+	ss := &fakeStakeSlasher{amount: big.NewInt(100), rates: map[string]uint64{}}
+	sh.stakeSlasher = ss
+	sm := testdb.NewMockStateManager(ctrl)
+
+	// no probation list in current epoch, all the delegates are new on probation
+	curList = nil
+	nextList := vote.NewProbationList(90)
+	nextList.ProbationInfo[a] = 1
+	nextList.ProbationInfo[b] = 2
+	require.NoError(sh.slashProbation(ctx, sm, nextList))
+	require.Equal(2, len(ss.rates))
+
+	// the delegates staying on probation are not slashed again
+	ss.rates = map[string]uint64{}
+	curList = nextList
+	nextList = vote.NewProbationList(90)
+	nextList.ProbationInfo[b] = 3
+	nextList.ProbationInfo[c] = 1
+	require.NoError(sh.slashProbation(ctx, sm, nextList))
+	require.Equal(map[string]uint64{c: 500}, ss.rates)
+
+	logs, err := stakeSlashLogs(ctx, sm, identityset.Address(30))
+	require.NoError(err)
+	require.Equal(3, len(logs))
+}
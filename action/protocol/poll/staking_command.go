@@ -15,6 +15,9 @@ import (
 
 	"github.com/iotexproject/iotex-core/action"
 	"github.com/iotexproject/iotex-core/action/protocol"
+	"github.com/iotexproject/iotex-core/action/protocol/rolldpos"
+	"github.com/iotexproject/iotex-core/action/protocol/staking"
+	"github.com/iotexproject/iotex-core/action/protocol/vote"
 	"github.com/iotexproject/iotex-core/config"
 	"github.com/iotexproject/iotex-core/state"
 )
@@ -81,6 +84,18 @@ func (sc *stakingCommand) CreatePreStates(ctx context.Context, sm protocol.State
 	return nil
 }
 
+func (sc *stakingCommand) CreateSystemLogs(ctx context.Context, sm protocol.StateManager) ([]*action.Log, error) {
+	if sc.useV2(ctx, sm) {
+		if p, ok := sc.stakingV2.(protocol.SystemLogsCreator); ok {
+			return p.CreateSystemLogs(ctx, sm)
+		}
+	}
+	if p, ok := sc.stakingV1.(protocol.SystemLogsCreator); ok {
+		return p.CreateSystemLogs(ctx, sm)
+	}
+	return nil, nil
+}
+
 func (sc *stakingCommand) CreatePostSystemActions(ctx context.Context, sr protocol.StateReader) ([]action.Envelope, error) {
 	// no height here,  v1 v2 has the same createPostSystemActions method, so directly use common one
 	return createPostSystemActions(ctx, sr, sc)
@@ -115,6 +130,37 @@ func (sc *stakingCommand) CalculateUnproductiveDelegates(
 	return sc.stakingV1.CalculateUnproductiveDelegates(ctx, sr)
 }
 
+func (sc *stakingCommand) SimulateProbationList(
+	ctx context.Context,
+	sr protocol.StateReader,
+	produce map[string]uint64,
+) (*vote.ProbationList, error) {
+	p := sc.stakingV1
+	if sc.useV2(ctx, sr) {
+		p = sc.stakingV2
+	}
+	ps, ok := p.(ProbationSimulator)
+	if !ok {
+		return nil, errors.New("probation is not supported")
+	}
+	return ps.SimulateProbationList(ctx, sr, produce)
+}
+
+func (sc *stakingCommand) ProbationList(
+	ctx context.Context,
+	sr protocol.StateReader,
+) (*vote.ProbationList, error) {
+	p := sc.stakingV1
+	if sc.useV2(ctx, sr) {
+		p = sc.stakingV2
+	}
+	pr, ok := p.(ProbationReader)
+	if !ok {
+		return nil, errors.New("probation is not supported")
+	}
+	return pr.ProbationList(ctx, sr)
+}
+
 // Delegates returns exact number of delegates of current epoch
 func (sc *stakingCommand) Delegates(ctx context.Context, sr protocol.StateReader) (state.CandidateList, error) {
 	if sc.useV2(ctx, sr) {
@@ -154,7 +200,7 @@ func (sc *stakingCommand) ReadState(ctx context.Context, sr protocol.StateReader
 			// check if reading from v1 only method
 			return sc.stakingV1.ReadState(ctx, sr, method, args...)
 		}
-		return res, height, nil
+		return res, height, err
 	}
 	return sc.stakingV1.ReadState(ctx, sr, method, args...)
 }
@@ -173,6 +219,14 @@ func (sc *stakingCommand) Name() string {
 	return protocolID
 }
 
+// Dependencies returns the IDs of the protocols which the protocol depends on
+func (sc *stakingCommand) Dependencies() []string {
+	if sc.stakingV2 != nil {
+		return []string{rolldpos.ProtocolID, staking.ProtocolID}
+	}
+	return []string{rolldpos.ProtocolID}
+}
+
 func (sc *stakingCommand) useV2(ctx context.Context, sr protocol.StateReader) bool {
 	height, err := sr.Height()
 	if err != nil {
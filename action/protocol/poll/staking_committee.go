@@ -28,6 +28,7 @@ import (
 	"github.com/iotexproject/iotex-core/action/protocol"
 	"github.com/iotexproject/iotex-core/action/protocol/execution/evm"
 	"github.com/iotexproject/iotex-core/action/protocol/rolldpos"
+	"github.com/iotexproject/iotex-core/action/protocol/vote"
 	"github.com/iotexproject/iotex-core/config"
 	"github.com/iotexproject/iotex-core/pkg/log"
 	"github.com/iotexproject/iotex-core/pkg/prometheustimer"
@@ -177,18 +178,38 @@ func (sc *stakingCommittee) CreatePreStates(ctx context.Context, sm protocol.Sta
 	return nil
 }
 
+func (sc *stakingCommittee) CreateSystemLogs(ctx context.Context, sm protocol.StateManager) ([]*action.Log, error) {
+	if slc, ok := sc.governanceStaking.(protocol.SystemLogsCreator); ok {
+		return slc.CreateSystemLogs(ctx, sm)
+	}
+
+	return nil, nil
+}
+
 func (sc *stakingCommittee) CreatePostSystemActions(ctx context.Context, sr protocol.StateReader) ([]action.Envelope, error) {
 	return createPostSystemActions(ctx, sr, sc)
 }
 
 func (sc *stakingCommittee) Handle(ctx context.Context, act action.Action, sm protocol.StateManager) (*action.Receipt, error) {
 	receipt, err := sc.governanceStaking.Handle(ctx, act, sm)
+	if _, ok := act.(*action.PutPollResult); !ok {
+		// the native buckets are persisted along with the poll result only
+		return receipt, err
+	}
 	if err := sc.persistNativeBuckets(ctx, receipt, err); err != nil {
 		return nil, err
 	}
 	return receipt, err
 }
 
+func (sc *stakingCommittee) Commit(ctx context.Context, sm protocol.StateManager) error {
+	if c, ok := sc.governanceStaking.(protocol.Committer); ok {
+		return c.Commit(ctx, sm)
+	}
+
+	return nil
+}
+
 func (sc *stakingCommittee) Validate(ctx context.Context, act action.Action, sr protocol.StateReader) error {
 	return validate(ctx, sr, sc, act)
 }
@@ -197,6 +218,11 @@ func (sc *stakingCommittee) Name() string {
 	return protocolID
 }
 
+// Dependencies returns the IDs of the protocols which the protocol depends on
+func (sc *stakingCommittee) Dependencies() []string {
+	return []string{rolldpos.ProtocolID}
+}
+
 // CalculateCandidatesByHeight calculates delegates with native staking and returns merged list
 func (sc *stakingCommittee) CalculateCandidatesByHeight(ctx context.Context, sr protocol.StateReader, height uint64) (state.CandidateList, error) {
 	timer := sc.timerFactory.NewTimer("Governance")
@@ -241,6 +267,29 @@ func (sc *stakingCommittee) CalculateUnproductiveDelegates(
 	return sc.governanceStaking.CalculateUnproductiveDelegates(ctx, sr)
 }
 
+func (sc *stakingCommittee) SimulateProbationList(
+	ctx context.Context,
+	sr protocol.StateReader,
+	produce map[string]uint64,
+) (*vote.ProbationList, error) {
+	ps, ok := sc.governanceStaking.(ProbationSimulator)
+	if !ok {
+		return nil, errors.New("probation is not supported")
+	}
+	return ps.SimulateProbationList(ctx, sr, produce)
+}
+
+func (sc *stakingCommittee) ProbationList(
+	ctx context.Context,
+	sr protocol.StateReader,
+) (*vote.ProbationList, error) {
+	pr, ok := sc.governanceStaking.(ProbationReader)
+	if !ok {
+		return nil, errors.New("probation is not supported")
+	}
+	return pr.ProbationList(ctx, sr)
+}
+
 func (sc *stakingCommittee) Delegates(ctx context.Context, sr protocol.StateReader) (state.CandidateList, error) {
 	return sc.governanceStaking.Delegates(ctx, sr)
 }
@@ -198,8 +198,8 @@ func setCandidates(
 		)
 	}
 	if indexer != nil {
-		if err := indexer.PutCandidateList(height, &candidates); err != nil {
-			return errors.Wrapf(err, "failed to put candidatelist into indexer at height %d", height)
+		if err := bufferCandidateList(sm, height, &candidates); err != nil {
+			return errors.Wrapf(err, "failed to buffer candidatelist for indexer at height %d", height)
 		}
 	}
 	if preEaster {
@@ -219,8 +219,8 @@ func setNextEpochProbationList(
 	probationlist *vote.ProbationList,
 ) error {
 	if indexer != nil {
-		if err := indexer.PutProbationList(height, probationlist); err != nil {
-			return errors.Wrapf(err, "failed to put probationlist into indexer at height %d", height)
+		if err := bufferProbationList(sm, height, probationlist); err != nil {
+			return errors.Wrapf(err, "failed to buffer probationlist for indexer at height %d", height)
 		}
 	}
 	probationListKey := candidatesutil.ConstructKey(candidatesutil.NxtProbationKey)
@@ -228,6 +228,13 @@ func setNextEpochProbationList(
 	return err
 }
 
+// setJailList sets the jail list with jail list key
+func setJailList(sm protocol.StateManager, jl *vote.JailList) error {
+	jlKey := candidatesutil.ConstructKey(candidatesutil.JailListKey)
+	_, err := sm.PutState(jl, protocol.KeyOption(jlKey[:]), protocol.NamespaceOption(protocol.SystemNamespace))
+	return err
+}
+
 // setUnproductiveDelegates sets the upd struct with updkey
 func setUnproductiveDelegates(
 	sm protocol.StateManager,
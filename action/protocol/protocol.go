@@ -8,6 +8,7 @@ package protocol
 
 import (
 	"context"
+	"math/big"
 
 	"github.com/pkg/errors"
 
@@ -17,6 +18,19 @@ import (
 var (
 	// ErrUnimplemented indicates a method is not implemented yet
 	ErrUnimplemented = errors.New("method is unimplemented")
+
+	// errors returned by ReadState, anything else is taken as internal error
+
+	// ErrNotFound indicates the requested method or state is not found
+	ErrNotFound = errors.New("not found")
+	// ErrInvalidArgument indicates the arguments of the request are invalid
+	ErrInvalidArgument = errors.New("invalid argument")
+	// ErrFutureEpoch indicates the requested epoch is later than the current epoch
+	ErrFutureEpoch = errors.New("epoch is in the future")
+	// ErrFutureHeight indicates the requested height is higher than the tip height
+	ErrFutureHeight = errors.New("height is in the future")
+	// ErrPreActivation indicates the requested state does not exist before the feature is activated
+	ErrPreActivation = errors.New("not activated at the height")
 )
 
 const (
@@ -38,6 +52,12 @@ type Starter interface {
 	Start(context.Context, StateReader) (interface{}, error)
 }
 
+// Dependent declares the IDs of the protocols which the protocol depends on, the registry starts and commits these
+// protocols before the dependent one
+type Dependent interface {
+	Dependencies() []string
+}
+
 // GenesisStateCreator creates some genesis states
 type GenesisStateCreator interface {
 	CreateGenesisStates(context.Context, StateManager) error
@@ -48,6 +68,12 @@ type PreStatesCreator interface {
 	CreatePreStates(context.Context, StateManager) error
 }
 
+// SystemLogsCreator creates the logs of the state changes made outside the actions, e.g., in CreatePreStates, which
+// are appended to the receipt of the last action of the block
+type SystemLogsCreator interface {
+	CreateSystemLogs(context.Context, StateManager) ([]*action.Log, error)
+}
+
 // Committer performs commit action of the protocol
 type Committer interface {
 	Commit(context.Context, StateManager) error
@@ -70,6 +96,9 @@ type ActionHandler interface {
 	Handle(context.Context, action.Action, StateManager) (*action.Receipt, error)
 }
 
+// DepositGas deposits the gas fee of an action into the rewarding pool
+type DepositGas func(ctx context.Context, sm StateManager, amount *big.Int) (*action.TransactionLog, error)
+
 // View stores the view for all protocols
 type View map[string]interface{}
 
@@ -0,0 +1,193 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+// Package protocolctx assembles and validates the context of the protocol calls. The protocols read the context with
+// the MustGet* functions, which panic and crash the node if the caller forgets a value, so the callers outside of the
+// state transition build the context here and get an error instead.
+package protocolctx
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/iotexproject/iotex-core/action/protocol"
+	"github.com/iotexproject/iotex-core/blockchain/genesis"
+	"github.com/iotexproject/iotex-core/config"
+)
+
+// ErrMissingContext indicates that a value required by the protocol call is missing in the context
+var ErrMissingContext = errors.New("missing protocol context")
+
+const (
+	// Registry requires the registry of the protocols
+	Registry Requirement = 1 << iota
+	// Blockchain requires the BlockchainCtx
+	Blockchain
+	// Block requires the BlockCtx
+	Block
+	// Action requires the ActionCtx
+	Action
+	// Feature requires the FeatureCtx
+	Feature
+
+	// ReadState is the requirement of reading the state of the protocols
+	ReadState = Registry | Blockchain | Block | Feature
+	// HandleAction is the requirement of handling an action
+	HandleAction = ReadState | Action
+)
+
+type (
+	featureContextKey struct{}
+
+	// Requirement is the set of values required in the context
+	Requirement uint
+
+	// FeatureCtx provides the height upgrades at the height of the block under process
+	FeatureCtx struct {
+		// BlockHeight is the height of the block under process
+		BlockHeight uint64
+		// HeightUpgrade is the height upgrades of the genesis
+		HeightUpgrade config.HeightUpgrade
+	}
+
+	// Builder assembles the context of the protocol calls
+	Builder struct {
+		registry *protocol.Registry
+		bcCtx    *protocol.BlockchainCtx
+		blkCtx   *protocol.BlockCtx
+		actCtx   *protocol.ActionCtx
+	}
+)
+
+// IsPost returns true if the block under process is at or after the upgrade height
+func (f FeatureCtx) IsPost(name config.HeightName) bool {
+	return f.HeightUpgrade.IsPost(name, f.BlockHeight)
+}
+
+// IsPre returns true if the block under process is before the upgrade height
+func (f FeatureCtx) IsPre(name config.HeightName) bool {
+	return !f.IsPost(name)
+}
+
+// WithFeatureCtx adds FeatureCtx into context
+func WithFeatureCtx(ctx context.Context, f FeatureCtx) context.Context {
+	return context.WithValue(ctx, featureContextKey{}, f)
+}
+
+// GetFeatureCtx gets FeatureCtx
+func GetFeatureCtx(ctx context.Context) (FeatureCtx, bool) {
+	f, ok := ctx.Value(featureContextKey{}).(FeatureCtx)
+	return f, ok
+}
+
+// NewBuilder creates a builder of the context
+func NewBuilder() *Builder {
+	return &Builder{}
+}
+
+// SetRegistry sets the registry of the protocols
+func (b *Builder) SetRegistry(registry *protocol.Registry) *Builder {
+	b.registry = registry
+	return b
+}
+
+// SetBlockchainCtx sets the BlockchainCtx
+func (b *Builder) SetBlockchainCtx(bcCtx protocol.BlockchainCtx) *Builder {
+	b.bcCtx = &bcCtx
+	return b
+}
+
+// SetGenesis sets the genesis of the BlockchainCtx
+func (b *Builder) SetGenesis(g genesis.Genesis) *Builder {
+	if b.bcCtx == nil {
+		b.bcCtx = &protocol.BlockchainCtx{}
+	}
+	b.bcCtx.Genesis = g
+	return b
+}
+
+// SetBlockCtx sets the BlockCtx
+func (b *Builder) SetBlockCtx(blkCtx protocol.BlockCtx) *Builder {
+	b.blkCtx = &blkCtx
+	return b
+}
+
+// SetBlockHeight sets the block height of the BlockCtx, which is enough for reading the state at the height
+func (b *Builder) SetBlockHeight(height uint64) *Builder {
+	if b.blkCtx == nil {
+		b.blkCtx = &protocol.BlockCtx{}
+	}
+	b.blkCtx.BlockHeight = height
+	return b
+}
+
+// SetActionCtx sets the ActionCtx
+func (b *Builder) SetActionCtx(actCtx protocol.ActionCtx) *Builder {
+	b.actCtx = &actCtx
+	return b
+}
+
+// Build adds the values set into the context, and derives the FeatureCtx from the genesis and the block height. The
+// registry is always required, and the BlockchainCtx and the BlockCtx are required once a value depending on them is
+// set, i.e., the BlockCtx with the ActionCtx, and the BlockchainCtx with the BlockCtx
+func (b *Builder) Build(ctx context.Context) (context.Context, error) {
+	if b.registry == nil {
+		return nil, errors.Wrap(ErrMissingContext, "registry is not set")
+	}
+	if b.actCtx != nil && b.blkCtx == nil {
+		return nil, errors.Wrap(ErrMissingContext, "block context is not set for the action context")
+	}
+	if b.blkCtx != nil && b.bcCtx == nil {
+		return nil, errors.Wrap(ErrMissingContext, "blockchain context is not set for the block context")
+	}
+	ctx = protocol.WithRegistry(ctx, b.registry)
+	if b.bcCtx != nil {
+		ctx = protocol.WithBlockchainCtx(ctx, *b.bcCtx)
+	}
+	if b.blkCtx != nil {
+		ctx = protocol.WithBlockCtx(ctx, *b.blkCtx)
+		ctx = WithFeatureCtx(ctx, FeatureCtx{
+			BlockHeight:   b.blkCtx.BlockHeight,
+			HeightUpgrade: config.NewHeightUpgrade(&b.bcCtx.Genesis),
+		})
+	}
+	if b.actCtx != nil {
+		ctx = protocol.WithActionCtx(ctx, *b.actCtx)
+	}
+	return ctx, nil
+}
+
+// Validate checks that the values of the requirement are in the context, so that a protocol call with the context
+// won't panic on a missing value
+func Validate(ctx context.Context, required Requirement) error {
+	if required&Registry != 0 {
+		if reg, ok := protocol.GetRegistry(ctx); !ok || reg == nil {
+			return errors.Wrap(ErrMissingContext, "registry")
+		}
+	}
+	if required&Blockchain != 0 {
+		if _, ok := protocol.GetBlockchainCtx(ctx); !ok {
+			return errors.Wrap(ErrMissingContext, "blockchain context")
+		}
+	}
+	if required&Block != 0 {
+		if _, ok := protocol.GetBlockCtx(ctx); !ok {
+			return errors.Wrap(ErrMissingContext, "block context")
+		}
+	}
+	if required&Action != 0 {
+		if _, ok := protocol.GetActionCtx(ctx); !ok {
+			return errors.Wrap(ErrMissingContext, "action context")
+		}
+	}
+	if required&Feature != 0 {
+		if _, ok := GetFeatureCtx(ctx); !ok {
+			return errors.Wrap(ErrMissingContext, "feature context")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package protocolctx
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/action/protocol"
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/test/identityset"
+)
+
+func TestBuilder(t *testing.T) {
+	require := require.New(t)
+
+	g := config.Default.Genesis
+	g.FairbankBlockHeight = 10
+	registry := protocol.NewRegistry()
+
+	// Case I: missing values
+	_, err := NewBuilder().SetGenesis(g).Build(context.Background())
+	require.Equal(ErrMissingContext, errors.Cause(err))
+	_, err = NewBuilder().SetRegistry(registry).SetBlockHeight(10).Build(context.Background())
+	require.Equal(ErrMissingContext, errors.Cause(err))
+	_, err = NewBuilder().SetRegistry(registry).SetGenesis(g).
+		SetActionCtx(protocol.ActionCtx{Caller: identityset.Address(0)}).
+		Build(context.Background())
+	require.Equal(ErrMissingContext, errors.Cause(err))
+
+	// Case II: context to read state
+	ctx, err := NewBuilder().SetRegistry(registry).SetGenesis(g).SetBlockHeight(10).Build(context.Background())
+	require.NoError(err)
+	require.NoError(Validate(ctx, ReadState))
+	require.Equal(ErrMissingContext, errors.Cause(Validate(ctx, HandleAction)))
+	require.Equal(registry, protocol.MustGetRegistry(ctx))
+	require.Equal(uint64(10), protocol.MustGetBlockCtx(ctx).BlockHeight)
+	require.Equal(g, protocol.MustGetBlockchainCtx(ctx).Genesis)
+	fCtx, ok := GetFeatureCtx(ctx)
+	require.True(ok)
+	require.True(fCtx.IsPost(config.Fairbank))
+	fCtx.BlockHeight = 9
+	require.True(fCtx.IsPre(config.Fairbank))
+
+	// Case III: context to handle action
+	ctx, err = NewBuilder().
+		SetRegistry(registry).
+		SetBlockchainCtx(protocol.BlockchainCtx{Genesis: g}).
+		SetBlockCtx(protocol.BlockCtx{BlockHeight: 9, Producer: identityset.Address(1)}).
+		SetActionCtx(protocol.ActionCtx{Caller: identityset.Address(0)}).
+		Build(context.Background())
+	require.NoError(err)
+	require.NoError(Validate(ctx, HandleAction))
+	require.Equal(identityset.Address(0), protocol.MustGetActionCtx(ctx).Caller)
+	fCtx, ok = GetFeatureCtx(ctx)
+	require.True(ok)
+	require.False(fCtx.IsPost(config.Fairbank))
+
+	// Case IV: nothing is in the background context
+	require.Equal(ErrMissingContext, errors.Cause(Validate(context.Background(), Registry)))
+	require.NoError(Validate(context.Background(), 0))
+}
@@ -0,0 +1,354 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package recovery
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/iotexproject/go-pkgs/hash"
+	"github.com/iotexproject/iotex-address/address"
+	"github.com/iotexproject/iotex-proto/golang/iotextypes"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/iotexproject/iotex-core/action"
+	"github.com/iotexproject/iotex-core/action/protocol"
+	accountutil "github.com/iotexproject/iotex-core/action/protocol/account/util"
+	"github.com/iotexproject/iotex-core/pkg/log"
+	"github.com/iotexproject/iotex-core/state"
+)
+
+const (
+	// TODO: it works only for one instance per protocol definition now
+	protocolID = "recovery"
+	// Namespace is the namespace to store the guardians and the pending recoveries of the accounts
+	Namespace = "Recovery"
+)
+
+// prefixes of the keys of the guardians and the pending recovery of an account
+const (
+	_guardiansPrefix byte = iota
+	_recoveryPrefix
+)
+
+type (
+	// Protocol is the social recovery of the accounts. An account registers its guardians, and once enough of them
+	// approve a new owner and the delay passes, the balance, the staking buckets and the guardians of the account are
+	// moved to the new owner. The account is able to cancel the recovery until enough guardians approve it, after which
+	// the lost key can neither cancel the recovery nor replace the guardians. The operations are the executions to the
+	// protocol address
+	Protocol struct {
+		addr             address.Address
+		depositGas       protocol.DepositGas
+		bucketTransferer BucketTransferer
+	}
+
+	// BucketTransferer transfers all the staking buckets owned by an address to the new owner
+	BucketTransferer interface {
+		TransferBuckets(context.Context, protocol.StateManager, address.Address, address.Address) ([]uint64, error)
+	}
+)
+
+// NewProtocol instantiates the protocol of account recovery, the buckets are not moved if the bucket transferer is nil
+func NewProtocol(depositGas protocol.DepositGas, bucketTransferer BucketTransferer) *Protocol {
+	h := hash.Hash160b([]byte(protocolID))
+	addr, err := address.FromBytes(h[:])
+	if err != nil {
+		log.L().Panic("Error when constructing the address of recovery protocol", zap.Error(err))
+	}
+	return &Protocol{
+		addr:             addr,
+		depositGas:       depositGas,
+		bucketTransferer: bucketTransferer,
+	}
+}
+
+// FindProtocol finds the registered protocol from registry
+func FindProtocol(registry *protocol.Registry) *Protocol {
+	if registry == nil {
+		return nil
+	}
+	p, ok := registry.Find(protocolID)
+	if !ok {
+		return nil
+	}
+	pp, ok := p.(*Protocol)
+	if !ok {
+		log.S().Panic("fail to cast recovery protocol")
+	}
+	return pp
+}
+
+// Address returns the address of the protocol, which is the contract of the executions operating the recoveries
+func (p *Protocol) Address() address.Address {
+	return p.addr
+}
+
+// Handle handles the operations of the account recovery
+func (p *Protocol) Handle(ctx context.Context, act action.Action, sm protocol.StateManager) (*action.Receipt, error) {
+	exec, ok := act.(*action.Execution)
+	if !ok || exec.Contract() != p.addr.String() || !accountutil.IsSystemExecutionActive(ctx) {
+		return nil, nil
+	}
+	si := sm.Snapshot()
+	tLog, err := p.handleOperation(ctx, exec, sm)
+	if err != nil {
+		log.L().Debug("Error when handling recovery operation", zap.Error(err))
+		return accountutil.SettleSystemExecution(ctx, sm, p.depositGas, p.addr, uint64(iotextypes.ReceiptStatus_Failure), si, nil)
+	}
+	return accountutil.SettleSystemExecution(ctx, sm, p.depositGas, p.addr, uint64(iotextypes.ReceiptStatus_Success), si, nil, tLog)
+}
+
+// ReadState reads the guardians or the pending recovery of the account
+func (p *Protocol) ReadState(
+	ctx context.Context,
+	sr protocol.StateReader,
+	method []byte,
+	args ...[]byte,
+) ([]byte, uint64, error) {
+	if len(args) != 1 {
+		return nil, uint64(0), errors.Wrapf(protocol.ErrInvalidArgument, "invalid number of arguments %d", len(args))
+	}
+	account, err := address.FromString(string(args[0]))
+	if err != nil {
+		return nil, uint64(0), errors.Wrap(protocol.ErrInvalidArgument, err.Error())
+	}
+	var s state.Serializer
+	switch string(method) {
+	case "Guardians":
+		s = &Guardians{}
+	case "Recovery":
+		s = &Recovery{}
+	default:
+		return nil, uint64(0), errors.Wrapf(protocol.ErrNotFound, "unknown method %s", string(method))
+	}
+	height, err := sr.State(s, protocol.NamespaceOption(Namespace), protocol.KeyOption(key(s, account)))
+	if err != nil {
+		return nil, height, err
+	}
+	data, err := s.Serialize()
+	return data, height, err
+}
+
+// Register registers the protocol with a unique ID
+func (p *Protocol) Register(r *protocol.Registry) error {
+	return r.Register(protocolID, p)
+}
+
+// ForceRegister registers the protocol with a unique ID and force replacing the previous protocol if it exists
+func (p *Protocol) ForceRegister(r *protocol.Registry) error {
+	return r.ForceRegister(protocolID, p)
+}
+
+// Name returns the name of protocol
+func (p *Protocol) Name() string {
+	return protocolID
+}
+
+func (p *Protocol) handleOperation(
+	ctx context.Context,
+	exec *action.Execution,
+	sm protocol.StateManager,
+) (*action.TransactionLog, error) {
+	actionCtx := protocol.MustGetActionCtx(ctx)
+	blkCtx := protocol.MustGetBlockCtx(ctx)
+	op := &Operation{}
+	if err := op.Deserialize(exec.Data()); err != nil {
+		return nil, err
+	}
+	if exec.Amount() != nil && exec.Amount().Sign() != 0 {
+		return nil, errors.Wrapf(ErrInvalidOperation, "operation %d does not accept amount", op.Op)
+	}
+	caller, err := accountutil.LoadAccount(sm, hash.BytesToHash160(actionCtx.Caller.Bytes()))
+	if err != nil {
+		return nil, err
+	}
+	gasFee := new(big.Int).Mul(actionCtx.GasPrice, new(big.Int).SetUint64(actionCtx.IntrinsicGas))
+	if gasFee.Cmp(caller.Balance) > 0 {
+		return nil, errors.Wrapf(state.ErrNotEnoughBalance, "caller %s balance not enough", actionCtx.Caller.String())
+	}
+
+	if op.Op == OpSetGuardians {
+		if err := p.checkNotFrozen(sm, actionCtx.Caller); err != nil {
+			return nil, err
+		}
+		return nil, p.setGuardians(sm, actionCtx.Caller, op.Guardians)
+	}
+	g := &Guardians{}
+	if err := p.get(sm, op.Account, g); err != nil {
+		return nil, errors.Wrapf(err, "failed to get guardians of %s", op.Account.String())
+	}
+	r := &Recovery{}
+	err = p.get(sm, op.Account, r)
+	switch errors.Cause(err) {
+	case nil:
+	case state.ErrStateNotExist:
+		r = nil
+	default:
+		return nil, err
+	}
+
+	switch op.Op {
+	case OpApprove:
+		if !g.Contains(actionCtx.Caller) {
+			return nil, errors.Wrapf(ErrNotGuardian, "%s of %s", actionCtx.Caller.String(), op.Account.String())
+		}
+		if address.Equal(op.Account, op.NewOwner) {
+			return nil, errors.Wrap(ErrInvalidOperation, "new owner is the account itself")
+		}
+		if r == nil {
+			r = &Recovery{
+				NewOwner:    op.NewOwner,
+				ReadyHeight: blkCtx.BlockHeight + g.Delay,
+			}
+		}
+		if !address.Equal(r.NewOwner, op.NewOwner) {
+			return nil, errors.Wrapf(ErrInvalidOperation, "recovery of %s to %s is pending", op.Account.String(), r.NewOwner.String())
+		}
+		if r.Approved(actionCtx.Caller) {
+			return nil, errors.Wrapf(ErrInvalidOperation, "%s already approved", actionCtx.Caller.String())
+		}
+		r.Approvals = append(r.Approvals, actionCtx.Caller)
+		return nil, p.put(sm, op.Account, r)
+	case OpCancel:
+		if !address.Equal(op.Account, actionCtx.Caller) {
+			return nil, errors.Wrapf(ErrInvalidOperation, "%s cannot cancel recovery of %s", actionCtx.Caller.String(), op.Account.String())
+		}
+		if r == nil {
+			return nil, errors.Wrapf(state.ErrStateNotExist, "no recovery of %s", op.Account.String())
+		}
+		if r.HasQuorum(g) {
+			return nil, errors.Wrapf(ErrAccountFrozen, "cannot cancel recovery of %s", op.Account.String())
+		}
+		return nil, p.del(sm, op.Account, r)
+	case OpExecute:
+		if !g.Contains(actionCtx.Caller) {
+			return nil, errors.Wrapf(ErrNotGuardian, "%s of %s", actionCtx.Caller.String(), op.Account.String())
+		}
+		if r == nil {
+			return nil, errors.Wrapf(state.ErrStateNotExist, "no recovery of %s", op.Account.String())
+		}
+		if !r.HasQuorum(g) {
+			return nil, errors.Wrapf(ErrInvalidOperation, "%d approvals less than threshold %d", len(r.Approvals), g.Threshold)
+		}
+		if blkCtx.BlockHeight < r.ReadyHeight {
+			return nil, errors.Wrapf(ErrInvalidOperation, "recovery of %s not ready until %d", op.Account.String(), r.ReadyHeight)
+		}
+		return p.execute(ctx, sm, op.Account, g, r)
+	}
+	return nil, errors.Wrapf(ErrInvalidOperation, "unknown operation %d", op.Op)
+}
+
+// checkNotFrozen returns ErrAccountFrozen if the pending recovery of the account is approved by enough guardians
+func (p *Protocol) checkNotFrozen(sr protocol.StateReader, account address.Address) error {
+	r := &Recovery{}
+	if err := p.get(sr, account, r); errors.Cause(err) == state.ErrStateNotExist {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	g := &Guardians{}
+	if err := p.get(sr, account, g); err != nil {
+		return errors.Wrapf(err, "failed to get guardians of %s", account.String())
+	}
+	if r.HasQuorum(g) {
+		return errors.Wrapf(ErrAccountFrozen, "cannot set guardians of %s", account.String())
+	}
+	return nil
+}
+
+func (p *Protocol) setGuardians(sm protocol.StateManager, account address.Address, g *Guardians) error {
+	// the pending recovery approved by the previous guardians is dropped
+	if err := p.del(sm, account, &Recovery{}); err != nil {
+		return err
+	}
+	if len(g.Addresses) == 0 {
+		return p.del(sm, account, g)
+	}
+	if len(g.Addresses) > MaxGuardians {
+		return errors.Wrapf(ErrInvalidOperation, "%d guardians exceed the limit %d", len(g.Addresses), MaxGuardians)
+	}
+	if g.Threshold == 0 || g.Threshold > uint64(len(g.Addresses)) {
+		return errors.Wrapf(ErrInvalidOperation, "invalid threshold %d of %d guardians", g.Threshold, len(g.Addresses))
+	}
+	for i, addr := range g.Addresses {
+		if address.Equal(addr, account) || contains(g.Addresses[:i], addr) {
+			return errors.Wrapf(ErrInvalidOperation, "invalid guardian %s", addr.String())
+		}
+	}
+	return p.put(sm, account, g)
+}
+
+// execute moves the balance, the staking buckets and the guardians of the account to the new owner
+func (p *Protocol) execute(
+	ctx context.Context,
+	sm protocol.StateManager,
+	account address.Address,
+	g *Guardians,
+	r *Recovery,
+) (*action.TransactionLog, error) {
+	if err := p.del(sm, account, r); err != nil {
+		return nil, err
+	}
+	if err := p.del(sm, account, g); err != nil {
+		return nil, err
+	}
+	if err := p.get(sm, r.NewOwner, &Guardians{}); errors.Cause(err) == state.ErrStateNotExist {
+		if err := p.put(sm, r.NewOwner, g); err != nil {
+			return nil, err
+		}
+	} else if err != nil {
+		return nil, err
+	}
+	if p.bucketTransferer != nil {
+		if _, err := p.bucketTransferer.TransferBuckets(ctx, sm, account, r.NewOwner); err != nil {
+			return nil, errors.Wrapf(err, "failed to transfer the buckets of %s", account.String())
+		}
+	}
+	acc, err := accountutil.LoadOrCreateAccount(sm, account.String())
+	if err != nil {
+		return nil, err
+	}
+	amount := new(big.Int).Set(acc.Balance)
+	if err := accountutil.Transfer(sm, account, r.NewOwner, amount); err != nil {
+		return nil, err
+	}
+	return &action.TransactionLog{
+		Type:      iotextypes.TransactionLogType_NATIVE_TRANSFER,
+		Sender:    account.String(),
+		Recipient: r.NewOwner.String(),
+		Amount:    amount,
+	}, nil
+}
+
+func (p *Protocol) get(sr protocol.StateReader, account address.Address, s state.Deserializer) error {
+	_, err := sr.State(s, protocol.NamespaceOption(Namespace), protocol.KeyOption(key(s, account)))
+	return err
+}
+
+func (p *Protocol) put(sm protocol.StateManager, account address.Address, s state.Serializer) error {
+	_, err := sm.PutState(s, protocol.NamespaceOption(Namespace), protocol.KeyOption(key(s, account)))
+	return err
+}
+
+func (p *Protocol) del(sm protocol.StateManager, account address.Address, s interface{}) error {
+	_, err := sm.DelState(protocol.NamespaceOption(Namespace), protocol.KeyOption(key(s, account)))
+	if errors.Cause(err) == state.ErrStateNotExist {
+		return nil
+	}
+	return err
+}
+
+// key returns the key of the guardians or the recovery of the account
+func key(s interface{}, account address.Address) []byte {
+	prefix := _guardiansPrefix
+	if _, ok := s.(*Recovery); ok {
+		prefix = _recoveryPrefix
+	}
+	return append([]byte{prefix}, account.Bytes()...)
+}
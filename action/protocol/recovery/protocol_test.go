@@ -0,0 +1,107 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package recovery
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/iotexproject/iotex-address/address"
+	"github.com/iotexproject/iotex-proto/golang/iotextypes"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/action"
+	"github.com/iotexproject/iotex-core/action/protocol"
+	accountutil "github.com/iotexproject/iotex-core/action/protocol/account/util"
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/state"
+	"github.com/iotexproject/iotex-core/test/identityset"
+	"github.com/iotexproject/iotex-core/testutil/testdb"
+)
+
+type fakeBucketTransferer struct {
+	owner, newOwner address.Address
+}
+
+func (f *fakeBucketTransferer) TransferBuckets(
+	_ context.Context,
+	_ protocol.StateManager,
+	owner address.Address,
+	newOwner address.Address,
+) ([]uint64, error) {
+	f.owner, f.newOwner = owner, newOwner
+	return []uint64{1}, nil
+}
+
+func TestProtocol(t *testing.T) {
+	require := require.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	sm := testdb.NewMockStateManager(ctrl)
+	bt := &fakeBucketTransferer{}
+	p := NewProtocol(func(context.Context, protocol.StateManager, *big.Int) (*action.TransactionLog, error) {
+		return nil, nil
+	}, bt)
+	g := config.Default.Genesis
+	height := g.KamchatkaBlockHeight
+	lost, newOwner := identityset.Address(10), identityset.Address(11)
+	require.NoError(accountutil.StoreAccount(sm, lost, &state.Account{Balance: big.NewInt(100)}))
+	operate := func(caller address.Address, op *Operation) uint64 {
+		exec, err := action.NewExecution(p.Address().String(), 1, big.NewInt(0), 100000, big.NewInt(0), op.Serialize())
+		require.NoError(err)
+		ctx := protocol.WithBlockchainCtx(context.Background(), protocol.BlockchainCtx{Genesis: g})
+		ctx = protocol.WithBlockCtx(ctx, protocol.BlockCtx{BlockHeight: height})
+		ctx = protocol.WithActionCtx(ctx, protocol.ActionCtx{Caller: caller, GasPrice: big.NewInt(0)})
+		r, err := p.Handle(ctx, exec, sm)
+		require.NoError(err)
+		return r.Status
+	}
+	success, failure := uint64(iotextypes.ReceiptStatus_Success), uint64(iotextypes.ReceiptStatus_Failure)
+	guardians := &Guardians{
+		Threshold: 2,
+		Delay:     10,
+		Addresses: []address.Address{identityset.Address(1), identityset.Address(2), identityset.Address(3)},
+	}
+	approve := &Operation{Op: OpApprove, Account: lost, NewOwner: newOwner}
+	cancel := &Operation{Op: OpCancel, Account: lost}
+	execute := &Operation{Op: OpExecute, Account: lost}
+
+	// the account cancels the recovery approved by less guardians than the threshold
+	require.Equal(success, operate(lost, &Operation{Op: OpSetGuardians, Guardians: guardians}))
+	require.Equal(success, operate(identityset.Address(1), approve))
+	require.Equal(failure, operate(identityset.Address(4), approve))
+	require.Equal(success, operate(lost, cancel))
+
+	// but not the recovery approved by enough guardians, nor replace the guardians
+	require.Equal(success, operate(identityset.Address(1), approve))
+	require.Equal(success, operate(identityset.Address(2), approve))
+	require.Equal(failure, operate(lost, cancel))
+	require.Equal(failure, operate(lost, &Operation{Op: OpSetGuardians, Guardians: &Guardians{}}))
+	require.Equal(ErrAccountFrozen, errors.Cause(p.checkNotFrozen(sm, lost)))
+
+	// the recovery is executed after the delay, moving the balance and the buckets to the new owner
+	require.Equal(failure, operate(identityset.Address(3), execute))
+	height += guardians.Delay
+	require.Equal(success, operate(identityset.Address(3), execute))
+	require.Equal(lost.String(), bt.owner.String())
+	require.Equal(newOwner.String(), bt.newOwner.String())
+	acc, err := accountutil.AccountState(sm, newOwner.String())
+	require.NoError(err)
+	require.Equal(big.NewInt(100), acc.Balance)
+	acc, err = accountutil.AccountState(sm, lost.String())
+	require.NoError(err)
+	require.Zero(acc.Balance.Sign())
+
+	// the guardians are moved to the new owner, and the account is not frozen any more
+	require.NoError(p.checkNotFrozen(sm, lost))
+	require.Equal(failure, operate(lost, cancel))
+	require.NoError(p.get(sm, newOwner, &Guardians{}))
+}
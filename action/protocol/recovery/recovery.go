@@ -0,0 +1,229 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package recovery
+
+import (
+	"github.com/iotexproject/iotex-address/address"
+	"github.com/pkg/errors"
+
+	"github.com/iotexproject/iotex-core/pkg/util/byteutil"
+)
+
+const (
+	_addressLength = 20
+	_heightLength  = 8
+	_opLength      = 1
+	// MaxGuardians is the max number of the guardians of an account
+	MaxGuardians = 16
+)
+
+// operations of the account recovery, the first byte of the data of the execution to the protocol address
+const (
+	// OpSetGuardians sets the guardians of the caller, the number of the approvals to recover the account, and the
+	// delay in blocks before the recovery could be executed. No guardian removes the guardians. The guardians are not
+	// replaced once a recovery of the caller is approved by enough of them. Data is
+	// op || threshold (8 bytes) || delay (8 bytes) || guardians (20 bytes each)
+	OpSetGuardians byte = iota + 1
+	// OpApprove approves the recovery of the account to the new owner by a guardian, the first approval starts the
+	// delay. Data is op || account (20 bytes) || new owner (20 bytes)
+	OpApprove
+	// OpCancel cancels the recovery of the account by the account itself, until the recovery is approved by enough
+	// guardians. Data is op || account (20 bytes)
+	OpCancel
+	// OpExecute executes the recovery of the account approved by the guardians after the delay, moving the balance,
+	// the staking buckets and the guardians of the account to the new owner. Data is op || account (20 bytes)
+	OpExecute
+)
+
+var (
+	// ErrInvalidOperation indicates the data of the execution is not a valid operation
+	ErrInvalidOperation = errors.New("invalid recovery operation")
+	// ErrNotGuardian indicates the caller is not a guardian of the account
+	ErrNotGuardian = errors.New("caller is not a guardian")
+	// ErrAccountFrozen indicates the account is not able to cancel the recovery or replace the guardians, since the
+	// recovery is approved by enough guardians
+	ErrAccountFrozen = errors.New("account is frozen by the approved recovery")
+)
+
+type (
+	// Guardians are the addresses able to recover an account together
+	Guardians struct {
+		// Threshold is the number of the approvals to recover the account
+		Threshold uint64
+		// Delay is the number of blocks between the first approval and the execution of the recovery
+		Delay     uint64
+		Addresses []address.Address
+	}
+
+	// Recovery is the pending recovery of an account
+	Recovery struct {
+		NewOwner address.Address
+		// ReadyHeight is the height from which the recovery could be executed
+		ReadyHeight uint64
+		Approvals   []address.Address
+	}
+
+	// Operation is an operation of the account recovery
+	Operation struct {
+		Op        byte
+		Account   address.Address
+		NewOwner  address.Address
+		Guardians *Guardians
+	}
+)
+
+// Contains returns true if the address is one of the guardians
+func (g *Guardians) Contains(addr address.Address) bool {
+	return contains(g.Addresses, addr)
+}
+
+// Serialize serializes the guardians
+func (g *Guardians) Serialize() ([]byte, error) {
+	data := make([]byte, 0, 2*_heightLength+len(g.Addresses)*_addressLength)
+	data = append(data, byteutil.Uint64ToBytesBigEndian(g.Threshold)...)
+	data = append(data, byteutil.Uint64ToBytesBigEndian(g.Delay)...)
+	return append(data, addressesBytes(g.Addresses)...), nil
+}
+
+// Deserialize deserializes the guardians
+func (g *Guardians) Deserialize(data []byte) error {
+	if len(data) < 2*_heightLength {
+		return errors.Errorf("invalid guardians length %d", len(data))
+	}
+	addrs, err := bytesAddresses(data[2*_heightLength:])
+	if err != nil {
+		return err
+	}
+	g.Threshold = byteutil.BytesToUint64BigEndian(data[:_heightLength])
+	g.Delay = byteutil.BytesToUint64BigEndian(data[_heightLength : 2*_heightLength])
+	g.Addresses = addrs
+	return nil
+}
+
+// Approved returns true if the recovery is approved by the guardian
+func (r *Recovery) Approved(addr address.Address) bool {
+	return contains(r.Approvals, addr)
+}
+
+// HasQuorum returns true if the recovery is approved by enough guardians
+func (r *Recovery) HasQuorum(g *Guardians) bool {
+	return uint64(len(r.Approvals)) >= g.Threshold
+}
+
+// Serialize serializes the recovery
+func (r *Recovery) Serialize() ([]byte, error) {
+	data := make([]byte, 0, _addressLength+_heightLength+len(r.Approvals)*_addressLength)
+	data = append(data, r.NewOwner.Bytes()...)
+	data = append(data, byteutil.Uint64ToBytesBigEndian(r.ReadyHeight)...)
+	return append(data, addressesBytes(r.Approvals)...), nil
+}
+
+// Deserialize deserializes the recovery
+func (r *Recovery) Deserialize(data []byte) error {
+	if len(data) < _addressLength+_heightLength {
+		return errors.Errorf("invalid recovery length %d", len(data))
+	}
+	newOwner, err := address.FromBytes(data[:_addressLength])
+	if err != nil {
+		return err
+	}
+	approvals, err := bytesAddresses(data[_addressLength+_heightLength:])
+	if err != nil {
+		return err
+	}
+	r.NewOwner = newOwner
+	r.ReadyHeight = byteutil.BytesToUint64BigEndian(data[_addressLength : _addressLength+_heightLength])
+	r.Approvals = approvals
+	return nil
+}
+
+// Serialize serializes the operation into the data of the execution to the protocol address
+func (o *Operation) Serialize() []byte {
+	data := []byte{o.Op}
+	switch o.Op {
+	case OpSetGuardians:
+		b, _ := o.Guardians.Serialize()
+		return append(data, b...)
+	case OpApprove:
+		data = append(data, o.Account.Bytes()...)
+		return append(data, o.NewOwner.Bytes()...)
+	default:
+		return append(data, o.Account.Bytes()...)
+	}
+}
+
+// Deserialize deserializes the operation from the data of the execution to the protocol address
+func (o *Operation) Deserialize(data []byte) error {
+	if len(data) < _opLength {
+		return errors.Wrap(ErrInvalidOperation, "empty data")
+	}
+	*o = Operation{Op: data[0]}
+	data = data[_opLength:]
+	switch o.Op {
+	case OpSetGuardians:
+		g := &Guardians{}
+		if err := g.Deserialize(data); err != nil {
+			return errors.Wrap(ErrInvalidOperation, err.Error())
+		}
+		o.Guardians = g
+		return nil
+	case OpApprove:
+		if len(data) != 2*_addressLength {
+			return errors.Wrapf(ErrInvalidOperation, "invalid data length %d of operation %d", len(data), o.Op)
+		}
+		addrs, err := bytesAddresses(data)
+		if err != nil {
+			return errors.Wrap(ErrInvalidOperation, err.Error())
+		}
+		o.Account, o.NewOwner = addrs[0], addrs[1]
+		return nil
+	case OpCancel, OpExecute:
+		if len(data) != _addressLength {
+			return errors.Wrapf(ErrInvalidOperation, "invalid data length %d of operation %d", len(data), o.Op)
+		}
+		account, err := address.FromBytes(data)
+		if err != nil {
+			return errors.Wrap(ErrInvalidOperation, err.Error())
+		}
+		o.Account = account
+		return nil
+	default:
+		return errors.Wrapf(ErrInvalidOperation, "unknown operation %d", o.Op)
+	}
+}
+
+func contains(addrs []address.Address, addr address.Address) bool {
+	for _, a := range addrs {
+		if address.Equal(a, addr) {
+			return true
+		}
+	}
+	return false
+}
+
+func addressesBytes(addrs []address.Address) []byte {
+	data := make([]byte, 0, len(addrs)*_addressLength)
+	for _, addr := range addrs {
+		data = append(data, addr.Bytes()...)
+	}
+	return data
+}
+
+func bytesAddresses(data []byte) ([]address.Address, error) {
+	if len(data)%_addressLength != 0 {
+		return nil, errors.Errorf("invalid addresses length %d", len(data))
+	}
+	var addrs []address.Address
+	for ; len(data) > 0; data = data[_addressLength:] {
+		addr, err := address.FromBytes(data[:_addressLength])
+		if err != nil {
+			return nil, err
+		}
+		addrs = append(addrs, addr)
+	}
+	return addrs, nil
+}
@@ -0,0 +1,85 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package recovery
+
+import (
+	"testing"
+
+	"github.com/iotexproject/iotex-address/address"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/test/identityset"
+)
+
+func TestGuardiansAndRecovery(t *testing.T) {
+	require := require.New(t)
+
+	g := &Guardians{
+		Threshold: 2,
+		Delay:     100,
+		Addresses: []address.Address{identityset.Address(1), identityset.Address(2), identityset.Address(3)},
+	}
+	data, err := g.Serialize()
+	require.NoError(err)
+	g1 := &Guardians{}
+	require.NoError(g1.Deserialize(data))
+	require.Equal(g.Threshold, g1.Threshold)
+	require.Equal(g.Delay, g1.Delay)
+	require.Equal(3, len(g1.Addresses))
+	require.True(g1.Contains(identityset.Address(2)))
+	require.False(g1.Contains(identityset.Address(4)))
+	require.Error(g1.Deserialize(data[1:]))
+
+	r := &Recovery{
+		NewOwner:    identityset.Address(5),
+		ReadyHeight: 200,
+		Approvals:   []address.Address{identityset.Address(1)},
+	}
+	data, err = r.Serialize()
+	require.NoError(err)
+	r1 := &Recovery{}
+	require.NoError(r1.Deserialize(data))
+	require.Equal(r.NewOwner.String(), r1.NewOwner.String())
+	require.Equal(r.ReadyHeight, r1.ReadyHeight)
+	require.True(r1.Approved(identityset.Address(1)))
+	require.False(r1.Approved(identityset.Address(2)))
+	require.Error(r1.Deserialize(data[:len(data)-1]))
+}
+
+func TestOperation(t *testing.T) {
+	require := require.New(t)
+
+	for _, op := range []*Operation{
+		{
+			Op: OpSetGuardians,
+			Guardians: &Guardians{
+				Threshold: 1,
+				Delay:     10,
+				Addresses: []address.Address{identityset.Address(1)},
+			},
+		},
+		{Op: OpSetGuardians, Guardians: &Guardians{}},
+		{Op: OpApprove, Account: identityset.Address(1), NewOwner: identityset.Address(2)},
+		{Op: OpCancel, Account: identityset.Address(1)},
+		{Op: OpExecute, Account: identityset.Address(1)},
+	} {
+		op1 := &Operation{}
+		require.NoError(op1.Deserialize(op.Serialize()))
+		require.Equal(op.Serialize(), op1.Serialize())
+	}
+
+	for _, data := range [][]byte{
+		nil,
+		{0},
+		{OpSetGuardians, 1},
+		{OpApprove, 1, 2, 3},
+		append([]byte{OpCancel}, identityset.Address(1).Bytes()[1:]...),
+	} {
+		require.Equal(ErrInvalidOperation, errors.Cause((&Operation{}).Deserialize(data)))
+	}
+}
@@ -14,6 +14,13 @@ import (
 	"github.com/pkg/errors"
 )
 
+var (
+	// ErrMissingDependency indicates that a protocol depends on a protocol which is not registered
+	ErrMissingDependency = errors.New("missing protocol dependency")
+	// ErrDependencyCycle indicates that the dependencies among the protocols form a cycle
+	ErrDependencyCycle = errors.New("protocol dependency cycle")
+)
+
 // Registry is the hub of all protocols deployed on the chain
 type Registry struct {
 	mu        sync.RWMutex
@@ -90,15 +97,112 @@ func (r *Registry) all() []Protocol {
 	return all
 }
 
-// StartAll starts all protocols which are startable
+// Validate checks that the dependencies of all protocols are registered and free of cycle
+func (r *Registry) Validate() error {
+	if r == nil {
+		return nil
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := r.names()
+	for i, p := range r.protocols {
+		for _, dep := range dependencies(p) {
+			if _, ok := r.ids[dep]; !ok {
+				return errors.Wrapf(ErrMissingDependency, "protocol %s depends on %s", names[i], dep)
+			}
+		}
+	}
+	_, err := r.sorted()
+	return err
+}
+
+// Sorted returns all protocols in the order of dependency, where each protocol comes after the protocols it depends
+// on, and otherwise keeps the order of registration. The dependencies not registered are ignored
+func (r *Registry) Sorted() ([]Protocol, error) {
+	if r == nil {
+		return nil, nil
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.sorted()
+}
+
+func (r *Registry) sorted() ([]Protocol, error) {
+	var (
+		n          = len(r.protocols)
+		indegrees  = make([]int, n)
+		dependents = make([][]int, n)
+		visited    = make([]bool, n)
+		sorted     = make([]Protocol, 0, n)
+	)
+	for i, p := range r.protocols {
+		for _, dep := range dependencies(p) {
+			j, ok := r.ids[dep]
+			if !ok {
+				continue
+			}
+			indegrees[i]++
+			dependents[j] = append(dependents[j], i)
+		}
+	}
+	for len(sorted) < n {
+		// always pick the earliest registered protocol ready to go, so that the order of registration is kept if it
+		// already satisfies the dependencies
+		next := -1
+		for i := 0; i < n; i++ {
+			if !visited[i] && indegrees[i] == 0 {
+				next = i
+				break
+			}
+		}
+		if next < 0 {
+			names := r.names()
+			var cycle []string
+			for i := 0; i < n; i++ {
+				if !visited[i] {
+					cycle = append(cycle, names[i])
+				}
+			}
+			return nil, errors.Wrapf(ErrDependencyCycle, "among protocols %v", cycle)
+		}
+		visited[next] = true
+		sorted = append(sorted, r.protocols[next])
+		for _, i := range dependents[next] {
+			indegrees[i]--
+		}
+	}
+	return sorted, nil
+}
+
+// names returns the IDs of the protocols by index
+func (r *Registry) names() []string {
+	names := make([]string, len(r.protocols))
+	for id, i := range r.ids {
+		names[i] = id
+	}
+	return names
+}
+
+func dependencies(p Protocol) []string {
+	if d, ok := p.(Dependent); ok {
+		return d.Dependencies()
+	}
+	return nil
+}
+
+// StartAll starts all protocols which are startable, in the order of dependency
 func (r *Registry) StartAll(ctx context.Context, sr StateReader) (View, error) {
 	if r == nil {
 		return nil, nil
 	}
 	r.mu.RLock()
 	defer r.mu.RUnlock()
+	protocols, err := r.sorted()
+	if err != nil {
+		return nil, err
+	}
 	allView := make(View)
-	for _, p := range r.all() {
+	for _, p := range protocols {
 		s, ok := p.(Starter)
 		if !ok {
 			continue
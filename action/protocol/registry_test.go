@@ -7,9 +7,11 @@
 package protocol
 
 import (
+	"context"
 	"testing"
 
 	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/require"
 )
 
@@ -57,3 +59,42 @@ func TestAll(t *testing.T) {
 	require.Equal(all[0], p)
 	require.Nil(all[1])
 }
+
+type dependentProtocol struct {
+	*MockProtocol
+	deps []string
+}
+
+func (p *dependentProtocol) Dependencies() []string { return p.deps }
+
+func TestSorted(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	require := require.New(t)
+
+	newProtocol := func(deps ...string) Protocol {
+		return &dependentProtocol{MockProtocol: NewMockProtocol(ctrl), deps: deps}
+	}
+	reg := NewRegistry()
+	a, b, c, d := newProtocol(), newProtocol("c"), newProtocol("a"), newProtocol()
+	require.NoError(reg.Register("a", a))
+	require.NoError(reg.Register("b", b))
+	require.NoError(reg.Register("c", c))
+	require.NoError(reg.Register("d", d))
+	// Case I: b is moved after c, otherwise the order of registration is kept
+	require.NoError(reg.Validate())
+	sorted, err := reg.Sorted()
+	require.NoError(err)
+	require.Equal([]Protocol{a, c, b, d}, sorted)
+	// Case II: missing dependency is ignored in sorting but fails the validation
+	require.NoError(reg.ForceRegister("d", newProtocol("e")))
+	require.Equal(ErrMissingDependency, errors.Cause(reg.Validate()))
+	_, err = reg.Sorted()
+	require.NoError(err)
+	// Case III: cycle
+	require.NoError(reg.ForceRegister("a", newProtocol("b")))
+	_, err = reg.Sorted()
+	require.Equal(ErrDependencyCycle, errors.Cause(err))
+	_, err = reg.StartAll(context.Background(), nil)
+	require.Equal(ErrDependencyCycle, errors.Cause(err))
+}
@@ -81,14 +81,10 @@ func (p *Protocol) Deposit(
 	if err := accountutil.StoreAccount(sm, actionCtx.Caller, acc); err != nil {
 		return nil, err
 	}
-	// Add balance to fund
-	f := fund{}
-	if _, err := p.state(ctx, sm, fundKey, &f); err != nil {
-		return nil, err
-	}
-	f.totalBalance = big.NewInt(0).Add(f.totalBalance, amount)
-	f.unclaimedBalance = big.NewInt(0).Add(f.unclaimedBalance, amount)
-	if err := p.putState(ctx, sm, fundKey, &f); err != nil {
+	// Add balance to fund, or leave it to be credited after the action is accepted if it is run speculatively
+	if dd, ok := protocol.GetDeferredDeposits(ctx); ok {
+		dd.Defer(amount, p.Credit)
+	} else if err := p.Credit(ctx, sm, amount); err != nil {
 		return nil, err
 	}
 	return &action.TransactionLog{
@@ -99,6 +95,25 @@ func (p *Protocol) Deposit(
 	}, nil
 }
 
+// Credit credits the amount, which has already been taken out of another pool, e.g., the self-stake slashed from
+// the staking bucket pool, into the rewarding fund. Unlike Deposit, it does not debit the caller of the action
+func (p *Protocol) Credit(
+	ctx context.Context,
+	sm protocol.StateManager,
+	amount *big.Int,
+) error {
+	if err := p.assertAmount(amount); err != nil {
+		return err
+	}
+	f := fund{}
+	if _, err := p.state(ctx, sm, fundKey, &f); err != nil {
+		return err
+	}
+	f.totalBalance = big.NewInt(0).Add(f.totalBalance, amount)
+	f.unclaimedBalance = big.NewInt(0).Add(f.unclaimedBalance, amount)
+	return p.putState(ctx, sm, fundKey, &f)
+}
+
 // TotalBalance returns the total balance of the rewarding fund
 func (p *Protocol) TotalBalance(
 	ctx context.Context,
@@ -162,3 +177,19 @@ func DepositGas(ctx context.Context, sm protocol.StateManager, amount *big.Int)
 	}
 	return rp.Deposit(ctx, sm, amount, iotextypes.TransactionLogType_GAS_FEE)
 }
+
+// CreditFund credits the amount taken out of another pool into the rewarding fund
+func CreditFund(ctx context.Context, sm protocol.StateManager, amount *big.Int) error {
+	if amount.Sign() == 0 {
+		return nil
+	}
+	reg, ok := protocol.GetRegistry(ctx)
+	if !ok {
+		return errors.New("failed to get registry to credit the rewarding fund")
+	}
+	rp := FindProtocol(reg)
+	if rp == nil {
+		return errors.New("rewarding protocol is not registered to credit the rewarding fund")
+	}
+	return rp.Credit(ctx, sm, amount)
+}
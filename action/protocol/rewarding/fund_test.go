@@ -52,6 +52,75 @@ func TestProtocol_Fund(t *testing.T) {
 
 }
 
+func TestCreditFund(t *testing.T) {
+	testProtocol(t, func(t *testing.T, ctx context.Context, sm protocol.StateManager, p *Protocol) {
+		// validating a block, the context still carries the action context of the last action
+		actionCtx, ok := protocol.GetActionCtx(ctx)
+		require.True(t, ok)
+		require.NoError(t, CreditFund(ctx, sm, big.NewInt(5)))
+		totalBalance, _, err := p.TotalBalance(ctx, sm)
+		require.NoError(t, err)
+		assert.Equal(t, big.NewInt(5), totalBalance)
+		availableBalance, _, err := p.AvailableBalance(ctx, sm)
+		require.NoError(t, err)
+		assert.Equal(t, big.NewInt(5), availableBalance)
+		// the caller of the last action is not debited
+		acc, err := accountutil.LoadAccount(sm, hash.BytesToHash160(actionCtx.Caller.Bytes()))
+		require.NoError(t, err)
+		assert.Equal(t, big.NewInt(1000), acc.Balance)
+
+		// minting a block, there is no action context when the pre-states are created
+		mintCtx := protocol.WithBlockchainCtx(
+			protocol.WithRegistry(
+				protocol.WithBlockCtx(context.Background(), protocol.MustGetBlockCtx(ctx)),
+				protocol.MustGetRegistry(ctx),
+			),
+			protocol.MustGetBlockchainCtx(ctx),
+		)
+		require.NoError(t, CreditFund(mintCtx, sm, big.NewInt(6)))
+		totalBalance, _, err = p.TotalBalance(ctx, sm)
+		require.NoError(t, err)
+		assert.Equal(t, big.NewInt(11), totalBalance)
+
+		require.Error(t, CreditFund(ctx, sm, big.NewInt(-1)))
+		// the rewarding protocol is required
+		require.Error(t, CreditFund(protocol.WithRegistry(mintCtx, protocol.NewRegistry()), sm, big.NewInt(1)))
+	}, false)
+}
+
+func TestDeferredDeposits(t *testing.T) {
+	testProtocol(t, func(t *testing.T, ctx context.Context, sm protocol.StateManager, p *Protocol) {
+		actionCtx, ok := protocol.GetActionCtx(ctx)
+		require.True(t, ok)
+		_, err := p.Deposit(ctx, sm, big.NewInt(10), iotextypes.TransactionLogType_DEPOSIT_TO_REWARDING_FUND)
+		require.NoError(t, err)
+
+		// the caller is debited while the fund is credited later
+		dd := &protocol.DeferredDeposits{}
+		dctx := protocol.WithDeferredDeposits(ctx, dd)
+		rlog, err := DepositGas(dctx, sm, big.NewInt(5))
+		require.NoError(t, err)
+		require.Equal(t, big.NewInt(5).String(), rlog.Amount.String())
+		acc, err := accountutil.LoadAccount(sm, hash.BytesToHash160(actionCtx.Caller.Bytes()))
+		require.NoError(t, err)
+		assert.Equal(t, big.NewInt(985), acc.Balance)
+		totalBalance, _, err := p.TotalBalance(ctx, sm)
+		require.NoError(t, err)
+		assert.Equal(t, big.NewInt(10), totalBalance)
+		require.True(t, dd.Pending())
+		// the fund cannot be withdrawn from before the deposits are credited
+		require.Error(t, p.updateAvailableBalance(dctx, sm, big.NewInt(1)))
+
+		require.NoError(t, dd.Settle(ctx, sm))
+		totalBalance, _, err = p.TotalBalance(ctx, sm)
+		require.NoError(t, err)
+		assert.Equal(t, big.NewInt(15), totalBalance)
+		availableBalance, _, err := p.AvailableBalance(ctx, sm)
+		require.NoError(t, err)
+		assert.Equal(t, big.NewInt(15), availableBalance)
+	}, false)
+}
+
 func TestDepositNegativeGasFee(t *testing.T) {
 	testProtocol(t, func(t *testing.T, ctx context.Context, sm protocol.StateManager, p *Protocol) {
 		_, err := DepositGas(ctx, sm, big.NewInt(-1))
@@ -0,0 +1,136 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package rewarding
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/pkg/errors"
+
+	"github.com/iotexproject/iotex-core/action/protocol"
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/state"
+)
+
+type (
+	// DelegateRewardProjection is the projected reward of a delegate in an epoch
+	DelegateRewardProjection struct {
+		Address       string `json:"address"`
+		RewardAddress string `json:"rewardAddress"`
+		Votes         string `json:"votes"`
+		// ProducedBlocks is the number of blocks produced by the delegate in the epoch so far
+		ProducedBlocks uint64 `json:"producedBlocks"`
+		// BlockReward is the block reward earned in the epoch so far
+		BlockReward string `json:"blockReward"`
+		// Qualified indicates whether the productivity meets the threshold to share the epoch reward, it is always true
+		// since Easter, when unproductive delegates are put on probation instead
+		Qualified bool `json:"qualified"`
+		// EpochReward is the expected share of the epoch reward if the votes stay unchanged till the epoch ends
+		EpochReward string `json:"epochReward"`
+		// FoundationBonusEligible indicates whether the delegate is expected to be granted the foundation bonus
+		FoundationBonusEligible bool   `json:"foundationBonusEligible"`
+		FoundationBonus         string `json:"foundationBonus"`
+	}
+
+	// EpochRewardProjection is the projected reward distribution of an epoch from the productivity so far
+	EpochRewardProjection struct {
+		EpochNum       uint64                      `json:"epochNum"`
+		Height         uint64                      `json:"height"`
+		ProducedBlocks uint64                      `json:"producedBlocks"`
+		Delegates      []*DelegateRewardProjection `json:"delegates"`
+	}
+)
+
+// ProjectEpochReward projects the reward distribution of the epoch at the height in the context, from the candidates
+// and the number of blocks produced by each delegate in the epoch so far. The epoch reward is split in the same way as
+// it is granted at the last block of the epoch
+func (p *Protocol) ProjectEpochReward(
+	ctx context.Context,
+	sr protocol.StateReader,
+	epochNum uint64,
+	epochStartHeight uint64,
+	candidates []*state.Candidate,
+	productivity map[string]uint64,
+) (*EpochRewardProjection, error) {
+	blkCtx := protocol.MustGetBlockCtx(ctx)
+	bcCtx := protocol.MustGetBlockchainCtx(ctx)
+	a := admin{}
+	if _, err := p.state(ctx, sr, adminKey, &a); err != nil {
+		return nil, err
+	}
+	e := exempt{}
+	if _, err := p.state(ctx, sr, exemptKey, &e); err != nil && errors.Cause(err) != state.ErrStateNotExist {
+		return nil, err
+	}
+	exemptAddrs := make(map[string]interface{})
+	for _, addr := range e.addrs {
+		exemptAddrs[addr.String()] = nil
+	}
+
+	var total uint64
+	for _, n := range productivity {
+		total += n
+	}
+	uqd := make(map[string]bool)
+	hu := config.NewHeightUpgrade(&bcCtx.Genesis)
+	if hu.IsPre(config.Easter, epochStartHeight) && len(productivity) > 0 {
+		expected := total / uint64(len(productivity))
+		for addr, n := range productivity {
+			if n*100 < expected*a.productivityThreshold {
+				uqd[addr] = true
+			}
+		}
+	}
+
+	projection := &EpochRewardProjection{
+		EpochNum:       epochNum,
+		Height:         blkCtx.BlockHeight,
+		ProducedBlocks: total,
+	}
+	delegates := make(map[string]*DelegateRewardProjection, len(candidates))
+	for _, c := range candidates {
+		produced := productivity[c.Address]
+		d := &DelegateRewardProjection{
+			Address:         c.Address,
+			RewardAddress:   c.RewardAddress,
+			Votes:           c.Votes.String(),
+			ProducedBlocks:  produced,
+			BlockReward:     new(big.Int).Mul(a.blockReward, new(big.Int).SetUint64(produced)).String(),
+			Qualified:       !uqd[c.Address],
+			EpochReward:     "0",
+			FoundationBonus: "0",
+		}
+		delegates[c.Address] = d
+		projection.Delegates = append(projection.Delegates, d)
+	}
+
+	filtered := make([]*state.Candidate, 0, len(candidates))
+	for _, c := range candidates {
+		if _, ok := exemptAddrs[c.Address]; !ok {
+			filtered = append(filtered, c)
+		}
+	}
+	if uint64(len(filtered)) > a.numDelegatesForEpochReward {
+		filtered = filtered[:a.numDelegatesForEpochReward]
+	}
+	_, amounts, err := p.splitEpochReward(epochStartHeight, nil, filtered, a.epochReward, a.numDelegatesForEpochReward, exemptAddrs, uqd)
+	if err != nil {
+		return nil, err
+	}
+	for i, amount := range amounts {
+		delegates[filtered[i].Address].EpochReward = amount.String()
+	}
+
+	if p.grantFoundationBonus(epochNum, &a) {
+		for _, c := range foundationBonusCandidates(candidates, exemptAddrs, a.numDelegatesForFoundationBonus) {
+			delegates[c.Address].FoundationBonusEligible = true
+			delegates[c.Address].FoundationBonus = a.foundationBonus.String()
+		}
+	}
+	return projection, nil
+}
@@ -0,0 +1,86 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package rewarding
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/action/protocol"
+	"github.com/iotexproject/iotex-core/action/protocol/poll"
+	"github.com/iotexproject/iotex-core/test/identityset"
+)
+
+func TestProtocol_ProjectEpochReward(t *testing.T) {
+	productivity := map[string]uint64{
+		identityset.Address(27).String(): 10,
+		identityset.Address(28).String(): 10,
+		identityset.Address(29).String(): 2,
+		identityset.Address(30).String(): 10,
+		identityset.Address(31).String(): 8,
+	}
+	type expected struct {
+		blockReward string
+		qualified   bool
+		epochReward string
+		bonus       string
+	}
+	check := func(
+		t *testing.T,
+		ctx context.Context,
+		sm protocol.StateManager,
+		p *Protocol,
+		expects map[int]expected,
+	) {
+		require := require.New(t)
+		candidates, err := poll.MustGetProtocol(protocol.MustGetRegistry(ctx)).Candidates(ctx, sm)
+		require.NoError(err)
+		projection, err := p.ProjectEpochReward(ctx, sm, 1, 1, candidates, productivity)
+		require.NoError(err)
+		require.Equal(uint64(1), projection.EpochNum)
+		require.Equal(uint64(40), projection.ProducedBlocks)
+		require.Len(projection.Delegates, len(candidates))
+		for _, d := range projection.Delegates {
+			for i, e := range expects {
+				if d.Address != identityset.Address(i).String() {
+					continue
+				}
+				require.Equal(e.blockReward, d.BlockReward, d.Address)
+				require.Equal(e.qualified, d.Qualified, d.Address)
+				require.Equal(e.epochReward, d.EpochReward, d.Address)
+				require.Equal(e.bonus != "0", d.FoundationBonusEligible, d.Address)
+				require.Equal(e.bonus, d.FoundationBonus, d.Address)
+			}
+		}
+	}
+
+	testProtocol(t, func(t *testing.T, ctx context.Context, sm protocol.StateManager, p *Protocol) {
+		// the epoch reward is split among the top 4 delegates except the unproductive one
+		check(t, ctx, sm, p, map[int]expected{
+			27: {"100", true, "40", "5"},
+			28: {"100", true, "30", "5"},
+			29: {"20", false, "0", "5"},
+			30: {"100", true, "10", "5"},
+			31: {"80", true, "0", "5"},
+			32: {"0", true, "0", "0"},
+		})
+	}, false)
+
+	testProtocol(t, func(t *testing.T, ctx context.Context, sm protocol.StateManager, p *Protocol) {
+		// the exempted delegate gets neither the epoch reward nor the foundation bonus
+		check(t, ctx, sm, p, map[int]expected{
+			27: {"100", true, "38", "5"},
+			28: {"100", true, "28", "5"},
+			29: {"20", false, "0", "5"},
+			30: {"100", true, "9", "5"},
+			31: {"80", true, "0", "0"},
+			32: {"0", true, "4", "5"},
+		})
+	}, true)
+}
@@ -28,8 +28,9 @@ import (
 
 const (
 	// TODO: it works only for one instance per protocol definition now
-	protocolID  = "rewarding"
-	v2Namespace = "Rewarding"
+	protocolID = "rewarding"
+	// V2Namespace is the namespace of the rewarding states since v2
+	V2Namespace = "Rewarding"
 )
 
 var (
@@ -172,7 +173,11 @@ func (p *Protocol) Handle(
 			log.L().Debug("Error when handling rewarding action", zap.Error(err))
 			return p.settleAction(ctx, sm, uint64(iotextypes.ReceiptStatus_Failure), si, nil)
 		}
-		return p.settleAction(ctx, sm, uint64(iotextypes.ReceiptStatus_Success), si, nil, rlog)
+		events, err := p.claimEvents(ctx, rlog)
+		if err != nil {
+			return nil, err
+		}
+		return p.settleAction(ctx, sm, uint64(iotextypes.ReceiptStatus_Success), si, events, rlog)
 	case *action.GrantReward:
 		switch act.RewardType() {
 		case action.BlockReward:
@@ -221,11 +226,11 @@ func (p *Protocol) ReadState(
 		return []byte(balance.String()), height, nil
 	case "UnclaimedBalance":
 		if len(args) != 1 {
-			return nil, uint64(0), errors.Errorf("invalid number of arguments %d", len(args))
+			return nil, uint64(0), errors.Wrapf(protocol.ErrInvalidArgument, "invalid number of arguments %d", len(args))
 		}
 		addr, err := address.FromString(string(args[0]))
 		if err != nil {
-			return nil, uint64(0), err
+			return nil, uint64(0), errors.Wrap(protocol.ErrInvalidArgument, err.Error())
 		}
 		balance, height, err := p.UnclaimedBalance(ctx, sr, addr)
 		if err != nil {
@@ -233,7 +238,7 @@ func (p *Protocol) ReadState(
 		}
 		return []byte(balance.String()), height, nil
 	default:
-		return nil, uint64(0), errors.New("corresponding method isn't found")
+		return nil, uint64(0), errors.Wrapf(protocol.ErrNotFound, "corresponding method %s isn't found", method)
 	}
 }
 
@@ -283,7 +288,7 @@ func (p *Protocol) stateV1(sm protocol.StateReader, key []byte, value interface{
 
 func (p *Protocol) stateV2(sm protocol.StateReader, key []byte, value interface{}) (uint64, error) {
 	k := append(p.keyPrefix, key...)
-	return sm.State(value, protocol.KeyOption(k), protocol.NamespaceOption(v2Namespace))
+	return sm.State(value, protocol.KeyOption(k), protocol.NamespaceOption(V2Namespace))
 }
 
 func (p *Protocol) putState(ctx context.Context, sm protocol.StateManager, key []byte, value interface{}) error {
@@ -301,7 +306,7 @@ func (p *Protocol) putStateV1(sm protocol.StateManager, key []byte, value interf
 
 func (p *Protocol) putStateV2(sm protocol.StateManager, key []byte, value interface{}) error {
 	k := append(p.keyPrefix, key...)
-	_, err := sm.PutState(value, protocol.KeyOption(k), protocol.NamespaceOption(v2Namespace))
+	_, err := sm.PutState(value, protocol.KeyOption(k), protocol.NamespaceOption(V2Namespace))
 	return err
 }
 
@@ -324,7 +329,7 @@ func (p *Protocol) deleteStateV1(sm protocol.StateManager, key []byte) error {
 
 func (p *Protocol) deleteStateV2(sm protocol.StateManager, key []byte) error {
 	k := append(p.keyPrefix, key...)
-	_, err := sm.DelState(protocol.KeyOption(k), protocol.NamespaceOption(v2Namespace))
+	_, err := sm.DelState(protocol.KeyOption(k), protocol.NamespaceOption(V2Namespace))
 	if errors.Cause(err) == state.ErrStateNotExist {
 		// don't care if not exist
 		return nil
@@ -9,6 +9,7 @@ package rewarding
 import (
 	"context"
 	"math/big"
+	"sort"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/pkg/errors"
@@ -143,8 +144,11 @@ func (p *Protocol) GrantEpochReward(
 	blkCtx := protocol.MustGetBlockCtx(ctx)
 	bcCtx := protocol.MustGetBlockchainCtx(ctx)
 	hu := config.NewHeightUpgrade(&bcCtx.Genesis)
-	rp := rolldpos.MustGetProtocol(protocol.MustGetRegistry(ctx))
-	pp := poll.MustGetProtocol(protocol.MustGetRegistry(ctx))
+	rp := rolldpos.FindProtocol(protocol.MustGetRegistry(ctx))
+	pp := poll.FindProtocol(protocol.MustGetRegistry(ctx))
+	if rp == nil || pp == nil {
+		return nil, errors.New("epoch reward requires rolldpos and poll protocols")
+	}
 	epochNum := rp.GetEpochNum(blkCtx.BlockHeight)
 	if err := p.assertNoRewardYet(ctx, sm, epochRewardHistoryKeyPrefix, epochNum); err != nil {
 		return nil, err
@@ -181,7 +185,7 @@ func (p *Protocol) GrantEpochReward(
 		}
 
 	}
-	candidates, err := poll.MustGetProtocol(protocol.MustGetRegistry(ctx)).Candidates(ctx, sm)
+	candidates, err := pp.Candidates(ctx, sm)
 	if err != nil {
 		return nil, err
 	}
@@ -223,22 +227,14 @@ func (p *Protocol) GrantEpochReward(
 	}
 
 	// Reward additional bootstrap bonus
-	if epochNum <= a.foundationBonusLastEpoch || (epochNum >= p.foundationBonusP2StartEpoch && epochNum <= p.foundationBonusP2EndEpoch) {
-		for i, count := 0, uint64(0); i < len(candidates) && count < a.numDelegatesForFoundationBonus; i++ {
-			if _, ok := exemptAddrs[candidates[i].Address]; ok {
-				continue
-			}
-			if candidates[i].Votes.Cmp(big.NewInt(0)) == 0 {
-				// hard probation
-				continue
-			}
-			count++
+	if p.grantFoundationBonus(epochNum, &a) {
+		for _, candidate := range foundationBonusCandidates(candidates, exemptAddrs, a.numDelegatesForFoundationBonus) {
 			// If reward address doesn't exist, do nothing
-			if candidates[i].RewardAddress == "" {
-				log.S().Warnf("Candidate %s doesn't have a reward address", candidates[i].Address)
+			if candidate.RewardAddress == "" {
+				log.S().Warnf("Candidate %s doesn't have a reward address", candidate.Address)
 				continue
 			}
-			rewardAddr, err := address.FromString(candidates[i].RewardAddress)
+			rewardAddr, err := address.FromString(candidate.RewardAddress)
 			if err != nil {
 				return nil, err
 			}
@@ -247,7 +243,7 @@ func (p *Protocol) GrantEpochReward(
 			}
 			rewardLog := rewardingpb.RewardLog{
 				Type:   rewardingpb.RewardLog_FOUNDATION_BONUS,
-				Addr:   candidates[i].RewardAddress,
+				Addr:   candidate.RewardAddress,
 				Amount: a.foundationBonus.String(),
 			}
 			data, err := proto.Marshal(&rewardLog)
@@ -265,6 +261,14 @@ func (p *Protocol) GrantEpochReward(
 		}
 	}
 
+	if hu.IsPost(config.Kamchatka, blkCtx.BlockHeight) {
+		kickoutLogs, err := p.kickoutEvents(ctx, sm, pp)
+		if err != nil {
+			return nil, err
+		}
+		rewardLogs = append(rewardLogs, kickoutLogs...)
+	}
+
 	// Update actual reward
 	if err := p.updateAvailableBalance(ctx, sm, actualTotalReward); err != nil {
 		return nil, err
@@ -300,6 +304,63 @@ func (p *Protocol) Claim(
 	}, nil
 }
 
+// claimEvents returns the system event of the claim since Kamchatka height
+func (p *Protocol) claimEvents(ctx context.Context, tLog *action.TransactionLog) ([]*action.Log, error) {
+	blkCtx := protocol.MustGetBlockCtx(ctx)
+	bcCtx := protocol.MustGetBlockchainCtx(ctx)
+	hu := config.NewHeightUpgrade(&bcCtx.Genesis)
+	if hu.IsPre(config.Kamchatka, blkCtx.BlockHeight) {
+		return nil, nil
+	}
+	claimer, err := address.FromString(tLog.Recipient)
+	if err != nil {
+		return nil, err
+	}
+	event, err := protocol.NewSystemEventLog(ctx, p.addr.String(), protocol.RewardClaimedEvent, &iotextypes.TransactionLog_Transaction{
+		Type:      tLog.Type,
+		Sender:    tLog.Sender,
+		Recipient: tLog.Recipient,
+		Amount:    tLog.Amount.String(),
+	}, claimer.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	return []*action.Log{event}, nil
+}
+
+// kickoutEvents returns the system events of the delegates on probation in the epoch, in the order of the addresses
+func (p *Protocol) kickoutEvents(ctx context.Context, sm protocol.StateManager, pp poll.Protocol) ([]*action.Log, error) {
+	pr, ok := pp.(poll.ProbationReader)
+	if !ok {
+		return nil, nil
+	}
+	probationList, err := pr.ProbationList(ctx, sm)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read probation list")
+	}
+	addrs := make([]string, 0, len(probationList.ProbationInfo))
+	for addr := range probationList.ProbationInfo {
+		addrs = append(addrs, addr)
+	}
+	sort.Strings(addrs)
+	logs := make([]*action.Log, 0, len(addrs))
+	for _, addr := range addrs {
+		delegate, err := address.FromString(addr)
+		if err != nil {
+			return nil, err
+		}
+		event, err := protocol.NewSystemEventLog(ctx, p.addr.String(), protocol.DelegateKickedEvent, &iotextypes.ProbationCandidateList_Info{
+			Address: addr,
+			Count:   probationList.ProbationInfo[addr],
+		}, delegate.Bytes())
+		if err != nil {
+			return nil, err
+		}
+		logs = append(logs, event)
+	}
+	return logs, nil
+}
+
 // UnclaimedBalance returns unclaimed balance of a given address
 func (p *Protocol) UnclaimedBalance(
 	ctx context.Context,
@@ -318,7 +379,19 @@ func (p *Protocol) UnclaimedBalance(
 	return nil, height, err
 }
 
+// assertNoDeferredDeposits makes sure that the deposits of the action have been credited into the fund before it is
+// withdrawn from, otherwise the action has to be run again without deferring the deposits
+func assertNoDeferredDeposits(ctx context.Context) error {
+	if dd, ok := protocol.GetDeferredDeposits(ctx); ok && dd.Pending() {
+		return errors.New("cannot withdraw from the fund with deposits deferred")
+	}
+	return nil
+}
+
 func (p *Protocol) updateTotalBalance(ctx context.Context, sm protocol.StateManager, amount *big.Int) error {
+	if err := assertNoDeferredDeposits(ctx); err != nil {
+		return err
+	}
 	f := fund{}
 	if _, err := p.state(ctx, sm, fundKey, &f); err != nil {
 		return err
@@ -332,6 +405,9 @@ func (p *Protocol) updateTotalBalance(ctx context.Context, sm protocol.StateMana
 }
 
 func (p *Protocol) updateAvailableBalance(ctx context.Context, sm protocol.StateManager, amount *big.Int) error {
+	if err := assertNoDeferredDeposits(ctx); err != nil {
+		return err
+	}
 	f := fund{}
 	if _, err := p.state(ctx, sm, fundKey, &f); err != nil {
 		return err
@@ -464,6 +540,33 @@ func (p *Protocol) splitEpochReward(
 	return rewardAddrs, amounts, nil
 }
 
+// grantFoundationBonus returns true if the foundation bonus is granted in the epoch
+func (p *Protocol) grantFoundationBonus(epochNum uint64, a *admin) bool {
+	return epochNum <= a.foundationBonusLastEpoch ||
+		(epochNum >= p.foundationBonusP2StartEpoch && epochNum <= p.foundationBonusP2EndEpoch)
+}
+
+// foundationBonusCandidates returns the top candidates not exempted and not on hard probation, which are granted the
+// foundation bonus
+func foundationBonusCandidates(
+	candidates []*state.Candidate,
+	exemptAddrs map[string]interface{},
+	numDelegates uint64,
+) []*state.Candidate {
+	var eligible []*state.Candidate
+	for i := 0; i < len(candidates) && uint64(len(eligible)) < numDelegates; i++ {
+		if _, ok := exemptAddrs[candidates[i].Address]; ok {
+			continue
+		}
+		if candidates[i].Votes.Cmp(big.NewInt(0)) == 0 {
+			// hard probation
+			continue
+		}
+		eligible = append(eligible, candidates[i])
+	}
+	return eligible
+}
+
 func (p *Protocol) assertNoRewardYet(ctx context.Context, sm protocol.StateManager, prefix []byte, index uint64) error {
 	history := rewardHistory{}
 	var indexBytes [8]byte
@@ -17,7 +17,8 @@ import (
 	"github.com/iotexproject/iotex-core/pkg/log"
 )
 
-const protocolID = "rolldpos"
+// ProtocolID is the protocol ID
+const ProtocolID = "rolldpos"
 
 // Protocol defines an epoch protocol
 type Protocol struct {
@@ -34,7 +35,7 @@ func FindProtocol(registry *protocol.Registry) *Protocol {
 	if registry == nil {
 		return nil
 	}
-	p, ok := registry.Find(protocolID)
+	p, ok := registry.Find(ProtocolID)
 	if !ok {
 		return nil
 	}
@@ -50,7 +51,7 @@ func MustGetProtocol(registry *protocol.Registry) *Protocol {
 	if registry == nil {
 		log.S().Panic("registry cannot be nil")
 	}
-	p, ok := registry.Find(protocolID)
+	p, ok := registry.Find(ProtocolID)
 	if !ok {
 		log.S().Panic("rolldpos protocol is not registered")
 	}
@@ -110,72 +111,72 @@ func (p *Protocol) ReadState(ctx context.Context, sr protocol.StateReader, metho
 		return []byte(strconv.FormatUint(p.numDelegates, 10)), tipHeight, nil
 	case "NumSubEpochs":
 		if len(args) != 1 {
-			return nil, uint64(0), errors.Errorf("invalid number of arguments %d", len(args))
+			return nil, uint64(0), errors.Wrapf(protocol.ErrInvalidArgument, "invalid number of arguments %d", len(args))
 		}
 		height, err := strconv.ParseUint(string(args[0]), 10, 64)
 		if err != nil {
-			return nil, uint64(0), err
+			return nil, uint64(0), errors.Wrap(protocol.ErrInvalidArgument, err.Error())
 		}
 		numSubEpochs := p.NumSubEpochs(height)
 		return []byte(strconv.FormatUint(numSubEpochs, 10)), tipHeight, nil
 	case "EpochNumber":
 		if len(args) != 1 {
-			return nil, uint64(0), errors.Errorf("invalid number of arguments %d", len(args))
+			return nil, uint64(0), errors.Wrapf(protocol.ErrInvalidArgument, "invalid number of arguments %d", len(args))
 		}
 		height, err := strconv.ParseUint(string(args[0]), 10, 64)
 		if err != nil {
-			return nil, uint64(0), err
+			return nil, uint64(0), errors.Wrap(protocol.ErrInvalidArgument, err.Error())
 		}
 		epochNumber := p.GetEpochNum(height)
 		return []byte(strconv.FormatUint(epochNumber, 10)), tipHeight, nil
 	case "EpochHeight":
 		if len(args) != 1 {
-			return nil, uint64(0), errors.Errorf("invalid number of arguments %d", len(args))
+			return nil, uint64(0), errors.Wrapf(protocol.ErrInvalidArgument, "invalid number of arguments %d", len(args))
 		}
 		epochNumber, err := strconv.ParseUint(string(args[0]), 10, 64)
 		if err != nil {
-			return nil, uint64(0), err
+			return nil, uint64(0), errors.Wrap(protocol.ErrInvalidArgument, err.Error())
 		}
 		epochHeight := p.GetEpochHeight(epochNumber)
 		return []byte(strconv.FormatUint(epochHeight, 10)), tipHeight, nil
 	case "EpochLastHeight":
 		if len(args) != 1 {
-			return nil, uint64(0), errors.Errorf("invalid number of arguments %d", len(args))
+			return nil, uint64(0), errors.Wrapf(protocol.ErrInvalidArgument, "invalid number of arguments %d", len(args))
 		}
 		epochNumber, err := strconv.ParseUint(string(args[0]), 10, 64)
 		if err != nil {
-			return nil, uint64(0), err
+			return nil, uint64(0), errors.Wrap(protocol.ErrInvalidArgument, err.Error())
 		}
 		epochLastHeight := p.GetEpochLastBlockHeight(epochNumber)
 		return []byte(strconv.FormatUint(epochLastHeight, 10)), tipHeight, nil
 	case "SubEpochNumber":
 		if len(args) != 1 {
-			return nil, uint64(0), errors.Errorf("invalid number of arguments %d", len(args))
+			return nil, uint64(0), errors.Wrapf(protocol.ErrInvalidArgument, "invalid number of arguments %d", len(args))
 		}
 		height, err := strconv.ParseUint(string(args[0]), 10, 64)
 		if err != nil {
-			return nil, uint64(0), err
+			return nil, uint64(0), errors.Wrap(protocol.ErrInvalidArgument, err.Error())
 		}
 		subEpochNumber := p.GetSubEpochNum(height)
 		return []byte(strconv.FormatUint(subEpochNumber, 10)), tipHeight, nil
 	default:
-		return nil, tipHeight, errors.New("corresponding method isn't found")
+		return nil, tipHeight, errors.Wrapf(protocol.ErrNotFound, "corresponding method %s isn't found", method)
 	}
 }
 
 // Register registers the protocol with a unique ID
 func (p *Protocol) Register(r *protocol.Registry) error {
-	return r.Register(protocolID, p)
+	return r.Register(ProtocolID, p)
 }
 
 // ForceRegister registers the protocol with a unique ID and force replacing the previous protocol if it exists
 func (p *Protocol) ForceRegister(r *protocol.Registry) error {
-	return r.ForceRegister(protocolID, p)
+	return r.ForceRegister(ProtocolID, p)
 }
 
 // Name returns the name of protocol
 func (p *Protocol) Name() string {
-	return protocolID
+	return ProtocolID
 }
 
 // NumCandidateDelegates returns the number of delegate candidates for an epoch
@@ -0,0 +1,345 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package sanction
+
+import (
+	"context"
+	"math/big"
+	"strconv"
+
+	"github.com/iotexproject/go-pkgs/hash"
+	"github.com/iotexproject/iotex-address/address"
+	"github.com/iotexproject/iotex-proto/golang/iotextypes"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/iotexproject/iotex-core/action"
+	"github.com/iotexproject/iotex-core/action/protocol"
+	accountutil "github.com/iotexproject/iotex-core/action/protocol/account/util"
+	"github.com/iotexproject/iotex-core/action/protocol/batch"
+	"github.com/iotexproject/iotex-core/pkg/log"
+	"github.com/iotexproject/iotex-core/state"
+)
+
+const (
+	// TODO: it works only for one instance per protocol definition now
+	protocolID = "sanction"
+	// Namespace is the namespace to store the sanction list and the pending changes of it
+	Namespace = "Sanction"
+)
+
+// prefixes of the keys of the history and the pending proposals of an address
+const (
+	_historyPrefix byte = iota
+	_proposalPrefix
+)
+
+var (
+	// SanctionAddedTopic is the first topic of the receipt log of adding an address to the sanction list, followed by
+	// the address
+	SanctionAddedTopic = hash.Hash256b([]byte("Sanction.Added"))
+	// SanctionRemovedTopic is the first topic of the receipt log of removing an address from the sanction list,
+	// followed by the address
+	SanctionRemovedTopic = hash.Hash256b([]byte("Sanction.Removed"))
+)
+
+type (
+	// Protocol is the sanction list managed by the governors in genesis. A change of the list is applied once enough
+	// governors approve it, and is kept in the history of the address. The addresses in the list are rejected as the
+	// senders and the recipients of the actions. The operations are the executions to the protocol address
+	Protocol struct {
+		addr       address.Address
+		depositGas protocol.DepositGas
+	}
+
+	// Validator rejects the actions sent from or to the sanctioned addresses before they enter the actpool
+	Validator struct {
+		sr protocol.StateReader
+	}
+)
+
+// NewProtocol instantiates the protocol of sanction list
+func NewProtocol(depositGas protocol.DepositGas) *Protocol {
+	h := hash.Hash160b([]byte(protocolID))
+	addr, err := address.FromBytes(h[:])
+	if err != nil {
+		log.L().Panic("Error when constructing the address of sanction protocol", zap.Error(err))
+	}
+	return &Protocol{
+		addr:       addr,
+		depositGas: depositGas,
+	}
+}
+
+// FindProtocol finds the registered protocol from registry
+func FindProtocol(registry *protocol.Registry) *Protocol {
+	if registry == nil {
+		return nil
+	}
+	p, ok := registry.Find(protocolID)
+	if !ok {
+		return nil
+	}
+	pp, ok := p.(*Protocol)
+	if !ok {
+		log.S().Panic("fail to cast sanction protocol")
+	}
+	return pp
+}
+
+// Address returns the address of the protocol, which is the contract of the executions operating the sanction list
+func (p *Protocol) Address() address.Address {
+	return p.addr
+}
+
+// Handle handles the operations of the sanction list
+func (p *Protocol) Handle(ctx context.Context, act action.Action, sm protocol.StateManager) (*action.Receipt, error) {
+	exec, ok := act.(*action.Execution)
+	if !ok || exec.Contract() != p.addr.String() || !accountutil.IsSystemExecutionActive(ctx) {
+		return nil, nil
+	}
+	si := sm.Snapshot()
+	l, err := p.handleOperation(ctx, exec, sm)
+	if err != nil {
+		log.L().Debug("Error when handling sanction operation", zap.Error(err))
+		return accountutil.SettleSystemExecution(ctx, sm, p.depositGas, p.addr, uint64(iotextypes.ReceiptStatus_Failure), si, nil)
+	}
+	return accountutil.SettleSystemExecution(ctx, sm, p.depositGas, p.addr, uint64(iotextypes.ReceiptStatus_Success), si, []*action.Log{l})
+}
+
+// Validate rejects the action if its sender or recipient is in the sanction list
+func (p *Protocol) Validate(ctx context.Context, act action.Action, sr protocol.StateReader) error {
+	bcCtx, ok := protocol.GetBlockchainCtx(ctx)
+	if !ok || len(bcCtx.Genesis.SanctionGovernors) == 0 {
+		return nil
+	}
+	actionCtx, ok := protocol.GetActionCtx(ctx)
+	if !ok {
+		return errors.New("failed to get action context to validate sanction list")
+	}
+	return validateAction(sr, actionCtx.Caller, act)
+}
+
+// ReadState reads the history of the address in the sanction list, or whether it is sanctioned now
+func (p *Protocol) ReadState(
+	ctx context.Context,
+	sr protocol.StateReader,
+	method []byte,
+	args ...[]byte,
+) ([]byte, uint64, error) {
+	if len(args) != 1 {
+		return nil, uint64(0), errors.Wrapf(protocol.ErrInvalidArgument, "invalid number of arguments %d", len(args))
+	}
+	addr, err := address.FromString(string(args[0]))
+	if err != nil {
+		return nil, uint64(0), errors.Wrap(protocol.ErrInvalidArgument, err.Error())
+	}
+	h := &History{}
+	height, err := sr.State(h, protocol.NamespaceOption(Namespace), protocol.KeyOption(historyKey(addr)))
+	switch method := string(method); method {
+	case "History":
+		if err != nil {
+			return nil, height, err
+		}
+		data, err := h.Serialize()
+		return data, height, err
+	case "Sanctioned":
+		if err != nil && errors.Cause(err) != state.ErrStateNotExist {
+			return nil, height, err
+		}
+		return []byte(strconv.FormatBool(h.Sanctioned())), height, nil
+	default:
+		return nil, uint64(0), errors.Wrapf(protocol.ErrNotFound, "unknown method %s", method)
+	}
+}
+
+// Register registers the protocol with a unique ID
+func (p *Protocol) Register(r *protocol.Registry) error {
+	return r.Register(protocolID, p)
+}
+
+// ForceRegister registers the protocol with a unique ID and force replacing the previous protocol if it exists
+func (p *Protocol) ForceRegister(r *protocol.Registry) error {
+	return r.ForceRegister(protocolID, p)
+}
+
+// Name returns the name of protocol
+func (p *Protocol) Name() string {
+	return protocolID
+}
+
+// NewValidator constructs a new Validator
+func NewValidator(sr protocol.StateReader) *Validator {
+	return &Validator{sr: sr}
+}
+
+// Validate validates the sender and the recipient of the action against the sanction list
+func (v *Validator) Validate(_ context.Context, selp action.SealedEnvelope) error {
+	caller, err := address.FromBytes(selp.SrcPubkey().Hash())
+	if err != nil {
+		return err
+	}
+	return validateAction(v.sr, caller, selp.Action())
+}
+
+func (p *Protocol) handleOperation(
+	ctx context.Context,
+	exec *action.Execution,
+	sm protocol.StateManager,
+) (*action.Log, error) {
+	actionCtx := protocol.MustGetActionCtx(ctx)
+	blkCtx := protocol.MustGetBlockCtx(ctx)
+	bcCtx := protocol.MustGetBlockchainCtx(ctx)
+	op := &Operation{}
+	if err := op.Deserialize(exec.Data()); err != nil {
+		return nil, err
+	}
+	if exec.Amount() != nil && exec.Amount().Sign() != 0 {
+		return nil, errors.Wrapf(ErrInvalidOperation, "operation %d does not accept amount", op.Op)
+	}
+	caller, err := accountutil.LoadAccount(sm, hash.BytesToHash160(actionCtx.Caller.Bytes()))
+	if err != nil {
+		return nil, err
+	}
+	gasFee := new(big.Int).Mul(actionCtx.GasPrice, new(big.Int).SetUint64(actionCtx.IntrinsicGas))
+	if gasFee.Cmp(caller.Balance) > 0 {
+		return nil, errors.Wrapf(state.ErrNotEnoughBalance, "caller %s balance not enough", actionCtx.Caller.String())
+	}
+	if !isGovernor(bcCtx.Genesis.SanctionGovernors, actionCtx.Caller) {
+		return nil, errors.Wrap(ErrNotGovernor, actionCtx.Caller.String())
+	}
+
+	h := &History{}
+	if err := p.get(sm, historyKey(op.Address), h); err != nil && errors.Cause(err) != state.ErrStateNotExist {
+		return nil, err
+	}
+	if h.Sanctioned() == (op.Op == OpAdd) {
+		return nil, errors.Wrapf(ErrInvalidOperation, "operation %d does not change the status of %s", op.Op, op.Address.String())
+	}
+	pKey := proposalKey(op.Op, op.Address)
+	proposal := &Proposal{}
+	if err := p.get(sm, pKey, proposal); err != nil && errors.Cause(err) != state.ErrStateNotExist {
+		return nil, err
+	}
+	if proposal.Approved(actionCtx.Caller) {
+		return nil, errors.Wrapf(ErrInvalidOperation, "%s already approved", actionCtx.Caller.String())
+	}
+	proposal.Approvals = append(proposal.Approvals, actionCtx.Caller)
+	if uint64(len(proposal.Approvals)) < bcCtx.Genesis.SanctionApprovals {
+		return nil, p.put(sm, pKey, proposal)
+	}
+
+	// the change is approved, apply it to the sanction list
+	if err := p.del(sm, pKey); err != nil {
+		return nil, err
+	}
+	h.Changes = append(h.Changes, &Change{
+		Op:         op.Op,
+		Height:     blkCtx.BlockHeight,
+		ActionHash: actionCtx.ActionHash,
+		Approvals:  proposal.Approvals,
+	})
+	if err := p.put(sm, historyKey(op.Address), h); err != nil {
+		return nil, err
+	}
+	topic := SanctionAddedTopic
+	if op.Op == OpRemove {
+		topic = SanctionRemovedTopic
+	}
+	return &action.Log{
+		Address:     p.addr.String(),
+		Topics:      action.Topics{topic, hash.BytesToHash256(op.Address.Bytes())},
+		BlockHeight: blkCtx.BlockHeight,
+		ActionHash:  actionCtx.ActionHash,
+	}, nil
+}
+
+func (p *Protocol) get(sr protocol.StateReader, key []byte, s state.Deserializer) error {
+	_, err := sr.State(s, protocol.NamespaceOption(Namespace), protocol.KeyOption(key))
+	return err
+}
+
+func (p *Protocol) put(sm protocol.StateManager, key []byte, s state.Serializer) error {
+	_, err := sm.PutState(s, protocol.NamespaceOption(Namespace), protocol.KeyOption(key))
+	return err
+}
+
+func (p *Protocol) del(sm protocol.StateManager, key []byte) error {
+	_, err := sm.DelState(protocol.NamespaceOption(Namespace), protocol.KeyOption(key))
+	if errors.Cause(err) == state.ErrStateNotExist {
+		return nil
+	}
+	return err
+}
+
+// validateAction returns action.ErrAddress if the sender or the recipient of the action is sanctioned
+func validateAction(sr protocol.StateReader, caller address.Address, act action.Action) error {
+	addrs := []address.Address{caller}
+	if recipient := recipientOf(act); recipient != nil {
+		addrs = append(addrs, recipient)
+	}
+	// the recipients of the sub-actions in a batch, which are sent by the caller as well
+	if e, ok := batch.EnvelopeOf(act); ok {
+		for _, elp := range e.Actions {
+			if recipient := recipientOf(elp.Action()); recipient != nil {
+				addrs = append(addrs, recipient)
+			}
+		}
+	}
+	for _, addr := range addrs {
+		h := &History{}
+		_, err := sr.State(h, protocol.NamespaceOption(Namespace), protocol.KeyOption(historyKey(addr)))
+		switch errors.Cause(err) {
+		case nil:
+		case state.ErrStateNotExist:
+			continue
+		default:
+			return errors.Wrapf(err, "failed to get sanction history of %s", addr.String())
+		}
+		if h.Sanctioned() {
+			return errors.Wrapf(action.ErrAddress, "%s is sanctioned", addr.String())
+		}
+	}
+	return nil
+}
+
+// recipientOf returns the recipient of the action, nil if the action has no recipient
+func recipientOf(act action.Action) address.Address {
+	var recipient string
+	switch act := act.(type) {
+	case *action.Transfer:
+		recipient = act.Recipient()
+	case *action.Execution:
+		recipient = act.Contract()
+	case *action.TransferStake:
+		return act.VoterAddress()
+	}
+	addr, err := address.FromString(recipient)
+	if err != nil {
+		return nil
+	}
+	return addr
+}
+
+func isGovernor(governors []string, addr address.Address) bool {
+	for _, g := range governors {
+		if g == addr.String() {
+			return true
+		}
+	}
+	return false
+}
+
+// historyKey returns the key of the history of the address
+func historyKey(addr address.Address) []byte {
+	return append([]byte{_historyPrefix}, addr.Bytes()...)
+}
+
+// proposalKey returns the key of the pending proposal of the operation on the address
+func proposalKey(op byte, addr address.Address) []byte {
+	return append([]byte{_proposalPrefix, op}, addr.Bytes()...)
+}
@@ -0,0 +1,191 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package sanction
+
+import (
+	"github.com/iotexproject/go-pkgs/hash"
+	"github.com/iotexproject/iotex-address/address"
+	"github.com/pkg/errors"
+
+	"github.com/iotexproject/iotex-core/pkg/util/byteutil"
+)
+
+const (
+	_addressLength = 20
+	_heightLength  = 8
+	_hashLength    = 32
+	_opLength      = 1
+	// _changeLength is the length of a change without the approvals
+	_changeLength = _opLength + _heightLength + _hashLength + _heightLength
+)
+
+// operations of the sanction list, the first byte of the data of the execution to the protocol address
+const (
+	// OpAdd approves adding the address to the sanction list by a governor. Data is op || address (20 bytes)
+	OpAdd byte = iota + 1
+	// OpRemove approves removing the address from the sanction list by a governor. Data is op || address (20 bytes)
+	OpRemove
+)
+
+var (
+	// ErrInvalidOperation indicates the data of the execution is not a valid operation
+	ErrInvalidOperation = errors.New("invalid sanction operation")
+	// ErrNotGovernor indicates the caller is not a governor of the sanction list
+	ErrNotGovernor = errors.New("caller is not a sanction governor")
+)
+
+type (
+	// Change is an applied change of the sanction list
+	Change struct {
+		Op         byte
+		Height     uint64
+		ActionHash hash.Hash256
+		// Approvals are the governors approving the change
+		Approvals []address.Address
+	}
+
+	// History is the changes of the sanction list of an address, in the order they are applied
+	History struct {
+		Changes []*Change
+	}
+
+	// Proposal is a pending change of the sanction list, applied once enough governors approve it
+	Proposal struct {
+		Approvals []address.Address
+	}
+
+	// Operation is an operation of the sanction list
+	Operation struct {
+		Op      byte
+		Address address.Address
+	}
+)
+
+// Sanctioned returns true if the last change of the history adds the address to the sanction list
+func (h *History) Sanctioned() bool {
+	return len(h.Changes) > 0 && h.Changes[len(h.Changes)-1].Op == OpAdd
+}
+
+// Serialize serializes the history
+func (h *History) Serialize() ([]byte, error) {
+	var data []byte
+	for _, c := range h.Changes {
+		data = append(data, c.Op)
+		data = append(data, byteutil.Uint64ToBytesBigEndian(c.Height)...)
+		data = append(data, c.ActionHash[:]...)
+		data = append(data, byteutil.Uint64ToBytesBigEndian(uint64(len(c.Approvals)))...)
+		data = append(data, addressesBytes(c.Approvals)...)
+	}
+	return data, nil
+}
+
+// Deserialize deserializes the history
+func (h *History) Deserialize(data []byte) error {
+	var changes []*Change
+	for len(data) > 0 {
+		if len(data) < _changeLength {
+			return errors.Errorf("invalid change length %d", len(data))
+		}
+		c := &Change{
+			Op:         data[0],
+			Height:     byteutil.BytesToUint64BigEndian(data[_opLength : _opLength+_heightLength]),
+			ActionHash: hash.BytesToHash256(data[_opLength+_heightLength : _opLength+_heightLength+_hashLength]),
+		}
+		n := byteutil.BytesToUint64BigEndian(data[_opLength+_heightLength+_hashLength : _changeLength])
+		data = data[_changeLength:]
+		if n > uint64(len(data)/_addressLength) {
+			return errors.Errorf("invalid number of approvals %d", n)
+		}
+		approvals, err := bytesAddresses(data[:n*_addressLength])
+		if err != nil {
+			return err
+		}
+		c.Approvals = approvals
+		changes = append(changes, c)
+		data = data[n*_addressLength:]
+	}
+	h.Changes = changes
+	return nil
+}
+
+// Approved returns true if the proposal is approved by the governor
+func (p *Proposal) Approved(addr address.Address) bool {
+	return contains(p.Approvals, addr)
+}
+
+// Serialize serializes the proposal
+func (p *Proposal) Serialize() ([]byte, error) {
+	return addressesBytes(p.Approvals), nil
+}
+
+// Deserialize deserializes the proposal
+func (p *Proposal) Deserialize(data []byte) error {
+	approvals, err := bytesAddresses(data)
+	if err != nil {
+		return err
+	}
+	p.Approvals = approvals
+	return nil
+}
+
+// Serialize serializes the operation into the data of the execution to the protocol address
+func (o *Operation) Serialize() []byte {
+	return append([]byte{o.Op}, o.Address.Bytes()...)
+}
+
+// Deserialize deserializes the operation from the data of the execution to the protocol address
+func (o *Operation) Deserialize(data []byte) error {
+	if len(data) < _opLength {
+		return errors.Wrap(ErrInvalidOperation, "empty data")
+	}
+	*o = Operation{Op: data[0]}
+	if o.Op != OpAdd && o.Op != OpRemove {
+		return errors.Wrapf(ErrInvalidOperation, "unknown operation %d", o.Op)
+	}
+	data = data[_opLength:]
+	if len(data) != _addressLength {
+		return errors.Wrapf(ErrInvalidOperation, "invalid data length %d of operation %d", len(data), o.Op)
+	}
+	addr, err := address.FromBytes(data)
+	if err != nil {
+		return errors.Wrap(ErrInvalidOperation, err.Error())
+	}
+	o.Address = addr
+	return nil
+}
+
+func contains(addrs []address.Address, addr address.Address) bool {
+	for _, a := range addrs {
+		if address.Equal(a, addr) {
+			return true
+		}
+	}
+	return false
+}
+
+func addressesBytes(addrs []address.Address) []byte {
+	data := make([]byte, 0, len(addrs)*_addressLength)
+	for _, addr := range addrs {
+		data = append(data, addr.Bytes()...)
+	}
+	return data
+}
+
+func bytesAddresses(data []byte) ([]address.Address, error) {
+	if len(data)%_addressLength != 0 {
+		return nil, errors.Errorf("invalid addresses length %d", len(data))
+	}
+	var addrs []address.Address
+	for ; len(data) > 0; data = data[_addressLength:] {
+		addr, err := address.FromBytes(data[:_addressLength])
+		if err != nil {
+			return nil, err
+		}
+		addrs = append(addrs, addr)
+	}
+	return addrs, nil
+}
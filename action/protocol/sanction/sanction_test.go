@@ -0,0 +1,161 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package sanction
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/iotexproject/go-pkgs/hash"
+	"github.com/iotexproject/iotex-address/address"
+	"github.com/iotexproject/iotex-proto/golang/iotextypes"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/action"
+	"github.com/iotexproject/iotex-core/action/protocol"
+	"github.com/iotexproject/iotex-core/action/protocol/batch"
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/test/identityset"
+	"github.com/iotexproject/iotex-core/testutil/testdb"
+)
+
+func TestHistoryAndProposal(t *testing.T) {
+	require := require.New(t)
+
+	h := &History{
+		Changes: []*Change{
+			{
+				Op:         OpAdd,
+				Height:     100,
+				ActionHash: hash.Hash256b([]byte("add")),
+				Approvals:  []address.Address{identityset.Address(1), identityset.Address(2)},
+			},
+			{
+				Op:         OpRemove,
+				Height:     200,
+				ActionHash: hash.Hash256b([]byte("remove")),
+				Approvals:  []address.Address{identityset.Address(3)},
+			},
+		},
+	}
+	require.False(h.Sanctioned())
+	data, err := h.Serialize()
+	require.NoError(err)
+	h1 := &History{}
+	require.NoError(h1.Deserialize(data))
+	require.Equal(2, len(h1.Changes))
+	require.Equal(h.Changes[0].ActionHash, h1.Changes[0].ActionHash)
+	require.Equal(h.Changes[1].Height, h1.Changes[1].Height)
+	require.Equal(2, len(h1.Changes[0].Approvals))
+	require.Equal(identityset.Address(3).String(), h1.Changes[1].Approvals[0].String())
+	require.Error(h1.Deserialize(data[:len(data)-1]))
+	h1.Changes = h1.Changes[:1]
+	require.True(h1.Sanctioned())
+
+	p := &Proposal{Approvals: []address.Address{identityset.Address(1)}}
+	data, err = p.Serialize()
+	require.NoError(err)
+	p1 := &Proposal{}
+	require.NoError(p1.Deserialize(data))
+	require.True(p1.Approved(identityset.Address(1)))
+	require.False(p1.Approved(identityset.Address(2)))
+
+	op := &Operation{Op: OpRemove, Address: identityset.Address(1)}
+	op1 := &Operation{}
+	require.NoError(op1.Deserialize(op.Serialize()))
+	require.Equal(op.Serialize(), op1.Serialize())
+	for _, data := range [][]byte{
+		nil,
+		{0},
+		append([]byte{OpAdd}, identityset.Address(1).Bytes()[1:]...),
+	} {
+		require.Equal(ErrInvalidOperation, errors.Cause((&Operation{}).Deserialize(data)))
+	}
+}
+
+func TestProtocol(t *testing.T) {
+	require := require.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	sm := testdb.NewMockStateManager(ctrl)
+	p := NewProtocol(func(context.Context, protocol.StateManager, *big.Int) (*action.TransactionLog, error) {
+		return nil, nil
+	})
+	g := config.Default.Genesis
+	g.SanctionGovernors = []string{identityset.Address(1).String(), identityset.Address(2).String()}
+	g.SanctionApprovals = 2
+	ctx := protocol.WithBlockchainCtx(context.Background(), protocol.BlockchainCtx{Genesis: g})
+	ctx = protocol.WithBlockCtx(ctx, protocol.BlockCtx{BlockHeight: g.KamchatkaBlockHeight})
+
+	target := identityset.Address(10)
+	operate := func(caller int, op byte) *action.Receipt {
+		exec, err := action.NewExecution(p.Address().String(), 1, big.NewInt(0), 100000, big.NewInt(0), (&Operation{Op: op, Address: target}).Serialize())
+		require.NoError(err)
+		ctx := protocol.WithActionCtx(ctx, protocol.ActionCtx{Caller: identityset.Address(caller), GasPrice: big.NewInt(0)})
+		r, err := p.Handle(ctx, exec, sm)
+		require.NoError(err)
+		return r
+	}
+	sanctioned := func() string {
+		data, _, err := p.ReadState(ctx, sm, []byte("Sanctioned"), []byte(target.String()))
+		require.NoError(err)
+		return string(data)
+	}
+	tsf, err := action.NewTransfer(1, big.NewInt(1), target.String(), nil, 10000, big.NewInt(0))
+	require.NoError(err)
+	send := protocol.WithActionCtx(ctx, protocol.ActionCtx{Caller: identityset.Address(11)})
+
+	require.False(isGovernor(g.SanctionGovernors, identityset.Address(3)))
+	// a change is applied once both governors approve it
+	require.EqualValues(iotextypes.ReceiptStatus_Success, operate(1, OpAdd).Status)
+	require.Equal("false", sanctioned())
+	require.NoError(p.Validate(send, tsf, sm))
+	r := operate(2, OpAdd)
+	require.EqualValues(iotextypes.ReceiptStatus_Success, r.Status)
+	require.Equal(SanctionAddedTopic, r.Logs()[0].Topics[0])
+	require.Equal("true", sanctioned())
+
+	// the sanctioned address is rejected as the recipient and the sender
+	require.Equal(action.ErrAddress, errors.Cause(p.Validate(send, tsf, sm)))
+	selp := action.FakeSeal((&action.EnvelopeBuilder{}).SetAction(tsf).SetGasPrice(big.NewInt(0)).Build(), identityset.PrivateKey(11).PublicKey())
+	require.Equal(action.ErrAddress, errors.Cause(NewValidator(sm).Validate(ctx, selp)))
+	// and as the recipient of a sub-action in a batch
+	e := &batch.Envelope{
+		Actions: []action.Envelope{
+			(&action.EnvelopeBuilder{}).SetNonce(1).SetGasLimit(10000).SetGasPrice(big.NewInt(0)).SetAction(tsf).Build(),
+		},
+	}
+	data, err := e.Serialize()
+	require.NoError(err)
+	batchExec, err := action.NewExecution(batch.NewProtocol(nil).Address().String(), 1, big.NewInt(0), 100000, big.NewInt(0), data)
+	require.NoError(err)
+	require.Equal(action.ErrAddress, errors.Cause(p.Validate(send, batchExec, sm)))
+	selp = action.FakeSeal((&action.EnvelopeBuilder{}).SetAction(batchExec).SetGasPrice(big.NewInt(0)).Build(), identityset.PrivateKey(11).PublicKey())
+	require.Equal(action.ErrAddress, errors.Cause(NewValidator(sm).Validate(ctx, selp)))
+	tsf1, err := action.NewTransfer(1, big.NewInt(1), identityset.Address(11).String(), nil, 10000, big.NewInt(0))
+	require.NoError(err)
+	require.Equal(action.ErrAddress, errors.Cause(p.Validate(protocol.WithActionCtx(ctx, protocol.ActionCtx{Caller: target}), tsf1, sm)))
+
+	require.EqualValues(iotextypes.ReceiptStatus_Success, operate(2, OpRemove).Status)
+	require.EqualValues(iotextypes.ReceiptStatus_Success, operate(1, OpRemove).Status)
+	require.Equal("false", sanctioned())
+	require.NoError(p.Validate(send, tsf, sm))
+	require.NoError(p.Validate(send, batchExec, sm))
+
+	data, _, err = p.ReadState(ctx, sm, []byte("History"), []byte(target.String()))
+	require.NoError(err)
+	h := &History{}
+	require.NoError(h.Deserialize(data))
+	require.Equal(2, len(h.Changes))
+	require.Equal(OpAdd, h.Changes[0].Op)
+	require.Equal(OpRemove, h.Changes[1].Op)
+	require.Equal(g.KamchatkaBlockHeight, h.Changes[1].Height)
+}
@@ -156,7 +156,7 @@ func (bp *BucketPool) Sync(sm protocol.StateManager) error {
 		return err
 	}
 	// get stashed total amount
-	err := sm.Unload(protocolID, stakingBucketPool, bp.total)
+	err := sm.Unload(ProtocolID, stakingBucketPool, bp.total)
 	if err != nil && err != protocol.ErrNoName {
 		return err
 	}
@@ -178,7 +178,21 @@ func (bp *BucketPool) CreditPool(sm protocol.StateManager, amount *big.Int) erro
 		_, err := sm.PutState(bp.total, protocol.NamespaceOption(StakingNameSpace), protocol.KeyOption(bucketPoolAddrKey))
 		return err
 	}
-	return sm.Load(protocolID, stakingBucketPool, bp.total)
+	return sm.Load(ProtocolID, stakingBucketPool, bp.total)
+}
+
+// SlashPool subtracts the amount slashed from a bucket from the pool, which keeps the number of buckets
+func (bp *BucketPool) SlashPool(sm protocol.StateManager, amount *big.Int) error {
+	if amount.Cmp(bp.total.amount) == 1 {
+		return state.ErrNotEnoughBalance
+	}
+	bp.total.amount.Sub(bp.total.amount, amount)
+
+	if bp.enableSMStorage {
+		_, err := sm.PutState(bp.total, protocol.NamespaceOption(StakingNameSpace), protocol.KeyOption(bucketPoolAddrKey))
+		return err
+	}
+	return sm.Load(ProtocolID, stakingBucketPool, bp.total)
 }
 
 // DebitPool adds staked amount into the pool
@@ -188,5 +202,5 @@ func (bp *BucketPool) DebitPool(sm protocol.StateManager, amount *big.Int, newBu
 		_, err := sm.PutState(bp.total, protocol.NamespaceOption(StakingNameSpace), protocol.KeyOption(bucketPoolAddrKey))
 		return err
 	}
-	return sm.Load(protocolID, stakingBucketPool, bp.total)
+	return sm.Load(ProtocolID, stakingBucketPool, bp.total)
 }
@@ -15,6 +15,7 @@ import (
 	"github.com/iotexproject/iotex-proto/golang/iotextypes"
 
 	"github.com/iotexproject/iotex-core/db"
+	"github.com/iotexproject/iotex-core/db/batch"
 	"github.com/iotexproject/iotex-core/pkg/util/byteutil"
 )
 
@@ -25,12 +26,19 @@ const (
 	StakingBucketsNamespace = "stakingBuckets"
 )
 
-const indexerHeightKey = "latestHeight"
+const (
+	indexerHeightKey = "latestHeight"
+	// snapshotHeightKey stores the first height stored since the candidates and buckets are stored with the start
+	// height of the epoch they are snapshotted at, they were stored with the start height of the previous epoch
+	snapshotHeightKey = "snapshotHeight"
+)
 
-// CandidatesBucketsIndexer is an indexer to store candidates by given height
+// CandidatesBucketsIndexer is an indexer to store the snapshot of candidates and buckets at the start height of each
+// epoch, a snapshot is never overwritten once stored
 type CandidatesBucketsIndexer struct {
 	latestCandidatesHeight uint64
 	latestBucketsHeight    uint64
+	snapshotHeight         uint64
 	kvStore                db.KVStore
 }
 
@@ -68,6 +76,16 @@ func (cbi *CandidatesBucketsIndexer) Start(ctx context.Context) error {
 	default:
 		return err
 	}
+
+	ret, err = cbi.kvStore.Get(StakingCandidatesNamespace, []byte(snapshotHeightKey))
+	switch errors.Cause(err) {
+	case nil:
+		cbi.snapshotHeight = byteutil.BytesToUint64BigEndian(ret)
+	case db.ErrNotExist:
+		cbi.snapshotHeight = 0
+	default:
+		return err
+	}
 	return nil
 }
 
@@ -76,20 +94,23 @@ func (cbi *CandidatesBucketsIndexer) Stop(ctx context.Context) error {
 	return cbi.kvStore.Stop(ctx)
 }
 
-// PutCandidates puts candidates into indexer
+// SnapshotHeight returns the first epoch start height stored with its own snapshot, a snapshot below the height is
+// stored with the start height of the previous epoch
+func (cbi *CandidatesBucketsIndexer) SnapshotHeight() uint64 {
+	return cbi.snapshotHeight
+}
+
+// PutCandidates puts candidates snapshotted at the epoch start height into indexer
 func (cbi *CandidatesBucketsIndexer) PutCandidates(height uint64, candidates *iotextypes.CandidateListV2) error {
 	candidatesBytes, err := proto.Marshal(candidates)
 	if err != nil {
 		return err
 	}
-	heightBytes := byteutil.Uint64ToBytesBigEndian(height)
-	if err := cbi.kvStore.Put(StakingCandidatesNamespace, heightBytes, candidatesBytes); err != nil {
-		return err
-	}
-	if err := cbi.kvStore.Put(StakingCandidatesNamespace, []byte(indexerHeightKey), heightBytes); err != nil {
+	latest, err := cbi.putSnapshot(StakingCandidatesNamespace, height, candidatesBytes, cbi.latestCandidatesHeight)
+	if err != nil {
 		return err
 	}
-	cbi.latestCandidatesHeight = height
+	cbi.latestCandidatesHeight = latest
 	return nil
 }
 
@@ -124,20 +145,17 @@ func (cbi *CandidatesBucketsIndexer) GetCandidates(height uint64, offset, limit
 	return d, height, err
 }
 
-// PutBuckets puts vote buckets into indexer
+// PutBuckets puts vote buckets snapshotted at the epoch start height into indexer
 func (cbi *CandidatesBucketsIndexer) PutBuckets(height uint64, buckets *iotextypes.VoteBucketList) error {
 	bucketsBytes, err := proto.Marshal(buckets)
 	if err != nil {
 		return err
 	}
-	heightBytes := byteutil.Uint64ToBytesBigEndian(height)
-	if err := cbi.kvStore.Put(StakingBucketsNamespace, heightBytes, bucketsBytes); err != nil {
-		return err
-	}
-	if err := cbi.kvStore.Put(StakingBucketsNamespace, []byte(indexerHeightKey), heightBytes); err != nil {
+	latest, err := cbi.putSnapshot(StakingBucketsNamespace, height, bucketsBytes, cbi.latestBucketsHeight)
+	if err != nil {
 		return err
 	}
-	cbi.latestBucketsHeight = height
+	cbi.latestBucketsHeight = latest
 	return nil
 }
 
@@ -171,3 +189,32 @@ func (cbi *CandidatesBucketsIndexer) GetBuckets(height uint64, offset, limit uin
 	d, err := proto.Marshal(buckets)
 	return d, height, err
 }
+
+// putSnapshot puts the snapshot unless one has been stored at the height, and returns the latest height
+func (cbi *CandidatesBucketsIndexer) putSnapshot(ns string, height uint64, data []byte, latest uint64) (uint64, error) {
+	heightBytes := byteutil.Uint64ToBytesBigEndian(height)
+	_, err := cbi.kvStore.Get(ns, heightBytes)
+	switch errors.Cause(err) {
+	case nil:
+		return latest, nil
+	case db.ErrNotExist:
+	default:
+		return latest, err
+	}
+	b := batch.NewBatch()
+	b.Put(ns, heightBytes, data, "failed to put snapshot")
+	if height > latest {
+		b.Put(ns, []byte(indexerHeightKey), heightBytes, "failed to put latest height")
+		latest = height
+	}
+	if cbi.snapshotHeight == 0 {
+		b.Put(StakingCandidatesNamespace, []byte(snapshotHeightKey), heightBytes, "failed to put snapshot height")
+	}
+	if err := cbi.kvStore.WriteBatch(b); err != nil {
+		return latest, err
+	}
+	if cbi.snapshotHeight == 0 {
+		cbi.snapshotHeight = height
+	}
+	return latest, nil
+}
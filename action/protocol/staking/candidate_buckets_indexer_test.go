@@ -4,6 +4,8 @@ import (
 	"context"
 	"testing"
 
+	"github.com/iotexproject/iotex-core/action/protocol/rolldpos"
+	"github.com/iotexproject/iotex-core/blockchain/genesis"
 	"github.com/iotexproject/iotex-core/db"
 	"github.com/iotexproject/iotex-proto/golang/iotextypes"
 	"github.com/stretchr/testify/require"
@@ -142,3 +144,59 @@ func TestCandidatesBucketsIndexer_PutGetBuckets(t *testing.T) {
 	}
 	require.NoError(cbi.Stop(ctx))
 }
+
+func TestCandidatesBucketsIndexer_Snapshot(t *testing.T) {
+	require := require.New(t)
+
+	ctx := context.Background()
+	store := db.NewMemKVStore()
+	cbi, err := NewStakingCandidatesBucketsIndexer(store)
+	require.NoError(err)
+	require.NoError(cbi.Start(ctx))
+	p, err := NewProtocol(nil, genesis.Default.Staking, cbi, genesis.Default.GreenlandBlockHeight)
+	require.NoError(err)
+	rp := rolldpos.NewProtocol(4, 4, 2)
+
+	// before any snapshot stored, the snapshot of an epoch is stored with the start height of the previous epoch
+	require.Zero(cbi.SnapshotHeight())
+	require.EqualValues(1, p.snapshotHeight(rp, 1))
+	require.EqualValues(1, p.snapshotHeight(rp, 2))
+	require.EqualValues(9, p.snapshotHeight(rp, 3))
+
+	snapshot := &iotextypes.CandidateListV2{
+		Candidates: []*iotextypes.CandidateV2{{Name: "abc"}},
+	}
+	require.NoError(cbi.PutCandidates(17, snapshot))
+	require.NoError(cbi.PutBuckets(17, &iotextypes.VoteBucketList{}))
+	require.EqualValues(17, cbi.SnapshotHeight())
+	require.EqualValues(1, p.snapshotHeight(rp, 2))
+	require.EqualValues(17, p.snapshotHeight(rp, 3))
+	require.EqualValues(25, p.snapshotHeight(rp, 4))
+
+	// the snapshot is never overwritten
+	require.NoError(cbi.PutCandidates(17, &iotextypes.CandidateListV2{}))
+	data, height, err := cbi.GetCandidates(17, 0, 10)
+	require.NoError(err)
+	require.EqualValues(17, height)
+	var r iotextypes.CandidateListV2
+	require.NoError(proto.Unmarshal(data, &r))
+	require.Equal(1, len(r.Candidates))
+	require.Equal("abc", r.Candidates[0].Name)
+
+	// a lower height does not move back the latest height
+	require.NoError(cbi.PutCandidates(9, &iotextypes.CandidateListV2{}))
+	_, height, err = cbi.GetCandidates(25, 0, 10)
+	require.NoError(err)
+	require.EqualValues(17, height)
+	require.NoError(cbi.Stop(ctx))
+
+	// reload from the store
+	cbi, err = NewStakingCandidatesBucketsIndexer(store)
+	require.NoError(err)
+	require.NoError(cbi.Start(ctx))
+	require.EqualValues(17, cbi.SnapshotHeight())
+	_, height, err = cbi.GetBuckets(25, 0, 10)
+	require.NoError(err)
+	require.EqualValues(17, height)
+	require.NoError(cbi.Stop(ctx))
+}
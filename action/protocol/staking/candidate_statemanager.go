@@ -37,6 +37,7 @@ type (
 		Upsert(*Candidate) error
 		CreditBucketPool(*big.Int) error
 		DebitBucketPool(*big.Int, bool) error
+		SlashBucketPool(*big.Int) error
 		Commit() error
 	}
 
@@ -128,7 +129,7 @@ func (csm *candSM) Upsert(d *Candidate) error {
 	}
 
 	// load change to sm
-	return csm.StateManager.Load(protocolID, stakingCandCenter, &delta)
+	return csm.StateManager.Load(ProtocolID, stakingCandCenter, &delta)
 }
 
 func (csm *candSM) CreditBucketPool(amount *big.Int) error {
@@ -139,6 +140,10 @@ func (csm *candSM) DebitBucketPool(amount *big.Int, newBucket bool) error {
 	return csm.bucketPool.DebitPool(csm.StateManager, amount, newBucket)
 }
 
+func (csm *candSM) SlashBucketPool(amount *big.Int) error {
+	return csm.bucketPool.SlashPool(csm.StateManager, amount)
+}
+
 func (csm *candSM) Commit() error {
 	if err := csm.candCenter.Commit(); err != nil {
 		return err
@@ -149,5 +154,5 @@ func (csm *candSM) Commit() error {
 	}
 
 	// write updated view back to state factory
-	return csm.WriteView(protocolID, csm.DirtyView())
+	return csm.WriteView(ProtocolID, csm.DirtyView())
 }
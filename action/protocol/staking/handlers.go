@@ -85,7 +85,10 @@ func (p *Protocol) handleCreateStake(ctx context.Context, act *action.CreateStak
 	log.AddTopics(byteutil.Uint64ToBytesBigEndian(bucketIdx), candidate.Owner.Bytes())
 
 	// update candidate
-	weightedVote := p.calculateVoteWeight(bucket, false)
+	weightedVote, err := p.calculateVoteWeight(ctx, csm, bucket, false)
+	if err != nil {
+		return log, nil, err
+	}
 	if err := candidate.AddVote(weightedVote); err != nil {
 		return log, nil, &handleError{
 			err:           errors.Wrapf(err, "failed to add vote for candidate %s", candidate.Owner.String()),
@@ -119,6 +122,13 @@ func (p *Protocol) handleCreateStake(ctx context.Context, act *action.CreateStak
 	log.AddAddress(candidate.Owner)
 	log.AddAddress(actionCtx.Caller)
 	log.SetData(byteutil.Uint64ToBytesBigEndian(bucketIdx))
+	if p.hu.IsPost(config.Kamchatka, blkCtx.BlockHeight) {
+		bucketPb, err := bucket.toProto()
+		if err != nil {
+			return log, nil, err
+		}
+		log.AddEvent(protocol.BucketCreatedEvent, bucketPb, byteutil.Uint64ToBytesBigEndian(bucketIdx), candidate.Owner.Bytes())
+	}
 
 	return log, []*action.TransactionLog{
 		{
@@ -179,7 +189,10 @@ func (p *Protocol) handleUnstake(ctx context.Context, act *action.Unstake, csm C
 		return log, errors.Wrapf(err, "failed to update bucket for voter %s", bucket.Owner.String())
 	}
 
-	weightedVote := p.calculateVoteWeight(bucket, csm.ContainsSelfStakingBucket(act.BucketIndex()))
+	weightedVote, err := p.calculateVoteWeight(ctx, csm, bucket, csm.ContainsSelfStakingBucket(act.BucketIndex()))
+	if err != nil {
+		return log, err
+	}
 	if err := candidate.SubVote(weightedVote); err != nil {
 		return log, &handleError{
 			err:           errors.Wrapf(err, "failed to subtract vote for candidate %s", bucket.Candidate.String()),
@@ -327,7 +340,10 @@ func (p *Protocol) handleChangeCandidate(ctx context.Context, act *action.Change
 	}
 
 	// update previous candidate
-	weightedVotes := p.calculateVoteWeight(bucket, false)
+	weightedVotes, err := p.calculateVoteWeight(ctx, csm, bucket, false)
+	if err != nil {
+		return log, err
+	}
 	if err := prevCandidate.SubVote(weightedVotes); err != nil {
 		return log, &handleError{
 			err:           errors.Wrapf(err, "failed to subtract vote for previous candidate %s", prevCandidate.Owner.String()),
@@ -464,7 +480,10 @@ func (p *Protocol) handleDepositToStake(ctx context.Context, act *action.Deposit
 		}
 	}
 
-	prevWeightedVotes := p.calculateVoteWeight(bucket, csm.ContainsSelfStakingBucket(act.BucketIndex()))
+	prevWeightedVotes, err := p.calculateVoteWeight(ctx, csm, bucket, csm.ContainsSelfStakingBucket(act.BucketIndex()))
+	if err != nil {
+		return log, nil, err
+	}
 	// update bucket
 	bucket.StakedAmount.Add(bucket.StakedAmount, act.Amount())
 	if err := updateBucket(csm, act.BucketIndex(), bucket); err != nil {
@@ -478,7 +497,10 @@ func (p *Protocol) handleDepositToStake(ctx context.Context, act *action.Deposit
 			failureStatus: iotextypes.ReceiptStatus_ErrNotEnoughBalance,
 		}
 	}
-	weightedVotes := p.calculateVoteWeight(bucket, csm.ContainsSelfStakingBucket(act.BucketIndex()))
+	weightedVotes, err := p.calculateVoteWeight(ctx, csm, bucket, csm.ContainsSelfStakingBucket(act.BucketIndex()))
+	if err != nil {
+		return log, nil, err
+	}
 	if err := candidate.AddVote(weightedVotes); err != nil {
 		return log, nil, &handleError{
 			err:           errors.Wrapf(err, "failed to add vote for candidate %s", candidate.Owner.String()),
@@ -557,7 +579,10 @@ func (p *Protocol) handleRestake(ctx context.Context, act *action.Restake, csm C
 		}
 	}
 
-	prevWeightedVotes := p.calculateVoteWeight(bucket, csm.ContainsSelfStakingBucket(act.BucketIndex()))
+	prevWeightedVotes, err := p.calculateVoteWeight(ctx, csm, bucket, csm.ContainsSelfStakingBucket(act.BucketIndex()))
+	if err != nil {
+		return log, err
+	}
 	// update bucket
 	actDuration := time.Duration(act.Duration()) * 24 * time.Hour
 	if bucket.StakedDuration.Hours() > actDuration.Hours() {
@@ -590,7 +615,10 @@ func (p *Protocol) handleRestake(ctx context.Context, act *action.Restake, csm C
 			failureStatus: iotextypes.ReceiptStatus_ErrNotEnoughBalance,
 		}
 	}
-	weightedVotes := p.calculateVoteWeight(bucket, csm.ContainsSelfStakingBucket(act.BucketIndex()))
+	weightedVotes, err := p.calculateVoteWeight(ctx, csm, bucket, csm.ContainsSelfStakingBucket(act.BucketIndex()))
+	if err != nil {
+		return log, err
+	}
 	if err := candidate.AddVote(weightedVotes); err != nil {
 		return log, &handleError{
 			err:           errors.Wrapf(err, "failed to add vote for candidate %s", candidate.Owner.String()),
@@ -655,12 +683,16 @@ func (p *Protocol) handleCandidateRegister(ctx context.Context, act *action.Cand
 	}
 	log.AddTopics(byteutil.Uint64ToBytesBigEndian(bucketIdx), owner.Bytes())
 
+	votes, err := p.calculateVoteWeight(ctx, csm, bucket, true)
+	if err != nil {
+		return log, nil, err
+	}
 	c = &Candidate{
 		Owner:              owner,
 		Operator:           act.OperatorAddress(),
 		Reward:             act.RewardAddress(),
 		Name:               act.Name(),
-		Votes:              p.calculateVoteWeight(bucket, true),
+		Votes:              votes,
 		SelfStakeBucketIdx: bucketIdx,
 		SelfStake:          act.Amount(),
 	}
@@ -697,6 +729,18 @@ func (p *Protocol) handleCandidateRegister(ctx context.Context, act *action.Cand
 	log.AddAddress(owner)
 	log.AddAddress(actCtx.Caller)
 	log.SetData(byteutil.Uint64ToBytesBigEndian(bucketIdx))
+	if p.hu.IsPost(config.Kamchatka, blkCtx.BlockHeight) {
+		candPb, err := c.toProto()
+		if err != nil {
+			return log, nil, err
+		}
+		bucketPb, err := bucket.toProto()
+		if err != nil {
+			return log, nil, err
+		}
+		log.AddEvent(protocol.CandidateRegisteredEvent, candPb, owner.Bytes())
+		log.AddEvent(protocol.BucketCreatedEvent, bucketPb, byteutil.Uint64ToBytesBigEndian(bucketIdx), owner.Bytes())
+	}
 
 	return log, []*action.TransactionLog{
 		{
@@ -876,7 +920,7 @@ func BucketIndexFromReceiptLog(log *iotextypes.Log) (uint64, bool) {
 		return 0, false
 	}
 
-	h := hash.Hash160b([]byte(protocolID))
+	h := hash.Hash160b([]byte(ProtocolID))
 	addr, _ := address.FromBytes(h[:])
 	if log.ContractAddress != addr.String() {
 		return 0, false
@@ -85,7 +85,7 @@ func TestProtocol_HandleCreateStake(t *testing.T) {
 	require.NoError(err)
 	cc, ok := v.(*ViewData)
 	require.True(ok)
-	require.NoError(sm.WriteView(protocolID, cc))
+	require.NoError(sm.WriteView(ProtocolID, cc))
 
 	stakerAddr := identityset.Address(1)
 	tests := []struct {
@@ -2563,7 +2563,7 @@ func initCreateStake(t *testing.T, sm protocol.StateManager, callerAddr address.
 	require.NoError(err)
 	cc, ok := v.(*ViewData)
 	require.True(ok)
-	require.NoError(sm.WriteView(protocolID, cc))
+	require.NoError(sm.WriteView(ProtocolID, cc))
 	_, err = p.Handle(ctx, a, sm)
 	require.NoError(err)
 	cost, err := a.Cost()
@@ -2599,7 +2599,7 @@ func initAll(t *testing.T, ctrl *gomock.Controller) (protocol.StateManager, *Pro
 	require.NoError(err)
 	cc, ok := v.(*ViewData)
 	require.True(ok)
-	require.NoError(sm.WriteView(protocolID, cc))
+	require.NoError(sm.WriteView(ProtocolID, cc))
 	return sm, p, candidate, candidate2
 }
 
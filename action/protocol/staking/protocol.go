@@ -31,8 +31,8 @@ import (
 )
 
 const (
-	// protocolID is the protocol ID
-	protocolID = "staking"
+	// ProtocolID is the protocol ID
+	ProtocolID = "staking"
 
 	// StakingNameSpace is the bucket name for staking state
 	StakingNameSpace = "Staking"
@@ -81,6 +81,8 @@ type (
 		WithdrawWaitingPeriod time.Duration
 		MinStakeAmount        *big.Int
 		BootstrapCandidates   []genesis.BootstrapCandidate
+		VoteWeightCurve       VoteWeightCurve
+		VoteWeightGovernor    address.Address
 	}
 
 	// DepositGas deposits gas to some pool
@@ -89,7 +91,7 @@ type (
 
 // NewProtocol instantiates the protocol of staking
 func NewProtocol(depositGas DepositGas, cfg genesis.Staking, candBucketsIndexer *CandidatesBucketsIndexer, reviseHeights ...uint64) (*Protocol, error) {
-	h := hash.Hash160b([]byte(protocolID))
+	h := hash.Hash160b([]byte(ProtocolID))
 	addr, err := address.FromBytes(h[:])
 	if err != nil {
 		return nil, err
@@ -110,6 +112,18 @@ func NewProtocol(depositGas DepositGas, cfg genesis.Staking, candBucketsIndexer
 		return nil, ErrInvalidAmount
 	}
 
+	curve := VoteWeightCurve(cfg.VoteWeightCurve)
+	if err := curve.Validate(); err != nil {
+		return nil, err
+	}
+
+	var governor address.Address
+	if cfg.VoteWeightGovernor != "" {
+		if governor, err = address.FromString(cfg.VoteWeightGovernor); err != nil {
+			return nil, errors.Wrap(err, "invalid vote weight governor")
+		}
+	}
+
 	// new vote reviser, revise ate greenland
 	voteReviser := NewVoteReviser(cfg.VoteWeightCalConsts, reviseHeights...)
 
@@ -124,6 +138,8 @@ func NewProtocol(depositGas DepositGas, cfg genesis.Staking, candBucketsIndexer
 			WithdrawWaitingPeriod: cfg.WithdrawWaitingPeriod,
 			MinStakeAmount:        minStakeAmount,
 			BootstrapCandidates:   cfg.BootstrapCandidates,
+			VoteWeightCurve:       curve,
+			VoteWeightGovernor:    governor,
 		},
 		depositGas:         depositGas,
 		candBucketsIndexer: candBucketsIndexer,
@@ -191,7 +207,7 @@ func (p *Protocol) CreateGenesisStates(
 			Operator:           operator,
 			Reward:             reward,
 			Name:               bc.Name,
-			Votes:              p.calculateVoteWeight(bucket, true),
+			Votes:              calculateVoteWeight(p.config.VoteWeightCalConsts, bucket, true),
 			SelfStakeBucketIdx: bucketIdx,
 			SelfStake:          selfStake,
 		}
@@ -233,6 +249,15 @@ func (p *Protocol) CreatePreStates(ctx context.Context, sm protocol.StateManager
 			return err
 		}
 	}
+	if p.needReviseVoteWeight(ctx) {
+		csm, err := NewCandidateStateManager(sm, p.hu.IsPost(config.Greenland, blkCtx.BlockHeight))
+		if err != nil {
+			return err
+		}
+		if err := p.reviseVoteWeight(csm, blkCtx.BlockTimeStamp); err != nil {
+			return err
+		}
+	}
 	if p.candBucketsIndexer == nil {
 		return nil
 	}
@@ -246,7 +271,23 @@ func (p *Protocol) CreatePreStates(ctx context.Context, sm protocol.StateManager
 		return nil
 	}
 
-	return p.handleStakingIndexer(rp.GetEpochHeight(currentEpochNum-1), sm)
+	return p.handleStakingIndexer(epochStartHeight, sm)
+}
+
+// needReviseVoteWeight returns true at Kamchatka height and the start height of each epoch afterwards
+func (p *Protocol) needReviseVoteWeight(ctx context.Context) bool {
+	blkCtx := protocol.MustGetBlockCtx(ctx)
+	if p.hu.IsPre(config.Kamchatka, blkCtx.BlockHeight) {
+		return false
+	}
+	if blkCtx.BlockHeight == p.hu.KamchatkaBlockHeight() {
+		return true
+	}
+	rp := rolldpos.FindProtocol(protocol.MustGetRegistry(ctx))
+	if rp == nil {
+		return false
+	}
+	return blkCtx.BlockHeight == rp.GetEpochHeight(rp.GetEpochNum(blkCtx.BlockHeight))
 }
 
 func (p *Protocol) handleStakingIndexer(epochStartHeight uint64, sm protocol.StateManager) error {
@@ -326,6 +367,11 @@ func (p *Protocol) handle(ctx context.Context, act action.Action, csm CandidateS
 		rLog, tLogs, err = p.handleCandidateRegister(ctx, act, csm)
 	case *action.CandidateUpdate:
 		rLog, err = p.handleCandidateUpdate(ctx, act, csm)
+	case *action.Execution:
+		if !p.isSetVoteWeightCurve(ctx, act) {
+			return nil, nil
+		}
+		rLog, err = p.handleSetVoteWeightCurve(ctx, act, csm)
 	default:
 		return nil, nil
 	}
@@ -334,6 +380,11 @@ func (p *Protocol) handle(ctx context.Context, act action.Action, csm CandidateS
 		logs = append(logs, l)
 	}
 	if err == nil {
+		events, err := rLog.BuildEvents(ctx)
+		if err != nil {
+			return nil, err
+		}
+		logs = append(logs, events...)
 		return p.settleAction(ctx, csm, uint64(iotextypes.ReceiptStatus_Success), logs, tLogs)
 	}
 
@@ -382,25 +433,30 @@ func (p *Protocol) ActiveCandidates(ctx context.Context, sr protocol.StateReader
 	list := c.AllCandidates()
 	cand := make(CandidateList, 0, len(list))
 	for i := range list {
-		if list[i].SelfStake.Cmp(p.config.RegistrationConsts.MinSelfStake) >= 0 {
+		if p.isActive(list[i]) {
 			cand = append(cand, list[i])
 		}
 	}
 	return cand.toStateCandidateList()
 }
 
+// isActive returns whether the self-stake of the candidate meets the minimum self-stake of registration
+func (p *Protocol) isActive(c *Candidate) bool {
+	return c.SelfStake.Cmp(p.config.RegistrationConsts.MinSelfStake) >= 0
+}
+
 // ReadState read the state on blockchain via protocol
 func (p *Protocol) ReadState(ctx context.Context, sr protocol.StateReader, method []byte, args ...[]byte) ([]byte, uint64, error) {
 	m := iotexapi.ReadStakingDataMethod{}
 	if err := proto.Unmarshal(method, &m); err != nil {
-		return nil, uint64(0), errors.Wrap(err, "failed to unmarshal method name")
+		return nil, uint64(0), errors.Wrapf(protocol.ErrInvalidArgument, "failed to unmarshal method name: %v", err)
 	}
 	if len(args) != 1 {
-		return nil, uint64(0), errors.Errorf("invalid number of arguments %d", len(args))
+		return nil, uint64(0), errors.Wrapf(protocol.ErrInvalidArgument, "invalid number of arguments %d", len(args))
 	}
 	r := iotexapi.ReadStakingDataRequest{}
 	if err := proto.Unmarshal(args[0], &r); err != nil {
-		return nil, uint64(0), errors.Wrap(err, "failed to unmarshal request")
+		return nil, uint64(0), errors.Wrapf(protocol.ErrInvalidArgument, "failed to unmarshal request: %v", err)
 	}
 
 	csr, err := ConstructBaseView(sr)
@@ -413,8 +469,12 @@ func (p *Protocol) ReadState(ctx context.Context, sr protocol.StateReader, metho
 	if err != nil {
 		return nil, 0, err
 	}
-	rp := rolldpos.MustGetProtocol(protocol.MustGetRegistry(ctx))
-	epochStartHeight := rp.GetEpochHeight(rp.GetEpochNum(inputHeight))
+	rp := rolldpos.FindProtocol(protocol.MustGetRegistry(ctx))
+	if rp == nil {
+		return nil, 0, errors.New("rolldpos protocol is not registered")
+	}
+	epochNum := rp.GetEpochNum(inputHeight)
+	epochStartHeight := rp.GetEpochHeight(epochNum)
 
 	var (
 		height uint64
@@ -423,7 +483,7 @@ func (p *Protocol) ReadState(ctx context.Context, sr protocol.StateReader, metho
 	switch m.GetMethod() {
 	case iotexapi.ReadStakingDataMethod_BUCKETS:
 		if epochStartHeight != 0 && p.candBucketsIndexer != nil {
-			return p.candBucketsIndexer.GetBuckets(epochStartHeight, r.GetBuckets().GetPagination().GetOffset(), r.GetBuckets().GetPagination().GetLimit())
+			return p.candBucketsIndexer.GetBuckets(p.snapshotHeight(rp, epochNum), r.GetBuckets().GetPagination().GetOffset(), r.GetBuckets().GetPagination().GetLimit())
 		}
 		resp, height, err = readStateBuckets(ctx, sr, r.GetBuckets())
 	case iotexapi.ReadStakingDataMethod_BUCKETS_BY_VOTER:
@@ -436,7 +496,7 @@ func (p *Protocol) ReadState(ctx context.Context, sr protocol.StateReader, metho
 		resp, height, err = readStateBucketCount(ctx, csr, r.GetBucketsCount())
 	case iotexapi.ReadStakingDataMethod_CANDIDATES:
 		if epochStartHeight != 0 && p.candBucketsIndexer != nil {
-			return p.candBucketsIndexer.GetCandidates(epochStartHeight, r.GetCandidates().GetPagination().GetOffset(), r.GetCandidates().GetPagination().GetLimit())
+			return p.candBucketsIndexer.GetCandidates(p.snapshotHeight(rp, epochNum), r.GetCandidates().GetPagination().GetOffset(), r.GetCandidates().GetPagination().GetLimit())
 		}
 		resp, height, err = readStateCandidates(ctx, csr, r.GetCandidates())
 	case iotexapi.ReadStakingDataMethod_CANDIDATE_BY_NAME:
@@ -446,7 +506,7 @@ func (p *Protocol) ReadState(ctx context.Context, sr protocol.StateReader, metho
 	case iotexapi.ReadStakingDataMethod_TOTAL_STAKING_AMOUNT:
 		resp, height, err = readStateTotalStakingAmount(ctx, csr, r.GetTotalStakingAmount())
 	default:
-		err = errors.New("corresponding method isn't found")
+		err = errors.Wrapf(protocol.ErrNotFound, "corresponding method %s isn't found", m.GetMethod())
 	}
 	if err != nil {
 		return nil, height, err
@@ -458,23 +518,62 @@ func (p *Protocol) ReadState(ctx context.Context, sr protocol.StateReader, metho
 	return data, height, nil
 }
 
+// snapshotHeight returns the height the snapshot at the start of the epoch is stored with in the indexer
+func (p *Protocol) snapshotHeight(rp *rolldpos.Protocol, epochNum uint64) uint64 {
+	epochStartHeight := rp.GetEpochHeight(epochNum)
+	if sh := p.candBucketsIndexer.SnapshotHeight(); (sh != 0 && epochStartHeight >= sh) || epochNum <= 1 {
+		return epochStartHeight
+	}
+	// the snapshot was stored with the start height of the previous epoch
+	return rp.GetEpochHeight(epochNum - 1)
+}
+
 // Register registers the protocol with a unique ID
 func (p *Protocol) Register(r *protocol.Registry) error {
-	return r.Register(protocolID, p)
+	return r.Register(ProtocolID, p)
 }
 
 // ForceRegister registers the protocol with a unique ID and force replacing the previous protocol if it exists
 func (p *Protocol) ForceRegister(r *protocol.Registry) error {
-	return r.ForceRegister(protocolID, p)
+	return r.ForceRegister(ProtocolID, p)
 }
 
 // Name returns the name of protocol
 func (p *Protocol) Name() string {
-	return protocolID
+	return ProtocolID
 }
 
-func (p *Protocol) calculateVoteWeight(v *VoteBucket, selfStake bool) *big.Int {
-	return calculateVoteWeight(p.config.VoteWeightCalConsts, v, selfStake)
+// Dependencies returns the IDs of the protocols which the protocol depends on, the staking indexer takes the snapshot
+// of the buckets at the start of each epoch
+func (p *Protocol) Dependencies() []string {
+	if p.candBucketsIndexer != nil {
+		return []string{rolldpos.ProtocolID}
+	}
+	return nil
+}
+
+// isSetVoteWeightCurve returns true if the execution calls the protocol to set the vote weight curve
+func (p *Protocol) isSetVoteWeightCurve(ctx context.Context, act *action.Execution) bool {
+	blkCtx := protocol.MustGetBlockCtx(ctx)
+	return p.config.VoteWeightGovernor != nil &&
+		p.hu.IsPost(config.Kamchatka, blkCtx.BlockHeight) &&
+		act.Contract() == p.addr.String()
+}
+
+func (p *Protocol) calculateVoteWeight(ctx context.Context, sr protocol.StateReader, v *VoteBucket, selfStake bool) (*big.Int, error) {
+	blkCtx := protocol.MustGetBlockCtx(ctx)
+	if p.hu.IsPre(config.Kamchatka, blkCtx.BlockHeight) {
+		return calculateVoteWeight(p.config.VoteWeightCalConsts, v, selfStake), nil
+	}
+	c, err := p.voteWeightCurve(sr)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get vote weight curve")
+	}
+	t, err := p.voteWeightTime(sr, blkCtx.BlockTimeStamp)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get vote weight time")
+	}
+	return c.Weight(v, selfStake, t), nil
 }
 
 // settleAccount deposits gas fee and updates caller's nonce
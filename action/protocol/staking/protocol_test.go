@@ -110,7 +110,7 @@ func TestProtocol(t *testing.T) {
 		Genesis: genesis.Default,
 	})
 	v, err := stk.Start(ctx, sm)
-	sm.WriteView(protocolID, v)
+	sm.WriteView(ProtocolID, v)
 	r.NoError(err)
 	_, ok := v.(*ViewData)
 	r.True(ok)
@@ -204,7 +204,7 @@ func TestCreatePreStates(t *testing.T) {
 	)
 	v, err := p.Start(ctx, sm)
 	require.NoError(err)
-	require.NoError(sm.WriteView(protocolID, v))
+	require.NoError(sm.WriteView(ProtocolID, v))
 	csm, err := NewCandidateStateManager(sm, false)
 	require.NoError(err)
 	require.NotNil(csm)
@@ -267,7 +267,7 @@ func Test_CreatePreStatesWithRegisterProtocol(t *testing.T) {
 	)
 	v, err := p.Start(ctx, sm)
 	require.NoError(err)
-	require.NoError(sm.WriteView(protocolID, v))
+	require.NoError(sm.WriteView(ProtocolID, v))
 	_, err = NewCandidateStateManager(sm, true)
 	require.Error(err)
 
@@ -373,7 +373,7 @@ func Test_CreateGenesisStates(t *testing.T) {
 
 		v, err := p.Start(ctx, sm)
 		require.NoError(err)
-		require.NoError(sm.WriteView(protocolID, v))
+		require.NoError(sm.WriteView(ProtocolID, v))
 
 		err = p.CreateGenesisStates(ctx, sm)
 		if err != nil {
@@ -9,6 +9,7 @@ package staking
 import (
 	"context"
 
+	"github.com/golang/protobuf/proto"
 	"github.com/iotexproject/go-pkgs/hash"
 	"github.com/iotexproject/iotex-address/address"
 
@@ -16,12 +17,21 @@ import (
 	"github.com/iotexproject/iotex-core/action/protocol"
 )
 
-type receiptLog struct {
-	addr                  string
-	topics                action.Topics
-	data                  []byte
-	postFairbankMigration bool
-}
+type (
+	receiptLog struct {
+		addr                  string
+		topics                action.Topics
+		data                  []byte
+		postFairbankMigration bool
+		events                []*systemEvent
+	}
+
+	systemEvent struct {
+		event   protocol.SystemEvent
+		msg     proto.Message
+		indexed [][]byte
+	}
+)
 
 func newReceiptLog(addr, topic string, postFairbankMigration bool) *receiptLog {
 	r := receiptLog{
@@ -76,3 +86,25 @@ func (r *receiptLog) Build(ctx context.Context, err error) *action.Log {
 	}
 	return nil
 }
+
+// AddEvent adds a system event emitted along with the log if the action succeeds
+func (r *receiptLog) AddEvent(e protocol.SystemEvent, msg proto.Message, indexed ...[]byte) {
+	r.events = append(r.events, &systemEvent{
+		event:   e,
+		msg:     msg,
+		indexed: indexed,
+	})
+}
+
+// BuildEvents builds the logs of the system events
+func (r *receiptLog) BuildEvents(ctx context.Context) ([]*action.Log, error) {
+	logs := make([]*action.Log, 0, len(r.events))
+	for _, e := range r.events {
+		log, err := protocol.NewSystemEventLog(ctx, r.addr, e.event, e.msg, e.indexed...)
+		if err != nil {
+			return nil, err
+		}
+		logs = append(logs, log)
+	}
+	return logs, nil
+}
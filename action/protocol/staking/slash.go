@@ -0,0 +1,100 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package staking
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/iotexproject/iotex-address/address"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/iotexproject/iotex-core/action/protocol"
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/pkg/log"
+)
+
+// _slashRateDenominator is the denominator of the slash rate in basis points
+const _slashRateDenominator = 10000
+
+// SlashSelfStake slashes the rate in basis points of the self-stake bucket of the candidate operated by the address,
+// and reduces the self-stake and the votes of the candidate accordingly. The amount slashed is taken out of the bucket
+// pool and returned, for the caller to burn or deposit elsewhere. It returns zero if there is no such candidate, or
+// the self-stake bucket is unstaked. A candidate whose self-stake falls below the minimum self-stake of registration
+// is deactivated, i.e., left out of the active candidates, until the self-stake bucket is topped up by a deposit
+func (p *Protocol) SlashSelfStake(
+	ctx context.Context,
+	sm protocol.StateManager,
+	operator address.Address,
+	rate uint64,
+) (*big.Int, error) {
+	blkCtx := protocol.MustGetBlockCtx(ctx)
+	if rate > _slashRateDenominator {
+		return nil, errors.Errorf("slash rate %d is greater than %d", rate, _slashRateDenominator)
+	}
+	csm, err := NewCandidateStateManager(sm, p.hu.IsPost(config.Greenland, blkCtx.BlockHeight))
+	if err != nil {
+		return nil, err
+	}
+	var candidate *Candidate
+	for _, c := range csm.DirtyView().candCenter.All() {
+		if address.Equal(c.Operator, operator) {
+			candidate = csm.GetByOwner(c.Owner)
+			break
+		}
+	}
+	if candidate == nil || !csm.ContainsSelfStakingBucket(candidate.SelfStakeBucketIdx) {
+		return big.NewInt(0), nil
+	}
+	bucket, err := getBucket(csm, candidate.SelfStakeBucketIdx)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get the self-stake bucket of %s", candidate.Owner.String())
+	}
+	if bucket.isUnstaked() {
+		return big.NewInt(0), nil
+	}
+	amount := new(big.Int).Mul(bucket.StakedAmount, new(big.Int).SetUint64(rate))
+	amount.Div(amount, big.NewInt(_slashRateDenominator))
+	if amount.Sign() == 0 {
+		return amount, nil
+	}
+
+	prevWeightedVotes, err := p.calculateVoteWeight(ctx, csm, bucket, true)
+	if err != nil {
+		return nil, err
+	}
+	bucket.StakedAmount.Sub(bucket.StakedAmount, amount)
+	if err := updateBucket(csm, candidate.SelfStakeBucketIdx, bucket); err != nil {
+		return nil, errors.Wrapf(err, "failed to update the self-stake bucket of %s", candidate.Owner.String())
+	}
+	weightedVotes, err := p.calculateVoteWeight(ctx, csm, bucket, true)
+	if err != nil {
+		return nil, err
+	}
+	if err := candidate.SubVote(prevWeightedVotes); err != nil {
+		return nil, errors.Wrapf(err, "failed to subtract vote for candidate %s", candidate.Owner.String())
+	}
+	if err := candidate.AddVote(weightedVotes); err != nil {
+		return nil, errors.Wrapf(err, "failed to add vote for candidate %s", candidate.Owner.String())
+	}
+	if err := candidate.SubSelfStake(amount); err != nil {
+		return nil, errors.Wrapf(err, "failed to subtract self stake for candidate %s", candidate.Owner.String())
+	}
+	if err := csm.Upsert(candidate); err != nil {
+		return nil, err
+	}
+	if !p.isActive(candidate) {
+		log.L().Info("Candidate is deactivated by slashing its self-stake",
+			zap.String("candidate", candidate.Owner.String()),
+			zap.String("selfStake", candidate.SelfStake.String()))
+	}
+	if err := csm.SlashBucketPool(amount); err != nil {
+		return nil, errors.Wrap(err, "failed to update staking bucket pool")
+	}
+	return amount, nil
+}
@@ -0,0 +1,97 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package staking
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/iotexproject/iotex-proto/golang/iotextypes"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/action"
+	"github.com/iotexproject/iotex-core/action/protocol"
+	"github.com/iotexproject/iotex-core/blockchain/genesis"
+	"github.com/iotexproject/iotex-core/test/identityset"
+)
+
+func TestProtocol_SlashSelfStake(t *testing.T) {
+	require := require.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	sm, p, _, _ := initAll(t, ctrl)
+
+	owner, operator := identityset.Address(30), identityset.Address(31)
+	require.NoError(setupAccount(sm, owner, 1400000))
+	handle := func(act action.Action, nonce uint64) *action.Receipt {
+		ctx := protocol.WithActionCtx(context.Background(), protocol.ActionCtx{
+			Caller:   owner,
+			GasPrice: big.NewInt(0),
+			Nonce:    nonce,
+		})
+		ctx = protocol.WithBlockCtx(ctx, protocol.BlockCtx{
+			BlockHeight:    1,
+			BlockTimeStamp: time.Now(),
+			GasLimit:       1000000,
+		})
+		ctx = protocol.WithBlockchainCtx(ctx, protocol.BlockchainCtx{Genesis: genesis.Default})
+		r, err := p.Handle(ctx, act, sm)
+		require.NoError(err)
+		require.NoError(p.Commit(ctx, sm))
+		return r
+	}
+	isActive := func() bool {
+		ctx := protocol.WithBlockchainCtx(context.Background(), protocol.BlockchainCtx{Genesis: genesis.Default})
+		list, err := p.ActiveCandidates(ctx, sm, 1)
+		require.NoError(err)
+		for _, c := range list {
+			if c.Address == operator.String() {
+				return true
+			}
+		}
+		return false
+	}
+
+	// the candidate registers with the minimum self-stake
+	minSelfStake := p.config.RegistrationConsts.MinSelfStake
+	register, err := action.NewCandidateRegister(1, "slashed", operator.String(), operator.String(), owner.String(),
+		minSelfStake.String(), 1, true, nil, 1000000, big.NewInt(0))
+	require.NoError(err)
+	require.Equal(uint64(iotextypes.ReceiptStatus_Success), handle(register, 1).Status)
+	require.True(isActive())
+
+	// the candidate is deactivated once its self-stake falls below the minimum
+	ctx := protocol.WithBlockCtx(context.Background(), protocol.BlockCtx{
+		BlockHeight:    1,
+		BlockTimeStamp: time.Now(),
+	})
+	amount, err := p.SlashSelfStake(ctx, sm, operator, 1000)
+	require.NoError(err)
+	require.Equal(new(big.Int).Div(minSelfStake, big.NewInt(10)), amount)
+	require.NoError(p.Commit(ctx, sm))
+	require.False(isActive())
+	csm, err := NewCandidateStateManager(sm, false)
+	require.NoError(err)
+	candidate := csm.GetByOwner(owner)
+	require.Equal(new(big.Int).Sub(minSelfStake, amount), candidate.SelfStake)
+
+	// and activated again once the self-stake bucket is topped up
+	deposit, err := action.NewDepositToStake(2, candidate.SelfStakeBucketIdx, amount.String(), nil, 1000000, big.NewInt(0))
+	require.NoError(err)
+	require.Equal(uint64(iotextypes.ReceiptStatus_Success), handle(deposit, 2).Status)
+	require.True(isActive())
+
+	// no candidate operated by the address
+	amount, err = p.SlashSelfStake(ctx, sm, identityset.Address(32), 1000)
+	require.NoError(err)
+	require.Zero(amount.Sign())
+	_, err = p.SlashSelfStake(ctx, sm, operator, _slashRateDenominator+1)
+	require.Error(err)
+}
@@ -0,0 +1,60 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package staking
+
+import (
+	"context"
+
+	"github.com/iotexproject/iotex-address/address"
+	"github.com/pkg/errors"
+
+	"github.com/iotexproject/iotex-core/action/protocol"
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/state"
+)
+
+// TransferBuckets transfers all the buckets owned by an address to the new owner, including the unstaked ones and
+// the self-stake buckets, and returns the indices of the buckets. The votes of the buckets stay with their candidates
+func (p *Protocol) TransferBuckets(
+	ctx context.Context,
+	sm protocol.StateManager,
+	owner address.Address,
+	newOwner address.Address,
+) ([]uint64, error) {
+	blkCtx := protocol.MustGetBlockCtx(ctx)
+	csm, err := NewCandidateStateManager(sm, p.hu.IsPost(config.Greenland, blkCtx.BlockHeight))
+	if err != nil {
+		return nil, err
+	}
+	indices, _, err := getVoterBucketIndices(csm, owner)
+	switch errors.Cause(err) {
+	case nil:
+	case state.ErrStateNotExist:
+		return nil, nil
+	default:
+		return nil, errors.Wrapf(err, "failed to get the buckets of %s", owner.String())
+	}
+	transferred := make([]uint64, 0, len(*indices))
+	for _, index := range *indices {
+		bucket, err := getBucket(csm, index)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get bucket %d", index)
+		}
+		if err := delVoterBucketIndex(csm, owner, index); err != nil {
+			return nil, errors.Wrapf(err, "failed to delete voter bucket index for voter %s", owner.String())
+		}
+		if err := putVoterBucketIndex(csm, newOwner, index); err != nil {
+			return nil, errors.Wrapf(err, "failed to put voter bucket index for voter %s", newOwner.String())
+		}
+		bucket.Owner = newOwner
+		if err := updateBucket(csm, index, bucket); err != nil {
+			return nil, errors.Wrapf(err, "failed to update bucket for voter %s", newOwner.String())
+		}
+		transferred = append(transferred, index)
+	}
+	return transferred, nil
+}
@@ -0,0 +1,48 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package staking
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/pkg/unit"
+	"github.com/iotexproject/iotex-core/state"
+	"github.com/iotexproject/iotex-core/test/identityset"
+)
+
+func TestProtocol_TransferBuckets(t *testing.T) {
+	require := require.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	sm, p, candidate, _ := initAll(t, ctrl)
+
+	owner, newOwner := identityset.Address(33), identityset.Address(34)
+	ctx, _ := initCreateStake(t, sm, owner, 1000, big.NewInt(unit.Qev), 10000, 1, 1, time.Now(), 10000, p, candidate, "100000000000000000000", false)
+	indices, err := p.TransferBuckets(ctx, sm, owner, newOwner)
+	require.NoError(err)
+	require.Equal([]uint64{0}, indices)
+	bucket, err := getBucket(sm, 0)
+	require.NoError(err)
+	require.Equal(newOwner.String(), bucket.Owner.String())
+	require.Equal(candidate.Owner.String(), bucket.Candidate.String())
+	bis, _, err := getVoterBucketIndices(sm, newOwner)
+	require.NoError(err)
+	require.Equal(BucketIndices{0}, *bis)
+	_, _, err = getVoterBucketIndices(sm, owner)
+	require.Equal(state.ErrStateNotExist, errors.Cause(err))
+
+	// no bucket to transfer
+	indices, err = p.TransferBuckets(ctx, sm, owner, newOwner)
+	require.NoError(err)
+	require.Empty(indices)
+}
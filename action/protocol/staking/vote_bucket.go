@@ -310,6 +310,45 @@ func getBucketsWithIndices(sr protocol.StateReader, indices BucketIndices) ([]*V
 	return buckets, nil
 }
 
+// VoterBuckets returns the staked buckets voting for the candidate operated by the address, except the buckets owned by
+// the owner of the candidate. It returns nil if there is no such candidate
+func VoterBuckets(sr protocol.StateReader, operator address.Address) ([]*VoteBucket, error) {
+	csr, err := GetStakingStateReader(sr)
+	if err != nil {
+		return nil, err
+	}
+	var cand *Candidate
+	for _, c := range csr.AllCandidates() {
+		if address.Equal(c.Operator, operator) {
+			cand = c
+			break
+		}
+	}
+	if cand == nil {
+		return nil, nil
+	}
+	indices, _, err := getCandBucketIndices(sr, cand.Owner)
+	switch errors.Cause(err) {
+	case nil:
+	case state.ErrStateNotExist:
+		return nil, nil
+	default:
+		return nil, err
+	}
+	buckets, err := getBucketsWithIndices(sr, *indices)
+	if err != nil {
+		return nil, err
+	}
+	voters := make([]*VoteBucket, 0, len(buckets))
+	for _, b := range buckets {
+		if b == nil || b.isUnstaked() || address.Equal(b.Owner, cand.Owner) {
+			continue
+		}
+		voters = append(voters, b)
+	}
+	return voters, nil
+}
+
 func bucketKey(index uint64) []byte {
 	key := []byte{_bucket}
 	return append(key, byteutil.Uint64ToBytesBigEndian(index)...)
@@ -61,6 +61,13 @@ func (vr *VoteReviser) NeedRevise(height uint64) bool {
 }
 
 func (vr *VoteReviser) calculateVoteWeight(sm protocol.StateManager) (CandidateList, error) {
+	return recalculateCandidateVotes(sm, func(v *VoteBucket, selfStake bool) *big.Int {
+		return calculateVoteWeight(vr.c, v, selfStake)
+	})
+}
+
+// recalculateCandidateVotes sums up the votes of all candidates from their buckets with the weight function
+func recalculateCandidateVotes(sm protocol.StateManager, weight func(*VoteBucket, bool) *big.Int) (CandidateList, error) {
 	cands, _, err := getAllCandidates(sm)
 	switch {
 	case errors.Cause(err) == state.ErrStateNotExist:
@@ -91,10 +98,10 @@ func (vr *VoteReviser) calculateVoteWeight(sm protocol.StateManager) (CandidateL
 		}
 
 		if cand.SelfStakeBucketIdx == bucket.Index {
-			cand.AddVote(calculateVoteWeight(vr.c, bucket, true))
+			cand.AddVote(weight(bucket, true))
 			cand.SelfStake = bucket.StakedAmount
 		} else {
-			cand.AddVote(calculateVoteWeight(vr.c, bucket, false))
+			cand.AddVote(weight(bucket, false))
 		}
 	}
 
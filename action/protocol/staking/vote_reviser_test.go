@@ -90,7 +90,7 @@ func TestVoteReviser(t *testing.T) {
 		Genesis: genesis.Default,
 	})
 	v, err := stk.Start(ctx, sm)
-	sm.WriteView(protocolID, v)
+	sm.WriteView(ProtocolID, v)
 	r.NoError(err)
 	_, ok := v.(*ViewData)
 	r.True(ok)
@@ -0,0 +1,274 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package staking
+
+import (
+	"context"
+	"math/big"
+	"math/bits"
+	"sort"
+	"time"
+
+	"github.com/iotexproject/iotex-proto/golang/iotextypes"
+	"github.com/pkg/errors"
+
+	"github.com/iotexproject/iotex-core/action"
+	"github.com/iotexproject/iotex-core/action/protocol"
+	"github.com/iotexproject/iotex-core/blockchain/genesis"
+	"github.com/iotexproject/iotex-core/pkg/util/byteutil"
+	"github.com/iotexproject/iotex-core/state"
+)
+
+// HandleSetVoteWeightCurve is the topic of the receipt log of changing the vote weight curve
+const HandleSetVoteWeightCurve = "setVoteWeightCurve"
+
+const (
+	_basisPoints = 10000
+	// _curveFields is the number of uint64 fields of a serialized curve
+	_curveFields = 6
+)
+
+var (
+	// voteWeightCurveKey is the key of the curve in use in the current epoch
+	voteWeightCurveKey = append([]byte{_const}, []byte("voteWeightCurve")...)
+	// pendingVoteWeightCurveKey is the key of the curve set by the governor, which takes effect since next epoch
+	pendingVoteWeightCurveKey = append([]byte{_const}, []byte("pendingVoteWeightCurve")...)
+	// voteWeightTimeKey is the key of the time the vote weights are calculated at in the current epoch
+	voteWeightTimeKey = append([]byte{_const}, []byte("voteWeightTime")...)
+
+	// ErrInvalidVoteWeightCurve indicates the parameters of the curve are invalid
+	ErrInvalidVoteWeightCurve = errors.New("invalid vote weight curve")
+)
+
+type (
+	// VoteWeightCurve calculates the vote weight of a bucket with integer arithmetic. The weights in an epoch are
+	// calculated at the start time of the epoch, when the candidate votes are recalculated, so that the votes
+	// subtracted from a candidate for a bucket are the votes added to it
+	VoteWeightCurve genesis.VoteWeightCurve
+
+	// voteWeightTime is the time the vote weights are calculated at
+	voteWeightTime struct {
+		t time.Time
+	}
+)
+
+// Validate validates the parameters of the curve
+func (c *VoteWeightCurve) Validate() error {
+	switch {
+	case c.DurationBonus > _basisPoints, c.AutoStakeBonus > _basisPoints, c.SelfStakeBonus > _basisPoints:
+		return errors.Wrap(ErrInvalidVoteWeightCurve, "bonus is larger than 100%")
+	case c.StalenessDecay > _basisPoints, c.MaxStalenessDecay > _basisPoints:
+		return errors.Wrap(ErrInvalidVoteWeightCurve, "decay is larger than 100%")
+	case c.StalenessDecay > 0 && c.StalenessPeriod < time.Second:
+		return errors.Wrap(ErrInvalidVoteWeightCurve, "staleness period is shorter than 1 second")
+	}
+	return nil
+}
+
+// Serialize serializes the curve into bytes, the staleness period is in seconds
+func (c *VoteWeightCurve) Serialize() ([]byte, error) {
+	data := make([]byte, 0, _curveFields*8)
+	for _, v := range []uint64{
+		c.DurationBonus,
+		c.AutoStakeBonus,
+		c.SelfStakeBonus,
+		uint64(c.StalenessPeriod / time.Second),
+		c.StalenessDecay,
+		c.MaxStalenessDecay,
+	} {
+		data = append(data, byteutil.Uint64ToBytesBigEndian(v)...)
+	}
+	return data, nil
+}
+
+// Deserialize deserializes bytes into the curve
+func (c *VoteWeightCurve) Deserialize(data []byte) error {
+	if len(data) != _curveFields*8 {
+		return errors.Wrapf(ErrInvalidVoteWeightCurve, "invalid data length %d", len(data))
+	}
+	field := func(i int) uint64 {
+		return byteutil.BytesToUint64BigEndian(data[i*8 : (i+1)*8])
+	}
+	c.DurationBonus = field(0)
+	c.AutoStakeBonus = field(1)
+	c.SelfStakeBonus = field(2)
+	c.StalenessPeriod = time.Duration(field(3)) * time.Second
+	c.StalenessDecay = field(4)
+	c.MaxStalenessDecay = field(5)
+	return nil
+}
+
+// Weight returns the weighted votes of the bucket at the time
+func (c *VoteWeightCurve) Weight(v *VoteBucket, selfStake bool, now time.Time) *big.Int {
+	weight := uint64(_basisPoints)
+	if days := uint64((v.StakedDuration + 24*time.Hour - 1) / (24 * time.Hour)); days > 0 {
+		weight += c.DurationBonus * uint64(bits.Len64(days)-1)
+	}
+	if v.AutoStake {
+		weight += c.AutoStakeBonus
+	}
+	if selfStake && v.AutoStake && v.StakedDuration >= time.Duration(91)*24*time.Hour {
+		// self-stake extra bonus requires enable auto-stake for at least 3 months
+		weight = weight * (_basisPoints + c.SelfStakeBonus) / _basisPoints
+	}
+	if decay := c.stalenessDecay(v, now); decay > 0 {
+		weight = weight * (_basisPoints - decay) / _basisPoints
+	}
+
+	weighted := new(big.Int).Mul(v.StakedAmount, new(big.Int).SetUint64(weight))
+	return weighted.Div(weighted, big.NewInt(_basisPoints))
+}
+
+// stalenessDecay returns the decay of a bucket without auto-stake matured before the time
+func (c *VoteWeightCurve) stalenessDecay(v *VoteBucket, now time.Time) uint64 {
+	if v.AutoStake || c.StalenessDecay == 0 || c.StalenessPeriod <= 0 {
+		return 0
+	}
+	maturity := v.StakeStartTime.Add(v.StakedDuration)
+	if !now.After(maturity) {
+		return 0
+	}
+	periods := uint64(now.Sub(maturity) / c.StalenessPeriod)
+	if periods >= c.MaxStalenessDecay/c.StalenessDecay {
+		return c.MaxStalenessDecay
+	}
+	return periods * c.StalenessDecay
+}
+
+// Serialize serializes the time into bytes in nanoseconds
+func (vt *voteWeightTime) Serialize() ([]byte, error) {
+	return byteutil.Uint64ToBytesBigEndian(uint64(vt.t.UnixNano())), nil
+}
+
+// Deserialize deserializes bytes into the time
+func (vt *voteWeightTime) Deserialize(data []byte) error {
+	if len(data) != 8 {
+		return errors.Errorf("invalid vote weight time length %d", len(data))
+	}
+	vt.t = time.Unix(0, int64(byteutil.BytesToUint64BigEndian(data)))
+	return nil
+}
+
+func getVoteWeightCurve(sr protocol.StateReader, key []byte) (*VoteWeightCurve, error) {
+	var c VoteWeightCurve
+	if _, err := sr.State(&c, protocol.NamespaceOption(StakingNameSpace), protocol.KeyOption(key)); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func putVoteWeightCurve(sm protocol.StateManager, key []byte, c *VoteWeightCurve) error {
+	_, err := sm.PutState(c, protocol.NamespaceOption(StakingNameSpace), protocol.KeyOption(key))
+	return err
+}
+
+// voteWeightCurve returns the curve in use, which is the one in genesis if it has not been stored yet
+func (p *Protocol) voteWeightCurve(sr protocol.StateReader) (*VoteWeightCurve, error) {
+	c, err := getVoteWeightCurve(sr, voteWeightCurveKey)
+	if errors.Cause(err) == state.ErrStateNotExist {
+		c, err = &p.config.VoteWeightCurve, nil
+	}
+	return c, err
+}
+
+// voteWeightTime returns the time the vote weights are calculated at in the current epoch, or the time given if the
+// votes have not been recalculated with the curve yet
+func (p *Protocol) voteWeightTime(sr protocol.StateReader, now time.Time) (time.Time, error) {
+	var vt voteWeightTime
+	_, err := sr.State(&vt, protocol.NamespaceOption(StakingNameSpace), protocol.KeyOption(voteWeightTimeKey))
+	switch errors.Cause(err) {
+	case nil:
+		return vt.t, nil
+	case state.ErrStateNotExist:
+		return now, nil
+	default:
+		return time.Time{}, err
+	}
+}
+
+// reviseVoteWeight activates the pending curve if any, and recalculates the votes of all candidates with the curve
+// at the time, which the weights are calculated at till the next revision
+func (p *Protocol) reviseVoteWeight(csm CandidateStateManager, now time.Time) error {
+	c, err := getVoteWeightCurve(csm, pendingVoteWeightCurveKey)
+	switch errors.Cause(err) {
+	case nil:
+		if _, err := csm.DelState(protocol.NamespaceOption(StakingNameSpace), protocol.KeyOption(pendingVoteWeightCurveKey)); err != nil {
+			return err
+		}
+	case state.ErrStateNotExist:
+		if c, err = p.voteWeightCurve(csm); err != nil {
+			return err
+		}
+	default:
+		return err
+	}
+	if err := putVoteWeightCurve(csm, voteWeightCurveKey, c); err != nil {
+		return err
+	}
+	if _, err := csm.PutState(
+		&voteWeightTime{t: now},
+		protocol.NamespaceOption(StakingNameSpace),
+		protocol.KeyOption(voteWeightTimeKey),
+	); err != nil {
+		return err
+	}
+
+	cands, err := recalculateCandidateVotes(csm, func(v *VoteBucket, selfStake bool) *big.Int {
+		return c.Weight(v, selfStake, now)
+	})
+	if err != nil {
+		return err
+	}
+	sort.Sort(cands)
+	for _, cand := range cands {
+		if err := csm.Upsert(cand); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *Protocol) handleSetVoteWeightCurve(ctx context.Context, act *action.Execution, csm CandidateStateManager,
+) (*receiptLog, error) {
+	actionCtx := protocol.MustGetActionCtx(ctx)
+	blkCtx := protocol.MustGetBlockCtx(ctx)
+	log := newReceiptLog(p.addr.String(), HandleSetVoteWeightCurve, blkCtx.BlockHeight >= p.hu.FbkMigrationBlockHeight())
+
+	if actionCtx.Caller.String() != p.config.VoteWeightGovernor.String() {
+		return log, &handleError{
+			err:           errors.Errorf("%s is not the vote weight governor", actionCtx.Caller.String()),
+			failureStatus: iotextypes.ReceiptStatus_ErrUnauthorizedOperator,
+		}
+	}
+	if act.Amount() != nil && act.Amount().Sign() != 0 {
+		return log, &handleError{
+			err:           errors.New("setting vote weight curve does not accept amount"),
+			failureStatus: iotextypes.ReceiptStatus_Failure,
+		}
+	}
+	c := &VoteWeightCurve{}
+	if err := c.Deserialize(act.Data()); err != nil {
+		return log, &handleError{
+			err:           err,
+			failureStatus: iotextypes.ReceiptStatus_Failure,
+		}
+	}
+	if err := c.Validate(); err != nil {
+		return log, &handleError{
+			err:           err,
+			failureStatus: iotextypes.ReceiptStatus_Failure,
+		}
+	}
+	if err := putVoteWeightCurve(csm, pendingVoteWeightCurveKey, c); err != nil {
+		return log, errors.Wrap(err, "failed to put pending vote weight curve")
+	}
+
+	log.AddTopics(actionCtx.Caller.Bytes())
+	log.AddAddress(actionCtx.Caller)
+	log.SetData(act.Data())
+	return log, nil
+}
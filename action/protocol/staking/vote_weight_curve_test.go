@@ -0,0 +1,215 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package staking
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-proto/golang/iotextypes"
+
+	"github.com/iotexproject/iotex-core/action"
+	"github.com/iotexproject/iotex-core/action/protocol"
+	"github.com/iotexproject/iotex-core/action/protocol/rolldpos"
+	"github.com/iotexproject/iotex-core/blockchain/genesis"
+	"github.com/iotexproject/iotex-core/pkg/unit"
+	"github.com/iotexproject/iotex-core/state"
+	"github.com/iotexproject/iotex-core/test/identityset"
+	"github.com/iotexproject/iotex-core/testutil/testdb"
+)
+
+func TestVoteWeightCurve(t *testing.T) {
+	require := require.New(t)
+
+	c := VoteWeightCurve(genesis.Default.Staking.VoteWeightCurve)
+	require.NoError(c.Validate())
+	data, err := c.Serialize()
+	require.NoError(err)
+	decoded := VoteWeightCurve{}
+	require.NoError(decoded.Deserialize(data))
+	require.Equal(c, decoded)
+	require.Equal(ErrInvalidVoteWeightCurve, errors.Cause(decoded.Deserialize(data[1:])))
+
+	for _, invalid := range []VoteWeightCurve{
+		{DurationBonus: 10001},
+		{SelfStakeBonus: 10001},
+		{MaxStalenessDecay: 10001},
+		{StalenessDecay: 100},
+	} {
+		require.Equal(ErrInvalidVoteWeightCurve, errors.Cause(invalid.Validate()))
+	}
+
+	start := time.Unix(1612345678, 0)
+	day := 24 * time.Hour
+	tests := []struct {
+		duration  uint32
+		autoStake bool
+		selfStake bool
+		now       time.Time
+		votes     int64
+	}{
+		{0, false, false, start, 10000},
+		{1, false, false, start, 10000},
+		{7, false, false, start, 10760},
+		{91, true, false, start, 12660},
+		{91, true, true, start, 13419},
+		{90, true, true, start, 12660},
+		// matured for 61 days, decays twice
+		{7, false, false, start.Add(68 * day), 10544},
+		// auto-stake bucket never decays
+		{7, true, false, start.Add(68 * day), 11140},
+		// decay is capped
+		{7, false, false, start.Add(3650 * day), 5380},
+	}
+	for _, test := range tests {
+		bucket := NewVoteBucket(identityset.Address(1), identityset.Address(2), big.NewInt(10000), test.duration, start, test.autoStake)
+		require.Equal(test.votes, c.Weight(bucket, test.selfStake, test.now).Int64())
+	}
+}
+
+func TestProtocol_VoteWeightCurve(t *testing.T) {
+	require := require.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	sm := testdb.NewMockStateManager(ctrl)
+	_, err := sm.PutState(
+		&totalBucketCount{count: 0},
+		protocol.NamespaceOption(StakingNameSpace),
+		protocol.KeyOption(TotalBucketKey),
+	)
+	require.NoError(err)
+
+	g := genesis.Default
+	g.KamchatkaBlockHeight = 1
+	governor := identityset.Address(10)
+	cfg := g.Staking
+	cfg.VoteWeightGovernor = governor.String()
+	p, err := NewProtocol(depositGas, cfg, nil, g.GreenlandBlockHeight)
+	require.NoError(err)
+
+	cfg.VoteWeightGovernor = "invalid"
+	_, err = NewProtocol(depositGas, cfg, nil, g.GreenlandBlockHeight)
+	require.Error(err)
+
+	candidate := testCandidates[0].d.Clone()
+	require.NoError(putCandidate(sm, candidate))
+	start := time.Unix(1612345678, 0)
+	_, err = putBucketAndIndex(sm, NewVoteBucket(candidate.Owner, identityset.Address(2), big.NewInt(10000), 7, start, false))
+	require.NoError(err)
+
+	registry := protocol.NewRegistry()
+	require.NoError(rolldpos.NewProtocol(4, 4, 2).Register(registry))
+	ctx := protocol.WithRegistry(
+		protocol.WithBlockchainCtx(context.Background(), protocol.BlockchainCtx{Genesis: g}),
+		registry,
+	)
+	v, err := p.Start(ctx, sm)
+	require.NoError(err)
+	require.NoError(sm.WriteView(ProtocolID, v))
+
+	votes := func() int64 {
+		csm, err := NewCandidateStateManager(sm, false)
+		require.NoError(err)
+		return csm.GetByOwner(candidate.Owner).Votes.Int64()
+	}
+	createPreStates := func(height uint64) {
+		require.NoError(p.CreatePreStates(protocol.WithBlockCtx(ctx, protocol.BlockCtx{
+			BlockHeight:    height,
+			BlockTimeStamp: start,
+		}), sm))
+	}
+
+	// the votes are recalculated with the curve in genesis at Kamchatka height
+	createPreStates(1)
+	require.EqualValues(10760, votes())
+	active, err := getVoteWeightCurve(sm, voteWeightCurveKey)
+	require.NoError(err)
+	require.Equal(p.config.VoteWeightCurve, *active)
+
+	curve := VoteWeightCurve{StalenessPeriod: 24 * time.Hour, StalenessDecay: 100, MaxStalenessDecay: 1000}
+	data, err := curve.Serialize()
+	require.NoError(err)
+	invalid, err := (&VoteWeightCurve{DurationBonus: 10001}).Serialize()
+	require.NoError(err)
+	tests := []struct {
+		caller   int
+		contract string
+		amount   *big.Int
+		data     []byte
+		status   iotextypes.ReceiptStatus
+	}{
+		{2, p.addr.String(), big.NewInt(0), data, iotextypes.ReceiptStatus_ErrUnauthorizedOperator},
+		{10, p.addr.String(), big.NewInt(1), data, iotextypes.ReceiptStatus_Failure},
+		{10, p.addr.String(), big.NewInt(0), data[8:], iotextypes.ReceiptStatus_Failure},
+		{10, p.addr.String(), big.NewInt(0), invalid, iotextypes.ReceiptStatus_Failure},
+		{10, p.addr.String(), big.NewInt(0), data, iotextypes.ReceiptStatus_Success},
+	}
+	for i, test := range tests {
+		caller := identityset.Address(test.caller)
+		require.NoError(setupAccount(sm, caller, 100))
+		ctx := protocol.WithActionCtx(ctx, protocol.ActionCtx{
+			Caller:       caller,
+			GasPrice:     big.NewInt(unit.Qev),
+			IntrinsicGas: 10000,
+			Nonce:        uint64(i + 1),
+		})
+		ctx = protocol.WithBlockCtx(ctx, protocol.BlockCtx{
+			BlockHeight:    2,
+			BlockTimeStamp: start,
+		})
+		act, err := action.NewExecution(test.contract, uint64(i+1), test.amount, 10000, big.NewInt(unit.Qev), test.data)
+		require.NoError(err)
+		r, err := p.Handle(ctx, act, sm)
+		require.NoError(err)
+		require.Equal(uint64(test.status), r.Status)
+	}
+	// not calling the staking protocol
+	act, err := action.NewExecution(identityset.Address(3).String(), 1, big.NewInt(0), 10000, big.NewInt(unit.Qev), data)
+	require.NoError(err)
+	r, err := p.Handle(protocol.WithBlockCtx(ctx, protocol.BlockCtx{BlockHeight: 2}), act, sm)
+	require.NoError(err)
+	require.Nil(r)
+
+	// the curve set takes effect since next epoch
+	pending, err := getVoteWeightCurve(sm, pendingVoteWeightCurveKey)
+	require.NoError(err)
+	require.Equal(curve, *pending)
+	createPreStates(5)
+	require.EqualValues(10760, votes())
+	createPreStates(9)
+	require.EqualValues(10000, votes())
+	active, err = getVoteWeightCurve(sm, voteWeightCurveKey)
+	require.NoError(err)
+	require.Equal(curve, *active)
+	_, err = getVoteWeightCurve(sm, pendingVoteWeightCurveKey)
+	require.Equal(state.ErrStateNotExist, errors.Cause(err))
+
+	// the weights in an epoch are calculated at the start time of the epoch, so that the votes subtracted for a bucket
+	// in the epoch are the votes added to the candidate
+	epochStart := start.Add(8 * 24 * time.Hour)
+	require.NoError(p.CreatePreStates(protocol.WithBlockCtx(ctx, protocol.BlockCtx{
+		BlockHeight:    17,
+		BlockTimeStamp: epochStart,
+	}), sm))
+	require.EqualValues(9900, votes())
+	bucket, err := getBucket(sm, 0)
+	require.NoError(err)
+	weight, err := p.calculateVoteWeight(protocol.WithBlockCtx(ctx, protocol.BlockCtx{
+		BlockHeight:    18,
+		BlockTimeStamp: epochStart.Add(3 * 24 * time.Hour),
+	}), sm, bucket, false)
+	require.NoError(err)
+	require.EqualValues(9900, weight.Int64())
+	require.EqualValues(9600, curve.Weight(bucket, false, epochStart.Add(3*24*time.Hour)).Int64())
+}
@@ -0,0 +1,412 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package subsidy
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/iotexproject/go-pkgs/hash"
+	"github.com/iotexproject/iotex-address/address"
+	"github.com/iotexproject/iotex-proto/golang/iotextypes"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/iotexproject/iotex-core/action"
+	"github.com/iotexproject/iotex-core/action/protocol"
+	accountutil "github.com/iotexproject/iotex-core/action/protocol/account/util"
+	"github.com/iotexproject/iotex-core/blockchain/genesis"
+	"github.com/iotexproject/iotex-core/pkg/log"
+	"github.com/iotexproject/iotex-core/state"
+)
+
+const (
+	// TODO: it works only for one instance per protocol definition now
+	protocolID = "subsidy"
+	// Namespace is the namespace to store the subsidies of the contracts and the usages of the callers
+	Namespace = "Subsidy"
+)
+
+type (
+	// Protocol is the registry of the contracts whose gas fee is paid by the subsidies deposited by the sponsors,
+	// up to the per-user limits. The operations on the registry are the executions to the protocol address, and the
+	// subsidies are held by the account of the protocol address
+	Protocol struct {
+		addr       address.Address
+		depositGas protocol.DepositGas
+	}
+)
+
+// NewProtocol instantiates the protocol of gas subsidy
+func NewProtocol(depositGas protocol.DepositGas) *Protocol {
+	h := hash.Hash160b([]byte(protocolID))
+	addr, err := address.FromBytes(h[:])
+	if err != nil {
+		log.L().Panic("Error when constructing the address of subsidy protocol", zap.Error(err))
+	}
+	return &Protocol{
+		addr:       addr,
+		depositGas: depositGas,
+	}
+}
+
+// FindProtocol finds the registered protocol from registry
+func FindProtocol(registry *protocol.Registry) *Protocol {
+	if registry == nil {
+		return nil
+	}
+	p, ok := registry.Find(protocolID)
+	if !ok {
+		return nil
+	}
+	sp, ok := p.(*Protocol)
+	if !ok {
+		log.S().Panic("fail to cast subsidy protocol")
+	}
+	return sp
+}
+
+// Address returns the address of the protocol, which is the contract of the executions operating the registry
+func (p *Protocol) Address() address.Address {
+	return p.addr
+}
+
+// Handle handles the operations on the registry
+func (p *Protocol) Handle(ctx context.Context, act action.Action, sm protocol.StateManager) (*action.Receipt, error) {
+	exec, ok := act.(*action.Execution)
+	if !ok || exec.Contract() != p.addr.String() || !accountutil.IsSystemExecutionActive(ctx) {
+		return nil, nil
+	}
+	si := sm.Snapshot()
+	tLog, err := p.handleOperation(ctx, exec, sm)
+	if err != nil {
+		log.L().Debug("Error when handling subsidy operation", zap.Error(err))
+		return accountutil.SettleSystemExecution(ctx, sm, p.depositGas, p.addr, uint64(iotextypes.ReceiptStatus_Failure), si, nil)
+	}
+	return accountutil.SettleSystemExecution(ctx, sm, p.depositGas, p.addr, uint64(iotextypes.ReceiptStatus_Success), si, nil, tLog)
+}
+
+// ReadState reads the subsidy of a contract, or the gas fee paid for a caller of a contract
+func (p *Protocol) ReadState(
+	ctx context.Context,
+	sr protocol.StateReader,
+	method []byte,
+	args ...[]byte,
+) ([]byte, uint64, error) {
+	switch string(method) {
+	case "Subsidy":
+		if len(args) != 1 {
+			return nil, uint64(0), errors.Wrapf(protocol.ErrInvalidArgument, "invalid number of arguments %d", len(args))
+		}
+		contract, err := address.FromString(string(args[0]))
+		if err != nil {
+			return nil, uint64(0), errors.Wrap(protocol.ErrInvalidArgument, err.Error())
+		}
+		s, height, err := p.subsidy(sr, contract)
+		if err != nil {
+			return nil, uint64(0), err
+		}
+		data, err := s.Serialize()
+		return data, height, err
+	case "Usage":
+		if len(args) != 2 {
+			return nil, uint64(0), errors.Wrapf(protocol.ErrInvalidArgument, "invalid number of arguments %d", len(args))
+		}
+		contract, err := address.FromString(string(args[0]))
+		if err != nil {
+			return nil, uint64(0), errors.Wrap(protocol.ErrInvalidArgument, err.Error())
+		}
+		caller, err := address.FromString(string(args[1]))
+		if err != nil {
+			return nil, uint64(0), errors.Wrap(protocol.ErrInvalidArgument, err.Error())
+		}
+		u, height, err := p.usage(sr, contract, caller)
+		if err != nil {
+			return nil, uint64(0), err
+		}
+		return []byte(u.String()), height, nil
+	default:
+		return nil, uint64(0), errors.Wrapf(protocol.ErrNotFound, "unknown method %s", string(method))
+	}
+}
+
+// Register registers the protocol with a unique ID
+func (p *Protocol) Register(r *protocol.Registry) error {
+	return r.Register(protocolID, p)
+}
+
+// ForceRegister registers the protocol with a unique ID and force replacing the previous protocol if it exists
+func (p *Protocol) ForceRegister(r *protocol.Registry) error {
+	return r.ForceRegister(protocolID, p)
+}
+
+// Name returns the name of protocol
+func (p *Protocol) Name() string {
+	return protocolID
+}
+
+// Prefund credits the caller of the execution with the max gas fee of the execution, if the contract is subsidized
+// and the subsidy covers it within the per-user limit. It returns the credited amount, or nil if the execution is
+// not subsidized. The caller pays the gas fee as usual, and Settle takes back the unused part of the credit
+func (p *Protocol) Prefund(ctx context.Context, sm protocol.StateManager, exec *action.Execution) (*big.Int, error) {
+	actionCtx := protocol.MustGetActionCtx(ctx)
+	maxFee, s, err := p.credit(ctx, sm, actionCtx.Caller, exec)
+	if err != nil || maxFee == nil {
+		return nil, err
+	}
+	contract, err := address.FromString(exec.Contract())
+	if err != nil {
+		return nil, err
+	}
+	s.Balance.Sub(s.Balance, maxFee)
+	if err := p.putSubsidy(sm, contract, s); err != nil {
+		return nil, err
+	}
+	if err := accountutil.Transfer(sm, p.addr, actionCtx.Caller, maxFee); err != nil {
+		return nil, err
+	}
+	return maxFee, nil
+}
+
+// PendingCredit returns the gas fee which Prefund credits to the sender of the action if it is run in the block next
+// to the state, or nil if the action is not subsidized. The actpool counts it out of the cost of the sender, so that
+// a sender without balance for the gas fee can call the subsidized contracts
+func (p *Protocol) PendingCredit(g genesis.Genesis, sr protocol.StateReader, selp action.SealedEnvelope) (*big.Int, error) {
+	exec, ok := selp.Action().(*action.Execution)
+	if !ok {
+		return nil, nil
+	}
+	caller, err := address.FromBytes(selp.SrcPubkey().Hash())
+	if err != nil {
+		return nil, err
+	}
+	height, err := sr.Height()
+	if err != nil {
+		return nil, err
+	}
+	ctx := protocol.WithBlockCtx(
+		protocol.WithBlockchainCtx(context.Background(), protocol.BlockchainCtx{Genesis: g}),
+		protocol.BlockCtx{BlockHeight: height + 1},
+	)
+	maxFee, _, err := p.credit(ctx, sr, caller, exec)
+	return maxFee, err
+}
+
+// credit returns the max gas fee of the execution and the subsidy of the contract, if the subsidy covers the fee
+// within the per-user limit of the caller
+func (p *Protocol) credit(
+	ctx context.Context,
+	sr protocol.StateReader,
+	caller address.Address,
+	exec *action.Execution,
+) (*big.Int, *Subsidy, error) {
+	if exec.Contract() == action.EmptyAddress || exec.Contract() == p.addr.String() || !accountutil.IsSystemExecutionActive(ctx) {
+		return nil, nil, nil
+	}
+	maxFee := new(big.Int).Mul(exec.GasPrice(), new(big.Int).SetUint64(exec.GasLimit()))
+	if maxFee.Sign() == 0 {
+		return nil, nil, nil
+	}
+	contract, err := address.FromString(exec.Contract())
+	if err != nil {
+		return nil, nil, err
+	}
+	s, _, err := p.subsidy(sr, contract)
+	switch errors.Cause(err) {
+	case nil:
+	case state.ErrStateNotExist:
+		return nil, nil, nil
+	default:
+		return nil, nil, err
+	}
+	if s.Balance.Cmp(maxFee) < 0 {
+		return nil, nil, nil
+	}
+	used, _, err := p.usage(sr, contract, caller)
+	if err != nil {
+		return nil, nil, err
+	}
+	if new(big.Int).Add(used, maxFee).Cmp(s.PerUserLimit) > 0 {
+		return nil, nil, nil
+	}
+	return maxFee, s, nil
+}
+
+// Settle takes back the part of the credit not consumed by the execution from the caller, and records the consumed
+// gas fee as the usage of the caller
+func (p *Protocol) Settle(
+	ctx context.Context,
+	sm protocol.StateManager,
+	exec *action.Execution,
+	credit *big.Int,
+	gasConsumed uint64,
+) (*action.TransactionLog, error) {
+	actionCtx := protocol.MustGetActionCtx(ctx)
+	contract, err := address.FromString(exec.Contract())
+	if err != nil {
+		return nil, err
+	}
+	fee := new(big.Int).Mul(exec.GasPrice(), new(big.Int).SetUint64(gasConsumed))
+	if fee.Cmp(credit) > 0 {
+		fee.Set(credit)
+	}
+	refund := new(big.Int).Sub(credit, fee)
+	if err := accountutil.Transfer(sm, actionCtx.Caller, p.addr, refund); err != nil {
+		return nil, err
+	}
+	s, _, err := p.subsidy(sm, contract)
+	if err != nil {
+		return nil, err
+	}
+	s.Balance.Add(s.Balance, refund)
+	if err := p.putSubsidy(sm, contract, s); err != nil {
+		return nil, err
+	}
+	used, _, err := p.usage(sm, contract, actionCtx.Caller)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.putUsage(sm, contract, actionCtx.Caller, used.Add(used, fee)); err != nil {
+		return nil, err
+	}
+	if fee.Sign() == 0 {
+		return nil, nil
+	}
+	return &action.TransactionLog{
+		Type:      iotextypes.TransactionLogType_NATIVE_TRANSFER,
+		Sender:    p.addr.String(),
+		Recipient: actionCtx.Caller.String(),
+		Amount:    fee,
+	}, nil
+}
+
+func (p *Protocol) handleOperation(
+	ctx context.Context,
+	exec *action.Execution,
+	sm protocol.StateManager,
+) (*action.TransactionLog, error) {
+	actionCtx := protocol.MustGetActionCtx(ctx)
+	op := &Operation{}
+	if err := op.Deserialize(exec.Data()); err != nil {
+		return nil, err
+	}
+	amount := exec.Amount()
+	if amount == nil {
+		amount = big.NewInt(0)
+	}
+	caller, err := accountutil.LoadAccount(sm, hash.BytesToHash160(actionCtx.Caller.Bytes()))
+	if err != nil {
+		return nil, err
+	}
+	gasFee := new(big.Int).Mul(actionCtx.GasPrice, new(big.Int).SetUint64(actionCtx.IntrinsicGas))
+	if new(big.Int).Add(amount, gasFee).Cmp(caller.Balance) > 0 {
+		return nil, errors.Wrapf(state.ErrNotEnoughBalance, "caller %s balance not enough", actionCtx.Caller.String())
+	}
+	s, _, err := p.subsidy(sm, op.Contract)
+	switch errors.Cause(err) {
+	case nil:
+	case state.ErrStateNotExist:
+		if op.Op != OpRegister {
+			return nil, errors.Wrapf(err, "contract %s is not registered", op.Contract.String())
+		}
+		s = &Subsidy{
+			Sponsor: actionCtx.Caller,
+			Balance: big.NewInt(0),
+		}
+	default:
+		return nil, err
+	}
+
+	switch op.Op {
+	case OpRegister:
+		if !address.Equal(s.Sponsor, actionCtx.Caller) {
+			return nil, errors.Wrapf(ErrNotSponsor, "%s is not the sponsor of contract %s", actionCtx.Caller.String(), op.Contract.String())
+		}
+		s.PerUserLimit = op.Amount
+		fallthrough
+	case OpDeposit:
+		if amount.Sign() == 0 {
+			return nil, p.putSubsidy(sm, op.Contract, s)
+		}
+		s.Balance.Add(s.Balance, amount)
+		if err := p.putSubsidy(sm, op.Contract, s); err != nil {
+			return nil, err
+		}
+		if err := accountutil.Transfer(sm, actionCtx.Caller, p.addr, amount); err != nil {
+			return nil, err
+		}
+		return &action.TransactionLog{
+			Type:      iotextypes.TransactionLogType_NATIVE_TRANSFER,
+			Sender:    actionCtx.Caller.String(),
+			Recipient: p.addr.String(),
+			Amount:    amount,
+		}, nil
+	case OpWithdraw:
+		if !address.Equal(s.Sponsor, actionCtx.Caller) {
+			return nil, errors.Wrapf(ErrNotSponsor, "%s is not the sponsor of contract %s", actionCtx.Caller.String(), op.Contract.String())
+		}
+		if amount.Sign() != 0 {
+			return nil, errors.New("withdrawing subsidy does not accept amount")
+		}
+		if op.Amount.Cmp(s.Balance) > 0 {
+			return nil, errors.Errorf("withdraw amount %s is more than subsidy %s", op.Amount.String(), s.Balance.String())
+		}
+		s.Balance.Sub(s.Balance, op.Amount)
+		if err := p.putSubsidy(sm, op.Contract, s); err != nil {
+			return nil, err
+		}
+		if err := accountutil.Transfer(sm, p.addr, actionCtx.Caller, op.Amount); err != nil {
+			return nil, err
+		}
+		return &action.TransactionLog{
+			Type:      iotextypes.TransactionLogType_NATIVE_TRANSFER,
+			Sender:    p.addr.String(),
+			Recipient: actionCtx.Caller.String(),
+			Amount:    op.Amount,
+		}, nil
+	}
+	return nil, errors.Wrapf(ErrInvalidOperation, "unknown operation %d", op.Op)
+}
+
+func (p *Protocol) subsidy(sr protocol.StateReader, contract address.Address) (*Subsidy, uint64, error) {
+	s := &Subsidy{}
+	height, err := sr.State(s, protocol.NamespaceOption(Namespace), protocol.KeyOption(contract.Bytes()))
+	if err != nil {
+		return nil, height, err
+	}
+	return s, height, nil
+}
+
+func (p *Protocol) putSubsidy(sm protocol.StateManager, contract address.Address, s *Subsidy) error {
+	_, err := sm.PutState(s, protocol.NamespaceOption(Namespace), protocol.KeyOption(contract.Bytes()))
+	return err
+}
+
+// usage returns the gas fee paid for the caller of the contract
+func (p *Protocol) usage(sr protocol.StateReader, contract, caller address.Address) (*big.Int, uint64, error) {
+	u := &usage{}
+	height, err := sr.State(u, protocol.NamespaceOption(Namespace), protocol.KeyOption(usageKey(contract, caller)))
+	switch errors.Cause(err) {
+	case nil:
+		return u.amount, height, nil
+	case state.ErrStateNotExist:
+		return big.NewInt(0), height, nil
+	default:
+		return nil, height, err
+	}
+}
+
+func (p *Protocol) putUsage(sm protocol.StateManager, contract, caller address.Address, amount *big.Int) error {
+	_, err := sm.PutState(&usage{amount: amount}, protocol.NamespaceOption(Namespace), protocol.KeyOption(usageKey(contract, caller)))
+	return err
+}
+
+func usageKey(contract, caller address.Address) []byte {
+	key := make([]byte, 0, 2*_addressLength)
+	key = append(key, contract.Bytes()...)
+	return append(key, caller.Bytes()...)
+}
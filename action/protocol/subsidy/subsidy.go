@@ -0,0 +1,152 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package subsidy
+
+import (
+	"math/big"
+
+	"github.com/iotexproject/iotex-address/address"
+	"github.com/pkg/errors"
+)
+
+const (
+	_addressLength = 20
+	_amountLength  = 32
+	_opLength      = 1
+)
+
+// operations of the subsidy registry, the first byte of the data of the execution to the protocol address
+const (
+	// OpRegister registers the contract with the per-user limit, or updates the limit by the sponsor, depositing the
+	// amount of the execution. Data is op || contract (20 bytes) || per-user limit (32 bytes)
+	OpRegister byte = iota + 1
+	// OpDeposit deposits the amount of the execution to the subsidy of the contract. Data is op || contract (20 bytes)
+	OpDeposit
+	// OpWithdraw withdraws from the subsidy of the contract to the sponsor. Data is op || contract (20 bytes) ||
+	// amount (32 bytes)
+	OpWithdraw
+)
+
+var (
+	// ErrInvalidOperation indicates the data of the execution is not a valid operation
+	ErrInvalidOperation = errors.New("invalid subsidy operation")
+	// ErrNotSponsor indicates the caller is not the sponsor of the subsidy
+	ErrNotSponsor = errors.New("caller is not the sponsor")
+)
+
+type (
+	// Subsidy is the fund of a contract paying the gas fee of the calls to the contract
+	Subsidy struct {
+		Sponsor address.Address
+		// Balance is the fund left to pay the gas fee
+		Balance *big.Int
+		// PerUserLimit is the max gas fee paid for each caller
+		PerUserLimit *big.Int
+	}
+
+	// usage is the gas fee paid for a caller of a contract
+	usage struct {
+		amount *big.Int
+	}
+
+	// Operation is an operation on the subsidy registry
+	Operation struct {
+		Op       byte
+		Contract address.Address
+		// Amount is the per-user limit of OpRegister, or the amount of OpWithdraw
+		Amount *big.Int
+	}
+)
+
+// Serialize serializes the subsidy
+func (s *Subsidy) Serialize() ([]byte, error) {
+	data := make([]byte, 0, _addressLength+2*_amountLength)
+	data = append(data, s.Sponsor.Bytes()...)
+	data = append(data, amountBytes(s.Balance)...)
+	return append(data, amountBytes(s.PerUserLimit)...), nil
+}
+
+// Deserialize deserializes the subsidy
+func (s *Subsidy) Deserialize(data []byte) error {
+	if len(data) != _addressLength+2*_amountLength {
+		return errors.Errorf("invalid subsidy length %d", len(data))
+	}
+	sponsor, err := address.FromBytes(data[:_addressLength])
+	if err != nil {
+		return err
+	}
+	s.Sponsor = sponsor
+	data = data[_addressLength:]
+	s.Balance = new(big.Int).SetBytes(data[:_amountLength])
+	s.PerUserLimit = new(big.Int).SetBytes(data[_amountLength:])
+	return nil
+}
+
+// Serialize serializes the usage
+func (u *usage) Serialize() ([]byte, error) {
+	return amountBytes(u.amount), nil
+}
+
+// Deserialize deserializes the usage
+func (u *usage) Deserialize(data []byte) error {
+	if len(data) != _amountLength {
+		return errors.Errorf("invalid usage length %d", len(data))
+	}
+	u.amount = new(big.Int).SetBytes(data)
+	return nil
+}
+
+// Serialize serializes the operation into the data of the execution to the protocol address
+func (o *Operation) Serialize() []byte {
+	data := make([]byte, 0, _opLength+_addressLength+_amountLength)
+	data = append(data, o.Op)
+	data = append(data, o.Contract.Bytes()...)
+	if o.Op != OpDeposit {
+		data = append(data, amountBytes(o.Amount)...)
+	}
+	return data
+}
+
+// Deserialize deserializes the operation from the data of the execution to the protocol address
+func (o *Operation) Deserialize(data []byte) error {
+	if len(data) < _opLength+_addressLength {
+		return errors.Wrapf(ErrInvalidOperation, "invalid data length %d", len(data))
+	}
+	o.Op = data[0]
+	length := _opLength + _addressLength
+	switch o.Op {
+	case OpRegister, OpWithdraw:
+		length += _amountLength
+	case OpDeposit:
+	default:
+		return errors.Wrapf(ErrInvalidOperation, "unknown operation %d", o.Op)
+	}
+	if len(data) != length {
+		return errors.Wrapf(ErrInvalidOperation, "invalid data length %d of operation %d", len(data), o.Op)
+	}
+	contract, err := address.FromBytes(data[_opLength : _opLength+_addressLength])
+	if err != nil {
+		return errors.Wrap(ErrInvalidOperation, err.Error())
+	}
+	o.Contract = contract
+	o.Amount = nil
+	if o.Op != OpDeposit {
+		o.Amount = new(big.Int).SetBytes(data[_opLength+_addressLength:])
+	}
+	return nil
+}
+
+// amountBytes returns the amount in 32 bytes big endian
+func amountBytes(amount *big.Int) []byte {
+	b := make([]byte, _amountLength)
+	if amount == nil {
+		return b
+	}
+	v := amount.Bytes()
+	copy(b[_amountLength-len(v):], v)
+	return b
+}
@@ -0,0 +1,71 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package subsidy
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/test/identityset"
+)
+
+func TestSubsidySerialization(t *testing.T) {
+	require := require.New(t)
+
+	s := &Subsidy{
+		Sponsor:      identityset.Address(1),
+		Balance:      big.NewInt(1000000),
+		PerUserLimit: big.NewInt(1000),
+	}
+	data, err := s.Serialize()
+	require.NoError(err)
+	s1 := &Subsidy{}
+	require.NoError(s1.Deserialize(data))
+	require.Equal(s.Sponsor.String(), s1.Sponsor.String())
+	require.Equal(s.Balance, s1.Balance)
+	require.Equal(s.PerUserLimit, s1.PerUserLimit)
+	require.Error(s1.Deserialize(data[1:]))
+
+	u := &usage{amount: big.NewInt(10)}
+	data, err = u.Serialize()
+	require.NoError(err)
+	u1 := &usage{}
+	require.NoError(u1.Deserialize(data))
+	require.Equal(u.amount, u1.amount)
+	require.Error(u1.Deserialize(nil))
+}
+
+func TestOperation(t *testing.T) {
+	require := require.New(t)
+
+	contract := identityset.Address(2)
+	for _, op := range []*Operation{
+		{Op: OpRegister, Contract: contract, Amount: big.NewInt(1000)},
+		{Op: OpDeposit, Contract: contract},
+		{Op: OpWithdraw, Contract: contract, Amount: big.NewInt(500)},
+	} {
+		op1 := &Operation{}
+		require.NoError(op1.Deserialize(op.Serialize()))
+		require.Equal(op.Op, op1.Op)
+		require.Equal(op.Contract.String(), op1.Contract.String())
+		require.Equal(op.Amount, op1.Amount)
+	}
+
+	// invalid operations
+	data := (&Operation{Op: OpRegister, Contract: contract, Amount: big.NewInt(1)}).Serialize()
+	for _, d := range [][]byte{
+		nil,
+		data[:_opLength+_addressLength],
+		append([]byte{OpWithdraw + 1}, data[_opLength:]...),
+		append(append([]byte{}, data...), 0),
+	} {
+		require.Equal(ErrInvalidOperation, errors.Cause((&Operation{}).Deserialize(d)))
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package protocol
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/iotexproject/go-pkgs/hash"
+	"github.com/pkg/errors"
+
+	"github.com/iotexproject/iotex-core/action"
+)
+
+// SystemEvent is a typed event of a state change of the native protocols. It is emitted as a receipt log, whose
+// first topic is the topic of the event, followed by the indexed fields of the event, and whose data is the
+// serialized protobuf message of the event, so that the logs are indexed and filtered the same as the EVM logs.
+type SystemEvent string
+
+// System events and the protobuf messages they carry
+const (
+	// CandidateRegisteredEvent carries stakingpb.Candidate, indexed by the owner address
+	CandidateRegisteredEvent SystemEvent = "CandidateRegistered"
+	// BucketCreatedEvent carries stakingpb.Bucket, indexed by the bucket index and the candidate address
+	BucketCreatedEvent SystemEvent = "BucketCreated"
+	// RewardClaimedEvent carries iotextypes.TransactionLog_Transaction, indexed by the claimer address
+	RewardClaimedEvent SystemEvent = "RewardClaimed"
+	// DelegateKickedEvent carries iotextypes.ProbationCandidateList_Info, indexed by the delegate address
+	DelegateKickedEvent SystemEvent = "DelegateKicked"
+)
+
+var _systemEventTopics = func() map[hash.Hash256]SystemEvent {
+	topics := make(map[hash.Hash256]SystemEvent)
+	for _, e := range []SystemEvent{
+		CandidateRegisteredEvent,
+		BucketCreatedEvent,
+		RewardClaimedEvent,
+		DelegateKickedEvent,
+	} {
+		topics[e.Topic()] = e
+	}
+	return topics
+}()
+
+// Topic returns the first topic of the logs of the event
+func (e SystemEvent) Topic() hash.Hash256 {
+	return hash.Hash256b([]byte("SystemEvent." + string(e)))
+}
+
+// SystemEventOf returns the system event of the log, and false if the log is not a system event
+func SystemEventOf(log *action.Log) (SystemEvent, bool) {
+	if log == nil || len(log.Topics) == 0 {
+		return "", false
+	}
+	e, ok := _systemEventTopics[log.Topics[0]]
+	return e, ok
+}
+
+// NewSystemEventLog returns the receipt log of the event emitted by the protocol at the address
+func NewSystemEventLog(
+	ctx context.Context,
+	addr string,
+	e SystemEvent,
+	msg proto.Message,
+	indexed ...[]byte,
+) (*action.Log, error) {
+	data, err := proto.Marshal(msg)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to serialize %s event", e)
+	}
+	blkCtx := MustGetBlockCtx(ctx)
+	actionCtx := MustGetActionCtx(ctx)
+	topics := action.Topics{e.Topic()}
+	for _, b := range indexed {
+		topics = append(topics, hash.BytesToHash256(b))
+	}
+	return &action.Log{
+		Address:     addr,
+		Topics:      topics,
+		Data:        data,
+		BlockHeight: blkCtx.BlockHeight,
+		ActionHash:  actionCtx.ActionHash,
+	}, nil
+}
@@ -0,0 +1,62 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package protocol
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/iotexproject/go-pkgs/hash"
+	"github.com/iotexproject/iotex-proto/golang/iotextypes"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/action"
+	"github.com/iotexproject/iotex-core/test/identityset"
+)
+
+func TestSystemEventLog(t *testing.T) {
+	require := require.New(t)
+
+	ctx := WithActionCtx(context.Background(), ActionCtx{
+		ActionHash: hash.Hash256b([]byte("test-action")),
+	})
+	ctx = WithBlockCtx(ctx, BlockCtx{
+		BlockHeight: 6,
+	})
+
+	delegate := identityset.Address(1)
+	info := &iotextypes.ProbationCandidateList_Info{
+		Address: delegate.String(),
+		Count:   2,
+	}
+	log, err := NewSystemEventLog(ctx, "io1rewarding", DelegateKickedEvent, info, delegate.Bytes())
+	require.NoError(err)
+	require.Equal("io1rewarding", log.Address)
+	require.Equal(uint64(6), log.BlockHeight)
+	require.Equal(hash.Hash256b([]byte("test-action")), log.ActionHash)
+	require.Equal(action.Topics{DelegateKickedEvent.Topic(), hash.BytesToHash256(delegate.Bytes())}, log.Topics)
+	e, ok := SystemEventOf(log)
+	require.True(ok)
+	require.Equal(DelegateKickedEvent, e)
+	decoded := &iotextypes.ProbationCandidateList_Info{}
+	require.NoError(proto.Unmarshal(log.Data, decoded))
+	require.True(proto.Equal(info, decoded))
+
+	// the topics of the events are distinct
+	topics := make(map[hash.Hash256]bool)
+	for _, e := range []SystemEvent{CandidateRegisteredEvent, BucketCreatedEvent, RewardClaimedEvent, DelegateKickedEvent} {
+		require.False(topics[e.Topic()])
+		topics[e.Topic()] = true
+	}
+
+	// the logs of the staking handlers and the contracts are not system events
+	_, ok = SystemEventOf(&action.Log{Topics: action.Topics{hash.BytesToHash256([]byte("createStake"))}})
+	require.False(ok)
+	_, ok = SystemEventOf(&action.Log{})
+	require.False(ok)
+}
@@ -0,0 +1,187 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package vesting
+
+import (
+	"math/big"
+
+	"github.com/iotexproject/go-pkgs/hash"
+	"github.com/iotexproject/iotex-address/address"
+	"github.com/pkg/errors"
+
+	"github.com/iotexproject/iotex-core/pkg/util/byteutil"
+)
+
+const (
+	_addressLength = 20
+	_amountLength  = 32
+	_heightLength  = 8
+	_opLength      = 1
+	_grantLength   = 2*_addressLength + 2*_amountLength + 3*_heightLength
+)
+
+// operations of the vesting grants, the first byte of the data of the execution to the protocol address
+const (
+	// OpGrant creates a grant of the amount of the execution to the beneficiary. Data is op || beneficiary (20 bytes)
+	// || start height (8 bytes, 0 for the current height) || cliff in blocks (8 bytes) || duration in blocks (8 bytes)
+	OpGrant byte = iota + 1
+	// OpRelease releases the vested amount of the grant not released yet to the beneficiary. Data is
+	// op || grant ID (32 bytes)
+	OpRelease
+)
+
+var (
+	// ErrInvalidOperation indicates the data of the execution is not a valid operation
+	ErrInvalidOperation = errors.New("invalid vesting operation")
+)
+
+type (
+	// Grant is the amount released to the beneficiary linearly over the duration from the start height, nothing is
+	// released before the cliff
+	Grant struct {
+		Grantor     address.Address
+		Beneficiary address.Address
+		Amount      *big.Int
+		// Released is the amount released to the beneficiary
+		Released *big.Int
+		Start    uint64
+		Cliff    uint64
+		Duration uint64
+	}
+
+	// Operation is an operation on the vesting grants
+	Operation struct {
+		Op          byte
+		Beneficiary address.Address
+		Start       uint64
+		Cliff       uint64
+		Duration    uint64
+		GrantID     hash.Hash256
+	}
+)
+
+// GrantID returns the ID of the grant created by the grantor with the nonce of the execution
+func GrantID(grantor address.Address, nonce uint64) hash.Hash256 {
+	return hash.Hash256b(append(grantor.Bytes(), byteutil.Uint64ToBytesBigEndian(nonce)...))
+}
+
+// Vested returns the amount vested at the height
+func (g *Grant) Vested(height uint64) *big.Int {
+	switch {
+	case height < g.Start+g.Cliff:
+		return big.NewInt(0)
+	case height >= g.Start+g.Duration:
+		return new(big.Int).Set(g.Amount)
+	default:
+		vested := new(big.Int).Mul(g.Amount, new(big.Int).SetUint64(height-g.Start))
+		return vested.Div(vested, new(big.Int).SetUint64(g.Duration))
+	}
+}
+
+// Releasable returns the amount vested at the height and not released yet
+func (g *Grant) Releasable(height uint64) *big.Int {
+	releasable := new(big.Int).Sub(g.Vested(height), g.Released)
+	if releasable.Sign() < 0 {
+		return big.NewInt(0)
+	}
+	return releasable
+}
+
+// Serialize serializes the grant
+func (g *Grant) Serialize() ([]byte, error) {
+	data := make([]byte, 0, _grantLength)
+	data = append(data, g.Grantor.Bytes()...)
+	data = append(data, g.Beneficiary.Bytes()...)
+	data = append(data, amountBytes(g.Amount)...)
+	data = append(data, amountBytes(g.Released)...)
+	data = append(data, byteutil.Uint64ToBytesBigEndian(g.Start)...)
+	data = append(data, byteutil.Uint64ToBytesBigEndian(g.Cliff)...)
+	return append(data, byteutil.Uint64ToBytesBigEndian(g.Duration)...), nil
+}
+
+// Deserialize deserializes the grant
+func (g *Grant) Deserialize(data []byte) error {
+	if len(data) != _grantLength {
+		return errors.Errorf("invalid grant length %d", len(data))
+	}
+	grantor, err := address.FromBytes(data[:_addressLength])
+	if err != nil {
+		return err
+	}
+	data = data[_addressLength:]
+	beneficiary, err := address.FromBytes(data[:_addressLength])
+	if err != nil {
+		return err
+	}
+	data = data[_addressLength:]
+	g.Grantor = grantor
+	g.Beneficiary = beneficiary
+	g.Amount = new(big.Int).SetBytes(data[:_amountLength])
+	data = data[_amountLength:]
+	g.Released = new(big.Int).SetBytes(data[:_amountLength])
+	data = data[_amountLength:]
+	g.Start = byteutil.BytesToUint64BigEndian(data[:_heightLength])
+	g.Cliff = byteutil.BytesToUint64BigEndian(data[_heightLength : 2*_heightLength])
+	g.Duration = byteutil.BytesToUint64BigEndian(data[2*_heightLength:])
+	return nil
+}
+
+// Serialize serializes the operation into the data of the execution to the protocol address
+func (o *Operation) Serialize() []byte {
+	data := []byte{o.Op}
+	if o.Op != OpGrant {
+		return append(data, o.GrantID[:]...)
+	}
+	data = append(data, o.Beneficiary.Bytes()...)
+	data = append(data, byteutil.Uint64ToBytesBigEndian(o.Start)...)
+	data = append(data, byteutil.Uint64ToBytesBigEndian(o.Cliff)...)
+	return append(data, byteutil.Uint64ToBytesBigEndian(o.Duration)...)
+}
+
+// Deserialize deserializes the operation from the data of the execution to the protocol address
+func (o *Operation) Deserialize(data []byte) error {
+	if len(data) < _opLength {
+		return errors.Wrap(ErrInvalidOperation, "empty data")
+	}
+	*o = Operation{Op: data[0]}
+	data = data[_opLength:]
+	switch o.Op {
+	case OpGrant:
+		if len(data) != _addressLength+3*_heightLength {
+			return errors.Wrapf(ErrInvalidOperation, "invalid data length %d of operation %d", len(data), o.Op)
+		}
+		beneficiary, err := address.FromBytes(data[:_addressLength])
+		if err != nil {
+			return errors.Wrap(ErrInvalidOperation, err.Error())
+		}
+		o.Beneficiary = beneficiary
+		data = data[_addressLength:]
+		o.Start = byteutil.BytesToUint64BigEndian(data[:_heightLength])
+		o.Cliff = byteutil.BytesToUint64BigEndian(data[_heightLength : 2*_heightLength])
+		o.Duration = byteutil.BytesToUint64BigEndian(data[2*_heightLength:])
+		return nil
+	case OpRelease:
+		if len(data) != len(o.GrantID) {
+			return errors.Wrapf(ErrInvalidOperation, "invalid data length %d of operation %d", len(data), o.Op)
+		}
+		o.GrantID = hash.BytesToHash256(data)
+		return nil
+	default:
+		return errors.Wrapf(ErrInvalidOperation, "unknown operation %d", o.Op)
+	}
+}
+
+// amountBytes returns the amount in 32 bytes big endian
+func amountBytes(amount *big.Int) []byte {
+	b := make([]byte, _amountLength)
+	if amount == nil {
+		return b
+	}
+	v := amount.Bytes()
+	copy(b[_amountLength-len(v):], v)
+	return b
+}
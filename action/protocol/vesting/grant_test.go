@@ -0,0 +1,92 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package vesting
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/test/identityset"
+)
+
+func TestGrant(t *testing.T) {
+	require := require.New(t)
+
+	g := &Grant{
+		Grantor:     identityset.Address(1),
+		Beneficiary: identityset.Address(2),
+		Amount:      big.NewInt(1000),
+		Released:    big.NewInt(100),
+		Start:       100,
+		Cliff:       20,
+		Duration:    100,
+	}
+	for _, v := range []struct {
+		height     uint64
+		vested     int64
+		releasable int64
+	}{
+		{0, 0, 0},
+		{119, 0, 0},
+		{120, 200, 100},
+		{150, 500, 400},
+		{199, 990, 890},
+		{200, 1000, 900},
+		{1000, 1000, 900},
+	} {
+		require.Equal(big.NewInt(v.vested), g.Vested(v.height))
+		require.Equal(big.NewInt(v.releasable), g.Releasable(v.height))
+	}
+
+	data, err := g.Serialize()
+	require.NoError(err)
+	g1 := &Grant{}
+	require.NoError(g1.Deserialize(data))
+	require.Equal(g.Grantor.String(), g1.Grantor.String())
+	require.Equal(g.Beneficiary.String(), g1.Beneficiary.String())
+	require.Equal(g.Amount, g1.Amount)
+	require.Equal(g.Released, g1.Released)
+	require.Equal(g.Start, g1.Start)
+	require.Equal(g.Cliff, g1.Cliff)
+	require.Equal(g.Duration, g1.Duration)
+	require.Error(g1.Deserialize(data[1:]))
+}
+
+func TestOperation(t *testing.T) {
+	require := require.New(t)
+
+	id := GrantID(identityset.Address(1), 1)
+	for _, op := range []*Operation{
+		{Op: OpGrant, Beneficiary: identityset.Address(2), Start: 10, Cliff: 5, Duration: 100},
+		{Op: OpRelease, GrantID: id},
+	} {
+		op1 := &Operation{}
+		require.NoError(op1.Deserialize(op.Serialize()))
+		require.Equal(op.Op, op1.Op)
+		require.Equal(op.Start, op1.Start)
+		require.Equal(op.Cliff, op1.Cliff)
+		require.Equal(op.Duration, op1.Duration)
+		require.Equal(op.GrantID, op1.GrantID)
+		if op.Beneficiary != nil {
+			require.Equal(op.Beneficiary.String(), op1.Beneficiary.String())
+		}
+	}
+
+	// invalid operations
+	data := (&Operation{Op: OpRelease, GrantID: id}).Serialize()
+	for _, d := range [][]byte{
+		nil,
+		data[:len(data)-1],
+		append([]byte{OpGrant}, data[_opLength:]...),
+		append([]byte{OpRelease + 1}, data[_opLength:]...),
+	} {
+		require.Equal(ErrInvalidOperation, errors.Cause((&Operation{}).Deserialize(d)))
+	}
+}
@@ -0,0 +1,242 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package vesting
+
+import (
+	"context"
+	"encoding/hex"
+	"math/big"
+
+	"github.com/iotexproject/go-pkgs/hash"
+	"github.com/iotexproject/iotex-address/address"
+	"github.com/iotexproject/iotex-proto/golang/iotextypes"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/iotexproject/iotex-core/action"
+	"github.com/iotexproject/iotex-core/action/protocol"
+	accountutil "github.com/iotexproject/iotex-core/action/protocol/account/util"
+	"github.com/iotexproject/iotex-core/pkg/log"
+	"github.com/iotexproject/iotex-core/state"
+)
+
+const (
+	// TODO: it works only for one instance per protocol definition now
+	protocolID = "vesting"
+	// Namespace is the namespace to store the vesting grants
+	Namespace = "Vesting"
+)
+
+type (
+	// Protocol is the vesting grants released to the beneficiaries by the schedules. The operations on the grants are
+	// the executions to the protocol address, and the amounts not released yet are held by the account of the protocol
+	// address, so they cannot be spent by the beneficiaries in any way before being released
+	Protocol struct {
+		addr       address.Address
+		depositGas protocol.DepositGas
+	}
+)
+
+// NewProtocol instantiates the protocol of vesting
+func NewProtocol(depositGas protocol.DepositGas) *Protocol {
+	h := hash.Hash160b([]byte(protocolID))
+	addr, err := address.FromBytes(h[:])
+	if err != nil {
+		log.L().Panic("Error when constructing the address of vesting protocol", zap.Error(err))
+	}
+	return &Protocol{
+		addr:       addr,
+		depositGas: depositGas,
+	}
+}
+
+// FindProtocol finds the registered protocol from registry
+func FindProtocol(registry *protocol.Registry) *Protocol {
+	if registry == nil {
+		return nil
+	}
+	p, ok := registry.Find(protocolID)
+	if !ok {
+		return nil
+	}
+	pp, ok := p.(*Protocol)
+	if !ok {
+		log.S().Panic("fail to cast vesting protocol")
+	}
+	return pp
+}
+
+// Address returns the address of the protocol, which is the contract of the executions operating the grants
+func (p *Protocol) Address() address.Address {
+	return p.addr
+}
+
+// Handle handles the operations on the vesting grants
+func (p *Protocol) Handle(ctx context.Context, act action.Action, sm protocol.StateManager) (*action.Receipt, error) {
+	exec, ok := act.(*action.Execution)
+	if !ok || exec.Contract() != p.addr.String() || !accountutil.IsSystemExecutionActive(ctx) {
+		return nil, nil
+	}
+	si := sm.Snapshot()
+	tLog, err := p.handleOperation(ctx, exec, sm)
+	if err != nil {
+		log.L().Debug("Error when handling vesting operation", zap.Error(err))
+		return accountutil.SettleSystemExecution(ctx, sm, p.depositGas, p.addr, uint64(iotextypes.ReceiptStatus_Failure), si, nil)
+	}
+	return accountutil.SettleSystemExecution(ctx, sm, p.depositGas, p.addr, uint64(iotextypes.ReceiptStatus_Success), si, nil, tLog)
+}
+
+// ReadState reads the vesting grant, or the amount releasable at the height read, of the hex encoded grant ID
+func (p *Protocol) ReadState(
+	ctx context.Context,
+	sr protocol.StateReader,
+	method []byte,
+	args ...[]byte,
+) ([]byte, uint64, error) {
+	switch string(method) {
+	case "Grant", "Releasable":
+		if len(args) != 1 {
+			return nil, uint64(0), errors.Wrapf(protocol.ErrInvalidArgument, "invalid number of arguments %d", len(args))
+		}
+		id, err := hex.DecodeString(string(args[0]))
+		if err != nil {
+			return nil, uint64(0), errors.Wrap(protocol.ErrInvalidArgument, err.Error())
+		}
+		g, height, err := p.grant(sr, hash.BytesToHash256(id))
+		if err != nil {
+			return nil, uint64(0), err
+		}
+		if string(method) == "Releasable" {
+			return []byte(g.Releasable(height).String()), height, nil
+		}
+		data, err := g.Serialize()
+		return data, height, err
+	default:
+		return nil, uint64(0), errors.Wrapf(protocol.ErrNotFound, "unknown method %s", string(method))
+	}
+}
+
+// Register registers the protocol with a unique ID
+func (p *Protocol) Register(r *protocol.Registry) error {
+	return r.Register(protocolID, p)
+}
+
+// ForceRegister registers the protocol with a unique ID and force replacing the previous protocol if it exists
+func (p *Protocol) ForceRegister(r *protocol.Registry) error {
+	return r.ForceRegister(protocolID, p)
+}
+
+// Name returns the name of protocol
+func (p *Protocol) Name() string {
+	return protocolID
+}
+
+func (p *Protocol) handleOperation(
+	ctx context.Context,
+	exec *action.Execution,
+	sm protocol.StateManager,
+) (*action.TransactionLog, error) {
+	actionCtx := protocol.MustGetActionCtx(ctx)
+	blkCtx := protocol.MustGetBlockCtx(ctx)
+	op := &Operation{}
+	if err := op.Deserialize(exec.Data()); err != nil {
+		return nil, err
+	}
+	amount := exec.Amount()
+	if amount == nil {
+		amount = big.NewInt(0)
+	}
+	if op.Op != OpGrant && amount.Sign() != 0 {
+		return nil, errors.Wrapf(ErrInvalidOperation, "operation %d does not accept amount", op.Op)
+	}
+	caller, err := accountutil.LoadAccount(sm, hash.BytesToHash160(actionCtx.Caller.Bytes()))
+	if err != nil {
+		return nil, err
+	}
+	gasFee := new(big.Int).Mul(actionCtx.GasPrice, new(big.Int).SetUint64(actionCtx.IntrinsicGas))
+	if new(big.Int).Add(amount, gasFee).Cmp(caller.Balance) > 0 {
+		return nil, errors.Wrapf(state.ErrNotEnoughBalance, "caller %s balance not enough", actionCtx.Caller.String())
+	}
+
+	switch op.Op {
+	case OpGrant:
+		if amount.Sign() == 0 {
+			return nil, errors.Wrap(ErrInvalidOperation, "grant amount is 0")
+		}
+		if op.Duration == 0 || op.Cliff > op.Duration {
+			return nil, errors.Wrapf(ErrInvalidOperation, "invalid cliff %d and duration %d", op.Cliff, op.Duration)
+		}
+		if op.Start == 0 {
+			op.Start = blkCtx.BlockHeight
+		}
+		g := &Grant{
+			Grantor:     actionCtx.Caller,
+			Beneficiary: op.Beneficiary,
+			Amount:      amount,
+			Released:    big.NewInt(0),
+			Start:       op.Start,
+			Cliff:       op.Cliff,
+			Duration:    op.Duration,
+		}
+		if err := p.putGrant(sm, GrantID(actionCtx.Caller, actionCtx.Nonce), g); err != nil {
+			return nil, err
+		}
+		if err := accountutil.Transfer(sm, actionCtx.Caller, p.addr, amount); err != nil {
+			return nil, err
+		}
+		return &action.TransactionLog{
+			Type:      iotextypes.TransactionLogType_NATIVE_TRANSFER,
+			Sender:    actionCtx.Caller.String(),
+			Recipient: p.addr.String(),
+			Amount:    amount,
+		}, nil
+	case OpRelease:
+		g, _, err := p.grant(sm, op.GrantID)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get grant %x", op.GrantID)
+		}
+		if !address.Equal(g.Beneficiary, actionCtx.Caller) {
+			return nil, errors.Wrapf(ErrInvalidOperation, "%s is not the beneficiary of grant %x", actionCtx.Caller.String(), op.GrantID)
+		}
+		releasable := g.Releasable(blkCtx.BlockHeight)
+		if releasable.Sign() == 0 {
+			return nil, errors.Wrapf(ErrInvalidOperation, "nothing to release of grant %x", op.GrantID)
+		}
+		g.Released.Add(g.Released, releasable)
+		if g.Released.Cmp(g.Amount) == 0 {
+			if _, err := sm.DelState(protocol.NamespaceOption(Namespace), protocol.KeyOption(op.GrantID[:])); err != nil {
+				return nil, err
+			}
+		} else if err := p.putGrant(sm, op.GrantID, g); err != nil {
+			return nil, err
+		}
+		if err := accountutil.Transfer(sm, p.addr, actionCtx.Caller, releasable); err != nil {
+			return nil, err
+		}
+		return &action.TransactionLog{
+			Type:      iotextypes.TransactionLogType_NATIVE_TRANSFER,
+			Sender:    p.addr.String(),
+			Recipient: actionCtx.Caller.String(),
+			Amount:    releasable,
+		}, nil
+	}
+	return nil, errors.Wrapf(ErrInvalidOperation, "unknown operation %d", op.Op)
+}
+
+func (p *Protocol) grant(sr protocol.StateReader, id hash.Hash256) (*Grant, uint64, error) {
+	g := &Grant{}
+	height, err := sr.State(g, protocol.NamespaceOption(Namespace), protocol.KeyOption(id[:]))
+	if err != nil {
+		return nil, height, err
+	}
+	return g, height, nil
+}
+
+func (p *Protocol) putGrant(sm protocol.StateManager, id hash.Hash256, g *Grant) error {
+	_, err := sm.PutState(g, protocol.NamespaceOption(Namespace), protocol.KeyOption(id[:]))
+	return err
+}
@@ -41,6 +41,9 @@ const NxtProbationKey = "NextKickoutKey."
 // UnproductiveDelegateKey is the key of unproductive Delegate struct
 const UnproductiveDelegateKey = "UnproductiveDelegateKey."
 
+// JailListKey is the key of the jail list
+const JailListKey = "JailListKey."
+
 // CandidatesFromDB returns array of Candidates in candidate pool of a given height or current epoch
 func CandidatesFromDB(sr protocol.StateReader, height uint64, beforeEaster bool, epochStartPoint bool) ([]*state.Candidate, uint64, error) {
 	var candidates state.CandidateList
@@ -134,6 +137,23 @@ func UnproductiveDelegateFromDB(sr protocol.StateReader) (*vote.UnproductiveDele
 	return nil, err
 }
 
+// JailListFromDB returns the jail list, which is empty if no delegate is ever jailed
+func JailListFromDB(sr protocol.StateReader) (*vote.JailList, error) {
+	jl := vote.NewJailList()
+	jlKey := ConstructKey(JailListKey)
+	_, err := sr.State(
+		jl,
+		protocol.KeyOption(jlKey[:]),
+		protocol.NamespaceOption(protocol.SystemNamespace),
+	)
+	switch errors.Cause(err) {
+	case nil, state.ErrStateNotExist:
+		return jl, nil
+	default:
+		return nil, errors.Wrap(err, "failed to get jail list")
+	}
+}
+
 // ConstructLegacyKey constructs a key for candidates storage (deprecated version)
 func ConstructLegacyKey(height uint64) hash.Hash160 {
 	heightInBytes := byteutil.Uint64ToBytes(height)
@@ -0,0 +1,98 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package vote
+
+import (
+	"sort"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+
+	"github.com/iotexproject/iotex-core/action/protocol/vote/probationlistpb"
+)
+
+type (
+	// JailTerm is the range of epochs [From, To] a delegate is jailed in
+	JailTerm struct {
+		From uint64
+		To   uint64
+	}
+
+	// JailList is the delegates hard kicked out for misbehaving, such as double signing. Unlike the probation list
+	// recalculated every epoch, a delegate stays in it with zero voting power until the end of its jail term
+	JailList struct {
+		Terms map[string]JailTerm
+	}
+)
+
+// NewJailList returns an empty jail list
+func NewJailList() *JailList {
+	return &JailList{
+		Terms: make(map[string]JailTerm),
+	}
+}
+
+// Jailed returns true if the delegate is jailed in the epoch
+func (jl *JailList) Jailed(addr string, epochNum uint64) bool {
+	term, ok := jl.Terms[addr]
+	return ok && term.From <= epochNum && epochNum <= term.To
+}
+
+// Jail jails the delegate in epochs [from, to], returns false if the delegate is already jailed till epoch to
+func (jl *JailList) Jail(addr string, from, to uint64) bool {
+	term, ok := jl.Terms[addr]
+	if ok && term.To >= to {
+		return false
+	}
+	if ok && term.To >= from {
+		// extend the current term
+		from = term.From
+	}
+	jl.Terms[addr] = JailTerm{From: from, To: to}
+	return true
+}
+
+// Release removes the delegates whose jail terms end before the epoch
+func (jl *JailList) Release(epochNum uint64) {
+	for addr, term := range jl.Terms {
+		if term.To < epochNum {
+			delete(jl.Terms, addr)
+		}
+	}
+}
+
+// Serialize serializes the jail list into bytes
+func (jl *JailList) Serialize() ([]byte, error) {
+	addrs := make([]string, 0, len(jl.Terms))
+	for addr := range jl.Terms {
+		addrs = append(addrs, addr)
+	}
+	sort.Strings(addrs)
+	jlpb := &probationlistpb.JailList{}
+	for _, addr := range addrs {
+		term := jl.Terms[addr]
+		jlpb.Delegates = append(jlpb.Delegates, &probationlistpb.JailedDelegate{
+			Address:   addr,
+			FromEpoch: term.From,
+			ToEpoch:   term.To,
+		})
+	}
+	return proto.Marshal(jlpb)
+}
+
+// Deserialize deserializes bytes into the jail list
+func (jl *JailList) Deserialize(buf []byte) error {
+	jlpb := &probationlistpb.JailList{}
+	if err := proto.Unmarshal(buf, jlpb); err != nil {
+		return errors.Wrap(err, "failed to unmarshal jail list")
+	}
+	jl.Terms = make(map[string]JailTerm, len(jlpb.Delegates))
+	for _, d := range jlpb.Delegates {
+		jl.Terms[d.Address] = JailTerm{From: d.FromEpoch, To: d.ToEpoch}
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package vote
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestJailList(t *testing.T) {
+	r := require.New(t)
+	jl := NewJailList()
+	r.True(jl.Jail("addr1", 3, 5))
+	r.False(jl.Jailed("addr1", 2))
+	r.True(jl.Jailed("addr1", 3))
+	r.True(jl.Jailed("addr1", 5))
+	r.False(jl.Jailed("addr1", 6))
+	r.False(jl.Jailed("addr2", 3))
+
+	// jailed again within the term
+	r.False(jl.Jail("addr1", 4, 5))
+	r.True(jl.Jail("addr1", 4, 7))
+	r.Equal(JailTerm{From: 3, To: 7}, jl.Terms["addr1"])
+	r.True(jl.Jail("addr2", 4, 4))
+
+	sbytes, err := jl.Serialize()
+	r.NoError(err)
+	jl2 := &JailList{}
+	r.NoError(jl2.Deserialize(sbytes))
+	r.Equal(jl.Terms, jl2.Terms)
+
+	jl.Release(5)
+	r.Equal(1, len(jl.Terms))
+	r.True(jl.Jailed("addr1", 5))
+}
@@ -13,8 +13,14 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/iotexproject/iotex-proto/golang/iotextypes"
+
+	"github.com/iotexproject/iotex-core/action/protocol/vote/probationlistpb"
+	"github.com/iotexproject/iotex-core/state"
 )
 
+// ProbationListVersion is the current schema version of versioned probation list encoding
+const ProbationListVersion uint32 = 1
+
 //ProbationList defines a map where key is candidate's name and value is the counter which counts the unproductivity during probation epoch.
 type ProbationList struct {
 	ProbationInfo map[string]uint32
@@ -34,6 +40,15 @@ func (pl *ProbationList) Serialize() ([]byte, error) {
 	return proto.Marshal(pl.Proto())
 }
 
+// SerializeVersioned serializes map of ProbationList to bytes with schema version
+func (pl *ProbationList) SerializeVersioned() ([]byte, error) {
+	payload, err := pl.Serialize()
+	if err != nil {
+		return nil, err
+	}
+	return state.EncodeVersioned(ProbationListVersion, payload), nil
+}
+
 // Proto converts the ProbationList to a protobuf message
 func (pl *ProbationList) Proto() *iotextypes.ProbationCandidateList {
 	probationListPb := make([]*iotextypes.ProbationCandidateList_Info, 0, len(pl.ProbationInfo))
@@ -54,10 +69,17 @@ func (pl *ProbationList) Proto() *iotextypes.ProbationCandidateList {
 	}
 }
 
-// Deserialize deserializes bytes to delegate ProbationList
+// Deserialize deserializes bytes in either legacy or versioned encoding to delegate ProbationList
 func (pl *ProbationList) Deserialize(buf []byte) error {
+	version, payload, err := state.DecodeVersioned(buf)
+	if err != nil {
+		return errors.Wrap(err, "failed to decode probationList")
+	}
+	if version > ProbationListVersion {
+		return errors.Wrapf(state.ErrUnsupportedVersion, "probationList version %d", version)
+	}
 	ProbationList := &iotextypes.ProbationCandidateList{}
-	if err := proto.Unmarshal(buf, ProbationList); err != nil {
+	if err := proto.Unmarshal(payload, ProbationList); err != nil {
 		return errors.Wrap(err, "failed to unmarshal probationList")
 	}
 	return pl.LoadProto(ProbationList)
@@ -75,3 +97,78 @@ func (pl *ProbationList) LoadProto(probationListpb *iotextypes.ProbationCandidat
 
 	return nil
 }
+
+// ProbationListsByEpoch is the probation lists of a range of epochs, keyed by the epoch number
+type ProbationListsByEpoch map[uint64]*ProbationList
+
+// Serialize serializes the probation lists in the order of the epochs
+func (pls ProbationListsByEpoch) Serialize() ([]byte, error) {
+	epochs := make([]uint64, 0, len(pls))
+	for epoch := range pls {
+		epochs = append(epochs, epoch)
+	}
+	sort.Slice(epochs, func(i, j int) bool { return epochs[i] < epochs[j] })
+	rangepb := &probationlistpb.ProbationListRange{}
+	for _, epoch := range epochs {
+		data, err := pls[epoch].Serialize()
+		if err != nil {
+			return nil, err
+		}
+		rangepb.ProbationLists = append(rangepb.ProbationLists, &probationlistpb.EpochProbationList{
+			EpochNum:      epoch,
+			ProbationList: data,
+		})
+	}
+	return proto.Marshal(rangepb)
+}
+
+// Deserialize deserializes bytes to the probation lists
+func (pls ProbationListsByEpoch) Deserialize(buf []byte) error {
+	rangepb := &probationlistpb.ProbationListRange{}
+	if err := proto.Unmarshal(buf, rangepb); err != nil {
+		return errors.Wrap(err, "failed to unmarshal probation list range")
+	}
+	for _, plpb := range rangepb.ProbationLists {
+		pl := &ProbationList{}
+		if err := pl.Deserialize(plpb.ProbationList); err != nil {
+			return errors.Wrapf(err, "failed to deserialize probation list of epoch %d", plpb.EpochNum)
+		}
+		pls[plpb.EpochNum] = pl
+	}
+	return nil
+}
+
+// ProbationRecord is the probation count of a delegate in an epoch
+type ProbationRecord struct {
+	EpochNum uint64
+	Count    uint32
+}
+
+// ProbationHistory is the epochs a delegate has been on probation, in the order of the epochs
+type ProbationHistory []ProbationRecord
+
+// Serialize serializes the probation history into bytes
+func (ph ProbationHistory) Serialize() ([]byte, error) {
+	historypb := &probationlistpb.ProbationHistory{}
+	for _, r := range ph {
+		historypb.Records = append(historypb.Records, &probationlistpb.ProbationRecord{
+			EpochNum: r.EpochNum,
+			Count:    r.Count,
+		})
+	}
+	return proto.Marshal(historypb)
+}
+
+// Deserialize deserializes bytes to the probation history
+func (ph *ProbationHistory) Deserialize(buf []byte) error {
+	historypb := &probationlistpb.ProbationHistory{}
+	if err := proto.Unmarshal(buf, historypb); err != nil {
+		return errors.Wrap(err, "failed to unmarshal probation history")
+	}
+	history := make(ProbationHistory, 0, len(historypb.Records))
+	for _, r := range historypb.Records {
+		history = append(history, ProbationRecord{EpochNum: r.EpochNum, Count: r.Count})
+	}
+	*ph = history
+	return nil
+}
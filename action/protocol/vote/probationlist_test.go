@@ -45,3 +45,40 @@ func TestProbationListSerializeAndDeserialize(t *testing.T) {
 
 	r.True(len(probationList4.ProbationInfo) == 0)
 }
+
+func TestProbationListsByEpochSerializeAndDeserialize(t *testing.T) {
+	r := require.New(t)
+	pls := ProbationListsByEpoch{
+		3: NewProbationList(50),
+		5: NewProbationList(90),
+	}
+	pls[3].ProbationInfo["addr1"] = 1
+	pls[5].ProbationInfo["addr1"] = 2
+	pls[5].ProbationInfo["addr2"] = 1
+	sbytes, err := pls.Serialize()
+	r.NoError(err)
+
+	pls2 := make(ProbationListsByEpoch)
+	r.NoError(pls2.Deserialize(sbytes))
+	r.Equal(2, len(pls2))
+	for epoch, pl := range pls {
+		r.Equal(pl.IntensityRate, pls2[epoch].IntensityRate)
+		r.Equal(pl.ProbationInfo, pls2[epoch].ProbationInfo)
+	}
+	r.Error(pls2.Deserialize([]byte{0xff}))
+}
+
+func TestProbationHistorySerializeAndDeserialize(t *testing.T) {
+	r := require.New(t)
+	ph := ProbationHistory{
+		{EpochNum: 3, Count: 1},
+		{EpochNum: 5, Count: 2},
+	}
+	sbytes, err := ph.Serialize()
+	r.NoError(err)
+
+	var ph2 ProbationHistory
+	r.NoError(ph2.Deserialize(sbytes))
+	r.Equal(ph, ph2)
+	r.Error(ph2.Deserialize([]byte{0xff}))
+}
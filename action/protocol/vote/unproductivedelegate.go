@@ -13,13 +13,19 @@ import (
 	"github.com/pkg/errors"
 
 	updpb "github.com/iotexproject/iotex-core/action/protocol/vote/unproductivedelegatepb"
+	"github.com/iotexproject/iotex-core/state"
 )
 
+// UnproductiveDelegateVersion is the current schema version of versioned unproductive delegate encoding
+const UnproductiveDelegateVersion uint32 = 1
+
 // UnproductiveDelegate defines unproductive delegates information within probation period
 type UnproductiveDelegate struct {
 	delegatelist    [][]string
 	probationPeriod uint64
 	cacheSize       uint64
+	// firstEpochs is the epoch each delegate first appears in the active block producers
+	firstEpochs map[string]uint64
 }
 
 // NewUnproductiveDelegate creates new UnproductiveDelegate with probationperiod and cacheSize
@@ -31,6 +37,7 @@ func NewUnproductiveDelegate(probationPeriod uint64, cacheSize uint64) (*Unprodu
 		delegatelist:    make([][]string, cacheSize),
 		probationPeriod: probationPeriod,
 		cacheSize:       cacheSize,
+		firstEpochs:     make(map[string]uint64),
 	}, nil
 }
 
@@ -51,11 +58,43 @@ func (upd *UnproductiveDelegate) ReadOldestUPD() []string {
 	return upd.delegatelist[upd.probationPeriod-1]
 }
 
+// FirstEpoch returns the epoch the delegate first appears in the active block producers, false if never recorded
+func (upd *UnproductiveDelegate) FirstEpoch(addr string) (uint64, bool) {
+	epoch, ok := upd.firstEpochs[addr]
+	return epoch, ok
+}
+
+// AddFirstEpochs records the epoch as the first appearance of the delegates never recorded
+func (upd *UnproductiveDelegate) AddFirstEpochs(delegates []string, epoch uint64) {
+	if upd.firstEpochs == nil {
+		upd.firstEpochs = make(map[string]uint64)
+	}
+	for _, addr := range delegates {
+		if _, ok := upd.firstEpochs[addr]; !ok {
+			upd.firstEpochs[addr] = epoch
+		}
+	}
+}
+
+// NumFirstEpochs returns the number of delegates whose first appearance is recorded
+func (upd *UnproductiveDelegate) NumFirstEpochs() int {
+	return len(upd.firstEpochs)
+}
+
 // Serialize serializes unproductvieDelegate struct to bytes
 func (upd *UnproductiveDelegate) Serialize() ([]byte, error) {
 	return proto.Marshal(upd.Proto())
 }
 
+// SerializeVersioned serializes unproductvieDelegate struct to bytes with schema version
+func (upd *UnproductiveDelegate) SerializeVersioned() ([]byte, error) {
+	payload, err := upd.Serialize()
+	if err != nil {
+		return nil, err
+	}
+	return state.EncodeVersioned(UnproductiveDelegateVersion, payload), nil
+}
+
 // Proto converts the unproductvieDelegate struct to a protobuf message
 func (upd *UnproductiveDelegate) Proto() *updpb.UnproductiveDelegate {
 	delegatespb := make([]*updpb.Delegatelist, 0, len(upd.delegatelist))
@@ -67,17 +106,37 @@ func (upd *UnproductiveDelegate) Proto() *updpb.UnproductiveDelegate {
 		}
 		delegatespb = append(delegatespb, listpb)
 	}
+	addrs := make([]string, 0, len(upd.firstEpochs))
+	for addr := range upd.firstEpochs {
+		addrs = append(addrs, addr)
+	}
+	sort.Strings(addrs)
+	var firstpb []*updpb.FirstAppearance
+	for _, addr := range addrs {
+		firstpb = append(firstpb, &updpb.FirstAppearance{
+			Delegate: addr,
+			Epoch:    upd.firstEpochs[addr],
+		})
+	}
 	return &updpb.UnproductiveDelegate{
-		DelegateList:    delegatespb,
-		ProbationPeriod: upd.probationPeriod,
-		CacheSize:       upd.cacheSize,
+		DelegateList:     delegatespb,
+		ProbationPeriod:  upd.probationPeriod,
+		CacheSize:        upd.cacheSize,
+		FirstAppearances: firstpb,
 	}
 }
 
-// Deserialize deserializes bytes to UnproductiveDelegate struct
+// Deserialize deserializes bytes in either legacy or versioned encoding to UnproductiveDelegate struct
 func (upd *UnproductiveDelegate) Deserialize(buf []byte) error {
+	version, payload, err := state.DecodeVersioned(buf)
+	if err != nil {
+		return errors.Wrap(err, "failed to decode unproductive delegate")
+	}
+	if version > UnproductiveDelegateVersion {
+		return errors.Wrapf(state.ErrUnsupportedVersion, "unproductive delegate version %d", version)
+	}
 	unproductivedelegatePb := &updpb.UnproductiveDelegate{}
-	if err := proto.Unmarshal(buf, unproductivedelegatePb); err != nil {
+	if err := proto.Unmarshal(payload, unproductivedelegatePb); err != nil {
 		return errors.Wrap(err, "failed to unmarshal unproductive delegate")
 	}
 	return upd.LoadProto(unproductivedelegatePb)
@@ -96,6 +155,10 @@ func (upd *UnproductiveDelegate) LoadProto(updPb *updpb.UnproductiveDelegate) er
 	upd.delegatelist = delegates
 	upd.probationPeriod = updPb.ProbationPeriod
 	upd.cacheSize = updPb.CacheSize
+	upd.firstEpochs = make(map[string]uint64, len(updPb.FirstAppearances))
+	for _, first := range updPb.FirstAppearances {
+		upd.firstEpochs[first.Delegate] = first.Epoch
+	}
 
 	return nil
 }
@@ -111,6 +174,14 @@ func (upd *UnproductiveDelegate) Equal(upd2 *UnproductiveDelegate) bool {
 	if len(upd.delegatelist) != len(upd2.delegatelist) {
 		return false
 	}
+	if len(upd.firstEpochs) != len(upd2.firstEpochs) {
+		return false
+	}
+	for addr, epoch := range upd.firstEpochs {
+		if epoch2, ok := upd2.firstEpochs[addr]; !ok || epoch != epoch2 {
+			return false
+		}
+	}
 	for i, list := range upd.delegatelist {
 		for j, str := range list {
 			if str != upd2.delegatelist[i][j] {
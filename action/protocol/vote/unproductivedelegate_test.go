@@ -10,6 +10,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/state"
 )
 
 func TestUnproductiveDelegate(t *testing.T) {
@@ -47,4 +49,48 @@ func TestUnproductiveDelegate(t *testing.T) {
 	r.NoError(err)
 
 	r.True(upd.Equal(upd2))
+
+	// legacy encoding starts with cache size field, should not be taken as versioned
+	r.False(state.IsVersioned(sbytes))
+	vbytes, err := upd.SerializeVersioned()
+	r.NoError(err)
+	upd3, err := NewUnproductiveDelegate(3, 10)
+	r.NoError(err)
+	r.NoError(upd3.Deserialize(vbytes))
+	r.True(upd.Equal(upd3))
+}
+
+func TestUnproductiveDelegateFirstEpochs(t *testing.T) {
+	r := require.New(t)
+	upd, err := NewUnproductiveDelegate(2, 10)
+	r.NoError(err)
+	sbytes, err := upd.Serialize()
+	r.NoError(err)
+
+	upd.AddFirstEpochs([]string{"a", "b"}, 3)
+	upd.AddFirstEpochs([]string{"b", "c"}, 5)
+	r.Equal(3, upd.NumFirstEpochs())
+	for addr, epoch := range map[string]uint64{"a": 3, "b": 3, "c": 5} {
+		first, ok := upd.FirstEpoch(addr)
+		r.True(ok)
+		r.Equal(epoch, first)
+	}
+	_, ok := upd.FirstEpoch("d")
+	r.False(ok)
+
+	// the encoding without first appearances is unchanged
+	upd2, err := NewUnproductiveDelegate(2, 10)
+	r.NoError(err)
+	sbytes2, err := upd2.Serialize()
+	r.NoError(err)
+	r.Equal(sbytes, sbytes2)
+	r.False(upd.Equal(upd2))
+
+	sbytes, err = upd.SerializeVersioned()
+	r.NoError(err)
+	r.NoError(upd2.Deserialize(sbytes))
+	r.True(upd.Equal(upd2))
+	first, ok := upd2.FirstEpoch("c")
+	r.True(ok)
+	r.Equal(uint64(5), first)
 }
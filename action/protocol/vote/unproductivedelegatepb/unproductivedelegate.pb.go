@@ -39,9 +39,10 @@ type UnproductiveDelegate struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	CacheSize       uint64          `protobuf:"varint,1,opt,name=cacheSize,proto3" json:"cacheSize,omitempty"`
-	ProbationPeriod uint64          `protobuf:"varint,2,opt,name=probationPeriod,proto3" json:"probationPeriod,omitempty"`
-	DelegateList    []*Delegatelist `protobuf:"bytes,3,rep,name=delegateList,proto3" json:"delegateList,omitempty"`
+	CacheSize        uint64             `protobuf:"varint,1,opt,name=cacheSize,proto3" json:"cacheSize,omitempty"`
+	ProbationPeriod  uint64             `protobuf:"varint,2,opt,name=probationPeriod,proto3" json:"probationPeriod,omitempty"`
+	DelegateList     []*Delegatelist    `protobuf:"bytes,3,rep,name=delegateList,proto3" json:"delegateList,omitempty"`
+	FirstAppearances []*FirstAppearance `protobuf:"bytes,4,rep,name=firstAppearances,proto3" json:"firstAppearances,omitempty"`
 }
 
 func (x *UnproductiveDelegate) Reset() {
@@ -97,6 +98,13 @@ func (x *UnproductiveDelegate) GetDelegateList() []*Delegatelist {
 	return nil
 }
 
+func (x *UnproductiveDelegate) GetFirstAppearances() []*FirstAppearance {
+	if x != nil {
+		return x.FirstAppearances
+	}
+	return nil
+}
+
 type Delegatelist struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -144,13 +152,68 @@ func (x *Delegatelist) GetDelegates() []string {
 	return nil
 }
 
+type FirstAppearance struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Delegate string `protobuf:"bytes,1,opt,name=delegate,proto3" json:"delegate,omitempty"`
+	Epoch    uint64 `protobuf:"varint,2,opt,name=epoch,proto3" json:"epoch,omitempty"`
+}
+
+func (x *FirstAppearance) Reset() {
+	*x = FirstAppearance{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_unproductivedelegate_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FirstAppearance) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FirstAppearance) ProtoMessage() {}
+
+func (x *FirstAppearance) ProtoReflect() protoreflect.Message {
+	mi := &file_unproductivedelegate_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FirstAppearance.ProtoReflect.Descriptor instead.
+func (*FirstAppearance) Descriptor() ([]byte, []int) {
+	return file_unproductivedelegate_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *FirstAppearance) GetDelegate() string {
+	if x != nil {
+		return x.Delegate
+	}
+	return ""
+}
+
+func (x *FirstAppearance) GetEpoch() uint64 {
+	if x != nil {
+		return x.Epoch
+	}
+	return 0
+}
+
 var File_unproductivedelegate_proto protoreflect.FileDescriptor
 
 var file_unproductivedelegate_proto_rawDesc = []byte{
 	0x0a, 0x1a, 0x75, 0x6e, 0x70, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74, 0x69, 0x76, 0x65, 0x64, 0x65,
 	0x6c, 0x65, 0x67, 0x61, 0x74, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x16, 0x75, 0x6e,
 	0x70, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74, 0x69, 0x76, 0x65, 0x64, 0x65, 0x6c, 0x65, 0x67, 0x61,
-	0x74, 0x65, 0x70, 0x62, 0x22, 0xa8, 0x01, 0x0a, 0x14, 0x75, 0x6e, 0x70, 0x72, 0x6f, 0x64, 0x75,
+	0x74, 0x65, 0x70, 0x62, 0x22, 0xfd, 0x01, 0x0a, 0x14, 0x75, 0x6e, 0x70, 0x72, 0x6f, 0x64, 0x75,
 	0x63, 0x74, 0x69, 0x76, 0x65, 0x44, 0x65, 0x6c, 0x65, 0x67, 0x61, 0x74, 0x65, 0x12, 0x1c, 0x0a,
 	0x09, 0x63, 0x61, 0x63, 0x68, 0x65, 0x53, 0x69, 0x7a, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04,
 	0x52, 0x09, 0x63, 0x61, 0x63, 0x68, 0x65, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x28, 0x0a, 0x0f, 0x70,
@@ -160,11 +223,20 @@ var file_unproductivedelegate_proto_rawDesc = []byte{
 	0x65, 0x4c, 0x69, 0x73, 0x74, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x24, 0x2e, 0x75, 0x6e,
 	0x70, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74, 0x69, 0x76, 0x65, 0x64, 0x65, 0x6c, 0x65, 0x67, 0x61,
 	0x74, 0x65, 0x70, 0x62, 0x2e, 0x64, 0x65, 0x6c, 0x65, 0x67, 0x61, 0x74, 0x65, 0x6c, 0x69, 0x73,
-	0x74, 0x52, 0x0c, 0x64, 0x65, 0x6c, 0x65, 0x67, 0x61, 0x74, 0x65, 0x4c, 0x69, 0x73, 0x74, 0x22,
-	0x2c, 0x0a, 0x0c, 0x64, 0x65, 0x6c, 0x65, 0x67, 0x61, 0x74, 0x65, 0x6c, 0x69, 0x73, 0x74, 0x12,
-	0x1c, 0x0a, 0x09, 0x64, 0x65, 0x6c, 0x65, 0x67, 0x61, 0x74, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03,
-	0x28, 0x09, 0x52, 0x09, 0x64, 0x65, 0x6c, 0x65, 0x67, 0x61, 0x74, 0x65, 0x73, 0x62, 0x06, 0x70,
-	0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x74, 0x52, 0x0c, 0x64, 0x65, 0x6c, 0x65, 0x67, 0x61, 0x74, 0x65, 0x4c, 0x69, 0x73, 0x74, 0x12,
+	0x53, 0x0a, 0x10, 0x66, 0x69, 0x72, 0x73, 0x74, 0x41, 0x70, 0x70, 0x65, 0x61, 0x72, 0x61, 0x6e,
+	0x63, 0x65, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x27, 0x2e, 0x75, 0x6e, 0x70, 0x72,
+	0x6f, 0x64, 0x75, 0x63, 0x74, 0x69, 0x76, 0x65, 0x64, 0x65, 0x6c, 0x65, 0x67, 0x61, 0x74, 0x65,
+	0x70, 0x62, 0x2e, 0x66, 0x69, 0x72, 0x73, 0x74, 0x41, 0x70, 0x70, 0x65, 0x61, 0x72, 0x61, 0x6e,
+	0x63, 0x65, 0x52, 0x10, 0x66, 0x69, 0x72, 0x73, 0x74, 0x41, 0x70, 0x70, 0x65, 0x61, 0x72, 0x61,
+	0x6e, 0x63, 0x65, 0x73, 0x22, 0x2c, 0x0a, 0x0c, 0x64, 0x65, 0x6c, 0x65, 0x67, 0x61, 0x74, 0x65,
+	0x6c, 0x69, 0x73, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x64, 0x65, 0x6c, 0x65, 0x67, 0x61, 0x74, 0x65,
+	0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x09, 0x64, 0x65, 0x6c, 0x65, 0x67, 0x61, 0x74,
+	0x65, 0x73, 0x22, 0x43, 0x0a, 0x0f, 0x66, 0x69, 0x72, 0x73, 0x74, 0x41, 0x70, 0x70, 0x65, 0x61,
+	0x72, 0x61, 0x6e, 0x63, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x64, 0x65, 0x6c, 0x65, 0x67, 0x61, 0x74,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x64, 0x65, 0x6c, 0x65, 0x67, 0x61, 0x74,
+	0x65, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x70, 0x6f, 0x63, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04,
+	0x52, 0x05, 0x65, 0x70, 0x6f, 0x63, 0x68, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -179,18 +251,20 @@ func file_unproductivedelegate_proto_rawDescGZIP() []byte {
 	return file_unproductivedelegate_proto_rawDescData
 }
 
-var file_unproductivedelegate_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_unproductivedelegate_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
 var file_unproductivedelegate_proto_goTypes = []interface{}{
 	(*UnproductiveDelegate)(nil), // 0: unproductivedelegatepb.unproductiveDelegate
 	(*Delegatelist)(nil),         // 1: unproductivedelegatepb.delegatelist
+	(*FirstAppearance)(nil),      // 2: unproductivedelegatepb.firstAppearance
 }
 var file_unproductivedelegate_proto_depIdxs = []int32{
 	1, // 0: unproductivedelegatepb.unproductiveDelegate.delegateList:type_name -> unproductivedelegatepb.delegatelist
-	1, // [1:1] is the sub-list for method output_type
-	1, // [1:1] is the sub-list for method input_type
-	1, // [1:1] is the sub-list for extension type_name
-	1, // [1:1] is the sub-list for extension extendee
-	0, // [0:1] is the sub-list for field type_name
+	2, // 1: unproductivedelegatepb.unproductiveDelegate.firstAppearances:type_name -> unproductivedelegatepb.firstAppearance
+	2, // [2:2] is the sub-list for method output_type
+	2, // [2:2] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
 }
 
 func init() { file_unproductivedelegate_proto_init() }
@@ -223,6 +297,18 @@ func file_unproductivedelegate_proto_init() {
 				return nil
 			}
 		}
+		file_unproductivedelegate_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FirstAppearance); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -230,7 +316,7 @@ func file_unproductivedelegate_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_unproductivedelegate_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   2,
+			NumMessages:   3,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -0,0 +1,465 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package actionbuilder
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/iotexproject/iotex-address/address"
+
+	"github.com/iotexproject/iotex-core/action"
+)
+
+var (
+	// ErrInvalidAddress indicates the address is malformed
+	ErrInvalidAddress = errors.New("invalid address")
+	// ErrInvalidCandidateName indicates the candidate name is not 1 to 12 lowercase letters and digits
+	ErrInvalidCandidateName = errors.New("invalid candidate name")
+)
+
+type (
+	// TransferBuilder builds a transfer
+	TransferBuilder struct {
+		finisher
+		recipient string
+		amount    Amount
+		data      []byte
+	}
+
+	// ExecutionBuilder builds an execution, that is a contract call or deployment
+	ExecutionBuilder struct {
+		finisher
+		contract string
+		amount   Amount
+		data     []byte
+	}
+
+	// CreateStakeBuilder builds a stake creation
+	CreateStakeBuilder struct {
+		finisher
+		candidate string
+		amount    Amount
+		duration  uint32
+		autoStake bool
+		data      []byte
+	}
+
+	// UnstakeBuilder builds an unstake of a bucket
+	UnstakeBuilder struct {
+		finisher
+		bucket uint64
+		data   []byte
+	}
+
+	// WithdrawStakeBuilder builds a withdrawal of an unstaked bucket
+	WithdrawStakeBuilder struct {
+		finisher
+		bucket uint64
+		data   []byte
+	}
+
+	// DepositToStakeBuilder builds a deposit to a bucket
+	DepositToStakeBuilder struct {
+		finisher
+		bucket uint64
+		amount Amount
+		data   []byte
+	}
+
+	// RestakeBuilder builds a restake of a bucket
+	RestakeBuilder struct {
+		finisher
+		bucket    uint64
+		duration  uint32
+		autoStake bool
+		data      []byte
+	}
+
+	// ChangeCandidateBuilder builds a change of the candidate a bucket votes for
+	ChangeCandidateBuilder struct {
+		finisher
+		bucket    uint64
+		candidate string
+		data      []byte
+	}
+
+	// TransferStakeBuilder builds a transfer of the ownership of a bucket
+	TransferStakeBuilder struct {
+		finisher
+		bucket   uint64
+		newOwner string
+		data     []byte
+	}
+
+	// CandidateRegisterBuilder builds a candidate registration
+	CandidateRegisterBuilder struct {
+		finisher
+		name      string
+		operator  string
+		reward    string
+		owner     string
+		amount    Amount
+		duration  uint32
+		autoStake bool
+		data      []byte
+	}
+
+	// CandidateUpdateBuilder builds an update of the candidate
+	CandidateUpdateBuilder struct {
+		finisher
+		name     string
+		operator string
+		reward   string
+	}
+
+	// ClaimRewardBuilder builds a claim from the rewarding fund
+	ClaimRewardBuilder struct {
+		finisher
+		amount Amount
+		data   []byte
+	}
+
+	// DepositRewardBuilder builds a deposit to the rewarding fund
+	DepositRewardBuilder struct {
+		finisher
+		amount Amount
+		data   []byte
+	}
+)
+
+// Transfer creates a builder of a transfer of the amount to the recipient
+func (b *Builder) Transfer(recipient string, amount Amount) *TransferBuilder {
+	t := &TransferBuilder{recipient: recipient, amount: amount}
+	t.finisher = finisher{b: b, payload: t.build}
+	return t
+}
+
+// SetPayload sets the payload of the transfer
+func (t *TransferBuilder) SetPayload(data []byte) *TransferBuilder {
+	t.data = data
+	return t
+}
+
+func (t *TransferBuilder) build() (payload, error) {
+	if err := validateAddress(t.recipient); err != nil {
+		return nil, err
+	}
+	if err := t.amount.validate(); err != nil {
+		return nil, err
+	}
+	return action.NewTransfer(t.b.nonce, t.amount.Rau(), t.recipient, t.data, t.b.gasLimit, t.b.gasPrice.Rau())
+}
+
+// Execution creates a builder of a call to the contract with the amount and calldata
+func (b *Builder) Execution(contract string, amount Amount, data []byte) *ExecutionBuilder {
+	e := &ExecutionBuilder{contract: contract, amount: amount, data: data}
+	e.finisher = finisher{b: b, payload: e.build}
+	return e
+}
+
+// Deploy creates a builder of the deployment of a contract with the amount and bytecode
+func (b *Builder) Deploy(amount Amount, bytecode []byte) *ExecutionBuilder {
+	return b.Execution(action.EmptyAddress, amount, bytecode)
+}
+
+func (e *ExecutionBuilder) build() (payload, error) {
+	if e.contract != action.EmptyAddress {
+		if err := validateAddress(e.contract); err != nil {
+			return nil, err
+		}
+	} else if len(e.data) == 0 {
+		return nil, errors.New("bytecode is required for deployment")
+	}
+	if err := e.amount.validate(); err != nil {
+		return nil, err
+	}
+	return action.NewExecution(e.contract, e.b.nonce, e.amount.Rau(), e.b.gasLimit, e.b.gasPrice.Rau(), e.data)
+}
+
+// CreateStake creates a builder of a stake of the amount for the candidate, locked for duration days
+func (b *Builder) CreateStake(candidate string, amount Amount, duration uint32) *CreateStakeBuilder {
+	c := &CreateStakeBuilder{candidate: candidate, amount: amount, duration: duration}
+	c.finisher = finisher{b: b, payload: c.build}
+	return c
+}
+
+// SetAutoStake sets whether the stake duration is kept from decreasing
+func (c *CreateStakeBuilder) SetAutoStake(autoStake bool) *CreateStakeBuilder {
+	c.autoStake = autoStake
+	return c
+}
+
+// SetPayload sets the payload of the stake creation
+func (c *CreateStakeBuilder) SetPayload(data []byte) *CreateStakeBuilder {
+	c.data = data
+	return c
+}
+
+func (c *CreateStakeBuilder) build() (payload, error) {
+	if err := validateCandidateName(c.candidate); err != nil {
+		return nil, err
+	}
+	if err := validatePositive(c.amount); err != nil {
+		return nil, err
+	}
+	return action.NewCreateStake(c.b.nonce, c.candidate, c.amount.Rau().String(), c.duration, c.autoStake, c.data,
+		c.b.gasLimit, c.b.gasPrice.Rau())
+}
+
+// Unstake creates a builder of an unstake of the bucket
+func (b *Builder) Unstake(bucket uint64) *UnstakeBuilder {
+	u := &UnstakeBuilder{bucket: bucket}
+	u.finisher = finisher{b: b, payload: u.build}
+	return u
+}
+
+// SetPayload sets the payload of the unstake
+func (u *UnstakeBuilder) SetPayload(data []byte) *UnstakeBuilder {
+	u.data = data
+	return u
+}
+
+func (u *UnstakeBuilder) build() (payload, error) {
+	return action.NewUnstake(u.b.nonce, u.bucket, u.data, u.b.gasLimit, u.b.gasPrice.Rau())
+}
+
+// WithdrawStake creates a builder of a withdrawal of the unstaked bucket
+func (b *Builder) WithdrawStake(bucket uint64) *WithdrawStakeBuilder {
+	w := &WithdrawStakeBuilder{bucket: bucket}
+	w.finisher = finisher{b: b, payload: w.build}
+	return w
+}
+
+// SetPayload sets the payload of the withdrawal
+func (w *WithdrawStakeBuilder) SetPayload(data []byte) *WithdrawStakeBuilder {
+	w.data = data
+	return w
+}
+
+func (w *WithdrawStakeBuilder) build() (payload, error) {
+	return action.NewWithdrawStake(w.b.nonce, w.bucket, w.data, w.b.gasLimit, w.b.gasPrice.Rau())
+}
+
+// DepositToStake creates a builder of a deposit of the amount to the bucket
+func (b *Builder) DepositToStake(bucket uint64, amount Amount) *DepositToStakeBuilder {
+	d := &DepositToStakeBuilder{bucket: bucket, amount: amount}
+	d.finisher = finisher{b: b, payload: d.build}
+	return d
+}
+
+// SetPayload sets the payload of the deposit
+func (d *DepositToStakeBuilder) SetPayload(data []byte) *DepositToStakeBuilder {
+	d.data = data
+	return d
+}
+
+func (d *DepositToStakeBuilder) build() (payload, error) {
+	if err := validatePositive(d.amount); err != nil {
+		return nil, err
+	}
+	return action.NewDepositToStake(d.b.nonce, d.bucket, d.amount.Rau().String(), d.data, d.b.gasLimit, d.b.gasPrice.Rau())
+}
+
+// Restake creates a builder of a restake of the bucket, locked for duration days
+func (b *Builder) Restake(bucket uint64, duration uint32) *RestakeBuilder {
+	r := &RestakeBuilder{bucket: bucket, duration: duration}
+	r.finisher = finisher{b: b, payload: r.build}
+	return r
+}
+
+// SetAutoStake sets whether the stake duration is kept from decreasing
+func (r *RestakeBuilder) SetAutoStake(autoStake bool) *RestakeBuilder {
+	r.autoStake = autoStake
+	return r
+}
+
+// SetPayload sets the payload of the restake
+func (r *RestakeBuilder) SetPayload(data []byte) *RestakeBuilder {
+	r.data = data
+	return r
+}
+
+func (r *RestakeBuilder) build() (payload, error) {
+	return action.NewRestake(r.b.nonce, r.bucket, r.duration, r.autoStake, r.data, r.b.gasLimit, r.b.gasPrice.Rau())
+}
+
+// ChangeCandidate creates a builder of a change of the bucket to vote for the candidate
+func (b *Builder) ChangeCandidate(bucket uint64, candidate string) *ChangeCandidateBuilder {
+	c := &ChangeCandidateBuilder{bucket: bucket, candidate: candidate}
+	c.finisher = finisher{b: b, payload: c.build}
+	return c
+}
+
+// SetPayload sets the payload of the change
+func (c *ChangeCandidateBuilder) SetPayload(data []byte) *ChangeCandidateBuilder {
+	c.data = data
+	return c
+}
+
+func (c *ChangeCandidateBuilder) build() (payload, error) {
+	if err := validateCandidateName(c.candidate); err != nil {
+		return nil, err
+	}
+	return action.NewChangeCandidate(c.b.nonce, c.candidate, c.bucket, c.data, c.b.gasLimit, c.b.gasPrice.Rau())
+}
+
+// TransferStake creates a builder of a transfer of the bucket to the new owner
+func (b *Builder) TransferStake(bucket uint64, newOwner string) *TransferStakeBuilder {
+	t := &TransferStakeBuilder{bucket: bucket, newOwner: newOwner}
+	t.finisher = finisher{b: b, payload: t.build}
+	return t
+}
+
+// SetPayload sets the payload of the transfer
+func (t *TransferStakeBuilder) SetPayload(data []byte) *TransferStakeBuilder {
+	t.data = data
+	return t
+}
+
+func (t *TransferStakeBuilder) build() (payload, error) {
+	if err := validateAddress(t.newOwner); err != nil {
+		return nil, err
+	}
+	return action.NewTransferStake(t.b.nonce, t.newOwner, t.bucket, t.data, t.b.gasLimit, t.b.gasPrice.Rau())
+}
+
+// CandidateRegister creates a builder of the registration of a candidate, with the self-stake of the amount locked
+// for duration days
+func (b *Builder) CandidateRegister(name, operator, reward string, amount Amount, duration uint32) *CandidateRegisterBuilder {
+	c := &CandidateRegisterBuilder{name: name, operator: operator, reward: reward, amount: amount, duration: duration}
+	c.finisher = finisher{b: b, payload: c.build}
+	return c
+}
+
+// SetOwner sets the owner of the candidate, which is the sender if not set
+func (c *CandidateRegisterBuilder) SetOwner(owner string) *CandidateRegisterBuilder {
+	c.owner = owner
+	return c
+}
+
+// SetAutoStake sets whether the self-stake duration is kept from decreasing
+func (c *CandidateRegisterBuilder) SetAutoStake(autoStake bool) *CandidateRegisterBuilder {
+	c.autoStake = autoStake
+	return c
+}
+
+// SetPayload sets the payload of the registration
+func (c *CandidateRegisterBuilder) SetPayload(data []byte) *CandidateRegisterBuilder {
+	c.data = data
+	return c
+}
+
+func (c *CandidateRegisterBuilder) build() (payload, error) {
+	if err := validateCandidateName(c.name); err != nil {
+		return nil, err
+	}
+	for _, addr := range []string{c.operator, c.reward} {
+		if err := validateAddress(addr); err != nil {
+			return nil, err
+		}
+	}
+	if c.owner != "" {
+		if err := validateAddress(c.owner); err != nil {
+			return nil, err
+		}
+	}
+	if err := validatePositive(c.amount); err != nil {
+		return nil, err
+	}
+	return action.NewCandidateRegister(c.b.nonce, c.name, c.operator, c.reward, c.owner, c.amount.Rau().String(),
+		c.duration, c.autoStake, c.data, c.b.gasLimit, c.b.gasPrice.Rau())
+}
+
+// CandidateUpdate creates a builder of the update of the candidate
+func (b *Builder) CandidateUpdate(name, operator, reward string) *CandidateUpdateBuilder {
+	c := &CandidateUpdateBuilder{name: name, operator: operator, reward: reward}
+	c.finisher = finisher{b: b, payload: c.build}
+	return c
+}
+
+func (c *CandidateUpdateBuilder) build() (payload, error) {
+	if err := validateCandidateName(c.name); err != nil {
+		return nil, err
+	}
+	for _, addr := range []string{c.operator, c.reward} {
+		if err := validateAddress(addr); err != nil {
+			return nil, err
+		}
+	}
+	return action.NewCandidateUpdate(c.b.nonce, c.name, c.operator, c.reward, c.b.gasLimit, c.b.gasPrice.Rau())
+}
+
+// ClaimReward creates a builder of a claim of the amount from the rewarding fund
+func (b *Builder) ClaimReward(amount Amount) *ClaimRewardBuilder {
+	c := &ClaimRewardBuilder{amount: amount}
+	c.finisher = finisher{b: b, payload: c.build}
+	return c
+}
+
+// SetData sets the data of the claim
+func (c *ClaimRewardBuilder) SetData(data []byte) *ClaimRewardBuilder {
+	c.data = data
+	return c
+}
+
+func (c *ClaimRewardBuilder) build() (payload, error) {
+	if err := validatePositive(c.amount); err != nil {
+		return nil, err
+	}
+	cb := action.ClaimFromRewardingFundBuilder{}
+	act := cb.SetAmount(c.amount.Rau()).SetData(c.data).Build()
+	return &act, nil
+}
+
+// DepositReward creates a builder of a deposit of the amount to the rewarding fund
+func (b *Builder) DepositReward(amount Amount) *DepositRewardBuilder {
+	d := &DepositRewardBuilder{amount: amount}
+	d.finisher = finisher{b: b, payload: d.build}
+	return d
+}
+
+// SetData sets the data of the deposit
+func (d *DepositRewardBuilder) SetData(data []byte) *DepositRewardBuilder {
+	d.data = data
+	return d
+}
+
+func (d *DepositRewardBuilder) build() (payload, error) {
+	if err := validatePositive(d.amount); err != nil {
+		return nil, err
+	}
+	db := action.DepositToRewardingFundBuilder{}
+	act := db.SetAmount(d.amount.Rau()).SetData(d.data).Build()
+	return &act, nil
+}
+
+func validateAddress(addr string) error {
+	if _, err := address.FromString(addr); err != nil {
+		return errors.Wrapf(ErrInvalidAddress, "%s: %v", addr, err)
+	}
+	return nil
+}
+
+// validateCandidateName follows the rule of candidate names in the staking protocol
+func validateCandidateName(name string) error {
+	if len(name) == 0 || len(name) > 12 {
+		return errors.Wrap(ErrInvalidCandidateName, name)
+	}
+	for _, c := range name {
+		if !(('a' <= c && c <= 'z') || ('0' <= c && c <= '9')) {
+			return errors.Wrap(ErrInvalidCandidateName, name)
+		}
+	}
+	return nil
+}
+
+func validatePositive(amount Amount) error {
+	if amount.rau == nil || amount.rau.Sign() <= 0 {
+		return errors.Wrapf(ErrInvalidAmount, "amount %s is not positive", amount.Rau().String())
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package actionbuilder
+
+import (
+	"math/big"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/iotexproject/iotex-core/pkg/unit"
+)
+
+// _iotxDecimals is the number of decimals of 1 IOTX in Rau
+const _iotxDecimals = 18
+
+// ErrInvalidAmount indicates the amount is negative or malformed
+var ErrInvalidAmount = errors.New("invalid amount")
+
+// Amount is an amount of IOTX, it carries the unit so that Rau and IOTX are never mixed up
+type Amount struct {
+	rau *big.Int
+}
+
+// Rau returns the amount of n Rau
+func Rau(n *big.Int) Amount {
+	if n == nil {
+		return Amount{}
+	}
+	return Amount{rau: new(big.Int).Set(n)}
+}
+
+// GRau returns the amount of n GRau, which is the unit commonly used for gas price
+func GRau(n int64) Amount {
+	return Amount{rau: new(big.Int).Mul(big.NewInt(n), big.NewInt(unit.GRau))}
+}
+
+// Qev returns the amount of n Qev
+func Qev(n int64) Amount {
+	return Amount{rau: new(big.Int).Mul(big.NewInt(n), big.NewInt(unit.Qev))}
+}
+
+// IOTX returns the amount of n IOTX
+func IOTX(n int64) Amount {
+	return Amount{rau: unit.ConvertIotxToRau(n)}
+}
+
+// ParseIOTX parses a decimal amount of IOTX, e.g. "1.5"
+func ParseIOTX(s string) (Amount, error) {
+	parts := strings.Split(s, ".")
+	if len(parts) > 2 || parts[0] == "" {
+		return Amount{}, errors.Wrapf(ErrInvalidAmount, "malformed amount %s", s)
+	}
+	digits, decimals := parts[0], 0
+	if len(parts) == 2 {
+		if len(parts[1]) > _iotxDecimals {
+			return Amount{}, errors.Wrapf(ErrInvalidAmount, "amount %s has more than %d decimals", s, _iotxDecimals)
+		}
+		digits += parts[1]
+		decimals = len(parts[1])
+	}
+	rau, ok := new(big.Int).SetString(digits+strings.Repeat("0", _iotxDecimals-decimals), 10)
+	if !ok {
+		return Amount{}, errors.Wrapf(ErrInvalidAmount, "malformed amount %s", s)
+	}
+	if rau.Sign() < 0 {
+		return Amount{}, errors.Wrapf(ErrInvalidAmount, "negative amount %s", s)
+	}
+	return Amount{rau: rau}, nil
+}
+
+// MustParseIOTX parses a decimal amount of IOTX, and panics if the amount is invalid
+func MustParseIOTX(s string) Amount {
+	a, err := ParseIOTX(s)
+	if err != nil {
+		panic(err)
+	}
+	return a
+}
+
+// Rau returns the amount in Rau
+func (a Amount) Rau() *big.Int {
+	if a.rau == nil {
+		return big.NewInt(0)
+	}
+	return new(big.Int).Set(a.rau)
+}
+
+// IsZero returns true if the amount is zero
+func (a Amount) IsZero() bool { return a.rau == nil || a.rau.Sign() == 0 }
+
+// String returns the amount in IOTX, e.g. "1.5"
+func (a Amount) String() string {
+	rau := a.Rau()
+	if rau.Sign() < 0 {
+		return "-" + Rau(new(big.Int).Neg(rau)).String()
+	}
+	unitIOTX := new(big.Int).Exp(big.NewInt(10), big.NewInt(_iotxDecimals), nil)
+	integer, fraction := new(big.Int).DivMod(rau, unitIOTX, new(big.Int))
+	if fraction.Sign() == 0 {
+		return integer.String()
+	}
+	dec := fraction.String()
+	dec = strings.Repeat("0", _iotxDecimals-len(dec)) + dec
+	return integer.String() + "." + strings.TrimRight(dec, "0")
+}
+
+func (a Amount) validate() error {
+	if a.rau != nil && a.rau.Sign() < 0 {
+		return errors.Wrapf(ErrInvalidAmount, "negative amount %s", a.rau.String())
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package actionbuilder
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAmount(t *testing.T) {
+	require := require.New(t)
+
+	require.Equal("1000000000000000000", IOTX(1).Rau().String())
+	require.Equal("1000000000", GRau(1).Rau().String())
+	require.Equal("1000000000000", Qev(1).Rau().String())
+	require.Equal("0", Amount{}.Rau().String())
+	require.True(Amount{}.IsZero())
+	require.False(IOTX(1).IsZero())
+
+	// the amount is not affected by changes of the input or output
+	n := big.NewInt(100)
+	a := Rau(n)
+	n.SetInt64(1)
+	a.Rau().SetInt64(2)
+	require.Equal("100", a.Rau().String())
+
+	for _, c := range []struct {
+		in  string
+		rau string
+		out string
+	}{
+		{"1", "1000000000000000000", "1"},
+		{"1.5", "1500000000000000000", "1.5"},
+		{"0.000000000000000001", "1", "0.000000000000000001"},
+		{"0.10", "100000000000000000", "0.1"},
+		{"12345", "12345000000000000000000", "12345"},
+	} {
+		a, err := ParseIOTX(c.in)
+		require.NoError(err, c.in)
+		require.Equal(c.rau, a.Rau().String())
+		require.Equal(c.out, a.String())
+	}
+	for _, in := range []string{"", ".1", "1.2.3", "0.0000000000000000001", "-1", "1e18", "abc"} {
+		_, err := ParseIOTX(in)
+		require.Equal(ErrInvalidAmount, errors.Cause(err), in)
+	}
+	require.Panics(func() { MustParseIOTX("-1") })
+	require.Equal("-0.5", Rau(big.NewInt(-500000000000000000)).String())
+	require.Error(Rau(big.NewInt(-1)).validate())
+}
@@ -0,0 +1,234 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+// Package actionbuilder provides a fluent api to build and sign actions, e.g.
+//
+//	selp, err := actionbuilder.New().
+//		SetNonce(1).
+//		SetGasPrice(actionbuilder.GRau(1000)).
+//		Transfer(recipient, actionbuilder.IOTX(10)).
+//		SetPayload([]byte("hello")).
+//		Sign(sk)
+//
+// The inputs are validated when the action is built, and the gas limit defaults to the intrinsic gas of the action
+// if not set, except for executions whose gas limit should be set, e.g. to the one estimated with EstimateGas:
+//
+//	b := actionbuilder.New().SetNonce(1).SetGasPrice(actionbuilder.GRau(1000))
+//	exec := b.Execution(contract, actionbuilder.IOTX(0), data)
+//	gas, err := exec.EstimateGas(ctx, cli, sender)
+//	...
+//	b.SetGasLimit(gas)
+//	selp, err := exec.Sign(sk)
+package actionbuilder
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+
+	"github.com/iotexproject/go-pkgs/crypto"
+	"github.com/iotexproject/iotex-proto/golang/iotexapi"
+
+	"github.com/iotexproject/iotex-core/action"
+	"github.com/iotexproject/iotex-core/pkg/version"
+)
+
+// ErrGasLimitRequired indicates the gas limit of an execution is not set
+var ErrGasLimitRequired = errors.New("gas limit is required for execution")
+
+type (
+	// AccountReader reads the account, it is satisfied by iotexapi.APIServiceClient
+	AccountReader interface {
+		GetAccount(context.Context, *iotexapi.GetAccountRequest, ...grpc.CallOption) (*iotexapi.GetAccountResponse, error)
+	}
+
+	// GasEstimator suggests the gas price and estimates the gas of actions, it is satisfied by
+	// iotexapi.APIServiceClient
+	GasEstimator interface {
+		SuggestGasPrice(context.Context, *iotexapi.SuggestGasPriceRequest, ...grpc.CallOption) (*iotexapi.SuggestGasPriceResponse, error)
+		EstimateActionGasConsumption(context.Context, *iotexapi.EstimateActionGasConsumptionRequest, ...grpc.CallOption) (*iotexapi.EstimateActionGasConsumptionResponse, error)
+	}
+
+	// Builder builds the envelope of an action, the builder of each action type is created from it
+	Builder struct {
+		version  uint32
+		nonce    uint64
+		gasLimit uint64
+		gasPrice Amount
+		err      error
+	}
+
+	// payload is the method set of the action payloads accepted by the envelope
+	payload interface {
+		action.Action
+		Serialize() []byte
+		Cost() (*big.Int, error)
+		IntrinsicGas() (uint64, error)
+	}
+
+	// finisher builds and signs the action, it is embedded in the builder of each action type
+	finisher struct {
+		b       *Builder
+		payload func() (payload, error)
+	}
+)
+
+// New creates a builder of the current envelope version
+func New() *Builder {
+	return &Builder{version: version.ProtocolVersion}
+}
+
+// SetNonce sets the nonce
+func (b *Builder) SetNonce(nonce uint64) *Builder {
+	b.nonce = nonce
+	return b
+}
+
+// SetGasLimit sets the gas limit
+func (b *Builder) SetGasLimit(gasLimit uint64) *Builder {
+	b.gasLimit = gasLimit
+	return b
+}
+
+// SetGasPrice sets the gas price
+func (b *Builder) SetGasPrice(gasPrice Amount) *Builder {
+	b.gasPrice = gasPrice
+	return b
+}
+
+// FetchNonce sets the nonce to the pending nonce of the sender, the error if any is returned when the action is built
+func (b *Builder) FetchNonce(ctx context.Context, c AccountReader, sender string) *Builder {
+	nonce, err := PendingNonce(ctx, c, sender)
+	if err != nil {
+		b.fail(err)
+		return b
+	}
+	return b.SetNonce(nonce)
+}
+
+// FetchGasPrice sets the gas price to the suggested one, the error if any is returned when the action is built
+func (b *Builder) FetchGasPrice(ctx context.Context, c GasEstimator) *Builder {
+	gasPrice, err := SuggestGasPrice(ctx, c)
+	if err != nil {
+		b.fail(err)
+		return b
+	}
+	return b.SetGasPrice(gasPrice)
+}
+
+func (b *Builder) fail(err error) {
+	if b.err == nil {
+		b.err = err
+	}
+}
+
+// Build builds the envelope of the action
+func (f *finisher) Build() (action.Envelope, error) {
+	if f.b.err != nil {
+		return action.Envelope{}, f.b.err
+	}
+	if err := f.b.gasPrice.validate(); err != nil {
+		return action.Envelope{}, errors.Wrap(err, "invalid gas price")
+	}
+	act, err := f.payload()
+	if err != nil {
+		return action.Envelope{}, err
+	}
+	if err := act.SanityCheck(); err != nil {
+		return action.Envelope{}, err
+	}
+	intrinsicGas, err := act.IntrinsicGas()
+	if err != nil {
+		return action.Envelope{}, err
+	}
+	gasLimit := f.b.gasLimit
+	if gasLimit == 0 {
+		if _, ok := act.(*action.Execution); ok {
+			return action.Envelope{}, ErrGasLimitRequired
+		}
+		gasLimit = intrinsicGas
+	}
+	if gasLimit < intrinsicGas {
+		return action.Envelope{}, errors.Errorf("gas limit %d is less than the intrinsic gas %d", gasLimit, intrinsicGas)
+	}
+	return (&action.EnvelopeBuilder{}).SetVersion(f.b.version).
+		SetNonce(f.b.nonce).
+		SetGasLimit(gasLimit).
+		SetGasPrice(f.b.gasPrice.Rau()).
+		SetAction(act).Build(), nil
+}
+
+// Sign builds the action and signs it with the private key
+func (f *finisher) Sign(sk crypto.PrivateKey) (action.SealedEnvelope, error) {
+	elp, err := f.Build()
+	if err != nil {
+		return action.SealedEnvelope{}, err
+	}
+	return action.Sign(elp, sk)
+}
+
+// EstimateGas estimates the gas of the action sent by the sender, the gas limit is not required to be set
+func (f *finisher) EstimateGas(ctx context.Context, c GasEstimator, sender string) (uint64, error) {
+	if f.b.err != nil {
+		return 0, f.b.err
+	}
+	act, err := f.payload()
+	if err != nil {
+		return 0, err
+	}
+	req := &iotexapi.EstimateActionGasConsumptionRequest{CallerAddress: sender}
+	switch act := act.(type) {
+	case *action.Transfer:
+		req.Action = &iotexapi.EstimateActionGasConsumptionRequest_Transfer{Transfer: act.Proto()}
+	case *action.Execution:
+		req.Action = &iotexapi.EstimateActionGasConsumptionRequest_Execution{Execution: act.Proto()}
+	case *action.CreateStake:
+		req.Action = &iotexapi.EstimateActionGasConsumptionRequest_StakeCreate{StakeCreate: act.Proto()}
+	case *action.Unstake:
+		req.Action = &iotexapi.EstimateActionGasConsumptionRequest_StakeUnstake{StakeUnstake: act.Proto()}
+	case *action.WithdrawStake:
+		req.Action = &iotexapi.EstimateActionGasConsumptionRequest_StakeWithdraw{StakeWithdraw: act.Proto()}
+	case *action.DepositToStake:
+		req.Action = &iotexapi.EstimateActionGasConsumptionRequest_StakeAddDeposit{StakeAddDeposit: act.Proto()}
+	case *action.Restake:
+		req.Action = &iotexapi.EstimateActionGasConsumptionRequest_StakeRestake{StakeRestake: act.Proto()}
+	case *action.ChangeCandidate:
+		req.Action = &iotexapi.EstimateActionGasConsumptionRequest_StakeChangeCandidate{StakeChangeCandidate: act.Proto()}
+	case *action.TransferStake:
+		req.Action = &iotexapi.EstimateActionGasConsumptionRequest_StakeTransferOwnership{StakeTransferOwnership: act.Proto()}
+	case *action.CandidateRegister:
+		req.Action = &iotexapi.EstimateActionGasConsumptionRequest_CandidateRegister{CandidateRegister: act.Proto()}
+	case *action.CandidateUpdate:
+		req.Action = &iotexapi.EstimateActionGasConsumptionRequest_CandidateUpdate{CandidateUpdate: act.Proto()}
+	default:
+		return act.IntrinsicGas()
+	}
+	res, err := c.EstimateActionGasConsumption(ctx, req)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to estimate gas")
+	}
+	return res.GetGas(), nil
+}
+
+// PendingNonce returns the nonce for the next action of the sender
+func PendingNonce(ctx context.Context, c AccountReader, sender string) (uint64, error) {
+	res, err := c.GetAccount(ctx, &iotexapi.GetAccountRequest{Address: sender})
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to get account %s", sender)
+	}
+	return res.GetAccountMeta().GetPendingNonce(), nil
+}
+
+// SuggestGasPrice returns the suggested gas price
+func SuggestGasPrice(ctx context.Context, c GasEstimator) (Amount, error) {
+	res, err := c.SuggestGasPrice(ctx, &iotexapi.SuggestGasPriceRequest{})
+	if err != nil {
+		return Amount{}, errors.Wrap(err, "failed to suggest gas price")
+	}
+	return Rau(new(big.Int).SetUint64(res.GetGasPrice())), nil
+}
@@ -0,0 +1,169 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package actionbuilder
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	"github.com/iotexproject/iotex-proto/golang/iotexapi"
+	"github.com/iotexproject/iotex-proto/golang/iotextypes"
+
+	"github.com/iotexproject/iotex-core/action"
+	"github.com/iotexproject/iotex-core/test/identityset"
+)
+
+type fakeClient struct {
+	nonce    uint64
+	gasPrice uint64
+	gas      uint64
+	req      *iotexapi.EstimateActionGasConsumptionRequest
+	err      error
+}
+
+func (c *fakeClient) GetAccount(_ context.Context, in *iotexapi.GetAccountRequest, _ ...grpc.CallOption) (*iotexapi.GetAccountResponse, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	return &iotexapi.GetAccountResponse{AccountMeta: &iotextypes.AccountMeta{Address: in.Address, PendingNonce: c.nonce}}, nil
+}
+
+func (c *fakeClient) SuggestGasPrice(context.Context, *iotexapi.SuggestGasPriceRequest, ...grpc.CallOption) (*iotexapi.SuggestGasPriceResponse, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	return &iotexapi.SuggestGasPriceResponse{GasPrice: c.gasPrice}, nil
+}
+
+func (c *fakeClient) EstimateActionGasConsumption(_ context.Context, in *iotexapi.EstimateActionGasConsumptionRequest, _ ...grpc.CallOption) (*iotexapi.EstimateActionGasConsumptionResponse, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	c.req = in
+	return &iotexapi.EstimateActionGasConsumptionResponse{Gas: c.gas}, nil
+}
+
+func TestBuilder(t *testing.T) {
+	require := require.New(t)
+
+	sk := identityset.PrivateKey(27)
+	addr := identityset.Address(28).String()
+	b := New().SetNonce(3).SetGasPrice(GRau(1000))
+
+	selp, err := b.Transfer(addr, MustParseIOTX("1.5")).SetPayload([]byte("hi")).Sign(sk)
+	require.NoError(err)
+	require.NoError(action.Verify(selp))
+	require.Equal(uint64(3), selp.Nonce())
+	require.Equal("1000000000000", selp.GasPrice().String())
+	intrinsicGas, err := selp.IntrinsicGas()
+	require.NoError(err)
+	require.Equal(intrinsicGas, selp.GasLimit())
+	tsf, ok := selp.Action().(*action.Transfer)
+	require.True(ok)
+	require.Equal(addr, tsf.Recipient())
+	require.Equal("1500000000000000000", tsf.Amount().String())
+	require.Equal([]byte("hi"), tsf.Payload())
+
+	// every action type
+	for _, c := range []struct {
+		f interface {
+			Build() (action.Envelope, error)
+		}
+		act action.Action
+	}{
+		{b.Deploy(Amount{}, []byte{0x60, 0x80}), &action.Execution{}},
+		{b.CreateStake("robotbp00001", IOTX(100), 91).SetAutoStake(true), &action.CreateStake{}},
+		{b.Unstake(1), &action.Unstake{}},
+		{b.WithdrawStake(1), &action.WithdrawStake{}},
+		{b.DepositToStake(1, IOTX(1)), &action.DepositToStake{}},
+		{b.Restake(1, 7).SetAutoStake(true), &action.Restake{}},
+		{b.ChangeCandidate(1, "robotbp00002"), &action.ChangeCandidate{}},
+		{b.TransferStake(1, addr), &action.TransferStake{}},
+		{b.CandidateRegister("robotbp00003", addr, addr, IOTX(1200000), 91).SetOwner(addr), &action.CandidateRegister{}},
+		{b.CandidateUpdate("robotbp00003", addr, addr), &action.CandidateUpdate{}},
+		{b.ClaimReward(IOTX(1)).SetData([]byte("claim")), &action.ClaimFromRewardingFund{}},
+		{b.DepositReward(IOTX(1)), &action.DepositToRewardingFund{}},
+	} {
+		if _, ok := c.act.(*action.Execution); ok {
+			b.SetGasLimit(100000)
+		} else {
+			b.SetGasLimit(0)
+		}
+		elp, err := c.f.Build()
+		require.NoError(err)
+		require.IsType(c.act, elp.Action())
+		require.Equal(uint64(3), elp.Nonce())
+	}
+
+	// invalid inputs
+	b.SetGasLimit(0)
+	for _, c := range []struct {
+		f interface {
+			Build() (action.Envelope, error)
+		}
+		err error
+	}{
+		{b.Transfer("io1invalid", IOTX(1)), ErrInvalidAddress},
+		{b.Transfer(addr, IOTX(-1)), ErrInvalidAmount},
+		{b.Execution(addr, Amount{}, nil), ErrGasLimitRequired},
+		{b.CreateStake("Robot", IOTX(100), 91), ErrInvalidCandidateName},
+		{b.CreateStake("robotbp00001", Amount{}, 91), ErrInvalidAmount},
+		{b.ChangeCandidate(1, "robotbp000001"), ErrInvalidCandidateName},
+		{b.CandidateRegister("robotbp00003", addr, "io1invalid", IOTX(1200000), 91), ErrInvalidAddress},
+		{b.ClaimReward(Amount{}), ErrInvalidAmount},
+	} {
+		_, err := c.f.Build()
+		require.Equal(c.err, errors.Cause(err))
+	}
+	_, err = b.Deploy(Amount{}, nil).Build()
+	require.Error(err)
+	b.SetGasLimit(1)
+	_, err = b.Transfer(addr, IOTX(1)).Build()
+	require.Error(err)
+}
+
+func TestBuilderWithClient(t *testing.T) {
+	require := require.New(t)
+
+	ctx := context.Background()
+	sender := identityset.Address(27).String()
+	addr := identityset.Address(28).String()
+	c := &fakeClient{nonce: 7, gasPrice: 1000000000000, gas: 12345}
+
+	b := New().FetchNonce(ctx, c, sender).FetchGasPrice(ctx, c)
+	exec := b.Execution(addr, IOTX(1), []byte{1, 2, 3})
+	gas, err := exec.EstimateGas(ctx, c, sender)
+	require.NoError(err)
+	require.Equal(uint64(12345), gas)
+	require.Equal(sender, c.req.CallerAddress)
+	require.Equal(addr, c.req.GetExecution().GetContract())
+	b.SetGasLimit(gas)
+	selp, err := exec.Sign(identityset.PrivateKey(27))
+	require.NoError(err)
+	require.Equal(uint64(7), selp.Nonce())
+	require.Equal(uint64(12345), selp.GasLimit())
+	require.Equal("1000000000000", selp.GasPrice().String())
+
+	// the actions without api estimation use the intrinsic gas
+	gas, err = b.ClaimReward(IOTX(1)).EstimateGas(ctx, c, sender)
+	require.NoError(err)
+	require.Equal(action.ClaimFromRewardingFundBaseGas, gas)
+
+	// the error of fetching is returned when building
+	c.err = errors.New("unavailable")
+	b = New().FetchNonce(ctx, c, sender)
+	_, err = b.Transfer(addr, IOTX(1)).Build()
+	require.Error(err)
+	_, err = b.Transfer(addr, IOTX(1)).EstimateGas(ctx, c, sender)
+	require.Error(err)
+	_, err = New().FetchGasPrice(ctx, c).Unstake(1).Build()
+	require.Error(err)
+}
@@ -16,12 +16,18 @@ import (
 
 // ActionByPrice implements both the sort and the heap interface, making it useful
 // for all at once sorting as well as individually adding and removing elements.
-// It's essentially a big root heap of actions
+// It's essentially a big root heap of actions, the one with the earlier deadline wins among the same gas price
 type actionByPrice []action.SealedEnvelope
 
-func (s actionByPrice) Len() int           { return len(s) }
-func (s actionByPrice) Less(i, j int) bool { return s[i].GasPrice().Cmp(s[j].GasPrice()) > 0 }
-func (s actionByPrice) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s actionByPrice) Len() int { return len(s) }
+func (s actionByPrice) Less(i, j int) bool {
+	if c := s[i].GasPrice().Cmp(s[j].GasPrice()); c != 0 {
+		return c > 0
+	}
+	di, dj := s[i].Deadline(), s[j].Deadline()
+	return !di.IsZero() && (dj.IsZero() || di.Before(dj))
+}
+func (s actionByPrice) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
 
 // Push define the push function of heap
 func (s *actionByPrice) Push(x interface{}) {
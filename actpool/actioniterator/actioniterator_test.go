@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"math/big"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -101,6 +102,40 @@ func TestActionIterator(t *testing.T) {
 	require.Equal(appliedActionList, []action.SealedEnvelope{selp3, selp1, selp2, selp4, selp5, selp6})
 }
 
+func TestActionIteratorDeadline(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Now()
+	accMap := make(map[string][]action.SealedEnvelope)
+	var expected []action.SealedEnvelope
+	for i, deadline := range []time.Time{{}, now.Add(time.Minute), now.Add(time.Second)} {
+		priKey := identityset.PrivateKey(28 + i)
+		tsf, err := action.NewTransfer(uint64(1), big.NewInt(100), "1", nil, uint64(0), big.NewInt(10))
+		require.NoError(err)
+		bd := &action.EnvelopeBuilder{}
+		elp := bd.SetNonce(1).
+			SetGasPrice(big.NewInt(10)).
+			SetDeadline(deadline).
+			SetAction(tsf).Build()
+		selp, err := action.Sign(elp, priKey)
+		require.NoError(err)
+		accMap[identityset.Address(28+i).String()] = []action.SealedEnvelope{selp}
+		expected = append([]action.SealedEnvelope{selp}, expected...)
+	}
+
+	// among the same gas price, the action with the earlier deadline goes first
+	ai := NewActionIterator(accMap)
+	appliedActionList := make([]action.SealedEnvelope, 0)
+	for {
+		bestAction, ok := ai.Next()
+		if !ok {
+			break
+		}
+		appliedActionList = append(appliedActionList, bestAction)
+	}
+	require.Equal(expected, appliedActionList)
+}
+
 func BenchmarkLooping(b *testing.B) {
 	accMap := make(map[string][]action.SealedEnvelope)
 	for i := 0; i < b.N; i++ {
@@ -8,9 +8,11 @@ package actpool
 
 import (
 	"context"
+	"math/big"
 	"sort"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/prometheus/client_golang/prometheus"
@@ -46,6 +48,8 @@ type ActPool interface {
 	Reset()
 	// PendingActionMap returns an action map with all accepted actions
 	PendingActionMap() map[string][]action.SealedEnvelope
+	// QueuedActionMap returns an action map with the accepted actions not executable until the nonce gaps are filled
+	QueuedActionMap() map[string][]action.SealedEnvelope
 	// Add adds an action into the pool after passing validation
 	Add(ctx context.Context, act action.SealedEnvelope) error
 	// GetPendingNonce returns pending nonce in pool given an account address
@@ -88,6 +92,19 @@ func EnableExperimentalActions() Option {
 	}
 }
 
+// GasSubsidy returns the gas fee of the action paid for its sender if the action is run in the block next to the state,
+// e.g., by the subsidy of the contract it calls, or nil if none
+type GasSubsidy func(protocol.StateReader, action.SealedEnvelope) (*big.Int, error)
+
+// GasSubsidyOption counts the gas fee paid for the sender out of the cost of the action, when checking the pending
+// balance of the sender
+func GasSubsidyOption(subsidy GasSubsidy) Option {
+	return func(pool *actPool) error {
+		pool.gasSubsidy = subsidy
+		return nil
+	}
+}
+
 // actPool implements ActPool interface
 type actPool struct {
 	mutex                     sync.RWMutex
@@ -101,6 +118,7 @@ type actPool struct {
 	timerFactory              *prometheustimer.TimerFactory
 	enableExperimentalActions bool
 	senderBlackList           map[string]bool
+	gasSubsidy                GasSubsidy
 }
 
 // NewActPool constructs a new actpool
@@ -182,6 +200,25 @@ func (ap *actPool) PendingActionMap() map[string][]action.SealedEnvelope {
 	return actionMap
 }
 
+func (ap *actPool) QueuedActionMap() map[string][]action.SealedEnvelope {
+	ap.mutex.RLock()
+	defer ap.mutex.RUnlock()
+
+	actionMap := make(map[string][]action.SealedEnvelope)
+	for from, queue := range ap.accountActs {
+		pending := make(map[uint64]bool)
+		for _, act := range queue.PendingActs() {
+			pending[act.Nonce()] = true
+		}
+		for _, act := range queue.AllActs() {
+			if !pending[act.Nonce()] {
+				actionMap[from] = append(actionMap[from], act)
+			}
+		}
+	}
+	return actionMap
+}
+
 func (ap *actPool) Add(ctx context.Context, act action.SealedEnvelope) error {
 	ap.mutex.Lock()
 	defer ap.mutex.Unlock()
@@ -216,6 +253,11 @@ func (ap *actPool) Add(ctx context.Context, act action.SealedEnvelope) error {
 			act.GasPrice(),
 		)
 	}
+	// Reject action if its deadline has passed
+	if act.Expired(time.Now()) {
+		actpoolMtc.WithLabelValues("expiredAction").Inc()
+		return errors.Wrapf(action.ErrActPool, "reject the action %x whose deadline has passed", hash)
+	}
 	if err := ap.validate(ctx, act); err != nil {
 		return err
 	}
@@ -380,7 +422,7 @@ func (ap *actPool) enqueueAction(sender string, act action.SealedEnvelope, actHa
 		return errors.Wrapf(action.ErrNonce, "nonce too large ,actNonce : %x", actNonce)
 	}
 
-	cost, err := act.Cost()
+	cost, err := ap.cost(act)
 	if err != nil {
 		actpoolMtc.WithLabelValues("failedToGetCost").Inc()
 		return errors.Wrapf(err, "failed to get cost of action %x", actHash)
@@ -506,6 +548,26 @@ func (ap *actPool) reset() {
 	}
 }
 
+// cost returns the cost of the action to its sender, which excludes the gas fee paid by the subsidy. The subsidy is
+// checked for each action alone, the actions not covered when they are run fail as if the sender has no balance
+func (ap *actPool) cost(act action.SealedEnvelope) (*big.Int, error) {
+	cost, err := act.Cost()
+	if err != nil || ap.gasSubsidy == nil {
+		return cost, err
+	}
+	credit, err := ap.gasSubsidy(ap.sf, act)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get the gas subsidy")
+	}
+	if credit == nil {
+		return cost, nil
+	}
+	if cost.Cmp(credit) <= 0 {
+		return big.NewInt(0), nil
+	}
+	return new(big.Int).Sub(cost, credit), nil
+}
+
 func (ap *actPool) subGasFromPool(gas uint64) {
 	if ap.gasInPool < gas {
 		ap.gasInPool = 0
@@ -901,6 +901,51 @@ func TestActPool_GetUnconfirmedActs(t *testing.T) {
 	require.Equal([]action.SealedEnvelope{tsf1, tsf3, tsf4, tsf5}, acts)
 }
 
+func TestActPool_QueuedActionMap(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	require := require.New(t)
+	sf := mock_chainmanager.NewMockStateReader(ctrl)
+	// Create actpool
+	apConfig := getActPoolCfg()
+	Ap, err := NewActPool(sf, apConfig, EnableExperimentalActions())
+	require.NoError(err)
+	ap, ok := Ap.(*actPool)
+	require.True(ok)
+	ap.AddActionEnvelopeValidators(protocol.NewGenericValidator(sf, accountutil.AccountState))
+
+	tsf1, err := testutil.SignedTransfer(addr2, priKey1, uint64(1), big.NewInt(10), []byte{}, uint64(100000), big.NewInt(0))
+	require.NoError(err)
+	tsf2, err := testutil.SignedTransfer(addr2, priKey1, uint64(2), big.NewInt(10), []byte{}, uint64(100000), big.NewInt(0))
+	require.NoError(err)
+	tsf4, err := testutil.SignedTransfer(addr2, priKey1, uint64(4), big.NewInt(10), []byte{}, uint64(100000), big.NewInt(0))
+	require.NoError(err)
+	tsf5, err := testutil.SignedTransfer(addr2, priKey1, uint64(5), big.NewInt(10), []byte{}, uint64(100000), big.NewInt(0))
+	require.NoError(err)
+	tsf6, err := testutil.SignedTransfer(addr1, priKey2, uint64(1), big.NewInt(10), []byte{}, uint64(100000), big.NewInt(0))
+	require.NoError(err)
+
+	sf.EXPECT().State(gomock.Any(), gomock.Any()).DoAndReturn(func(account interface{}, opts ...protocol.StateOption) (uint64, error) {
+		acct, ok := account.(*state.Account)
+		require.True(ok)
+		acct.Nonce = 0
+		acct.Balance = big.NewInt(100000000000000000)
+
+		return 0, nil
+	}).AnyTimes()
+	for _, act := range []action.SealedEnvelope{tsf1, tsf2, tsf4, tsf5, tsf6} {
+		require.NoError(ap.Add(context.Background(), act))
+	}
+
+	// actions after the nonce gap of addr1 are queued, and addr2 has none
+	require.Equal(map[string][]action.SealedEnvelope{
+		addr1: {tsf4, tsf5},
+	}, ap.QueuedActionMap())
+	pending := ap.PendingActionMap()
+	require.Equal([]action.SealedEnvelope{tsf1, tsf2}, pending[addr1])
+	require.Equal([]action.SealedEnvelope{tsf6}, pending[addr2])
+}
+
 func TestActPool_GetActionByHash(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -1024,6 +1069,51 @@ func TestActPool_AddActionNotEnoughGasPrice(t *testing.T) {
 	require.Error(t, ap.Add(ctx, tsf))
 }
 
+func TestActPool_GasSubsidy(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	require := require.New(t)
+
+	sf := mock_chainmanager.NewMockStateReader(ctrl)
+	sf.EXPECT().State(gomock.Any(), gomock.Any()).DoAndReturn(func(account interface{}, opts ...protocol.StateOption) (uint64, error) {
+		acct, ok := account.(*state.Account)
+		require.True(ok)
+		acct.Nonce = 0
+		acct.Balance = big.NewInt(0)
+		return 0, nil
+	}).AnyTimes()
+	// the gas fee of the executions to addr5 is paid by the subsidy
+	subsidy := func(_ protocol.StateReader, selp action.SealedEnvelope) (*big.Int, error) {
+		if exec, ok := selp.Action().(*action.Execution); ok && exec.Contract() == addr5 {
+			return new(big.Int).Mul(exec.GasPrice(), new(big.Int).SetUint64(exec.GasLimit())), nil
+		}
+		return nil, nil
+	}
+	Ap, err := NewActPool(sf, getActPoolCfg(), GasSubsidyOption(subsidy))
+	require.NoError(err)
+	ap, ok := Ap.(*actPool)
+	require.True(ok)
+	ctx := protocol.WithBlockchainCtx(context.Background(), protocol.BlockchainCtx{})
+
+	// the sender without balance can call the subsidized contract only
+	exec1, err := testutil.SignedExecution(addr5, priKey1, 1, big.NewInt(0), 10000, big.NewInt(1), nil)
+	require.NoError(err)
+	require.NoError(ap.Add(ctx, exec1))
+	exec2, err := testutil.SignedExecution(addr4, priKey1, 2, big.NewInt(0), 10000, big.NewInt(1), nil)
+	require.NoError(err)
+	require.Equal(action.ErrBalance, errors.Cause(ap.Add(ctx, exec2)))
+	// the amount is not paid by the subsidy
+	exec3, err := testutil.SignedExecution(addr5, priKey1, 2, big.NewInt(1), 10000, big.NewInt(1), nil)
+	require.NoError(err)
+	require.Equal(action.ErrBalance, errors.Cause(ap.Add(ctx, exec3)))
+	pending := ap.PendingActionMap()[addr1]
+	require.Equal(1, len(pending))
+	require.Equal(exec1.Hash(), pending[0].Hash())
+	// the subsidized action stays pending after the pool is reset
+	ap.Reset()
+	require.Equal(1, len(ap.PendingActionMap()[addr1]))
+}
+
 // Helper function to return the correct pending nonce just in case of empty queue
 func (ap *actPool) getPendingNonce(addr string) (uint64, error) {
 	if queue, ok := ap.accountActs[addr]; ok {
@@ -113,7 +113,15 @@ func (q *actQueue) Put(act action.SealedEnvelope) error {
 	if _, exist := q.items[nonce]; exist {
 		return errors.Wrap(action.ErrNonce, "duplicate nonce")
 	}
-	heap.Push(&q.index, nonceWithTTL{nonce: nonce, deadline: time.Now().Add(q.ttl)})
+	// the action expires at the earlier one of the ttl and its deadline
+	var deadline time.Time
+	if q.ttl != 0 {
+		deadline = time.Now().Add(q.ttl)
+	}
+	if d := act.Deadline(); !d.IsZero() && (deadline.IsZero() || d.Before(deadline)) {
+		deadline = d
+	}
+	heap.Push(&q.index, nonceWithTTL{nonce: nonce, deadline: deadline})
 	q.items[nonce] = act
 	return nil
 }
@@ -133,7 +141,7 @@ func (q *actQueue) FilterNonce(threshold uint64) []action.SealedEnvelope {
 func (q *actQueue) cleanTimeout() []action.SealedEnvelope {
 	removedFromQueue := make([]action.SealedEnvelope, 0)
 	for i := 0; i < len(q.index); i++ {
-		if time.Now().After(q.index[i].deadline) {
+		if !q.index[i].deadline.IsZero() && time.Now().After(q.index[i].deadline) {
 			// remove
 			removedFromQueue = append(removedFromQueue, q.items[q.index[i].nonce])
 			delete(q.items, q.index[i].nonce)
@@ -147,9 +155,7 @@ func (q *actQueue) cleanTimeout() []action.SealedEnvelope {
 func (q *actQueue) UpdateQueue(nonce uint64) []action.SealedEnvelope {
 	removedFromQueue := make([]action.SealedEnvelope, 0)
 	// First remove all timed out actions
-	if q.ttl != 0 {
-		removedFromQueue = append(removedFromQueue, q.cleanTimeout()...)
-	}
+	removedFromQueue = append(removedFromQueue, q.cleanTimeout()...)
 
 	// Now, starting from the current pending nonce, incrementally find the next pending nonce
 	// while updating pending balance if actions are payable
@@ -272,7 +278,15 @@ func (q *actQueue) removeActs(idx int) []action.SealedEnvelope {
 
 // enoughBalance helps check whether queue's pending balance is sufficient for the given action
 func (q *actQueue) enoughBalance(act action.SealedEnvelope, updateBalance bool) bool {
-	cost, _ := act.Cost()
+	var cost *big.Int
+	if q.ap != nil {
+		cost, _ = q.ap.cost(act)
+	} else {
+		cost, _ = act.Cost()
+	}
+	if cost == nil {
+		return false
+	}
 	if q.pendingBalance.Cmp(cost) < 0 {
 		return false
 	}
@@ -212,3 +212,26 @@ func TestActQueueTimeOutAction(t *testing.T) {
 	q.(*actQueue).cleanTimeout()
 	assert.Equal(t, 1, q.Len())
 }
+
+func TestActQueueDeadline(t *testing.T) {
+	require := require.New(t)
+
+	q := NewActQueue(nil, "")
+	tsf1, err := testutil.SignedTransfer(addr2, priKey1, 1, big.NewInt(100), nil, uint64(0), big.NewInt(0))
+	require.NoError(err)
+	tsf2, err := testutil.SignedTransfer(addr2, priKey1, 2, big.NewInt(100), nil, uint64(0), big.NewInt(0))
+	require.NoError(err)
+	tsf3, err := testutil.SignedTransfer(addr2, priKey1, 3, big.NewInt(100), nil, uint64(0), big.NewInt(0))
+	require.NoError(err)
+	tsf1.SetDeadline(time.Now().Add(-time.Second))
+	tsf2.SetDeadline(time.Now().Add(time.Hour))
+
+	require.NoError(q.Put(tsf1))
+	require.NoError(q.Put(tsf2))
+	require.NoError(q.Put(tsf3))
+	// the action without ttl and deadline never expires
+	removed := q.(*actQueue).cleanTimeout()
+	require.Equal(1, len(removed))
+	require.Equal(tsf1.Hash(), removed[0].Hash())
+	require.Equal(2, q.Len())
+}
@@ -14,6 +14,8 @@ import (
 	"math"
 	"math/big"
 	"net"
+	"net/http"
+	"reflect"
 	"strconv"
 	"time"
 
@@ -25,6 +27,8 @@ import (
 	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/reflection"
 	"google.golang.org/grpc/status"
 
@@ -38,8 +42,12 @@ import (
 	"github.com/iotexproject/iotex-core/action/protocol"
 	accountutil "github.com/iotexproject/iotex-core/action/protocol/account/util"
 	"github.com/iotexproject/iotex-core/action/protocol/poll"
+	"github.com/iotexproject/iotex-core/action/protocol/protocolctx"
+	"github.com/iotexproject/iotex-core/action/protocol/rewarding"
 	"github.com/iotexproject/iotex-core/action/protocol/rolldpos"
+	"github.com/iotexproject/iotex-core/action/protocol/vote"
 	"github.com/iotexproject/iotex-core/actpool"
+	"github.com/iotexproject/iotex-core/api/apipb"
 	logfilter "github.com/iotexproject/iotex-core/api/logfilter"
 	"github.com/iotexproject/iotex-core/blockchain"
 	"github.com/iotexproject/iotex-core/blockchain/block"
@@ -49,8 +57,13 @@ import (
 	"github.com/iotexproject/iotex-core/blocksync"
 	"github.com/iotexproject/iotex-core/config"
 	"github.com/iotexproject/iotex-core/db"
+	"github.com/iotexproject/iotex-core/epochbus"
 	"github.com/iotexproject/iotex-core/gasstation"
+	"github.com/iotexproject/iotex-core/p2p"
+	"github.com/iotexproject/iotex-core/participation"
 	"github.com/iotexproject/iotex-core/pkg/log"
+	"github.com/iotexproject/iotex-core/pkg/routine"
+	"github.com/iotexproject/iotex-core/pkg/util/tlsutil"
 	"github.com/iotexproject/iotex-core/pkg/version"
 	"github.com/iotexproject/iotex-core/state"
 	"github.com/iotexproject/iotex-core/state/factory"
@@ -65,6 +78,21 @@ var (
 	ErrAction = errors.New("invalid action")
 )
 
+// _batchReadStateRetries is the max number of retries of a batch read interleaved with block commits
+const _batchReadStateRetries = 3
+
+// ChainIDMetadataKey is the key of the grpc metadata, with which a client claims the chain an action is meant for
+const ChainIDMetadataKey = "x-iotex-chain-id"
+
+// DeadlineMetadataKey is the key of the grpc metadata, with which a client hints the unix time in seconds after
+// which the action is useless, so the actpool prioritizes it among the actions of the same gas price and drops it
+// after the deadline
+const DeadlineMetadataKey = "x-iotex-action-deadline"
+
+// NextHeightMetadataKey is the key of the grpc response header, with which GetRawBlocks and GetReceiptsByBlocks tell
+// the height to continue from. It is larger than the tip height if the blocks up to the tip are all returned
+const NextHeightMetadataKey = "x-iotex-next-height"
+
 // BroadcastOutbound sends a broadcast message to the whole network
 type BroadcastOutbound func(ctx context.Context, chainID uint32, msg proto.Message) error
 
@@ -72,6 +100,13 @@ type BroadcastOutbound func(ctx context.Context, chainID uint32, msg proto.Messa
 type Config struct {
 	broadcastHandler  BroadcastOutbound
 	electionCommittee committee.Committee
+	blockStatsIndexer blockindex.BlockStatsIndexer
+	candHistory       blockindex.CandidateHistoryIndexer
+	stateAccess       blockindex.StateAccessIndexer
+	epochBus          *epochbus.Bus
+	participation     *participation.Tracker
+	latencyTracker    *p2p.LatencyTracker
+	taskManager       *routine.TaskManager
 }
 
 // Option is the option to override the api config
@@ -93,6 +128,62 @@ func WithNativeElection(committee committee.Committee) Option {
 	}
 }
 
+// WithBlockStatsIndexer is the option to return block execution stats through API.
+func WithBlockStatsIndexer(indexer blockindex.BlockStatsIndexer) Option {
+	return func(cfg *Config) error {
+		cfg.blockStatsIndexer = indexer
+		return nil
+	}
+}
+
+// WithCandidateHistoryIndexer is the option to return candidate register and update history through API.
+func WithCandidateHistoryIndexer(indexer blockindex.CandidateHistoryIndexer) Option {
+	return func(cfg *Config) error {
+		cfg.candHistory = indexer
+		return nil
+	}
+}
+
+// WithStateAccessIndexer is the option to return the state access lists of actions through API.
+func WithStateAccessIndexer(indexer blockindex.StateAccessIndexer) Option {
+	return func(cfg *Config) error {
+		cfg.stateAccess = indexer
+		return nil
+	}
+}
+
+// WithEpochBus is the option to cache the block producers of epochs until the candidates are shifted.
+func WithEpochBus(bus *epochbus.Bus) Option {
+	return func(cfg *Config) error {
+		cfg.epochBus = bus
+		return nil
+	}
+}
+
+// WithParticipationTracker is the option to return endorsement participation through API.
+func WithParticipationTracker(tracker *participation.Tracker) Option {
+	return func(cfg *Config) error {
+		cfg.participation = tracker
+		return nil
+	}
+}
+
+// WithLatencyTracker is the option to return the network latency report through API.
+func WithLatencyTracker(tracker *p2p.LatencyTracker) Option {
+	return func(cfg *Config) error {
+		cfg.latencyTracker = tracker
+		return nil
+	}
+}
+
+// WithTaskManager is the option to return the status of the background subsystems through API.
+func WithTaskManager(tm *routine.TaskManager) Option {
+	return func(cfg *Config) error {
+		cfg.taskManager = tm
+		return nil
+	}
+}
+
 // Server provides api for user to query blockchain data
 type Server struct {
 	bc                blockchain.Blockchain
@@ -108,8 +199,17 @@ type Server struct {
 	registry          *protocol.Registry
 	chainListener     Listener
 	grpcServer        *grpc.Server
+	grpcWebServer     *http.Server
 	hasActionIndex    bool
 	electionCommittee committee.Committee
+	blockStatsIndexer blockindex.BlockStatsIndexer
+	candHistory       blockindex.CandidateHistoryIndexer
+	stateAccess       blockindex.StateAccessIndexer
+	producersCache    *epochbus.Cache
+	participation     *participation.Tracker
+	latencyTracker    *p2p.LatencyTracker
+	taskManager       *routine.TaskManager
+	receiptWatcher    *receiptWatcher
 }
 
 // NewServer creates a new server
@@ -132,7 +232,7 @@ func NewServer(
 		}
 	}
 
-	if cfg.API == (config.API{}) {
+	if reflect.DeepEqual(cfg.API, config.API{}) {
 		log.L().Warn("API server is not configured.")
 		cfg.API = config.Default.API
 	}
@@ -155,21 +255,60 @@ func NewServer(
 		chainListener:     NewChainListener(),
 		gs:                gasstation.NewGasStation(chain, sf.SimulateExecution, dao, cfg.API),
 		electionCommittee: apiCfg.electionCommittee,
+		blockStatsIndexer: apiCfg.blockStatsIndexer,
+		candHistory:       apiCfg.candHistory,
+		stateAccess:       apiCfg.stateAccess,
+		producersCache:    epochbus.NewCache(apiCfg.epochBus),
+		participation:     apiCfg.participation,
+		latencyTracker:    apiCfg.latencyTracker,
+		taskManager:       apiCfg.taskManager,
 	}
 	if _, ok := cfg.Plugins[config.GatewayPlugin]; ok {
 		svr.hasActionIndex = true
 	}
-	svr.grpcServer = grpc.NewServer(
-		grpc.StreamInterceptor(grpc_prometheus.StreamServerInterceptor),
-		grpc.UnaryInterceptor(grpc_prometheus.UnaryServerInterceptor),
-	)
+	if cfg.API.ReceiptWebhook.MaxPending > 0 {
+		svr.receiptWatcher = newReceiptWatcher(cfg.API.ReceiptWebhook)
+	}
+	grpcServer, err := newGRPCServer(cfg.API.TLS)
+	if err != nil {
+		return nil, err
+	}
+	svr.grpcServer = grpcServer
 	iotexapi.RegisterAPIServiceServer(svr.grpcServer, svr)
+	apipb.RegisterReceiptServiceServer(svr.grpcServer, svr)
 	grpc_prometheus.Register(svr.grpcServer)
 	reflection.Register(svr.grpcServer)
+	if cfg.API.GRPCWeb.Port != 0 {
+		if svr.grpcWebServer, err = newGRPCWebServer(cfg.API, svr.grpcServer); err != nil {
+			return nil, err
+		}
+	}
 
 	return svr, nil
 }
 
+// newGRPCServer creates a grpc server, which serves with TLS if the cert file is configured
+func newGRPCServer(tlsCfg config.TLS) (*grpc.Server, error) {
+	opts := []grpc.ServerOption{
+		grpc.StreamInterceptor(grpc_prometheus.StreamServerInterceptor),
+		grpc.UnaryInterceptor(grpc_prometheus.UnaryServerInterceptor),
+	}
+	if tlsCfg.CertFile != "" {
+		c, err := tlsutil.NewServerConfig(
+			tlsCfg.CertFile,
+			tlsCfg.KeyFile,
+			tlsCfg.ClientCAFile,
+			tlsCfg.AllowedClients,
+			tlsCfg.ReloadInterval,
+		)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create tls config of API server")
+		}
+		opts = append(opts, grpc.Creds(credentials.NewTLS(c)))
+	}
+	return grpc.NewServer(opts...), nil
+}
+
 // GetAccount returns the metadata of an account
 func (api *Server) GetAccount(ctx context.Context, in *iotexapi.GetAccountRequest) (*iotexapi.GetAccountResponse, error) {
 	if in.Address == address.RewardingPoolAddr || in.Address == address.StakingBucketPoolAddr {
@@ -345,11 +484,19 @@ func (api *Server) GetServerMeta(ctx context.Context,
 // SendAction is the API to send an action to blockchain.
 func (api *Server) SendAction(ctx context.Context, in *iotexapi.SendActionRequest) (*iotexapi.SendActionResponse, error) {
 	log.L().Debug("receive send action request")
+	if err := api.checkChainID(ctx); err != nil {
+		return nil, err
+	}
 	var selp action.SealedEnvelope
 	var err error
 	if err = selp.LoadProto(in.Action); err != nil {
 		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
+	deadline, err := actionDeadline(ctx)
+	if err != nil {
+		return nil, err
+	}
+	selp.SetDeadline(deadline)
 	// Add to local actpool
 	ctx = protocol.WithRegistry(ctx, api.registry)
 	if err = api.ap.Add(ctx, selp); err != nil {
@@ -360,6 +507,8 @@ func (api *Server) SendAction(ctx context.Context, in *iotexapi.SendActionReques
 			desc = "Invalid balance"
 		case action.ErrInsufficientBalanceForGas:
 			desc = "Insufficient balance for gas"
+		case action.ErrBalanceReservation:
+			desc = "Balance below minimum reservation"
 		case action.ErrNonce:
 			desc = "Invalid nonce"
 		case action.ErrAddress:
@@ -378,11 +527,7 @@ func (api *Server) SendAction(ctx context.Context, in *iotexapi.SendActionReques
 		}
 		br := &errdetails.BadRequest{}
 		br.FieldViolations = append(br.FieldViolations, v)
-		st, err := st.WithDetails(br)
-		if err != nil {
-			log.S().Panicf("Unexpected error attaching metadata: %v", err)
-		}
-		return nil, st.Err()
+		return nil, withErrorInfo(ctx, st, err, br)
 	}
 	// If there is no error putting into local actpool,
 	// Broadcast it to the network
@@ -393,6 +538,59 @@ func (api *Server) SendAction(ctx context.Context, in *iotexapi.SendActionReques
 	return &iotexapi.SendActionResponse{ActionHash: hex.EncodeToString(hash[:])}, nil
 }
 
+// SendActionWithWebhook sends the action like SendAction, and posts the receipt to the webhook once the action is
+// included, or the timeout if it is not included within the ttl
+func (api *Server) SendActionWithWebhook(
+	ctx context.Context,
+	in *iotexapi.SendActionRequest,
+	webhook string,
+) (*iotexapi.SendActionResponse, error) {
+	if api.receiptWatcher == nil {
+		return nil, status.Error(codes.Unavailable, "receipt webhook is not enabled")
+	}
+	var selp action.SealedEnvelope
+	if err := selp.LoadProto(in.Action); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	// the webhook is added before sending, in case the action is included before SendAction returns
+	h := selp.Hash()
+	if err := api.receiptWatcher.AddWebhook(h, webhook, time.Now()); err != nil {
+		code := codes.InvalidArgument
+		if err == errTooManyPendingReceipts {
+			code = codes.ResourceExhausted
+		}
+		return nil, status.Error(code, err.Error())
+	}
+	res, err := api.SendAction(ctx, in)
+	if err != nil {
+		api.receiptWatcher.RemoveWebhooks(h)
+		return nil, err
+	}
+	return res, nil
+}
+
+// WaitForReceipt returns the receipt of the action once it is included, or an error if the context is done first
+func (api *Server) WaitForReceipt(ctx context.Context, h hash.Hash256) (*action.Receipt, error) {
+	if api.receiptWatcher == nil {
+		return nil, status.Error(codes.Unavailable, "receipt watcher is not enabled")
+	}
+	ch, cancel, err := api.receiptWatcher.Watch(h)
+	if err != nil {
+		return nil, status.Error(codes.ResourceExhausted, err.Error())
+	}
+	defer cancel()
+	// the action may be included before watching
+	if receipt, err := api.GetReceiptByActionHash(h); err == nil {
+		return receipt, nil
+	}
+	select {
+	case receipt := <-ch:
+		return receipt, nil
+	case <-ctx.Done():
+		return nil, status.Error(codes.DeadlineExceeded, ctx.Err().Error())
+	}
+}
+
 // GetReceiptByAction gets receipt with corresponding action hash
 func (api *Server) GetReceiptByAction(ctx context.Context, in *iotexapi.GetReceiptByActionRequest) (*iotexapi.GetReceiptByActionResponse, error) {
 	if !api.hasActionIndex || api.indexer == nil {
@@ -464,11 +662,11 @@ func (api *Server) ReadContract(ctx context.Context, in *iotexapi.ReadContractRe
 func (api *Server) ReadState(ctx context.Context, in *iotexapi.ReadStateRequest) (*iotexapi.ReadStateResponse, error) {
 	p, ok := api.registry.Find(string(in.ProtocolID))
 	if !ok {
-		return nil, status.Errorf(codes.Internal, "protocol %s isn't registered", string(in.ProtocolID))
+		return nil, status.Errorf(codes.NotFound, "protocol %s isn't registered", string(in.ProtocolID))
 	}
 	data, readStateHeight, err := api.readState(ctx, p, in.GetHeight(), in.MethodName, in.Arguments...)
 	if err != nil {
-		return nil, status.Error(codes.NotFound, err.Error())
+		return nil, catalogError(ctx, readStateErrorCode(err), err)
 	}
 	blkHash, err := api.dao.GetBlockHash(readStateHeight)
 	if err != nil {
@@ -487,6 +685,72 @@ func (api *Server) ReadState(ctx context.Context, in *iotexapi.ReadStateRequest)
 	return &out, nil
 }
 
+// BatchReadState reads the states of multiple protocols against the same state height. All requests must ask for the
+// same height, and either all the responses are returned or none of them
+func (api *Server) BatchReadState(ctx context.Context, in []*iotexapi.ReadStateRequest) ([]*iotexapi.ReadStateResponse, error) {
+	if len(in) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "empty batch")
+	}
+	if uint64(len(in)) > api.cfg.API.RangeQueryLimit {
+		return nil, status.Errorf(codes.InvalidArgument, "batch size %d exceeds the limit %d", len(in), api.cfg.API.RangeQueryLimit)
+	}
+	ps := make([]protocol.Protocol, len(in))
+	for i, req := range in {
+		if req.GetHeight() != in[0].GetHeight() {
+			return nil, status.Errorf(codes.InvalidArgument, "request %d asks for height %s other than %s", i, req.GetHeight(), in[0].GetHeight())
+		}
+		p, ok := api.registry.Find(string(req.ProtocolID))
+		if !ok {
+			return nil, status.Errorf(codes.NotFound, "protocol %s isn't registered", string(req.ProtocolID))
+		}
+		ps[i] = p
+	}
+
+	data := make([][]byte, len(in))
+	for retry := 0; ; retry++ {
+		var readStateHeight uint64
+		consistent := true
+		for i, req := range in {
+			d, height, err := api.readState(ctx, ps[i], req.GetHeight(), req.MethodName, req.Arguments...)
+			if err != nil {
+				return nil, status.Errorf(readStateErrorCode(err), "request %d: %s", i, err.Error())
+			}
+			if i == 0 {
+				readStateHeight = height
+			} else if height != readStateHeight {
+				// a block is committed in between, read again
+				consistent = false
+				break
+			}
+			data[i] = d
+		}
+		if consistent {
+			blkHash, err := api.dao.GetBlockHash(readStateHeight)
+			if err != nil {
+				if errors.Cause(err) == db.ErrNotExist {
+					return nil, status.Error(codes.NotFound, err.Error())
+				}
+				return nil, status.Error(codes.Internal, err.Error())
+			}
+			blkIdentifier := &iotextypes.BlockIdentifier{
+				Height: readStateHeight,
+				Hash:   hex.EncodeToString(blkHash[:]),
+			}
+			out := make([]*iotexapi.ReadStateResponse, len(in))
+			for i := range data {
+				out[i] = &iotexapi.ReadStateResponse{
+					Data:            data[i],
+					BlockIdentifier: blkIdentifier,
+				}
+			}
+			return out, nil
+		}
+		if retry == _batchReadStateRetries {
+			return nil, status.Error(codes.Aborted, "state height keeps changing during batch read")
+		}
+	}
+}
+
 // SuggestGasPrice suggests gas price
 func (api *Server) SuggestGasPrice(ctx context.Context, in *iotexapi.SuggestGasPriceRequest) (*iotexapi.SuggestGasPriceResponse, error) {
 	suggestPrice, err := api.gs.SuggestGasPrice()
@@ -496,6 +760,21 @@ func (api *Server) SuggestGasPrice(ctx context.Context, in *iotexapi.SuggestGasP
 	return &iotexapi.SuggestGasPriceResponse{GasPrice: suggestPrice}, nil
 }
 
+// FeeHistory returns the fee history of at most blockCount blocks up to the newest block, which is a height or a
+// block tag, as eth_feeHistory does
+func (api *Server) FeeHistory(blockCount uint64, newestBlock string, rewardPercentiles []float64) (*gasstation.FeeHistory, error) {
+	tipHeight := api.bc.TipHeight()
+	newestHeight, err := api.heightByTagOrNumber(tipHeight, newestBlock)
+	if err != nil {
+		return nil, status.Error(readStateErrorCode(err), err.Error())
+	}
+	history, err := api.gs.FeeHistory(blockCount, newestHeight, rewardPercentiles)
+	if err != nil {
+		return nil, status.Error(readStateErrorCode(err), err.Error())
+	}
+	return history, nil
+}
+
 // EstimateGasForAction estimates gas for action
 func (api *Server) EstimateGasForAction(ctx context.Context, in *iotexapi.EstimateGasForActionRequest) (*iotexapi.EstimateGasForActionResponse, error) {
 	estimateGas, err := api.gs.EstimateGasForAction(in.Action)
@@ -566,17 +845,9 @@ func (api *Server) GetEpochMeta(
 		return nil, status.Error(codes.Internal, "poll protocol is not registered")
 	}
 
-	methodName := []byte("ActiveBlockProducersByEpoch")
-	arguments := [][]byte{[]byte(strconv.FormatUint(in.EpochNumber, 10))}
-	height := strconv.FormatUint(epochHeight, 10)
-	data, _, err := api.readState(context.Background(), pp, height, methodName, arguments...)
+	activeConsensusBlockProducers, err := api.blockProducersByEpoch(pp, "ActiveBlockProducersByEpoch", in.EpochNumber, epochHeight)
 	if err != nil {
-		return nil, status.Error(codes.NotFound, err.Error())
-	}
-
-	var activeConsensusBlockProducers state.CandidateList
-	if err := activeConsensusBlockProducers.Deserialize(data); err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, catalogError(ctx, readStateErrorCode(err), err)
 	}
 
 	numBlks, produce, err := api.getProductivityByEpoch(rp, in.EpochNumber, api.bc.TipHeight(), activeConsensusBlockProducers)
@@ -584,15 +855,9 @@ func (api *Server) GetEpochMeta(
 		return nil, status.Error(codes.NotFound, err.Error())
 	}
 
-	methodName = []byte("BlockProducersByEpoch")
-	data, _, err = api.readState(context.Background(), pp, height, methodName, arguments...)
+	BlockProducers, err := api.blockProducersByEpoch(pp, "BlockProducersByEpoch", in.EpochNumber, epochHeight)
 	if err != nil {
-		return nil, status.Error(codes.NotFound, err.Error())
-	}
-
-	var BlockProducers state.CandidateList
-	if err := BlockProducers.Deserialize(data); err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, catalogError(ctx, readStateErrorCode(err), err)
 	}
 
 	var blockProducersInfo []*iotexapi.BlockProducerInfo
@@ -631,7 +896,10 @@ func (api *Server) GetRawBlocks(
 	if in.StartHeight > tipHeight {
 		return nil, status.Error(codes.InvalidArgument, "start height should not exceed tip height")
 	}
-	var res []*iotexapi.BlockInfo
+	var (
+		res  []*iotexapi.BlockInfo
+		size int
+	)
 	for height := int(in.StartHeight); height <= int(tipHeight); height++ {
 		if uint64(len(res)) >= in.Count {
 			break
@@ -656,16 +924,62 @@ func (api *Server) GetRawBlocks(
 				return nil, status.Error(codes.NotFound, err.Error())
 			}
 		}
-		res = append(res, &iotexapi.BlockInfo{
+		info := &iotexapi.BlockInfo{
 			Block:           blk.ConvertToBlockPb(),
 			Receipts:        receiptsPb,
 			TransactionLogs: transactionLogs,
-		})
+		}
+		// at least one block is returned, so that the client always makes progress
+		size += proto.Size(info)
+		if maxBytes := api.cfg.API.RawBlocksMaxBytes; maxBytes > 0 && size > maxBytes && len(res) > 0 {
+			break
+		}
+		res = append(res, info)
 	}
+	setNextHeightHeader(ctx, in.StartHeight+uint64(len(res)))
 
 	return &iotexapi.GetRawBlocksResponse{Blocks: res}, nil
 }
 
+// GetReceiptsByBlocks gets the receipts of the blocks in a height range, which saves the bulk indexers from fetching
+// the receipts action by action. Like GetRawBlocks, the response is bounded by size and the next height to fetch is
+// sent in the header
+func (api *Server) GetReceiptsByBlocks(
+	ctx context.Context,
+	in *apipb.GetReceiptsByBlocksRequest,
+) (*apipb.GetReceiptsByBlocksResponse, error) {
+	if in.Count == 0 || in.Count > api.cfg.API.RangeQueryLimit {
+		return nil, status.Error(codes.InvalidArgument, "range exceeds the limit")
+	}
+
+	tipHeight := api.bc.TipHeight()
+	if in.StartHeight == 0 || in.StartHeight > tipHeight {
+		return nil, status.Error(codes.InvalidArgument, "start height should be in range [1, tip height]")
+	}
+	var (
+		res  []*apipb.BlockReceipts
+		size int
+	)
+	for height := in.StartHeight; height <= tipHeight && uint64(len(res)) < in.Count; height++ {
+		receipts, err := api.dao.GetReceipts(height)
+		if err != nil {
+			return nil, status.Error(codes.NotFound, err.Error())
+		}
+		blkReceipts := &apipb.BlockReceipts{Height: height}
+		for _, receipt := range receipts {
+			blkReceipts.Receipts = append(blkReceipts.Receipts, receipt.ConvertToReceiptPb())
+		}
+		size += proto.Size(blkReceipts)
+		if maxBytes := api.cfg.API.RawBlocksMaxBytes; maxBytes > 0 && size > maxBytes && len(res) > 0 {
+			break
+		}
+		res = append(res, blkReceipts)
+	}
+	setNextHeightHeader(ctx, in.StartHeight+uint64(len(res)))
+
+	return &apipb.GetReceiptsByBlocksResponse{Blocks: res}, nil
+}
+
 // GetLogs get logs filtered by contract address and topics
 func (api *Server) GetLogs(
 	ctx context.Context,
@@ -802,6 +1116,225 @@ func (api *Server) GetActionByActionHash(h hash.Hash256) (action.SealedEnvelope,
 	return selp, err
 }
 
+// GetBlockStats returns the execution stats of the block at height
+func (api *Server) GetBlockStats(height uint64) (*blockindex.BlockStats, error) {
+	if api.blockStatsIndexer == nil {
+		return nil, status.Error(codes.Unavailable, "block stats index is not available")
+	}
+	if height == 0 || height > api.bc.TipHeight() {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid block height %d", height)
+	}
+	stats, err := api.blockStatsIndexer.BlockStats(height)
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	return stats, nil
+}
+
+// GetCandidateHistory returns the owner and the register and update history of the candidate, which is looked up by
+// the owner address or any name the candidate has used
+func (api *Server) GetCandidateHistory(ownerOrName string) (string, []*blockindex.CandidateEvent, error) {
+	if api.candHistory == nil {
+		return "", nil, status.Error(codes.Unavailable, "candidate history index is not available")
+	}
+	if ownerOrName == "" {
+		return "", nil, status.Error(codes.InvalidArgument, "empty candidate owner or name")
+	}
+	var (
+		owner   address.Address
+		history []*blockindex.CandidateEvent
+		err     error
+	)
+	if addr, aerr := address.FromString(ownerOrName); aerr == nil {
+		owner = addr
+		history, err = api.candHistory.CandidateHistory(owner)
+	} else {
+		owner, history, err = api.candHistory.CandidateHistoryByName(ownerOrName)
+	}
+	if err != nil {
+		return "", nil, status.Error(codes.NotFound, err.Error())
+	}
+	return owner.String(), history, nil
+}
+
+// GetStateAccessList returns the keys of the states read and written by the action, which are only recorded for the
+// blocks run by this node
+func (api *Server) GetStateAccessList(actionHash string) (*factory.AccessList, error) {
+	if api.stateAccess == nil {
+		return nil, status.Error(codes.Unavailable, "state access index is not available")
+	}
+	h, err := hash.HexStringToHash256(actionHash)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	al, err := api.stateAccess.AccessList(h)
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	return al, nil
+}
+
+// GetNetworkLatencyReport returns the propagation latency of the block and consensus messages per peer
+func (api *Server) GetNetworkLatencyReport() ([]p2p.PeerLatency, error) {
+	if api.latencyTracker == nil {
+		return nil, status.Error(codes.Unavailable, "network latency report is not enabled")
+	}
+	return api.latencyTracker.Report(time.Now()), nil
+}
+
+// GetEpochRewardProjection projects the reward distribution of the current epoch from the blocks produced so far
+func (api *Server) GetEpochRewardProjection(ctx context.Context) (*rewarding.EpochRewardProjection, error) {
+	rp := rolldpos.FindProtocol(api.registry)
+	pp := poll.FindProtocol(api.registry)
+	rwd := rewarding.FindProtocol(api.registry)
+	if rp == nil || pp == nil || rwd == nil {
+		return nil, status.Error(codes.Unavailable, "epoch reward is not available without rolldpos, poll and rewarding protocols")
+	}
+	tipHeight := api.bc.TipHeight()
+	epochNum := rp.GetEpochNum(tipHeight)
+	ctx, err := api.readStateContext(ctx, tipHeight)
+	if err != nil {
+		return nil, err
+	}
+	candidates, err := pp.Candidates(ctx, api.sf)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	_, produce, err := api.getProductivityByEpoch(rp, epochNum, tipHeight, nil)
+	if err != nil {
+		return nil, err
+	}
+	projection, err := rwd.ProjectEpochReward(ctx, api.sf, epochNum, rp.GetEpochHeight(epochNum), candidates, produce)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return projection, nil
+}
+
+// SimulateKickout returns the probation list of the next epoch if the active delegates produce the given numbers of
+// blocks in the current epoch, the blocks produced so far are counted for the delegates not in the productivity. The
+// state is not changed, so the delegates can evaluate the impact of missing blocks before maintenance
+func (api *Server) SimulateKickout(
+	ctx context.Context,
+	epochNum uint64,
+	productivity map[string]uint64,
+) (*vote.ProbationList, error) {
+	rp := rolldpos.FindProtocol(api.registry)
+	pp := poll.FindProtocol(api.registry)
+	if rp == nil || pp == nil {
+		return nil, status.Error(codes.Unavailable, "kickout simulation is not available without rolldpos and poll protocols")
+	}
+	ps, ok := pp.(poll.ProbationSimulator)
+	if !ok {
+		return nil, status.Error(codes.Unimplemented, "kickout is not supported by the poll protocol")
+	}
+	tipHeight := api.bc.TipHeight()
+	if tipEpochNum := rp.GetEpochNum(tipHeight); epochNum != tipEpochNum {
+		return nil, status.Errorf(
+			codes.InvalidArgument,
+			"kickout can only be simulated for current epoch %d, not epoch %d",
+			tipEpochNum,
+			epochNum,
+		)
+	}
+	ctx, err := api.readStateContext(ctx, tipHeight)
+	if err != nil {
+		return nil, err
+	}
+	abps, err := pp.Delegates(ctx, api.sf)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	_, produce, err := api.getProductivityByEpoch(rp, epochNum, tipHeight, abps)
+	if err != nil {
+		return nil, err
+	}
+	for addr, n := range productivity {
+		if _, ok := produce[addr]; !ok {
+			return nil, status.Errorf(codes.InvalidArgument, "%s is not an active delegate of epoch %d", addr, epochNum)
+		}
+		produce[addr] = n
+	}
+	probationList, err := ps.SimulateProbationList(ctx, api.sf, produce)
+	if err != nil {
+		return nil, catalogError(ctx, readStateErrorCode(err), err)
+	}
+	return probationList, nil
+}
+
+// DiffStates streams the key-level differences between the states at the two heights to fn, including the accounts,
+// the staking buckets and the storage slots of the contracts, which requires the archive mode
+func (api *Server) DiffStates(ctx context.Context, from, to uint64, fn func(*factory.StateChange) error) error {
+	sd, ok := api.sf.(factory.StateDiffer)
+	if !ok || !api.cfg.Chain.EnableArchiveMode {
+		return status.Error(codes.Unavailable, "state diff requires the archive mode of the trie state factory")
+	}
+	if tipHeight := api.bc.TipHeight(); from > tipHeight || to > tipHeight {
+		return status.Errorf(codes.InvalidArgument, "height %d or %d is higher than tip height %d", from, to, tipHeight)
+	}
+	err := sd.DiffStates(from, to, factory.StateNamespaces, func(c *factory.StateChange) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		return fn(c)
+	})
+	switch errors.Cause(err) {
+	case nil:
+		return nil
+	case context.Canceled, context.DeadlineExceeded:
+		return status.Error(codes.Canceled, err.Error())
+	case factory.ErrNoArchiveData:
+		return status.Error(codes.NotFound, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
+
+// GetBlockHashPreimage returns the exact bytes hashed into the hashes of the block at the height and the identifiers of
+// the hashing algorithms, for auditors and other clients to verify the hashes and the signatures independently
+func (api *Server) GetBlockHashPreimage(height uint64) (*block.HashPreimage, error) {
+	if height < 1 || height > api.bc.TipHeight() {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid block height = %d", height)
+	}
+	blk, err := api.dao.GetBlockByHeight(height)
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	preimage, err := blk.HashPreimage()
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return preimage, nil
+}
+
+// GetSubsystemStatus returns the status of the background subsystems
+func (api *Server) GetSubsystemStatus() ([]routine.SubsystemStatus, error) {
+	if api.taskManager == nil {
+		return nil, status.Error(codes.Unavailable, "subsystem status is not available")
+	}
+	return api.taskManager.Status(), nil
+}
+
+// GetEndorsementParticipation returns the endorsement participation of the active delegates in the epoch
+func (api *Server) GetEndorsementParticipation(epochNum uint64) (*participation.EpochParticipation, error) {
+	if api.participation == nil {
+		return nil, status.Error(codes.Unavailable, "endorsement participation is not available")
+	}
+	ep, err := api.participation.Participation(epochNum)
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	return ep, nil
+}
+
+// GetStaleTipEvents returns the latest events of the node being stuck with stale peers
+func (api *Server) GetStaleTipEvents() ([]blocksync.StaleTipEvent, error) {
+	if api.bs == nil {
+		return nil, status.Error(codes.Unavailable, "block sync is not available")
+	}
+	return api.bs.StaleTipEvents(), nil
+}
+
 // GetEvmTransfersByActionHash returns evm transfers by action hash
 func (api *Server) GetEvmTransfersByActionHash(ctx context.Context, in *iotexapi.GetEvmTransfersByActionHashRequest) (*iotexapi.GetEvmTransfersByActionHashResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "evm transfer index is deprecated, call GetSystemLogByActionHash instead")
@@ -913,17 +1446,32 @@ func (api *Server) Start() error {
 			log.L().Fatal("Node failed to serve.", zap.Error(err))
 		}
 	}()
+	if api.grpcWebServer != nil {
+		if err := api.startGRPCWeb(); err != nil {
+			return err
+		}
+	}
 	if err := api.bc.AddSubscriber(api.chainListener); err != nil {
 		return errors.Wrap(err, "failed to subscribe to block creations")
 	}
 	if err := api.chainListener.Start(); err != nil {
 		return errors.Wrap(err, "failed to start blockchain listener")
 	}
+	if api.receiptWatcher != nil {
+		if err := api.chainListener.AddResponder(api.receiptWatcher); err != nil {
+			return errors.Wrap(err, "failed to add receipt watcher")
+		}
+	}
 	return nil
 }
 
 // Stop stops the API server
 func (api *Server) Stop() error {
+	if api.grpcWebServer != nil {
+		if err := api.grpcWebServer.Close(); err != nil {
+			return errors.Wrap(err, "failed to stop grpc-web server")
+		}
+	}
 	api.grpcServer.Stop()
 	if err := api.bc.RemoveSubscriber(api.chainListener); err != nil {
 		return errors.Wrap(err, "failed to unsubscribe blockchain listener")
@@ -931,39 +1479,165 @@ func (api *Server) Stop() error {
 	return api.chainListener.Stop()
 }
 
+// checkChainID rejects the request if the client claims a chain other than the one the node is on
+func (api *Server) checkChainID(ctx context.Context) error {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil
+	}
+	values := md.Get(ChainIDMetadataKey)
+	if len(values) == 0 {
+		return nil
+	}
+	chainID, err := strconv.ParseUint(values[0], 10, 32)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid chain id %s", values[0])
+	}
+	if expected := api.bc.ChainID(); uint32(chainID) != expected {
+		return status.Errorf(codes.InvalidArgument, "chain id mismatch: action is meant for chain %d, node is on chain %d", chainID, expected)
+	}
+	return nil
+}
+
+// actionDeadline returns the deadline hinted by the client, zero if there is no deadline
+func actionDeadline(ctx context.Context) (time.Time, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return time.Time{}, nil
+	}
+	values := md.Get(DeadlineMetadataKey)
+	if len(values) == 0 {
+		return time.Time{}, nil
+	}
+	deadline, err := strconv.ParseInt(values[0], 10, 64)
+	if err != nil || deadline <= 0 {
+		return time.Time{}, status.Errorf(codes.InvalidArgument, "invalid action deadline %s", values[0])
+	}
+	return time.Unix(deadline, 0), nil
+}
+
 func (api *Server) readState(ctx context.Context, p protocol.Protocol, height string, methodName []byte, arguments ...[]byte) ([]byte, uint64, error) {
-	// TODO: need to complete the context
 	tipHeight := api.bc.TipHeight()
-	ctx = protocol.WithBlockCtx(ctx, protocol.BlockCtx{
-		BlockHeight: tipHeight,
-	})
-	ctx = protocol.WithBlockchainCtx(
-		protocol.WithRegistry(ctx, api.registry),
-		protocol.BlockchainCtx{
-			Genesis: api.cfg.Genesis,
-		},
-	)
+	sr, readHeight, err := api.stateReaderAt(tipHeight, height)
+	if err != nil {
+		return nil, uint64(0), err
+	}
+	ctx, err = api.readStateContext(ctx, readHeight)
+	if err != nil {
+		return nil, uint64(0), err
+	}
+	return p.ReadState(ctx, sr, methodName, arguments...)
+}
+
+// readStateContext returns the context to read the state at the height
+func (api *Server) readStateContext(ctx context.Context, height uint64) (context.Context, error) {
+	ctx, err := protocolctx.NewBuilder().
+		SetRegistry(api.registry).
+		SetGenesis(api.cfg.Genesis).
+		SetBlockHeight(height).
+		Build(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return ctx, nil
+}
+
+// stateReaderAt returns the state reader pinned at the height or the block tag requested, and the height the reader is
+// pinned at. The state is read at the exact height from the archive if archive mode is enabled, otherwise a height in a past epoch
+// is resolved to the start of that epoch, which the protocols reading history from their indexers rely on
+// heightByTagOrNumber parses the height, which is either a block tag or a number
+func (api *Server) heightByTagOrNumber(tipHeight uint64, height string) (uint64, error) {
+	if height == "" {
+		return tipHeight, nil
+	}
+	if isBlockTag(height) {
+		return api.blockHeightByTag(tipHeight, height)
+	}
+	inputHeight, err := strconv.ParseUint(height, 0, 64)
+	if err != nil {
+		return 0, errors.Wrap(protocol.ErrInvalidArgument, err.Error())
+	}
+	return inputHeight, nil
+}
+
+func (api *Server) stateReaderAt(tipHeight uint64, height string) (protocol.StateReader, uint64, error) {
+	if height == "" || height == BlockTagLatest {
+		return api.sf, tipHeight, nil
+	}
+	inputHeight, err := api.heightByTagOrNumber(tipHeight, height)
+	if err != nil {
+		return nil, uint64(0), err
+	}
+	if inputHeight > tipHeight {
+		return nil, uint64(0), errors.Wrapf(protocol.ErrFutureHeight, "height %d is higher than tip height %d", inputHeight, tipHeight)
+	}
+	if api.cfg.Chain.EnableArchiveMode {
+		return factory.NewHistoryStateReader(api.sf, inputHeight), inputHeight, nil
+	}
 
 	rp := rolldpos.FindProtocol(api.registry)
 	if rp == nil {
 		return nil, uint64(0), errors.New("rolldpos is not registered")
 	}
-
-	tipEpochNum := rp.GetEpochNum(tipHeight)
-	if height != "" {
-		inputHeight, err := strconv.ParseUint(height, 0, 64)
+	inputEpochNum := rp.GetEpochNum(inputHeight)
+	if inputEpochNum < rp.GetEpochNum(tipHeight) {
+		// old data, wrap to history state reader
+		epochHeight := rp.GetEpochHeight(inputEpochNum)
+		return factory.NewHistoryStateReader(api.sf, epochHeight), epochHeight, nil
+	}
+	return api.sf, tipHeight, nil
+}
+
+// readStateErrorCode maps the error returned by protocol's ReadState to gRPC status code
+// blockProducersByEpoch reads the block producers of the epoch with the poll method, the result is cached until the
+// candidates are shifted
+func (api *Server) blockProducersByEpoch(
+	pp poll.Protocol,
+	method string,
+	epochNum, epochHeight uint64,
+) (state.CandidateList, error) {
+	type key struct {
+		method   string
+		epochNum uint64
+	}
+	producers, err := api.producersCache.Get(key{method, epochNum}, func() (interface{}, error) {
+		data, _, err := api.readState(
+			context.Background(),
+			pp,
+			strconv.FormatUint(epochHeight, 10),
+			[]byte(method),
+			[]byte(strconv.FormatUint(epochNum, 10)),
+		)
 		if err != nil {
-			return nil, uint64(0), err
+			return nil, err
 		}
-		inputEpochNum := rp.GetEpochNum(inputHeight)
-		if inputEpochNum < tipEpochNum {
-			// old data, wrap to history state reader
-			return p.ReadState(ctx, factory.NewHistoryStateReader(api.sf, rp.GetEpochHeight(inputEpochNum)), methodName, arguments...)
+		var producers state.CandidateList
+		if err := producers.Deserialize(data); err != nil {
+			return nil, err
 		}
+		return producers, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return producers.(state.CandidateList), nil
+}
+
+func readStateErrorCode(err error) codes.Code {
+	switch errors.Cause(err) {
+	case protocol.ErrNotFound, state.ErrStateNotExist, db.ErrNotExist, poll.ErrIndexerNotExist:
+		return codes.NotFound
+	case protocol.ErrInvalidArgument:
+		return codes.InvalidArgument
+	case protocol.ErrFutureEpoch, protocol.ErrFutureHeight:
+		return codes.OutOfRange
+	case protocol.ErrPreActivation, factory.ErrNoArchiveData:
+		return codes.FailedPrecondition
+	case protocol.ErrUnimplemented, factory.ErrNotSupported:
+		return codes.Unimplemented
+	default:
+		return codes.Internal
 	}
-
-	// TODO: need to distinguish user error and system error
-	return p.ReadState(ctx, api.sf, methodName, arguments...)
 }
 
 func (api *Server) getActionsFromIndex(totalActions, start, count uint64) (*iotexapi.GetActionsResponse, error) {
@@ -1174,6 +1848,14 @@ func (api *Server) getActionsByBlock(blkHash string, start uint64, count uint64)
 }
 
 // getBlockMetas returns blockmetas response within the height range
+// setNextHeightHeader sets the height to continue a range retrieval from into the grpc response header
+func setNextHeightHeader(ctx context.Context, nextHeight uint64) {
+	if err := grpc.SetHeader(ctx, metadata.Pairs(NextHeightMetadataKey, strconv.FormatUint(nextHeight, 10))); err != nil {
+		// not called by grpc
+		log.L().Debug("Failed to set the next height header.", zap.Error(err))
+	}
+}
+
 func (api *Server) getBlockMetas(start uint64, count uint64) (*iotexapi.GetBlockMetasResponse, error) {
 	if count == 0 {
 		return nil, status.Error(codes.InvalidArgument, "count must be greater than zero")
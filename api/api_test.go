@@ -20,6 +20,7 @@ import (
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 
 	"github.com/iotexproject/go-pkgs/hash"
@@ -37,6 +38,7 @@ import (
 	"github.com/iotexproject/iotex-core/action/protocol/rewarding"
 	"github.com/iotexproject/iotex-core/action/protocol/rolldpos"
 	"github.com/iotexproject/iotex-core/actpool"
+	"github.com/iotexproject/iotex-core/api/apipb"
 	"github.com/iotexproject/iotex-core/blockchain"
 	"github.com/iotexproject/iotex-core/blockchain/block"
 	"github.com/iotexproject/iotex-core/blockchain/blockdao"
@@ -443,6 +445,7 @@ var (
 		addr       string
 		// Expected values
 		returnErr bool
+		code      codes.Code
 		balance   *big.Int
 	}{
 		{
@@ -464,12 +467,21 @@ var (
 			methodName: "UnclaimedBalance",
 			addr:       identityset.Address(27).String(),
 			returnErr:  true,
+			code:       codes.NotFound,
 		},
 		{
 			protocolID: "rewarding",
 			methodName: "Wrong Method",
 			addr:       identityset.Address(27).String(),
 			returnErr:  true,
+			code:       codes.NotFound,
+		},
+		{
+			protocolID: "rewarding",
+			methodName: "UnclaimedBalance",
+			addr:       "Wrong Address",
+			returnErr:  true,
+			code:       codes.InvalidArgument,
 		},
 	}
 
@@ -1145,6 +1157,13 @@ func TestServer_SendAction(t *testing.T) {
 		require.Equal(test.actionHash, res.ActionHash)
 	}
 
+	// claiming another chain is rejected
+	chain.EXPECT().ChainID().Return(uint32(1)).Times(1)
+	mdCtx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(ChainIDMetadataKey, "2"))
+	_, err := svr.SendAction(mdCtx, &iotexapi.SendActionRequest{Action: sendActionTests[0].actionPb})
+	require.Error(err)
+	require.Contains(err.Error(), "chain id mismatch")
+
 	// 2 failure cases
 	ctx := context.Background()
 	tests := []struct {
@@ -1264,6 +1283,32 @@ func TestServer_SuggestGasPrice(t *testing.T) {
 	}
 }
 
+func TestServer_FeeHistory(t *testing.T) {
+	require := require.New(t)
+	cfg := newConfig(t)
+
+	svr, bfIndexFile, err := createServer(cfg, false)
+	require.NoError(err)
+	defer func() {
+		testutil.CleanupPath(t, bfIndexFile)
+	}()
+	tipHeight := svr.bc.TipHeight()
+	history, err := svr.FeeHistory(2, BlockTagLatest, []float64{50})
+	require.NoError(err)
+	require.Equal(tipHeight-1, history.OldestBlock)
+	require.Len(history.GasUsedRatio, 2)
+	require.Len(history.Reward, 2)
+
+	history, err = svr.FeeHistory(1, strconv.FormatUint(tipHeight-1, 10), nil)
+	require.NoError(err)
+	require.Equal(tipHeight-1, history.OldestBlock)
+
+	_, err = svr.FeeHistory(1, "pending", nil)
+	require.Equal(codes.InvalidArgument, status.Code(err))
+	_, err = svr.FeeHistory(1, strconv.FormatUint(tipHeight+1, 10), nil)
+	require.Equal(codes.OutOfRange, status.Code(err))
+}
+
 func TestServer_EstimateGasForAction(t *testing.T) {
 	require := require.New(t)
 	cfg := newConfig(t)
@@ -1484,6 +1529,9 @@ func TestServer_ReadUnclaimedBalance(t *testing.T) {
 		})
 		if test.returnErr {
 			require.Error(err)
+			sta, ok := status.FromError(err)
+			require.True(ok)
+			require.Equal(test.code, sta.Code())
 			continue
 		}
 		require.NoError(err)
@@ -1493,6 +1541,160 @@ func TestServer_ReadUnclaimedBalance(t *testing.T) {
 	}
 }
 
+func TestServer_BatchReadState(t *testing.T) {
+	require := require.New(t)
+	cfg := newConfig(t)
+	cfg.Consensus.Scheme = config.RollDPoSScheme
+	svr, bfIndexFile, err := createServer(cfg, false)
+	require.NoError(err)
+	defer func() {
+		testutil.CleanupPath(t, bfIndexFile)
+	}()
+
+	out, err := svr.BatchReadState(context.Background(), []*iotexapi.ReadStateRequest{
+		{
+			ProtocolID: []byte("rewarding"),
+			MethodName: []byte("UnclaimedBalance"),
+			Arguments:  [][]byte{[]byte(identityset.Address(0).String())},
+		},
+		{
+			ProtocolID: []byte("rewarding"),
+			MethodName: []byte("TotalBalance"),
+		},
+		{
+			ProtocolID: []byte("rewarding"),
+			MethodName: []byte("AvailableBalance"),
+		},
+	})
+	require.NoError(err)
+	require.Equal(3, len(out))
+	for i, balance := range []*big.Int{
+		unit.ConvertIotxToRau(64),
+		unit.ConvertIotxToRau(200000000),
+		unit.ConvertIotxToRau(199999936),
+	} {
+		val, ok := big.NewInt(0).SetString(string(out[i].Data), 10)
+		require.True(ok)
+		require.Equal(balance, val)
+		require.Equal(out[0].BlockIdentifier, out[i].BlockIdentifier)
+	}
+	require.Equal(svr.bc.TipHeight(), out[0].BlockIdentifier.Height)
+
+	for _, test := range []struct {
+		in   []*iotexapi.ReadStateRequest
+		code codes.Code
+	}{
+		{nil, codes.InvalidArgument},
+		{
+			[]*iotexapi.ReadStateRequest{
+				{ProtocolID: []byte("rewarding"), MethodName: []byte("TotalBalance")},
+				{ProtocolID: []byte("rewarding"), MethodName: []byte("TotalBalance"), Height: "1"},
+			},
+			codes.InvalidArgument,
+		},
+		{
+			[]*iotexapi.ReadStateRequest{
+				{ProtocolID: []byte("rewarding"), MethodName: []byte("TotalBalance")},
+				{ProtocolID: []byte("Wrong ID"), MethodName: []byte("TotalBalance")},
+			},
+			codes.NotFound,
+		},
+	} {
+		_, err := svr.BatchReadState(context.Background(), test.in)
+		sta, ok := status.FromError(err)
+		require.True(ok)
+		require.Equal(test.code, sta.Code())
+	}
+}
+
+func TestServer_ReadStateAtHeight(t *testing.T) {
+	require := require.New(t)
+	cfg := newConfig(t)
+	svr, bfIndexFile, err := createServer(cfg, false)
+	require.NoError(err)
+	defer func() {
+		testutil.CleanupPath(t, bfIndexFile)
+	}()
+
+	tipHeight := svr.bc.TipHeight()
+	for _, test := range []struct {
+		height string
+		code   codes.Code
+	}{
+		{strconv.FormatUint(tipHeight, 10), codes.OK},
+		{strconv.FormatUint(tipHeight+1, 10), codes.OutOfRange},
+		{"tip", codes.InvalidArgument},
+		{BlockTagLatest, codes.OK},
+		{BlockTagSafe, codes.OK},
+	} {
+		out, err := svr.ReadState(context.Background(), &iotexapi.ReadStateRequest{
+			ProtocolID: []byte("rewarding"),
+			MethodName: []byte("TotalBalance"),
+			Height:     test.height,
+		})
+		if test.code != codes.OK {
+			sta, ok := status.FromError(err)
+			require.True(ok)
+			require.Equal(test.code, sta.Code())
+			continue
+		}
+		require.NoError(err)
+		require.Equal(tipHeight, out.BlockIdentifier.Height)
+	}
+
+	// the state is read at the exact height in archive mode
+	svr.cfg.Chain.EnableArchiveMode = true
+	sr, height, err := svr.stateReaderAt(tipHeight, "1")
+	require.NoError(err)
+	require.EqualValues(1, height)
+	h, err := sr.Height()
+	require.NoError(err)
+	require.EqualValues(1, h)
+}
+
+func TestServer_BlockHeightByTag(t *testing.T) {
+	require := require.New(t)
+	cfg := newConfig(t)
+	svr, bfIndexFile, err := createServer(cfg, false)
+	require.NoError(err)
+	defer func() {
+		testutil.CleanupPath(t, bfIndexFile)
+	}()
+
+	tipHeight := svr.bc.TipHeight()
+	height, err := svr.BlockHeightByTag(BlockTagLatest)
+	require.NoError(err)
+	require.Equal(tipHeight, height)
+	height, err = svr.BlockHeightByTag(BlockTagSafe)
+	require.NoError(err)
+	require.Equal(tipHeight-cfg.API.Finality.SafeDepth, height)
+	// the blocks of the test chain carry no endorsements, and only the genesis block is final
+	height, err = svr.BlockHeightByTag(BlockTagFinalized)
+	require.NoError(err)
+	require.Zero(height)
+	svr.cfg.API.Finality.Mode = config.FinalityCheckpoint
+	height, err = svr.BlockHeightByTag(BlockTagFinalized)
+	require.NoError(err)
+	require.Zero(height)
+	_, err = svr.BlockHeightByTag("pending")
+	sta, ok := status.FromError(err)
+	require.True(ok)
+	require.Equal(codes.InvalidArgument, sta.Code())
+
+	rp := rolldpos.NewProtocol(24, 24, 1)
+	for _, test := range []struct {
+		tip, checkpoint uint64
+	}{
+		{10, 0},
+		{24, 24},
+		{30, 24},
+		{72, 72},
+		{73, 72},
+	} {
+		require.Equal(test.checkpoint, lastCheckpoint(rp, test.tip))
+	}
+}
+
 func TestServer_TotalBalance(t *testing.T) {
 	require := require.New(t)
 	cfg := newConfig(t)
@@ -1949,6 +2151,61 @@ func TestServer_GetRawBlocks(t *testing.T) {
 		require.Equal(test.numActions, numActions)
 		require.Equal(test.numReceipts, numReceipts)
 	}
+
+	// the blocks exceeding the size limit are not returned, except the first one
+	svr.cfg.API.RawBlocksMaxBytes = 1
+	res, err := svr.GetRawBlocks(context.Background(), &iotexapi.GetRawBlocksRequest{
+		StartHeight:  1,
+		Count:        2,
+		WithReceipts: true,
+	})
+	require.NoError(err)
+	require.Len(res.Blocks, 1)
+	require.Equal(uint64(1), res.Blocks[0].GetBlock().GetHeader().GetCore().GetHeight())
+}
+
+func TestServer_GetReceiptsByBlocks(t *testing.T) {
+	require := require.New(t)
+	cfg := newConfig(t)
+
+	svr, bfIndexFile, err := createServer(cfg, false)
+	require.NoError(err)
+	defer func() {
+		testutil.CleanupPath(t, bfIndexFile)
+	}()
+
+	res, err := svr.GetReceiptsByBlocks(context.Background(), &apipb.GetReceiptsByBlocksRequest{
+		StartHeight: 1,
+		Count:       2,
+	})
+	require.NoError(err)
+	require.Len(res.Blocks, 2)
+	var numReceipts int
+	for i, blk := range res.Blocks {
+		require.Equal(uint64(i+1), blk.Height)
+		numReceipts += len(blk.Receipts)
+	}
+	require.Equal(9, numReceipts)
+
+	for _, request := range []*apipb.GetReceiptsByBlocksRequest{
+		{StartHeight: 1, Count: 0},
+		{StartHeight: 0, Count: 1},
+		{StartHeight: svr.bc.TipHeight() + 1, Count: 1},
+		{StartHeight: 1, Count: cfg.API.RangeQueryLimit + 1},
+	} {
+		_, err := svr.GetReceiptsByBlocks(context.Background(), request)
+		require.Equal(codes.InvalidArgument, status.Code(err))
+	}
+
+	// the receipts exceeding the size limit are not returned, except the first block's
+	svr.cfg.API.RawBlocksMaxBytes = 1
+	res, err = svr.GetReceiptsByBlocks(context.Background(), &apipb.GetReceiptsByBlocksRequest{
+		StartHeight: 1,
+		Count:       2,
+	})
+	require.NoError(err)
+	require.Len(res.Blocks, 1)
+	require.Equal(uint64(1), res.Blocks[0].Height)
 }
 
 func TestServer_GetLogs(t *testing.T) {
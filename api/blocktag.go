@@ -0,0 +1,109 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package api
+
+import (
+	"github.com/iotexproject/iotex-address/address"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/iotexproject/iotex-core/action/protocol/rolldpos"
+	"github.com/iotexproject/iotex-core/config"
+)
+
+const (
+	// BlockTagLatest is the tag of the tip block
+	BlockTagLatest = "latest"
+	// BlockTagSafe is the tag of the block unlikely to be reverted, which is config.Finality.SafeDepth below the tip
+	BlockTagSafe = "safe"
+	// BlockTagFinalized is the tag of the highest finalized block, see config.Finality for the modes
+	BlockTagFinalized = "finalized"
+)
+
+func isBlockTag(tag string) bool {
+	switch tag {
+	case BlockTagLatest, BlockTagSafe, BlockTagFinalized:
+		return true
+	default:
+		return false
+	}
+}
+
+// BlockHeightByTag returns the height of the block the tag is resolved to
+func (api *Server) BlockHeightByTag(tag string) (uint64, error) {
+	if !isBlockTag(tag) {
+		return 0, status.Errorf(codes.InvalidArgument, "unknown block tag %s", tag)
+	}
+	height, err := api.blockHeightByTag(api.bc.TipHeight(), tag)
+	if err != nil {
+		return 0, status.Error(codes.Unavailable, err.Error())
+	}
+	return height, nil
+}
+
+func (api *Server) blockHeightByTag(tipHeight uint64, tag string) (uint64, error) {
+	switch tag {
+	case BlockTagSafe:
+		if depth := api.cfg.API.Finality.SafeDepth; tipHeight > depth {
+			return tipHeight - depth, nil
+		}
+		return 0, nil
+	case BlockTagFinalized:
+		rp := rolldpos.FindProtocol(api.registry)
+		if rp == nil {
+			return 0, errors.New("rolldpos is not registered")
+		}
+		if api.cfg.API.Finality.Mode == config.FinalityCheckpoint {
+			return lastCheckpoint(rp, tipHeight), nil
+		}
+		return api.lastEndorsedHeight(rp, tipHeight)
+	default:
+		return tipHeight, nil
+	}
+}
+
+// lastCheckpoint returns the last block of the latest ended epoch
+func lastCheckpoint(rp *rolldpos.Protocol, tipHeight uint64) uint64 {
+	epochNum := rp.GetEpochNum(tipHeight)
+	if tipHeight == rp.GetEpochLastBlockHeight(epochNum) {
+		return tipHeight
+	}
+	if epochNum <= 1 {
+		return 0
+	}
+	return rp.GetEpochLastBlockHeight(epochNum - 1)
+}
+
+// lastEndorsedHeight returns the highest block within an epoch below the tip, which is committed with the
+// endorsements of more than 2/3 of the delegates
+func (api *Server) lastEndorsedHeight(rp *rolldpos.Protocol, tipHeight uint64) (uint64, error) {
+	numDelegates := rp.NumDelegates()
+	window := numDelegates * rp.NumSubEpochs(tipHeight)
+	for height := tipHeight; height > 0 && height+window > tipHeight; height-- {
+		footer, err := api.dao.FooterByHeight(height)
+		if err != nil {
+			return 0, err
+		}
+		endorsers := make(map[string]bool)
+		for _, en := range footer.Endorsements() {
+			addr, err := address.FromBytes(en.Endorser().Hash())
+			if err != nil {
+				return 0, err
+			}
+			endorsers[addr.String()] = true
+		}
+		if uint64(len(endorsers))*3 > numDelegates*2 {
+			return height, nil
+		}
+	}
+	if tipHeight < window {
+		// the genesis block is always final
+		return 0, nil
+	}
+	return 0, errors.Errorf("no block is committed with enough endorsements in the last %d blocks", window)
+}
@@ -0,0 +1,98 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package api
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+
+	"github.com/golang/snappy"
+	"github.com/klauspost/compress/zstd"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/encoding"
+	// registers the gzip compressor
+	_ "google.golang.org/grpc/encoding/gzip"
+
+	"github.com/iotexproject/iotex-core/pkg/log"
+)
+
+const (
+	// SnappyCompressorName is the name of the snappy compressor of the grpc messages. A client calling with
+	// grpc.UseCompressor(SnappyCompressorName), ZstdCompressorName or "gzip" gets the responses compressed in the same
+	// way, which saves the bandwidth of the bulk retrievals like GetRawBlocks
+	SnappyCompressorName = "snappy"
+	// ZstdCompressorName is the name of the zstd compressor of the grpc messages
+	ZstdCompressorName = "zstd"
+)
+
+type (
+	snappyCompressor struct{}
+
+	// zstdCompressor compresses the whole message at once with the encoder and decoder shared by the calls, since the
+	// grpc messages are buffered anyway, and the zstd encoders and decoders are expensive to create
+	zstdCompressor struct {
+		encoder *zstd.Encoder
+		decoder *zstd.Decoder
+	}
+
+	zstdWriter struct {
+		bytes.Buffer
+		w       io.Writer
+		encoder *zstd.Encoder
+	}
+)
+
+func init() {
+	encoding.RegisterCompressor(snappyCompressor{})
+	encoder, err := zstd.NewWriter(nil)
+	if err != nil {
+		log.L().Panic("Failed to create zstd encoder.", zap.Error(err))
+	}
+	decoder, err := zstd.NewReader(nil)
+	if err != nil {
+		log.L().Panic("Failed to create zstd decoder.", zap.Error(err))
+	}
+	encoding.RegisterCompressor(&zstdCompressor{encoder: encoder, decoder: decoder})
+}
+
+func (snappyCompressor) Compress(w io.Writer) (io.WriteCloser, error) {
+	return snappy.NewBufferedWriter(w), nil
+}
+
+func (snappyCompressor) Decompress(r io.Reader) (io.Reader, error) {
+	return snappy.NewReader(r), nil
+}
+
+func (snappyCompressor) Name() string {
+	return SnappyCompressorName
+}
+
+func (c *zstdCompressor) Compress(w io.Writer) (io.WriteCloser, error) {
+	return &zstdWriter{w: w, encoder: c.encoder}, nil
+}
+
+func (c *zstdCompressor) Decompress(r io.Reader) (io.Reader, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	data, err = c.decoder.DecodeAll(data, nil)
+	if err != nil {
+		return nil, err
+	}
+	return bytes.NewReader(data), nil
+}
+
+func (c *zstdCompressor) Name() string {
+	return ZstdCompressorName
+}
+
+func (zw *zstdWriter) Close() error {
+	_, err := zw.w.Write(zw.encoder.EncodeAll(zw.Bytes(), nil))
+	return err
+}
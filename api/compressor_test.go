@@ -0,0 +1,38 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package api
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/encoding"
+)
+
+func TestCompressors(t *testing.T) {
+	require := require.New(t)
+
+	data := bytes.Repeat([]byte("block"), 1000)
+	for _, name := range []string{SnappyCompressorName, ZstdCompressorName, "gzip"} {
+		c := encoding.GetCompressor(name)
+		require.NotNil(c)
+		var buf bytes.Buffer
+		w, err := c.Compress(&buf)
+		require.NoError(err)
+		_, err = w.Write(data)
+		require.NoError(err)
+		require.NoError(w.Close())
+		require.True(buf.Len() < len(data))
+		r, err := c.Decompress(&buf)
+		require.NoError(err)
+		decompressed, err := ioutil.ReadAll(r)
+		require.NoError(err)
+		require.Equal(data, decompressed)
+	}
+}
@@ -0,0 +1,314 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/iotexproject/iotex-proto/golang/iotextypes"
+
+	"github.com/iotexproject/iotex-core/action"
+	"github.com/iotexproject/iotex-core/action/protocol"
+	"github.com/iotexproject/iotex-core/action/protocol/poll"
+	"github.com/iotexproject/iotex-core/db"
+	"github.com/iotexproject/iotex-core/pkg/log"
+	"github.com/iotexproject/iotex-core/state"
+	"github.com/iotexproject/iotex-core/state/factory"
+)
+
+// ErrorDomain is the domain of the error info attached to the API errors
+const ErrorDomain = "iotex.io"
+
+// Locales of the error messages
+const (
+	LocaleEnglish = "en"
+	LocaleChinese = "zh"
+)
+
+// ErrorCode is the stable machine-readable code of an API error, attached to the error as the reason of the error
+// info, and set to the failed receipts by ReceiptErrorCode
+type ErrorCode string
+
+// Codes of the API errors
+const (
+	ErrCodeInternal               ErrorCode = "INTERNAL"
+	ErrCodeInvalidArgument        ErrorCode = "INVALID_ARGUMENT"
+	ErrCodeNotFound               ErrorCode = "NOT_FOUND"
+	ErrCodeFutureHeight           ErrorCode = "FUTURE_HEIGHT"
+	ErrCodeNotActivated           ErrorCode = "NOT_ACTIVATED"
+	ErrCodeNotSupported           ErrorCode = "NOT_SUPPORTED"
+	ErrCodeNonceTooLow            ErrorCode = "NONCE_TOO_LOW"
+	ErrCodeNonceTooHigh           ErrorCode = "NONCE_TOO_HIGH"
+	ErrCodeNonceUsed              ErrorCode = "NONCE_USED"
+	ErrCodeInvalidNonce           ErrorCode = "INVALID_NONCE"
+	ErrCodeInsufficientBalance    ErrorCode = "INSUFFICIENT_BALANCE"
+	ErrCodeBalanceReservation     ErrorCode = "BALANCE_RESERVATION"
+	ErrCodeGasPriceTooLow         ErrorCode = "GAS_PRICE_TOO_LOW"
+	ErrCodeAddressBlacklisted     ErrorCode = "ADDRESS_BLACKLISTED"
+	ErrCodeActPoolFull            ErrorCode = "ACTPOOL_FULL"
+	ErrCodeOutOfGas               ErrorCode = "OUT_OF_GAS"
+	ErrCodeExecutionReverted      ErrorCode = "EXECUTION_REVERTED"
+	ErrCodeBucketNotMatured       ErrorCode = "BUCKET_NOT_MATURED"
+	ErrCodeBucketNotUnstaked      ErrorCode = "BUCKET_NOT_UNSTAKED"
+	ErrCodeBucketNotFound         ErrorCode = "BUCKET_NOT_FOUND"
+	ErrCodeInvalidBucketAmount    ErrorCode = "INVALID_BUCKET_AMOUNT"
+	ErrCodeInvalidBucketType      ErrorCode = "INVALID_BUCKET_TYPE"
+	ErrCodeCandidateAlreadyExists ErrorCode = "CANDIDATE_ALREADY_EXISTS"
+	ErrCodeCandidateConflict      ErrorCode = "CANDIDATE_CONFLICT"
+	ErrCodeCandidateNotFound      ErrorCode = "CANDIDATE_NOT_FOUND"
+	ErrCodeUnauthorizedOperator   ErrorCode = "UNAUTHORIZED_OPERATOR"
+)
+
+// _errorMessages are the human messages of the error codes in the locales
+var _errorMessages = map[ErrorCode]map[string]string{
+	ErrCodeInternal: {
+		LocaleEnglish: "Internal error of the node, please retry later.",
+		LocaleChinese: "节点内部错误，请稍后重试。",
+	},
+	ErrCodeInvalidArgument: {
+		LocaleEnglish: "The request is invalid.",
+		LocaleChinese: "请求无效。",
+	},
+	ErrCodeNotFound: {
+		LocaleEnglish: "The requested data is not found.",
+		LocaleChinese: "未找到请求的数据。",
+	},
+	ErrCodeFutureHeight: {
+		LocaleEnglish: "The requested height or epoch is not reached yet.",
+		LocaleChinese: "请求的高度或纪元尚未到达。",
+	},
+	ErrCodeNotActivated: {
+		LocaleEnglish: "The feature is not activated at the requested height.",
+		LocaleChinese: "该功能在请求的高度尚未激活。",
+	},
+	ErrCodeNotSupported: {
+		LocaleEnglish: "The request is not supported by the node.",
+		LocaleChinese: "该节点不支持此请求。",
+	},
+	ErrCodeNonceTooLow: {
+		LocaleEnglish: "Nonce too low, the nonce is already used by a confirmed action. Use the pending nonce of the account.",
+		LocaleChinese: "nonce 过低，已被确认的交易使用。请使用账户的待处理 nonce。",
+	},
+	ErrCodeNonceTooHigh: {
+		LocaleEnglish: "Nonce too high, too many pending actions of the account. Wait for them to be confirmed.",
+		LocaleChinese: "nonce 过高，账户的待处理交易过多。请等待它们被确认。",
+	},
+	ErrCodeNonceUsed: {
+		LocaleEnglish: "The nonce is used by a pending action. Raise the gas price to replace it, or use the next nonce.",
+		LocaleChinese: "该 nonce 已被待处理的交易使用。请提高 gas 价格以替换它，或使用下一个 nonce。",
+	},
+	ErrCodeInvalidNonce: {
+		LocaleEnglish: "Invalid nonce.",
+		LocaleChinese: "nonce 无效。",
+	},
+	ErrCodeInsufficientBalance: {
+		LocaleEnglish: "Insufficient balance for the amount and the gas of the action.",
+		LocaleChinese: "余额不足以支付交易的金额和 gas。",
+	},
+	ErrCodeBalanceReservation: {
+		LocaleEnglish: "The transfer leaves the sender below the minimum balance reserved for the future gas, lower the amount.",
+		LocaleChinese: "该转账会使发送账户的余额低于为后续 gas 保留的最低余额，请降低转账金额。",
+	},
+	ErrCodeGasPriceTooLow: {
+		LocaleEnglish: "Gas price too low, raise it to at least the minimal gas price of the node.",
+		LocaleChinese: "gas 价格过低，请提高到节点的最低 gas 价格以上。",
+	},
+	ErrCodeAddressBlacklisted: {
+		LocaleEnglish: "The sender address is not allowed to send actions.",
+		LocaleChinese: "发送地址不允许发送交易。",
+	},
+	ErrCodeActPoolFull: {
+		LocaleEnglish: "The action pool of the node is full, please retry later.",
+		LocaleChinese: "节点的交易池已满，请稍后重试。",
+	},
+	ErrCodeOutOfGas: {
+		LocaleEnglish: "Out of gas, raise the gas limit of the action.",
+		LocaleChinese: "gas 不足，请提高交易的 gas 上限。",
+	},
+	ErrCodeExecutionReverted: {
+		LocaleEnglish: "The contract execution is reverted.",
+		LocaleChinese: "合约执行被回滚。",
+	},
+	ErrCodeBucketNotMatured: {
+		LocaleEnglish: "The bucket is not matured yet, wait until the end of its stake duration.",
+		LocaleChinese: "投票桶尚未到期，请等到质押期结束。",
+	},
+	ErrCodeBucketNotUnstaked: {
+		LocaleEnglish: "The bucket must be unstaked before withdrawn.",
+		LocaleChinese: "投票桶必须先解除质押才能提取。",
+	},
+	ErrCodeBucketNotFound: {
+		LocaleEnglish: "The bucket does not exist or is not owned by the sender.",
+		LocaleChinese: "投票桶不存在或不属于发送者。",
+	},
+	ErrCodeInvalidBucketAmount: {
+		LocaleEnglish: "Invalid bucket amount.",
+		LocaleChinese: "投票桶金额无效。",
+	},
+	ErrCodeInvalidBucketType: {
+		LocaleEnglish: "The operation is not allowed on this type of bucket.",
+		LocaleChinese: "该类型的投票桶不允许此操作。",
+	},
+	ErrCodeCandidateAlreadyExists: {
+		LocaleEnglish: "The candidate is already registered.",
+		LocaleChinese: "候选人已注册。",
+	},
+	ErrCodeCandidateConflict: {
+		LocaleEnglish: "The name or the operator address is used by another candidate.",
+		LocaleChinese: "名称或操作地址已被其他候选人使用。",
+	},
+	ErrCodeCandidateNotFound: {
+		LocaleEnglish: "The candidate does not exist.",
+		LocaleChinese: "候选人不存在。",
+	},
+	ErrCodeUnauthorizedOperator: {
+		LocaleEnglish: "The sender is not authorized to operate the bucket or the candidate.",
+		LocaleChinese: "发送者无权操作该投票桶或候选人。",
+	},
+}
+
+// _receiptErrorCodes are the error codes of the failed receipt statuses
+var _receiptErrorCodes = map[iotextypes.ReceiptStatus]ErrorCode{
+	iotextypes.ReceiptStatus_ErrUnknown:                      ErrCodeInternal,
+	iotextypes.ReceiptStatus_ErrOutOfGas:                     ErrCodeOutOfGas,
+	iotextypes.ReceiptStatus_ErrCodeStoreOutOfGas:            ErrCodeOutOfGas,
+	iotextypes.ReceiptStatus_ErrExecutionReverted:            ErrCodeExecutionReverted,
+	iotextypes.ReceiptStatus_ErrNotEnoughBalance:             ErrCodeInsufficientBalance,
+	iotextypes.ReceiptStatus_ErrUnstakeBeforeMaturity:        ErrCodeBucketNotMatured,
+	iotextypes.ReceiptStatus_ErrWithdrawBeforeMaturity:       ErrCodeBucketNotMatured,
+	iotextypes.ReceiptStatus_ErrReduceDurationBeforeMaturity: ErrCodeBucketNotMatured,
+	iotextypes.ReceiptStatus_ErrWithdrawBeforeUnstake:        ErrCodeBucketNotUnstaked,
+	iotextypes.ReceiptStatus_ErrInvalidBucketIndex:           ErrCodeBucketNotFound,
+	iotextypes.ReceiptStatus_ErrInvalidBucketAmount:          ErrCodeInvalidBucketAmount,
+	iotextypes.ReceiptStatus_ErrInvalidBucketType:            ErrCodeInvalidBucketType,
+	iotextypes.ReceiptStatus_ErrCandidateAlreadyExist:        ErrCodeCandidateAlreadyExists,
+	iotextypes.ReceiptStatus_ErrCandidateConflict:            ErrCodeCandidateConflict,
+	iotextypes.ReceiptStatus_ErrCandidateNotExist:            ErrCodeCandidateNotFound,
+	iotextypes.ReceiptStatus_ErrUnauthorizedOperator:         ErrCodeUnauthorizedOperator,
+}
+
+// ErrorCatalog returns the messages of all the error codes in the locale, for the clients to bundle the messages
+func ErrorCatalog(locale string) map[ErrorCode]string {
+	locale = normalizeLocale(locale)
+	catalog := make(map[ErrorCode]string, len(_errorMessages))
+	for code, messages := range _errorMessages {
+		catalog[code] = messages[locale]
+	}
+	return catalog
+}
+
+// ErrorMessage returns the message of the error code in the locale, defaulting to English
+func ErrorMessage(code ErrorCode, locale string) string {
+	messages, ok := _errorMessages[code]
+	if !ok {
+		messages = _errorMessages[ErrCodeInternal]
+	}
+	return messages[normalizeLocale(locale)]
+}
+
+// ReceiptErrorCode returns the error code of the receipt status, and false if the receipt is successful
+func ReceiptErrorCode(receiptStatus uint64) (ErrorCode, bool) {
+	s := iotextypes.ReceiptStatus(receiptStatus)
+	if s == iotextypes.ReceiptStatus_Success {
+		return "", false
+	}
+	if code, ok := _receiptErrorCodes[s]; ok {
+		return code, true
+	}
+	return ErrCodeInternal, true
+}
+
+// errorCode classifies the error into the error code
+func errorCode(err error) ErrorCode {
+	switch errors.Cause(err) {
+	case action.ErrNonce:
+		// the nonce errors share the cause, and are told apart by the messages wrapping the cause
+		msg := err.Error()
+		switch {
+		case strings.Contains(msg, "too low"):
+			return ErrCodeNonceTooLow
+		case strings.Contains(msg, "too large"):
+			return ErrCodeNonceTooHigh
+		case strings.Contains(msg, "duplicate"):
+			return ErrCodeNonceUsed
+		default:
+			return ErrCodeInvalidNonce
+		}
+	case action.ErrBalance, action.ErrInsufficientBalanceForGas:
+		return ErrCodeInsufficientBalance
+	case action.ErrBalanceReservation:
+		return ErrCodeBalanceReservation
+	case action.ErrGasPrice:
+		return ErrCodeGasPriceTooLow
+	case action.ErrAddress:
+		return ErrCodeAddressBlacklisted
+	case action.ErrActPool:
+		return ErrCodeActPoolFull
+	case action.ErrOutOfGas, action.ErrHitGasLimit:
+		return ErrCodeOutOfGas
+	case protocol.ErrInvalidArgument:
+		return ErrCodeInvalidArgument
+	case protocol.ErrNotFound, state.ErrStateNotExist, db.ErrNotExist, poll.ErrIndexerNotExist:
+		return ErrCodeNotFound
+	case protocol.ErrFutureEpoch, protocol.ErrFutureHeight:
+		return ErrCodeFutureHeight
+	case protocol.ErrPreActivation, factory.ErrNoArchiveData:
+		return ErrCodeNotActivated
+	case protocol.ErrUnimplemented, factory.ErrNotSupported:
+		return ErrCodeNotSupported
+	default:
+		return ErrCodeInternal
+	}
+}
+
+// requestLocale returns the locale of the accept-language metadata of the request
+func requestLocale(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return LocaleEnglish
+	}
+	values := md.Get("accept-language")
+	if len(values) == 0 {
+		return LocaleEnglish
+	}
+	return normalizeLocale(values[0])
+}
+
+func normalizeLocale(locale string) string {
+	if strings.HasPrefix(strings.ToLower(strings.TrimSpace(locale)), LocaleChinese) {
+		return LocaleChinese
+	}
+	return LocaleEnglish
+}
+
+// withErrorInfo attaches the error code and the localized message of the error to the status
+func withErrorInfo(ctx context.Context, st *status.Status, err error, details ...proto.Message) error {
+	code := errorCode(err)
+	locale := requestLocale(ctx)
+	details = append(details,
+		&errdetails.ErrorInfo{Reason: string(code), Domain: ErrorDomain},
+		&errdetails.LocalizedMessage{Locale: locale, Message: ErrorMessage(code, locale)},
+	)
+	st, e := st.WithDetails(details...)
+	if e != nil {
+		log.S().Panicf("Unexpected error attaching metadata: %v", e)
+	}
+	return st.Err()
+}
+
+// catalogError returns the status error of the code with the error code and the localized message of the error
+func catalogError(ctx context.Context, c codes.Code, err error) error {
+	return withErrorInfo(ctx, status.New(c, err.Error()), err)
+}
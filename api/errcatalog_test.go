@@ -0,0 +1,82 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/iotexproject/iotex-proto/golang/iotextypes"
+
+	"github.com/iotexproject/iotex-core/action"
+	"github.com/iotexproject/iotex-core/action/protocol"
+)
+
+func TestErrorCode(t *testing.T) {
+	require := require.New(t)
+
+	for _, c := range []struct {
+		err  error
+		code ErrorCode
+	}{
+		{errors.Wrapf(action.ErrNonce, "nonce is too low"), ErrCodeNonceTooLow},
+		{errors.Wrapf(action.ErrNonce, "nonce too large"), ErrCodeNonceTooHigh},
+		{errors.Wrapf(action.ErrNonce, "duplicate nonce"), ErrCodeNonceUsed},
+		{action.ErrNonce, ErrCodeInvalidNonce},
+		{errors.Wrap(action.ErrInsufficientBalanceForGas, "insufficient balance"), ErrCodeInsufficientBalance},
+		{errors.Wrap(action.ErrBalanceReservation, "below reservation"), ErrCodeBalanceReservation},
+		{errors.Wrap(action.ErrGasPrice, "gas price too low"), ErrCodeGasPriceTooLow},
+		{errors.Wrap(action.ErrActPool, "insufficient space"), ErrCodeActPoolFull},
+		{errors.Wrap(protocol.ErrNotFound, "bucket"), ErrCodeNotFound},
+		{errors.New("unknown"), ErrCodeInternal},
+	} {
+		require.Equal(c.code, errorCode(c.err))
+	}
+
+	code, failed := ReceiptErrorCode(uint64(iotextypes.ReceiptStatus_ErrWithdrawBeforeMaturity))
+	require.True(failed)
+	require.Equal(ErrCodeBucketNotMatured, code)
+	_, failed = ReceiptErrorCode(uint64(iotextypes.ReceiptStatus_Success))
+	require.False(failed)
+}
+
+func TestErrorCatalog(t *testing.T) {
+	require := require.New(t)
+
+	// every code has the messages in all the locales
+	for _, locale := range []string{LocaleEnglish, LocaleChinese} {
+		for code, msg := range ErrorCatalog(locale) {
+			require.NotEmpty(msg, "%s in %s", code, locale)
+		}
+	}
+	require.Equal(ErrorMessage(ErrCodeNonceTooLow, LocaleEnglish), ErrorMessage(ErrCodeNonceTooLow, "fr-FR"))
+	require.Equal(ErrorMessage(ErrCodeNonceTooLow, LocaleChinese), ErrorMessage(ErrCodeNonceTooLow, "zh-CN"))
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("accept-language", "zh-CN,zh;q=0.9"))
+	err := errors.Wrap(action.ErrNonce, "nonce is too low")
+	st, ok := status.FromError(catalogError(ctx, codes.Internal, err))
+	require.True(ok)
+	require.Equal(codes.Internal, st.Code())
+	require.Equal(err.Error(), st.Message())
+	details := st.Details()
+	require.Len(details, 2)
+	info, ok := details[0].(*errdetails.ErrorInfo)
+	require.True(ok)
+	require.Equal(string(ErrCodeNonceTooLow), info.Reason)
+	require.Equal(ErrorDomain, info.Domain)
+	msg, ok := details[1].(*errdetails.LocalizedMessage)
+	require.True(ok)
+	require.Equal(LocaleChinese, msg.Locale)
+	require.Equal(ErrorMessage(ErrCodeNonceTooLow, LocaleChinese), msg.Message)
+}
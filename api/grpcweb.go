@@ -0,0 +1,289 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package api
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/binary"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/pkg/log"
+	"github.com/iotexproject/iotex-core/pkg/util/httputil"
+	"github.com/iotexproject/iotex-core/pkg/util/tlsutil"
+)
+
+const (
+	_grpcWebContentType     = "application/grpc-web"
+	_grpcWebTextContentType = "application/grpc-web-text"
+	// _grpcWebTrailerFlag is the flag of the frame carrying the trailers at the end of a grpc-web response body
+	_grpcWebTrailerFlag = 0x80
+)
+
+var (
+	// _grpcWebHeaders are the request headers a grpc-web client sends, which are always allowed by CORS
+	_grpcWebHeaders = []string{
+		"content-type",
+		"x-grpc-web",
+		"x-user-agent",
+		"grpc-timeout",
+		ChainIDMetadataKey,
+		DeadlineMetadataKey,
+	}
+	// _grpcWebExposedHeaders are the response headers a grpc-web client reads
+	_grpcWebExposedHeaders = "grpc-status, grpc-message, grpc-status-details-bin"
+)
+
+type (
+	// grpcWebHandler serves the grpc server to browsers by grpc-web, which is grpc over HTTP/1.1 with the trailers
+	// framed at the end of the response body, and checks the origins of the cross-origin requests by CORS
+	grpcWebHandler struct {
+		grpcServer *grpc.Server
+		cors       config.CORS
+		headers    map[string]bool
+	}
+
+	// grpcWebResponse translates the grpc response into grpc-web
+	grpcWebResponse struct {
+		w           http.ResponseWriter
+		contentType string
+		text        bool
+		buf         bytes.Buffer
+		trailers    []string
+		wroteHeader bool
+	}
+)
+
+// newGRPCWebServer creates the http server of grpc-web, which serves with the TLS config of the api if any
+func newGRPCWebServer(cfg config.API, grpcServer *grpc.Server) (*http.Server, error) {
+	server := httputil.Server(":"+strconv.Itoa(cfg.GRPCWeb.Port), newGRPCWebHandler(grpcServer, cfg.GRPCWeb.CORS))
+	// the server streams last as long as the clients subscribe
+	server.WriteTimeout = 0
+	if cfg.TLS.CertFile != "" {
+		c, err := tlsutil.NewServerConfig(
+			cfg.TLS.CertFile,
+			cfg.TLS.KeyFile,
+			cfg.TLS.ClientCAFile,
+			cfg.TLS.AllowedClients,
+			cfg.TLS.ReloadInterval,
+		)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create tls config of grpc-web server")
+		}
+		server.TLSConfig = c
+	}
+	return &server, nil
+}
+
+// startGRPCWeb starts serving grpc-web
+func (api *Server) startGRPCWeb() error {
+	ln, err := httputil.LimitListener(api.grpcWebServer.Addr)
+	if err != nil {
+		return errors.Wrap(err, "grpc-web server failed to listen")
+	}
+	if api.grpcWebServer.TLSConfig != nil {
+		ln = tls.NewListener(ln, api.grpcWebServer.TLSConfig)
+	}
+	log.L().Info("grpc-web server is listening.", zap.String("addr", ln.Addr().String()))
+	go func() {
+		if err := api.grpcWebServer.Serve(ln); err != nil && err != http.ErrServerClosed {
+			log.L().Fatal("Node failed to serve grpc-web.", zap.Error(err))
+		}
+	}()
+	return nil
+}
+
+func newGRPCWebHandler(grpcServer *grpc.Server, cors config.CORS) *grpcWebHandler {
+	headers := make(map[string]bool, len(_grpcWebHeaders)+len(cors.AllowedHeaders))
+	for _, h := range append(_grpcWebHeaders, cors.AllowedHeaders...) {
+		headers[strings.ToLower(h)] = true
+	}
+	return &grpcWebHandler{
+		grpcServer: grpcServer,
+		cors:       cors,
+		headers:    headers,
+	}
+}
+
+func (h *grpcWebHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if origin := r.Header.Get("Origin"); origin != "" {
+		w.Header().Add("Vary", "Origin")
+		if !h.allowedOrigin(origin) {
+			http.Error(w, "origin not allowed", http.StatusForbidden)
+			return
+		}
+		w.Header().Set("Access-Control-Allow-Origin", origin)
+		if h.cors.AllowCredentials {
+			w.Header().Set("Access-Control-Allow-Credentials", "true")
+		}
+		if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+			h.preflight(w, r)
+			return
+		}
+		w.Header().Set("Access-Control-Expose-Headers", _grpcWebExposedHeaders)
+	}
+	contentType := r.Header.Get("Content-Type")
+	if r.Method != http.MethodPost || !strings.HasPrefix(contentType, _grpcWebContentType) {
+		http.Error(w, "grpc-web request is required", http.StatusUnsupportedMediaType)
+		return
+	}
+	text := strings.HasPrefix(contentType, _grpcWebTextContentType)
+
+	// the grpc server serves the request as HTTP/2 grpc
+	req := r.Clone(r.Context())
+	req.ProtoMajor, req.ProtoMinor, req.Proto = 2, 0, "HTTP/2"
+	req.Header.Set("Content-Type", "application/grpc")
+	req.Header.Del("Content-Length")
+	if text {
+		req.Body = ioutil.NopCloser(base64.NewDecoder(base64.StdEncoding, r.Body))
+	}
+	res := &grpcWebResponse{
+		w:           w,
+		contentType: contentType,
+		text:        text,
+	}
+	h.grpcServer.ServeHTTP(res, req)
+	res.finish()
+}
+
+// preflight responds to the CORS preflight request, allowing POST with the grpc-web headers and the configured ones
+func (h *grpcWebHandler) preflight(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Access-Control-Request-Method") != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusForbidden)
+		return
+	}
+	var requested []string
+	for _, header := range strings.Split(r.Header.Get("Access-Control-Request-Headers"), ",") {
+		header = strings.ToLower(strings.TrimSpace(header))
+		if header == "" {
+			continue
+		}
+		if !h.headers[header] {
+			http.Error(w, "header "+header+" not allowed", http.StatusForbidden)
+			return
+		}
+		requested = append(requested, header)
+	}
+	w.Header().Set("Access-Control-Allow-Methods", http.MethodPost)
+	if len(requested) > 0 {
+		w.Header().Set("Access-Control-Allow-Headers", strings.Join(requested, ", "))
+	}
+	if h.cors.MaxAge > 0 {
+		w.Header().Set("Access-Control-Max-Age", strconv.Itoa(int(h.cors.MaxAge.Seconds())))
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// allowedOrigin returns true if the origin matches the allowlist, where "*" matches any origin and a wildcard
+// subdomain like "https://*.example.com" matches the subdomains of the domain
+func (h *grpcWebHandler) allowedOrigin(origin string) bool {
+	for _, allowed := range h.cors.AllowedOrigins {
+		if allowed == "*" || strings.EqualFold(allowed, origin) {
+			return true
+		}
+		if i := strings.Index(allowed, "*."); i >= 0 {
+			prefix, suffix := allowed[:i], allowed[i+1:]
+			if len(origin) > len(prefix)+len(suffix) &&
+				strings.HasPrefix(strings.ToLower(origin), strings.ToLower(prefix)) &&
+				strings.HasSuffix(strings.ToLower(origin), strings.ToLower(suffix)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (res *grpcWebResponse) Header() http.Header { return res.w.Header() }
+
+func (res *grpcWebResponse) WriteHeader(code int) {
+	if res.wroteHeader {
+		return
+	}
+	res.wroteHeader = true
+	h := res.w.Header()
+	// the trailers are written in the body instead
+	res.trailers = h["Trailer"]
+	h.Del("Trailer")
+	h.Set("Content-Type", res.contentType)
+	res.w.WriteHeader(code)
+}
+
+func (res *grpcWebResponse) Write(b []byte) (int, error) {
+	if !res.wroteHeader {
+		res.WriteHeader(http.StatusOK)
+	}
+	if res.text {
+		return res.buf.Write(b)
+	}
+	return res.w.Write(b)
+}
+
+// Flush writes the buffered response, which is encoded in base64 in text mode
+func (res *grpcWebResponse) Flush() {
+	if res.text && res.buf.Len() > 0 {
+		data := base64.StdEncoding.EncodeToString(res.buf.Bytes())
+		res.buf.Reset()
+		if _, err := res.w.Write([]byte(data)); err != nil {
+			return
+		}
+	}
+	if f, ok := res.w.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// finish writes the trailers set by the grpc server into the trailer frame
+func (res *grpcWebResponse) finish() {
+	if !res.wroteHeader {
+		res.WriteHeader(http.StatusOK)
+	}
+	h := res.w.Header()
+	trailers := make(map[string][]string)
+	for _, names := range res.trailers {
+		for _, name := range strings.Split(names, ",") {
+			name = strings.TrimSpace(name)
+			if values := h[http.CanonicalHeaderKey(name)]; len(values) > 0 {
+				trailers[strings.ToLower(name)] = values
+			}
+		}
+	}
+	for name, values := range h {
+		if strings.HasPrefix(name, http.TrailerPrefix) {
+			trailers[strings.ToLower(strings.TrimPrefix(name, http.TrailerPrefix))] = values
+			// not to be sent as the http trailers
+			h.Del(name)
+		}
+	}
+	names := make([]string, 0, len(trailers))
+	for name := range trailers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var payload bytes.Buffer
+	for _, name := range names {
+		for _, v := range trailers[name] {
+			payload.WriteString(name + ": " + v + "\r\n")
+		}
+	}
+	frame := make([]byte, 5, 5+payload.Len())
+	frame[0] = _grpcWebTrailerFlag
+	binary.BigEndian.PutUint32(frame[1:], uint32(payload.Len()))
+	if _, err := res.Write(append(frame, payload.Bytes()...)); err != nil {
+		return
+	}
+	res.Flush()
+}
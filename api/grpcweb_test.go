@@ -0,0 +1,100 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package api
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	"github.com/iotexproject/iotex-core/config"
+)
+
+func TestGRPCWebHandler(t *testing.T) {
+	require := require.New(t)
+
+	grpcServer := grpc.NewServer()
+	healthpb.RegisterHealthServer(grpcServer, health.NewServer())
+	h := newGRPCWebHandler(grpcServer, config.CORS{
+		AllowedOrigins: []string{"https://dapp.io", "https://*.example.com"},
+		AllowedHeaders: []string{"Authorization"},
+		MaxAge:         time.Minute,
+	})
+
+	data, err := proto.Marshal(&healthpb.HealthCheckRequest{})
+	require.NoError(err)
+	frame := make([]byte, 5, 5+len(data))
+	binary.BigEndian.PutUint32(frame[1:], uint32(len(data)))
+	frame = append(frame, data...)
+	call := func(contentType, origin string, body []byte) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/grpc.health.v1.Health/Check", bytes.NewReader(body))
+		req.Header.Set("Content-Type", contentType)
+		if origin != "" {
+			req.Header.Set("Origin", origin)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+	// the response is the message frame followed by the trailer frame
+	checkResponse := func(body []byte) {
+		require.True(len(body) > 5)
+		n := binary.BigEndian.Uint32(body[1:5])
+		res := &healthpb.HealthCheckResponse{}
+		require.NoError(proto.Unmarshal(body[5:5+n], res))
+		require.Equal(healthpb.HealthCheckResponse_SERVING, res.Status)
+		trailer := body[5+n:]
+		require.Equal(byte(_grpcWebTrailerFlag), trailer[0])
+		require.Contains(string(trailer[5:]), "grpc-status: 0\r\n")
+	}
+
+	rec := call("application/grpc-web+proto", "https://dapp.io", frame)
+	require.Equal(http.StatusOK, rec.Code)
+	require.Equal("application/grpc-web+proto", rec.Header().Get("Content-Type"))
+	require.Equal("https://dapp.io", rec.Header().Get("Access-Control-Allow-Origin"))
+	require.Empty(rec.Header().Get("Trailer"))
+	checkResponse(rec.Body.Bytes())
+
+	rec = call("application/grpc-web-text", "", []byte(base64.StdEncoding.EncodeToString(frame)))
+	require.Equal(http.StatusOK, rec.Code)
+	body, err := base64.StdEncoding.DecodeString(rec.Body.String())
+	require.NoError(err)
+	checkResponse(body)
+
+	require.Equal(http.StatusForbidden, call("application/grpc-web", "https://evil.io", frame).Code)
+	require.Equal(http.StatusOK, call("application/grpc-web", "https://a.example.com", frame).Code)
+	require.Equal(http.StatusForbidden, call("application/grpc-web", "https://example.com", frame).Code)
+	require.Equal(http.StatusUnsupportedMediaType, call("application/json", "", frame).Code)
+
+	preflight := func(origin, method, headers string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodOptions, "/grpc.health.v1.Health/Check", nil)
+		req.Header.Set("Origin", origin)
+		req.Header.Set("Access-Control-Request-Method", method)
+		req.Header.Set("Access-Control-Request-Headers", headers)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+	rec = preflight("https://dapp.io", http.MethodPost, "content-type, x-grpc-web, authorization")
+	require.Equal(http.StatusNoContent, rec.Code)
+	require.Equal("https://dapp.io", rec.Header().Get("Access-Control-Allow-Origin"))
+	require.Equal("content-type, x-grpc-web, authorization", rec.Header().Get("Access-Control-Allow-Headers"))
+	require.Equal("60", rec.Header().Get("Access-Control-Max-Age"))
+	require.Equal(http.StatusForbidden, preflight("https://evil.io", http.MethodPost, "content-type").Code)
+	require.Equal(http.StatusForbidden, preflight("https://dapp.io", http.MethodPut, "content-type").Code)
+	require.Equal(http.StatusForbidden, preflight("https://dapp.io", http.MethodPost, "x-secret").Code)
+}
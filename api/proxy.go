@@ -0,0 +1,500 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"sync/atomic"
+
+	"github.com/golang/protobuf/proto"
+	grpc_prometheus "github.com/grpc-ecosystem/go-grpc-prometheus"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+
+	"github.com/iotexproject/go-pkgs/cache"
+	"github.com/iotexproject/iotex-proto/golang/iotexapi"
+
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/pkg/log"
+	"github.com/iotexproject/iotex-core/pkg/routine"
+	"github.com/iotexproject/iotex-core/pkg/util/tlsutil"
+)
+
+// ErrNoHealthyUpstream indicates that none of the upstream full nodes is available
+var ErrNoHealthyUpstream = errors.New("no healthy upstream node")
+
+type (
+	// upstream is a full node the proxy server forwards requests to
+	upstream struct {
+		endpoint string
+		conn     *grpc.ClientConn
+		client   ServiceClient
+		healthy  int32
+		height   uint64
+	}
+
+	// ProxyServer is a stateless api server which forwards requests to a set of full nodes
+	ProxyServer struct {
+		cfg         config.APIProxy
+		port        int
+		dialOpt     grpc.DialOption
+		upstreams   []*upstream
+		next        uint64
+		cache       *cache.ThreadSafeLruCache
+		grpcServer  *grpc.Server
+		healthCheck *routine.RecurringTask
+	}
+)
+
+// NewProxyServer creates a new proxy server listening on the port, which serves with TLS if the cert file in tlsCfg
+// is configured
+func NewProxyServer(cfg config.APIProxy, port int, tlsCfg config.TLS) (*ProxyServer, error) {
+	if len(cfg.Endpoints) == 0 {
+		return nil, errors.New("no upstream endpoint is configured")
+	}
+	svr := &ProxyServer{
+		cfg:     cfg,
+		port:    port,
+		dialOpt: grpc.WithInsecure(),
+	}
+	if up := cfg.UpstreamTLS; up.CAFile != "" || up.CertFile != "" {
+		c, err := tlsutil.NewClientConfig(up.CAFile, up.CertFile, up.KeyFile, up.ServerName)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create tls config of upstream connections")
+		}
+		svr.dialOpt = grpc.WithTransportCredentials(credentials.NewTLS(c))
+	}
+	for _, endpoint := range cfg.Endpoints {
+		svr.upstreams = append(svr.upstreams, &upstream{endpoint: endpoint})
+	}
+	if cfg.CacheSize > 0 {
+		svr.cache = cache.NewThreadSafeLruCache(cfg.CacheSize)
+	}
+	svr.healthCheck = routine.NewRecurringTask(svr.checkUpstreams, cfg.HealthCheckInterval)
+	grpcServer, err := newGRPCServer(tlsCfg)
+	if err != nil {
+		return nil, err
+	}
+	svr.grpcServer = grpcServer
+	iotexapi.RegisterAPIServiceServer(svr.grpcServer, svr)
+	grpc_prometheus.Register(svr.grpcServer)
+	reflection.Register(svr.grpcServer)
+	return svr, nil
+}
+
+// Start starts the proxy server
+func (svr *ProxyServer) Start(ctx context.Context) error {
+	for _, u := range svr.upstreams {
+		if u.client != nil {
+			continue
+		}
+		conn, err := grpc.Dial(u.endpoint, svr.dialOpt)
+		if err != nil {
+			return errors.Wrapf(err, "failed to connect to upstream %s", u.endpoint)
+		}
+		u.conn = conn
+		u.client = iotexapi.NewAPIServiceClient(conn)
+	}
+	svr.checkUpstreams()
+	if err := svr.healthCheck.Start(ctx); err != nil {
+		return err
+	}
+	lis, err := net.Listen("tcp", ":"+strconv.Itoa(svr.port))
+	if err != nil {
+		return errors.Wrap(err, "API proxy server failed to listen")
+	}
+	log.L().Info("API proxy server is listening.", zap.String("addr", lis.Addr().String()))
+	go func() {
+		if err := svr.grpcServer.Serve(lis); err != nil {
+			log.L().Fatal("API proxy server failed to serve.", zap.Error(err))
+		}
+	}()
+	return nil
+}
+
+// Stop stops the proxy server
+func (svr *ProxyServer) Stop(ctx context.Context) error {
+	svr.grpcServer.Stop()
+	if err := svr.healthCheck.Stop(ctx); err != nil {
+		return err
+	}
+	for _, u := range svr.upstreams {
+		if u.conn != nil {
+			if err := u.conn.Close(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// checkUpstreams refreshes the health status of upstream nodes. A node is healthy if it responds and its tip height
+// is within MaxHeightLag of the highest one
+func (svr *ProxyServer) checkUpstreams() {
+	var (
+		wg  sync.WaitGroup
+		max uint64
+		mu  sync.Mutex
+	)
+	for _, u := range svr.upstreams {
+		wg.Add(1)
+		go func(u *upstream) {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(context.Background(), svr.cfg.HealthCheckInterval)
+			defer cancel()
+			res, err := u.client.GetChainMeta(ctx, &iotexapi.GetChainMetaRequest{})
+			if err != nil {
+				log.L().Warn("Upstream node is unreachable.", zap.String("endpoint", u.endpoint), zap.Error(err))
+				atomic.StoreUint64(&u.height, 0)
+				return
+			}
+			height := res.GetChainMeta().GetHeight()
+			atomic.StoreUint64(&u.height, height)
+			mu.Lock()
+			if height > max {
+				max = height
+			}
+			mu.Unlock()
+		}(u)
+	}
+	wg.Wait()
+	for _, u := range svr.upstreams {
+		height := atomic.LoadUint64(&u.height)
+		if height > 0 && height+svr.cfg.MaxHeightLag >= max {
+			atomic.StoreInt32(&u.healthy, 1)
+		} else {
+			atomic.StoreInt32(&u.healthy, 0)
+		}
+	}
+}
+
+// healthyUpstreams returns the healthy upstream nodes
+func (svr *ProxyServer) healthyUpstreams() []*upstream {
+	var res []*upstream
+	for _, u := range svr.upstreams {
+		if atomic.LoadInt32(&u.healthy) == 1 {
+			res = append(res, u)
+		}
+	}
+	return res
+}
+
+// pick picks a healthy upstream node in round-robin
+func (svr *ProxyServer) pick() (ServiceClient, error) {
+	healthy := svr.healthyUpstreams()
+	if len(healthy) == 0 {
+		return nil, status.Error(codes.Unavailable, ErrNoHealthyUpstream.Error())
+	}
+	n := atomic.AddUint64(&svr.next, 1)
+	return healthy[n%uint64(len(healthy))].client, nil
+}
+
+// cached returns the response of an immutable query from cache, or calls the upstream and caches the response
+func (svr *ProxyServer) cached(method string, in proto.Message, call func(ServiceClient) (proto.Message, error)) (proto.Message, error) {
+	var key string
+	if svr.cache != nil {
+		b, err := proto.Marshal(in)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		key = method + string(b)
+		if res, ok := svr.cache.Get(key); ok {
+			return res.(proto.Message), nil
+		}
+	}
+	cli, err := svr.pick()
+	if err != nil {
+		return nil, err
+	}
+	res, err := call(cli)
+	if err != nil {
+		return nil, err
+	}
+	if svr.cache != nil {
+		svr.cache.Add(key, res)
+	}
+	return res, nil
+}
+
+// GetAccount returns the metadata of an account
+func (svr *ProxyServer) GetAccount(ctx context.Context, in *iotexapi.GetAccountRequest) (*iotexapi.GetAccountResponse, error) {
+	cli, err := svr.pick()
+	if err != nil {
+		return nil, err
+	}
+	return cli.GetAccount(ctx, in)
+}
+
+// GetActions returns actions
+func (svr *ProxyServer) GetActions(ctx context.Context, in *iotexapi.GetActionsRequest) (*iotexapi.GetActionsResponse, error) {
+	cli, err := svr.pick()
+	if err != nil {
+		return nil, err
+	}
+	return cli.GetActions(ctx, in)
+}
+
+// GetBlockMetas returns block metadata
+func (svr *ProxyServer) GetBlockMetas(ctx context.Context, in *iotexapi.GetBlockMetasRequest) (*iotexapi.GetBlockMetasResponse, error) {
+	if in.GetByHash() == nil {
+		cli, err := svr.pick()
+		if err != nil {
+			return nil, err
+		}
+		return cli.GetBlockMetas(ctx, in)
+	}
+	res, err := svr.cached("GetBlockMetas", in, func(cli ServiceClient) (proto.Message, error) {
+		return cli.GetBlockMetas(ctx, in)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res.(*iotexapi.GetBlockMetasResponse), nil
+}
+
+// GetChainMeta returns blockchain metadata
+func (svr *ProxyServer) GetChainMeta(ctx context.Context, in *iotexapi.GetChainMetaRequest) (*iotexapi.GetChainMetaResponse, error) {
+	cli, err := svr.pick()
+	if err != nil {
+		return nil, err
+	}
+	return cli.GetChainMeta(ctx, in)
+}
+
+// GetServerMeta gets the server metadata
+func (svr *ProxyServer) GetServerMeta(ctx context.Context, in *iotexapi.GetServerMetaRequest) (*iotexapi.GetServerMetaResponse, error) {
+	cli, err := svr.pick()
+	if err != nil {
+		return nil, err
+	}
+	return cli.GetServerMeta(ctx, in)
+}
+
+// SendAction sends the action to all healthy upstream nodes, it succeeds if any of them accepts the action
+func (svr *ProxyServer) SendAction(ctx context.Context, in *iotexapi.SendActionRequest) (*iotexapi.SendActionResponse, error) {
+	healthy := svr.healthyUpstreams()
+	if len(healthy) == 0 {
+		return nil, status.Error(codes.Unavailable, ErrNoHealthyUpstream.Error())
+	}
+	type result struct {
+		res *iotexapi.SendActionResponse
+		err error
+	}
+	results := make(chan result, len(healthy))
+	for _, u := range healthy {
+		go func(u *upstream) {
+			res, err := u.client.SendAction(ctx, in)
+			results <- result{res, err}
+		}(u)
+	}
+	var (
+		res     *iotexapi.SendActionResponse
+		lastErr error
+	)
+	for range healthy {
+		r := <-results
+		if r.err != nil {
+			lastErr = r.err
+			continue
+		}
+		res = r.res
+	}
+	if res != nil {
+		return res, nil
+	}
+	return nil, lastErr
+}
+
+// GetReceiptByAction gets receipt with corresponding action hash
+func (svr *ProxyServer) GetReceiptByAction(ctx context.Context, in *iotexapi.GetReceiptByActionRequest) (*iotexapi.GetReceiptByActionResponse, error) {
+	res, err := svr.cached("GetReceiptByAction", in, func(cli ServiceClient) (proto.Message, error) {
+		return cli.GetReceiptByAction(ctx, in)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res.(*iotexapi.GetReceiptByActionResponse), nil
+}
+
+// ReadContract reads the state in a contract address specified by the slot
+func (svr *ProxyServer) ReadContract(ctx context.Context, in *iotexapi.ReadContractRequest) (*iotexapi.ReadContractResponse, error) {
+	cli, err := svr.pick()
+	if err != nil {
+		return nil, err
+	}
+	return cli.ReadContract(ctx, in)
+}
+
+// SuggestGasPrice suggests gas price
+func (svr *ProxyServer) SuggestGasPrice(ctx context.Context, in *iotexapi.SuggestGasPriceRequest) (*iotexapi.SuggestGasPriceResponse, error) {
+	cli, err := svr.pick()
+	if err != nil {
+		return nil, err
+	}
+	return cli.SuggestGasPrice(ctx, in)
+}
+
+// EstimateGasForAction estimates gas for action
+func (svr *ProxyServer) EstimateGasForAction(ctx context.Context, in *iotexapi.EstimateGasForActionRequest) (*iotexapi.EstimateGasForActionResponse, error) {
+	cli, err := svr.pick()
+	if err != nil {
+		return nil, err
+	}
+	return cli.EstimateGasForAction(ctx, in)
+}
+
+// EstimateActionGasConsumption estimate gas consume for action without signature
+func (svr *ProxyServer) EstimateActionGasConsumption(ctx context.Context, in *iotexapi.EstimateActionGasConsumptionRequest) (*iotexapi.EstimateActionGasConsumptionResponse, error) {
+	cli, err := svr.pick()
+	if err != nil {
+		return nil, err
+	}
+	return cli.EstimateActionGasConsumption(ctx, in)
+}
+
+// ReadState reads state on blockchain
+func (svr *ProxyServer) ReadState(ctx context.Context, in *iotexapi.ReadStateRequest) (*iotexapi.ReadStateResponse, error) {
+	cli, err := svr.pick()
+	if err != nil {
+		return nil, err
+	}
+	return cli.ReadState(ctx, in)
+}
+
+// GetEpochMeta gets epoch metadata
+func (svr *ProxyServer) GetEpochMeta(ctx context.Context, in *iotexapi.GetEpochMetaRequest) (*iotexapi.GetEpochMetaResponse, error) {
+	cli, err := svr.pick()
+	if err != nil {
+		return nil, err
+	}
+	return cli.GetEpochMeta(ctx, in)
+}
+
+// GetRawBlocks gets raw block data
+func (svr *ProxyServer) GetRawBlocks(ctx context.Context, in *iotexapi.GetRawBlocksRequest) (*iotexapi.GetRawBlocksResponse, error) {
+	cli, err := svr.pick()
+	if err != nil {
+		return nil, err
+	}
+	return cli.GetRawBlocks(ctx, in)
+}
+
+// GetLogs get logs filtered by contract address and topics
+func (svr *ProxyServer) GetLogs(ctx context.Context, in *iotexapi.GetLogsRequest) (*iotexapi.GetLogsResponse, error) {
+	cli, err := svr.pick()
+	if err != nil {
+		return nil, err
+	}
+	return cli.GetLogs(ctx, in)
+}
+
+// GetEvmTransfersByActionHash returns evm transfers by action hash
+func (svr *ProxyServer) GetEvmTransfersByActionHash(ctx context.Context, in *iotexapi.GetEvmTransfersByActionHashRequest) (*iotexapi.GetEvmTransfersByActionHashResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "evm transfer index is deprecated, call GetSystemLogByActionHash instead")
+}
+
+// GetEvmTransfersByBlockHeight returns evm transfers by block height
+func (svr *ProxyServer) GetEvmTransfersByBlockHeight(ctx context.Context, in *iotexapi.GetEvmTransfersByBlockHeightRequest) (*iotexapi.GetEvmTransfersByBlockHeightResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "evm transfer index is deprecated, call GetSystemLogByBlockHeight instead")
+}
+
+// GetTransactionLogByActionHash returns transaction log by action hash
+func (svr *ProxyServer) GetTransactionLogByActionHash(ctx context.Context, in *iotexapi.GetTransactionLogByActionHashRequest) (*iotexapi.GetTransactionLogByActionHashResponse, error) {
+	res, err := svr.cached("GetTransactionLogByActionHash", in, func(cli ServiceClient) (proto.Message, error) {
+		return cli.GetTransactionLogByActionHash(ctx, in)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res.(*iotexapi.GetTransactionLogByActionHashResponse), nil
+}
+
+// GetTransactionLogByBlockHeight returns transaction log by block height
+func (svr *ProxyServer) GetTransactionLogByBlockHeight(ctx context.Context, in *iotexapi.GetTransactionLogByBlockHeightRequest) (*iotexapi.GetTransactionLogByBlockHeightResponse, error) {
+	res, err := svr.cached("GetTransactionLogByBlockHeight", in, func(cli ServiceClient) (proto.Message, error) {
+		return cli.GetTransactionLogByBlockHeight(ctx, in)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res.(*iotexapi.GetTransactionLogByBlockHeightResponse), nil
+}
+
+// StreamBlocks relays the block stream of an upstream node
+func (svr *ProxyServer) StreamBlocks(in *iotexapi.StreamBlocksRequest, stream iotexapi.APIService_StreamBlocksServer) error {
+	cli, err := svr.pick()
+	if err != nil {
+		return err
+	}
+	us, err := cli.StreamBlocks(stream.Context(), in)
+	if err != nil {
+		return err
+	}
+	for {
+		res, err := us.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return status.Error(codes.Aborted, err.Error())
+		}
+		if err := stream.Send(res); err != nil {
+			return err
+		}
+	}
+}
+
+// StreamLogs relays the log stream of an upstream node
+func (svr *ProxyServer) StreamLogs(in *iotexapi.StreamLogsRequest, stream iotexapi.APIService_StreamLogsServer) error {
+	cli, err := svr.pick()
+	if err != nil {
+		return err
+	}
+	us, err := cli.StreamLogs(stream.Context(), in)
+	if err != nil {
+		return err
+	}
+	for {
+		res, err := us.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return status.Error(codes.Aborted, err.Error())
+		}
+		if err := stream.Send(res); err != nil {
+			return err
+		}
+	}
+}
+
+// GetElectionBuckets returns the native election buckets
+func (svr *ProxyServer) GetElectionBuckets(ctx context.Context, in *iotexapi.GetElectionBucketsRequest) (*iotexapi.GetElectionBucketsResponse, error) {
+	cli, err := svr.pick()
+	if err != nil {
+		return nil, err
+	}
+	return cli.GetElectionBuckets(ctx, in)
+}
+
+// GetActPoolActions returns the all Transaction Identifiers in the mempool
+func (svr *ProxyServer) GetActPoolActions(ctx context.Context, in *iotexapi.GetActPoolActionsRequest) (*iotexapi.GetActPoolActionsResponse, error) {
+	cli, err := svr.pick()
+	if err != nil {
+		return nil, err
+	}
+	return cli.GetActPoolActions(ctx, in)
+}
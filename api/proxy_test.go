@@ -0,0 +1,85 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-proto/golang/iotexapi"
+	"github.com/iotexproject/iotex-proto/golang/iotextypes"
+
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/test/mock/mock_apiserviceclient"
+)
+
+func TestProxyServer(t *testing.T) {
+	require := require.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	_, err := NewProxyServer(config.APIProxy{}, 0, config.TLS{})
+	require.Error(err)
+
+	cfg := config.Default.APIProxy
+	cfg.Endpoints = []string{"node1:14014", "node2:14014", "node3:14014"}
+	cfg.HealthCheckInterval = time.Second
+	svr, err := NewProxyServer(cfg, 0, config.TLS{})
+	require.NoError(err)
+
+	chainMeta := func(height uint64) *iotexapi.GetChainMetaResponse {
+		return &iotexapi.GetChainMetaResponse{ChainMeta: &iotextypes.ChainMeta{Height: height}}
+	}
+	clients := make([]*mock_apiserviceclient.MockServiceClient, 3)
+	for i := range clients {
+		clients[i] = mock_apiserviceclient.NewMockServiceClient(ctrl)
+		svr.upstreams[i].client = clients[i]
+	}
+	// node2 falls behind and node3 is unreachable
+	clients[0].EXPECT().GetChainMeta(gomock.Any(), gomock.Any()).Return(chainMeta(100), nil).Times(1)
+	clients[1].EXPECT().GetChainMeta(gomock.Any(), gomock.Any()).Return(chainMeta(90), nil).Times(1)
+	clients[2].EXPECT().GetChainMeta(gomock.Any(), gomock.Any()).Return(nil, errors.New("unreachable")).Times(1)
+	svr.checkUpstreams()
+	healthy := svr.healthyUpstreams()
+	require.Equal(1, len(healthy))
+	require.Equal("node1:14014", healthy[0].endpoint)
+
+	// immutable query is served from cache
+	receipt := &iotexapi.GetReceiptByActionResponse{ReceiptInfo: &iotexapi.ReceiptInfo{BlkHash: "abcd"}}
+	clients[0].EXPECT().GetReceiptByAction(gomock.Any(), gomock.Any()).Return(receipt, nil).Times(1)
+	for i := 0; i < 2; i++ {
+		res, err := svr.GetReceiptByAction(context.Background(), &iotexapi.GetReceiptByActionRequest{ActionHash: "1234"})
+		require.NoError(err)
+		require.Equal("abcd", res.ReceiptInfo.BlkHash)
+	}
+
+	// action is broadcast to all healthy nodes
+	clients[0].EXPECT().GetChainMeta(gomock.Any(), gomock.Any()).Return(chainMeta(100), nil).Times(1)
+	clients[1].EXPECT().GetChainMeta(gomock.Any(), gomock.Any()).Return(chainMeta(99), nil).Times(1)
+	clients[2].EXPECT().GetChainMeta(gomock.Any(), gomock.Any()).Return(chainMeta(101), nil).Times(1)
+	svr.checkUpstreams()
+	require.Equal(3, len(svr.healthyUpstreams()))
+	clients[0].EXPECT().SendAction(gomock.Any(), gomock.Any()).Return(nil, errors.New("rejected")).Times(1)
+	clients[1].EXPECT().SendAction(gomock.Any(), gomock.Any()).Return(&iotexapi.SendActionResponse{ActionHash: "1234"}, nil).Times(1)
+	clients[2].EXPECT().SendAction(gomock.Any(), gomock.Any()).Return(&iotexapi.SendActionResponse{ActionHash: "1234"}, nil).Times(1)
+	res, err := svr.SendAction(context.Background(), &iotexapi.SendActionRequest{})
+	require.NoError(err)
+	require.Equal("1234", res.ActionHash)
+
+	// no healthy node
+	for _, c := range clients {
+		c.EXPECT().GetChainMeta(gomock.Any(), gomock.Any()).Return(nil, errors.New("unreachable")).Times(1)
+	}
+	svr.checkUpstreams()
+	_, err = svr.GetChainMeta(context.Background(), &iotexapi.GetChainMetaRequest{})
+	require.Error(err)
+}
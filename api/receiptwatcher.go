@@ -0,0 +1,236 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package api
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/iotexproject/go-pkgs/hash"
+	"github.com/iotexproject/iotex-proto/golang/iotextypes"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/iotexproject/iotex-core/action"
+	"github.com/iotexproject/iotex-core/blockchain/block"
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/pkg/log"
+)
+
+// Statuses of the receipt notifications
+const (
+	ReceiptStatusSuccess = "success"
+	ReceiptStatusFailed  = "failed"
+	ReceiptStatusTimeout = "timeout"
+)
+
+var (
+	errTooManyPendingReceipts = errors.New("too many actions waiting for receipts")
+	errInvalidWebhook         = errors.New("webhook should be an http or https url")
+)
+
+type (
+	// ReceiptNotification is posted to the webhook when the action is included, or not included within the ttl
+	ReceiptNotification struct {
+		ActionHash      string `json:"actionHash"`
+		Status          string `json:"status"`
+		BlockHeight     uint64 `json:"blockHeight,omitempty"`
+		ReceiptStatus   uint64 `json:"receiptStatus,omitempty"`
+		GasConsumed     uint64 `json:"gasConsumed,omitempty"`
+		ContractAddress string `json:"contractAddress,omitempty"`
+	}
+
+	receiptHook struct {
+		url      string
+		deadline time.Time
+	}
+
+	// receiptWatcher notifies the receipts of the watched actions in the new blocks to the webhooks and the waiters
+	receiptWatcher struct {
+		cfg     config.ReceiptWebhook
+		post    func(webhook string, body []byte) error
+		mu      sync.Mutex
+		pending int
+		hooks   map[hash.Hash256][]receiptHook
+		waiters map[hash.Hash256][]chan *action.Receipt
+	}
+)
+
+func newReceiptWatcher(cfg config.ReceiptWebhook) *receiptWatcher {
+	client := &http.Client{Timeout: cfg.Timeout}
+	return &receiptWatcher{
+		cfg: cfg,
+		post: func(webhook string, body []byte) error {
+			resp, err := client.Post(webhook, "application/json", bytes.NewReader(body))
+			if err != nil {
+				return err
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+				return errors.Errorf("webhook responded %s", resp.Status)
+			}
+			return nil
+		},
+		hooks:   make(map[hash.Hash256][]receiptHook),
+		waiters: make(map[hash.Hash256][]chan *action.Receipt),
+	}
+}
+
+// AddWebhook registers the webhook to be notified of the receipt of the action
+func (rw *receiptWatcher) AddWebhook(h hash.Hash256, webhook string, now time.Time) error {
+	u, err := url.Parse(webhook)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errInvalidWebhook
+	}
+	rw.mu.Lock()
+	defer rw.mu.Unlock()
+	if rw.pending >= rw.cfg.MaxPending {
+		return errTooManyPendingReceipts
+	}
+	rw.pending++
+	rw.hooks[h] = append(rw.hooks[h], receiptHook{url: webhook, deadline: now.Add(rw.cfg.TTL)})
+	return nil
+}
+
+// RemoveWebhooks removes the webhooks of the action
+func (rw *receiptWatcher) RemoveWebhooks(h hash.Hash256) {
+	rw.mu.Lock()
+	defer rw.mu.Unlock()
+	rw.pending -= len(rw.hooks[h])
+	delete(rw.hooks, h)
+}
+
+// Watch returns a channel receiving the receipt of the action, and the function to stop watching
+func (rw *receiptWatcher) Watch(h hash.Hash256) (<-chan *action.Receipt, func(), error) {
+	rw.mu.Lock()
+	defer rw.mu.Unlock()
+	if rw.pending >= rw.cfg.MaxPending {
+		return nil, nil, errTooManyPendingReceipts
+	}
+	rw.pending++
+	ch := make(chan *action.Receipt, 1)
+	rw.waiters[h] = append(rw.waiters[h], ch)
+	return ch, func() {
+		rw.mu.Lock()
+		defer rw.mu.Unlock()
+		waiters := rw.waiters[h]
+		for i, w := range waiters {
+			if w == ch {
+				rw.pending--
+				waiters = append(waiters[:i], waiters[i+1:]...)
+				break
+			}
+		}
+		if len(waiters) == 0 {
+			delete(rw.waiters, h)
+		} else {
+			rw.waiters[h] = waiters
+		}
+	}, nil
+}
+
+// Respond notifies the receipts in the block, and the timeout of the expired webhooks
+func (rw *receiptWatcher) Respond(blk *block.Block) error {
+	rw.respond(blk, time.Now())
+	return nil
+}
+
+// Exit is called when the chain listener stops
+func (rw *receiptWatcher) Exit() {}
+
+func (rw *receiptWatcher) respond(blk *block.Block, now time.Time) {
+	type delivery struct {
+		webhook string
+		n       *ReceiptNotification
+	}
+	var deliveries []delivery
+	rw.mu.Lock()
+	for _, r := range blk.Receipts {
+		for _, ch := range rw.waiters[r.ActionHash] {
+			ch <- r
+			rw.pending--
+		}
+		delete(rw.waiters, r.ActionHash)
+		hooks, ok := rw.hooks[r.ActionHash]
+		if !ok {
+			continue
+		}
+		n := &ReceiptNotification{
+			ActionHash:      hex.EncodeToString(r.ActionHash[:]),
+			Status:          ReceiptStatusSuccess,
+			BlockHeight:     r.BlockHeight,
+			ReceiptStatus:   r.Status,
+			GasConsumed:     r.GasConsumed,
+			ContractAddress: r.ContractAddress,
+		}
+		if r.Status != uint64(iotextypes.ReceiptStatus_Success) {
+			n.Status = ReceiptStatusFailed
+		}
+		for _, hook := range hooks {
+			deliveries = append(deliveries, delivery{hook.url, n})
+		}
+		rw.pending -= len(hooks)
+		delete(rw.hooks, r.ActionHash)
+	}
+	for h, hooks := range rw.hooks {
+		var alive []receiptHook
+		for _, hook := range hooks {
+			if now.Before(hook.deadline) {
+				alive = append(alive, hook)
+				continue
+			}
+			deliveries = append(deliveries, delivery{hook.url, &ReceiptNotification{
+				ActionHash: hex.EncodeToString(h[:]),
+				Status:     ReceiptStatusTimeout,
+			}})
+			rw.pending--
+		}
+		if len(alive) == 0 {
+			delete(rw.hooks, h)
+		} else {
+			rw.hooks[h] = alive
+		}
+	}
+	rw.mu.Unlock()
+
+	for _, d := range deliveries {
+		go rw.notify(d.webhook, d.n)
+	}
+}
+
+func (rw *receiptWatcher) notify(webhook string, n *ReceiptNotification) {
+	body, err := json.Marshal(n)
+	if err != nil {
+		log.L().Error("Failed to marshal receipt notification.", zap.Error(err))
+		return
+	}
+	backoff := time.Second
+	for i := 0; ; i++ {
+		if err = rw.post(webhook, body); err == nil {
+			return
+		}
+		if i >= rw.cfg.MaxRetries {
+			break
+		}
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+	// the url is not logged as a whole in case it carries credentials
+	host := ""
+	if u, e := url.Parse(webhook); e == nil {
+		host = u.Host
+	}
+	log.L().Warn("Failed to notify the receipt.",
+		zap.String("webhookHost", host),
+		zap.String("actionHash", n.ActionHash),
+		zap.Error(err))
+}
@@ -0,0 +1,83 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package api
+
+import (
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/iotexproject/go-pkgs/hash"
+	"github.com/iotexproject/iotex-proto/golang/iotextypes"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/action"
+	"github.com/iotexproject/iotex-core/blockchain/block"
+	"github.com/iotexproject/iotex-core/config"
+)
+
+func TestReceiptWatcher(t *testing.T) {
+	require := require.New(t)
+
+	rw := newReceiptWatcher(config.ReceiptWebhook{MaxPending: 3, TTL: time.Minute})
+	var (
+		mu       sync.Mutex
+		notified = make(map[string]*ReceiptNotification)
+	)
+	rw.post = func(webhook string, body []byte) error {
+		n := &ReceiptNotification{}
+		if err := json.Unmarshal(body, n); err != nil {
+			return err
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		notified[webhook] = n
+		return nil
+	}
+	h1, h2, h3 := hash.Hash256b([]byte("1")), hash.Hash256b([]byte("2")), hash.Hash256b([]byte("3"))
+	now := time.Now()
+
+	require.Equal(errInvalidWebhook, rw.AddWebhook(h1, "ftp://example.com", now))
+	require.Equal(errInvalidWebhook, rw.AddWebhook(h1, "/receipts", now))
+	require.NoError(rw.AddWebhook(h1, "http://example.com/1", now))
+	require.NoError(rw.AddWebhook(h2, "http://example.com/2", now))
+	ch, cancel, err := rw.Watch(h3)
+	require.NoError(err)
+	defer cancel()
+	_, _, err = rw.Watch(h1)
+	require.Equal(errTooManyPendingReceipts, err)
+
+	rw.respond(&block.Block{Receipts: []*action.Receipt{
+		{Status: uint64(iotextypes.ReceiptStatus_Success), BlockHeight: 10, ActionHash: h3},
+		{Status: uint64(iotextypes.ReceiptStatus_Failure), BlockHeight: 10, ActionHash: h1},
+	}}, now)
+	receipt := <-ch
+	require.Equal(h3, receipt.ActionHash)
+	require.Eventually(func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return notified["http://example.com/1"] != nil
+	}, time.Second, 10*time.Millisecond)
+	mu.Lock()
+	require.Equal(ReceiptStatusFailed, notified["http://example.com/1"].Status)
+	require.Equal(uint64(10), notified["http://example.com/1"].BlockHeight)
+	mu.Unlock()
+	require.Equal(1, rw.pending)
+
+	// the webhook not notified within the ttl is notified of the timeout
+	rw.respond(&block.Block{}, now.Add(time.Minute))
+	require.Eventually(func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		n := notified["http://example.com/2"]
+		return n != nil && n.Status == ReceiptStatusTimeout
+	}, time.Second, 10*time.Millisecond)
+	require.Zero(rw.pending)
+	require.Empty(rw.hooks)
+	require.Empty(rw.waiters)
+}
@@ -0,0 +1,174 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"math/big"
+	"sort"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/iotexproject/iotex-address/address"
+	"github.com/iotexproject/iotex-proto/golang/iotextypes"
+
+	accountutil "github.com/iotexproject/iotex-core/action/protocol/account/util"
+	"github.com/iotexproject/iotex-core/action/protocol/rewarding"
+	"github.com/iotexproject/iotex-core/blockchain/filedao"
+	"github.com/iotexproject/iotex-core/state/factory"
+)
+
+// _reconcileMaxRange is the max number of blocks reconciled in one call
+const _reconcileMaxRange = 10000
+
+type (
+	// BalanceDiscrepancy is an account whose balance change differs from the net amount of its transaction logs
+	BalanceDiscrepancy struct {
+		Address string
+		// Expected is the net amount of the transaction logs
+		Expected *big.Int
+		// Actual is the balance change read from the state
+		Actual *big.Int
+	}
+
+	// BalanceReconciliation is the result of reconciling the balances in a height range
+	BalanceReconciliation struct {
+		StartHeight   uint64
+		EndHeight     uint64
+		NumLogs       int
+		NumAccounts   int
+		Discrepancies []*BalanceDiscrepancy
+	}
+)
+
+// ReconcileBalances replays the transaction logs of the blocks in [start, end] into the balance change of each
+// account, and compares it with the balances read from the state at start-1 and end. The rewarding pool is compared
+// with the balance of the rewarding fund, while the staking bucket pool and the burned amount are not compared. It
+// requires the archive mode to read the balances at the past heights
+func (api *Server) ReconcileBalances(ctx context.Context, start, end uint64) (*BalanceReconciliation, error) {
+	if !api.cfg.Chain.EnableArchiveMode {
+		return nil, status.Error(codes.Unavailable, "balance reconciliation requires the archive mode")
+	}
+	if !api.dao.ContainsTransactionLog() {
+		return nil, status.Error(codes.Unimplemented, filedao.ErrNotSupported.Error())
+	}
+	tipHeight := api.bc.TipHeight()
+	if start == 0 || start > end || end > tipHeight {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid height range [%d, %d] with tip height %d", start, end, tipHeight)
+	}
+	if end-start >= _reconcileMaxRange {
+		return nil, status.Errorf(codes.InvalidArgument, "range exceeds the limit %d", _reconcileMaxRange)
+	}
+
+	deltas := make(map[string]*big.Int)
+	res := &BalanceReconciliation{StartHeight: start, EndHeight: end}
+	for height := start; height <= end; height++ {
+		if err := ctx.Err(); err != nil {
+			return nil, status.Error(codes.Canceled, err.Error())
+		}
+		logs, err := api.dao.TransactionLogs(height)
+		if err != nil {
+			if errors.Cause(err) == filedao.ErrNotSupported {
+				return nil, status.Errorf(codes.FailedPrecondition, "no transaction log at height %d", height)
+			}
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		n, err := replayTransactionLogs(logs, deltas)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "invalid transaction log at height %d: %v", height, err)
+		}
+		res.NumLogs += n
+	}
+	res.NumAccounts = len(deltas)
+
+	addrs := make([]string, 0, len(deltas))
+	for addr := range deltas {
+		addrs = append(addrs, addr)
+	}
+	sort.Strings(addrs)
+	for _, addr := range addrs {
+		actual, err := api.balanceChange(ctx, addr, start-1, end)
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		if actual == nil || actual.Cmp(deltas[addr]) == 0 {
+			continue
+		}
+		res.Discrepancies = append(res.Discrepancies, &BalanceDiscrepancy{
+			Address:  addr,
+			Expected: deltas[addr],
+			Actual:   actual,
+		})
+	}
+	return res, nil
+}
+
+// balanceChange returns the change of the balance from height from to height to, or nil if the balance of the address
+// is not compared
+func (api *Server) balanceChange(ctx context.Context, addr string, from, to uint64) (*big.Int, error) {
+	switch addr {
+	case "", address.StakingBucketPoolAddr:
+		return nil, nil
+	case address.RewardingPoolAddr:
+		rp := rewarding.FindProtocol(api.registry)
+		if rp == nil {
+			return nil, nil
+		}
+		fromCtx, err := api.readStateContext(ctx, from)
+		if err != nil {
+			return nil, err
+		}
+		fromBalance, _, err := rp.TotalBalance(fromCtx, factory.NewHistoryStateReader(api.sf, from))
+		if err != nil {
+			return nil, err
+		}
+		toCtx, err := api.readStateContext(ctx, to)
+		if err != nil {
+			return nil, err
+		}
+		toBalance, _, err := rp.TotalBalance(toCtx, factory.NewHistoryStateReader(api.sf, to))
+		if err != nil {
+			return nil, err
+		}
+		return new(big.Int).Sub(toBalance, fromBalance), nil
+	}
+	fromAccount, err := accountutil.AccountState(factory.NewHistoryStateReader(api.sf, from), addr)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read %s at height %d", addr, from)
+	}
+	toAccount, err := accountutil.AccountState(factory.NewHistoryStateReader(api.sf, to), addr)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read %s at height %d", addr, to)
+	}
+	return new(big.Int).Sub(toAccount.Balance, fromAccount.Balance), nil
+}
+
+// replayTransactionLogs adds the amounts of the transaction logs into the balance changes of the recipients and
+// subtracts them from the senders, and returns the number of the logs
+func replayTransactionLogs(logs *iotextypes.TransactionLogs, deltas map[string]*big.Int) (int, error) {
+	add := func(addr string, amount *big.Int) {
+		if _, ok := deltas[addr]; !ok {
+			deltas[addr] = big.NewInt(0)
+		}
+		deltas[addr].Add(deltas[addr], amount)
+	}
+	var n int
+	for _, actLog := range logs.GetLogs() {
+		for _, tx := range actLog.GetTransactions() {
+			amount, ok := new(big.Int).SetString(tx.GetAmount(), 10)
+			if !ok || amount.Sign() < 0 {
+				return n, errors.Errorf("invalid amount %s", tx.GetAmount())
+			}
+			add(tx.GetSender(), new(big.Int).Neg(amount))
+			add(tx.GetRecipient(), amount)
+			n++
+		}
+	}
+	return n, nil
+}
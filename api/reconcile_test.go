@@ -0,0 +1,59 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package api
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-address/address"
+	"github.com/iotexproject/iotex-proto/golang/iotextypes"
+
+	"github.com/iotexproject/iotex-core/test/identityset"
+)
+
+func TestReplayTransactionLogs(t *testing.T) {
+	require := require.New(t)
+
+	alice, bob := identityset.Address(1).String(), identityset.Address(2).String()
+	tx := func(typ iotextypes.TransactionLogType, amount, sender, recipient string) *iotextypes.TransactionLog_Transaction {
+		return &iotextypes.TransactionLog_Transaction{Type: typ, Amount: amount, Sender: sender, Recipient: recipient}
+	}
+	logs := &iotextypes.TransactionLogs{Logs: []*iotextypes.TransactionLog{
+		{Transactions: []*iotextypes.TransactionLog_Transaction{
+			tx(iotextypes.TransactionLogType_GAS_FEE, "10", alice, address.RewardingPoolAddr),
+			tx(iotextypes.TransactionLogType_NATIVE_TRANSFER, "100", alice, bob),
+		}},
+		{Transactions: []*iotextypes.TransactionLog_Transaction{
+			tx(iotextypes.TransactionLogType_CREATE_BUCKET, "30", bob, address.StakingBucketPoolAddr),
+			tx(iotextypes.TransactionLogType_CLAIM_FROM_REWARDING_FUND, "5", address.RewardingPoolAddr, bob),
+		}},
+	}}
+	deltas := make(map[string]*big.Int)
+	n, err := replayTransactionLogs(logs, deltas)
+	require.NoError(err)
+	require.Equal(4, n)
+	require.Equal(big.NewInt(-110), deltas[alice])
+	require.Equal(big.NewInt(75), deltas[bob])
+	require.Equal(big.NewInt(5), deltas[address.RewardingPoolAddr])
+	require.Equal(big.NewInt(30), deltas[address.StakingBucketPoolAddr])
+
+	// replaying more logs accumulates into the changes
+	n, err = replayTransactionLogs(logs, deltas)
+	require.NoError(err)
+	require.Equal(4, n)
+	require.Equal(big.NewInt(-220), deltas[alice])
+
+	_, err = replayTransactionLogs(&iotextypes.TransactionLogs{Logs: []*iotextypes.TransactionLog{
+		{Transactions: []*iotextypes.TransactionLog_Transaction{
+			tx(iotextypes.TransactionLogType_NATIVE_TRANSFER, "-1", alice, bob),
+		}},
+	}}, deltas)
+	require.Error(err)
+}
@@ -0,0 +1,124 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"encoding/hex"
+	"time"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/iotexproject/iotex-proto/golang/iotexapi"
+	"github.com/iotexproject/iotex-proto/golang/iotextypes"
+
+	logfilter "github.com/iotexproject/iotex-core/api/logfilter"
+	"github.com/iotexproject/iotex-core/pkg/util/byteutil"
+)
+
+// _replayLogsBatchSize is the number of blocks scanned by the bloom filter index at a time
+const _replayLogsBatchSize = 1000
+
+// LogSink receives the logs replayed from history. The resume token marks the position right after the logs, a
+// replay restarted from the token continues with the next block
+type LogSink func(logs []*iotextypes.Log, resumeToken string) error
+
+// NewStreamLogSink returns a log sink writing into a log stream
+func NewStreamLogSink(stream iotexapi.APIService_StreamLogsServer) LogSink {
+	return func(logs []*iotextypes.Log, _ string) error {
+		for _, l := range logs {
+			if err := stream.Send(&iotexapi.StreamLogsResponse{Log: l}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// EncodeReplayResumeToken encodes the height to resume a log replay from
+func EncodeReplayResumeToken(height uint64) string {
+	return hex.EncodeToString(byteutil.Uint64ToBytesBigEndian(height))
+}
+
+// DecodeReplayResumeToken decodes the height to resume a log replay from
+func DecodeReplayResumeToken(token string) (uint64, error) {
+	b, err := hex.DecodeString(token)
+	if err != nil || len(b) != 8 {
+		return 0, errors.Errorf("invalid resume token %s", token)
+	}
+	return byteutil.BytesToUint64BigEndian(b), nil
+}
+
+// ReplayLogs streams the historical logs matching the filter in height range [fromHeight, toHeight] into the sink,
+// block by block, at the rate of API.LogReplayRate blocks per second. It returns the resume token of the next block
+// to replay when the context is cancelled or the sink fails, and an empty token once the whole range is replayed
+func (api *Server) ReplayLogs(
+	ctx context.Context,
+	fromHeight, toHeight uint64,
+	filter *iotexapi.LogsFilter,
+	sink LogSink,
+) (string, error) {
+	if filter == nil {
+		return "", status.Error(codes.InvalidArgument, "empty filter")
+	}
+	if api.bfIndexer == nil {
+		return "", status.Error(codes.Unavailable, "bloom filter index is not available")
+	}
+	tipHeight := api.bc.TipHeight()
+	if fromHeight == 0 {
+		fromHeight = 1
+	}
+	if toHeight == 0 || toHeight > tipHeight {
+		toHeight = tipHeight
+	}
+	if fromHeight > toHeight {
+		return "", status.Error(codes.InvalidArgument, "invalid start and end height")
+	}
+
+	var throttle <-chan time.Time
+	if api.cfg.API.LogReplayRate > 0 {
+		ticker := time.NewTicker(time.Second / time.Duration(api.cfg.API.LogReplayRate))
+		defer ticker.Stop()
+		throttle = ticker.C
+	}
+	lf := logfilter.NewLogFilter(filter, nil, nil)
+	for start := fromHeight; start <= toHeight; start += _replayLogsBatchSize {
+		end := start + _replayLogsBatchSize - 1
+		if end > toHeight {
+			end = toHeight
+		}
+		heights, err := api.bfIndexer.FilterBlocksInRange(lf, start, end)
+		if err != nil {
+			return EncodeReplayResumeToken(start), status.Error(codes.Internal, err.Error())
+		}
+		for _, height := range heights {
+			if throttle != nil {
+				select {
+				case <-ctx.Done():
+					return EncodeReplayResumeToken(height), ctx.Err()
+				case <-throttle:
+				}
+			}
+			if err := ctx.Err(); err != nil {
+				return EncodeReplayResumeToken(height), err
+			}
+			logs, err := api.getLogsInBlock(lf, height)
+			if err != nil {
+				return EncodeReplayResumeToken(height), err
+			}
+			if len(logs) == 0 {
+				continue
+			}
+			if err := sink(logs, EncodeReplayResumeToken(height+1)); err != nil {
+				return EncodeReplayResumeToken(height), status.Error(codes.Aborted, err.Error())
+			}
+		}
+	}
+	return "", nil
+}
@@ -0,0 +1,132 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package api
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/iotexproject/iotex-proto/golang/iotexapi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/iotexproject/iotex-core/action"
+)
+
+type (
+	// TxPoolStatus is the number of the pending and queued actions in the actpool, as txpool_status does
+	TxPoolStatus struct {
+		Pending uint64
+		Queued  uint64
+	}
+
+	// TxPoolContent is the pending and queued actions in the actpool by sender and nonce, as txpool_content does.
+	// Pending actions are executable in the next blocks, while the queued ones wait for the nonce gaps to be filled
+	TxPoolContent struct {
+		Pending map[string]map[uint64]*iotexapi.ActionInfo
+		Queued  map[string]map[uint64]*iotexapi.ActionInfo
+	}
+
+	// TxPoolInspect is the summaries of the actions in TxPoolContent, as txpool_inspect does
+	TxPoolInspect struct {
+		Pending map[string]map[uint64]string
+		Queued  map[string]map[uint64]string
+	}
+
+	hasAmount interface {
+		Amount() *big.Int
+	}
+)
+
+// TxPoolStatus returns the number of the pending and queued actions in the actpool
+func (api *Server) TxPoolStatus() *TxPoolStatus {
+	ret := &TxPoolStatus{}
+	for _, acts := range api.ap.PendingActionMap() {
+		ret.Pending += uint64(len(acts))
+	}
+	for _, acts := range api.ap.QueuedActionMap() {
+		ret.Queued += uint64(len(acts))
+	}
+	return ret
+}
+
+// TxPoolContent returns the pending and queued actions in the actpool
+func (api *Server) TxPoolContent() (*TxPoolContent, error) {
+	pending, err := api.actionInfoMap(api.ap.PendingActionMap())
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	queued, err := api.actionInfoMap(api.ap.QueuedActionMap())
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &TxPoolContent{
+		Pending: pending,
+		Queued:  queued,
+	}, nil
+}
+
+// TxPoolInspect returns the summaries of the pending and queued actions in the actpool
+func (api *Server) TxPoolInspect() *TxPoolInspect {
+	return &TxPoolInspect{
+		Pending: actionSummaryMap(api.ap.PendingActionMap()),
+		Queued:  actionSummaryMap(api.ap.QueuedActionMap()),
+	}
+}
+
+func (api *Server) actionInfoMap(actMap map[string][]action.SealedEnvelope) (map[string]map[uint64]*iotexapi.ActionInfo, error) {
+	ret := make(map[string]map[uint64]*iotexapi.ActionInfo, len(actMap))
+	for sender, acts := range actMap {
+		if len(acts) == 0 {
+			continue
+		}
+		infos := make(map[uint64]*iotexapi.ActionInfo, len(acts))
+		for _, selp := range acts {
+			info, err := api.pendingAction(selp)
+			if err != nil {
+				return nil, err
+			}
+			infos[selp.Nonce()] = info
+		}
+		ret[sender] = infos
+	}
+	return ret, nil
+}
+
+func actionSummaryMap(actMap map[string][]action.SealedEnvelope) map[string]map[uint64]string {
+	ret := make(map[string]map[uint64]string, len(actMap))
+	for sender, acts := range actMap {
+		if len(acts) == 0 {
+			continue
+		}
+		summaries := make(map[uint64]string, len(acts))
+		for _, selp := range acts {
+			summaries[selp.Nonce()] = actionSummary(selp)
+		}
+		ret[sender] = summaries
+	}
+	return ret
+}
+
+// actionSummary summarizes the action as "<destination>: <amount> Rau + <gas limit> gas × <gas price> Rau", where the
+// destination is "contract creation" for deploying a contract, or the action type if the action has no destination
+func actionSummary(selp action.SealedEnvelope) string {
+	dst, ok := selp.Destination()
+	switch {
+	case ok && dst != "":
+	case ok:
+		dst = "contract creation"
+	default:
+		dst = strings.TrimPrefix(fmt.Sprintf("%T", selp.Action()), "*action.")
+	}
+	amount := big.NewInt(0)
+	if act, ok := selp.Action().(hasAmount); ok && act.Amount() != nil {
+		amount = act.Amount()
+	}
+	return fmt.Sprintf("%s: %s Rau + %d gas × %s Rau", dst, amount, selp.GasLimit(), selp.GasPrice())
+}
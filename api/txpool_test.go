@@ -0,0 +1,67 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/test/identityset"
+	"github.com/iotexproject/iotex-core/testutil"
+)
+
+func TestServer_TxPool(t *testing.T) {
+	require := require.New(t)
+	cfg := newConfig(t)
+	ctx := context.Background()
+
+	svr, bfIndexFile, err := createServer(cfg, false)
+	require.NoError(err)
+	defer func() {
+		testutil.CleanupPath(t, bfIndexFile)
+	}()
+
+	sender := identityset.Address(27).String()
+	status := svr.TxPoolStatus()
+	nonce, err := svr.ap.GetPendingNonce(sender)
+	require.NoError(err)
+
+	tsf1, err := testutil.SignedTransfer(identityset.Address(28).String(), identityset.PrivateKey(27), nonce,
+		big.NewInt(20), []byte{}, testutil.TestGasLimit, big.NewInt(testutil.TestGasPriceInt64))
+	require.NoError(err)
+	tsf2, err := testutil.SignedTransfer(identityset.Address(29).String(), identityset.PrivateKey(27), nonce+1,
+		big.NewInt(20), []byte{}, testutil.TestGasLimit, big.NewInt(testutil.TestGasPriceInt64))
+	require.NoError(err)
+	// execution after a nonce gap is queued
+	execution, err := testutil.SignedExecution("", identityset.PrivateKey(27), nonce+3,
+		big.NewInt(1), testutil.TestGasLimit, big.NewInt(10), []byte{1})
+	require.NoError(err)
+	require.NoError(svr.ap.Add(ctx, tsf1))
+	require.NoError(svr.ap.Add(ctx, tsf2))
+	require.NoError(svr.ap.Add(ctx, execution))
+
+	require.Equal(&TxPoolStatus{Pending: status.Pending + 2, Queued: status.Queued + 1}, svr.TxPoolStatus())
+
+	content, err := svr.TxPoolContent()
+	require.NoError(err)
+	h1 := tsf1.Hash()
+	require.Equal(hex.EncodeToString(h1[:]), content.Pending[sender][nonce].ActHash)
+	h2 := tsf2.Hash()
+	require.Equal(hex.EncodeToString(h2[:]), content.Pending[sender][nonce+1].ActHash)
+	require.NotContains(content.Pending[sender], nonce+3)
+	h3 := execution.Hash()
+	require.Equal(hex.EncodeToString(h3[:]), content.Queued[sender][nonce+3].ActHash)
+	require.Equal(sender, content.Queued[sender][nonce+3].Sender)
+
+	inspect := svr.TxPoolInspect()
+	require.Equal(identityset.Address(28).String()+": 20 Rau + 20000 gas × 0 Rau", inspect.Pending[sender][nonce])
+	require.Equal("contract creation: 1 Rau + 20000 gas × 10 Rau", inspect.Queued[sender][nonce+3])
+}
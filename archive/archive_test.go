@@ -0,0 +1,271 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package archive
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/action"
+	"github.com/iotexproject/iotex-core/blockchain/block"
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/db"
+	"github.com/iotexproject/iotex-core/pkg/util/byteutil"
+	"github.com/iotexproject/iotex-core/state/factory"
+	"github.com/iotexproject/iotex-core/test/identityset"
+	"github.com/iotexproject/iotex-core/testutil"
+)
+
+type testBlockReader struct {
+	blks []*block.Block
+}
+
+func (r *testBlockReader) Height() (uint64, error) {
+	return uint64(len(r.blks)), nil
+}
+
+func (r *testBlockReader) GetBlockByHeight(height uint64) (*block.Block, error) {
+	return r.blks[height-1], nil
+}
+
+func (r *testBlockReader) GetReceipts(height uint64) ([]*action.Receipt, error) {
+	return r.blks[height-1].Receipts, nil
+}
+
+func TestFileStore(t *testing.T) {
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "archive")
+	require.NoError(err)
+	defer testutil.CleanupPath(t, dir)
+
+	ctx := context.Background()
+	store := NewFileStore(dir)
+	_, err = store.Get(ctx, "a/b")
+	require.Equal(ErrObjectNotExist, errors.Cause(err))
+	require.Error(store.Put(ctx, "a/b", strings.NewReader("data"), 5))
+	require.NoError(store.Put(ctx, "a/b", strings.NewReader("data"), 4))
+	r, err := store.Get(ctx, "a/b")
+	require.NoError(err)
+	data, err := ioutil.ReadAll(r)
+	require.NoError(err)
+	require.NoError(r.Close())
+	require.Equal("data", string(data))
+}
+
+func TestS3Store(t *testing.T) {
+	require := require.New(t)
+
+	var (
+		mutex   sync.Mutex
+		objects = map[string][]byte{}
+	)
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth := r.Header.Get("Authorization")
+		if !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=key/20210301/auto/s3/aws4_request, ") {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		mutex.Lock()
+		defer mutex.Unlock()
+		switch r.Method {
+		case http.MethodPut:
+			data, _ := ioutil.ReadAll(r.Body)
+			objects[r.URL.Path] = data
+		case http.MethodGet:
+			data, ok := objects[r.URL.Path]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_, _ = w.Write(data)
+		}
+	}))
+	defer svr.Close()
+
+	store := NewS3Store(svr.URL, "auto", "bucket", "/iotex/", "key", "secret", time.Second).(*s3Store)
+	store.now = func() time.Time { return time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC) }
+	ctx := context.Background()
+	require.NoError(store.Put(ctx, "segments/1 2.gz", strings.NewReader("data"), 4))
+	mutex.Lock()
+	require.Contains(objects, "/bucket/iotex/segments/1 2.gz")
+	mutex.Unlock()
+	r, err := store.Get(ctx, "segments/1 2.gz")
+	require.NoError(err)
+	data, err := ioutil.ReadAll(r)
+	require.NoError(err)
+	require.NoError(r.Close())
+	require.Equal("data", string(data))
+	_, err = store.Get(ctx, "manifest.json")
+	require.Equal(ErrObjectNotExist, errors.Cause(err))
+
+	store.signer.AccessKey = "another"
+	require.Error(store.Put(ctx, "manifest.json", strings.NewReader("data"), 4))
+}
+
+func TestUploadAndRestore(t *testing.T) {
+	require := require.New(t)
+
+	reader := &testBlockReader{}
+	for i := 1; i <= 5; i++ {
+		tsf, err := testutil.SignedTransfer(identityset.Address(1).String(), identityset.PrivateKey(0), uint64(i), big.NewInt(1), nil, 10000, big.NewInt(1))
+		require.NoError(err)
+		blk, err := block.NewTestingBuilder().
+			SetHeight(uint64(i)).
+			SetTimeStamp(time.Now()).
+			AddActions(tsf).
+			SetReceipts([]*action.Receipt{{ActionHash: tsf.Hash(), GasConsumed: 10000, Status: uint64(1)}}).
+			SignAndBuild(identityset.PrivateKey(0))
+		require.NoError(err)
+		reader.blks = append(reader.blks, &blk)
+	}
+
+	dir, err := ioutil.TempDir("", "archive")
+	require.NoError(err)
+	defer testutil.CleanupPath(t, dir)
+	stateDBPath, err := testutil.PathOfTempFile("archive-state")
+	require.NoError(err)
+	defer testutil.CleanupPath(t, stateDBPath)
+
+	ctx := context.Background()
+	dbCfg := config.Default.DB
+	dbCfg.DbPath = stateDBPath
+	stateDB := db.NewBoltDB(dbCfg)
+	require.NoError(stateDB.Start(ctx))
+	defer stateDB.Stop(ctx)
+	require.NoError(stateDB.Put(factory.AccountKVNamespace, []byte(factory.CurrentHeightKey), byteutil.Uint64ToBytes(3)))
+
+	cfg := config.Default
+	cfg.Archive.Type = FileStoreType
+	cfg.Archive.Path = dir
+	cfg.Archive.SegmentSize = 2
+	cfg.Archive.SnapshotInterval = 1
+	store := NewFileStore(dir)
+	u := NewUploader(cfg.Archive, cfg.Genesis.Hash(), reader, stateDB, store)
+	genesisHash := cfg.Genesis.Hash()
+	u.manifest = &Manifest{GenesisHash: hex.EncodeToString(genesisHash[:])}
+	require.NoError(u.upload())
+	require.Equal(2, len(u.manifest.Segments))
+	require.Equal(uint64(4), u.manifest.Height())
+	require.Equal(1, len(u.manifest.Snapshots))
+	require.Equal(uint64(3), u.manifest.LatestSnapshot().Height)
+
+	// nothing new to upload
+	require.NoError(u.upload())
+	require.Equal(2, len(u.manifest.Segments))
+	require.Equal(1, len(u.manifest.Snapshots))
+
+	// segment is intact
+	stores, err := readSegment(ctx, store, u.manifest.Segments[1])
+	require.NoError(err)
+	require.Equal(2, len(stores))
+	require.Equal(reader.blks[2].HashBlock(), stores[0].Block.HashBlock())
+	require.Equal(uint64(1), stores[0].Receipts[0].Status)
+
+	// restore
+	chainDBPath, err := testutil.PathOfTempFile("archive-chain")
+	require.NoError(err)
+	trieDBPath, err := testutil.PathOfTempFile("archive-trie")
+	require.NoError(err)
+	defer func() {
+		testutil.CleanupPath(t, chainDBPath)
+		testutil.CleanupPath(t, trieDBPath)
+	}()
+	cfg.Chain.ChainDBPath = chainDBPath
+	cfg.Chain.TrieDBPath = trieDBPath
+	_, err = Restore(ctx, cfg, store)
+	require.Error(err)
+	require.NoError(os.Remove(chainDBPath))
+	require.NoError(os.Remove(trieDBPath))
+
+	height, err := Restore(ctx, cfg, store)
+	require.NoError(err)
+	require.Equal(uint64(4), height)
+	stateHeight, err := snapshotHeight(trieDBPath)
+	require.NoError(err)
+	require.Equal(uint64(3), stateHeight)
+
+	// corrupted segment is rejected
+	require.NoError(store.Put(ctx, u.manifest.Segments[0].Key, bytes.NewReader([]byte("corrupted")), 9))
+	_, err = readSegment(ctx, store, u.manifest.Segments[0])
+	require.Error(err)
+}
+
+func TestStateSnapshots(t *testing.T) {
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "snapshot")
+	require.NoError(err)
+	defer testutil.CleanupPath(t, dir)
+	stateDBPath, err := testutil.PathOfTempFile("snapshot-state")
+	require.NoError(err)
+	defer testutil.CleanupPath(t, stateDBPath)
+
+	ctx := context.Background()
+	dbCfg := config.Default.DB
+	dbCfg.DbPath = stateDBPath
+	stateDB := db.NewBoltDB(dbCfg)
+	require.NoError(stateDB.Start(ctx))
+	defer stateDB.Stop(ctx)
+	setHeight := func(height uint64) {
+		require.NoError(stateDB.Put(factory.AccountKVNamespace, []byte(factory.CurrentHeightKey), byteutil.Uint64ToBytes(height)))
+	}
+
+	cfg := config.Default
+	cfg.StateSnapshot.Dir = dir
+	cfg.StateSnapshot.Retention = 2
+	s := NewStateSnapshots(cfg.StateSnapshot, stateDB)
+	for _, height := range []uint64{3, 3, 5, 7} {
+		setHeight(height)
+		require.NoError(s.take())
+	}
+	// the oldest snapshot is removed beyond the retention
+	snapshots, err := s.List()
+	require.NoError(err)
+	require.Equal(2, len(snapshots))
+	require.Equal(uint64(5), snapshots[0].Height)
+	require.Equal(uint64(7), snapshots[1].Height)
+
+	// restore the staged snapshot
+	require.Equal(ErrSnapshotNotExist, errors.Cause(s.StageRestore(3)))
+	require.NoError(s.StageRestore(5))
+	trieDBPath, err := testutil.PathOfTempFile("snapshot-trie")
+	require.NoError(err)
+	defer func() {
+		testutil.CleanupPath(t, trieDBPath)
+		testutil.CleanupPath(t, trieDBPath+".faulty")
+	}()
+	cfg.Chain.TrieDBPath = trieDBPath
+	height, err := RestoreStateSnapshot(cfg, 0)
+	require.NoError(err)
+	require.Equal(uint64(5), height)
+	stateHeight, err := snapshotHeight(trieDBPath)
+	require.NoError(err)
+	require.Equal(uint64(5), stateHeight)
+	_, err = os.Stat(trieDBPath + ".faulty")
+	require.NoError(err)
+
+	// nothing staged
+	height, err = RestoreStateSnapshot(cfg, 0)
+	require.NoError(err)
+	require.Zero(height)
+	_, err = RestoreStateSnapshot(cfg, 3)
+	require.Equal(ErrSnapshotNotExist, errors.Cause(err))
+}
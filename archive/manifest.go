@@ -0,0 +1,185 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package archive
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/pkg/errors"
+
+	"github.com/iotexproject/iotex-core/blockchain/block"
+)
+
+const (
+	_manifestKey = "manifest.json"
+	// _maxRecordSize is the max size of a block in the segment, to guard against a corrupted length
+	_maxRecordSize = 64 << 20
+)
+
+type (
+	// Manifest lists the archived objects with their checksums. It is updated after the object is uploaded, so any
+	// object listed in the manifest is complete
+	Manifest struct {
+		GenesisHash string      `json:"genesisHash"`
+		Segments    []*Segment  `json:"segments"`
+		Snapshots   []*Snapshot `json:"snapshots"`
+	}
+
+	// Segment is a gzipped object of the blocks and receipts from Start to End
+	Segment struct {
+		Start  uint64 `json:"start"`
+		End    uint64 `json:"end"`
+		Key    string `json:"key"`
+		Size   int64  `json:"size"`
+		SHA256 string `json:"sha256"`
+	}
+
+	// Snapshot is a copy of the state db at Height
+	Snapshot struct {
+		Height uint64 `json:"height"`
+		Key    string `json:"key"`
+		Size   int64  `json:"size"`
+		SHA256 string `json:"sha256"`
+	}
+)
+
+// Height returns the height of the last archived block
+func (m *Manifest) Height() uint64 {
+	if len(m.Segments) == 0 {
+		return 0
+	}
+	return m.Segments[len(m.Segments)-1].End
+}
+
+// LatestSnapshot returns the latest snapshot which the archived blocks can catch up from, or nil if none
+func (m *Manifest) LatestSnapshot() *Snapshot {
+	height := m.Height()
+	for i := len(m.Snapshots) - 1; i >= 0; i-- {
+		if m.Snapshots[i].Height <= height {
+			return m.Snapshots[i]
+		}
+	}
+	return nil
+}
+
+func segmentKey(start, end uint64) string {
+	return fmt.Sprintf("segments/%012d-%012d.gz", start, end)
+}
+
+func snapshotKey(height uint64) string {
+	return fmt.Sprintf("snapshots/%012d.db", height)
+}
+
+func checksum(data []byte) string {
+	h := sha256.Sum256(data)
+	return hex.EncodeToString(h[:])
+}
+
+// readManifest reads the manifest from the store, it returns ErrObjectNotExist if the archive is empty
+func readManifest(ctx context.Context, store ObjectStore) (*Manifest, error) {
+	r, err := store.Get(ctx, _manifestKey)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	m := &Manifest{}
+	if err := json.NewDecoder(r).Decode(m); err != nil {
+		return nil, errors.Wrap(err, "failed to decode archive manifest")
+	}
+	return m, nil
+}
+
+func writeManifest(ctx context.Context, store ObjectStore, m *Manifest) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return store.Put(ctx, _manifestKey, bytes.NewReader(data), int64(len(data)))
+}
+
+// encodeSegment encodes the blocks into a gzipped stream of length-prefixed serialized block stores
+func encodeSegment(stores []*block.Store) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	lenBuf := make([]byte, binary.MaxVarintLen64)
+	for _, s := range stores {
+		data, err := s.Serialize()
+		if err != nil {
+			return nil, err
+		}
+		n := binary.PutUvarint(lenBuf, uint64(len(data)))
+		if _, err := zw.Write(lenBuf[:n]); err != nil {
+			return nil, err
+		}
+		if _, err := zw.Write(data); err != nil {
+			return nil, err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// decodeSegment decodes the blocks encoded by encodeSegment
+func decodeSegment(data []byte) ([]*block.Store, error) {
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+	r := bufio.NewReader(zr)
+	var stores []*block.Store
+	for {
+		size, err := binary.ReadUvarint(r)
+		if err == io.EOF {
+			return stores, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if size > _maxRecordSize {
+			return nil, errors.Errorf("block size %d exceeds limit", size)
+		}
+		buf := make([]byte, size)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		s := &block.Store{}
+		if err := s.Deserialize(buf); err != nil {
+			return nil, err
+		}
+		stores = append(stores, s)
+	}
+}
+
+// readSegment downloads the segment and verifies its checksum
+func readSegment(ctx context.Context, store ObjectStore, seg *Segment) ([]*block.Store, error) {
+	r, err := store.Get(ctx, seg.Key)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	data, err := ioutil.ReadAll(io.LimitReader(r, seg.Size+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) != seg.Size || checksum(data) != seg.SHA256 {
+		return nil, errors.Errorf("segment %s is corrupted", seg.Key)
+	}
+	return decodeSegment(data)
+}
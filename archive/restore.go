@@ -0,0 +1,121 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package archive
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/iotexproject/iotex-core/action/protocol"
+	"github.com/iotexproject/iotex-core/blockchain/blockdao"
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/pkg/log"
+	"github.com/iotexproject/iotex-core/pkg/util/fileutil"
+)
+
+// Restore bootstraps the chain db from the archived blocks, and the state db from the latest snapshot if any. The
+// state db is brought up to the restored height by replaying blocks when the node starts. It returns the height of
+// the restored chain db
+func Restore(ctx context.Context, cfg config.Config, store ObjectStore) (uint64, error) {
+	if fileutil.FileExists(cfg.Chain.ChainDBPath) {
+		return 0, errors.Errorf("chain db %s already exists", cfg.Chain.ChainDBPath)
+	}
+	if fileutil.FileExists(cfg.Chain.TrieDBPath) {
+		return 0, errors.Errorf("state db %s already exists", cfg.Chain.TrieDBPath)
+	}
+	m, err := readManifest(ctx, store)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to read archive manifest")
+	}
+	genesisHash := cfg.Genesis.Hash()
+	if m.GenesisHash != hex.EncodeToString(genesisHash[:]) {
+		return 0, errors.Errorf("archive of genesis %s doesn't match the chain", m.GenesisHash)
+	}
+
+	if snapshot := m.LatestSnapshot(); snapshot != nil {
+		if err := restoreSnapshot(ctx, store, snapshot, cfg.Chain.TrieDBPath); err != nil {
+			return 0, errors.Wrapf(err, "failed to restore state snapshot at height %d", snapshot.Height)
+		}
+		log.L().Info("Restored state snapshot.", zap.Uint64("height", snapshot.Height))
+	}
+
+	cfg.DB.DbPath = cfg.Chain.ChainDBPath
+	cfg.DB.CompressLegacy = cfg.Chain.CompressBlock
+	dao := blockdao.NewBlockDAO(nil, cfg.DB)
+	ctx = protocol.WithBlockchainCtx(ctx, protocol.BlockchainCtx{Genesis: cfg.Genesis})
+	if err := dao.Start(ctx); err != nil {
+		return 0, err
+	}
+	defer func() {
+		if err := dao.Stop(ctx); err != nil {
+			log.L().Error("Failed to stop block dao.", zap.Error(err))
+		}
+	}()
+	var height uint64
+	for _, seg := range m.Segments {
+		if seg.Start != height+1 {
+			return height, errors.Errorf("segment %s is not contiguous to height %d", seg.Key, height)
+		}
+		stores, err := readSegment(ctx, store, seg)
+		if err != nil {
+			return height, err
+		}
+		for _, s := range stores {
+			if s.Block.Height() != height+1 {
+				return height, errors.Errorf("unexpected block %d in segment %s", s.Block.Height(), seg.Key)
+			}
+			s.Block.Receipts = s.Receipts
+			if err := dao.PutBlock(ctx, s.Block); err != nil {
+				return height, errors.Wrapf(err, "failed to put block %d", s.Block.Height())
+			}
+			height++
+		}
+		if height != seg.End {
+			return height, errors.Errorf("segment %s ends at %d, expecting %d", seg.Key, height, seg.End)
+		}
+		log.L().Info("Restored block segment.", zap.Uint64("start", seg.Start), zap.Uint64("end", seg.End))
+	}
+	return height, nil
+}
+
+// restoreSnapshot downloads the snapshot to a temp file, and moves it to path after verifying the checksum
+func restoreSnapshot(ctx context.Context, store ObjectStore, snapshot *Snapshot, path string) error {
+	r, err := store.Get(ctx, snapshot.Key)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return err
+	}
+	f, err := ioutil.TempFile(filepath.Dir(path), ".tmp-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	h := sha256.New()
+	n, err := io.Copy(io.MultiWriter(f, h), r)
+	if err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if n != snapshot.Size || hex.EncodeToString(h.Sum(nil)) != snapshot.SHA256 {
+		return errors.Errorf("snapshot %s is corrupted", snapshot.Key)
+	}
+	return os.Rename(f.Name(), path)
+}
@@ -0,0 +1,112 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package archive
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/iotexproject/iotex-core/pkg/util/sigv4"
+)
+
+// s3Store stores objects with the s3 rest api, requests are signed with signature version 4 and path-style urls.
+// It works with aws s3 and other s3 compatible storages, such as gcs at https://storage.googleapis.com with hmac keys
+type s3Store struct {
+	endpoint string
+	bucket   string
+	prefix   string
+	signer   *sigv4.Signer
+	client   *http.Client
+	now      func() time.Time
+}
+
+// NewS3Store creates an object store in the bucket of the s3 compatible endpoint
+func NewS3Store(endpoint, region, bucket, prefix, accessKey, secretKey string, timeout time.Duration) ObjectStore {
+	return &s3Store{
+		endpoint: strings.TrimSuffix(endpoint, "/"),
+		bucket:   bucket,
+		prefix:   strings.Trim(prefix, "/"),
+		signer: &sigv4.Signer{
+			AccessKey: accessKey,
+			SecretKey: secretKey,
+			Region:    region,
+			Service:   "s3",
+		},
+		client: &http.Client{Timeout: timeout},
+		now:    time.Now,
+	}
+}
+
+func (s *s3Store) Put(ctx context.Context, key string, r io.Reader, size int64) error {
+	req, err := s.newRequest(ctx, http.MethodPut, key, r)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return errors.Wrapf(err, "failed to put object %s", key)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return s.responseError(resp, key)
+	}
+	return nil
+}
+
+func (s *s3Store) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	req, err := s.newRequest(ctx, http.MethodGet, key, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get object %s", key)
+	}
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return resp.Body, nil
+	case http.StatusNotFound:
+		resp.Body.Close()
+		return nil, errors.Wrap(ErrObjectNotExist, key)
+	default:
+		defer resp.Body.Close()
+		return nil, s.responseError(resp, key)
+	}
+}
+
+func (s *s3Store) newRequest(ctx context.Context, method, key string, body io.Reader) (*http.Request, error) {
+	objectPath := "/" + s.bucket + "/" + key
+	if s.prefix != "" {
+		objectPath = "/" + s.bucket + "/" + path.Join(s.prefix, key)
+	}
+	u, err := url.Parse(s.endpoint)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid endpoint %s", s.endpoint)
+	}
+	u.Path = objectPath
+	u.RawPath = sigv4.EscapePath(objectPath)
+	req, err := http.NewRequest(method, u.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	s.signer.Sign(req, sigv4.UnsignedPayload, s.now())
+	return req.WithContext(ctx), nil
+}
+
+func (s *s3Store) responseError(resp *http.Response, key string) error {
+	body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+	return errors.Errorf("failed to access object %s, status %s: %s", key, resp.Status, strings.TrimSpace(string(body)))
+}
@@ -0,0 +1,275 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package archive
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/iotexproject/iotex-core/blockchain/block"
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/pkg/log"
+	"github.com/iotexproject/iotex-core/pkg/util/fileutil"
+)
+
+// _restoreFile is the file in the snapshot directory recording the height of the snapshot staged to restore
+const _restoreFile = "RESTORE"
+
+// ErrSnapshotNotExist indicates there is no state snapshot at the height
+var ErrSnapshotNotExist = errors.New("state snapshot does not exist")
+
+// StateSnapshots takes a copy of the state db into the local directory every interval blocks in background, and keeps
+// the latest ones of the retention. A snapshot is restored when the node is stopped, then the state db catches up to
+// the chain db by replaying the blocks after the snapshot when the node starts
+type StateSnapshots struct {
+	cfg     config.StateSnapshot
+	stateDB Snapshotter
+	mutex   sync.Mutex
+	notify  chan struct{}
+	done    chan struct{}
+	wg      sync.WaitGroup
+	errMu   sync.RWMutex
+	lastErr error
+}
+
+// NewStateSnapshots creates the state snapshots in the directory of the config
+func NewStateSnapshots(cfg config.StateSnapshot, stateDB Snapshotter) *StateSnapshots {
+	return &StateSnapshots{
+		cfg:     cfg,
+		stateDB: stateDB,
+		notify:  make(chan struct{}, 1),
+		done:    make(chan struct{}),
+	}
+}
+
+// Start creates the snapshot directory and starts taking snapshots
+func (s *StateSnapshots) Start(ctx context.Context) error {
+	if err := os.MkdirAll(s.cfg.Dir, 0750); err != nil {
+		return errors.Wrap(err, "failed to create state snapshot directory")
+	}
+	s.wg.Add(1)
+	go s.run()
+	return nil
+}
+
+// Stop stops taking snapshots
+func (s *StateSnapshots) Stop(ctx context.Context) error {
+	close(s.done)
+	s.wg.Wait()
+	return nil
+}
+
+// ReceiveBlock notifies a snapshot to be taken if the block height is at the interval
+func (s *StateSnapshots) ReceiveBlock(blk *block.Block) error {
+	if blk.Height()%s.cfg.Interval != 0 {
+		return nil
+	}
+	select {
+	case s.notify <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Health returns the error of the last snapshot, nil if it succeeded
+func (s *StateSnapshots) Health() error {
+	s.errMu.RLock()
+	defer s.errMu.RUnlock()
+	return s.lastErr
+}
+
+// List returns the snapshots in ascending order of height
+func (s *StateSnapshots) List() ([]*Snapshot, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return listStateSnapshots(s.cfg.Dir)
+}
+
+// StageRestore stages the snapshot at the height to be restored the next time the node starts
+func (s *StateSnapshots) StageRestore(height uint64) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if !fileutil.FileExists(filepath.Join(s.cfg.Dir, stateSnapshotKey(height))) {
+		return errors.Wrapf(ErrSnapshotNotExist, "height %d", height)
+	}
+	return ioutil.WriteFile(filepath.Join(s.cfg.Dir, _restoreFile), []byte(strconv.FormatUint(height, 10)), 0600)
+}
+
+func (s *StateSnapshots) run() {
+	defer s.wg.Done()
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-s.notify:
+		}
+		err := s.take()
+		if err != nil {
+			log.L().Error("Failed to take state snapshot.", zap.Error(err))
+		}
+		s.errMu.Lock()
+		s.lastErr = err
+		s.errMu.Unlock()
+	}
+}
+
+func (s *StateSnapshots) take() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	f, err := ioutil.TempFile(s.cfg.Dir, ".tmp-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if err := s.stateDB.Backup(f); err != nil {
+		f.Close()
+		return err
+	}
+	size, err := f.Seek(0, io.SeekCurrent)
+	if err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	height, err := snapshotHeight(f.Name())
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(s.cfg.Dir, stateSnapshotKey(height))
+	if fileutil.FileExists(path) {
+		// the state db hasn't moved since the last snapshot
+		return nil
+	}
+	if err := os.Rename(f.Name(), path); err != nil {
+		return err
+	}
+	archiveMtc.WithLabelValues("local").Set(float64(height))
+	log.L().Info("Took state snapshot.", zap.Uint64("height", height), zap.Int64("size", size))
+	return s.prune()
+}
+
+// prune removes the oldest snapshots beyond the retention
+func (s *StateSnapshots) prune() error {
+	snapshots, err := listStateSnapshots(s.cfg.Dir)
+	if err != nil {
+		return err
+	}
+	for i := 0; i < len(snapshots)-s.cfg.Retention; i++ {
+		if err := os.Remove(filepath.Join(s.cfg.Dir, snapshots[i].Key)); err != nil {
+			return err
+		}
+		log.L().Info("Removed state snapshot.", zap.Uint64("height", snapshots[i].Height))
+	}
+	return nil
+}
+
+// RestoreStateSnapshot replaces the state db with the snapshot at the height, or the one staged to restore if height
+// is 0. The faulty state db is kept aside with the suffix ".faulty". It returns the height of the restored snapshot, or
+// 0 if nothing is staged
+func RestoreStateSnapshot(cfg config.Config, height uint64) (uint64, error) {
+	dir := cfg.StateSnapshot.Dir
+	restoreFile := filepath.Join(dir, _restoreFile)
+	if height == 0 {
+		data, err := ioutil.ReadFile(restoreFile)
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		if err != nil {
+			return 0, err
+		}
+		if height, err = strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64); err != nil {
+			return 0, errors.Wrap(err, "invalid staged state snapshot")
+		}
+	}
+	path := filepath.Join(dir, stateSnapshotKey(height))
+	if !fileutil.FileExists(path) {
+		return 0, errors.Wrapf(ErrSnapshotNotExist, "height %d", height)
+	}
+	if fileutil.FileExists(cfg.Chain.TrieDBPath) {
+		if err := os.Rename(cfg.Chain.TrieDBPath, cfg.Chain.TrieDBPath+".faulty"); err != nil {
+			return 0, errors.Wrap(err, "failed to move aside the state db")
+		}
+	}
+	if err := copyFile(path, cfg.Chain.TrieDBPath); err != nil {
+		return 0, errors.Wrapf(err, "failed to restore state snapshot at height %d", height)
+	}
+	if err := os.Remove(restoreFile); err != nil && !os.IsNotExist(err) {
+		return 0, err
+	}
+	return height, nil
+}
+
+// copyFile copies src to a temp file, and moves it to dst
+func copyFile(src, dst string) error {
+	r, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	if err := os.MkdirAll(filepath.Dir(dst), 0750); err != nil {
+		return err
+	}
+	f, err := ioutil.TempFile(filepath.Dir(dst), ".tmp-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), dst)
+}
+
+// listStateSnapshots returns the snapshots in the directory in ascending order of height
+func listStateSnapshots(dir string) ([]*Snapshot, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	// the file names are zero padded, so they are sorted by height
+	snapshots := make([]*Snapshot, 0, len(files))
+	for _, file := range files {
+		var height uint64
+		if _, err := fmt.Sscanf(file.Name(), "state-%d.db", &height); err != nil || file.Name() != stateSnapshotKey(height) {
+			continue
+		}
+		snapshots = append(snapshots, &Snapshot{
+			Height: height,
+			Key:    file.Name(),
+			Size:   file.Size(),
+		})
+	}
+	return snapshots, nil
+}
+
+func stateSnapshotKey(height uint64) string {
+	return fmt.Sprintf("state-%012d.db", height)
+}
@@ -0,0 +1,98 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package archive
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+
+	"github.com/iotexproject/iotex-core/config"
+)
+
+const (
+	// FileStoreType stores the archive in a local directory, which could be a mounted bucket
+	FileStoreType = "file"
+	// S3StoreType stores the archive in an s3 compatible object storage, including gcs with hmac keys
+	S3StoreType = "s3"
+)
+
+// ErrObjectNotExist indicates the object doesn't exist in the object storage
+var ErrObjectNotExist = errors.New("object does not exist")
+
+type (
+	// ObjectStore stores objects by key
+	ObjectStore interface {
+		// Put stores size bytes read from r with key, overwriting the existing object
+		Put(ctx context.Context, key string, r io.Reader, size int64) error
+		// Get returns the content of the object with key, or ErrObjectNotExist
+		Get(ctx context.Context, key string) (io.ReadCloser, error)
+	}
+
+	fileStore struct {
+		dir string
+	}
+)
+
+// NewObjectStore creates the object store of the config
+func NewObjectStore(cfg config.Archive) (ObjectStore, error) {
+	switch cfg.Type {
+	case FileStoreType:
+		return NewFileStore(cfg.Path), nil
+	case S3StoreType:
+		return NewS3Store(cfg.Endpoint, cfg.Region, cfg.Bucket, cfg.Path, cfg.AccessKey, cfg.SecretKey, cfg.Timeout), nil
+	default:
+		return nil, errors.Errorf("unsupported archive type %s", cfg.Type)
+	}
+}
+
+// NewFileStore creates an object store in the local directory
+func NewFileStore(dir string) ObjectStore {
+	return &fileStore{dir: dir}
+}
+
+func (s *fileStore) Put(_ context.Context, key string, r io.Reader, size int64) error {
+	path := filepath.Join(s.dir, filepath.FromSlash(key))
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return err
+	}
+	// write to a temp file and rename, so that a partially written object is never visible
+	f, err := ioutil.TempFile(filepath.Dir(path), ".tmp-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	n, err := io.Copy(f, r)
+	if err != nil {
+		f.Close()
+		return err
+	}
+	if n != size {
+		f.Close()
+		return errors.Errorf("object %s size mismatch, expecting %d, got %d", key, size, n)
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+func (s *fileStore) Get(_ context.Context, key string) (io.ReadCloser, error) {
+	f, err := os.Open(filepath.Join(s.dir, filepath.FromSlash(key)))
+	if os.IsNotExist(err) {
+		return nil, errors.Wrap(ErrObjectNotExist, key)
+	}
+	return f, err
+}
@@ -0,0 +1,278 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package archive
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+
+	"github.com/iotexproject/go-pkgs/hash"
+
+	"github.com/iotexproject/iotex-core/action"
+	"github.com/iotexproject/iotex-core/blockchain/block"
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/db"
+	"github.com/iotexproject/iotex-core/pkg/log"
+	"github.com/iotexproject/iotex-core/pkg/util/byteutil"
+	"github.com/iotexproject/iotex-core/state/factory"
+)
+
+var archiveMtc = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "iotex_archive_height",
+		Help: "Height of the last archived block and state snapshot",
+	},
+	[]string{"type"},
+)
+
+func init() {
+	prometheus.MustRegister(archiveMtc)
+}
+
+type (
+	// BlockReader reads the committed blocks and receipts
+	BlockReader interface {
+		Height() (uint64, error)
+		GetBlockByHeight(uint64) (*block.Block, error)
+		GetReceipts(uint64) ([]*action.Receipt, error)
+	}
+
+	// Snapshotter writes a consistent copy of the state db
+	Snapshotter interface {
+		Backup(io.Writer) error
+	}
+
+	// Uploader uploads sealed segments of blocks and periodic snapshots of the state db to the object storage in
+	// background. The progress is recorded in the manifest of the archive, uploading resumes from it after restart
+	Uploader struct {
+		cfg         config.Archive
+		genesisHash hash.Hash256
+		dao         BlockReader
+		stateDB     Snapshotter
+		store       ObjectStore
+		manifest    *Manifest
+		notify      chan struct{}
+		done        chan struct{}
+		wg          sync.WaitGroup
+		errMu       sync.RWMutex
+		lastErr     error
+	}
+)
+
+// NewUploader creates a new uploader, no snapshot is taken if stateDB is nil
+func NewUploader(cfg config.Archive, genesisHash hash.Hash256, dao BlockReader, stateDB Snapshotter, store ObjectStore) *Uploader {
+	return &Uploader{
+		cfg:         cfg,
+		genesisHash: genesisHash,
+		dao:         dao,
+		stateDB:     stateDB,
+		store:       store,
+		notify:      make(chan struct{}, 1),
+		done:        make(chan struct{}),
+	}
+}
+
+// Start loads the manifest and starts uploading
+func (u *Uploader) Start(ctx context.Context) error {
+	m, err := readManifest(ctx, u.store)
+	switch errors.Cause(err) {
+	case nil:
+		if m.GenesisHash != hex.EncodeToString(u.genesisHash[:]) {
+			return errors.Errorf("archive of genesis %s doesn't match the chain", m.GenesisHash)
+		}
+	case ErrObjectNotExist:
+		m = &Manifest{GenesisHash: hex.EncodeToString(u.genesisHash[:])}
+	default:
+		return errors.Wrap(err, "failed to read archive manifest")
+	}
+	u.manifest = m
+	u.wg.Add(1)
+	go u.run()
+	return nil
+}
+
+// Stop stops the uploader
+func (u *Uploader) Stop(ctx context.Context) error {
+	close(u.done)
+	u.wg.Wait()
+	return nil
+}
+
+// ReceiveBlock notifies the uploader of the new block
+func (u *Uploader) ReceiveBlock(*block.Block) error {
+	select {
+	case u.notify <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Health returns the error of the last uploading attempt, nil if it succeeded
+func (u *Uploader) Health() error {
+	u.errMu.RLock()
+	defer u.errMu.RUnlock()
+	return u.lastErr
+}
+
+func (u *Uploader) run() {
+	defer u.wg.Done()
+	for {
+		err := u.upload()
+		if err != nil {
+			log.L().Error("Failed to upload archive.", zap.Error(err))
+		}
+		u.errMu.Lock()
+		u.lastErr = err
+		u.errMu.Unlock()
+		select {
+		case <-u.done:
+			return
+		case <-u.notify:
+		case <-time.After(u.cfg.RetryInterval):
+		}
+	}
+}
+
+func (u *Uploader) upload() error {
+	tipHeight, err := u.dao.Height()
+	if err != nil {
+		return err
+	}
+	for start := u.manifest.Height() + 1; start+u.cfg.SegmentSize-1 <= tipHeight; start += u.cfg.SegmentSize {
+		select {
+		case <-u.done:
+			return nil
+		default:
+		}
+		if err := u.uploadSegment(start, start+u.cfg.SegmentSize-1); err != nil {
+			return errors.Wrapf(err, "failed to upload segment from %d", start)
+		}
+	}
+	if u.stateDB == nil || u.cfg.SnapshotInterval == 0 {
+		return nil
+	}
+	var lastSnapshot uint64
+	if n := len(u.manifest.Snapshots); n > 0 {
+		lastSnapshot = u.manifest.Snapshots[n-1].Height
+	}
+	if tipHeight < lastSnapshot+u.cfg.SnapshotInterval {
+		return nil
+	}
+	return errors.Wrap(u.uploadSnapshot(), "failed to upload snapshot")
+}
+
+func (u *Uploader) uploadSegment(start, end uint64) error {
+	stores := make([]*block.Store, 0, end-start+1)
+	for height := start; height <= end; height++ {
+		blk, err := u.dao.GetBlockByHeight(height)
+		if err != nil {
+			return err
+		}
+		receipts, err := u.dao.GetReceipts(height)
+		if err != nil {
+			return err
+		}
+		stores = append(stores, &block.Store{Block: blk, Receipts: receipts})
+	}
+	data, err := encodeSegment(stores)
+	if err != nil {
+		return err
+	}
+	seg := &Segment{
+		Start:  start,
+		End:    end,
+		Key:    segmentKey(start, end),
+		Size:   int64(len(data)),
+		SHA256: checksum(data),
+	}
+	ctx := context.Background()
+	if err := u.store.Put(ctx, seg.Key, bytes.NewReader(data), seg.Size); err != nil {
+		return err
+	}
+	u.manifest.Segments = append(u.manifest.Segments, seg)
+	if err := writeManifest(ctx, u.store, u.manifest); err != nil {
+		u.manifest.Segments = u.manifest.Segments[:len(u.manifest.Segments)-1]
+		return err
+	}
+	archiveMtc.WithLabelValues("block").Set(float64(end))
+	log.L().Info("Uploaded block segment.", zap.Uint64("start", start), zap.Uint64("end", end))
+	return nil
+}
+
+func (u *Uploader) uploadSnapshot() error {
+	f, err := ioutil.TempFile("", "iotex-snapshot-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+	h := sha256.New()
+	if err := u.stateDB.Backup(io.MultiWriter(f, h)); err != nil {
+		return err
+	}
+	size, err := f.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+	height, err := snapshotHeight(f.Name())
+	if err != nil {
+		return err
+	}
+	if n := len(u.manifest.Snapshots); n > 0 && height <= u.manifest.Snapshots[n-1].Height {
+		// the state db hasn't moved since the last snapshot
+		return nil
+	}
+	snapshot := &Snapshot{
+		Height: height,
+		Key:    snapshotKey(height),
+		Size:   size,
+		SHA256: hex.EncodeToString(h.Sum(nil)),
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	ctx := context.Background()
+	if err := u.store.Put(ctx, snapshot.Key, f, snapshot.Size); err != nil {
+		return err
+	}
+	u.manifest.Snapshots = append(u.manifest.Snapshots, snapshot)
+	if err := writeManifest(ctx, u.store, u.manifest); err != nil {
+		u.manifest.Snapshots = u.manifest.Snapshots[:len(u.manifest.Snapshots)-1]
+		return err
+	}
+	archiveMtc.WithLabelValues("state").Set(float64(height))
+	log.L().Info("Uploaded state snapshot.", zap.Uint64("height", height), zap.Int64("size", size))
+	return nil
+}
+
+// snapshotHeight reads the height of the state db at path
+func snapshotHeight(path string) (uint64, error) {
+	cfg := config.Default.DB
+	cfg.DbPath = path
+	kv := db.NewBoltDB(cfg)
+	ctx := context.Background()
+	if err := kv.Start(ctx); err != nil {
+		return 0, err
+	}
+	defer kv.Stop(ctx)
+	h, err := kv.Get(factory.AccountKVNamespace, []byte(factory.CurrentHeightKey))
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to read the height of state snapshot")
+	}
+	return byteutil.BytesToUint64(h), nil
+}
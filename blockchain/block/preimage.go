@@ -0,0 +1,85 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package block
+
+import (
+	"github.com/golang/protobuf/proto"
+	"github.com/iotexproject/go-pkgs/hash"
+	"github.com/pkg/errors"
+)
+
+// Identifiers of the algorithms hashing and signing the blocks
+const (
+	// HashAlgorithm hashes the serialized headers and actions
+	HashAlgorithm = "blake2b-256"
+	// SignatureAlgorithm signs the hashes, the signature is 65 bytes of R || S || V
+	SignatureAlgorithm = "secp256k1"
+	// TxRootAlgorithm is the binary merkle tree of the action hashes, hashing the concatenation of the two children
+	// with HashAlgorithm and duplicating the last node of a level of odd size, the root of no action is all zeros
+	TxRootAlgorithm = "merkle-blake2b-256-duplicate-last"
+)
+
+type (
+	// ActionPreimage is the bytes hashed for an action
+	ActionPreimage struct {
+		// Hash is the hash of Serialized, and the leaf of the tx root
+		Hash hash.Hash256
+		// Serialized is the serialized action with the public key and the signature of the sender
+		Serialized []byte
+		// Core is the serialized action core, whose hash is signed by the sender
+		Core []byte
+	}
+
+	// HashPreimage is the exact bytes hashed into the hashes of a block, to verify the hashes and the signatures of
+	// the block independently
+	HashPreimage struct {
+		Hash hash.Hash256
+		// Header is the serialized header, whose hash is the hash of the block
+		Header []byte
+		// HeaderCore is the serialized header core, whose hash is signed by the producer
+		HeaderCore         []byte
+		ProducerPubKey     []byte
+		Signature          []byte
+		Actions            []*ActionPreimage
+		HashAlgorithm      string
+		SignatureAlgorithm string
+		TxRootAlgorithm    string
+	}
+)
+
+// HashPreimage returns the bytes hashed into the hashes of the block
+func (b *Block) HashPreimage() (*HashPreimage, error) {
+	header, err := b.Header.Serialize()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to serialize block header")
+	}
+	preimage := &HashPreimage{
+		Hash:               hash.Hash256b(header),
+		Header:             header,
+		HeaderCore:         b.Header.SerializeCore(),
+		Signature:          b.Header.blockSig,
+		Actions:            make([]*ActionPreimage, 0, len(b.Actions)),
+		HashAlgorithm:      HashAlgorithm,
+		SignatureAlgorithm: SignatureAlgorithm,
+		TxRootAlgorithm:    TxRootAlgorithm,
+	}
+	if b.Header.pubkey != nil {
+		preimage.ProducerPubKey = b.Header.pubkey.Bytes()
+	}
+	for i := range b.Actions {
+		serialized, err := proto.Marshal(b.Actions[i].Proto())
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to serialize action %d", i)
+		}
+		preimage.Actions = append(preimage.Actions, &ActionPreimage{
+			Hash:       hash.Hash256b(serialized),
+			Serialized: serialized,
+			Core:       b.Actions[i].Envelope.Serialize(),
+		})
+	}
+	return preimage, nil
+}
@@ -0,0 +1,46 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package block
+
+import (
+	"testing"
+
+	"github.com/iotexproject/go-pkgs/crypto"
+	"github.com/iotexproject/go-pkgs/hash"
+	"github.com/stretchr/testify/require"
+
+	iotexcrypto "github.com/iotexproject/iotex-core/crypto"
+)
+
+func TestHashPreimage(t *testing.T) {
+	require := require.New(t)
+
+	blk := makeBlock(t, 3)
+	preimage, err := blk.HashPreimage()
+	require.NoError(err)
+	require.Equal(blk.HashBlock(), preimage.Hash)
+	require.Equal(preimage.Hash, hash.Hash256b(preimage.Header))
+	require.Equal(HashAlgorithm, preimage.HashAlgorithm)
+
+	// the producer signs the hash of the header core
+	pk, err := crypto.BytesToPublicKey(preimage.ProducerPubKey)
+	require.NoError(err)
+	coreHash := hash.Hash256b(preimage.HeaderCore)
+	require.True(pk.Verify(coreHash[:], preimage.Signature))
+
+	// the tx root is the merkle root of the action hashes, each signed by the sender
+	require.Len(preimage.Actions, 3)
+	hashes := make([]hash.Hash256, 0, len(preimage.Actions))
+	for i, act := range preimage.Actions {
+		require.Equal(blk.Actions[i].Hash(), act.Hash)
+		require.Equal(act.Hash, hash.Hash256b(act.Serialized))
+		h := hash.Hash256b(act.Core)
+		require.True(blk.Actions[i].SrcPubkey().Verify(h[:], blk.Actions[i].Signature()))
+		hashes = append(hashes, act.Hash)
+	}
+	require.Equal(blk.TxRoot(), iotexcrypto.NewMerkleTree(hashes).HashTree())
+}
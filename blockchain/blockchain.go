@@ -8,7 +8,9 @@ package blockchain
 
 import (
 	"context"
+	"encoding/hex"
 	"strconv"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -126,6 +128,7 @@ type blockchain struct {
 	dao            blockdao.BlockDAO
 	config         config.Config
 	blockValidator block.Validator
+	validated      *validatedBlocks
 	lifecycle      lifecycle.Lifecycle
 	pubSubManager  PubSubManager
 	timerFactory   *prometheustimer.TimerFactory
@@ -194,6 +197,9 @@ func NewBlockchain(cfg config.Config, dao blockdao.BlockDAO, bbf BlockBuilderFac
 		log.L().Panic("Failed to generate prometheus timer factory.", zap.Error(err))
 	}
 	chain.timerFactory = timerFactory
+	if cfg.Chain.WorkingSetCacheSize > 0 {
+		chain.validated = newValidatedBlocks(int(cfg.Chain.WorkingSetCacheSize))
+	}
 	// Set block validator
 	if err != nil {
 		log.L().Panic("Failed to get block producer address.", zap.Error(err))
@@ -295,6 +301,17 @@ func (bc *blockchain) ValidateBlock(blk *block.Block) error {
 			tip.Hash,
 		)
 	}
+	if cp := bc.config.Chain.TrustedCheckpoint; cp.Height > 0 && blk.Height() <= cp.Height {
+		return bc.validateCheckpointedBlock(blk, cp)
+	}
+	if bc.validated == nil {
+		return bc.validateBlock(blk)
+	}
+	return bc.validated.Validate(blk, bc.validateBlock)
+}
+
+// validateBlock verifies the signature and merkle root of the block linked to the tip, and runs the block validator
+func (bc *blockchain) validateBlock(blk *block.Block) error {
 	if err := block.VerifyBlock(blk); err != nil {
 		return errors.Wrap(err, "failed to verify block's signature and merkle root")
 	}
@@ -322,6 +339,28 @@ func (bc *blockchain) ValidateBlock(blk *block.Block) error {
 	return bc.blockValidator.Validate(ctx, blk)
 }
 
+// validateCheckpointedBlock verifies the signature and merkle root of the block up to the trusted checkpoint, without
+// running the actions. The block at the checkpoint must have the trusted hash
+func (bc *blockchain) validateCheckpointedBlock(blk *block.Block, cp config.Checkpoint) error {
+	if err := block.VerifyBlock(blk); err != nil {
+		return errors.Wrap(err, "failed to verify block's signature and merkle root")
+	}
+	if blk.Height() != cp.Height {
+		return nil
+	}
+	blkHash := blk.HashBlock()
+	if hex.EncodeToString(blkHash[:]) != strings.ToLower(cp.Hash) {
+		return errors.Wrapf(
+			ErrInvalidBlock,
+			"block %x at the checkpoint height %d, expecting %s",
+			blkHash,
+			cp.Height,
+			cp.Hash,
+		)
+	}
+	return nil
+}
+
 func (bc *blockchain) Context() (context.Context, error) {
 	bc.mu.RLock()
 	defer bc.mu.RUnlock()
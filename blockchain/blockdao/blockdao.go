@@ -8,6 +8,7 @@ package blockdao
 
 import (
 	"context"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -48,6 +49,8 @@ type (
 		GetActionByActionHash(hash.Hash256, uint64) (action.SealedEnvelope, error)
 		GetReceiptByActionHash(hash.Hash256, uint64) (*action.Receipt, error)
 		DeleteBlockToTarget(uint64) error
+		// AddIndexer starts the indexer, catches it up to the tip and indexes the blocks put afterwards
+		AddIndexer(context.Context, BlockIndexer) error
 	}
 
 	// BlockIndexer defines an interface to accept block to build index
@@ -61,6 +64,7 @@ type (
 
 	blockDAO struct {
 		blockStore   filedao.FileDAO
+		mutex        sync.RWMutex // mutex guards the indexers, which could be added after the dao started
 		indexers     []BlockIndexer
 		timerFactory *prometheustimer.TimerFactory
 		lifecycle    lifecycle.Lifecycle
@@ -130,63 +134,108 @@ func (dao *blockDAO) fillWithBlockInfoAsTip(ctx context.Context, height uint64)
 }
 
 func (dao *blockDAO) checkIndexers(ctx context.Context) error {
+	for ii, indexer := range dao.indexers {
+		if err := dao.catchUp(ctx, ii, indexer, dao.tipHeight); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// catchUp puts the blocks from the tip of the indexer to the target height into the indexer
+func (dao *blockDAO) catchUp(ctx context.Context, ii int, indexer BlockIndexer, targetHeight uint64) error {
 	bcCtx, ok := protocol.GetBlockchainCtx(ctx)
 	if !ok {
 		return errors.New("failed to find blockchain ctx")
 	}
-	for ii, indexer := range dao.indexers {
-		tipHeight, err := indexer.Height()
+	tipHeight, err := indexer.Height()
+	if err != nil {
+		return err
+	}
+	if tipHeight > targetHeight {
+		if _, ok := indexer.(*checkpointIndexer); ok {
+			log.L().Info(
+				"indexer is ahead of the dao syncing to the checkpoint.",
+				zap.Int("indexer", ii),
+				zap.Uint64("height", tipHeight),
+			)
+			return nil
+		}
+		// TODO: delete block
+		return errors.New("indexer tip height cannot by higher than dao tip height")
+	}
+	for i := tipHeight + 1; i <= targetHeight; i++ {
+		blk, err := dao.GetBlockByHeight(i)
 		if err != nil {
 			return err
 		}
-		if tipHeight > dao.tipHeight {
-			// TODO: delete block
-			return errors.New("indexer tip height cannot by higher than dao tip height")
-		}
-		for i := tipHeight + 1; i <= dao.tipHeight; i++ {
-			blk, err := dao.GetBlockByHeight(i)
-			if err != nil {
-				return err
-			}
-			if blk.Receipts == nil {
-				blk.Receipts, err = dao.GetReceipts(i)
-				if err != nil {
-					return err
-				}
-			}
-			producer, err := address.FromBytes(blk.PublicKey().Hash())
+		if blk.Receipts == nil {
+			blk.Receipts, err = dao.GetReceipts(i)
 			if err != nil {
 				return err
 			}
-			ctx, err = dao.fillWithBlockInfoAsTip(ctx, i-1)
-			if err != nil {
-				return err
-			}
-			if err := indexer.PutBlock(protocol.WithBlockCtx(
-				ctx,
-				protocol.BlockCtx{
-					BlockHeight:    i,
-					BlockTimeStamp: blk.Timestamp(),
-					Producer:       producer,
-					GasLimit:       bcCtx.Genesis.BlockGasLimit,
-				},
-			), blk); err != nil {
-				return err
-			}
-			if i%5000 == 0 {
-				log.L().Info(
-					"indexer is catching up.",
-					zap.Int("indexer", ii),
-					zap.Uint64("height", i),
-				)
-			}
 		}
-		log.L().Info(
-			"indexer is up to date.",
-			zap.Int("indexer", ii),
-			zap.Uint64("height", tipHeight),
-		)
+		producer, err := address.FromBytes(blk.PublicKey().Hash())
+		if err != nil {
+			return err
+		}
+		ctx, err = dao.fillWithBlockInfoAsTip(ctx, i-1)
+		if err != nil {
+			return err
+		}
+		if err := indexer.PutBlock(protocol.WithBlockCtx(
+			ctx,
+			protocol.BlockCtx{
+				BlockHeight:    i,
+				BlockTimeStamp: blk.Timestamp(),
+				Producer:       producer,
+				GasLimit:       bcCtx.Genesis.BlockGasLimit,
+			},
+		), blk); err != nil {
+			return err
+		}
+		if i%5000 == 0 {
+			log.L().Info(
+				"indexer is catching up.",
+				zap.Int("indexer", ii),
+				zap.Uint64("height", i),
+			)
+		}
+	}
+	log.L().Info(
+		"indexer is up to date.",
+		zap.Int("indexer", ii),
+		zap.Uint64("height", tipHeight),
+	)
+	return nil
+}
+
+// AddIndexer materializes an indexer disabled when the dao was created. Most of the blocks are put into the indexer
+// without blocking the dao, the ones committed meanwhile are caught up with before the indexer is attached
+func (dao *blockDAO) AddIndexer(ctx context.Context, indexer BlockIndexer) error {
+	dao.mutex.RLock()
+	for _, idx := range dao.indexers {
+		if idx == indexer {
+			dao.mutex.RUnlock()
+			return errors.New("indexer has been added")
+		}
+	}
+	ii := len(dao.indexers)
+	dao.mutex.RUnlock()
+
+	if err := indexer.Start(ctx); err != nil {
+		return errors.Wrap(err, "failed to start indexer")
+	}
+	if err := dao.catchUp(ctx, ii, indexer, atomic.LoadUint64(&dao.tipHeight)); err != nil {
+		return err
+	}
+	dao.mutex.Lock()
+	defer dao.mutex.Unlock()
+	if err := dao.catchUp(ctx, ii, indexer, atomic.LoadUint64(&dao.tipHeight)); err != nil {
+		return err
 	}
+	dao.indexers = append(dao.indexers, indexer)
+	dao.lifecycle.Add(indexer)
 	return nil
 }
 
@@ -314,6 +363,8 @@ func (dao *blockDAO) PutBlock(ctx context.Context, blk *block.Block) error {
 	timer := dao.timerFactory.NewTimer("put_block")
 	defer timer.End()
 
+	dao.mutex.RLock()
+	defer dao.mutex.RUnlock()
 	var err error
 	ctx, err = dao.fillWithBlockInfoAsTip(ctx, dao.tipHeight)
 	if err != nil {
@@ -343,6 +394,8 @@ func (dao *blockDAO) DeleteTipBlock() error {
 }
 
 func (dao *blockDAO) DeleteBlockToTarget(targetHeight uint64) error {
+	dao.mutex.RLock()
+	defer dao.mutex.RUnlock()
 	tipHeight, err := dao.blockStore.Height()
 	if err != nil {
 		return err
@@ -408,6 +408,103 @@ func TestBlockDAO(t *testing.T) {
 	}
 }
 
+type testIndexer struct {
+	started bool
+	heights []uint64
+}
+
+func (ti *testIndexer) Start(context.Context) error {
+	ti.started = true
+	return nil
+}
+
+func (ti *testIndexer) Stop(context.Context) error {
+	ti.started = false
+	return nil
+}
+
+func (ti *testIndexer) Height() (uint64, error) {
+	if len(ti.heights) == 0 {
+		return 0, nil
+	}
+	return ti.heights[len(ti.heights)-1], nil
+}
+
+func (ti *testIndexer) PutBlock(_ context.Context, blk *block.Block) error {
+	ti.heights = append(ti.heights, blk.Height())
+	return nil
+}
+
+func (ti *testIndexer) DeleteTipBlock(*block.Block) error {
+	ti.heights = ti.heights[:len(ti.heights)-1]
+	return nil
+}
+
+func TestAddIndexer(t *testing.T) {
+	require := require.New(t)
+
+	blks := getTestBlocks(t)
+	ctx := protocol.WithBlockchainCtx(
+		context.Background(),
+		protocol.BlockchainCtx{
+			Genesis: config.Default.Genesis,
+		},
+	)
+	indexer := &testIndexer{}
+	dao := NewBlockDAOInMemForTest([]BlockIndexer{indexer})
+	require.NoError(dao.Start(ctx))
+	for _, blk := range blks[:2] {
+		blk.Receipts = []*action.Receipt{}
+		require.NoError(dao.PutBlock(ctx, blk))
+	}
+
+	// the indexer disabled is caught up with the blocks committed before it is added
+	lazy := &testIndexer{}
+	require.NoError(dao.AddIndexer(ctx, lazy))
+	require.True(lazy.started)
+	require.Equal([]uint64{1, 2}, lazy.heights)
+	require.Error(dao.AddIndexer(ctx, lazy))
+	require.Error(dao.AddIndexer(ctx, indexer))
+
+	blks[2].Receipts = []*action.Receipt{}
+	require.NoError(dao.PutBlock(ctx, blks[2]))
+	require.Equal([]uint64{1, 2, 3}, indexer.heights)
+	require.Equal([]uint64{1, 2, 3}, lazy.heights)
+	require.NoError(dao.DeleteBlockToTarget(1))
+	require.Equal([]uint64{1}, lazy.heights)
+
+	require.NoError(dao.Stop(ctx))
+	require.False(lazy.started)
+}
+
+func TestCheckpointIndexer(t *testing.T) {
+	require := require.New(t)
+
+	blks := getTestBlocks(t)
+	ctx := protocol.WithBlockchainCtx(
+		context.Background(),
+		protocol.BlockchainCtx{
+			Genesis: config.Default.Genesis,
+		},
+	)
+	// the indexer restored from a snapshot at height 2 is ahead of the dao
+	restored := &testIndexer{heights: []uint64{2}}
+	dao := NewBlockDAOInMemForTest([]BlockIndexer{restored})
+	require.Error(dao.Start(ctx))
+
+	restored = &testIndexer{heights: []uint64{2}}
+	dao = NewBlockDAOInMemForTest([]BlockIndexer{NewCheckpointIndexer(restored)})
+	require.NoError(dao.Start(ctx))
+	for _, blk := range blks[:3] {
+		blk.Receipts = []*action.Receipt{}
+		require.NoError(dao.PutBlock(ctx, blk))
+	}
+	require.Equal([]uint64{2, 3}, restored.heights)
+	require.NoError(dao.DeleteBlockToTarget(2))
+	require.Equal([]uint64{2}, restored.heights)
+	require.NoError(dao.Stop(ctx))
+}
+
 func createTestBlockDAO(inMemory, legacy bool, compressBlock string, cfg config.DB) (BlockDAO, error) {
 	if inMemory {
 		return NewBlockDAOInMemForTest(nil), nil
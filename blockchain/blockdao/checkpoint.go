@@ -0,0 +1,48 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package blockdao
+
+import (
+	"context"
+
+	"github.com/iotexproject/iotex-core/blockchain/block"
+)
+
+// checkpointIndexer is an indexer restored from a snapshot, e.g., the state factory, which is ahead of the dao syncing
+// the blocks up to a trusted checkpoint. The blocks the indexer already has are skipped
+type checkpointIndexer struct {
+	BlockIndexer
+}
+
+// NewCheckpointIndexer wraps the indexer restored from a snapshot, so that the dao accepts it being ahead of the tip
+func NewCheckpointIndexer(indexer BlockIndexer) BlockIndexer {
+	return &checkpointIndexer{indexer}
+}
+
+// PutBlock puts the block into the indexer if it is higher than the indexer's tip
+func (ci *checkpointIndexer) PutBlock(ctx context.Context, blk *block.Block) error {
+	height, err := ci.BlockIndexer.Height()
+	if err != nil {
+		return err
+	}
+	if blk.Height() <= height {
+		return nil
+	}
+	return ci.BlockIndexer.PutBlock(ctx, blk)
+}
+
+// DeleteTipBlock deletes the block from the indexer if it is the indexer's tip
+func (ci *checkpointIndexer) DeleteTipBlock(blk *block.Block) error {
+	height, err := ci.BlockIndexer.Height()
+	if err != nil {
+		return err
+	}
+	if blk.Height() != height {
+		return nil
+	}
+	return ci.BlockIndexer.DeleteTipBlock(blk)
+}
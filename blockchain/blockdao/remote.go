@@ -0,0 +1,263 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package blockdao
+
+import (
+	"context"
+	"encoding/hex"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+
+	"github.com/iotexproject/go-pkgs/hash"
+	"github.com/iotexproject/iotex-proto/golang/iotexapi"
+	"github.com/iotexproject/iotex-proto/golang/iotextypes"
+
+	"github.com/iotexproject/iotex-core/action"
+	"github.com/iotexproject/iotex-core/blockchain/block"
+	"github.com/iotexproject/iotex-core/blockchain/filedao"
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/db"
+)
+
+const _remoteCallTimeout = 10 * time.Second
+
+// remoteFileDAO reads block data from a remote chain-data service. It is read-only, blocks committed locally must
+// already exist on the remote service with the same hash
+type remoteFileDAO struct {
+	endpoint  string
+	conn      *grpc.ClientConn
+	client    iotexapi.APIServiceClient
+	tipHeight uint64
+}
+
+// NewRemoteBlockDAO instantiates a block DAO which reads block data from the remote chain-data service at endpoint
+func NewRemoteBlockDAO(indexers []BlockIndexer, endpoint string, cfg config.DB) (BlockDAO, error) {
+	if endpoint == "" {
+		return nil, errors.New("empty endpoint of remote chain-data service")
+	}
+	return createBlockDAO(&remoteFileDAO{endpoint: endpoint}, indexers, cfg), nil
+}
+
+// newRemoteFileDAOWithClient creates a remote file DAO on top of an existing api client
+func newRemoteFileDAOWithClient(client iotexapi.APIServiceClient) *remoteFileDAO {
+	return &remoteFileDAO{client: client}
+}
+
+func (fd *remoteFileDAO) Start(ctx context.Context) error {
+	if fd.client == nil {
+		conn, err := grpc.Dial(fd.endpoint, grpc.WithInsecure())
+		if err != nil {
+			return errors.Wrapf(err, "failed to connect to remote chain-data service %s", fd.endpoint)
+		}
+		fd.conn = conn
+		fd.client = iotexapi.NewAPIServiceClient(conn)
+	}
+	_, err := fd.refreshTipHeight()
+	return err
+}
+
+func (fd *remoteFileDAO) Stop(ctx context.Context) error {
+	if fd.conn != nil {
+		return fd.conn.Close()
+	}
+	return nil
+}
+
+func (fd *remoteFileDAO) Height() (uint64, error) {
+	return atomic.LoadUint64(&fd.tipHeight), nil
+}
+
+func (fd *remoteFileDAO) GetBlockHash(height uint64) (hash.Hash256, error) {
+	if height == 0 {
+		return hash.ZeroHash256, nil
+	}
+	meta, err := fd.blockMeta(&iotexapi.GetBlockMetasRequest{
+		Lookup: &iotexapi.GetBlockMetasRequest_ByIndex{
+			ByIndex: &iotexapi.GetBlockMetasByIndexRequest{Start: height, Count: 1},
+		},
+	})
+	if err != nil {
+		return hash.ZeroHash256, errors.Wrapf(err, "failed to get block hash at height %d", height)
+	}
+	return hash.HexStringToHash256(meta.Hash)
+}
+
+func (fd *remoteFileDAO) GetBlockHeight(h hash.Hash256) (uint64, error) {
+	meta, err := fd.blockMeta(&iotexapi.GetBlockMetasRequest{
+		Lookup: &iotexapi.GetBlockMetasRequest_ByHash{
+			ByHash: &iotexapi.GetBlockMetaByHashRequest{BlkHash: hex.EncodeToString(h[:])},
+		},
+	})
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to get block height")
+	}
+	return meta.Height, nil
+}
+
+func (fd *remoteFileDAO) GetBlock(h hash.Hash256) (*block.Block, error) {
+	height, err := fd.GetBlockHeight(h)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get block")
+	}
+	return fd.GetBlockByHeight(height)
+}
+
+func (fd *remoteFileDAO) GetBlockByHeight(height uint64) (*block.Block, error) {
+	info, err := fd.rawBlock(height, false, false)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get block at height %d", height)
+	}
+	blk := &block.Block{}
+	if err := blk.ConvertFromBlockPb(info.Block); err != nil {
+		return nil, err
+	}
+	return blk, nil
+}
+
+func (fd *remoteFileDAO) GetReceipts(height uint64) ([]*action.Receipt, error) {
+	info, err := fd.rawBlock(height, true, false)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get receipts at height %d", height)
+	}
+	receipts := make([]*action.Receipt, 0, len(info.Receipts))
+	for _, pb := range info.Receipts {
+		r := &action.Receipt{}
+		r.ConvertFromReceiptPb(pb)
+		receipts = append(receipts, r)
+	}
+	return receipts, nil
+}
+
+func (fd *remoteFileDAO) ContainsTransactionLog() bool {
+	return true
+}
+
+func (fd *remoteFileDAO) TransactionLogs(height uint64) (*iotextypes.TransactionLogs, error) {
+	info, err := fd.rawBlock(height, false, true)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get transaction log at height %d", height)
+	}
+	if info.TransactionLogs == nil {
+		return nil, db.ErrNotExist
+	}
+	return info.TransactionLogs, nil
+}
+
+func (fd *remoteFileDAO) PutBlock(_ context.Context, blk *block.Block) error {
+	tip := atomic.LoadUint64(&fd.tipHeight)
+	if blk.Height() != tip+1 {
+		return filedao.ErrInvalidTipHeight
+	}
+	h, err := fd.GetBlockHash(blk.Height())
+	if err != nil {
+		return errors.Wrapf(err, "block %d is not available on remote chain-data service", blk.Height())
+	}
+	if h != blk.HashBlock() {
+		return errors.Wrapf(filedao.ErrDataCorruption, "block %d hash mismatch with remote chain-data service", blk.Height())
+	}
+	atomic.StoreUint64(&fd.tipHeight, blk.Height())
+	return nil
+}
+
+func (fd *remoteFileDAO) DeleteTipBlock() error {
+	return filedao.ErrNotSupported
+}
+
+func (fd *remoteFileDAO) Header(h hash.Hash256) (*block.Header, error) {
+	blk, err := fd.GetBlock(h)
+	if err != nil {
+		return nil, err
+	}
+	return &blk.Header, nil
+}
+
+func (fd *remoteFileDAO) HeaderByHeight(height uint64) (*block.Header, error) {
+	blk, err := fd.GetBlockByHeight(height)
+	if err != nil {
+		return nil, err
+	}
+	return &blk.Header, nil
+}
+
+func (fd *remoteFileDAO) FooterByHeight(height uint64) (*block.Footer, error) {
+	blk, err := fd.GetBlockByHeight(height)
+	if err != nil {
+		return nil, err
+	}
+	return &blk.Footer, nil
+}
+
+// refreshTipHeight moves the tip height forward to the height of the remote chain-data service, which keeps growing
+// when the blocks are not committed locally, e.g., on a stateless API node
+func (fd *remoteFileDAO) refreshTipHeight() (uint64, error) {
+	height, err := fd.remoteHeight()
+	if err != nil {
+		return 0, err
+	}
+	for {
+		tip := atomic.LoadUint64(&fd.tipHeight)
+		if height <= tip {
+			return tip, nil
+		}
+		if atomic.CompareAndSwapUint64(&fd.tipHeight, tip, height) {
+			return height, nil
+		}
+	}
+}
+
+func (fd *remoteFileDAO) remoteHeight() (uint64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), _remoteCallTimeout)
+	defer cancel()
+	res, err := fd.client.GetChainMeta(ctx, &iotexapi.GetChainMetaRequest{})
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to get remote chain meta")
+	}
+	return res.ChainMeta.Height, nil
+}
+
+func (fd *remoteFileDAO) blockMeta(in *iotexapi.GetBlockMetasRequest) (*iotextypes.BlockMeta, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), _remoteCallTimeout)
+	defer cancel()
+	res, err := fd.client.GetBlockMetas(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	if len(res.BlkMetas) == 0 {
+		return nil, db.ErrNotExist
+	}
+	return res.BlkMetas[0], nil
+}
+
+func (fd *remoteFileDAO) rawBlock(height uint64, withReceipts, withTransactionLogs bool) (*iotexapi.BlockInfo, error) {
+	if height > atomic.LoadUint64(&fd.tipHeight) {
+		tip, err := fd.refreshTipHeight()
+		if err != nil {
+			return nil, err
+		}
+		if height > tip {
+			return nil, db.ErrNotExist
+		}
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), _remoteCallTimeout)
+	defer cancel()
+	res, err := fd.client.GetRawBlocks(ctx, &iotexapi.GetRawBlocksRequest{
+		StartHeight:         height,
+		Count:               1,
+		WithReceipts:        withReceipts,
+		WithTransactionLogs: withTransactionLogs,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(res.Blocks) == 0 {
+		return nil, db.ErrNotExist
+	}
+	return res.Blocks[0], nil
+}
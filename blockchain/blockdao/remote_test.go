@@ -0,0 +1,105 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package blockdao
+
+import (
+	"context"
+	"encoding/hex"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	"github.com/iotexproject/iotex-proto/golang/iotexapi"
+	"github.com/iotexproject/iotex-proto/golang/iotextypes"
+
+	"github.com/iotexproject/iotex-core/action"
+	"github.com/iotexproject/iotex-core/blockchain/filedao"
+	"github.com/iotexproject/iotex-core/db"
+	"github.com/iotexproject/iotex-core/test/mock/mock_apiserviceclient"
+)
+
+func TestRemoteFileDAO(t *testing.T) {
+	require := require.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	blks := getTestBlocks(t)
+	blks[0].Receipts = []*action.Receipt{
+		{Status: 1, BlockHeight: 1, ActionHash: blks[0].Actions[0].Hash(), GasConsumed: 15, ContractAddress: "1"},
+	}
+	client := mock_apiserviceclient.NewMockServiceClient(ctrl)
+	gomock.InOrder(
+		client.EXPECT().GetChainMeta(gomock.Any(), gomock.Any()).Return(&iotexapi.GetChainMetaResponse{
+			ChainMeta: &iotextypes.ChainMeta{Height: 1},
+		}, nil).Times(1),
+		client.EXPECT().GetChainMeta(gomock.Any(), gomock.Any()).Return(&iotexapi.GetChainMetaResponse{
+			ChainMeta: &iotextypes.ChainMeta{Height: 2},
+		}, nil).AnyTimes(),
+	)
+	client.EXPECT().GetRawBlocks(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, in *iotexapi.GetRawBlocksRequest, _ ...grpc.CallOption) (*iotexapi.GetRawBlocksResponse, error) {
+			require.True(in.StartHeight == 1 || in.StartHeight == 2)
+			if in.StartHeight == 2 {
+				return &iotexapi.GetRawBlocksResponse{
+					Blocks: []*iotexapi.BlockInfo{{Block: blks[1].ConvertToBlockPb()}},
+				}, nil
+			}
+			info := &iotexapi.BlockInfo{Block: blks[0].ConvertToBlockPb()}
+			if in.WithReceipts {
+				info.Receipts = []*iotextypes.Receipt{blks[0].Receipts[0].ConvertToReceiptPb()}
+			}
+			return &iotexapi.GetRawBlocksResponse{Blocks: []*iotexapi.BlockInfo{info}}, nil
+		}).AnyTimes()
+	h1 := blks[0].HashBlock()
+	client.EXPECT().GetBlockMetas(gomock.Any(), gomock.Any()).Return(&iotexapi.GetBlockMetasResponse{
+		Total:    1,
+		BlkMetas: []*iotextypes.BlockMeta{{Hash: hex.EncodeToString(h1[:]), Height: 1}},
+	}, nil).AnyTimes()
+
+	fd := newRemoteFileDAOWithClient(client)
+	ctx := context.Background()
+	require.NoError(fd.Start(ctx))
+	defer func() {
+		require.NoError(fd.Stop(ctx))
+	}()
+
+	height, err := fd.Height()
+	require.NoError(err)
+	require.Equal(uint64(1), height)
+
+	h, err := fd.GetBlockHash(1)
+	require.NoError(err)
+	require.Equal(h1, h)
+	height, err = fd.GetBlockHeight(h1)
+	require.NoError(err)
+	require.Equal(uint64(1), height)
+
+	blk, err := fd.GetBlock(h1)
+	require.NoError(err)
+	require.Equal(h1, blk.HashBlock())
+	receipts, err := fd.GetReceipts(1)
+	require.NoError(err)
+	require.Equal(1, len(receipts))
+	require.Equal(blks[0].Receipts[0].Hash(), receipts[0].Hash())
+
+	// the tip follows the remote chain-data service
+	blk, err = fd.GetBlockByHeight(2)
+	require.NoError(err)
+	require.Equal(blks[1].HashBlock(), blk.HashBlock())
+	height, err = fd.Height()
+	require.NoError(err)
+	require.Equal(uint64(2), height)
+	// block beyond the remote tip is not served
+	_, err = fd.GetBlockByHeight(3)
+	require.Equal(db.ErrNotExist, errors.Cause(err))
+	// remote file DAO is read-only
+	require.Equal(filedao.ErrNotSupported, fd.DeleteTipBlock())
+	require.Equal(filedao.ErrInvalidTipHeight, fd.PutBlock(ctx, blks[0]))
+}
@@ -39,26 +39,34 @@ func init() {
 func defaultConfig() Genesis {
 	return Genesis{
 		Blockchain: Blockchain{
-			Timestamp:               1546329600,
-			BlockGasLimit:           20000000,
-			ActionGasLimit:          5000000,
-			BlockInterval:           10 * time.Second,
-			NumSubEpochs:            2,
-			DardanellesNumSubEpochs: 30,
-			NumDelegates:            24,
-			NumCandidateDelegates:   36,
-			TimeBasedRotation:       false,
-			PacificBlockHeight:      432001,
-			AleutianBlockHeight:     864001,
-			BeringBlockHeight:       1512001,
-			CookBlockHeight:         1641601,
-			DardanellesBlockHeight:  1816201,
-			DaytonaBlockHeight:      3238921,
-			EasterBlockHeight:       4478761,
-			FbkMigrationBlockHeight: 5157001,
-			FairbankBlockHeight:     5165641,
-			GreenlandBlockHeight:    6544441,
-			HawaiiBlockHeight:       11073241,
+			Timestamp:                 1546329600,
+			BlockGasLimit:             20000000,
+			ActionGasLimit:            5000000,
+			BlockInterval:             10 * time.Second,
+			NumSubEpochs:              2,
+			DardanellesNumSubEpochs:   30,
+			NumDelegates:              24,
+			NumCandidateDelegates:     36,
+			TimeBasedRotation:         false,
+			PacificBlockHeight:        432001,
+			AleutianBlockHeight:       864001,
+			BeringBlockHeight:         1512001,
+			CookBlockHeight:           1641601,
+			DardanellesBlockHeight:    1816201,
+			DaytonaBlockHeight:        3238921,
+			EasterBlockHeight:         4478761,
+			FbkMigrationBlockHeight:   5157001,
+			FairbankBlockHeight:       5165641,
+			GreenlandBlockHeight:      6544441,
+			HawaiiBlockHeight:         11073241,
+			IcelandBlockHeight:        12289321,
+			JutlandBlockHeight:        13685401,
+			KamchatkaBlockHeight:      13979161,
+			ContractDeployerAllowlist: []string{},
+			SanctionGovernors:         []string{},
+			SanctionApprovals:         1,
+			ParameterGovernors:        []string{},
+			ParameterApprovals:        1,
 		},
 		Account: Account{
 			InitBalanceMap: make(map[string]string),
@@ -70,6 +78,7 @@ func defaultConfig() Genesis {
 			ProbationEpochPeriod:             6,
 			ProbationIntensityRate:           90,
 			UnproductiveDelegateMaxCacheSize: 20,
+			MinActiveDelegates:               4,
 		},
 		Rewarding: Rewarding{
 			InitBalanceStr:                 unit.ConvertIotxToRau(200000000).String(),
@@ -98,6 +107,14 @@ func defaultConfig() Genesis {
 			WithdrawWaitingPeriod: 3 * 24 * time.Hour,
 			MinStakeAmount:        unit.ConvertIotxToRau(100).String(),
 			BootstrapCandidates:   []BootstrapCandidate{},
+			VoteWeightCurve: VoteWeightCurve{
+				DurationBonus:     380,
+				AutoStakeBonus:    380,
+				SelfStakeBonus:    600,
+				StalenessPeriod:   30 * 24 * time.Hour,
+				StalenessDecay:    100,
+				MaxStalenessDecay: 5000,
+			},
 		},
 	}
 }
@@ -172,11 +189,35 @@ type (
 		GreenlandBlockHeight uint64 `yaml:"greenlandHeight"`
 		// HawaiiBlockHeight is the start height to fix GetBlockHash in EVM
 		HawaiiBlockHeight uint64 `yaml:"hawaiiHeight"`
+		// IcelandBlockHeight is the start height to calculate probation voting power with integer arithmetic
+		IcelandBlockHeight uint64 `yaml:"icelandHeight"`
+		// JutlandBlockHeight is the start height to resize the (active) block producers according to the number of
+		// qualified candidates
+		JutlandBlockHeight uint64 `yaml:"jutlandHeight"`
+		// KamchatkaBlockHeight is the start height to calculate the vote weight of buckets with the curve stored in state,
+		// and recalculate the votes of candidates at each epoch start
+		KamchatkaBlockHeight uint64 `yaml:"kamchatkaHeight"`
+		// ContractDeployerAllowlist is the addresses allowed to deploy contracts, for private chains to restrict contract
+		// creation. Any address is allowed to deploy contracts if it is empty
+		ContractDeployerAllowlist []string `yaml:"contractDeployerAllowlist"`
+		// SanctionGovernors is the addresses managing the sanction list, whose addresses are rejected as the senders
+		// and the recipients of the actions. The sanction list is disabled if it is empty
+		SanctionGovernors []string `yaml:"sanctionGovernors"`
+		// SanctionApprovals is the number of the governors approving a change of the sanction list to apply it
+		SanctionApprovals uint64 `yaml:"sanctionApprovals"`
+		// ParameterGovernors is the addresses changing the chain parameters, such as the productivity threshold and the
+		// probation intensity of the slasher, without a hard fork. The parameters are fixed if it is empty
+		ParameterGovernors []string `yaml:"parameterGovernors"`
+		// ParameterApprovals is the number of the governors approving a change of a parameter to apply it
+		ParameterApprovals uint64 `yaml:"parameterApprovals"`
 	}
 	// Account contains the configs for account protocol
 	Account struct {
 		// InitBalanceMap is the address and initial balance mapping before the first block.
 		InitBalanceMap map[string]string `yaml:"initBalances"`
+		// MinBalanceReservationStr is the balance in decimal string format that a transfer must leave to the sender
+		// for the future gas since Kamchatka height, empty means no reservation
+		MinBalanceReservationStr string `yaml:"minBalanceReservation"`
 	}
 	// Poll contains the configs for poll protocol
 	Poll struct {
@@ -212,8 +253,38 @@ type (
 		ProbationEpochPeriod uint64 `yaml:"probationEpochPeriod"`
 		// ProbationIntensityRate is a intensity rate of probation range from [0, 100], where 100 is hard-probation
 		ProbationIntensityRate uint32 `yaml:"probationIntensityRate"`
+		// ProbationIntensitySchedule is the intensity rates applied to a delegate on probation for 1, 2, ... epochs in
+		// the probation period, the last rate applies to the longer probation. It decays or escalates the intensity so
+		// that the repeat offenders are punished more than the first-time ones. The flat ProbationIntensityRate applies
+		// if empty
+		ProbationIntensitySchedule []uint32 `yaml:"probationIntensitySchedule"`
+		// ProbationIntensityScheduleBlockHeight is the height since which the ProbationIntensitySchedule applies to the
+		// epochs starting at or after it. The schedule is disabled if 0
+		ProbationIntensityScheduleBlockHeight uint64 `yaml:"probationIntensityScheduleHeight"`
 		// UnproductiveDelegateMaxCacheSize is a max cache size of upd which is stored into state DB (probationEpochPeriod <= UnproductiveDelegateMaxCacheSize)
 		UnproductiveDelegateMaxCacheSize uint64 `yaml:unproductiveDelegateMaxCacheSize`
+		// ProbationGracePeriod is the number of epochs since a delegate first appears in the active block producers,
+		// during which it is not counted as unproductive since kamchatka height. The grace period is disabled if 0
+		ProbationGracePeriod uint64 `yaml:"probationGracePeriod"`
+		// EquivocationJailPeriod is the number of epochs since the double signing, in which a delegate proven by the
+		// submitted evidence to have signed two different blocks of the same height and round is jailed with zero
+		// voting power since kamchatka height. The evidence is not accepted if 0
+		EquivocationJailPeriod uint64 `yaml:"equivocationJailPeriod"`
+		// ProbationEventBlockHeight is the height since which an event of each delegate on the probation list of the
+		// next epoch is emitted in the last block of an epoch, for the indexers to subscribe. The events are disabled
+		// if 0
+		ProbationEventBlockHeight uint64 `yaml:"probationEventHeight"`
+		// StakeSlashRate is the rate in basis points of the self-stake slashed from a delegate since kamchatka height,
+		// in addition to the reduced voting power. A delegate is slashed once when it enters the probation list of the
+		// next epoch, not again while it stays on the list, and each time it is jailed for double signing. The
+		// self-stake is not slashed if 0
+		StakeSlashRate uint64 `yaml:"stakeSlashRate"`
+		// StakeSlashToRewardingPool deposits the self-stake slashed into the rewarding pool. The self-stake slashed is
+		// burned if false
+		StakeSlashToRewardingPool bool `yaml:"stakeSlashToRewardingPool"`
+		// MinActiveDelegates is the floor of the number of active block producers when it is resized according to the
+		// number of qualified candidates since jutland height
+		MinActiveDelegates uint64 `yaml:"minActiveDelegates"`
 	}
 	// Delegate defines a delegate with address and votes
 	Delegate struct {
@@ -260,6 +331,11 @@ type (
 		WithdrawWaitingPeriod time.Duration        `yaml:"withdrawWaitingPeriod"`
 		MinStakeAmount        string               `yaml:"minStakeAmount"`
 		BootstrapCandidates   []BootstrapCandidate `yaml:"bootstrapCandidates"`
+		// VoteWeightCurve is the initial curve to calculate vote weight since Kamchatka height
+		VoteWeightCurve VoteWeightCurve `yaml:"voteWeightCurve"`
+		// VoteWeightGovernor is the address allowed to change the vote weight curve, empty means the curve is not
+		// governable
+		VoteWeightGovernor string `yaml:"voteWeightGovernor"`
 	}
 
 	// VoteWeightCalConsts contains the configs for calculating vote weight
@@ -269,6 +345,24 @@ type (
 		SelfStake  float64 `yaml:"selfStake"`
 	}
 
+	// VoteWeightCurve contains the configs for calculating vote weight with integer arithmetic, all the bonuses and
+	// decays are in basis points
+	VoteWeightCurve struct {
+		// DurationBonus is the bonus for each doubling of the staked days
+		DurationBonus uint64 `yaml:"durationBonus"`
+		// AutoStakeBonus is the bonus of a bucket with auto-stake on
+		AutoStakeBonus uint64 `yaml:"autoStakeBonus"`
+		// SelfStakeBonus is the extra bonus applied to the weight of a self-stake bucket with auto-stake on for at
+		// least 91 days
+		SelfStakeBonus uint64 `yaml:"selfStakeBonus"`
+		// StalenessPeriod is the period for which the weight of a matured bucket without auto-stake decays once
+		StalenessPeriod time.Duration `yaml:"stalenessPeriod"`
+		// StalenessDecay is the decay for each staleness period passed
+		StalenessDecay uint64 `yaml:"stalenessDecay"`
+		// MaxStalenessDecay is the cap of the total staleness decay
+		MaxStalenessDecay uint64 `yaml:"maxStalenessDecay"`
+	}
+
 	// RegistrationConsts contains the configs for candidate registration
 	RegistrationConsts struct {
 		Fee          string `yaml:"fee"`
@@ -434,6 +528,18 @@ func (d *Delegate) Votes() *big.Int {
 	return val
 }
 
+// MinBalanceReservation returns the balance that a transfer must leave to the sender, 0 if there is no reservation
+func (a *Account) MinBalanceReservation() *big.Int {
+	if a.MinBalanceReservationStr == "" {
+		return big.NewInt(0)
+	}
+	val, ok := big.NewInt(0).SetString(a.MinBalanceReservationStr, 10)
+	if !ok {
+		log.S().Panicf("Error when casting min balance reservation string %s into big int", a.MinBalanceReservationStr)
+	}
+	return val
+}
+
 // InitBalance returns the init balance of the rewarding fund
 func (r *Rewarding) InitBalance() *big.Int {
 	val, ok := big.NewInt(0).SetString(r.InitBalanceStr, 10)
@@ -40,7 +40,7 @@ func TestAccount_InitBalances(t *testing.T) {
 	InitBalanceMap := make(map[string]string, 0)
 	InitBalanceMap["io1emxf8zzqckhgjde6dqd97ts0y3q496gm3fdrl6"] = "1"
 	InitBalanceMap["io1mflp9m6hcgm2qcghchsdqj3z3eccrnekx9p0ms"] = "2"
-	acc := Account{InitBalanceMap}
+	acc := Account{InitBalanceMap: InitBalanceMap}
 	adds, balances := acc.InitBalances()
 	require.Equal("io1emxf8zzqckhgjde6dqd97ts0y3q496gm3fdrl6", adds[0].String())
 	require.Equal("io1mflp9m6hcgm2qcghchsdqj3z3eccrnekx9p0ms", adds[1].String())
@@ -0,0 +1,106 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package blockchain
+
+import (
+	"sync"
+
+	"github.com/iotexproject/go-pkgs/cache"
+	"github.com/iotexproject/go-pkgs/hash"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/iotexproject/iotex-core/blockchain/block"
+)
+
+var validatedBlockMtc = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "iotex_validated_block_cache",
+		Help: "Number of block validations served by the validated block cache, shared with a concurrent validation, or run",
+	},
+	[]string{"result"},
+)
+
+func init() {
+	prometheus.MustRegister(validatedBlockMtc)
+}
+
+type (
+	// validatedBlocks is the cache of the blocks validated recently, shared by consensus and blocksync, so that a block
+	// received from both of them is validated once. Concurrent validations of the same block wait for the first one
+	validatedBlocks struct {
+		blocks *cache.ThreadSafeLruCache
+		mu     sync.Mutex
+		calls  map[hash.Hash256]*validateCall
+	}
+
+	// validateCall is an in-flight validation of a block
+	validateCall struct {
+		wg  sync.WaitGroup
+		blk *block.Block
+		err error
+	}
+)
+
+func newValidatedBlocks(size int) *validatedBlocks {
+	return &validatedBlocks{
+		blocks: cache.NewThreadSafeLruCache(size),
+		calls:  make(map[hash.Hash256]*validateCall),
+	}
+}
+
+// Validate returns nil if the block has been validated, otherwise validates it by the func, or waits for the validation
+// in flight. The receipts of the validated block are copied into the block. The block must be linked to the tip, so that
+// the cached result still holds
+func (vb *validatedBlocks) Validate(blk *block.Block, validate func(*block.Block) error) error {
+	h := blk.HashBlock()
+	if validated, ok := vb.get(h); ok {
+		validatedBlockMtc.WithLabelValues("hit").Inc()
+		blk.Receipts = validated.Receipts
+		return nil
+	}
+	vb.mu.Lock()
+	if call, ok := vb.calls[h]; ok {
+		vb.mu.Unlock()
+		call.wg.Wait()
+		validatedBlockMtc.WithLabelValues("shared").Inc()
+		if call.err != nil {
+			return call.err
+		}
+		blk.Receipts = call.blk.Receipts
+		return nil
+	}
+	if validated, ok := vb.get(h); ok {
+		// validated in between
+		vb.mu.Unlock()
+		validatedBlockMtc.WithLabelValues("hit").Inc()
+		blk.Receipts = validated.Receipts
+		return nil
+	}
+	call := &validateCall{blk: blk}
+	call.wg.Add(1)
+	vb.calls[h] = call
+	vb.mu.Unlock()
+
+	validatedBlockMtc.WithLabelValues("miss").Inc()
+	call.err = validate(blk)
+	if call.err == nil {
+		vb.blocks.Add(h, blk)
+	}
+	vb.mu.Lock()
+	delete(vb.calls, h)
+	vb.mu.Unlock()
+	call.wg.Done()
+	return call.err
+}
+
+func (vb *validatedBlocks) get(h hash.Hash256) (*block.Block, bool) {
+	v, ok := vb.blocks.Get(h)
+	if !ok {
+		return nil, false
+	}
+	return v.(*block.Block), true
+}
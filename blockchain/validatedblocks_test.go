@@ -0,0 +1,88 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package blockchain
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/action"
+	"github.com/iotexproject/iotex-core/blockchain/block"
+	"github.com/iotexproject/iotex-core/test/identityset"
+	"github.com/iotexproject/iotex-core/testutil"
+)
+
+func TestValidatedBlocks(t *testing.T) {
+	require := require.New(t)
+
+	newBlock := func(height uint64) *block.Block {
+		blk, err := block.NewTestingBuilder().
+			SetHeight(height).
+			SetTimeStamp(testutil.TimestampNow()).
+			SignAndBuild(identityset.PrivateKey(0))
+		require.NoError(err)
+		return &blk
+	}
+	receipts := []*action.Receipt{{Status: 1}}
+	var runs int32
+	validate := func(blk *block.Block) error {
+		atomic.AddInt32(&runs, 1)
+		if blk.Height() == 2 {
+			return ErrInvalidBlock
+		}
+		blk.Receipts = receipts
+		return nil
+	}
+
+	vb := newValidatedBlocks(2)
+	blk := newBlock(1)
+	require.NoError(vb.Validate(blk, validate))
+	require.EqualValues(1, runs)
+
+	// the same block received again is not validated, but gets the receipts
+	dup := *blk
+	dup.Receipts = nil
+	require.NoError(vb.Validate(&dup, validate))
+	require.EqualValues(1, runs)
+	require.Equal(receipts, dup.Receipts)
+
+	// invalid block is not cached
+	invalid := newBlock(2)
+	require.Equal(ErrInvalidBlock, errors.Cause(vb.Validate(invalid, validate)))
+	require.Equal(ErrInvalidBlock, errors.Cause(vb.Validate(invalid, validate)))
+	require.EqualValues(3, runs)
+
+	// concurrent validations of the same block run once
+	blk = newBlock(3)
+	release := make(chan struct{})
+	slow := func(blk *block.Block) error {
+		<-release
+		return validate(blk)
+	}
+	var wg sync.WaitGroup
+	errs := make([]error, 5)
+	copies := make([]block.Block, 5)
+	for i := range errs {
+		copies[i] = *blk
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = vb.Validate(&copies[i], slow)
+		}(i)
+	}
+	close(release)
+	wg.Wait()
+	require.EqualValues(4, runs)
+	for i := range errs {
+		require.NoError(errs[i])
+		require.Equal(receipts, copies[i].Receipts)
+	}
+}
@@ -0,0 +1,152 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package blockindex
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/iotexproject/iotex-core/blockchain/block"
+	"github.com/iotexproject/iotex-core/blockchain/blockdao"
+	"github.com/iotexproject/iotex-core/db"
+	"github.com/iotexproject/iotex-core/pkg/util/byteutil"
+	"github.com/iotexproject/iotex-core/state/factory"
+)
+
+const (
+	// BlockStatsNamespace indicates the kvstore namespace to store block execution stats
+	BlockStatsNamespace = "BlockStats"
+)
+
+type (
+	// BlockStats is the execution stats of a block
+	BlockStats struct {
+		Height        uint64            `json:"height"`
+		GasConsumed   uint64            `json:"gasConsumed"`
+		ExecutionTime time.Duration     `json:"executionTime"`
+		ActionTypes   map[string]uint64 `json:"actionTypes"`
+		StateReads    uint64            `json:"stateReads"`
+		StateWrites   uint64            `json:"stateWrites"`
+	}
+
+	// ExecutionStatsReader reads the stats collected by state factory when running a block
+	ExecutionStatsReader interface {
+		ExecutionStats(uint64) (factory.ExecutionStats, bool)
+	}
+
+	// BlockStatsIndexer is the interface for block stats indexer
+	BlockStatsIndexer interface {
+		blockdao.BlockIndexer
+		// BlockStats returns the execution stats of the block at height
+		BlockStats(uint64) (*BlockStats, error)
+	}
+
+	// blockStatsIndexer stores the execution stats of each block in an auxiliary table
+	blockStatsIndexer struct {
+		kvStore db.KVStore
+		reader  ExecutionStatsReader
+	}
+)
+
+// NewBlockStatsIndexer creates a new block stats indexer
+func NewBlockStatsIndexer(kv db.KVStore, reader ExecutionStatsReader) (BlockStatsIndexer, error) {
+	if kv == nil {
+		return nil, errors.New("empty kvStore")
+	}
+	return &blockStatsIndexer{
+		kvStore: kv,
+		reader:  reader,
+	}, nil
+}
+
+// Start starts the block stats indexer
+func (bsx *blockStatsIndexer) Start(ctx context.Context) error {
+	return bsx.kvStore.Start(ctx)
+}
+
+// Stop stops the block stats indexer
+func (bsx *blockStatsIndexer) Stop(ctx context.Context) error {
+	return bsx.kvStore.Stop(ctx)
+}
+
+// Height returns the tip height of the block stats indexer
+func (bsx *blockStatsIndexer) Height() (uint64, error) {
+	h, err := bsx.kvStore.Get(BlockStatsNamespace, []byte(CurrentHeightKey))
+	switch errors.Cause(err) {
+	case nil:
+		return byteutil.BytesToUint64BigEndian(h), nil
+	case db.ErrNotExist:
+		return 0, nil
+	default:
+		return 0, err
+	}
+}
+
+// PutBlock stores the execution stats of the block
+func (bsx *blockStatsIndexer) PutBlock(_ context.Context, blk *block.Block) error {
+	stats := &BlockStats{
+		Height:      blk.Height(),
+		ActionTypes: make(map[string]uint64),
+	}
+	for _, receipt := range blk.Receipts {
+		stats.GasConsumed += receipt.GasConsumed
+	}
+	for _, selp := range blk.Actions {
+		stats.ActionTypes[actionTypeName(selp.Action())]++
+	}
+	if bsx.reader != nil {
+		if es, ok := bsx.reader.ExecutionStats(blk.Height()); ok {
+			stats.ExecutionTime = es.ExecutionTime
+			stats.StateReads = es.StateReads
+			stats.StateWrites = es.StateWrites
+		}
+	}
+	data, err := json.Marshal(stats)
+	if err != nil {
+		return err
+	}
+	if err := bsx.kvStore.Put(BlockStatsNamespace, byteutil.Uint64ToBytesBigEndian(blk.Height()), data); err != nil {
+		return errors.Wrapf(err, "failed to put stats of block %d", blk.Height())
+	}
+	return bsx.kvStore.Put(BlockStatsNamespace, []byte(CurrentHeightKey), byteutil.Uint64ToBytesBigEndian(blk.Height()))
+}
+
+// DeleteTipBlock deletes the stats of the tip block
+func (bsx *blockStatsIndexer) DeleteTipBlock(blk *block.Block) error {
+	if err := bsx.kvStore.Delete(BlockStatsNamespace, byteutil.Uint64ToBytesBigEndian(blk.Height())); err != nil {
+		return err
+	}
+	return bsx.kvStore.Put(BlockStatsNamespace, []byte(CurrentHeightKey), byteutil.Uint64ToBytesBigEndian(blk.Height()-1))
+}
+
+// BlockStats returns the execution stats of the block at height
+func (bsx *blockStatsIndexer) BlockStats(height uint64) (*BlockStats, error) {
+	data, err := bsx.kvStore.Get(BlockStatsNamespace, byteutil.Uint64ToBytesBigEndian(height))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get stats of block %d", height)
+	}
+	stats := &BlockStats{}
+	if err := json.Unmarshal(data, stats); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+func actionTypeName(act interface{}) string {
+	t := reflect.TypeOf(act)
+	if t == nil {
+		return ""
+	}
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Name()
+}
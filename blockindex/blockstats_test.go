@@ -0,0 +1,70 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package blockindex
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/db"
+	"github.com/iotexproject/iotex-core/state/factory"
+)
+
+type testStatsReader map[uint64]factory.ExecutionStats
+
+func (r testStatsReader) ExecutionStats(height uint64) (factory.ExecutionStats, bool) {
+	stats, ok := r[height]
+	return stats, ok
+}
+
+func TestBlockStatsIndexer(t *testing.T) {
+	require := require.New(t)
+
+	blks := getTestLogBlocks(t)
+	blks[0].Receipts[0].GasConsumed = 10000
+	reader := testStatsReader{
+		1: {ExecutionTime: time.Millisecond, StateReads: 12, StateWrites: 5},
+	}
+	indexer, err := NewBlockStatsIndexer(db.NewMemKVStore(), reader)
+	require.NoError(err)
+	ctx := context.Background()
+	require.NoError(indexer.Start(ctx))
+	defer func() {
+		require.NoError(indexer.Stop(ctx))
+	}()
+
+	for _, blk := range blks[:2] {
+		require.NoError(indexer.PutBlock(ctx, blk))
+	}
+	height, err := indexer.Height()
+	require.NoError(err)
+	require.Equal(uint64(2), height)
+
+	stats, err := indexer.BlockStats(1)
+	require.NoError(err)
+	require.Equal(uint64(10000), stats.GasConsumed)
+	require.Equal(time.Millisecond, stats.ExecutionTime)
+	require.Equal(uint64(12), stats.StateReads)
+	require.Equal(uint64(5), stats.StateWrites)
+	require.Equal(map[string]uint64{"Transfer": 2, "Execution": 1}, stats.ActionTypes)
+
+	// stats not available from state factory
+	stats, err = indexer.BlockStats(2)
+	require.NoError(err)
+	require.Zero(stats.StateReads)
+	require.Equal(map[string]uint64{"Transfer": 1, "Execution": 1}, stats.ActionTypes)
+
+	require.NoError(indexer.DeleteTipBlock(blks[1]))
+	_, err = indexer.BlockStats(2)
+	require.Error(err)
+	height, err = indexer.Height()
+	require.NoError(err)
+	require.Equal(uint64(1), height)
+}
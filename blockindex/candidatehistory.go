@@ -0,0 +1,278 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package blockindex
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+
+	"github.com/iotexproject/go-pkgs/hash"
+	"github.com/iotexproject/iotex-proto/golang/iotextypes"
+	"github.com/pkg/errors"
+
+	"github.com/iotexproject/iotex-address/address"
+	"github.com/iotexproject/iotex-core/action"
+	"github.com/iotexproject/iotex-core/blockchain/block"
+	"github.com/iotexproject/iotex-core/blockchain/blockdao"
+	"github.com/iotexproject/iotex-core/db"
+	"github.com/iotexproject/iotex-core/db/batch"
+	"github.com/iotexproject/iotex-core/pkg/util/byteutil"
+)
+
+const (
+	// CandidateHistoryNamespace indicates the kvstore namespace to store the history of each candidate by owner
+	CandidateHistoryNamespace = "CandidateHistory"
+	// CandidateHistoryHeightNamespace indicates the kvstore namespace to store the owners of the candidates changed
+	// in each block, and the tip height of the index
+	CandidateHistoryHeightNamespace = "CandidateHistoryHeight"
+	// CandidateNameNamespace indicates the kvstore namespace to store the owner of each candidate name
+	CandidateNameNamespace = "CandidateName"
+
+	// CandidateRegisterEvent is the type of the event of a candidate register action
+	CandidateRegisterEvent = "register"
+	// CandidateUpdateEvent is the type of the event of a candidate update action
+	CandidateUpdateEvent = "update"
+)
+
+type (
+	// CandidateEvent is a successful candidate register or update action
+	CandidateEvent struct {
+		Type       string `json:"type"`
+		Height     uint64 `json:"height"`
+		ActionHash string `json:"actionHash"`
+		// Name, Operator and Reward are the candidate info after the action
+		Name     string `json:"name"`
+		Operator string `json:"operator"`
+		Reward   string `json:"reward"`
+		// Changes are the fields changed by the action, among "name", "operator" and "reward"
+		Changes []string `json:"changes"`
+	}
+
+	// CandidateHistoryIndexer is the interface for candidate history indexer
+	CandidateHistoryIndexer interface {
+		blockdao.BlockIndexer
+		// CandidateHistory returns the events of the candidate owned by the address, in the order of height
+		CandidateHistory(address.Address) ([]*CandidateEvent, error)
+		// CandidateHistoryByName returns the owner and the events of the candidate which has ever used the name
+		CandidateHistoryByName(string) (address.Address, []*CandidateEvent, error)
+	}
+
+	// candidateHistoryIndexer stores the register and update actions of each candidate in an auxiliary table
+	candidateHistoryIndexer struct {
+		kvStore db.KVStore
+	}
+)
+
+// NewCandidateHistoryIndexer creates a new candidate history indexer
+func NewCandidateHistoryIndexer(kv db.KVStore) (CandidateHistoryIndexer, error) {
+	if kv == nil {
+		return nil, errors.New("empty kvStore")
+	}
+	return &candidateHistoryIndexer{
+		kvStore: kv,
+	}, nil
+}
+
+// Start starts the candidate history indexer
+func (chx *candidateHistoryIndexer) Start(ctx context.Context) error {
+	return chx.kvStore.Start(ctx)
+}
+
+// Stop stops the candidate history indexer
+func (chx *candidateHistoryIndexer) Stop(ctx context.Context) error {
+	return chx.kvStore.Stop(ctx)
+}
+
+// Height returns the tip height of the candidate history indexer
+func (chx *candidateHistoryIndexer) Height() (uint64, error) {
+	h, err := chx.kvStore.Get(CandidateHistoryHeightNamespace, []byte(CurrentHeightKey))
+	switch errors.Cause(err) {
+	case nil:
+		return byteutil.BytesToUint64BigEndian(h), nil
+	case db.ErrNotExist:
+		return 0, nil
+	default:
+		return 0, err
+	}
+}
+
+// PutBlock appends the successful candidate register and update actions of the block to the history
+func (chx *candidateHistoryIndexer) PutBlock(_ context.Context, blk *block.Block) error {
+	succeeded := make(map[hash.Hash256]bool, len(blk.Receipts))
+	for _, receipt := range blk.Receipts {
+		succeeded[receipt.ActionHash] = receipt.Status == uint64(iotextypes.ReceiptStatus_Success)
+	}
+	var (
+		b         = batch.NewBatch()
+		histories = make(map[string][]*CandidateEvent)
+		owners    []string
+	)
+	for _, selp := range blk.Actions {
+		h, err := selp.Hash()
+		if err != nil {
+			return err
+		}
+		if !succeeded[h] {
+			continue
+		}
+		var (
+			owner address.Address
+			evt   = &CandidateEvent{Height: blk.Height(), ActionHash: hex.EncodeToString(h[:])}
+			name  string
+			op    address.Address
+			rew   address.Address
+		)
+		switch act := selp.Action().(type) {
+		case *action.CandidateRegister:
+			evt.Type = CandidateRegisterEvent
+			owner = act.OwnerAddress()
+			name, op, rew = act.Name(), act.OperatorAddress(), act.RewardAddress()
+		case *action.CandidateUpdate:
+			evt.Type = CandidateUpdateEvent
+			name, op, rew = act.Name(), act.OperatorAddress(), act.RewardAddress()
+		default:
+			continue
+		}
+		if owner == nil {
+			// the caller owns the candidate if the register action doesn't specify the owner
+			if owner, err = address.FromBytes(selp.SrcPubkey().Hash()); err != nil {
+				return err
+			}
+		}
+		history, ok := histories[owner.String()]
+		if !ok {
+			if history, err = chx.CandidateHistory(owner); err != nil && errors.Cause(err) != db.ErrNotExist {
+				return err
+			}
+			owners = append(owners, owner.String())
+		}
+		if len(history) > 0 {
+			last := history[len(history)-1]
+			evt.Name, evt.Operator, evt.Reward = last.Name, last.Operator, last.Reward
+		}
+		// an update leaves the fields empty in the action unchanged
+		if name != "" && name != evt.Name {
+			evt.Name = name
+			evt.Changes = append(evt.Changes, "name")
+			b.Put(CandidateNameNamespace, []byte(name), owner.Bytes(), "failed to put owner of candidate name")
+		}
+		if op != nil && op.String() != evt.Operator {
+			evt.Operator = op.String()
+			evt.Changes = append(evt.Changes, "operator")
+		}
+		if rew != nil && rew.String() != evt.Reward {
+			evt.Reward = rew.String()
+			evt.Changes = append(evt.Changes, "reward")
+		}
+		histories[owner.String()] = append(history, evt)
+	}
+	for _, owner := range owners {
+		addr, err := address.FromString(owner)
+		if err != nil {
+			return err
+		}
+		data, err := json.Marshal(histories[owner])
+		if err != nil {
+			return err
+		}
+		b.Put(CandidateHistoryNamespace, addr.Bytes(), data, "failed to put candidate history")
+	}
+	height := byteutil.Uint64ToBytesBigEndian(blk.Height())
+	if len(owners) > 0 {
+		data, err := json.Marshal(owners)
+		if err != nil {
+			return err
+		}
+		b.Put(CandidateHistoryHeightNamespace, height, data, "failed to put candidates of block")
+	}
+	b.Put(CandidateHistoryHeightNamespace, []byte(CurrentHeightKey), height, "failed to put tip height")
+	return chx.kvStore.WriteBatch(b)
+}
+
+// DeleteTipBlock removes the events of the tip block from the history
+func (chx *candidateHistoryIndexer) DeleteTipBlock(blk *block.Block) error {
+	height := byteutil.Uint64ToBytesBigEndian(blk.Height())
+	b := batch.NewBatch()
+	data, err := chx.kvStore.Get(CandidateHistoryHeightNamespace, height)
+	switch errors.Cause(err) {
+	case nil:
+		var owners []string
+		if err := json.Unmarshal(data, &owners); err != nil {
+			return err
+		}
+		for _, owner := range owners {
+			addr, err := address.FromString(owner)
+			if err != nil {
+				return err
+			}
+			history, err := chx.CandidateHistory(addr)
+			if err != nil {
+				return err
+			}
+			for len(history) > 0 && history[len(history)-1].Height == blk.Height() {
+				history = history[:len(history)-1]
+			}
+			if len(history) == 0 {
+				b.Delete(CandidateHistoryNamespace, addr.Bytes(), "failed to delete candidate history")
+				continue
+			}
+			data, err := json.Marshal(history)
+			if err != nil {
+				return err
+			}
+			b.Put(CandidateHistoryNamespace, addr.Bytes(), data, "failed to put candidate history")
+		}
+		b.Delete(CandidateHistoryHeightNamespace, height, "failed to delete candidates of block")
+	case db.ErrNotExist:
+	default:
+		return err
+	}
+	b.Put(
+		CandidateHistoryHeightNamespace,
+		[]byte(CurrentHeightKey),
+		byteutil.Uint64ToBytesBigEndian(blk.Height()-1),
+		"failed to put tip height",
+	)
+	return chx.kvStore.WriteBatch(b)
+}
+
+// CandidateHistory returns the events of the candidate owned by the address, in the order of height
+func (chx *candidateHistoryIndexer) CandidateHistory(owner address.Address) ([]*CandidateEvent, error) {
+	data, err := chx.kvStore.Get(CandidateHistoryNamespace, owner.Bytes())
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get history of candidate %s", owner)
+	}
+	var history []*CandidateEvent
+	if err := json.Unmarshal(data, &history); err != nil {
+		return nil, err
+	}
+	return history, nil
+}
+
+// CandidateHistoryByName returns the owner and the events of the candidate which has ever used the name
+func (chx *candidateHistoryIndexer) CandidateHistoryByName(name string) (address.Address, []*CandidateEvent, error) {
+	data, err := chx.kvStore.Get(CandidateNameNamespace, []byte(name))
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to get owner of candidate %s", name)
+	}
+	owner, err := address.FromBytes(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	history, err := chx.CandidateHistory(owner)
+	if err != nil {
+		return nil, nil, err
+	}
+	// the name index is not rolled back with the tip block, make sure the candidate did use the name
+	for _, evt := range history {
+		if evt.Name == name {
+			return owner, history, nil
+		}
+	}
+	return nil, nil, errors.Wrapf(db.ErrNotExist, "candidate %s", name)
+}
@@ -0,0 +1,129 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package blockindex
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/iotexproject/iotex-proto/golang/iotextypes"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/action"
+	"github.com/iotexproject/iotex-core/blockchain/block"
+	"github.com/iotexproject/iotex-core/db"
+	"github.com/iotexproject/iotex-core/test/identityset"
+	"github.com/iotexproject/iotex-core/testutil"
+)
+
+func TestCandidateHistoryIndexer(t *testing.T) {
+	require := require.New(t)
+
+	sign := func(act action.Action, nonce uint64, senderID int) action.SealedEnvelope {
+		elp := (&action.EnvelopeBuilder{}).SetNonce(nonce).SetGasLimit(10000).SetGasPrice(big.NewInt(0)).
+			SetAction(act).Build()
+		selp, err := action.Sign(elp, identityset.PrivateKey(senderID))
+		require.NoError(err)
+		return selp
+	}
+	newBlock := func(height uint64, statuses []iotextypes.ReceiptStatus, selps ...action.SealedEnvelope) *block.Block {
+		blk, err := block.NewTestingBuilder().
+			SetHeight(height).
+			SetTimeStamp(testutil.TimestampNow()).
+			AddActions(selps...).
+			SignAndBuild(identityset.PrivateKey(0))
+		require.NoError(err)
+		for i, selp := range selps {
+			h, err := selp.Hash()
+			require.NoError(err)
+			blk.Receipts = append(blk.Receipts, &action.Receipt{Status: uint64(statuses[i]), ActionHash: h})
+		}
+		return &blk
+	}
+	owner := identityset.Address(1)
+	register, err := action.NewCandidateRegister(1, "alice", identityset.Address(2).String(),
+		identityset.Address(3).String(), "", "100", 0, false, nil, 10000, big.NewInt(0))
+	require.NoError(err)
+	failed, err := action.NewCandidateUpdate(2, "bob", "", "", 10000, big.NewInt(0))
+	require.NoError(err)
+	rename, err := action.NewCandidateUpdate(3, "carol", "", "", 10000, big.NewInt(0))
+	require.NoError(err)
+	tsf, err := action.NewTransfer(4, big.NewInt(1), identityset.Address(5).String(), nil, 10000, big.NewInt(0))
+	require.NoError(err)
+	changeOperator, err := action.NewCandidateUpdate(5, "", identityset.Address(4).String(), "", 10000, big.NewInt(0))
+	require.NoError(err)
+	success, failure := iotextypes.ReceiptStatus_Success, iotextypes.ReceiptStatus_Failure
+	blks := []*block.Block{
+		newBlock(1, nil),
+		newBlock(2, []iotextypes.ReceiptStatus{success, failure}, sign(register, 1, 1), sign(failed, 2, 1)),
+		newBlock(3, []iotextypes.ReceiptStatus{success, success}, sign(rename, 3, 1), sign(tsf, 4, 1)),
+		newBlock(4, []iotextypes.ReceiptStatus{success}, sign(changeOperator, 5, 1)),
+	}
+
+	indexer, err := NewCandidateHistoryIndexer(db.NewMemKVStore())
+	require.NoError(err)
+	ctx := context.Background()
+	require.NoError(indexer.Start(ctx))
+	defer func() {
+		require.NoError(indexer.Stop(ctx))
+	}()
+	for _, blk := range blks {
+		require.NoError(indexer.PutBlock(ctx, blk))
+	}
+	height, err := indexer.Height()
+	require.NoError(err)
+	require.Equal(uint64(4), height)
+
+	history, err := indexer.CandidateHistory(owner)
+	require.NoError(err)
+	require.Equal(3, len(history))
+	require.Equal(CandidateRegisterEvent, history[0].Type)
+	require.Equal(uint64(2), history[0].Height)
+	require.Equal("alice", history[0].Name)
+	require.Equal(identityset.Address(2).String(), history[0].Operator)
+	require.Equal(identityset.Address(3).String(), history[0].Reward)
+	require.Equal([]string{"name", "operator", "reward"}, history[0].Changes)
+	require.Equal(CandidateUpdateEvent, history[1].Type)
+	require.Equal(uint64(3), history[1].Height)
+	require.Equal("carol", history[1].Name)
+	require.Equal(identityset.Address(2).String(), history[1].Operator)
+	require.Equal([]string{"name"}, history[1].Changes)
+	require.Equal("carol", history[2].Name)
+	require.Equal(identityset.Address(4).String(), history[2].Operator)
+	require.Equal(identityset.Address(3).String(), history[2].Reward)
+	require.Equal([]string{"operator"}, history[2].Changes)
+
+	// queried by current or previous name, but not the name of the failed update
+	for _, name := range []string{"alice", "carol"} {
+		addr, byName, err := indexer.CandidateHistoryByName(name)
+		require.NoError(err)
+		require.Equal(owner.String(), addr.String())
+		require.Equal(history, byName)
+	}
+	_, _, err = indexer.CandidateHistoryByName("bob")
+	require.Equal(db.ErrNotExist, errors.Cause(err))
+	_, err = indexer.CandidateHistory(identityset.Address(5))
+	require.Equal(db.ErrNotExist, errors.Cause(err))
+
+	require.NoError(indexer.DeleteTipBlock(blks[3]))
+	require.NoError(indexer.DeleteTipBlock(blks[2]))
+	height, err = indexer.Height()
+	require.NoError(err)
+	require.Equal(uint64(2), height)
+	history, err = indexer.CandidateHistory(owner)
+	require.NoError(err)
+	require.Equal(1, len(history))
+	require.Equal("alice", history[0].Name)
+	_, _, err = indexer.CandidateHistoryByName("carol")
+	require.Equal(db.ErrNotExist, errors.Cause(err))
+
+	require.NoError(indexer.DeleteTipBlock(blks[1]))
+	_, err = indexer.CandidateHistory(owner)
+	require.Equal(db.ErrNotExist, errors.Cause(err))
+}
@@ -0,0 +1,152 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package sqlindexer
+
+import (
+	"database/sql"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/iotexproject/iotex-core/pkg/log"
+)
+
+// _migrations are the schema migrations, the i-th migration upgrades the schema to version i+1. Applied migrations
+// must never be modified, add a new one to change the schema
+var _migrations = [][]string{
+	{
+		`CREATE TABLE blocks (
+			height BIGINT PRIMARY KEY,
+			hash VARCHAR(64) NOT NULL,
+			producer VARCHAR(64) NOT NULL,
+			block_time BIGINT NOT NULL,
+			num_actions INTEGER NOT NULL,
+			gas_consumed BIGINT NOT NULL
+		)`,
+		`CREATE TABLE actions (
+			action_hash VARCHAR(64) PRIMARY KEY,
+			block_height BIGINT NOT NULL,
+			action_index INTEGER NOT NULL,
+			sender VARCHAR(64) NOT NULL,
+			action_type VARCHAR(64) NOT NULL,
+			nonce BIGINT NOT NULL,
+			gas_limit BIGINT NOT NULL,
+			gas_price VARCHAR(80) NOT NULL,
+			recipient VARCHAR(64) NOT NULL,
+			amount VARCHAR(80) NOT NULL
+		)`,
+		`CREATE INDEX actions_block_height ON actions (block_height)`,
+		`CREATE INDEX actions_sender ON actions (sender)`,
+		`CREATE INDEX actions_recipient ON actions (recipient)`,
+		`CREATE TABLE receipts (
+			action_hash VARCHAR(64) PRIMARY KEY,
+			block_height BIGINT NOT NULL,
+			status BIGINT NOT NULL,
+			gas_consumed BIGINT NOT NULL,
+			contract_address VARCHAR(64) NOT NULL
+		)`,
+		`CREATE TABLE logs (
+			block_height BIGINT NOT NULL,
+			log_index INTEGER NOT NULL,
+			action_hash VARCHAR(64) NOT NULL,
+			address VARCHAR(64) NOT NULL,
+			topics TEXT NOT NULL,
+			data TEXT NOT NULL,
+			PRIMARY KEY (block_height, log_index)
+		)`,
+		`CREATE INDEX logs_address ON logs (address)`,
+		`CREATE TABLE staking_events (
+			action_hash VARCHAR(64) PRIMARY KEY,
+			block_height BIGINT NOT NULL,
+			event_type VARCHAR(64) NOT NULL,
+			status BIGINT NOT NULL,
+			candidate VARCHAR(64) NOT NULL,
+			bucket_index BIGINT,
+			amount VARCHAR(80) NOT NULL,
+			voter VARCHAR(64) NOT NULL
+		)`,
+		`CREATE INDEX staking_events_candidate ON staking_events (candidate)`,
+		`CREATE TABLE sync_state (
+			id INTEGER PRIMARY KEY,
+			height BIGINT NOT NULL
+		)`,
+		`INSERT INTO sync_state (id, height) VALUES (1, 0)`,
+	},
+}
+
+// dialect adapts the queries to the sql driver
+type dialect struct {
+	numberedPlaceholder bool
+}
+
+func newDialect(driver string) (dialect, error) {
+	switch driver {
+	case "postgres":
+		return dialect{numberedPlaceholder: true}, nil
+	case "sqlite3":
+		return dialect{}, nil
+	default:
+		return dialect{}, errors.Errorf("unsupported sql driver %s", driver)
+	}
+}
+
+// rebind converts the ? placeholders in query to the format of the driver
+func (d dialect) rebind(query string) string {
+	if !d.numberedPlaceholder {
+		return query
+	}
+	var (
+		sb strings.Builder
+		n  int
+	)
+	for _, c := range query {
+		if c == '?' {
+			n++
+			sb.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		sb.WriteRune(c)
+	}
+	return sb.String()
+}
+
+// migrate upgrades the schema to the latest version
+func migrate(db *sql.DB, d dialect) error {
+	if _, err := db.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (version INTEGER PRIMARY KEY)`); err != nil {
+		return errors.Wrap(err, "failed to create schema_migrations table")
+	}
+	var version int
+	if err := db.QueryRow(`SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&version); err != nil {
+		return errors.Wrap(err, "failed to read schema version")
+	}
+	if version > len(_migrations) {
+		return errors.Errorf("schema version %d is newer than supported version %d", version, len(_migrations))
+	}
+	for ; version < len(_migrations); version++ {
+		tx, err := db.Begin()
+		if err != nil {
+			return err
+		}
+		for _, stmt := range _migrations[version] {
+			if _, err := tx.Exec(stmt); err != nil {
+				_ = tx.Rollback()
+				return errors.Wrapf(err, "failed to migrate schema to version %d", version+1)
+			}
+		}
+		if _, err := tx.Exec(d.rebind(`INSERT INTO schema_migrations (version) VALUES (?)`), version+1); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+		log.L().Info("Migrated sql indexer schema.", zap.Int("version", version+1))
+	}
+	return nil
+}
@@ -0,0 +1,332 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package sqlindexer
+
+import (
+	"context"
+	"database/sql"
+	"encoding/hex"
+	"math/big"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/iotexproject/go-pkgs/hash"
+	"github.com/iotexproject/iotex-address/address"
+
+	"github.com/iotexproject/iotex-core/action"
+	"github.com/iotexproject/iotex-core/blockchain/block"
+	"github.com/iotexproject/iotex-core/config"
+	dbsql "github.com/iotexproject/iotex-core/db/sql"
+	"github.com/iotexproject/iotex-core/pkg/log"
+)
+
+type (
+	// BlockReader reads the committed blocks and receipts
+	BlockReader interface {
+		Height() (uint64, error)
+		GetBlockByHeight(uint64) (*block.Block, error)
+		GetReceipts(uint64) ([]*action.Receipt, error)
+	}
+
+	// Indexer mirrors blocks, actions, receipts, logs and staking events into a sql database. It follows the chain in
+	// background, backfilling from the height stored in the database, which is updated in the same transaction as
+	// the block data
+	Indexer struct {
+		cfg     config.SQLIndexer
+		dao     BlockReader
+		store   dbsql.Store
+		dialect dialect
+		notify  chan struct{}
+		done    chan struct{}
+		wg      sync.WaitGroup
+	}
+)
+
+// NewIndexer creates a new sql indexer
+func NewIndexer(cfg config.SQLIndexer, dao BlockReader) (*Indexer, error) {
+	d, err := newDialect(cfg.Driver)
+	if err != nil {
+		return nil, err
+	}
+	var store dbsql.Store
+	switch cfg.Driver {
+	case "postgres":
+		store = dbsql.NewPostgres(dbsql.Postgres{ConnectionString: cfg.ConnectionString})
+	case "sqlite3":
+		store = dbsql.NewSQLite3(dbsql.CQLITE3{SQLite3File: cfg.ConnectionString})
+	}
+	return &Indexer{
+		cfg:     cfg,
+		dao:     dao,
+		store:   store,
+		dialect: d,
+		notify:  make(chan struct{}, 1),
+		done:    make(chan struct{}),
+	}, nil
+}
+
+// Start migrates the schema and starts following the chain
+func (x *Indexer) Start(ctx context.Context) error {
+	if err := x.store.Start(ctx); err != nil {
+		return err
+	}
+	if err := migrate(x.store.GetDB(), x.dialect); err != nil {
+		return err
+	}
+	x.wg.Add(1)
+	go x.run()
+	return nil
+}
+
+// Stop stops the indexer
+func (x *Indexer) Stop(ctx context.Context) error {
+	close(x.done)
+	x.wg.Wait()
+	return x.store.Stop(ctx)
+}
+
+// ReceiveBlock notifies the indexer of the new block
+func (x *Indexer) ReceiveBlock(*block.Block) error {
+	select {
+	case x.notify <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Height returns the height of the last indexed block
+func (x *Indexer) Height() (uint64, error) {
+	var height uint64
+	if err := x.store.GetDB().QueryRow(`SELECT height FROM sync_state WHERE id = 1`).Scan(&height); err != nil {
+		return 0, err
+	}
+	return height, nil
+}
+
+func (x *Indexer) run() {
+	defer x.wg.Done()
+	for {
+		if err := x.catchUp(); err != nil {
+			log.L().Error("Failed to index blocks into sql.", zap.Error(err))
+		}
+		select {
+		case <-x.done:
+			return
+		case <-x.notify:
+		case <-time.After(x.cfg.RetryInterval):
+		}
+	}
+}
+
+func (x *Indexer) catchUp() error {
+	height, err := x.Height()
+	if err != nil {
+		return err
+	}
+	tipHeight, err := x.dao.Height()
+	if err != nil {
+		return err
+	}
+	for height++; height <= tipHeight; height++ {
+		select {
+		case <-x.done:
+			return nil
+		default:
+		}
+		blk, err := x.dao.GetBlockByHeight(height)
+		if err != nil {
+			return err
+		}
+		if blk.Receipts, err = x.dao.GetReceipts(height); err != nil {
+			return err
+		}
+		if err := x.indexBlock(blk); err != nil {
+			return errors.Wrapf(err, "failed to index block %d", height)
+		}
+		if height%1000 == 0 {
+			log.L().Info("Indexed blocks into sql.", zap.Uint64("height", height))
+		}
+	}
+	return nil
+}
+
+// indexBlock writes the block data and the indexed height in one transaction
+func (x *Indexer) indexBlock(blk *block.Block) error {
+	tx, err := x.store.GetDB().Begin()
+	if err != nil {
+		return err
+	}
+	if err := x.putBlock(tx, blk); err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			log.L().Error("Failed to rollback.", zap.Error(rollbackErr))
+		}
+		return err
+	}
+	return tx.Commit()
+}
+
+func (x *Indexer) exec(tx *sql.Tx, query string, args ...interface{}) error {
+	_, err := tx.Exec(x.dialect.rebind(query), args...)
+	return err
+}
+
+func (x *Indexer) putBlock(tx *sql.Tx, blk *block.Block) error {
+	var (
+		height      = blk.Height()
+		blkHash     = blk.HashBlock()
+		gasConsumed uint64
+		receipts    = make(map[hash.Hash256]*action.Receipt, len(blk.Receipts))
+	)
+	for _, r := range blk.Receipts {
+		gasConsumed += r.GasConsumed
+		receipts[r.ActionHash] = r
+	}
+	if err := x.exec(
+		tx,
+		`INSERT INTO blocks (height, hash, producer, block_time, num_actions, gas_consumed) VALUES (?, ?, ?, ?, ?, ?)`,
+		height,
+		hex.EncodeToString(blkHash[:]),
+		blk.ProducerAddress(),
+		blk.Timestamp().Unix(),
+		len(blk.Actions),
+		gasConsumed,
+	); err != nil {
+		return err
+	}
+	for i, selp := range blk.Actions {
+		actHash := selp.Hash()
+		sender, _ := address.FromBytes(selp.SrcPubkey().Hash())
+		var (
+			recipient string
+			amount    = big.NewInt(0)
+		)
+		switch act := selp.Action().(type) {
+		case *action.Transfer:
+			recipient, amount = act.Recipient(), act.Amount()
+		case *action.Execution:
+			recipient, amount = act.Contract(), act.Amount()
+		}
+		if err := x.exec(
+			tx,
+			`INSERT INTO actions (action_hash, block_height, action_index, sender, action_type, nonce, gas_limit, gas_price, recipient, amount) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			hex.EncodeToString(actHash[:]),
+			height,
+			i,
+			sender.String(),
+			typeName(selp.Action()),
+			selp.Nonce(),
+			selp.GasLimit(),
+			selp.GasPrice().String(),
+			recipient,
+			amount.String(),
+		); err != nil {
+			return err
+		}
+		if r, ok := receipts[actHash]; ok {
+			if err := x.putStakingEvent(tx, height, selp, r); err != nil {
+				return err
+			}
+		}
+	}
+	var logIndex int
+	for _, r := range blk.Receipts {
+		if err := x.exec(
+			tx,
+			`INSERT INTO receipts (action_hash, block_height, status, gas_consumed, contract_address) VALUES (?, ?, ?, ?, ?)`,
+			hex.EncodeToString(r.ActionHash[:]),
+			height,
+			r.Status,
+			r.GasConsumed,
+			r.ContractAddress,
+		); err != nil {
+			return err
+		}
+		for _, l := range r.Logs() {
+			topics := make([]string, 0, len(l.Topics))
+			for _, topic := range l.Topics {
+				topics = append(topics, hex.EncodeToString(topic[:]))
+			}
+			if err := x.exec(
+				tx,
+				`INSERT INTO logs (block_height, log_index, action_hash, address, topics, data) VALUES (?, ?, ?, ?, ?, ?)`,
+				height,
+				logIndex,
+				hex.EncodeToString(r.ActionHash[:]),
+				l.Address,
+				strings.Join(topics, ","),
+				hex.EncodeToString(l.Data),
+			); err != nil {
+				return err
+			}
+			logIndex++
+		}
+	}
+	return x.exec(tx, `UPDATE sync_state SET height = ? WHERE id = 1`, height)
+}
+
+func (x *Indexer) putStakingEvent(tx *sql.Tx, height uint64, selp action.SealedEnvelope, r *action.Receipt) error {
+	var (
+		candidate   string
+		bucketIndex *uint64
+		amount      = big.NewInt(0)
+		voter       string
+	)
+	index := func(i uint64) *uint64 { return &i }
+	switch act := selp.Action().(type) {
+	case *action.CreateStake:
+		candidate, amount = act.Candidate(), act.Amount()
+	case *action.DepositToStake:
+		bucketIndex, amount = index(act.BucketIndex()), act.Amount()
+	case *action.ChangeCandidate:
+		candidate, bucketIndex = act.Candidate(), index(act.BucketIndex())
+	case *action.Unstake:
+		bucketIndex = index(act.BucketIndex())
+	case *action.WithdrawStake:
+		bucketIndex = index(act.BucketIndex())
+	case *action.Restake:
+		bucketIndex = index(act.BucketIndex())
+	case *action.TransferStake:
+		bucketIndex = index(act.BucketIndex())
+		if act.VoterAddress() != nil {
+			voter = act.VoterAddress().String()
+		}
+	case *action.CandidateRegister:
+		candidate, amount = act.Name(), act.Amount()
+	case *action.CandidateUpdate:
+		candidate = act.Name()
+	default:
+		return nil
+	}
+	return x.exec(
+		tx,
+		`INSERT INTO staking_events (action_hash, block_height, event_type, status, candidate, bucket_index, amount, voter) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		hex.EncodeToString(r.ActionHash[:]),
+		height,
+		typeName(selp.Action()),
+		r.Status,
+		candidate,
+		bucketIndex,
+		amount.String(),
+		voter,
+	)
+}
+
+func typeName(act interface{}) string {
+	t := reflect.TypeOf(act)
+	if t == nil {
+		return ""
+	}
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Name()
+}
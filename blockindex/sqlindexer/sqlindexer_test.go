@@ -0,0 +1,123 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package sqlindexer
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/action"
+	"github.com/iotexproject/iotex-core/blockchain/block"
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/test/identityset"
+	"github.com/iotexproject/iotex-core/testutil"
+)
+
+type testBlockReader struct {
+	blks []*block.Block
+}
+
+func (r *testBlockReader) Height() (uint64, error) {
+	return uint64(len(r.blks)), nil
+}
+
+func (r *testBlockReader) GetBlockByHeight(height uint64) (*block.Block, error) {
+	return r.blks[height-1], nil
+}
+
+func (r *testBlockReader) GetReceipts(height uint64) ([]*action.Receipt, error) {
+	return r.blks[height-1].Receipts, nil
+}
+
+func TestDialect(t *testing.T) {
+	require := require.New(t)
+
+	d, err := newDialect("postgres")
+	require.NoError(err)
+	require.Equal("UPDATE t SET a = $1 WHERE b = $2", d.rebind("UPDATE t SET a = ? WHERE b = ?"))
+	d, err = newDialect("sqlite3")
+	require.NoError(err)
+	require.Equal("UPDATE t SET a = ? WHERE b = ?", d.rebind("UPDATE t SET a = ? WHERE b = ?"))
+	_, err = newDialect("oracle")
+	require.Error(err)
+}
+
+func TestIndexer(t *testing.T) {
+	require := require.New(t)
+
+	reader := &testBlockReader{}
+	for i := 1; i <= 3; i++ {
+		tsf, err := testutil.SignedTransfer(identityset.Address(1).String(), identityset.PrivateKey(0), uint64(i), big.NewInt(1), nil, 10000, big.NewInt(1))
+		require.NoError(err)
+		cs, err := action.NewCreateStake(uint64(i), "alpha", "100", 1, true, nil, 10000, big.NewInt(1))
+		require.NoError(err)
+		elp := (&action.EnvelopeBuilder{}).SetNonce(uint64(i)).SetGasLimit(10000).SetGasPrice(big.NewInt(1)).SetAction(cs).Build()
+		stake, err := action.Sign(elp, identityset.PrivateKey(0))
+		require.NoError(err)
+		blk, err := block.NewTestingBuilder().
+			SetHeight(uint64(i)).
+			SetTimeStamp(time.Now()).
+			AddActions(tsf, stake).
+			SetReceipts([]*action.Receipt{
+				{ActionHash: tsf.Hash(), GasConsumed: 10000, Status: uint64(1)},
+				{ActionHash: stake.Hash(), GasConsumed: 10000, Status: uint64(1)},
+			}).
+			SignAndBuild(identityset.PrivateKey(0))
+		require.NoError(err)
+		reader.blks = append(reader.blks, &blk)
+	}
+
+	testPath, err := testutil.PathOfTempFile("sqlindexer")
+	require.NoError(err)
+	defer testutil.CleanupPath(t, testPath)
+
+	cfg := config.Default.SQLIndexer
+	cfg.Driver = "sqlite3"
+	cfg.ConnectionString = testPath
+	x, err := NewIndexer(cfg, reader)
+	require.NoError(err)
+	ctx := context.Background()
+	require.NoError(x.store.Start(ctx))
+	defer func() {
+		require.NoError(x.store.Stop(ctx))
+	}()
+	db := x.store.GetDB()
+	require.NoError(migrate(db, x.dialect))
+	// migration is idempotent
+	require.NoError(migrate(db, x.dialect))
+
+	height, err := x.Height()
+	require.NoError(err)
+	require.Zero(height)
+	require.NoError(x.catchUp())
+	height, err = x.Height()
+	require.NoError(err)
+	require.Equal(uint64(3), height)
+
+	count := func(table string) int {
+		var n int
+		require.NoError(db.QueryRow("SELECT COUNT(*) FROM " + table).Scan(&n))
+		return n
+	}
+	require.Equal(3, count("blocks"))
+	require.Equal(6, count("actions"))
+	require.Equal(6, count("receipts"))
+	require.Equal(3, count("staking_events"))
+
+	var candidate, amount string
+	require.NoError(db.QueryRow("SELECT candidate, amount FROM staking_events WHERE block_height = 2").Scan(&candidate, &amount))
+	require.Equal("alpha", candidate)
+	require.Equal("100", amount)
+
+	// nothing to index at tip
+	require.NoError(x.catchUp())
+	require.Equal(3, count("blocks"))
+}
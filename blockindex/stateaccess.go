@@ -0,0 +1,162 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package blockindex
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+
+	"github.com/iotexproject/go-pkgs/hash"
+	"github.com/pkg/errors"
+
+	"github.com/iotexproject/iotex-core/blockchain/block"
+	"github.com/iotexproject/iotex-core/blockchain/blockdao"
+	"github.com/iotexproject/iotex-core/db"
+	"github.com/iotexproject/iotex-core/db/batch"
+	"github.com/iotexproject/iotex-core/pkg/util/byteutil"
+	"github.com/iotexproject/iotex-core/state/factory"
+)
+
+const (
+	// StateAccessNamespace indicates the kvstore namespace to store the state access list of each action
+	StateAccessNamespace = "StateAccess"
+	// StateAccessHeightNamespace indicates the kvstore namespace to store the actions of each block having access
+	// lists, and the tip height of the index
+	StateAccessHeightNamespace = "StateAccessHeight"
+)
+
+type (
+	// AccessListReader reads the state access lists recorded by state factory when running a block
+	AccessListReader interface {
+		AccessLists(uint64) ([]*factory.AccessList, bool)
+	}
+
+	// StateAccessIndexer is the interface for state access indexer
+	StateAccessIndexer interface {
+		blockdao.BlockIndexer
+		// AccessList returns the keys of the states read and written by the action
+		AccessList(hash.Hash256) (*factory.AccessList, error)
+	}
+
+	// stateAccessIndexer stores the state access list of each action in an auxiliary table. Only the blocks run by
+	// the local state factory have the access lists, the blocks indexed when catching up with the chain db don't
+	stateAccessIndexer struct {
+		kvStore db.KVStore
+		reader  AccessListReader
+	}
+)
+
+// NewStateAccessIndexer creates a new state access indexer
+func NewStateAccessIndexer(kv db.KVStore, reader AccessListReader) (StateAccessIndexer, error) {
+	if kv == nil {
+		return nil, errors.New("empty kvStore")
+	}
+	if reader == nil {
+		return nil, errors.New("empty access list reader")
+	}
+	return &stateAccessIndexer{
+		kvStore: kv,
+		reader:  reader,
+	}, nil
+}
+
+// Start starts the state access indexer
+func (sax *stateAccessIndexer) Start(ctx context.Context) error {
+	return sax.kvStore.Start(ctx)
+}
+
+// Stop stops the state access indexer
+func (sax *stateAccessIndexer) Stop(ctx context.Context) error {
+	return sax.kvStore.Stop(ctx)
+}
+
+// Height returns the tip height of the state access indexer
+func (sax *stateAccessIndexer) Height() (uint64, error) {
+	h, err := sax.kvStore.Get(StateAccessHeightNamespace, []byte(CurrentHeightKey))
+	switch errors.Cause(err) {
+	case nil:
+		return byteutil.BytesToUint64BigEndian(h), nil
+	case db.ErrNotExist:
+		return 0, nil
+	default:
+		return 0, err
+	}
+}
+
+// PutBlock stores the state access lists of the actions of the block
+func (sax *stateAccessIndexer) PutBlock(_ context.Context, blk *block.Block) error {
+	height := byteutil.Uint64ToBytesBigEndian(blk.Height())
+	b := batch.NewBatch()
+	if accessLists, ok := sax.reader.AccessLists(blk.Height()); ok && len(accessLists) > 0 {
+		actionHashes := make([]string, 0, len(accessLists))
+		for _, al := range accessLists {
+			h, err := hex.DecodeString(al.ActionHash)
+			if err != nil {
+				return err
+			}
+			data, err := json.Marshal(al)
+			if err != nil {
+				return err
+			}
+			b.Put(StateAccessNamespace, h, data, "failed to put access list of action %s", al.ActionHash)
+			actionHashes = append(actionHashes, al.ActionHash)
+		}
+		data, err := json.Marshal(actionHashes)
+		if err != nil {
+			return err
+		}
+		b.Put(StateAccessHeightNamespace, height, data, "failed to put actions of block %d", blk.Height())
+	}
+	b.Put(StateAccessHeightNamespace, []byte(CurrentHeightKey), height, "failed to put tip height")
+	return sax.kvStore.WriteBatch(b)
+}
+
+// DeleteTipBlock deletes the state access lists of the tip block
+func (sax *stateAccessIndexer) DeleteTipBlock(blk *block.Block) error {
+	height := byteutil.Uint64ToBytesBigEndian(blk.Height())
+	b := batch.NewBatch()
+	data, err := sax.kvStore.Get(StateAccessHeightNamespace, height)
+	switch errors.Cause(err) {
+	case nil:
+		var actionHashes []string
+		if err := json.Unmarshal(data, &actionHashes); err != nil {
+			return err
+		}
+		for _, actionHash := range actionHashes {
+			h, err := hex.DecodeString(actionHash)
+			if err != nil {
+				return err
+			}
+			b.Delete(StateAccessNamespace, h, "failed to delete access list of action %s", actionHash)
+		}
+		b.Delete(StateAccessHeightNamespace, height, "failed to delete actions of block %d", blk.Height())
+	case db.ErrNotExist:
+	default:
+		return err
+	}
+	b.Put(
+		StateAccessHeightNamespace,
+		[]byte(CurrentHeightKey),
+		byteutil.Uint64ToBytesBigEndian(blk.Height()-1),
+		"failed to put tip height",
+	)
+	return sax.kvStore.WriteBatch(b)
+}
+
+// AccessList returns the keys of the states read and written by the action
+func (sax *stateAccessIndexer) AccessList(actionHash hash.Hash256) (*factory.AccessList, error) {
+	data, err := sax.kvStore.Get(StateAccessNamespace, actionHash[:])
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get access list of action %x", actionHash)
+	}
+	al := &factory.AccessList{}
+	if err := json.Unmarshal(data, al); err != nil {
+		return nil, err
+	}
+	return al, nil
+}
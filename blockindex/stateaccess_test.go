@@ -0,0 +1,88 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package blockindex
+
+import (
+	"context"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/db"
+	"github.com/iotexproject/iotex-core/state/factory"
+)
+
+type testAccessListReader map[uint64][]*factory.AccessList
+
+func (r testAccessListReader) AccessLists(height uint64) ([]*factory.AccessList, bool) {
+	lists, ok := r[height]
+	return lists, ok
+}
+
+func TestStateAccessIndexer(t *testing.T) {
+	require := require.New(t)
+
+	blks := getTestLogBlocks(t)
+	h1, err := blks[0].Actions[0].Hash()
+	require.NoError(err)
+	h2, err := blks[0].Actions[1].Hash()
+	require.NoError(err)
+	reader := testAccessListReader{
+		1: {
+			{
+				ActionHash: hex.EncodeToString(h1[:]),
+				Reads:      []factory.StateKey{{Namespace: "Account", Key: "01"}},
+				Writes:     []factory.StateKey{{Namespace: "Account", Key: "01"}, {Namespace: "Account", Key: "02"}},
+			},
+			{
+				ActionHash: hex.EncodeToString(h2[:]),
+				Reads:      []factory.StateKey{{Namespace: "Candidate"}},
+				Writes:     []factory.StateKey{},
+			},
+		},
+	}
+	_, err = NewStateAccessIndexer(db.NewMemKVStore(), nil)
+	require.Error(err)
+	indexer, err := NewStateAccessIndexer(db.NewMemKVStore(), reader)
+	require.NoError(err)
+	ctx := context.Background()
+	require.NoError(indexer.Start(ctx))
+	defer func() {
+		require.NoError(indexer.Stop(ctx))
+	}()
+
+	for _, blk := range blks[:2] {
+		require.NoError(indexer.PutBlock(ctx, blk))
+	}
+	height, err := indexer.Height()
+	require.NoError(err)
+	require.Equal(uint64(2), height)
+
+	al, err := indexer.AccessList(h1)
+	require.NoError(err)
+	require.Equal(reader[1][0].Reads, al.Reads)
+	require.Equal(reader[1][0].Writes, al.Writes)
+	al, err = indexer.AccessList(h2)
+	require.NoError(err)
+	require.Equal([]factory.StateKey{{Namespace: "Candidate"}}, al.Reads)
+	require.Empty(al.Writes)
+
+	// access lists not available from state factory
+	h3, err := blks[1].Actions[0].Hash()
+	require.NoError(err)
+	_, err = indexer.AccessList(h3)
+	require.Error(err)
+
+	require.NoError(indexer.DeleteTipBlock(blks[1]))
+	require.NoError(indexer.DeleteTipBlock(blks[0]))
+	_, err = indexer.AccessList(h1)
+	require.Error(err)
+	height, err = indexer.Height()
+	require.NoError(err)
+	require.Zero(height)
+}
@@ -31,6 +31,8 @@ type (
 	UnicastOutbound func(ctx context.Context, peer peerstore.PeerInfo, msg proto.Message) error
 	// Neighbors returns the neighbors' addresses
 	Neighbors func(ctx context.Context) ([]peerstore.PeerInfo, error)
+	// Rebootstrap reconnects to the bootstrap and fallback nodes
+	Rebootstrap func(ctx context.Context) error
 )
 
 // BlockDAO represents the block data access object
@@ -40,8 +42,9 @@ type BlockDAO interface {
 
 // Config represents the config to setup blocksync
 type Config struct {
-	unicastHandler   UnicastOutbound
-	neighborsHandler Neighbors
+	unicastHandler     UnicastOutbound
+	neighborsHandler   Neighbors
+	rebootstrapHandler Rebootstrap
 }
 
 // Option is the option to override the blocksync config
@@ -63,6 +66,14 @@ func WithNeighbors(neighborsHandler Neighbors) Option {
 	}
 }
 
+// WithRebootstrap is the option to set the callback to recover from stale tip
+func WithRebootstrap(rebootstrapHandler Rebootstrap) Option {
+	return func(cfg *Config) error {
+		cfg.rebootstrapHandler = rebootstrapHandler
+		return nil
+	}
+}
+
 // BlockSync defines the interface of blocksyncer
 type BlockSync interface {
 	lifecycle.StartStopper
@@ -72,6 +83,7 @@ type BlockSync interface {
 	ProcessBlock(ctx context.Context, blk *block.Block) error
 	ProcessBlockSync(ctx context.Context, blk *block.Block) error
 	SyncStatus() string
+	StaleTipEvents() []StaleTipEvent
 }
 
 // blockSyncer implements BlockSync interface
@@ -84,6 +96,8 @@ type blockSyncer struct {
 	dao                   BlockDAO
 	unicastHandler        UnicastOutbound
 	neighborsHandler      Neighbors
+	rebootstrapHandler    Rebootstrap
+	staleTip              *staleTipDetector
 	syncStageTask         *routine.RecurringTask
 	syncStageHeight       uint64
 	syncBlockIncrease     uint64
@@ -116,6 +130,8 @@ func NewBlockSyncer(
 		buf:                   buf,
 		unicastHandler:        bsCfg.unicastHandler,
 		neighborsHandler:      bsCfg.neighborsHandler,
+		rebootstrapHandler:    bsCfg.rebootstrapHandler,
+		staleTip:              newStaleTipDetector(cfg.BlockSync.StaleTipThreshold),
 		worker:                newSyncWorker(chain.ChainID(), cfg, bsCfg.unicastHandler, bsCfg.neighborsHandler, buf),
 		processSyncRequestTTL: cfg.BlockSync.ProcessSyncRequestTTL,
 	}
@@ -152,6 +168,7 @@ func (bs *blockSyncer) Stop(ctx context.Context) error {
 
 // ProcessBlock processes an incoming latest committed block
 func (bs *blockSyncer) ProcessBlock(_ context.Context, blk *block.Block) error {
+	bs.staleTip.Observe(blk.Height(), time.Now())
 	var needSync bool
 	moved, re := bs.buf.Flush(blk)
 	switch re {
@@ -174,6 +191,7 @@ func (bs *blockSyncer) ProcessBlock(_ context.Context, blk *block.Block) error {
 }
 
 func (bs *blockSyncer) ProcessBlockSync(_ context.Context, blk *block.Block) error {
+	bs.staleTip.Observe(blk.Height(), time.Now())
 	bs.buf.Flush(blk)
 	if bs.bc.TipHeight() == bs.TargetHeight() {
 		bs.worker.SetTargetHeight(bs.TargetHeight() + bs.buf.bufSize())
@@ -215,10 +233,54 @@ func (bs *blockSyncer) syncStageChecker() {
 	tipHeight := bs.bc.TipHeight()
 	atomic.StoreUint64(&bs.syncBlockIncrease, tipHeight-bs.syncStageHeight)
 	bs.syncStageHeight = tipHeight
+	bs.checkStaleTip(tipHeight, time.Now())
+}
+
+// checkStaleTip re-bootstraps the node if no peer has reported any block higher than the tip for too long, so that the
+// node does not stay stuck in a network partition
+func (bs *blockSyncer) checkStaleTip(tipHeight uint64, now time.Time) {
+	stale, peakHeight := bs.staleTip.Check(tipHeight, now)
+	if !stale {
+		return
+	}
+	ctx := context.Background()
+	e := StaleTipEvent{
+		Time:       now,
+		TipHeight:  tipHeight,
+		PeakHeight: peakHeight,
+	}
+	if bs.neighborsHandler != nil {
+		if peers, err := bs.neighborsHandler(ctx); err == nil {
+			e.NumPeers = len(peers)
+		}
+	}
+	log.L().Warn("Peers report no block higher than tip, re-bootstrapping.",
+		zap.Uint64("tipHeight", tipHeight),
+		zap.Uint64("peakHeight", peakHeight),
+		zap.Int("numPeers", e.NumPeers))
+	if bs.rebootstrapHandler != nil {
+		if err := bs.rebootstrapHandler(ctx); err != nil {
+			log.L().Error("Failed to re-bootstrap.", zap.Error(err))
+			e.Error = err.Error()
+		} else {
+			e.Rebootstrapped = true
+		}
+	}
+	bs.staleTip.Record(e)
+}
+
+// StaleTipEvents returns the latest stale tip events
+func (bs *blockSyncer) StaleTipEvents() []StaleTipEvent {
+	events, _ := bs.staleTip.Events()
+	return events
 }
 
 // SyncStatus report block sync status
 func (bs *blockSyncer) SyncStatus() string {
+	if events, stale := bs.staleTip.Events(); stale && len(events) > 0 {
+		e := events[len(events)-1]
+		return fmt.Sprintf("stale tip at height %d, no higher block from %d peers since %s", e.TipHeight, e.NumPeers, e.Time.Format(time.RFC3339))
+	}
 	syncBlockIncrease := atomic.LoadUint64(&bs.syncBlockIncrease)
 	if syncBlockIncrease == 1 {
 		return "synced to blockchain tip"
@@ -0,0 +1,103 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package blocksync
+
+import (
+	"sync"
+	"time"
+)
+
+// _maxStaleTipEvents is the number of the latest stale tip events kept
+const _maxStaleTipEvents = 16
+
+// StaleTipEvent is raised when the node has neither committed a block nor heard of a higher block from its peers for
+// longer than the threshold, which indicates a network partition or an isolated node
+type StaleTipEvent struct {
+	Time time.Time
+	// TipHeight is the tip height of the node
+	TipHeight uint64
+	// PeakHeight is the highest block height heard from the peers
+	PeakHeight uint64
+	// NumPeers is the number of peers connected
+	NumPeers int
+	// Rebootstrapped indicates whether the node has reconnected to any bootstrap or fallback node
+	Rebootstrapped bool
+	// Error is the error of re-bootstrap if failed
+	Error string
+}
+
+type staleTipDetector struct {
+	mu           sync.Mutex
+	threshold    time.Duration
+	tipHeight    uint64
+	peakHeight   uint64
+	lastProgress time.Time
+	stale        bool
+	events       []StaleTipEvent
+}
+
+func newStaleTipDetector(threshold time.Duration) *staleTipDetector {
+	return &staleTipDetector{
+		threshold: threshold,
+	}
+}
+
+// Observe records the height of a block received from the peers
+func (d *staleTipDetector) Observe(height uint64, now time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if height > d.peakHeight {
+		d.peakHeight = height
+		d.progress(now)
+	}
+}
+
+// Check returns true if there has been no progress for longer than the threshold since the last progress or the last
+// time stale tip is detected, and the peak height heard from the peers
+func (d *staleTipDetector) Check(tipHeight uint64, now time.Time) (bool, uint64) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.threshold == 0 {
+		return false, d.peakHeight
+	}
+	if tipHeight > d.tipHeight || d.lastProgress.IsZero() {
+		d.tipHeight = tipHeight
+		d.progress(now)
+		return false, d.peakHeight
+	}
+	if now.Sub(d.lastProgress) < d.threshold {
+		return false, d.peakHeight
+	}
+	// check again after another threshold
+	d.lastProgress = now
+	d.stale = true
+	return true, d.peakHeight
+}
+
+// Record keeps the stale tip event
+func (d *staleTipDetector) Record(e StaleTipEvent) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.events = append(d.events, e)
+	if len(d.events) > _maxStaleTipEvents {
+		d.events = d.events[len(d.events)-_maxStaleTipEvents:]
+	}
+}
+
+// Events returns the latest stale tip events, and whether the tip is still stale
+func (d *staleTipDetector) Events() ([]StaleTipEvent, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	events := make([]StaleTipEvent, len(d.events))
+	copy(events, d.events)
+	return events, d.stale
+}
+
+func (d *staleTipDetector) progress(now time.Time) {
+	d.lastProgress = now
+	d.stale = false
+}
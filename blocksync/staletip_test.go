@@ -0,0 +1,101 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package blocksync
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	peerstore "github.com/libp2p/go-libp2p-peerstore"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStaleTipDetector(t *testing.T) {
+	require := require.New(t)
+
+	start := time.Unix(1612345678, 0)
+	d := newStaleTipDetector(time.Minute)
+	stale, _ := d.Check(10, start)
+	require.False(stale)
+	// a higher block heard from peers is progress
+	d.Observe(12, start.Add(50*time.Second))
+	stale, _ = d.Check(10, start.Add(100*time.Second))
+	require.False(stale)
+	stale, peak := d.Check(10, start.Add(110*time.Second))
+	require.True(stale)
+	require.EqualValues(12, peak)
+	// detected again only after another threshold
+	stale, _ = d.Check(10, start.Add(150*time.Second))
+	require.False(stale)
+	stale, _ = d.Check(10, start.Add(170*time.Second))
+	require.True(stale)
+	_, stale = d.Events()
+	require.True(stale)
+	// committing a block is progress
+	stale, _ = d.Check(11, start.Add(300*time.Second))
+	require.False(stale)
+	_, stale = d.Events()
+	require.False(stale)
+
+	// disabled
+	d = newStaleTipDetector(0)
+	for i := 0; i < 3; i++ {
+		stale, _ = d.Check(10, start.Add(time.Duration(i)*time.Hour))
+		require.False(stale)
+	}
+}
+
+func TestBlockSyncer_CheckStaleTip(t *testing.T) {
+	require := require.New(t)
+
+	var rebootstrapErr error
+	rebootstrapped := 0
+	bs := &blockSyncer{
+		neighborsHandler: func(context.Context) ([]peerstore.PeerInfo, error) {
+			return []peerstore.PeerInfo{{}, {}}, nil
+		},
+		rebootstrapHandler: func(context.Context) error {
+			rebootstrapped++
+			return rebootstrapErr
+		},
+		staleTip: newStaleTipDetector(time.Minute),
+	}
+	start := time.Unix(1612345678, 0)
+	bs.checkStaleTip(10, start)
+	bs.checkStaleTip(10, start.Add(30*time.Second))
+	require.Zero(rebootstrapped)
+	require.Empty(bs.StaleTipEvents())
+
+	bs.checkStaleTip(10, start.Add(time.Minute))
+	require.Equal(1, rebootstrapped)
+	rebootstrapErr = errors.New("no node")
+	bs.checkStaleTip(10, start.Add(2*time.Minute))
+	require.Equal(2, rebootstrapped)
+	events := bs.StaleTipEvents()
+	require.Equal([]StaleTipEvent{
+		{
+			Time:           start.Add(time.Minute),
+			TipHeight:      10,
+			NumPeers:       2,
+			Rebootstrapped: true,
+		},
+		{
+			Time:      start.Add(2 * time.Minute),
+			TipHeight: 10,
+			NumPeers:  2,
+			Error:     "no node",
+		},
+	}, events)
+	require.Contains(bs.SyncStatus(), "stale tip at height 10")
+
+	for i := 0; i < _maxStaleTipEvents; i++ {
+		bs.checkStaleTip(10, start.Add(time.Duration(i+3)*time.Minute))
+	}
+	require.Len(bs.StaleTipEvents(), _maxStaleTipEvents)
+}
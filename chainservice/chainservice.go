@@ -9,6 +9,7 @@ package chainservice
 import (
 	"context"
 	"math/big"
+	"sync"
 	"time"
 
 	"github.com/golang/protobuf/proto"
@@ -25,25 +26,48 @@ import (
 	"github.com/iotexproject/iotex-core/action/protocol"
 	"github.com/iotexproject/iotex-core/action/protocol/account"
 	accountutil "github.com/iotexproject/iotex-core/action/protocol/account/util"
+	"github.com/iotexproject/iotex-core/action/protocol/batch"
 	"github.com/iotexproject/iotex-core/action/protocol/execution"
+	"github.com/iotexproject/iotex-core/action/protocol/insurance"
+	"github.com/iotexproject/iotex-core/action/protocol/parameter"
+	"github.com/iotexproject/iotex-core/action/protocol/paymentchannel"
 	"github.com/iotexproject/iotex-core/action/protocol/poll"
+	"github.com/iotexproject/iotex-core/action/protocol/recovery"
 	"github.com/iotexproject/iotex-core/action/protocol/rewarding"
 	"github.com/iotexproject/iotex-core/action/protocol/rolldpos"
+	"github.com/iotexproject/iotex-core/action/protocol/sanction"
 	"github.com/iotexproject/iotex-core/action/protocol/staking"
+	"github.com/iotexproject/iotex-core/action/protocol/subsidy"
+	"github.com/iotexproject/iotex-core/action/protocol/vesting"
 	"github.com/iotexproject/iotex-core/action/protocol/vote/candidatesutil"
 	"github.com/iotexproject/iotex-core/actpool"
 	"github.com/iotexproject/iotex-core/api"
+	"github.com/iotexproject/iotex-core/archive"
 	"github.com/iotexproject/iotex-core/blockchain"
 	"github.com/iotexproject/iotex-core/blockchain/block"
 	"github.com/iotexproject/iotex-core/blockchain/blockdao"
+	"github.com/iotexproject/iotex-core/blockchain/genesis"
 	"github.com/iotexproject/iotex-core/blockindex"
+	"github.com/iotexproject/iotex-core/blockindex/sqlindexer"
 	"github.com/iotexproject/iotex-core/blocksync"
+	"github.com/iotexproject/iotex-core/clockhealth"
 	"github.com/iotexproject/iotex-core/config"
 	"github.com/iotexproject/iotex-core/consensus"
+	"github.com/iotexproject/iotex-core/dashboard"
 	"github.com/iotexproject/iotex-core/db"
 	"github.com/iotexproject/iotex-core/dispatcher"
+	"github.com/iotexproject/iotex-core/epochbus"
+	"github.com/iotexproject/iotex-core/epochevent"
+	"github.com/iotexproject/iotex-core/exporter"
+	"github.com/iotexproject/iotex-core/faucet"
+	"github.com/iotexproject/iotex-core/govreport"
 	"github.com/iotexproject/iotex-core/p2p"
+	"github.com/iotexproject/iotex-core/participation"
+	"github.com/iotexproject/iotex-core/pkg/lifecycle"
 	"github.com/iotexproject/iotex-core/pkg/log"
+	"github.com/iotexproject/iotex-core/pkg/routine"
+	"github.com/iotexproject/iotex-core/rosetta"
+	"github.com/iotexproject/iotex-core/slareport"
 	"github.com/iotexproject/iotex-core/state/factory"
 )
 
@@ -62,7 +86,20 @@ type ChainService struct {
 	bfIndexer          blockindex.BloomFilterIndexer
 	candidateIndexer   *poll.CandidateIndexer
 	candBucketsIndexer *staking.CandidatesBucketsIndexer
+	blockStatsIndexer  blockindex.BlockStatsIndexer
+	tasks              *routine.TaskManager
+	faucet             *faucet.Faucet
+	dashboard          *dashboard.Dashboard
+	rosetta            *rosetta.Server
+	clockMonitor       *clockhealth.Monitor
+	slaReporter        *slareport.Reporter
+	participation      *participation.Tracker
 	registry           *protocol.Registry
+	genesis            genesis.Genesis
+	stateSnapshots     *archive.StateSnapshots
+	// lazyIndexers are the indexers disabled by config, which are materialized on demand
+	lazyIndexers map[string]blockdao.BlockIndexer
+	lazyMutex    sync.Mutex
 }
 
 type optionParams struct {
@@ -103,6 +140,10 @@ func New(
 		bfIndexer          blockindex.BloomFilterIndexer
 		candidateIndexer   *poll.CandidateIndexer
 		candBucketsIndexer *staking.CandidatesBucketsIndexer
+		blockStatsIndexer  blockindex.BlockStatsIndexer
+		candHistoryIndexer blockindex.CandidateHistoryIndexer
+		stateAccessIndexer blockindex.StateAccessIndexer
+		lazyIndexers       = make(map[string]blockdao.BlockIndexer)
 		err                error
 		ops                optionParams
 	)
@@ -113,27 +154,42 @@ func New(
 	}
 	registry := protocol.NewRegistry()
 	// create state factory
-	var sf factory.Factory
+	var (
+		sf      factory.Factory
+		stateDB *db.BoltDB
+	)
 	if ops.isTesting {
 		sf, err = factory.NewFactory(cfg, factory.InMemTrieOption(), factory.RegistryOption(registry))
 		if err != nil {
 			return nil, errors.Wrapf(err, "Failed to create state factory")
 		}
 	} else {
+		if cfg.Chain.TrieDBPath == "" {
+			return nil, errors.New("Invalid empty trie db path")
+		}
+		// state db is pre-created to be shared with the archive uploader
+		stateDBCfg := cfg.DB
+		stateDBCfg.DbPath = cfg.Chain.TrieDBPath
+		stateDB = db.NewBoltDB(stateDBCfg)
 		if cfg.Chain.EnableTrielessStateDB {
 			if cfg.Chain.EnableStateDBCaching {
-				sf, err = factory.NewStateDB(cfg, factory.CachedStateDBOption(), factory.RegistryStateDBOption(registry))
+				sf, err = factory.NewStateDB(cfg, factory.PrecreatedStateDBOption(db.NewKvStoreWithCache(stateDB, cfg.Chain.StateDBCacheSize)), factory.RegistryStateDBOption(registry))
 			} else {
-				sf, err = factory.NewStateDB(cfg, factory.DefaultStateDBOption(), factory.RegistryStateDBOption(registry))
+				sf, err = factory.NewStateDB(cfg, factory.PrecreatedStateDBOption(stateDB), factory.RegistryStateDBOption(registry))
 			}
 		} else {
-			sf, err = factory.NewFactory(cfg, factory.DefaultTrieOption(), factory.RegistryOption(registry))
+			sf, err = factory.NewFactory(cfg, factory.PrecreatedTrieDBOption(stateDB), factory.RegistryOption(registry))
 		}
 		if err != nil {
 			return nil, errors.Wrapf(err, "Failed to create state factory")
 		}
 	}
-	indexers = append(indexers, sf)
+	if cfg.Chain.TrustedCheckpoint.Height > 0 {
+		// the state at the checkpoint is restored from a snapshot ahead of the chain db
+		indexers = append(indexers, blockdao.NewCheckpointIndexer(sf))
+	} else {
+		indexers = append(indexers, sf)
+	}
 	var chainOpts []blockchain.Option
 	var electionCommittee committee.Committee
 	if cfg.Genesis.EnableGravityChainVoting {
@@ -163,6 +219,15 @@ func New(
 			}
 		}
 	}
+	// attachIndexer attaches the indexer to the blockdao unless it is disabled
+	attachIndexer := func(name string, indexer blockdao.BlockIndexer) bool {
+		if cfg.Chain.IndexerDisabled(name) {
+			lazyIndexers[name] = indexer
+			return false
+		}
+		indexers = append(indexers, indexer)
+		return true
+	}
 	_, gateway := cfg.Plugins[config.GatewayPlugin]
 	if gateway {
 		cfg.DB.DbPath = cfg.Chain.IndexDBPath
@@ -180,7 +245,43 @@ func New(
 		if err != nil {
 			return nil, err
 		}
-		indexers = append(indexers, bfIndexer)
+		if !attachIndexer(config.BloomfilterIndexer, bfIndexer) {
+			bfIndexer = nil
+		}
+
+		// create block stats indexer
+		cfg.DB.DbPath = cfg.Chain.BlockStatsIndexDBPath
+		statsReader, _ := sf.(blockindex.ExecutionStatsReader)
+		blockStatsIndexer, err = blockindex.NewBlockStatsIndexer(db.NewBoltDB(cfg.DB), statsReader)
+		if err != nil {
+			return nil, err
+		}
+		if !attachIndexer(config.BlockStatsIndexer, blockStatsIndexer) {
+			blockStatsIndexer = nil
+		}
+
+		// create candidate history indexer
+		cfg.DB.DbPath = cfg.Chain.CandidateHistoryIndexDBPath
+		candHistoryIndexer, err = blockindex.NewCandidateHistoryIndexer(db.NewBoltDB(cfg.DB))
+		if err != nil {
+			return nil, err
+		}
+		if !attachIndexer(config.CandidateHistoryIndexer, candHistoryIndexer) {
+			candHistoryIndexer = nil
+		}
+
+		// create state access indexer
+		if cfg.Chain.StateAccessIndexDBPath != "" {
+			cfg.DB.DbPath = cfg.Chain.StateAccessIndexDBPath
+			accessReader, _ := sf.(blockindex.AccessListReader)
+			stateAccessIndexer, err = blockindex.NewStateAccessIndexer(db.NewBoltDB(cfg.DB), accessReader)
+			if err != nil {
+				return nil, err
+			}
+			if !attachIndexer(config.StateAccessIndexer, stateAccessIndexer) {
+				stateAccessIndexer = nil
+			}
+		}
 
 		// create candidate indexer
 		cfg.DB.DbPath = cfg.Chain.CandidateIndexDBPath
@@ -201,6 +302,10 @@ func New(
 	var dao blockdao.BlockDAO
 	if ops.isTesting {
 		dao = blockdao.NewBlockDAOInMemForTest(indexers)
+	} else if cfg.Chain.RemoteChainDBEndpoint != "" {
+		if dao, err = blockdao.NewRemoteBlockDAO(indexers, cfg.Chain.RemoteChainDBEndpoint, cfg.DB); err != nil {
+			return nil, err
+		}
 	} else {
 		cfg.DB.DbPath = cfg.Chain.ChainDBPath
 		cfg.DB.CompressLegacy = cfg.Chain.CompressBlock
@@ -209,6 +314,13 @@ func New(
 
 	// Create ActPool
 	actOpts := make([]actpool.Option, 0)
+	// the actpool counts the gas fee paid by the subsidy out of the cost of the sender
+	subsidyProtocol := subsidy.NewProtocol(rewarding.DepositGas)
+	if !ops.isSubchain {
+		actOpts = append(actOpts, actpool.GasSubsidyOption(func(sr protocol.StateReader, selp action.SealedEnvelope) (*big.Int, error) {
+			return subsidyProtocol.PendingCredit(cfg.Genesis, sr, selp)
+		}))
+	}
 	actPool, err := actpool.NewActPool(sf, cfg.ActPool, actOpts...)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to create actpool")
@@ -217,7 +329,11 @@ func New(
 	// Add action validators
 	actPool.AddActionEnvelopeValidators(
 		protocol.NewGenericValidator(sf, accountutil.AccountState),
+		account.NewReservationValidator(sf, &cfg.Genesis),
 	)
+	if len(cfg.Genesis.SanctionGovernors) > 0 {
+		actPool.AddActionEnvelopeValidators(sanction.NewValidator(sf))
+	}
 	if !ops.isSubchain {
 		chainOpts = append(chainOpts, blockchain.BlockValidatorOption(block.NewValidator(sf, actPool)))
 	} else {
@@ -242,15 +358,25 @@ func New(
 			log.L().Warn("Failed to add subscriber: index builder.", zap.Error(err))
 		}
 	}
+	// epochBus invalidates the caches of the delegates when the candidates are shifted
+	epochBus := epochbus.NewBus(registry)
+	if err := chain.AddSubscriber(epochBus); err != nil {
+		log.L().Warn("Failed to add subscriber: epoch bus.", zap.Error(err))
+		// the caches could not be invalidated without the bus, so nothing is cached
+		epochBus = nil
+	}
 	copts := []consensus.Option{
 		consensus.WithBroadcast(func(msg proto.Message) error {
 			return p2pAgent.BroadcastOutbound(p2p.WitContext(context.Background(), p2p.Context{ChainID: chain.ChainID()}), msg)
 		}),
+		consensus.WithEpochBus(epochBus),
 	}
 	var (
 		rDPoSProtocol   *rolldpos.Protocol
 		pollProtocol    poll.Protocol
 		stakingProtocol *staking.Protocol
+		clockMonitor    *clockhealth.Monitor
+		tracker         *participation.Tracker
 	)
 	// staking protocol need to be put in registry before poll protocol when enabling
 	if cfg.Chain.EnableStakingProtocol {
@@ -270,6 +396,22 @@ func New(
 			rolldpos.EnableDardanellesSubEpoch(cfg.Genesis.DardanellesBlockHeight, cfg.Genesis.DardanellesNumSubEpochs),
 		)
 		copts = append(copts, consensus.WithRollDPoSProtocol(rDPoSProtocol))
+		clockMonitor = clockhealth.NewMonitor(cfg.ClockHealth)
+		copts = append(copts, consensus.WithClockChecker(clockMonitor))
+		if p2pAgent != nil && !ops.isSubchain {
+			p2pAgent.SetPeerTimeHandler(clockMonitor.ObservePeerTime)
+		}
+		if cfg.Chain.ParticipationDBPath != "" {
+			cfg.DB.DbPath = cfg.Chain.ParticipationDBPath
+			tracker, err = participation.NewTracker(cfg.Genesis, sf, registry, db.NewBoltDB(cfg.DB))
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to create participation tracker")
+			}
+			copts = append(copts, consensus.WithEndorsementObserver(tracker.ObserveEndorsement))
+			if err := chain.AddSubscriber(tracker); err != nil {
+				log.L().Warn("Failed to add subscriber: participation tracker.", zap.Error(err))
+			}
+		}
 		pollProtocol, err = poll.NewProtocol(
 			cfg,
 			candidateIndexer,
@@ -311,10 +453,17 @@ func New(
 				return blockchain.Productivity(chain, start, end)
 			},
 			dao.GetBlockHash,
+			rewarding.DepositGas,
+			rewarding.CreditFund,
 		)
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to generate poll protocol")
 		}
+		if pollProtocol != nil && cfg.Chain.ShadowFork.Height > 0 {
+			log.L().Warn("Delegates are replaced by the local ones after the shadow fork height.",
+				zap.Uint64("height", cfg.Chain.ShadowFork.Height))
+			pollProtocol = poll.NewShadowForkProtocol(pollProtocol, cfg.Chain.ShadowFork.Height, cfg.Chain.ShadowFork.Delegates)
+		}
 		if pollProtocol != nil {
 			copts = append(copts, consensus.WithPollProtocol(pollProtocol))
 		}
@@ -339,11 +488,14 @@ func New(
 			return p2pAgent.UnicastOutbound(ctx, peer, msg)
 		}),
 		blocksync.WithNeighbors(p2pAgent.Neighbors),
+		blocksync.WithRebootstrap(p2pAgent.Rebootstrap),
 	)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to create blockSyncer")
 	}
 
+	// tasks owns the background subsystems consuming the committed blocks
+	tasks := routine.NewTaskManager()
 	var apiSvr *api.Server
 	apiSvr, err = api.NewServer(
 		cfg,
@@ -360,6 +512,13 @@ func New(
 			return p2pAgent.BroadcastOutbound(ctx, msg)
 		}),
 		api.WithNativeElection(electionCommittee),
+		api.WithBlockStatsIndexer(blockStatsIndexer),
+		api.WithCandidateHistoryIndexer(candHistoryIndexer),
+		api.WithStateAccessIndexer(stateAccessIndexer),
+		api.WithEpochBus(epochBus),
+		api.WithParticipationTracker(tracker),
+		api.WithLatencyTracker(p2pAgent.LatencyTracker()),
+		api.WithTaskManager(tasks),
 	)
 	if err != nil {
 		return nil, err
@@ -380,6 +539,29 @@ func New(
 			return nil, err
 		}
 	}
+	if !ops.isSubchain {
+		var bucketTransferer recovery.BucketTransferer
+		if stakingProtocol != nil {
+			bucketTransferer = stakingProtocol
+		}
+		// batch, subsidy, payment channel, vesting, recovery, sanction, parameter and insurance protocols handle the
+		// executions to their addresses before the execution protocol. The insurance protocol is registered after the
+		// poll protocol, which writes the probation list it pays out on
+		for _, p := range []protocol.Protocol{
+			batch.NewProtocol(rewarding.DepositGas),
+			subsidyProtocol,
+			paymentchannel.NewProtocol(rewarding.DepositGas),
+			vesting.NewProtocol(rewarding.DepositGas),
+			recovery.NewProtocol(rewarding.DepositGas, bucketTransferer),
+			sanction.NewProtocol(rewarding.DepositGas),
+			parameter.NewProtocol(rewarding.DepositGas),
+			insurance.NewProtocol(rewarding.DepositGas),
+		} {
+			if err = p.Register(registry); err != nil {
+				return nil, err
+			}
+		}
+	}
 	executionProtocol := execution.NewProtocol(dao.GetBlockHash, rewarding.DepositGas)
 	if executionProtocol != nil {
 		if err = executionProtocol.Register(registry); err != nil {
@@ -391,6 +573,125 @@ func New(
 			return nil, err
 		}
 	}
+	if err = registry.Validate(); err != nil {
+		return nil, errors.Wrap(err, "invalid protocol dependencies")
+	}
+	if cfg.Exporter.Type != "" {
+		cfg.DB.DbPath = cfg.Exporter.DBPath
+		blockExporter, err := exporter.NewExporter(
+			cfg.Exporter,
+			dao,
+			db.NewBoltDB(cfg.DB),
+			exporter.NewNATSPublisher(cfg.Exporter.Endpoint, cfg.Exporter.Timeout),
+		)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create exporter")
+		}
+		if err := chain.AddSubscriber(blockExporter); err != nil {
+			log.L().Warn("Failed to add subscriber: exporter.", zap.Error(err))
+		}
+		if err := tasks.AddService("exporter", newSubscriberService("exporter", chain, blockExporter)); err != nil {
+			return nil, err
+		}
+	}
+	if cfg.SQLIndexer.Driver != "" {
+		sqlIndexer, err := sqlindexer.NewIndexer(cfg.SQLIndexer, dao)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create sql indexer")
+		}
+		if err := chain.AddSubscriber(sqlIndexer); err != nil {
+			log.L().Warn("Failed to add subscriber: sql indexer.", zap.Error(err))
+		}
+		if err := tasks.AddService("sql indexer", newSubscriberService("sql indexer", chain, sqlIndexer)); err != nil {
+			return nil, err
+		}
+	}
+	if cfg.Archive.Type != "" {
+		store, err := archive.NewObjectStore(cfg.Archive)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create archive store")
+		}
+		var snapshotter archive.Snapshotter
+		if stateDB != nil {
+			snapshotter = stateDB
+		}
+		archiveUploader := archive.NewUploader(cfg.Archive, cfg.Genesis.Hash(), dao, snapshotter, store)
+		if err := chain.AddSubscriber(archiveUploader); err != nil {
+			log.L().Warn("Failed to add subscriber: archive uploader.", zap.Error(err))
+		}
+		if err := tasks.AddService(
+			"archive uploader",
+			newSubscriberService("archive uploader", chain, archiveUploader),
+		); err != nil {
+			return nil, err
+		}
+	}
+	var stateSnapshots *archive.StateSnapshots
+	if cfg.StateSnapshot.Dir != "" && stateDB != nil {
+		stateSnapshots = archive.NewStateSnapshots(cfg.StateSnapshot, stateDB)
+		if err := chain.AddSubscriber(stateSnapshots); err != nil {
+			log.L().Warn("Failed to add subscriber: state snapshots.", zap.Error(err))
+		}
+		if err := tasks.AddService(
+			"state snapshots",
+			newSubscriberService("state snapshots", chain, stateSnapshots),
+		); err != nil {
+			return nil, err
+		}
+	}
+	var fct *faucet.Faucet
+	if cfg.Faucet.Port != 0 {
+		fct, err = faucet.NewFaucet(cfg.Faucet, apiSvr)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create faucet")
+		}
+	}
+	var dsh *dashboard.Dashboard
+	if cfg.Dashboard.Port != 0 {
+		dsh = dashboard.NewDashboard(cfg.Dashboard, apiSvr)
+	}
+	var rst *rosetta.Server
+	if cfg.Rosetta.Port != 0 {
+		rst = rosetta.NewServer(cfg.Rosetta, apiSvr)
+	}
+	if len(cfg.EpochEvent.WebhookURLs) > 0 {
+		epochEventBus := epochevent.NewBus(cfg.EpochEvent, cfg.Genesis, sf, dao, registry)
+		if err := chain.AddSubscriber(epochEventBus); err != nil {
+			log.L().Warn("Failed to add subscriber: epoch event bus.", zap.Error(err))
+		}
+	}
+	if cfg.GovernanceReport.Store.Type != "" {
+		store, err := archive.NewObjectStore(cfg.GovernanceReport.Store)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create governance report store")
+		}
+		govReporter, err := govreport.NewReporter(cfg.Genesis, sf, dao, registry, cfg.ProducerPrivateKey(), store)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create governance reporter")
+		}
+		if err := chain.AddSubscriber(govReporter); err != nil {
+			log.L().Warn("Failed to add subscriber: governance reporter.", zap.Error(err))
+		}
+	}
+	var slaReporter *slareport.Reporter
+	if cfg.SLAReport.DBPath != "" && cfg.Consensus.Scheme == config.RollDPoSScheme {
+		cfg.DB.DbPath = cfg.SLAReport.DBPath
+		slaReporter, err = slareport.NewReporter(
+			cfg.SLAReport,
+			cfg.Genesis,
+			sf,
+			chain,
+			registry,
+			cfg.ProducerPrivateKey(),
+			db.NewBoltDB(cfg.DB),
+		)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create sla reporter")
+		}
+		if err := chain.AddSubscriber(slaReporter); err != nil {
+			log.L().Warn("Failed to add subscriber: sla reporter.", zap.Error(err))
+		}
+	}
 
 	return &ChainService{
 		actpool:            actPool,
@@ -404,11 +705,44 @@ func New(
 		bfIndexer:          bfIndexer,
 		candidateIndexer:   candidateIndexer,
 		candBucketsIndexer: candBucketsIndexer,
+		blockStatsIndexer:  blockStatsIndexer,
+		tasks:              tasks,
+		faucet:             fct,
+		dashboard:          dsh,
+		rosetta:            rst,
+		clockMonitor:       clockMonitor,
+		slaReporter:        slaReporter,
+		participation:      tracker,
 		api:                apiSvr,
 		registry:           registry,
+		genesis:            cfg.Genesis,
+		stateSnapshots:     stateSnapshots,
+		lazyIndexers:       lazyIndexers,
 	}, nil
 }
 
+// StateSnapshots returns the local snapshots of the state db, nil if disabled
+func (cs *ChainService) StateSnapshots() *archive.StateSnapshots {
+	return cs.stateSnapshots
+}
+
+// MaterializeIndexer catches the indexer disabled by config up to the tip and builds it on commit afterwards. The
+// api serves the requests depending on the indexer once the node restarts with the indexer enabled
+func (cs *ChainService) MaterializeIndexer(ctx context.Context, name string) error {
+	cs.lazyMutex.Lock()
+	defer cs.lazyMutex.Unlock()
+	indexer, ok := cs.lazyIndexers[name]
+	if !ok {
+		return errors.Errorf("indexer %s is not disabled", name)
+	}
+	ctx = protocol.WithBlockchainCtx(ctx, protocol.BlockchainCtx{Genesis: cs.genesis})
+	if err := cs.blockdao.AddIndexer(ctx, indexer); err != nil {
+		return errors.Wrapf(err, "failed to materialize indexer %s", name)
+	}
+	delete(cs.lazyIndexers, name)
+	return nil
+}
+
 // Start starts the server
 func (cs *ChainService) Start(ctx context.Context) error {
 	if cs.electionCommittee != nil {
@@ -420,6 +754,12 @@ func (cs *ChainService) Start(ctx context.Context) error {
 		if err := cs.candidateIndexer.Start(ctx); err != nil {
 			return errors.Wrap(err, "error when starting candidate indexer")
 		}
+		if _, err := cs.candidateIndexer.Migrate(); err != nil {
+			return errors.Wrap(err, "error when migrating candidate indexer")
+		}
+		if _, err := cs.candidateIndexer.BackfillProbationHistory(); err != nil {
+			return errors.Wrap(err, "error when backfilling probation history of candidate indexer")
+		}
 	}
 	if cs.candBucketsIndexer != nil {
 		if err := cs.candBucketsIndexer.Start(ctx); err != nil {
@@ -429,6 +769,16 @@ func (cs *ChainService) Start(ctx context.Context) error {
 	if err := cs.chain.Start(ctx); err != nil {
 		return errors.Wrap(err, "error when starting blockchain")
 	}
+	if cs.participation != nil {
+		if err := cs.participation.Start(ctx); err != nil {
+			return errors.Wrap(err, "error when starting participation tracker")
+		}
+	}
+	if cs.clockMonitor != nil {
+		if err := cs.clockMonitor.Start(ctx); err != nil {
+			return errors.Wrap(err, "error when starting clock monitor")
+		}
+	}
 	if err := cs.consensus.Start(ctx); err != nil {
 		return errors.Wrap(err, "error when starting consensus")
 	}
@@ -437,6 +787,9 @@ func (cs *ChainService) Start(ctx context.Context) error {
 			return errors.Wrap(err, "error when starting index builder")
 		}
 	}
+	if err := cs.tasks.Start(ctx); err != nil {
+		return errors.Wrap(err, "error when starting background subsystems")
+	}
 	if err := cs.blocksync.Start(ctx); err != nil {
 		return errors.Wrap(err, "error when starting blocksync")
 	}
@@ -446,6 +799,26 @@ func (cs *ChainService) Start(ctx context.Context) error {
 			return errors.Wrap(err, "err when starting API server")
 		}
 	}
+	if cs.slaReporter != nil {
+		if err := cs.slaReporter.Start(ctx); err != nil {
+			return errors.Wrap(err, "error when starting sla reporter")
+		}
+	}
+	if cs.faucet != nil {
+		if err := cs.faucet.Start(ctx); err != nil {
+			return errors.Wrap(err, "error when starting faucet")
+		}
+	}
+	if cs.dashboard != nil {
+		if err := cs.dashboard.Start(ctx); err != nil {
+			return errors.Wrap(err, "error when starting dashboard")
+		}
+	}
+	if cs.rosetta != nil {
+		if err := cs.rosetta.Start(ctx); err != nil {
+			return errors.Wrap(err, "error when starting rosetta server")
+		}
+	}
 
 	return nil
 }
@@ -460,6 +833,29 @@ func (cs *ChainService) Stop(ctx context.Context) error {
 			return errors.Wrap(err, "error when stopping index builder")
 		}
 	}
+	if cs.slaReporter != nil {
+		if err := cs.chain.RemoveSubscriber(cs.slaReporter); err != nil {
+			return errors.Wrap(err, "failed to unsubscribe sla reporter")
+		}
+		if err := cs.slaReporter.Stop(ctx); err != nil {
+			return errors.Wrap(err, "error when stopping sla reporter")
+		}
+	}
+	if cs.faucet != nil {
+		if err := cs.faucet.Stop(ctx); err != nil {
+			return errors.Wrap(err, "error when stopping faucet")
+		}
+	}
+	if cs.dashboard != nil {
+		if err := cs.dashboard.Stop(ctx); err != nil {
+			return errors.Wrap(err, "error when stopping dashboard")
+		}
+	}
+	if cs.rosetta != nil {
+		if err := cs.rosetta.Stop(ctx); err != nil {
+			return errors.Wrap(err, "error when stopping rosetta server")
+		}
+	}
 	// TODO: explorer dependency deleted at #1085, need to revive by migrating to api
 	if cs.api != nil {
 		if err := cs.api.Stop(); err != nil {
@@ -469,6 +865,22 @@ func (cs *ChainService) Stop(ctx context.Context) error {
 	if err := cs.consensus.Stop(ctx); err != nil {
 		return errors.Wrap(err, "error when stopping consensus")
 	}
+	if cs.clockMonitor != nil {
+		if err := cs.clockMonitor.Stop(ctx); err != nil {
+			return errors.Wrap(err, "error when stopping clock monitor")
+		}
+	}
+	if cs.participation != nil {
+		if err := cs.chain.RemoveSubscriber(cs.participation); err != nil {
+			return errors.Wrap(err, "failed to unsubscribe participation tracker")
+		}
+		if err := cs.participation.Stop(ctx); err != nil {
+			return errors.Wrap(err, "error when stopping participation tracker")
+		}
+	}
+	if err := cs.tasks.Stop(ctx); err != nil {
+		return errors.Wrap(err, "error when stopping background subsystems")
+	}
 	if err := cs.blocksync.Stop(ctx); err != nil {
 		return errors.Wrap(err, "error when stopping blocksync")
 	}
@@ -573,3 +985,46 @@ func (cs *ChainService) BlockSync() blocksync.BlockSync {
 
 // Registry returns a pointer to the registry
 func (cs *ChainService) Registry() *protocol.Registry { return cs.registry }
+
+type (
+	blockSubscriberService interface {
+		lifecycle.StartStopper
+		blockchain.BlockCreationSubscriber
+	}
+
+	// subscriberService unsubscribes the service from the chain before stopping it
+	subscriberService struct {
+		name  string
+		chain blockchain.Blockchain
+		svc   blockSubscriberService
+	}
+)
+
+func newSubscriberService(name string, chain blockchain.Blockchain, svc blockSubscriberService) *subscriberService {
+	return &subscriberService{
+		name:  name,
+		chain: chain,
+		svc:   svc,
+	}
+}
+
+// Start starts the service
+func (s *subscriberService) Start(ctx context.Context) error {
+	return s.svc.Start(ctx)
+}
+
+// Stop unsubscribes and stops the service
+func (s *subscriberService) Stop(ctx context.Context) error {
+	if err := s.chain.RemoveSubscriber(s.svc); err != nil {
+		return errors.Wrapf(err, "failed to unsubscribe %s", s.name)
+	}
+	return s.svc.Stop(ctx)
+}
+
+// Health returns the health of the service if it reports
+func (s *subscriberService) Health() error {
+	if hc, ok := s.svc.(routine.HealthChecker); ok {
+		return hc.Health()
+	}
+	return nil
+}
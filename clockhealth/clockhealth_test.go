@@ -0,0 +1,119 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package clockhealth
+
+import (
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/config"
+)
+
+// serveNTP serves one ntp request with a clock ahead of the local one by the offset
+func serveNTP(t *testing.T, offset time.Duration, mode byte) string {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go func() {
+		defer conn.Close()
+		req := make([]byte, _ntpPacketSize)
+		_, addr, err := conn.ReadFrom(req)
+		if err != nil {
+			return
+		}
+		res := make([]byte, _ntpPacketSize)
+		res[0] = 0x20 | mode
+		res[1] = 1
+		copy(res[24:32], req[40:48])
+		copy(res[32:40], toNTPTime(time.Now().Add(offset)))
+		copy(res[40:48], toNTPTime(time.Now().Add(offset)))
+		conn.WriteTo(res, addr)
+	}()
+	return conn.LocalAddr().String()
+}
+
+func TestQueryNTP(t *testing.T) {
+	require := require.New(t)
+
+	ts := time.Unix(1612345678, 123456789)
+	require.InDelta(ts.UnixNano(), fromNTPTime(toNTPTime(ts)).UnixNano(), 1)
+
+	offset, err := QueryNTP(serveNTP(t, 3*time.Second, _ntpModeServer), time.Second)
+	require.NoError(err)
+	require.InDelta(float64(3*time.Second), float64(offset), float64(100*time.Millisecond))
+
+	_, err = QueryNTP(serveNTP(t, 0, 3), time.Second)
+	require.Error(err)
+
+	// no response
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(err)
+	defer conn.Close()
+	_, err = QueryNTP(conn.LocalAddr().String(), 100*time.Millisecond)
+	require.Error(err)
+}
+
+func TestMonitor(t *testing.T) {
+	require := require.New(t)
+
+	// ntp is opt-in
+	cfg := config.Default.ClockHealth
+	require.Empty(cfg.NTPServers)
+	m := NewMonitor(cfg)
+	now := time.Now()
+	m.now = func() time.Time { return now }
+
+	// healthy if not measured yet
+	_, ok := m.Offset()
+	require.False(ok)
+	require.NoError(m.Check())
+
+	// peer reported times
+	for i, d := range []time.Duration{-100 * time.Millisecond, 2 * time.Second, 3 * time.Second} {
+		m.ObservePeerTime(strconv.Itoa(i), now.Add(d))
+	}
+	m.ObservePeerTime("zero", time.Time{})
+	offset, ok := m.PeerOffset()
+	require.True(ok)
+	require.Equal(2*time.Second, offset)
+	require.Equal(ErrClockSkew, errors.Cause(m.Check()))
+	m.ObservePeerTime("3", now)
+	offset, _ = m.Offset()
+	require.Equal(time.Second, offset)
+	require.NoError(m.Check())
+
+	// stale peer reported times are dropped
+	now = now.Add(_peerSampleTTL + time.Second)
+	_, ok = m.PeerOffset()
+	require.False(ok)
+
+	// ntp offset is preferred
+	cfg.NTPServers = []string{"a", "b", "c"}
+	m = newMonitor(cfg, func(server string, _ time.Duration) (time.Duration, error) {
+		switch server {
+		case "a":
+			return -2 * time.Second, nil
+		case "b":
+			return -3 * time.Second, nil
+		default:
+			return 0, errors.New("timeout")
+		}
+	})
+	m.ObservePeerTime("peer", time.Now())
+	m.update()
+	offset, ok = m.NTPOffset()
+	require.True(ok)
+	require.Equal(-2*time.Second, offset)
+	offset, _ = m.Offset()
+	require.Equal(-2*time.Second, offset)
+	require.Equal(ErrClockSkew, errors.Cause(m.Check()))
+	require.False(m.RefuseProposal())
+}
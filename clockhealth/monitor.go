@@ -0,0 +1,219 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+// Package clockhealth measures the offset of the local clock against ntp servers and the times reported by peers.
+// A skewed clock makes the delegate miss its rounds silently, so the offset is exposed in metrics and checked before
+// proposing blocks.
+package clockhealth
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/pkg/lifecycle"
+	"github.com/iotexproject/iotex-core/pkg/log"
+	"github.com/iotexproject/iotex-core/pkg/routine"
+)
+
+const (
+	_sourceNTP  = "ntp"
+	_sourcePeer = "peer"
+	// _peerSampleTTL is how long the time reported by a peer is taken into account
+	_peerSampleTTL = 10 * time.Minute
+	// _maxPeerSamples bounds the number of peers tracked
+	_maxPeerSamples = 1024
+)
+
+var (
+	// ErrClockSkew indicates the offset of the local clock exceeds the tolerated one
+	ErrClockSkew = errors.New("local clock is skewed")
+
+	_clockOffsetMtc = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "iotex_clock_offset",
+			Help: "Offset in seconds of the local clock, a positive offset means the local clock is behind",
+		},
+		[]string{"source"},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(_clockOffsetMtc)
+}
+
+var _ lifecycle.StartStopper = (*Monitor)(nil)
+
+type (
+	// QueryFunc queries the offset of the local clock to the ntp server
+	QueryFunc func(server string, timeout time.Duration) (time.Duration, error)
+
+	// Monitor monitors the offset of the local clock
+	Monitor struct {
+		cfg   config.ClockHealth
+		query QueryFunc
+		task  *routine.RecurringTask
+		now   func() time.Time
+
+		mutex      sync.RWMutex
+		ntpOffset  time.Duration
+		ntpUpdated bool
+		peers      map[string]peerSample
+	}
+
+	peerSample struct {
+		offset time.Duration
+		at     time.Time
+	}
+)
+
+// NewMonitor creates a clock monitor
+func NewMonitor(cfg config.ClockHealth) *Monitor {
+	return newMonitor(cfg, QueryNTP)
+}
+
+func newMonitor(cfg config.ClockHealth, query QueryFunc) *Monitor {
+	m := &Monitor{
+		cfg:   cfg,
+		query: query,
+		now:   time.Now,
+		peers: map[string]peerSample{},
+	}
+	if len(cfg.NTPServers) > 0 {
+		m.task = routine.NewRecurringTask(m.update, cfg.Interval)
+	}
+	return m
+}
+
+// Start starts querying the ntp servers
+func (m *Monitor) Start(ctx context.Context) error {
+	if m.task == nil {
+		return nil
+	}
+	go m.update()
+	return m.task.Start(ctx)
+}
+
+// Stop stops querying the ntp servers
+func (m *Monitor) Stop(ctx context.Context) error {
+	if m.task == nil {
+		return nil
+	}
+	return m.task.Stop(ctx)
+}
+
+// ObservePeerTime records the time reported by a peer, e.g., the timestamp of a message it sent
+func (m *Monitor) ObservePeerTime(peer string, ts time.Time) {
+	if ts.IsZero() {
+		return
+	}
+	now := m.now()
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if _, ok := m.peers[peer]; !ok && len(m.peers) >= _maxPeerSamples {
+		m.prunePeers(now)
+		if len(m.peers) >= _maxPeerSamples {
+			return
+		}
+	}
+	m.peers[peer] = peerSample{offset: ts.Sub(now), at: now}
+}
+
+// NTPOffset returns the offset measured against the ntp servers, false if it's not measured yet
+func (m *Monitor) NTPOffset() (time.Duration, bool) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return m.ntpOffset, m.ntpUpdated
+}
+
+// PeerOffset returns the median offset to the times recently reported by peers, false if there is none
+func (m *Monitor) PeerOffset() (time.Duration, bool) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.prunePeers(m.now())
+	if len(m.peers) == 0 {
+		return 0, false
+	}
+	offsets := make([]time.Duration, 0, len(m.peers))
+	for _, s := range m.peers {
+		offsets = append(offsets, s.offset)
+	}
+	sort.Slice(offsets, func(i, j int) bool { return offsets[i] < offsets[j] })
+	offset := offsets[len(offsets)/2]
+	if len(offsets)%2 == 0 {
+		offset = (offsets[len(offsets)/2-1] + offset) / 2
+	}
+	_clockOffsetMtc.WithLabelValues(_sourcePeer).Set(offset.Seconds())
+	return offset, true
+}
+
+// Offset returns the offset of the local clock, the one measured against ntp servers is preferred
+func (m *Monitor) Offset() (time.Duration, bool) {
+	if offset, ok := m.NTPOffset(); ok {
+		return offset, true
+	}
+	return m.PeerOffset()
+}
+
+// Check returns ErrClockSkew if the offset of the local clock exceeds the tolerated one
+func (m *Monitor) Check() error {
+	offset, ok := m.Offset()
+	if !ok {
+		return nil
+	}
+	if offset > m.cfg.MaxOffset || offset < -m.cfg.MaxOffset {
+		return errors.Wrapf(ErrClockSkew, "offset %s exceeds the tolerated %s", offset, m.cfg.MaxOffset)
+	}
+	return nil
+}
+
+// RefuseProposal returns true if the delegate should not propose blocks while the clock is skewed
+func (m *Monitor) RefuseProposal() bool {
+	return m.cfg.RefuseProposal
+}
+
+func (m *Monitor) update() {
+	var (
+		offsets []time.Duration
+		lastErr error
+	)
+	for _, server := range m.cfg.NTPServers {
+		offset, err := m.query(server, m.cfg.Timeout)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		offsets = append(offsets, offset)
+	}
+	if len(offsets) == 0 {
+		log.L().Warn("Failed to query ntp servers.", zap.Error(lastErr))
+		return
+	}
+	sort.Slice(offsets, func(i, j int) bool { return offsets[i] < offsets[j] })
+	offset := offsets[len(offsets)/2]
+	m.mutex.Lock()
+	m.ntpOffset = offset
+	m.ntpUpdated = true
+	m.mutex.Unlock()
+	_clockOffsetMtc.WithLabelValues(_sourceNTP).Set(offset.Seconds())
+	if err := m.Check(); err != nil {
+		log.L().Warn("Local clock is skewed, the node may miss its consensus rounds.", zap.Error(err))
+	}
+}
+
+func (m *Monitor) prunePeers(now time.Time) {
+	for peer, s := range m.peers {
+		if now.Sub(s.at) > _peerSampleTTL {
+			delete(m.peers, peer)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package clockhealth
+
+import (
+	"bytes"
+	"encoding/binary"
+	"net"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	_ntpPort       = "123"
+	_ntpPacketSize = 48
+	// _ntpEpochOffset is the number of seconds from 1900-01-01 (ntp epoch) to 1970-01-01 (unix epoch)
+	_ntpEpochOffset = 2208988800
+	// _ntpClientHeader is leap indicator 0, version 4 and mode 3 (client)
+	_ntpClientHeader = 0x23
+	_ntpModeServer   = 4
+)
+
+// QueryNTP queries the ntp server with SNTP (RFC 4330), and returns the offset of the local clock to the server's,
+// i.e., a positive offset means the local clock is behind
+func QueryNTP(server string, timeout time.Duration) (time.Duration, error) {
+	if _, _, err := net.SplitHostPort(server); err != nil {
+		server = net.JoinHostPort(server, _ntpPort)
+	}
+	conn, err := net.DialTimeout("udp", server, timeout)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to dial ntp server %s", server)
+	}
+	defer conn.Close()
+	if err := conn.SetDeadline(time.Now().Add(timeout)); err != nil {
+		return 0, err
+	}
+
+	req := make([]byte, _ntpPacketSize)
+	req[0] = _ntpClientHeader
+	t1 := time.Now()
+	// the transmit timestamp is echoed as the originate timestamp in the response
+	copy(req[40:], toNTPTime(t1))
+	if _, err := conn.Write(req); err != nil {
+		return 0, errors.Wrapf(err, "failed to send request to ntp server %s", server)
+	}
+	res := make([]byte, _ntpPacketSize)
+	n, err := conn.Read(res)
+	t4 := time.Now()
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to read response from ntp server %s", server)
+	}
+	if n < _ntpPacketSize {
+		return 0, errors.Errorf("short response of %d bytes from ntp server %s", n, server)
+	}
+	if res[0]&0x07 != _ntpModeServer {
+		return 0, errors.Errorf("invalid mode %d in response from ntp server %s", res[0]&0x07, server)
+	}
+	if res[1] == 0 {
+		return 0, errors.Errorf("kiss-of-death response from ntp server %s", server)
+	}
+	if !bytes.Equal(res[24:32], req[40:48]) {
+		return 0, errors.Errorf("mismatched originate timestamp in response from ntp server %s", server)
+	}
+	t2 := fromNTPTime(res[32:40])
+	t3 := fromNTPTime(res[40:48])
+	return (t2.Sub(t1) + t3.Sub(t4)) / 2, nil
+}
+
+func toNTPTime(t time.Time) []byte {
+	b := make([]byte, 8)
+	nsec := t.UnixNano()
+	sec := uint64(nsec/int64(time.Second)) + _ntpEpochOffset
+	frac := (uint64(nsec%int64(time.Second)) << 32) / uint64(time.Second)
+	binary.BigEndian.PutUint32(b[:4], uint32(sec))
+	binary.BigEndian.PutUint32(b[4:], uint32(frac))
+	return b
+}
+
+func fromNTPTime(b []byte) time.Time {
+	sec := int64(binary.BigEndian.Uint32(b[:4])) - _ntpEpochOffset
+	frac := int64(binary.BigEndian.Uint32(b[4:]))
+	return time.Unix(sec, (frac*int64(time.Second))>>32)
+}
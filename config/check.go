@@ -0,0 +1,179 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package config
+
+import (
+	"os"
+
+	"github.com/pkg/errors"
+	uconfig "go.uber.org/config"
+)
+
+type (
+	// Deprecation describes a deprecated config field
+	Deprecation struct {
+		// Field is the yaml path of the field, e.g. "chain.enableSystemLog"
+		Field string `json:"field"`
+		// Replacement is the yaml path of the field replacing it, empty if the field has no replacement
+		Replacement string `json:"replacement,omitempty"`
+		// Note explains the deprecation
+		Note string `json:"note,omitempty"`
+	}
+
+	// Report is the result of checking the config
+	Report struct {
+		Valid        bool          `json:"valid"`
+		Errors       []string      `json:"errors"`
+		Warnings     []string      `json:"warnings"`
+		Deprecations []Deprecation `json:"deprecations"`
+	}
+)
+
+var (
+	// Deprecations are the deprecated config fields
+	Deprecations = []Deprecation{
+		{
+			Field: "chain.enableSystemLog",
+			Note:  "system log indexer has been removed, the field has no effect",
+		},
+	}
+
+	// Warnings are the checks of config values which are valid but likely to be wrong
+	Warnings = []Validate{
+		WarnProductivityThreshold,
+		WarnActPoolGasLimit,
+	}
+)
+
+// CheckConfig runs all the validations and warnings on the config, and reports the deprecated fields set in the
+// config files. Unlike New, it doesn't stop at the first error
+func CheckConfig(cfg Config) *Report {
+	report := &Report{
+		Errors:   []string{},
+		Warnings: []string{},
+	}
+	for _, validate := range Validates {
+		if err := validate(cfg); err != nil {
+			report.Errors = append(report.Errors, err.Error())
+		}
+	}
+	for _, warn := range Warnings {
+		if err := warn(cfg); err != nil {
+			report.Warnings = append(report.Warnings, err.Error())
+		}
+	}
+	deprecations, err := deprecatedFieldsInUse(_overwritePath, _secretPath)
+	if err != nil {
+		report.Errors = append(report.Errors, err.Error())
+	}
+	report.Deprecations = deprecations
+	report.Valid = len(report.Errors) == 0
+	return report
+}
+
+// deprecatedFieldsInUse returns the deprecated fields set in the config files
+func deprecatedFieldsInUse(paths ...string) ([]Deprecation, error) {
+	opts := []uconfig.YAMLOption{uconfig.Expand(os.LookupEnv)}
+	for _, path := range paths {
+		if path != "" {
+			opts = append(opts, uconfig.File(path))
+		}
+	}
+	inUse := []Deprecation{}
+	if len(opts) == 1 {
+		return inUse, nil
+	}
+	yaml, err := uconfig.NewYAML(opts...)
+	if err != nil {
+		return inUse, errors.Wrap(err, "failed to init config")
+	}
+	for _, d := range Deprecations {
+		if yaml.Get(d.Field).HasValue() {
+			inUse = append(inUse, d)
+		}
+	}
+	return inUse, nil
+}
+
+// ValidateProbation validates the probation configs against the epoch
+func ValidateProbation(cfg Config) error {
+	g := cfg.Genesis
+	if g.ProductivityThreshold > 100 {
+		return errors.Wrapf(ErrInvalidCfg, "productivity threshold %d is greater than 100", g.ProductivityThreshold)
+	}
+	if g.ProbationIntensityRate > 100 {
+		return errors.Wrapf(ErrInvalidCfg, "probation intensity rate %d is greater than 100", g.ProbationIntensityRate)
+	}
+	for _, rate := range g.ProbationIntensitySchedule {
+		if rate > 100 {
+			return errors.Wrapf(ErrInvalidCfg, "probation intensity rate %d in schedule is greater than 100", rate)
+		}
+	}
+	if g.StakeSlashRate > 10000 {
+		return errors.Wrapf(ErrInvalidCfg, "stake slash rate %d is greater than 10000 basis points", g.StakeSlashRate)
+	}
+	if g.ProbationEpochPeriod > g.UnproductiveDelegateMaxCacheSize {
+		return errors.Wrapf(
+			ErrInvalidCfg,
+			"probation epoch period %d is greater than unproductive delegate cache size %d",
+			g.ProbationEpochPeriod,
+			g.UnproductiveDelegateMaxCacheSize,
+		)
+	}
+	if g.NumDelegates > g.NumCandidateDelegates {
+		return errors.Wrapf(
+			ErrInvalidCfg,
+			"number of delegates %d is greater than number of candidate delegates %d",
+			g.NumDelegates,
+			g.NumCandidateDelegates,
+		)
+	}
+	return nil
+}
+
+// ValidateGasLimit validates the action gas limit against the block gas limit
+func ValidateGasLimit(cfg Config) error {
+	if cfg.Genesis.ActionGasLimit > cfg.Genesis.BlockGasLimit {
+		return errors.Wrapf(
+			ErrInvalidCfg,
+			"action gas limit %d is greater than block gas limit %d",
+			cfg.Genesis.ActionGasLimit,
+			cfg.Genesis.BlockGasLimit,
+		)
+	}
+	return nil
+}
+
+// WarnProductivityThreshold warns if a delegate gets probation by missing a single block in an epoch
+func WarnProductivityThreshold(cfg Config) error {
+	g := cfg.Genesis
+	if g.DardanellesNumSubEpochs == 0 || g.ProductivityThreshold == 0 {
+		return nil
+	}
+	// a delegate is expected to produce one block in each sub epoch
+	if maxProductivity := 100 - 100/g.DardanellesNumSubEpochs; g.ProductivityThreshold > maxProductivity {
+		return errors.Errorf(
+			"productivity threshold %d is higher than %d, a delegate missing a single block out of %d gets probation",
+			g.ProductivityThreshold,
+			maxProductivity,
+			g.DardanellesNumSubEpochs,
+		)
+	}
+	return nil
+}
+
+// WarnActPoolGasLimit warns if the actpool cannot hold enough actions to fill a block
+func WarnActPoolGasLimit(cfg Config) error {
+	if cfg.ActPool.MaxGasLimitPerPool < cfg.Genesis.BlockGasLimit {
+		return errors.Errorf(
+			"actpool gas limit %d is less than block gas limit %d",
+			cfg.ActPool.MaxGasLimitPerPool,
+			cfg.Genesis.BlockGasLimit,
+		)
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package config
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateProbation(t *testing.T) {
+	require := require.New(t)
+
+	cfg := Default
+	require.NoError(ValidateProbation(cfg))
+	cfg.Genesis.ProbationEpochPeriod = cfg.Genesis.UnproductiveDelegateMaxCacheSize + 1
+	err := ValidateProbation(cfg)
+	require.Equal(ErrInvalidCfg, errors.Cause(err))
+	require.Contains(err.Error(), "probation epoch period")
+
+	cfg = Default
+	cfg.Genesis.ProbationIntensityRate = 101
+	require.Equal(ErrInvalidCfg, errors.Cause(ValidateProbation(cfg)))
+
+	cfg = Default
+	cfg.Genesis.ProbationIntensitySchedule = []uint32{50, 101}
+	require.Equal(ErrInvalidCfg, errors.Cause(ValidateProbation(cfg)))
+
+	cfg = Default
+	cfg.Genesis.StakeSlashRate = 10001
+	require.Equal(ErrInvalidCfg, errors.Cause(ValidateProbation(cfg)))
+
+	cfg = Default
+	cfg.Genesis.NumDelegates = cfg.Genesis.NumCandidateDelegates + 1
+	require.Equal(ErrInvalidCfg, errors.Cause(ValidateProbation(cfg)))
+
+	cfg = Default
+	cfg.Genesis.ActionGasLimit = cfg.Genesis.BlockGasLimit + 1
+	require.Equal(ErrInvalidCfg, errors.Cause(ValidateGasLimit(cfg)))
+}
+
+func TestWarnings(t *testing.T) {
+	require := require.New(t)
+
+	cfg := Default
+	for _, warn := range Warnings {
+		require.NoError(warn(cfg))
+	}
+	cfg.Genesis.ProductivityThreshold = 99
+	require.Contains(WarnProductivityThreshold(cfg).Error(), "missing a single block")
+	cfg.ActPool.MaxGasLimitPerPool = cfg.Genesis.BlockGasLimit - 1
+	require.Error(WarnActPoolGasLimit(cfg))
+}
+
+func TestCheckConfig(t *testing.T) {
+	require := require.New(t)
+
+	report := CheckConfig(Default)
+	require.True(report.Valid)
+	require.Empty(report.Errors)
+	require.Empty(report.Deprecations)
+
+	cfgStr := `
+chain:
+    enableSystemLog: true
+`
+	require.NoError(makePathAndWriteFile(cfgStr, overwritePath))
+	defer resetPathValues(t, []string{overwritePath})
+
+	cfg := Default
+	cfg.Dispatcher.EventChanSize = 0
+	cfg.Genesis.ProbationIntensityRate = 101
+	cfg.ActPool.MaxGasLimitPerPool = 0
+	report = CheckConfig(cfg)
+	require.False(report.Valid)
+	require.Equal(2, len(report.Errors))
+	require.Equal(1, len(report.Warnings))
+	require.Equal([]Deprecation{Deprecations[0]}, report.Deprecations)
+}
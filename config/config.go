@@ -7,15 +7,19 @@
 package config
 
 import (
+	"context"
 	"crypto/ecdsa"
 	"flag"
+	"fmt"
 	"math/big"
 	"os"
+	"reflect"
 	"strings"
 	"time"
 
 	"github.com/iotexproject/go-p2p"
 	"github.com/iotexproject/go-pkgs/crypto"
+	"github.com/iotexproject/go-pkgs/hash"
 	"github.com/iotexproject/iotex-election/committee"
 	"github.com/pkg/errors"
 	uconfig "go.uber.org/config"
@@ -24,6 +28,7 @@ import (
 	"github.com/iotexproject/iotex-address/address"
 	"github.com/iotexproject/iotex-core/blockchain/genesis"
 	"github.com/iotexproject/iotex-core/pkg/log"
+	"github.com/iotexproject/iotex-core/pkg/secret"
 	"github.com/iotexproject/iotex-core/pkg/unit"
 )
 
@@ -55,9 +60,30 @@ const (
 	NOOPScheme = "NOOP"
 )
 
+const (
+	// FinalityEndorsement means a block is finalized once it is committed with the endorsements of more than 2/3 of
+	// the delegates
+	FinalityEndorsement = "endorsement"
+	// FinalityCheckpoint means a block is finalized once the epoch it belongs to has ended
+	FinalityCheckpoint = "checkpoint"
+)
+
 const (
 	// GatewayPlugin is the plugin of accepting user API requests and serving blockchain data to users
 	GatewayPlugin = iota
+	// APIProxyPlugin is the plugin of running the node as a stateless api gateway in front of a set of full nodes
+	APIProxyPlugin
+)
+
+const (
+	// BloomfilterIndexer is the name of the indexer of the bloom filters of the block logs
+	BloomfilterIndexer = "bloomfilter"
+	// BlockStatsIndexer is the name of the indexer of the block stats
+	BlockStatsIndexer = "blockstats"
+	// CandidateHistoryIndexer is the name of the indexer of the candidate history
+	CandidateHistoryIndexer = "candidatehistory"
+	// StateAccessIndexer is the name of the indexer of the state access of the actions
+	StateAccessIndexer = "stateaccess"
 )
 
 type strs []string
@@ -97,24 +123,27 @@ var (
 			ExternalHost:      "",
 			ExternalPort:      4689,
 			BootstrapNodes:    []string{},
+			FallbackNodes:     []string{},
 			MasterKey:         "",
 			RateLimit:         p2p.DefaultRatelimitConfig,
 			EnableRateLimit:   true,
 			PrivateNetworkPSK: "",
 		},
 		Chain: Chain{
-			ChainDBPath:            "/var/data/chain.db",
-			TrieDBPath:             "/var/data/trie.db",
-			IndexDBPath:            "/var/data/index.db",
-			BloomfilterIndexDBPath: "/var/data/bloomfilter.index.db",
-			CandidateIndexDBPath:   "/var/data/candidate.index.db",
-			StakingIndexDBPath:     "/var/data/staking.index.db",
-			ID:                     1,
-			Address:                "",
-			ProducerPrivKey:        generateRandomKey(SigP256k1),
-			SignatureScheme:        []string{SigP256k1},
-			EmptyGenesis:           false,
-			GravityChainDB:         DB{DbPath: "/var/data/poll.db", NumRetries: 10},
+			ChainDBPath:                 "/var/data/chain.db",
+			TrieDBPath:                  "/var/data/trie.db",
+			IndexDBPath:                 "/var/data/index.db",
+			BloomfilterIndexDBPath:      "/var/data/bloomfilter.index.db",
+			CandidateIndexDBPath:        "/var/data/candidate.index.db",
+			StakingIndexDBPath:          "/var/data/staking.index.db",
+			BlockStatsIndexDBPath:       "/var/data/blockstats.index.db",
+			CandidateHistoryIndexDBPath: "/var/data/candidatehistory.index.db",
+			ID:                          1,
+			Address:                     "",
+			ProducerPrivKey:             generateRandomKey(SigP256k1),
+			SignatureScheme:             []string{SigP256k1},
+			EmptyGenesis:                false,
+			GravityChainDB:              DB{DbPath: "/var/data/poll.db", NumRetries: 10},
 			Committee: committee.Config{
 				GravityChainAPIs: []string{},
 			},
@@ -131,6 +160,10 @@ var (
 			PollInitialCandidatesInterval: 10 * time.Second,
 			StateDBCacheSize:              1000,
 			WorkingSetCacheSize:           20,
+			ActionExecutionBudget:         200 * time.Millisecond,
+			ShadowFork: ShadowFork{
+				Delegates: []genesis.Delegate{},
+			},
 		},
 		ActPool: ActPool{
 			MaxNumActsPerPool:  32000,
@@ -153,8 +186,14 @@ var (
 					EventChanSize:                10000,
 				},
 				ToleratedOvertime: 2 * time.Second,
+				TimestampDrift:    5 * time.Second,
 				Delay:             5 * time.Second,
 				ConsensusDBPath:   "/var/data/consensus.db",
+				AdaptiveTiming: AdaptiveTiming{
+					Enabled:      false,
+					Percentile:   90,
+					MaxExtension: 500 * time.Millisecond,
+				},
 			},
 		},
 		BlockSync: BlockSync{
@@ -164,9 +203,16 @@ var (
 			IntervalSize:          20,
 			MaxRepeat:             3,
 			RepeatDecayStep:       1,
+			StaleTipThreshold:     2 * time.Minute,
 		},
 		Dispatcher: Dispatcher{
-			EventChanSize: 10000,
+			EventChanSize:        10000,
+			ConsensusChanSize:    1000,
+			BlockChanSize:        1000,
+			BlockEnqueueTimeout:  time.Second,
+			ActionChanSize:       10000,
+			ActionDedupCacheSize: 10000,
+			ActionDedupTTL:       time.Minute,
 		},
 		API: API{
 			UseRDS:    false,
@@ -178,6 +224,28 @@ var (
 				Percentile:         60,
 			},
 			RangeQueryLimit: 1000,
+			LogReplayRate:   100,
+			TLS: TLS{
+				AllowedClients: []string{},
+			},
+			ReceiptWebhook: ReceiptWebhook{
+				MaxPending: 10000,
+				TTL:        10 * time.Minute,
+				Timeout:    5 * time.Second,
+				MaxRetries: 3,
+			},
+			Finality: Finality{
+				SafeDepth: 1,
+				Mode:      FinalityEndorsement,
+			},
+			GRPCWeb: GRPCWeb{
+				Port: 0,
+				CORS: CORS{
+					AllowedOrigins: []string{},
+					AllowedHeaders: []string{},
+					MaxAge:         10 * time.Minute,
+				},
+			},
 		},
 		System: System{
 			Active:                true,
@@ -186,6 +254,9 @@ var (
 			HTTPAdminPort:         9009,
 			StartSubChainInterval: 10 * time.Second,
 			SystemLogDBPath:       "/var/data/systemlog.db",
+			HTTPAdminTLS: TLS{
+				AllowedClients: []string{},
+			},
 		},
 		DB: DB{
 			NumRetries:            3,
@@ -203,6 +274,83 @@ var (
 			RangeBloomFilterSize:        1200000,
 			RangeBloomFilterNumHash:     8,
 		},
+		APIProxy: APIProxy{
+			Endpoints:           []string{},
+			HealthCheckInterval: 5 * time.Second,
+			MaxHeightLag:        3,
+			CacheSize:           1000,
+		},
+		EpochEvent: EpochEvent{
+			WebhookURLs: []string{},
+			Timeout:     10 * time.Second,
+			MaxRetries:  3,
+		},
+		Exporter: Exporter{
+			Type:          "",
+			TopicPrefix:   "iotex",
+			Encoding:      "protobuf",
+			DBPath:        "/var/data/exporter.db",
+			Timeout:       10 * time.Second,
+			RetryInterval: 5 * time.Second,
+		},
+		SQLIndexer: SQLIndexer{
+			Driver:        "",
+			RetryInterval: 5 * time.Second,
+		},
+		Archive: Archive{
+			Type:             "",
+			Region:           "us-east-1",
+			SegmentSize:      10000,
+			SnapshotInterval: 100000,
+			Timeout:          10 * time.Minute,
+			RetryInterval:    time.Minute,
+		},
+		StateSnapshot: StateSnapshot{
+			Dir:       "",
+			Interval:  10000,
+			Retention: 3,
+		},
+		Updater: Updater{
+			CheckInterval:  time.Hour,
+			Timeout:        10 * time.Minute,
+			StageBinary:    false,
+			SwapLeadBlocks: 720,
+		},
+		Faucet: Faucet{
+			Port:            0,
+			Amount:          "10000000000000000000",
+			GasLimit:        10000,
+			GasPrice:        "1000000000000",
+			AddressInterval: 24 * time.Hour,
+			IPInterval:      time.Hour,
+			Tokens:          []string{},
+		},
+		ClockHealth: ClockHealth{
+			NTPServers:     []string{},
+			Interval:       10 * time.Minute,
+			Timeout:        5 * time.Second,
+			MaxOffset:      time.Second,
+			RefuseProposal: false,
+		},
+		SLAReport: SLAReport{
+			DBPath: "",
+			Port:   0,
+		},
+		Dashboard: Dashboard{
+			Port:      0,
+			NumBlocks: 20,
+		},
+		Rosetta: Rosetta{
+			Port:    0,
+			Network: "mainnet",
+		},
+		GovernanceReport: GovernanceReport{
+			Store: Archive{
+				Type:    "",
+				Region:  "us-east-1",
+				Timeout: time.Minute,
+			},
+		},
 		Genesis: genesis.Default,
 	}
 
@@ -217,6 +365,24 @@ var (
 		ValidateAPI,
 		ValidateActPool,
 		ValidateForkHeights,
+		ValidateProbation,
+		ValidateGasLimit,
+		ValidateAPIProxy,
+		ValidateExporter,
+		ValidateSQLIndexer,
+		ValidateDisabledIndexers,
+		ValidateArchive,
+		ValidateStateSnapshot,
+		ValidateUpdater,
+		ValidateFaucet,
+		ValidateClockHealth,
+		ValidateTLS,
+		ValidateShadowFork,
+		ValidateTrustedCheckpoint,
+		ValidateContractDeployerAllowlist,
+		ValidateMinBalanceReservation,
+		ValidateSanctionGovernors,
+		ValidateParameterGovernors,
 	}
 )
 
@@ -235,23 +401,36 @@ type (
 		RateLimit         p2p.RateLimitConfig `yaml:"rateLimit"`
 		EnableRateLimit   bool                `yaml:"enableRateLimit"`
 		PrivateNetworkPSK string              `yaml:"privateNetworkPSK"`
+		// FallbackNodes are dialed besides the bootstrap nodes when all the peers are stale
+		FallbackNodes []string `yaml:"fallbackNodes"`
+		// EnableLatencyReport enables measuring the propagation latency of the block and consensus messages per peer
+		EnableLatencyReport bool `yaml:"enableLatencyReport"`
+		// MaxPeersPerIP is the max number of peers allowed to send unicast messages from the same IP, 0 means unlimited
+		MaxPeersPerIP int `yaml:"maxPeersPerIP"`
+		// NewPeerRatePerIP is the max number of new peers per second allowed from the same IP, 0 means unlimited
+		NewPeerRatePerIP float64 `yaml:"newPeerRatePerIP"`
+		// MaxMessageSize is the max size in bytes of an inbound message, larger ones are dropped before decoding, 0
+		// means unlimited
+		MaxMessageSize int `yaml:"maxMessageSize"`
 	}
 
 	// Chain is the config struct for blockchain package
 	Chain struct {
-		ChainDBPath            string           `yaml:"chainDBPath"`
-		TrieDBPath             string           `yaml:"trieDBPath"`
-		IndexDBPath            string           `yaml:"indexDBPath"`
-		BloomfilterIndexDBPath string           `yaml:"bloomfilterIndexDBPath"`
-		CandidateIndexDBPath   string           `yaml:"candidateIndexDBPath"`
-		StakingIndexDBPath     string           `yaml:"stakingIndexDBPath"`
-		ID                     uint32           `yaml:"id"`
-		Address                string           `yaml:"address"`
-		ProducerPrivKey        string           `yaml:"producerPrivKey"`
-		SignatureScheme        []string         `yaml:"signatureScheme"`
-		EmptyGenesis           bool             `yaml:"emptyGenesis"`
-		GravityChainDB         DB               `yaml:"gravityChainDB"`
-		Committee              committee.Config `yaml:"committee"`
+		ChainDBPath                 string           `yaml:"chainDBPath"`
+		TrieDBPath                  string           `yaml:"trieDBPath"`
+		IndexDBPath                 string           `yaml:"indexDBPath"`
+		BloomfilterIndexDBPath      string           `yaml:"bloomfilterIndexDBPath"`
+		CandidateIndexDBPath        string           `yaml:"candidateIndexDBPath"`
+		StakingIndexDBPath          string           `yaml:"stakingIndexDBPath"`
+		BlockStatsIndexDBPath       string           `yaml:"blockStatsIndexDBPath"`
+		CandidateHistoryIndexDBPath string           `yaml:"candidateHistoryIndexDBPath"`
+		ID                          uint32           `yaml:"id"`
+		Address                     string           `yaml:"address"`
+		ProducerPrivKey             string           `yaml:"producerPrivKey"`
+		SignatureScheme             []string         `yaml:"signatureScheme"`
+		EmptyGenesis                bool             `yaml:"emptyGenesis"`
+		GravityChainDB              DB               `yaml:"gravityChainDB"`
+		Committee                   committee.Config `yaml:"committee"`
 
 		EnableTrielessStateDB bool `yaml:"enableTrielessStateDB"`
 		// EnableStateDBCaching enables cachedStateDBOption
@@ -278,6 +457,48 @@ type (
 		StateDBCacheSize int `yaml:"stateDBCacheSize"`
 		// WorkingSetCacheSize is the max size of workingset cache in state factory
 		WorkingSetCacheSize uint64 `yaml:"workingSetCacheSize"`
+		// RemoteChainDBEndpoint is the endpoint of a shared chain-data service. If set, block data is read from the
+		// remote service instead of the local chain db
+		RemoteChainDBEndpoint string `yaml:"remoteChainDBEndpoint"`
+		// ActionExecutionBudget is the soft limit of wall-clock time to run an action, actions exceeding it are logged.
+		// 0 means disabled
+		ActionExecutionBudget time.Duration `yaml:"actionExecutionBudget"`
+		// ParallelExecutionWorkers is the number of workers to run the executions of a block in parallel when
+		// validating it. 0 or 1 means serial execution
+		ParallelExecutionWorkers int `yaml:"parallelExecutionWorkers"`
+		// ParticipationDBPath is the path of db storing the endorsement participation of the delegates per epoch.
+		// Endorsement participation tracking is disabled if empty
+		ParticipationDBPath string `yaml:"participationDBPath"`
+		// StateAccessIndexDBPath is the path of db storing the keys of the states read and written by each action.
+		// Recording the state access is disabled if empty
+		StateAccessIndexDBPath string `yaml:"stateAccessIndexDBPath"`
+		// EnablePollShadowRead additionally reads the candidates via the other path of the Easter switch and compares
+		// them with the candidates read, the differences are only logged and counted
+		EnablePollShadowRead bool `yaml:"enablePollShadowRead"`
+		// ShadowFork replaces the delegates after the fork height with the local ones, to run a devnet forked from the
+		// state of another chain, e.g., the mainnet
+		ShadowFork ShadowFork `yaml:"shadowFork"`
+		// DisabledIndexers are the names of the indexers not to build on commit, which saves the disk and the commit
+		// latency on the delegates serving no user requests. A disabled indexer could be materialized later on demand
+		DisabledIndexers []string `yaml:"disabledIndexers"`
+		// TrustedCheckpoint is the block trusted by the operator, below which the blocks are synced without execution
+		TrustedCheckpoint Checkpoint `yaml:"trustedCheckpoint"`
+	}
+
+	// ShadowFork is the config of the devnet forked from the state of another chain, which is enabled if Height is set
+	ShadowFork struct {
+		Height    uint64             `yaml:"height"`
+		Delegates []genesis.Delegate `yaml:"delegates"`
+	}
+
+	// Checkpoint is a block trusted by the operator. The blocks up to the checkpoint are synced by verifying the headers
+	// and endorsements only, and the state at the checkpoint is restored from a snapshot instead of executing the
+	// actions. The receipts of these blocks are not available, and their receipt roots are not verified. Checkpoint
+	// sync is disabled if Height is 0
+	Checkpoint struct {
+		Height uint64 `yaml:"height"`
+		// Hash is the hex encoded hash of the block at the height
+		Hash string `yaml:"hash"`
 	}
 
 	// Consensus is the config struct for consensus package
@@ -297,14 +518,35 @@ type (
 		MaxRepeat int `yaml:"maxRepeat"`
 		// RepeatDecayStep is the step for repeat number decreasing by 1
 		RepeatDecayStep int `yaml:"repeatDecayStep"`
+		// StaleTipThreshold is the duration without any new block committed or heard from the peers, after which the
+		// peers are taken as stale and the node re-bootstraps. 0 disables the detection
+		StaleTipThreshold time.Duration `yaml:"staleTipThreshold"`
 	}
 
 	// RollDPoS is the config struct for RollDPoS consensus package
 	RollDPoS struct {
 		FSM               ConsensusTiming `yaml:"fsm"`
 		ToleratedOvertime time.Duration   `yaml:"toleratedOvertime"`
-		Delay             time.Duration   `yaml:"delay"`
-		ConsensusDBPath   string          `yaml:"consensusDBPath"`
+		// TimestampDrift is the tolerated drift of a proposed block timestamp ahead of the local clock, 0 disables the check
+		TimestampDrift  time.Duration `yaml:"timestampDrift"`
+		Delay           time.Duration `yaml:"delay"`
+		ConsensusDBPath string        `yaml:"consensusDBPath"`
+		// AdaptiveTiming is the config to extend the endorsement ttls by the measured endorsement latency
+		AdaptiveTiming AdaptiveTiming `yaml:"adaptiveTiming"`
+		// SignLedgerPath is the path of the ledger of the last signed height and round, which keeps the delegate from
+		// signing conflicting messages after a restart or a failover. A lockfile is created next to it. The ledger is
+		// disabled if empty
+		SignLedgerPath string `yaml:"signLedgerPath"`
+	}
+
+	// AdaptiveTiming defines how the endorsement ttls of the fsm are extended, so that the delegates far apart do not
+	// give up the rounds during network-wide latency events. The endorsements arriving after the ttls of their phases
+	// are measured in each epoch, and the ttls of the next epoch are extended to accept Percentile of them. The
+	// extension eats into the tolerated overtime of the next round, so three times MaxExtension must fit in it
+	AdaptiveTiming struct {
+		Enabled      bool          `yaml:"enabled"`
+		Percentile   int           `yaml:"percentile"`
+		MaxExtension time.Duration `yaml:"maxExtension"`
 	}
 
 	// ConsensusTiming defines a set of time durations used in fsm and event queue size
@@ -320,7 +562,21 @@ type (
 
 	// Dispatcher is the dispatcher config
 	Dispatcher struct {
+		// EventChanSize is the size of the block sync request queue, and of the queues below if they are 0
 		EventChanSize uint `yaml:"eventChanSize"`
+		// ConsensusChanSize is the size of the consensus message queue, the oldest message is dropped if it is full
+		ConsensusChanSize uint `yaml:"consensusChanSize"`
+		// BlockChanSize is the size of the block message queue
+		BlockChanSize uint `yaml:"blockChanSize"`
+		// BlockEnqueueTimeout is the duration a block message waits for the full block queue before dropped
+		BlockEnqueueTimeout time.Duration `yaml:"blockEnqueueTimeout"`
+		// ActionChanSize is the size of the action message queue, the new message is dropped if it is full
+		ActionChanSize uint `yaml:"actionChanSize"`
+		// ActionDedupCacheSize is the max number of broadcast action hashes kept to drop the duplicate actions, 0
+		// means disabled
+		ActionDedupCacheSize int `yaml:"actionDedupCacheSize"`
+		// ActionDedupTTL is the duration within which an action seen again is dropped as duplicate
+		ActionDedupTTL time.Duration `yaml:"actionDedupTTL"`
 		// TODO: explorer dependency deleted at #1085, need to revive by migrating to api
 	}
 
@@ -331,6 +587,81 @@ type (
 		TpsWindow       int        `yaml:"tpsWindow"`
 		GasStation      GasStation `yaml:"gasStation"`
 		RangeQueryLimit uint64     `yaml:"rangeQueryLimit"`
+		// LogReplayRate is the max number of blocks replayed per second by ReplayLogs, 0 means unlimited
+		LogReplayRate int `yaml:"logReplayRate"`
+		// RawBlocksMaxBytes is the max size of the blocks returned by a GetRawBlocks call, or the receipts returned by a
+		// GetReceiptsByBlocks call, which returns fewer blocks than requested once the size is exceeded. 0 means unlimited
+		RawBlocksMaxBytes int `yaml:"rawBlocksMaxBytes"`
+		// TLS is the config to serve the api with TLS
+		TLS TLS `yaml:"tls"`
+		// ReceiptWebhook is the config to notify the receipts of the submitted actions
+		ReceiptWebhook ReceiptWebhook `yaml:"receiptWebhook"`
+		// Finality is the config to resolve the "safe" and "finalized" block tags
+		Finality Finality `yaml:"finality"`
+		// GRPCWeb is the config to serve the api to browsers by grpc-web
+		GRPCWeb GRPCWeb `yaml:"grpcWeb"`
+	}
+
+	// GRPCWeb is the config to serve the api by grpc-web on a separate port, so that the browser dapps could call the
+	// api directly without a proxy. It is disabled if Port is 0, and served with the TLS config of the api if any
+	GRPCWeb struct {
+		Port int  `yaml:"port"`
+		CORS CORS `yaml:"cors"`
+	}
+
+	// CORS is the policy of the cross-origin requests from browsers
+	CORS struct {
+		// AllowedOrigins is the allowlist of the origins, where "*" allows any origin and "https://*.example.com"
+		// allows the subdomains of example.com. No cross-origin request is allowed if empty
+		AllowedOrigins []string `yaml:"allowedOrigins"`
+		// AllowedHeaders are the request headers allowed in addition to the ones of grpc-web
+		AllowedHeaders []string `yaml:"allowedHeaders"`
+		// AllowCredentials allows the requests with cookies or authorization headers
+		AllowCredentials bool `yaml:"allowCredentials"`
+		// MaxAge is how long the browsers could cache the result of a preflight request
+		MaxAge time.Duration `yaml:"maxAge"`
+	}
+
+	// Finality defines the blocks the "safe" and "finalized" block tags are resolved to. The safe block is SafeDepth
+	// blocks below the tip. The finalized block is the highest block committed with the endorsements of more than 2/3
+	// of the delegates in "endorsement" mode, or the last block of the previous epoch in "checkpoint" mode
+	Finality struct {
+		SafeDepth uint64 `yaml:"safeDepth"`
+		Mode      string `yaml:"mode"`
+	}
+
+	// ReceiptWebhook is the config to notify the receipts of the submitted actions by webhooks or long polling
+	ReceiptWebhook struct {
+		// MaxPending is the max number of actions waited for receipts, 0 means disabled
+		MaxPending int `yaml:"maxPending"`
+		// TTL is the time to wait for the receipt before the webhook is notified of the timeout
+		TTL time.Duration `yaml:"ttl"`
+		// Timeout is the timeout of calling the webhook
+		Timeout time.Duration `yaml:"timeout"`
+		// MaxRetries is the max number of retries if the webhook fails
+		MaxRetries int `yaml:"maxRetries"`
+	}
+
+	// TLS is the config to serve with TLS, which is enabled if CertFile is set. Client certificates signed by the CA
+	// in ClientCAFile are required if it is set, i.e., mutual TLS
+	TLS struct {
+		CertFile     string `yaml:"certFile"`
+		KeyFile      string `yaml:"keyFile"`
+		ClientCAFile string `yaml:"clientCAFile"`
+		// AllowedClients is the allowlist of the common names or DNS names of the client certificates, any client
+		// certificate signed by the CA is accepted if empty
+		AllowedClients []string `yaml:"allowedClients"`
+		// ReloadInterval is the interval to reload the files for certificate rotation, 0 means never reloaded
+		ReloadInterval time.Duration `yaml:"reloadInterval"`
+	}
+
+	// ClientTLS is the config to connect to a server with TLS, which is enabled if CAFile or CertFile is set. The
+	// client certificate is presented if CertFile is set, i.e., mutual TLS
+	ClientTLS struct {
+		CAFile     string `yaml:"caFile"`
+		CertFile   string `yaml:"certFile"`
+		KeyFile    string `yaml:"keyFile"`
+		ServerName string `yaml:"serverName"`
 	}
 
 	// GasStation is the gas station config
@@ -351,6 +682,8 @@ type (
 		HTTPStatsPort         int           `yaml:"httpStatsPort"`
 		StartSubChainInterval time.Duration `yaml:"startSubChainInterval"`
 		SystemLogDBPath       string        `yaml:"systemLogDBPath"`
+		// HTTPAdminTLS is the config to serve the http admin endpoints with TLS
+		HTTPAdminTLS TLS `yaml:"httpAdminTLS"`
 	}
 
 	// ActPool is the actpool config
@@ -402,22 +735,218 @@ type (
 		RangeBloomFilterNumHash uint64 `yaml:"rangeBloomFilterNumHash"`
 	}
 
+	// EpochEvent is the config for publishing epoch transition events to external systems
+	EpochEvent struct {
+		// WebhookURLs are the endpoints the events are posted to in json, no event is published if empty
+		WebhookURLs []string `yaml:"webhookURLs"`
+		// Timeout is the timeout of each publishing attempt
+		Timeout time.Duration `yaml:"timeout"`
+		// MaxRetries is the max number of retries after a failed publishing attempt
+		MaxRetries int `yaml:"maxRetries"`
+	}
+
+	// Exporter is the config for exporting blocks, actions and receipts to a message queue
+	Exporter struct {
+		// Type is the type of message queue, only "nats" is supported. Exporter is disabled if empty
+		Type string `yaml:"type"`
+		// Endpoint is the address of the message queue server
+		Endpoint string `yaml:"endpoint"`
+		// TopicPrefix is the prefix of the topics, messages are published to <prefix>.blocks/actions/receipts
+		TopicPrefix string `yaml:"topicPrefix"`
+		// Encoding is the encoding of messages, "protobuf" or "json"
+		Encoding string `yaml:"encoding"`
+		// DBPath is the path of db storing the exported height
+		DBPath string `yaml:"dbPath"`
+		// Timeout is the timeout of connecting to and waiting for acknowledgement from the server
+		Timeout time.Duration `yaml:"timeout"`
+		// RetryInterval is the interval to retry after exporting fails
+		RetryInterval time.Duration `yaml:"retryInterval"`
+	}
+
+	// SQLIndexer is the config for mirroring chain data into a sql database for analytics
+	SQLIndexer struct {
+		// Driver is the sql driver, "postgres" or "sqlite3". SQL indexer is disabled if empty
+		Driver string `yaml:"driver"`
+		// ConnectionString is the data source name of the database
+		ConnectionString string `yaml:"connectionString"`
+		// RetryInterval is the interval to retry after indexing fails
+		RetryInterval time.Duration `yaml:"retryInterval"`
+	}
+
+	// Archive is the config for uploading blocks and state snapshots to object storage for disaster recovery
+	Archive struct {
+		// Type is the type of object storage, "file" or "s3". Archive is disabled if empty
+		Type string `yaml:"type"`
+		// Endpoint is the url of s3 compatible storage, e.g. https://s3.amazonaws.com, or https://storage.googleapis.com
+		// for gcs with hmac keys
+		Endpoint string `yaml:"endpoint"`
+		// Region is the region of the bucket, used in the request signature
+		Region string `yaml:"region"`
+		// Bucket is the name of the bucket
+		Bucket string `yaml:"bucket"`
+		// AccessKey is the access key id of the bucket
+		AccessKey string `yaml:"accessKey"`
+		// SecretKey is the secret access key of the bucket
+		SecretKey string `yaml:"secretKey"`
+		// Path is the directory of "file" storage, or the key prefix in the bucket of "s3" storage
+		Path string `yaml:"path"`
+		// SegmentSize is the number of blocks in an uploaded segment
+		SegmentSize uint64 `yaml:"segmentSize"`
+		// SnapshotInterval is the number of blocks between two state snapshots. 0 means no snapshot
+		SnapshotInterval uint64 `yaml:"snapshotInterval"`
+		// Timeout is the timeout of uploading or downloading an object
+		Timeout time.Duration `yaml:"timeout"`
+		// RetryInterval is the interval to retry after uploading fails
+		RetryInterval time.Duration `yaml:"retryInterval"`
+	}
+
+	// StateSnapshot is the config for taking local snapshots of the state db, which the node rolls back to after a
+	// faulty restart instead of resyncing
+	StateSnapshot struct {
+		// Dir is the directory of the snapshots. State snapshot is disabled if empty
+		Dir string `yaml:"dir"`
+		// Interval is the number of blocks between two snapshots
+		Interval uint64 `yaml:"interval"`
+		// Retention is the number of the latest snapshots kept, the older ones are removed
+		Retention int `yaml:"retention"`
+	}
+
+	// Updater is the config for checking the release manifest and staging the new release
+	Updater struct {
+		// ManifestURL is the url of the signed release manifest. Updater is disabled if empty
+		ManifestURL string `yaml:"manifestURL"`
+		// PublicKey is the hex encoded public key of the release key signing the manifest
+		PublicKey string `yaml:"publicKey"`
+		// CheckInterval is the interval to check the release manifest
+		CheckInterval time.Duration `yaml:"checkInterval"`
+		// Timeout is the timeout of downloading the manifest or binary
+		Timeout time.Duration `yaml:"timeout"`
+		// StageBinary enables downloading the new release and swapping the executable before the activation height
+		StageBinary bool `yaml:"stageBinary"`
+		// SwapLeadBlocks is the number of blocks before the activation height to swap the executable
+		SwapLeadBlocks uint64 `yaml:"swapLeadBlocks"`
+	}
+
+	// Faucet is the config for serving tokens from a funded account, it's meant for testnet nodes
+	Faucet struct {
+		// Port is the port of the faucet http endpoint. Faucet is disabled if 0
+		Port int `yaml:"port"`
+		// PrivateKey is the hex encoded private key of the funded account
+		PrivateKey string `yaml:"privateKey"`
+		// Amount is the amount in rau sent per claim
+		Amount string `yaml:"amount"`
+		// GasLimit is the gas limit of the transfer
+		GasLimit uint64 `yaml:"gasLimit"`
+		// GasPrice is the gas price in rau of the transfer
+		GasPrice string `yaml:"gasPrice"`
+		// AddressInterval is the min interval between two claims to the same address
+		AddressInterval time.Duration `yaml:"addressInterval"`
+		// IPInterval is the min interval between two claims from the same ip
+		IPInterval time.Duration `yaml:"ipInterval"`
+		// Tokens are the access tokens, a claim has to carry one of them if not empty
+		Tokens []string `yaml:"tokens"`
+		// CaptchaVerifyURL is the siteverify url of recaptcha or hcaptcha, a claim has to carry a captcha response if set
+		CaptchaVerifyURL string `yaml:"captchaVerifyURL"`
+		// CaptchaSecret is the secret key of the site verifying the captcha response
+		CaptchaSecret string `yaml:"captchaSecret"`
+		// TrustForwardedFor takes the client ip from the X-Forwarded-For header, set it only if the faucet is behind a
+		// reverse proxy
+		TrustForwardedFor bool `yaml:"trustForwardedFor"`
+	}
+
+	// Dashboard is the config for the embedded web dashboard of the chain, it's meant for devnets and private chains
+	Dashboard struct {
+		// Port is the port of the dashboard http endpoint. Dashboard is disabled if 0
+		Port int `yaml:"port"`
+		// NumBlocks is the number of the recent blocks shown on the dashboard
+		NumBlocks uint64 `yaml:"numBlocks"`
+	}
+
+	// Rosetta is the config for the Rosetta data and construction api, which exchanges integrate the chain with
+	Rosetta struct {
+		// Port is the port of the Rosetta http endpoint. Rosetta api is disabled if 0
+		Port int `yaml:"port"`
+		// Network is the network name in the network identifier of the Rosetta api
+		Network string `yaml:"network"`
+	}
+
+	// ClockHealth is the config for monitoring the offset of the local clock
+	ClockHealth struct {
+		// NTPServers are the ntp servers to measure the clock offset against, e.g. pool.ntp.org. Only peer reported
+		// times are used if empty, which is the default
+		NTPServers []string `yaml:"ntpServers"`
+		// Interval is the interval to query the ntp servers
+		Interval time.Duration `yaml:"interval"`
+		// Timeout is the timeout of querying a ntp server
+		Timeout time.Duration `yaml:"timeout"`
+		// MaxOffset is the max tolerated offset of the local clock
+		MaxOffset time.Duration `yaml:"maxOffset"`
+		// RefuseProposal makes the delegate skip proposing blocks while the clock offset exceeds MaxOffset, otherwise
+		// only a warning is logged
+		RefuseProposal bool `yaml:"refuseProposal"`
+	}
+
+	// SLAReport is the config for generating the productivity report of the local delegate at the end of each epoch
+	SLAReport struct {
+		// DBPath is the path of db storing the reports. SLA report is disabled if empty
+		DBPath string `yaml:"dbPath"`
+		// Port is the port of the http endpoint serving the reports, the reports are not served if 0
+		Port int `yaml:"port"`
+	}
+
+	// GovernanceReport is the config for writing the signed governance report at the end of each epoch
+	GovernanceReport struct {
+		// Store is the object storage of the reports, where only the storage fields are used. Governance report is
+		// disabled if the type of the store is empty
+		Store Archive `yaml:"store"`
+	}
+
+	// APIProxy is the config for running the node as a stateless api gateway
+	APIProxy struct {
+		// Endpoints are the api endpoints of the upstream full nodes
+		Endpoints []string `yaml:"endpoints"`
+		// HealthCheckInterval is the interval to check the health of upstream nodes
+		HealthCheckInterval time.Duration `yaml:"healthCheckInterval"`
+		// MaxHeightLag is the max number of blocks an upstream node could fall behind the highest one and still serve
+		MaxHeightLag uint64 `yaml:"maxHeightLag"`
+		// CacheSize is the max number of immutable query responses kept in the LRU cache. 0 means disabled
+		CacheSize int `yaml:"cacheSize"`
+		// UpstreamTLS is the config to connect to the upstream nodes with TLS
+		UpstreamTLS ClientTLS `yaml:"upstreamTLS"`
+	}
+
 	// Config is the root config struct, each package's config should be put as its sub struct
 	Config struct {
-		Plugins    map[int]interface{}         `ymal:"plugins"`
-		Network    Network                     `yaml:"network"`
-		Chain      Chain                       `yaml:"chain"`
-		ActPool    ActPool                     `yaml:"actPool"`
-		Consensus  Consensus                   `yaml:"consensus"`
-		BlockSync  BlockSync                   `yaml:"blockSync"`
-		Dispatcher Dispatcher                  `yaml:"dispatcher"`
-		API        API                         `yaml:"api"`
-		System     System                      `yaml:"system"`
-		DB         DB                          `yaml:"db"`
-		Indexer    Indexer                     `yaml:"indexer"`
-		Log        log.GlobalConfig            `yaml:"log"`
-		SubLogs    map[string]log.GlobalConfig `yaml:"subLogs"`
-		Genesis    genesis.Genesis             `yaml:"genesis"`
+		// Profile is the preset of the node resources, "delegate", "fullnode", "gateway" or "archive". The preset tunes
+		// the default config, and the values set in the config files take precedence over it
+		Profile          string                      `yaml:"profile"`
+		Plugins          map[int]interface{}         `ymal:"plugins"`
+		Network          Network                     `yaml:"network"`
+		Chain            Chain                       `yaml:"chain"`
+		ActPool          ActPool                     `yaml:"actPool"`
+		Consensus        Consensus                   `yaml:"consensus"`
+		BlockSync        BlockSync                   `yaml:"blockSync"`
+		Dispatcher       Dispatcher                  `yaml:"dispatcher"`
+		API              API                         `yaml:"api"`
+		System           System                      `yaml:"system"`
+		DB               DB                          `yaml:"db"`
+		Indexer          Indexer                     `yaml:"indexer"`
+		APIProxy         APIProxy                    `yaml:"apiProxy"`
+		EpochEvent       EpochEvent                  `yaml:"epochEvent"`
+		Exporter         Exporter                    `yaml:"exporter"`
+		SQLIndexer       SQLIndexer                  `yaml:"sqlIndexer"`
+		Archive          Archive                     `yaml:"archive"`
+		StateSnapshot    StateSnapshot               `yaml:"stateSnapshot"`
+		Updater          Updater                     `yaml:"updater"`
+		Faucet           Faucet                      `yaml:"faucet"`
+		Dashboard        Dashboard                   `yaml:"dashboard"`
+		Rosetta          Rosetta                     `yaml:"rosetta"`
+		ClockHealth      ClockHealth                 `yaml:"clockHealth"`
+		SLAReport        SLAReport                   `yaml:"slaReport"`
+		GovernanceReport GovernanceReport            `yaml:"governanceReport"`
+		Log              log.GlobalConfig            `yaml:"log"`
+		SubLogs          map[string]log.GlobalConfig `yaml:"subLogs"`
+		Genesis          genesis.Genesis             `yaml:"genesis"`
 	}
 
 	// Validate is the interface of validating the config
@@ -446,11 +975,29 @@ func New(validates ...Validate) (Config, error) {
 	if err != nil {
 		return Config{}, errors.Wrap(err, "failed to init config")
 	}
+	var profile string
+	if err := yaml.Get("profile").Populate(&profile); err != nil {
+		return Config{}, errors.Wrap(err, "failed to unmarshal profile")
+	}
+	if profile != "" {
+		// load the config files again on top of the preset of the profile
+		base, err := applyProfile(Default, profile)
+		if err != nil {
+			return Config{}, err
+		}
+		opts[0] = uconfig.Static(base)
+		if yaml, err = uconfig.NewYAML(opts...); err != nil {
+			return Config{}, errors.Wrap(err, "failed to init config")
+		}
+	}
 
 	var cfg Config
 	if err := yaml.Get(uconfig.Root).Populate(&cfg); err != nil {
 		return Config{}, errors.Wrap(err, "failed to unmarshal YAML config to struct")
 	}
+	if err := resolveSecrets(reflect.ValueOf(&cfg).Elem(), ""); err != nil {
+		return Config{}, err
+	}
 
 	// set network master key to private key
 	if cfg.Network.MasterKey == "" {
@@ -458,10 +1005,15 @@ func New(validates ...Validate) (Config, error) {
 	}
 
 	// set plugins
+	if gatewayProfile(cfg.Profile) {
+		cfg.Plugins[GatewayPlugin] = nil
+	}
 	for _, plugin := range _plugins {
 		switch strings.ToLower(plugin) {
 		case "gateway":
 			cfg.Plugins[GatewayPlugin] = nil
+		case "apiproxy":
+			cfg.Plugins[APIProxyPlugin] = nil
 		default:
 			return Config{}, errors.Errorf("Plugin %s is not supported", plugin)
 		}
@@ -479,6 +1031,47 @@ func New(validates ...Validate) (Config, error) {
 	return cfg, nil
 }
 
+// resolveSecrets replaces the secret references in string fields with the secrets, e.g. a producer private key of
+// "vault://secret/data/iotex#producerKey"
+func resolveSecrets(v reflect.Value, path string) error {
+	switch v.Kind() {
+	case reflect.String:
+		if !secret.IsReference(v.String()) {
+			return nil
+		}
+		s, err := secret.Resolve(context.Background(), v.String())
+		if err != nil {
+			return errors.Wrapf(err, "failed to resolve config %s", path)
+		}
+		v.SetString(s)
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < v.NumField(); i++ {
+			if t.Field(i).PkgPath != "" {
+				// unexported
+				continue
+			}
+			name := strings.Split(t.Field(i).Tag.Get("yaml"), ",")[0]
+			if name == "" {
+				name = t.Field(i).Name
+			}
+			if path != "" {
+				name = path + "." + name
+			}
+			if err := resolveSecrets(v.Field(i), name); err != nil {
+				return err
+			}
+		}
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			if err := resolveSecrets(v.Index(i), fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 // NewSub create config for sub chain.
 func NewSub(validates ...Validate) (Config, error) {
 	if _subChainPath == "" {
@@ -500,6 +1093,9 @@ func NewSub(validates ...Validate) (Config, error) {
 	if err := yaml.Get(uconfig.Root).Populate(&cfg); err != nil {
 		return Config{}, errors.Wrap(err, "failed to unmarshal YAML config to struct")
 	}
+	if err := resolveSecrets(reflect.ValueOf(&cfg).Elem(), ""); err != nil {
+		return Config{}, err
+	}
 
 	// By default, the config needs to pass all the validation
 	if len(validates) == 0 {
@@ -586,6 +1182,16 @@ func (ap ActPool) MinGasPrice() *big.Int {
 	return mgp
 }
 
+// IndexerDisabled returns true if the indexer of the name is disabled
+func (c Chain) IndexerDisabled(name string) bool {
+	for _, n := range c.DisabledIndexers {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
 // ValidateDispatcher validates the dispatcher configs
 func ValidateDispatcher(cfg Config) error {
 	if cfg.Dispatcher.EventChanSize <= 0 {
@@ -604,6 +1210,17 @@ func ValidateRollDPoS(cfg Config) error {
 	if fsm.EventChanSize <= 0 {
 		return errors.Wrap(ErrInvalidCfg, "roll-DPoS event chan size should be greater than 0")
 	}
+	if rollDPoS.TimestampDrift < 0 {
+		return errors.Wrap(ErrInvalidCfg, "roll-DPoS timestamp drift should not be negative")
+	}
+	if at := rollDPoS.AdaptiveTiming; at.Enabled {
+		if at.Percentile <= 0 || at.Percentile > 100 {
+			return errors.Wrap(ErrInvalidCfg, "roll-DPoS adaptive timing percentile should be in (0, 100]")
+		}
+		if at.MaxExtension < 0 || 3*at.MaxExtension > rollDPoS.ToleratedOvertime {
+			return errors.Wrap(ErrInvalidCfg, "roll-DPoS adaptive timing extensions should fit in the tolerated overtime")
+		}
+	}
 	return nil
 }
 
@@ -621,6 +1238,126 @@ func ValidateAPI(cfg Config) error {
 	if cfg.API.TpsWindow <= 0 {
 		return errors.Wrap(ErrInvalidCfg, "tps window is not a positive integer when the api is enabled")
 	}
+	switch cfg.API.Finality.Mode {
+	case FinalityEndorsement, FinalityCheckpoint:
+	default:
+		return errors.Wrapf(ErrInvalidCfg, "unknown finality mode %s", cfg.API.Finality.Mode)
+	}
+	return nil
+}
+
+// ValidateTLS validates the tls configs
+func ValidateTLS(cfg Config) error {
+	for name, c := range map[string]TLS{
+		"api":        cfg.API.TLS,
+		"http admin": cfg.System.HTTPAdminTLS,
+	} {
+		if (c.CertFile == "") != (c.KeyFile == "") {
+			return errors.Wrapf(ErrInvalidCfg, "%s tls requires both cert file and key file", name)
+		}
+		if c.CertFile == "" && c.ClientCAFile != "" {
+			return errors.Wrapf(ErrInvalidCfg, "%s tls requires cert file to verify client certificates", name)
+		}
+		if c.ClientCAFile == "" && len(c.AllowedClients) > 0 {
+			return errors.Wrapf(ErrInvalidCfg, "%s tls requires client ca file to allow clients", name)
+		}
+		if c.ReloadInterval < 0 {
+			return errors.Wrapf(ErrInvalidCfg, "%s tls reload interval should not be negative", name)
+		}
+	}
+	if c := cfg.APIProxy.UpstreamTLS; (c.CertFile == "") != (c.KeyFile == "") {
+		return errors.Wrap(ErrInvalidCfg, "api proxy upstream tls requires both cert file and key file")
+	}
+	return nil
+}
+
+// ValidateShadowFork validates the shadow fork config
+func ValidateShadowFork(cfg Config) error {
+	sf := cfg.Chain.ShadowFork
+	if sf.Height == 0 {
+		return nil
+	}
+	if len(sf.Delegates) == 0 {
+		return errors.Wrap(ErrInvalidCfg, "shadow fork requires the local delegates")
+	}
+	for _, d := range sf.Delegates {
+		if _, err := address.FromString(d.OperatorAddrStr); err != nil {
+			return errors.Wrapf(ErrInvalidCfg, "invalid shadow fork delegate operator address %s", d.OperatorAddrStr)
+		}
+		if _, ok := new(big.Int).SetString(d.VotesStr, 10); !ok {
+			return errors.Wrapf(ErrInvalidCfg, "invalid shadow fork delegate votes %s", d.VotesStr)
+		}
+	}
+	return nil
+}
+
+// ValidateTrustedCheckpoint validates the trusted checkpoint
+func ValidateTrustedCheckpoint(cfg Config) error {
+	cp := cfg.Chain.TrustedCheckpoint
+	if cp.Height == 0 {
+		return nil
+	}
+	if _, err := hash.HexStringToHash256(cp.Hash); err != nil || len(cp.Hash) != 2*len(hash.ZeroHash256) {
+		return errors.Wrapf(ErrInvalidCfg, "invalid trusted checkpoint hash %s", cp.Hash)
+	}
+	if cfg.Chain.EnableArchiveMode {
+		return errors.Wrap(ErrInvalidCfg, "archive mode cannot sync from a trusted checkpoint")
+	}
+	return nil
+}
+
+// ValidateContractDeployerAllowlist validates the addresses allowed to deploy contracts
+func ValidateContractDeployerAllowlist(cfg Config) error {
+	for _, addr := range cfg.Genesis.ContractDeployerAllowlist {
+		if _, err := address.FromString(addr); err != nil {
+			return errors.Wrapf(ErrInvalidCfg, "invalid contract deployer address %s", addr)
+		}
+	}
+	return nil
+}
+
+// ValidateMinBalanceReservation validates the minimum balance reservation of the transfers
+func ValidateMinBalanceReservation(cfg Config) error {
+	if cfg.Genesis.MinBalanceReservationStr == "" {
+		return nil
+	}
+	if val, ok := new(big.Int).SetString(cfg.Genesis.MinBalanceReservationStr, 10); !ok || val.Sign() < 0 {
+		return errors.Wrapf(ErrInvalidCfg, "invalid min balance reservation %s", cfg.Genesis.MinBalanceReservationStr)
+	}
+	return nil
+}
+
+// ValidateSanctionGovernors validates the governors of the sanction list
+func ValidateSanctionGovernors(cfg Config) error {
+	governors := cfg.Genesis.SanctionGovernors
+	if len(governors) == 0 {
+		return nil
+	}
+	for _, addr := range governors {
+		if _, err := address.FromString(addr); err != nil {
+			return errors.Wrapf(ErrInvalidCfg, "invalid sanction governor address %s", addr)
+		}
+	}
+	if cfg.Genesis.SanctionApprovals == 0 || cfg.Genesis.SanctionApprovals > uint64(len(governors)) {
+		return errors.Wrapf(ErrInvalidCfg, "invalid sanction approvals %d of %d governors", cfg.Genesis.SanctionApprovals, len(governors))
+	}
+	return nil
+}
+
+// ValidateParameterGovernors validates the governors of the chain parameters
+func ValidateParameterGovernors(cfg Config) error {
+	governors := cfg.Genesis.ParameterGovernors
+	if len(governors) == 0 {
+		return nil
+	}
+	for _, addr := range governors {
+		if _, err := address.FromString(addr); err != nil {
+			return errors.Wrapf(ErrInvalidCfg, "invalid parameter governor address %s", addr)
+		}
+	}
+	if cfg.Genesis.ParameterApprovals == 0 || cfg.Genesis.ParameterApprovals > uint64(len(governors)) {
+		return errors.Wrapf(ErrInvalidCfg, "invalid parameter approvals %d of %d governors", cfg.Genesis.ParameterApprovals, len(governors))
+	}
 	return nil
 }
 
@@ -665,6 +1402,162 @@ func ValidateForkHeights(cfg Config) error {
 		return errors.Wrap(ErrInvalidCfg, "FairbankMigration is heigher than Fairbank")
 	case hu.FairbankBlockHeight() > hu.GreenlandBlockHeight():
 		return errors.Wrap(ErrInvalidCfg, "Fairbank is heigher than Greenland")
+	case hu.HawaiiBlockHeight() > hu.IcelandBlockHeight():
+		return errors.Wrap(ErrInvalidCfg, "Hawaii is heigher than Iceland")
+	case hu.IcelandBlockHeight() > hu.JutlandBlockHeight():
+		return errors.Wrap(ErrInvalidCfg, "Iceland is heigher than Jutland")
+	case hu.JutlandBlockHeight() > hu.KamchatkaBlockHeight():
+		return errors.Wrap(ErrInvalidCfg, "Jutland is heigher than Kamchatka")
+	}
+	return nil
+}
+
+// ValidateAPIProxy validates the api proxy configs
+func ValidateAPIProxy(cfg Config) error {
+	if _, ok := cfg.Plugins[APIProxyPlugin]; !ok {
+		return nil
+	}
+	if len(cfg.APIProxy.Endpoints) == 0 {
+		return errors.Wrap(ErrInvalidCfg, "api proxy requires at least one upstream endpoint")
+	}
+	if cfg.APIProxy.HealthCheckInterval <= 0 {
+		return errors.Wrap(ErrInvalidCfg, "api proxy health check interval should be greater than 0")
+	}
+	return nil
+}
+
+// ValidateExporter validates the exporter configs
+func ValidateExporter(cfg Config) error {
+	switch cfg.Exporter.Type {
+	case "":
+		return nil
+	case "nats":
+	default:
+		return errors.Wrapf(ErrInvalidCfg, "unsupported exporter type %s", cfg.Exporter.Type)
+	}
+	if cfg.Exporter.Endpoint == "" {
+		return errors.Wrap(ErrInvalidCfg, "exporter endpoint is empty")
+	}
+	if cfg.Exporter.Encoding != "protobuf" && cfg.Exporter.Encoding != "json" {
+		return errors.Wrapf(ErrInvalidCfg, "unsupported exporter encoding %s", cfg.Exporter.Encoding)
+	}
+	if cfg.Exporter.RetryInterval <= 0 {
+		return errors.Wrap(ErrInvalidCfg, "exporter retry interval should be greater than 0")
+	}
+	return nil
+}
+
+// ValidateSQLIndexer validates the sql indexer configs
+func ValidateSQLIndexer(cfg Config) error {
+	switch cfg.SQLIndexer.Driver {
+	case "":
+		return nil
+	case "postgres", "sqlite3":
+	default:
+		return errors.Wrapf(ErrInvalidCfg, "unsupported sql indexer driver %s", cfg.SQLIndexer.Driver)
+	}
+	if cfg.SQLIndexer.ConnectionString == "" {
+		return errors.Wrap(ErrInvalidCfg, "sql indexer connection string is empty")
+	}
+	if cfg.SQLIndexer.RetryInterval <= 0 {
+		return errors.Wrap(ErrInvalidCfg, "sql indexer retry interval should be greater than 0")
+	}
+	return nil
+}
+
+// ValidateDisabledIndexers validates the names of the disabled indexers
+func ValidateDisabledIndexers(cfg Config) error {
+	for _, name := range cfg.Chain.DisabledIndexers {
+		switch name {
+		case BloomfilterIndexer, BlockStatsIndexer, CandidateHistoryIndexer, StateAccessIndexer:
+		default:
+			return errors.Wrapf(ErrInvalidCfg, "unknown indexer %s", name)
+		}
+	}
+	return nil
+}
+
+// ValidateArchive validates the archive configs
+func ValidateArchive(cfg Config) error {
+	switch cfg.Archive.Type {
+	case "":
+		return nil
+	case "file":
+		if cfg.Archive.Path == "" {
+			return errors.Wrap(ErrInvalidCfg, "archive path is empty")
+		}
+	case "s3":
+		if cfg.Archive.Endpoint == "" || cfg.Archive.Bucket == "" {
+			return errors.Wrap(ErrInvalidCfg, "archive endpoint or bucket is empty")
+		}
+	default:
+		return errors.Wrapf(ErrInvalidCfg, "unsupported archive type %s", cfg.Archive.Type)
+	}
+	if cfg.Archive.SegmentSize == 0 {
+		return errors.Wrap(ErrInvalidCfg, "archive segment size should be greater than 0")
+	}
+	if cfg.Archive.RetryInterval <= 0 {
+		return errors.Wrap(ErrInvalidCfg, "archive retry interval should be greater than 0")
+	}
+	return nil
+}
+
+// ValidateStateSnapshot validates the state snapshot configs
+func ValidateStateSnapshot(cfg Config) error {
+	if cfg.StateSnapshot.Dir == "" {
+		return nil
+	}
+	if cfg.StateSnapshot.Interval == 0 {
+		return errors.Wrap(ErrInvalidCfg, "state snapshot interval should be greater than 0")
+	}
+	if cfg.StateSnapshot.Retention <= 0 {
+		return errors.Wrap(ErrInvalidCfg, "state snapshot retention should be greater than 0")
+	}
+	return nil
+}
+
+// ValidateUpdater validates the updater configs
+func ValidateUpdater(cfg Config) error {
+	if cfg.Updater.ManifestURL == "" {
+		return nil
+	}
+	if cfg.Updater.PublicKey == "" {
+		return errors.Wrap(ErrInvalidCfg, "updater public key is empty")
+	}
+	if cfg.Updater.CheckInterval <= 0 {
+		return errors.Wrap(ErrInvalidCfg, "updater check interval should be greater than 0")
+	}
+	return nil
+}
+
+// ValidateFaucet validates the faucet configs
+func ValidateFaucet(cfg Config) error {
+	if cfg.Faucet.Port == 0 {
+		return nil
+	}
+	if _, err := crypto.HexStringToPrivateKey(cfg.Faucet.PrivateKey); err != nil {
+		return errors.Wrap(ErrInvalidCfg, "invalid faucet private key")
+	}
+	amount, ok := new(big.Int).SetString(cfg.Faucet.Amount, 10)
+	if !ok || amount.Sign() <= 0 {
+		return errors.Wrapf(ErrInvalidCfg, "invalid faucet amount %s", cfg.Faucet.Amount)
+	}
+	if gasPrice, ok := new(big.Int).SetString(cfg.Faucet.GasPrice, 10); !ok || gasPrice.Sign() < 0 {
+		return errors.Wrapf(ErrInvalidCfg, "invalid faucet gas price %s", cfg.Faucet.GasPrice)
+	}
+	if cfg.Faucet.CaptchaVerifyURL != "" && cfg.Faucet.CaptchaSecret == "" {
+		return errors.Wrap(ErrInvalidCfg, "faucet captcha secret is empty")
+	}
+	return nil
+}
+
+// ValidateClockHealth validates the clock health configs
+func ValidateClockHealth(cfg Config) error {
+	if cfg.ClockHealth.MaxOffset <= 0 {
+		return errors.Wrap(ErrInvalidCfg, "max clock offset should be greater than 0")
+	}
+	if len(cfg.ClockHealth.NTPServers) > 0 && (cfg.ClockHealth.Interval <= 0 || cfg.ClockHealth.Timeout <= 0) {
+		return errors.Wrap(ErrInvalidCfg, "ntp query interval and timeout should be greater than 0")
 	}
 	return nil
 }
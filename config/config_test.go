@@ -14,6 +14,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/require"
@@ -173,6 +174,39 @@ func TestNewConfigWithOverride(t *testing.T) {
 	require.Equal(t, sk.HexString(), cfg.Chain.ProducerPrivKey)
 }
 
+func TestNewConfigWithProfile(t *testing.T) {
+	r := require.New(t)
+	defer resetPathValues(t, []string{"_overwritePath"})
+
+	r.NoError(makePathAndWriteFile(`
+profile: delegate
+chain:
+    stateDBCacheSize: 3000
+`, "_overwritePath"))
+	cfg, err := New()
+	r.NoError(err)
+	r.Equal(ProfileDelegate, cfg.Profile)
+	// the values in the config file take precedence over the preset
+	r.Equal(3000, cfg.Chain.StateDBCacheSize)
+	r.Equal(16, cfg.DB.MaxCacheSize)
+	r.True(cfg.Chain.IndexerDisabled(BloomfilterIndexer))
+	_, ok := cfg.Plugins[GatewayPlugin]
+	r.False(ok)
+
+	r.NoError(makePathAndWriteFile("profile: archive\n", "_overwritePath"))
+	cfg, err = New()
+	r.NoError(err)
+	r.True(cfg.Chain.EnableArchiveMode)
+	r.False(cfg.Chain.EnableTrielessStateDB)
+	r.Empty(cfg.Chain.DisabledIndexers)
+	_, ok = cfg.Plugins[GatewayPlugin]
+	r.True(ok)
+
+	r.NoError(makePathAndWriteFile("profile: miner\n", "_overwritePath"))
+	_, err = New()
+	r.Equal(ErrInvalidCfg, errors.Cause(err))
+}
+
 func TestNewConfigWithSecret(t *testing.T) {
 	sk, cfgStr, err := generateProducerPrivKey()
 	require.NoError(t, err)
@@ -210,6 +244,31 @@ func TestNewConfigWithLookupEnv(t *testing.T) {
 	require.NotNil(t, cfg)
 }
 
+func TestNewConfigWithSecretReference(t *testing.T) {
+	sk, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	keyPath := filepath.Join(os.TempDir(), "producer.key")
+	require.NoError(t, ioutil.WriteFile(keyPath, []byte(sk.HexString()+"\n"), 0600))
+	defer os.Remove(keyPath)
+
+	cfgStr := fmt.Sprintf(`
+chain:
+    producerPrivKey: "file://%s"
+`, keyPath)
+	require.NoError(t, makePathAndWriteFile(cfgStr, "_overwritePath"))
+	defer resetPathValues(t, []string{"_overwritePath"})
+
+	cfg, err := New()
+	require.NoError(t, err)
+	require.Equal(t, sk.HexString(), cfg.Chain.ProducerPrivKey)
+	require.Equal(t, sk.HexString(), cfg.Network.MasterKey)
+
+	require.NoError(t, os.Remove(keyPath))
+	_, err = New()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "chain.producerPrivKey")
+}
+
 func TestValidateDispatcher(t *testing.T) {
 	cfg := Default
 	cfg.Dispatcher.EventChanSize = 0
@@ -234,6 +293,18 @@ func TestValidateRollDPoS(t *testing.T) {
 		t,
 		strings.Contains(err.Error(), "roll-DPoS event chan size should be greater than 0"),
 	)
+
+	cfg = Default
+	cfg.Consensus.Scheme = RollDPoSScheme
+	cfg.Consensus.RollDPoS.AdaptiveTiming.Enabled = true
+	require.NoError(t, ValidateRollDPoS(cfg))
+	cfg.Consensus.RollDPoS.AdaptiveTiming.Percentile = 0
+	require.Equal(t, ErrInvalidCfg, errors.Cause(ValidateRollDPoS(cfg)))
+	cfg.Consensus.RollDPoS.AdaptiveTiming.Percentile = 90
+	cfg.Consensus.RollDPoS.AdaptiveTiming.MaxExtension = time.Second
+	err = ValidateRollDPoS(cfg)
+	require.Equal(t, ErrInvalidCfg, errors.Cause(err))
+	require.Contains(t, err.Error(), "should fit in the tolerated overtime")
 }
 
 func TestValidateArchiveMode(t *testing.T) {
@@ -293,6 +364,94 @@ func TestValidateActPool(t *testing.T) {
 	)
 }
 
+func TestValidateTLS(t *testing.T) {
+	r := require.New(t)
+
+	cfg := Default
+	r.NoError(ValidateTLS(cfg))
+	cfg.API.TLS = TLS{
+		CertFile:       "cert.pem",
+		KeyFile:        "key.pem",
+		ClientCAFile:   "ca.pem",
+		AllowedClients: []string{"admin"},
+		ReloadInterval: time.Hour,
+	}
+	r.NoError(ValidateTLS(cfg))
+
+	for _, test := range []struct {
+		tls TLS
+		err string
+	}{
+		{TLS{CertFile: "cert.pem"}, "requires both cert file and key file"},
+		{TLS{ClientCAFile: "ca.pem"}, "requires cert file to verify client certificates"},
+		{TLS{CertFile: "cert.pem", KeyFile: "key.pem", AllowedClients: []string{"admin"}}, "requires client ca file"},
+		{TLS{CertFile: "cert.pem", KeyFile: "key.pem", ReloadInterval: -time.Second}, "reload interval should not be negative"},
+	} {
+		cfg = Default
+		cfg.System.HTTPAdminTLS = test.tls
+		err := ValidateTLS(cfg)
+		r.Equal(ErrInvalidCfg, errors.Cause(err))
+		r.Contains(err.Error(), "http admin tls "+test.err)
+	}
+
+	cfg = Default
+	cfg.APIProxy.UpstreamTLS = ClientTLS{KeyFile: "key.pem"}
+	r.Equal(ErrInvalidCfg, errors.Cause(ValidateTLS(cfg)))
+}
+
+func TestValidateAPIFinality(t *testing.T) {
+	r := require.New(t)
+
+	cfg := Default
+	r.NoError(ValidateAPI(cfg))
+	cfg.API.Finality.Mode = FinalityCheckpoint
+	r.NoError(ValidateAPI(cfg))
+	cfg.API.Finality.Mode = "probabilistic"
+	err := ValidateAPI(cfg)
+	r.Equal(ErrInvalidCfg, errors.Cause(err))
+	r.Contains(err.Error(), "unknown finality mode")
+}
+
+func TestValidateParameterGovernors(t *testing.T) {
+	r := require.New(t)
+
+	cfg := Default
+	r.NoError(ValidateParameterGovernors(cfg))
+	cfg.Genesis.ParameterGovernors = []string{"io1invalid"}
+	r.Equal(ErrInvalidCfg, errors.Cause(ValidateParameterGovernors(cfg)))
+	cfg.Genesis.ParameterGovernors = []string{"io1mflp9m6hcgm2qcghchsdqj3z3eccrnekx9p0ms"}
+	r.NoError(ValidateParameterGovernors(cfg))
+	cfg.Genesis.ParameterApprovals = 2
+	r.Equal(ErrInvalidCfg, errors.Cause(ValidateParameterGovernors(cfg)))
+}
+
+func TestValidateDisabledIndexers(t *testing.T) {
+	r := require.New(t)
+
+	cfg := Default
+	r.NoError(ValidateDisabledIndexers(cfg))
+	r.False(cfg.Chain.IndexerDisabled(BloomfilterIndexer))
+	cfg.Chain.DisabledIndexers = []string{BloomfilterIndexer, StateAccessIndexer}
+	r.NoError(ValidateDisabledIndexers(cfg))
+	r.True(cfg.Chain.IndexerDisabled(BloomfilterIndexer))
+	r.False(cfg.Chain.IndexerDisabled(BlockStatsIndexer))
+	cfg.Chain.DisabledIndexers = []string{"receipt"}
+	r.Equal(ErrInvalidCfg, errors.Cause(ValidateDisabledIndexers(cfg)))
+}
+
+func TestValidateTrustedCheckpoint(t *testing.T) {
+	r := require.New(t)
+
+	cfg := Default
+	r.NoError(ValidateTrustedCheckpoint(cfg))
+	cfg.Chain.TrustedCheckpoint = Checkpoint{Height: 100, Hash: "abc"}
+	r.Equal(ErrInvalidCfg, errors.Cause(ValidateTrustedCheckpoint(cfg)))
+	cfg.Chain.TrustedCheckpoint.Hash = strings.Repeat("ab", 32)
+	r.NoError(ValidateTrustedCheckpoint(cfg))
+	cfg.Chain.EnableArchiveMode = true
+	r.Equal(ErrInvalidCfg, errors.Cause(ValidateTrustedCheckpoint(cfg)))
+}
+
 func TestValidateMinGasPrice(t *testing.T) {
 	ap := ActPool{MinGasPriceStr: Default.ActPool.MinGasPriceStr}
 	mgp := ap.MinGasPrice()
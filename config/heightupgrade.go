@@ -25,6 +25,9 @@ const (
 	FbkMigration
 	Greenland
 	Hawaii
+	Iceland
+	Jutland
+	Kamchatka
 )
 
 type (
@@ -49,6 +52,9 @@ type (
 		fbkMigrationHeight uint64
 		greanlandHeight    uint64
 		hawaiiHeight       uint64
+		icelandHeight      uint64
+		jutlandHeight      uint64
+		kamchatkaHeight    uint64
 	}
 )
 
@@ -66,6 +72,9 @@ func NewHeightUpgrade(cfg *genesis.Genesis) HeightUpgrade {
 		cfg.FbkMigrationBlockHeight,
 		cfg.GreenlandBlockHeight,
 		cfg.HawaiiBlockHeight,
+		cfg.IcelandBlockHeight,
+		cfg.JutlandBlockHeight,
+		cfg.KamchatkaBlockHeight,
 	}
 }
 
@@ -95,6 +104,12 @@ func (hu *HeightUpgrade) IsPost(name HeightName, height uint64) bool {
 		h = hu.greanlandHeight
 	case Hawaii:
 		h = hu.hawaiiHeight
+	case Iceland:
+		h = hu.icelandHeight
+	case Jutland:
+		h = hu.jutlandHeight
+	case Kamchatka:
+		h = hu.kamchatkaHeight
 	default:
 		log.Panic("invalid height name!")
 	}
@@ -138,3 +153,12 @@ func (hu *HeightUpgrade) GreenlandBlockHeight() uint64 { return hu.greanlandHeig
 
 // HawaiiBlockHeight returns the hawaii height
 func (hu *HeightUpgrade) HawaiiBlockHeight() uint64 { return hu.hawaiiHeight }
+
+// IcelandBlockHeight returns the iceland height
+func (hu *HeightUpgrade) IcelandBlockHeight() uint64 { return hu.icelandHeight }
+
+// JutlandBlockHeight returns the jutland height
+func (hu *HeightUpgrade) JutlandBlockHeight() uint64 { return hu.jutlandHeight }
+
+// KamchatkaBlockHeight returns the kamchatka height
+func (hu *HeightUpgrade) KamchatkaBlockHeight() uint64 { return hu.kamchatkaHeight }
@@ -26,6 +26,9 @@ func TestNewHeightChange(t *testing.T) {
 	require.Equal(8, FbkMigration)
 	require.Equal(9, Greenland)
 	require.Equal(10, Hawaii)
+	require.Equal(11, Iceland)
+	require.Equal(12, Jutland)
+	require.Equal(13, Kamchatka)
 
 	cfg := Default
 	cfg.Genesis.PacificBlockHeight = uint64(432001)
@@ -53,6 +56,12 @@ func TestNewHeightChange(t *testing.T) {
 	require.True(hu.IsPost(Greenland, uint64(6544441)))
 	require.True(hu.IsPre(Hawaii, uint64(11073240)))
 	require.True(hu.IsPost(Hawaii, uint64(11073241)))
+	require.True(hu.IsPre(Iceland, uint64(12289320)))
+	require.True(hu.IsPost(Iceland, uint64(12289321)))
+	require.True(hu.IsPre(Jutland, uint64(13685400)))
+	require.True(hu.IsPost(Jutland, uint64(13685401)))
+	require.True(hu.IsPre(Kamchatka, uint64(13979160)))
+	require.True(hu.IsPost(Kamchatka, uint64(13979161)))
 	require.Panics(func() {
 		hu.IsPost(-1, 0)
 	})
@@ -68,4 +77,7 @@ func TestNewHeightChange(t *testing.T) {
 	require.Equal(hu.FbkMigrationBlockHeight(), uint64(5157001))
 	require.Equal(hu.GreenlandBlockHeight(), uint64(6544441))
 	require.Equal(hu.HawaiiBlockHeight(), uint64(11073241))
+	require.Equal(hu.IcelandBlockHeight(), uint64(12289321))
+	require.Equal(hu.JutlandBlockHeight(), uint64(13685401))
+	require.Equal(hu.KamchatkaBlockHeight(), uint64(13979161))
 }
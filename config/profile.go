@@ -0,0 +1,65 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package config
+
+import (
+	"github.com/pkg/errors"
+)
+
+// the presets of the node resources, selected by the profile config
+const (
+	// ProfileDelegate is for the delegates producing blocks only, which serve no user requests. The indexers are
+	// disabled and the state is cached more, to keep the commit latency low
+	ProfileDelegate = "delegate"
+	// ProfileFullnode is for the nodes syncing and validating the blocks, which serve the basic api only
+	ProfileFullnode = "fullnode"
+	// ProfileGateway is for the nodes serving user requests, with all the indexers enabled and more blocks cached
+	ProfileGateway = "gateway"
+	// ProfileArchive is a gateway keeping the history states, to serve the requests at any height
+	ProfileArchive = "archive"
+)
+
+// applyProfile returns the config tuned by the preset of the profile, which is the base of the config files
+func applyProfile(cfg Config, profile string) (Config, error) {
+	switch profile {
+	case "":
+	case ProfileDelegate:
+		cfg.Chain.StateDBCacheSize = 4000
+		cfg.Chain.WorkingSetCacheSize = 20
+		cfg.Chain.EnableArchiveMode = false
+		cfg.Chain.DisabledIndexers = []string{
+			BloomfilterIndexer,
+			BlockStatsIndexer,
+			CandidateHistoryIndexer,
+			StateAccessIndexer,
+		}
+		cfg.DB.MaxCacheSize = 16
+		cfg.API.GRPCWeb.Port = 0
+	case ProfileFullnode:
+		cfg.Chain.StateDBCacheSize = 1000
+		cfg.Chain.EnableArchiveMode = false
+		cfg.DB.MaxCacheSize = 64
+		cfg.API.GRPCWeb.Port = 0
+	case ProfileGateway, ProfileArchive:
+		cfg.Chain.StateDBCacheSize = 2000
+		cfg.Chain.EnableArchiveMode = profile == ProfileArchive
+		if cfg.Chain.EnableArchiveMode {
+			cfg.Chain.EnableTrielessStateDB = false
+		}
+		cfg.Chain.DisabledIndexers = nil
+		cfg.DB.MaxCacheSize = 1000
+	default:
+		return Config{}, errors.Wrapf(ErrInvalidCfg, "unknown profile %s", profile)
+	}
+	cfg.Profile = profile
+	return cfg, nil
+}
+
+// gatewayProfile returns true if the profile serves user requests by the gateway plugin
+func gatewayProfile(profile string) bool {
+	return profile == ProfileGateway || profile == ProfileArchive
+}
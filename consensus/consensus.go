@@ -21,6 +21,7 @@ import (
 	"github.com/iotexproject/iotex-core/config"
 	"github.com/iotexproject/iotex-core/consensus/scheme"
 	"github.com/iotexproject/iotex-core/consensus/scheme/rolldpos"
+	"github.com/iotexproject/iotex-core/epochbus"
 	"github.com/iotexproject/iotex-core/pkg/lifecycle"
 	"github.com/iotexproject/iotex-core/pkg/log"
 	"github.com/iotexproject/iotex-core/state"
@@ -50,6 +51,9 @@ type optionParams struct {
 	broadcastHandler scheme.Broadcast
 	pp               poll.Protocol
 	rp               *rp.Protocol
+	clockChecker     rolldpos.ClockChecker
+	observer         rolldpos.EndorsementObserver
+	epochBus         *epochbus.Bus
 }
 
 // Option sets Consensus construction parameter.
@@ -79,6 +83,30 @@ func WithPollProtocol(pp poll.Protocol) Option {
 	}
 }
 
+// WithClockChecker is an option to check the local clock before proposing blocks
+func WithClockChecker(clockChecker rolldpos.ClockChecker) Option {
+	return func(ops *optionParams) error {
+		ops.clockChecker = clockChecker
+		return nil
+	}
+}
+
+// WithEndorsementObserver is an option to observe the commit endorsements arrived
+func WithEndorsementObserver(observer rolldpos.EndorsementObserver) Option {
+	return func(ops *optionParams) error {
+		ops.observer = observer
+		return nil
+	}
+}
+
+// WithEpochBus is an option to cache the delegates of epochs until the candidates are shifted
+func WithEpochBus(bus *epochbus.Bus) Option {
+	return func(ops *optionParams) error {
+		ops.epochBus = bus
+		return nil
+	}
+}
+
 // NewConsensus creates a IotxConsensus struct.
 func NewConsensus(
 	cfg config.Config,
@@ -97,6 +125,7 @@ func NewConsensus(
 	var err error
 	switch cfg.Consensus.Scheme {
 	case config.RollDPoSScheme:
+		delegatesCache := epochbus.NewCache(ops.epochBus)
 		bd := rolldpos.NewRollDPoSBuilder().
 			SetAddr(cfg.ProducerAddress().String()).
 			SetPriKey(cfg.ProducerPrivateKey()).
@@ -104,38 +133,17 @@ func NewConsensus(
 			SetChainManager(bc).
 			SetBroadcast(ops.broadcastHandler).
 			SetDelegatesByEpochFunc(func(epochNum uint64) ([]string, error) {
-				re := protocol.NewRegistry()
-				if err := ops.rp.Register(re); err != nil {
-					return nil, err
-				}
-				ctx := protocol.WithBlockchainCtx(
-					protocol.WithRegistry(context.Background(), re),
-					protocol.BlockchainCtx{
-						Genesis: cfg.Genesis,
-					},
-				)
-				tipHeight := bc.TipHeight()
-				tipEpochNum := ops.rp.GetEpochNum(tipHeight)
-				var candidatesList state.CandidateList
-				var err error
-				switch epochNum {
-				case tipEpochNum:
-					candidatesList, err = ops.pp.Delegates(ctx, sf)
-				case tipEpochNum + 1:
-					candidatesList, err = ops.pp.NextDelegates(ctx, sf)
-				default:
-					err = errors.Errorf("invalid epoch number %d compared to tip epoch number %d", epochNum, tipEpochNum)
-				}
+				delegates, err := delegatesCache.Get(epochNum, func() (interface{}, error) {
+					return delegatesByEpoch(cfg, bc, sf, &ops, epochNum)
+				})
 				if err != nil {
 					return nil, err
 				}
-				addrs := []string{}
-				for _, candidate := range candidatesList {
-					addrs = append(addrs, candidate.Address)
-				}
-				return addrs, nil
+				return delegates.([]string), nil
 			}).
-			RegisterProtocol(ops.rp)
+			RegisterProtocol(ops.rp).
+			SetClockChecker(ops.clockChecker).
+			SetEndorsementObserver(ops.observer)
 		// TODO: explorer dependency deleted here at #1085, need to revive by migrating to api
 		cs.scheme, err = bd.Build()
 		if err != nil {
@@ -182,6 +190,46 @@ func NewConsensus(
 	return cs, nil
 }
 
+// delegatesByEpoch reads the delegates of the tip epoch or the next epoch from the poll protocol
+func delegatesByEpoch(
+	cfg config.Config,
+	bc blockchain.Blockchain,
+	sf factory.Factory,
+	ops *optionParams,
+	epochNum uint64,
+) ([]string, error) {
+	re := protocol.NewRegistry()
+	if err := ops.rp.Register(re); err != nil {
+		return nil, err
+	}
+	ctx := protocol.WithBlockchainCtx(
+		protocol.WithRegistry(context.Background(), re),
+		protocol.BlockchainCtx{
+			Genesis: cfg.Genesis,
+		},
+	)
+	tipHeight := bc.TipHeight()
+	tipEpochNum := ops.rp.GetEpochNum(tipHeight)
+	var candidatesList state.CandidateList
+	var err error
+	switch epochNum {
+	case tipEpochNum:
+		candidatesList, err = ops.pp.Delegates(ctx, sf)
+	case tipEpochNum + 1:
+		candidatesList, err = ops.pp.NextDelegates(ctx, sf)
+	default:
+		err = errors.Errorf("invalid epoch number %d compared to tip epoch number %d", epochNum, tipEpochNum)
+	}
+	if err != nil {
+		return nil, err
+	}
+	addrs := []string{}
+	for _, candidate := range candidatesList {
+		addrs = append(addrs, candidate.Address)
+	}
+	return addrs, nil
+}
+
 // Start starts running the consensus algorithm
 func (c *IotxConsensus) Start(ctx context.Context) error {
 	log.Logger("consensus").Info("Starting IotxConsensus scheme.", zap.String("scheme", c.cfg.Scheme))
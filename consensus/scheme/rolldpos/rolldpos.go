@@ -12,6 +12,7 @@ import (
 
 	fsm "github.com/iotexproject/go-fsm"
 	"github.com/iotexproject/go-pkgs/crypto"
+	"github.com/iotexproject/iotex-address/address"
 	"github.com/iotexproject/iotex-proto/golang/iotextypes"
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
@@ -33,6 +34,8 @@ var (
 	ErrZeroDelegate = errors.New("zero delegates in the network")
 	// ErrNotEnoughCandidates indicates there are not enough candidates from the candidate pool
 	ErrNotEnoughCandidates = errors.New("Candidate pool does not have enough candidates")
+	// ErrInvalidBlockTimestamp indicates the timestamp of the proposed block is out of the tolerated range
+	ErrInvalidBlockTimestamp = errors.New("invalid block timestamp")
 )
 
 // ChainManager defines the blockchain interface
@@ -56,12 +59,27 @@ type ChainManager interface {
 	ChainAddress() string
 }
 
+// ClockChecker checks the health of the local clock
+type ClockChecker interface {
+	// Check returns an error if the local clock is skewed
+	Check() error
+	// RefuseProposal returns true if blocks should not be proposed while the local clock is skewed
+	RefuseProposal() bool
+}
+
+// EndorsementObserver is called on the arrival of a verified commit endorsement, with the height of the block, the
+// endorser, and the delay of the arrival after the timestamp of the endorsement
+type EndorsementObserver func(height uint64, endorser string, delay time.Duration)
+
 // RollDPoS is Roll-DPoS consensus main entrance
 type RollDPoS struct {
-	cfsm       *consensusfsm.ConsensusFSM
-	ctx        *rollDPoSCtx
-	startDelay time.Duration
-	ready      chan interface{}
+	cfsm                *consensusfsm.ConsensusFSM
+	ctx                 *rollDPoSCtx
+	startDelay          time.Duration
+	ready               chan interface{}
+	endorsementObserver EndorsementObserver
+	checkpoint          uint64
+	numDelegates        uint64
 }
 
 // Start starts RollDPoS consensus
@@ -105,6 +123,9 @@ func (r *RollDPoS) HandleConsensusMsg(msg *iotextypes.ConsensusMessage) error {
 			zap.Uint64("consensusHeight", consensusHeight),
 			zap.Uint64("msgHeight", msg.Height),
 		)
+		if msg.Height+1 == consensusHeight {
+			r.observeLateEndorsement(msg)
+		}
 		return nil
 	case msg.Height > consensusHeight+1:
 		log.Logger("consensus").Debug(
@@ -133,6 +154,8 @@ func (r *RollDPoS) HandleConsensusMsg(msg *iotextypes.ConsensusMessage) error {
 		if err := r.ctx.CheckVoteEndorser(endorsedMessage.Height(), consensusMessage, en); err != nil {
 			return errors.Wrapf(err, "failed to verify vote")
 		}
+		r.ctx.observeEndorsementLateness(endorsedMessage.Height(), consensusMessage, en)
+		r.observeEndorsement(endorsedMessage.Height(), consensusMessage, en)
 		switch consensusMessage.Topic() {
 		case PROPOSAL:
 			r.cfsm.ProduceReceiveProposalEndorsementEvent(endorsedMessage)
@@ -148,6 +171,40 @@ func (r *RollDPoS) HandleConsensusMsg(msg *iotextypes.ConsensusMessage) error {
 	}
 }
 
+// observeLateEndorsement observes the endorsements of the last committed block
+func (r *RollDPoS) observeLateEndorsement(msg *iotextypes.ConsensusMessage) {
+	if r.endorsementObserver == nil && r.ctx.tuner == nil {
+		return
+	}
+	endorsedMessage := &EndorsedConsensusMessage{}
+	if err := endorsedMessage.LoadProto(msg); err != nil {
+		return
+	}
+	if !endorsement.VerifyEndorsedDocument(endorsedMessage) {
+		return
+	}
+	vote, ok := endorsedMessage.Document().(*ConsensusVote)
+	if !ok {
+		return
+	}
+	height, en := endorsedMessage.Height(), endorsedMessage.Endorsement()
+	if r.ctx.CheckVoteEndorser(height, vote, en) == nil {
+		r.ctx.observeEndorsementLateness(height, vote, en)
+	}
+	r.observeEndorsement(height, vote, en)
+}
+
+func (r *RollDPoS) observeEndorsement(height uint64, vote *ConsensusVote, en *endorsement.Endorsement) {
+	if r.endorsementObserver == nil || vote.Topic() != COMMIT {
+		return
+	}
+	endorser, err := address.FromBytes(en.Endorser().Hash())
+	if err != nil {
+		return
+	}
+	r.endorsementObserver(height, endorser.String(), time.Since(en.Timestamp()))
+}
+
 // Calibrate called on receive a new block not via consensus
 func (r *RollDPoS) Calibrate(height uint64) {
 	r.cfsm.Calibrate(height)
@@ -156,6 +213,9 @@ func (r *RollDPoS) Calibrate(height uint64) {
 // ValidateBlockFooter validates the signatures in the block footer
 func (r *RollDPoS) ValidateBlockFooter(blk *block.Block) error {
 	height := blk.Height()
+	if height <= r.checkpoint {
+		return r.validateCheckpointedFooter(blk)
+	}
 	round, err := r.ctx.roundCalc.NewRound(height, r.ctx.BlockInterval(height), blk.Timestamp(), nil)
 	if err != nil {
 		return err
@@ -185,6 +245,25 @@ func (r *RollDPoS) ValidateBlockFooter(blk *block.Block) error {
 	return nil
 }
 
+// validateCheckpointedFooter verifies the commit endorsements of the block up to the trusted checkpoint. The delegates
+// of the epochs before the state snapshot are unknown, so the distinct endorsers are counted against the number of
+// delegates instead
+func (r *RollDPoS) validateCheckpointedFooter(blk *block.Block) error {
+	blkHash := blk.HashBlock()
+	vote := NewConsensusVote(blkHash[:], COMMIT)
+	endorsers := make(map[string]struct{})
+	for _, en := range blk.Endorsements() {
+		if !endorsement.VerifyEndorsement(vote, en) {
+			return errors.Errorf("invalid commit endorsement of block %x", blkHash)
+		}
+		endorsers[en.Endorser().HexString()] = struct{}{}
+	}
+	if 3*uint64(len(endorsers)) <= 2*r.numDelegates {
+		return ErrInsufficientEndorsements
+	}
+	return nil
+}
+
 // Metrics returns RollDPoS consensus metrics
 func (r *RollDPoS) Metrics() (scheme.ConsensusMetrics, error) {
 	var metrics scheme.ConsensusMetrics
@@ -238,6 +317,8 @@ type Builder struct {
 	// TODO: explorer dependency deleted at #1085, need to add api params
 	rp                   *rolldpos.Protocol
 	delegatesByEpochFunc DelegatesByEpochFunc
+	clockChecker         ClockChecker
+	endorsementObserver  EndorsementObserver
 }
 
 // NewRollDPoSBuilder instantiates a Builder instance
@@ -289,6 +370,18 @@ func (b *Builder) RegisterProtocol(rp *rolldpos.Protocol) *Builder {
 	return b
 }
 
+// SetClockChecker sets the checker of the local clock
+func (b *Builder) SetClockChecker(clockChecker ClockChecker) *Builder {
+	b.clockChecker = clockChecker
+	return b
+}
+
+// SetEndorsementObserver sets the observer of the commit endorsements arrived
+func (b *Builder) SetEndorsementObserver(observer EndorsementObserver) *Builder {
+	b.endorsementObserver = observer
+	return b
+}
+
 // Build builds a RollDPoS consensus module
 func (b *Builder) Build() (*RollDPoS, error) {
 	if b.chain == nil {
@@ -303,6 +396,7 @@ func (b *Builder) Build() (*RollDPoS, error) {
 		b.cfg.DB,
 		b.cfg.System.Active,
 		b.cfg.Consensus.RollDPoS.ToleratedOvertime,
+		b.cfg.Consensus.RollDPoS.TimestampDrift,
 		b.cfg.Genesis.TimeBasedRotation,
 		b.chain,
 		b.rp,
@@ -311,18 +405,29 @@ func (b *Builder) Build() (*RollDPoS, error) {
 		b.encodedAddr,
 		b.priKey,
 		b.cfg.Genesis.BeringBlockHeight,
+		b.cfg.Genesis.JutlandBlockHeight,
 	)
 	if err != nil {
 		return nil, errors.Wrap(err, "error when constructing consensus context")
 	}
+	ctx.clockChecker = b.clockChecker
+	if at := b.cfg.Consensus.RollDPoS.AdaptiveTiming; at.Enabled {
+		ctx.tuner = newTimingTuner(b.rp, at.Percentile, at.MaxExtension)
+	}
+	if path := b.cfg.Consensus.RollDPoS.SignLedgerPath; path != "" {
+		ctx.signGuard = newSignGuard(path)
+	}
 	cfsm, err := consensusfsm.NewConsensusFSM(ctx)
 	if err != nil {
 		return nil, errors.Wrap(err, "error when constructing the consensus FSM")
 	}
 	return &RollDPoS{
-		cfsm:       cfsm,
-		ctx:        ctx,
-		startDelay: b.cfg.Consensus.RollDPoS.Delay,
-		ready:      make(chan interface{}),
+		cfsm:                cfsm,
+		ctx:                 ctx,
+		startDelay:          b.cfg.Consensus.RollDPoS.Delay,
+		ready:               make(chan interface{}),
+		endorsementObserver: b.endorsementObserver,
+		checkpoint:          b.cfg.Chain.TrustedCheckpoint.Height,
+		numDelegates:        b.cfg.Genesis.NumDelegates,
 	}, nil
 }
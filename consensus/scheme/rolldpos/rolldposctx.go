@@ -60,6 +60,14 @@ var (
 		},
 		[]string{},
 	)
+
+	blockTimestampDriftMtc = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "iotex_consensus_block_timestamp_drift",
+			Help: "Drift in seconds of the proposed block timestamp ahead of the local clock",
+		},
+		[]string{"delegate"},
+	)
 )
 
 func init() {
@@ -67,6 +75,7 @@ func init() {
 	prometheus.MustRegister(blockIntervalMtc)
 	prometheus.MustRegister(consensusDurationMtc)
 	prometheus.MustRegister(consensusHeightMtc)
+	prometheus.MustRegister(blockTimestampDriftMtc)
 }
 
 // DelegatesByEpochFunc defines a function to overwrite candidates
@@ -80,6 +89,10 @@ type rollDPoSCtx struct {
 	roundCalc         *roundCalculator
 	eManagerDB        db.KVStore
 	toleratedOvertime time.Duration
+	timestampDrift    time.Duration
+	clockChecker      ClockChecker
+	tuner             *timingTuner
+	signGuard         *signGuard
 
 	encodedAddr string
 	priKey      crypto.PrivateKey
@@ -93,6 +106,7 @@ func newRollDPoSCtx(
 	consensusDBConfig config.DB,
 	active bool,
 	toleratedOvertime time.Duration,
+	timestampDrift time.Duration,
 	timeBasedRotation bool,
 	chain ChainManager,
 	rp *rolldpos.Protocol,
@@ -101,6 +115,7 @@ func newRollDPoSCtx(
 	encodedAddr string,
 	priKey crypto.PrivateKey,
 	beringHeight uint64,
+	jutlandHeight uint64,
 ) (*rollDPoSCtx, error) {
 	if chain == nil {
 		return nil, errors.New("chain cannot be nil")
@@ -131,6 +146,7 @@ func newRollDPoSCtx(
 		rp:                   rp,
 		timeBasedRotation:    timeBasedRotation,
 		beringHeight:         beringHeight,
+		jutlandHeight:        jutlandHeight,
 	}
 	return &rollDPoSCtx{
 		ConsensusConfig:   cfg,
@@ -142,10 +158,16 @@ func newRollDPoSCtx(
 		roundCalc:         roundCalc,
 		eManagerDB:        eManagerDB,
 		toleratedOvertime: toleratedOvertime,
+		timestampDrift:    timestampDrift,
 	}, nil
 }
 
 func (ctx *rollDPoSCtx) Start(c context.Context) (err error) {
+	if ctx.signGuard != nil {
+		if err := ctx.signGuard.Start(c); err != nil {
+			return errors.Wrap(err, "Error when starting the sign guard")
+		}
+	}
 	var eManager *endorsementManager
 	if ctx.eManagerDB != nil {
 		if err := ctx.eManagerDB.Start(c); err != nil {
@@ -159,6 +181,11 @@ func (ctx *rollDPoSCtx) Start(c context.Context) (err error) {
 }
 
 func (ctx *rollDPoSCtx) Stop(c context.Context) error {
+	if ctx.signGuard != nil {
+		if err := ctx.signGuard.Stop(c); err != nil {
+			return err
+		}
+	}
 	if ctx.eManagerDB != nil {
 		return ctx.eManagerDB.Stop(c)
 	}
@@ -217,6 +244,9 @@ func (ctx *rollDPoSCtx) CheckBlockProposer(
 	if !proposal.block.VerifySignature() {
 		return errors.Errorf("invalid block signature")
 	}
+	if err := ctx.checkBlockTimestamp(height, proposal, proposerAddr == endorserAddr.String()); err != nil {
+		return err
+	}
 	if proposerAddr != endorserAddr.String() {
 		round, err := ctx.roundCalc.NewRound(height, ctx.BlockInterval(height), en.Timestamp(), nil)
 		if err != nil {
@@ -247,6 +277,38 @@ func (ctx *rollDPoSCtx) CheckBlockProposer(
 	return nil
 }
 
+// checkBlockTimestamp checks that the block timestamp is after the parent block's, and is not ahead of the local clock
+// by more than the tolerated drift. The drift of a block proposed in the current round is recorded per delegate.
+func (ctx *rollDPoSCtx) checkBlockTimestamp(height uint64, proposal *blockProposal, fresh bool) error {
+	parent, err := ctx.chain.BlockHeaderByHeight(height - 1)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get block header of height %d", height-1)
+	}
+	ts := proposal.block.Timestamp()
+	if !ts.After(parent.Timestamp()) {
+		return errors.Wrapf(
+			ErrInvalidBlockTimestamp,
+			"block timestamp %s is not after parent block timestamp %s",
+			ts,
+			parent.Timestamp(),
+		)
+	}
+	drift := ts.Sub(time.Now())
+	if fresh {
+		blockTimestampDriftMtc.WithLabelValues(proposal.ProposerAddress()).Set(drift.Seconds())
+	}
+	if ctx.timestampDrift > 0 && drift > ctx.timestampDrift {
+		return errors.Wrapf(
+			ErrInvalidBlockTimestamp,
+			"block timestamp %s is %s ahead of local clock, tolerated drift %s",
+			ts,
+			drift,
+			ctx.timestampDrift,
+		)
+	}
+	return nil
+}
+
 func (ctx *rollDPoSCtx) RoundCalc() *roundCalculator {
 	return ctx.roundCalc
 }
@@ -317,6 +379,14 @@ func (ctx *rollDPoSCtx) Proposal() (interface{}, error) {
 	if ctx.round.Proposer() != ctx.encodedAddr {
 		return nil, nil
 	}
+	if ctx.clockChecker != nil {
+		if err := ctx.clockChecker.Check(); err != nil {
+			ctx.logger().Warn("local clock is skewed when proposing block", zap.Error(err))
+			if ctx.clockChecker.RefuseProposal() {
+				return nil, nil
+			}
+		}
+	}
 	if ctx.round.IsLocked() {
 		return ctx.endorseBlockProposal(newBlockProposal(
 			ctx.round.Block(ctx.round.HashOfBlockInLock()),
@@ -405,9 +475,7 @@ func (ctx *rollDPoSCtx) NewLockEndorsement(
 			return ctx.newEndorsement(
 				blkHash,
 				LOCK,
-				ctx.round.StartTime().Add(
-					ctx.AcceptBlockTTL(ctx.round.height)+ctx.AcceptProposalEndorsementTTL(ctx.round.height),
-				),
+				ctx.round.StartTime().Add(ctx.lockPhaseOffset(ctx.round.height)),
 			)
 		}
 		ctx.loggerWithStats().Debug("Unlocked")
@@ -432,9 +500,7 @@ func (ctx *rollDPoSCtx) NewPreCommitEndorsement(
 		return ctx.newEndorsement(
 			blkHash,
 			COMMIT,
-			ctx.round.StartTime().Add(
-				ctx.AcceptBlockTTL(ctx.round.height)+ctx.AcceptProposalEndorsementTTL(ctx.round.height)+ctx.AcceptLockEndorsementTTL(ctx.round.height),
-			),
+			ctx.round.StartTime().Add(ctx.commitPhaseOffset(ctx.round.height)),
 		)
 	default:
 		return nil, err
@@ -461,9 +527,7 @@ func (ctx *rollDPoSCtx) Commit(msg interface{}) (bool, error) {
 	ctx.logger().Info("consensus reached", zap.Uint64("blockHeight", ctx.round.Height()))
 	if err := pendingBlock.Finalize(
 		ctx.round.Endorsements(blkHash, []ConsensusVoteTopic{COMMIT}),
-		ctx.round.StartTime().Add(
-			ctx.AcceptBlockTTL(ctx.round.height)+ctx.AcceptProposalEndorsementTTL(ctx.round.height)+ctx.AcceptLockEndorsementTTL(ctx.round.height),
-		),
+		ctx.round.StartTime().Add(ctx.commitPhaseOffset(ctx.round.height)),
 	); err != nil {
 		return false, errors.Wrap(err, "failed to add endorsements to block")
 	}
@@ -543,6 +607,58 @@ func (ctx *rollDPoSCtx) IsFutureEvent(evt *consensusfsm.ConsensusEvent) bool {
 	return ctx.round.IsFuture(evt.Height(), evt.Round())
 }
 
+// AcceptProposalEndorsementTTL returns the ttl of accepting the proposal endorsements, extended by the timing tuner
+func (ctx *rollDPoSCtx) AcceptProposalEndorsementTTL(height uint64) time.Duration {
+	return ctx.ConsensusConfig.AcceptProposalEndorsementTTL(height) + ctx.ttlExtension()
+}
+
+// AcceptLockEndorsementTTL returns the ttl of accepting the lock endorsements, extended by the timing tuner
+func (ctx *rollDPoSCtx) AcceptLockEndorsementTTL(height uint64) time.Duration {
+	return ctx.ConsensusConfig.AcceptLockEndorsementTTL(height) + ctx.ttlExtension()
+}
+
+// CommitTTL returns the ttl of accepting the commit endorsements, extended by the timing tuner
+func (ctx *rollDPoSCtx) CommitTTL(height uint64) time.Duration {
+	return ctx.ConsensusConfig.CommitTTL(height) + ctx.ttlExtension()
+}
+
+func (ctx *rollDPoSCtx) ttlExtension() time.Duration {
+	if ctx.tuner == nil {
+		return 0
+	}
+	return ctx.tuner.Extension()
+}
+
+// lockPhaseOffset returns the offset of the lock phase in the round. The timestamps of the endorsements are the starts
+// of their phases, which are derived from the nominal ttls rather than the extended ones, so all delegates agree on them
+func (ctx *rollDPoSCtx) lockPhaseOffset(height uint64) time.Duration {
+	return ctx.AcceptBlockTTL(height) + ctx.ConsensusConfig.AcceptProposalEndorsementTTL(height)
+}
+
+// commitPhaseOffset returns the offset of the commit phase in the round
+func (ctx *rollDPoSCtx) commitPhaseOffset(height uint64) time.Duration {
+	return ctx.lockPhaseOffset(height) + ctx.ConsensusConfig.AcceptLockEndorsementTTL(height)
+}
+
+// observeEndorsementLateness measures how late the endorsement arrives after the nominal ttl of its phase
+func (ctx *rollDPoSCtx) observeEndorsementLateness(height uint64, vote *ConsensusVote, en *endorsement.Endorsement) {
+	if ctx.tuner == nil {
+		return
+	}
+	var ttl time.Duration
+	switch vote.Topic() {
+	case PROPOSAL:
+		ttl = ctx.ConsensusConfig.AcceptProposalEndorsementTTL(height)
+	case LOCK:
+		ttl = ctx.ConsensusConfig.AcceptLockEndorsementTTL(height)
+	case COMMIT:
+		ttl = ctx.ConsensusConfig.CommitTTL(height)
+	default:
+		return
+	}
+	ctx.tuner.Observe(height, time.Since(en.Timestamp())-ttl)
+}
+
 func (ctx *rollDPoSCtx) IsStaleUnmatchedEvent(evt *consensusfsm.ConsensusEvent) bool {
 	ctx.mutex.RLock()
 	defer ctx.mutex.RUnlock()
@@ -605,6 +721,12 @@ func (ctx *rollDPoSCtx) isDelegate() bool {
 }
 
 func (ctx *rollDPoSCtx) endorseBlockProposal(proposal *blockProposal) (*EndorsedConsensusMessage, error) {
+	if ctx.signGuard != nil {
+		blkHash := proposal.block.HashBlock()
+		if err := ctx.signGuard.Check(ctx.encodedAddr, proposal.block.Height(), ctx.round.Number(), _blockKind, blkHash[:]); err != nil {
+			return nil, err
+		}
+	}
 	en, err := endorsement.Endorse(ctx.priKey, proposal, ctx.round.StartTime())
 	if err != nil {
 		return nil, err
@@ -691,6 +813,11 @@ func (ctx *rollDPoSCtx) newEndorsement(
 	topic ConsensusVoteTopic,
 	timestamp time.Time,
 ) (*EndorsedConsensusMessage, error) {
+	if ctx.signGuard != nil {
+		if err := ctx.signGuard.Check(ctx.encodedAddr, ctx.round.Height(), ctx.round.Number(), voteKind(topic), blkHash); err != nil {
+			return nil, err
+		}
+	}
 	vote := NewConsensusVote(
 		blkHash,
 		topic,
@@ -36,12 +36,12 @@ func TestRollDPoSCtx(t *testing.T) {
 	b, _, _, _, _ := makeChain(t)
 
 	t.Run("case 1:panic because of chain is nil", func(t *testing.T) {
-		_, err := newRollDPoSCtx(consensusfsm.NewConsensusConfig(cfg), dbConfig, true, time.Second, true, nil, nil, nil, dummyCandidatesByHeightFunc, "", nil, 0)
+		_, err := newRollDPoSCtx(consensusfsm.NewConsensusConfig(cfg), dbConfig, true, time.Second, 0, true, nil, nil, nil, dummyCandidatesByHeightFunc, "", nil, 0, 0)
 		require.Error(err)
 	})
 
 	t.Run("case 2:panic because of rp is nil", func(t *testing.T) {
-		_, err := newRollDPoSCtx(consensusfsm.NewConsensusConfig(cfg), dbConfig, true, time.Second, true, b, nil, nil, dummyCandidatesByHeightFunc, "", nil, 0)
+		_, err := newRollDPoSCtx(consensusfsm.NewConsensusConfig(cfg), dbConfig, true, time.Second, 0, true, b, nil, nil, dummyCandidatesByHeightFunc, "", nil, 0, 0)
 		require.Error(err)
 	})
 
@@ -56,19 +56,19 @@ func TestRollDPoSCtx(t *testing.T) {
 	cfg.Consensus.RollDPoS.FSM.AcceptLockEndorsementTTL = time.Second
 	cfg.Consensus.RollDPoS.FSM.CommitTTL = time.Second
 	t.Run("case 4:panic because of fsm time bigger than block interval", func(t *testing.T) {
-		_, err := newRollDPoSCtx(consensusfsm.NewConsensusConfig(cfg), dbConfig, true, time.Second, true, b, rp, nil, dummyCandidatesByHeightFunc, "", nil, 0)
+		_, err := newRollDPoSCtx(consensusfsm.NewConsensusConfig(cfg), dbConfig, true, time.Second, 0, true, b, rp, nil, dummyCandidatesByHeightFunc, "", nil, 0, 0)
 		require.Error(err)
 	})
 
 	cfg.Genesis.Blockchain.BlockInterval = time.Second * 20
 	t.Run("case 5:panic because of nil CandidatesByHeight function", func(t *testing.T) {
-		_, err := newRollDPoSCtx(consensusfsm.NewConsensusConfig(cfg), dbConfig, true, time.Second, true, b, rp, nil, nil, "", nil, 0)
+		_, err := newRollDPoSCtx(consensusfsm.NewConsensusConfig(cfg), dbConfig, true, time.Second, 0, true, b, rp, nil, nil, "", nil, 0, 0)
 		require.Error(err)
 	})
 
 	t.Run("case 6:normal", func(t *testing.T) {
 		bh := config.Default.Genesis.BeringBlockHeight
-		rctx, err := newRollDPoSCtx(consensusfsm.NewConsensusConfig(cfg), dbConfig, true, time.Second, true, b, rp, nil, dummyCandidatesByHeightFunc, "", nil, bh)
+		rctx, err := newRollDPoSCtx(consensusfsm.NewConsensusConfig(cfg), dbConfig, true, time.Second, 0, true, b, rp, nil, dummyCandidatesByHeightFunc, "", nil, bh, config.Default.Genesis.JutlandBlockHeight)
 		require.NoError(err)
 		require.Equal(bh, rctx.roundCalc.beringHeight)
 		require.NotNil(rctx)
@@ -85,6 +85,7 @@ func TestCheckVoteEndorser(t *testing.T) {
 		config.Default.DB,
 		true,
 		time.Second,
+		0,
 		true,
 		b,
 		rp,
@@ -127,6 +128,7 @@ func TestCheckVoteEndorser(t *testing.T) {
 		"",
 		nil,
 		config.Default.Genesis.BeringBlockHeight,
+		config.Default.Genesis.JutlandBlockHeight,
 	)
 	require.NoError(err)
 	require.NotNil(rctx)
@@ -153,6 +155,7 @@ func TestCheckBlockProposer(t *testing.T) {
 		config.Default.DB,
 		true,
 		time.Second,
+		0,
 		true,
 		b,
 		rp,
@@ -195,6 +198,7 @@ func TestCheckBlockProposer(t *testing.T) {
 		"",
 		nil,
 		config.Default.Genesis.BeringBlockHeight,
+		config.Default.Genesis.JutlandBlockHeight,
 	)
 	require.NoError(err)
 	require.NotNil(rctx)
@@ -250,6 +254,58 @@ func TestCheckBlockProposer(t *testing.T) {
 	require.NoError(rctx.CheckBlockProposer(51, bp, en))
 }
 
+func TestCheckBlockTimestamp(t *testing.T) {
+	require := require.New(t)
+	cfg := config.Default
+	b, _, _, rp, _ := makeChain(t)
+	rctx, err := newRollDPoSCtx(
+		consensusfsm.NewConsensusConfig(cfg),
+		config.Default.DB,
+		true,
+		time.Second,
+		5*time.Second,
+		true,
+		b,
+		rp,
+		nil,
+		dummyCandidatesByHeightFunc,
+		"",
+		nil,
+		config.Default.Genesis.BeringBlockHeight,
+		config.Default.Genesis.JutlandBlockHeight,
+	)
+	require.NoError(err)
+	parent, err := b.BlockHeaderByHeight(50)
+	require.NoError(err)
+
+	proposal := func(ts time.Time) *blockProposal {
+		blk, err := block.NewTestingBuilder().
+			SetHeight(51).
+			SetTimeStamp(ts).
+			SignAndBuild(identityset.PrivateKey(1))
+		require.NoError(err)
+		return newBlockProposal(&blk, nil)
+	}
+
+	// case 1:not after the parent block
+	require.Equal(ErrInvalidBlockTimestamp, errors.Cause(rctx.checkBlockTimestamp(51, proposal(parent.Timestamp()), true)))
+
+	// case 2:too far ahead of the local clock
+	require.Equal(ErrInvalidBlockTimestamp, errors.Cause(rctx.checkBlockTimestamp(51, proposal(time.Now().Add(time.Minute)), true)))
+
+	// case 3:normal
+	require.NoError(rctx.checkBlockTimestamp(51, proposal(time.Now()), true))
+	require.NoError(rctx.checkBlockTimestamp(51, proposal(time.Now().Add(2*time.Second)), true))
+	require.NoError(rctx.checkBlockTimestamp(51, proposal(parent.Timestamp().Add(time.Second)), false))
+
+	// case 4:drift check disabled
+	rctx.timestampDrift = 0
+	require.NoError(rctx.checkBlockTimestamp(51, proposal(time.Now().Add(time.Minute)), true))
+
+	// case 5:parent block does not exist
+	require.Error(rctx.checkBlockTimestamp(100, proposal(time.Now()), true))
+}
+
 func TestNotProducingMultipleBlocks(t *testing.T) {
 	require := require.New(t)
 	cfg := config.Default
@@ -260,6 +316,7 @@ func TestNotProducingMultipleBlocks(t *testing.T) {
 		config.Default.DB,
 		true,
 		time.Second,
+		0,
 		true,
 		b,
 		rp,
@@ -302,6 +359,7 @@ func TestNotProducingMultipleBlocks(t *testing.T) {
 		"",
 		identityset.PrivateKey(10),
 		config.Default.Genesis.BeringBlockHeight,
+		config.Default.Genesis.JutlandBlockHeight,
 	)
 	require.NoError(err)
 	require.NotNil(rctx)
@@ -22,6 +22,7 @@ type roundCalculator struct {
 	rp                   *rolldpos.Protocol
 	delegatesByEpochFunc DelegatesByEpochFunc
 	beringHeight         uint64
+	jutlandHeight        uint64
 }
 
 // UpdateRound updates previous roundCtx
@@ -252,7 +253,11 @@ func (c *roundCalculator) calculateProposer(
 	delegates []string,
 ) (proposer string, err error) {
 	numDelegates := c.rp.NumDelegates()
-	if numDelegates != uint64(len(delegates)) {
+	if height >= c.jutlandHeight {
+		// the active block producers are resized with the qualified candidates since jutland height
+		numDelegates = uint64(len(delegates))
+	}
+	if numDelegates == 0 || numDelegates != uint64(len(delegates)) {
 		err = errors.New("invalid delegate list")
 		return
 	}
@@ -91,6 +91,21 @@ func TestNewRound(t *testing.T) {
 	require.Equal(identityset.Address(12).String(), ra.proposer)
 }
 
+func TestCalculateProposerAfterJutland(t *testing.T) {
+	require := require.New(t)
+	rc := makeRoundCalculator(t)
+	rc.jutlandHeight = 10
+	delegates := []string{"1", "2", "3", "4", "5"}
+	_, err := rc.calculateProposer(9, 1, delegates)
+	require.Error(err)
+	// the active block producers are resized
+	proposer, err := rc.calculateProposer(10, 1, delegates)
+	require.NoError(err)
+	require.Equal("2", proposer)
+	_, err = rc.calculateProposer(10, 1, nil)
+	require.Error(err)
+}
+
 func TestDelegates(t *testing.T) {
 	require := require.New(t)
 	rc := makeRoundCalculator(t)
@@ -260,5 +275,6 @@ func makeRoundCalculator(t *testing.T) *roundCalculator {
 			return addrs, nil
 		},
 		0,
+		config.Default.Genesis.JutlandBlockHeight,
 	}
 }
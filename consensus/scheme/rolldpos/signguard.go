@@ -0,0 +1,198 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package rolldpos
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"sync"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/iotexproject/iotex-core/pkg/log"
+)
+
+// _blockKind is the kind of the signed message proposing a block, while the consensus votes are told by their topics
+const _blockKind = "block"
+
+// ErrConflictingSignature indicates that signing the message would conflict with a message signed by the same key
+// before, i.e., a different block of the same height and round, or a lower height or round than the last signed
+var ErrConflictingSignature = errors.New("conflicting with a signed message")
+
+type (
+	// signGuard is the last line of defense against a delegate double signing by accident, e.g., restarting from a
+	// stale consensus db or running two nodes of the same key after a misconfigured failover. It persists the last
+	// signed (height, round) of each key into a ledger before any message is signed, and refuses to sign a conflicting
+	// one. A lockfile next to the ledger keeps more than one node from using the ledger at the same time
+	signGuard struct {
+		path   string
+		lock   *os.File
+		ledger map[string]*signRecord
+		mutex  sync.Mutex
+	}
+
+	// signRecord is the last (height, round) signed by a key, along with the hashes of the blocks signed in the round
+	// by the kind of the messages
+	signRecord struct {
+		Height uint64            `json:"height"`
+		Round  uint32            `json:"round"`
+		Blocks map[string]string `json:"blocks"`
+	}
+)
+
+func newSignGuard(path string) *signGuard {
+	return &signGuard{
+		path:   path,
+		ledger: map[string]*signRecord{},
+	}
+}
+
+// Start acquires the lockfile and loads the ledger
+func (g *signGuard) Start(_ context.Context) error {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	f, err := os.OpenFile(g.path+".lock", os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return errors.Wrap(err, "failed to open the lockfile of the sign ledger")
+	}
+	if err := lockFile(f); err != nil {
+		f.Close()
+		return errors.Wrapf(err, "sign ledger %s is in use by another node", g.path)
+	}
+	if err := f.Truncate(0); err == nil {
+		f.WriteString(strconv.Itoa(os.Getpid()))
+	}
+	g.lock = f
+	data, err := ioutil.ReadFile(g.path)
+	switch {
+	case os.IsNotExist(err), err == nil && len(data) == 0:
+		return nil
+	case err != nil:
+		return errors.Wrap(err, "failed to read the sign ledger")
+	}
+	if err := json.Unmarshal(data, &g.ledger); err != nil {
+		return errors.Wrap(err, "failed to parse the sign ledger")
+	}
+	return nil
+}
+
+// Stop releases the lockfile
+func (g *signGuard) Stop(_ context.Context) error {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	if g.lock == nil {
+		return nil
+	}
+	err := unlockFile(g.lock)
+	if cerr := g.lock.Close(); err == nil {
+		err = cerr
+	}
+	g.lock = nil
+	return err
+}
+
+// Check records the block of the kind to be signed by the signer at the height and round into the ledger, or returns
+// ErrConflictingSignature if it conflicts with the messages signed before. An empty hash, i.e., endorsing no block, is
+// never conflicting
+func (g *signGuard) Check(signer string, height uint64, round uint32, kind string, blkHash []byte) error {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	if g.lock == nil {
+		return errors.New("sign guard is not started")
+	}
+	last, ok := g.ledger[signer]
+	rec := last
+	switch {
+	case !ok, height > last.Height, height == last.Height && round > last.Round:
+		rec = &signRecord{Height: height, Round: round, Blocks: map[string]string{}}
+	case height < last.Height, round < last.Round:
+		log.L().Error(
+			"Refused to sign a message lower than the last signed.",
+			zap.String("signer", signer),
+			zap.Uint64("height", height),
+			zap.Uint32("round", round),
+			zap.Uint64("lastHeight", last.Height),
+			zap.Uint32("lastRound", last.Round),
+		)
+		return errors.Wrapf(ErrConflictingSignature, "last signed height %d round %d", last.Height, last.Round)
+	}
+	if len(blkHash) == 0 {
+		return nil
+	}
+	encoded := hex.EncodeToString(blkHash)
+	if signed, ok := rec.Blocks[kind]; ok {
+		if signed == encoded {
+			return nil
+		}
+		log.L().Error(
+			"Refused to sign a conflicting message.",
+			zap.String("signer", signer),
+			zap.Uint64("height", height),
+			zap.Uint32("round", round),
+			zap.String("kind", kind),
+			zap.String("signed", signed),
+			zap.String("block", encoded),
+		)
+		return errors.Wrapf(ErrConflictingSignature, "signed %s of block %s at height %d round %d", kind, signed, height, round)
+	}
+	rec.Blocks[kind] = encoded
+	g.ledger[signer] = rec
+	if err := g.flush(); err != nil {
+		// the block is not signed if it is not recorded
+		delete(rec.Blocks, kind)
+		if last != nil {
+			g.ledger[signer] = last
+		} else {
+			delete(g.ledger, signer)
+		}
+		return err
+	}
+	return nil
+}
+
+// flush writes the ledger into a temporary file and renames it, so that a crash never leaves a partial ledger
+func (g *signGuard) flush() error {
+	data, err := json.Marshal(g.ledger)
+	if err != nil {
+		return err
+	}
+	tmp := g.path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return errors.Wrap(err, "failed to write the sign ledger")
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return errors.Wrap(err, "failed to write the sign ledger")
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return errors.Wrap(err, "failed to sync the sign ledger")
+	}
+	if err := f.Close(); err != nil {
+		return errors.Wrap(err, "failed to write the sign ledger")
+	}
+	return errors.Wrap(os.Rename(tmp, g.path), "failed to replace the sign ledger")
+}
+
+func voteKind(topic ConsensusVoteTopic) string {
+	switch topic {
+	case PROPOSAL:
+		return "proposal"
+	case LOCK:
+		return "lock"
+	case COMMIT:
+		return "commit"
+	default:
+		return strconv.Itoa(int(topic))
+	}
+}
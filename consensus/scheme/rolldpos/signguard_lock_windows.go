@@ -0,0 +1,28 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+//go:build windows
+// +build windows
+
+package rolldpos
+
+import (
+	"os"
+
+	"go.uber.org/zap"
+
+	"github.com/iotexproject/iotex-core/pkg/log"
+)
+
+// lockFile is not supported on windows, where the sign ledger is guarded without the lockfile
+func lockFile(f *os.File) error {
+	log.L().Warn("Lockfile of the sign ledger is not supported on windows.", zap.String("file", f.Name()))
+	return nil
+}
+
+func unlockFile(_ *os.File) error {
+	return nil
+}
@@ -0,0 +1,66 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package rolldpos
+
+import (
+	"context"
+	"runtime"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/testutil"
+)
+
+func TestSignGuard(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+	path, err := testutil.PathOfTempFile("signledger")
+	require.NoError(err)
+	defer func() {
+		testutil.CleanupPath(t, path)
+		testutil.CleanupPath(t, path+".lock")
+	}()
+
+	g := newSignGuard(path)
+	require.Error(g.Check("a", 10, 0, _blockKind, []byte{1}))
+	require.NoError(g.Start(ctx))
+	require.NoError(g.Check("a", 10, 0, _blockKind, []byte{1}))
+	require.NoError(g.Check("a", 10, 0, voteKind(PROPOSAL), []byte{1}))
+	// signing the same block again is fine
+	require.NoError(g.Check("a", 10, 0, _blockKind, []byte{1}))
+	// endorsing no block is never conflicting
+	require.NoError(g.Check("a", 10, 0, voteKind(LOCK), nil))
+	require.NoError(g.Check("a", 10, 0, voteKind(LOCK), []byte{1}))
+	// a different block of the same height and round
+	require.Equal(ErrConflictingSignature, errors.Cause(g.Check("a", 10, 0, _blockKind, []byte{2})))
+	require.Equal(ErrConflictingSignature, errors.Cause(g.Check("a", 10, 0, voteKind(LOCK), []byte{2})))
+	// another key is guarded separately
+	require.NoError(g.Check("b", 10, 0, voteKind(LOCK), []byte{2}))
+	// a different block in the next round
+	require.NoError(g.Check("a", 10, 1, voteKind(PROPOSAL), []byte{2}))
+	// lower round or height than the last signed
+	require.Equal(ErrConflictingSignature, errors.Cause(g.Check("a", 10, 0, voteKind(COMMIT), []byte{1})))
+	require.Equal(ErrConflictingSignature, errors.Cause(g.Check("a", 9, 3, voteKind(PROPOSAL), []byte{1})))
+
+	if runtime.GOOS != "windows" {
+		// the ledger in use cannot be started by another node
+		require.Error(newSignGuard(path).Start(ctx))
+	}
+
+	// the ledger survives restart
+	require.NoError(g.Stop(ctx))
+	g = newSignGuard(path)
+	require.NoError(g.Start(ctx))
+	defer func() {
+		require.NoError(g.Stop(ctx))
+	}()
+	require.Equal(ErrConflictingSignature, errors.Cause(g.Check("a", 10, 1, voteKind(PROPOSAL), []byte{3})))
+	require.NoError(g.Check("a", 10, 1, voteKind(PROPOSAL), []byte{2}))
+	require.NoError(g.Check("a", 11, 0, _blockKind, []byte{3}))
+}
@@ -0,0 +1,95 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package rolldpos
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/iotexproject/iotex-core/action/protocol/rolldpos"
+)
+
+var ttlExtensionMtc = prometheus.NewGauge(
+	prometheus.GaugeOpts{
+		Name: "iotex_consensus_endorsement_ttl_extension",
+		Help: "Extension in seconds of each endorsement ttl tuned by the endorsement lateness of the last epoch",
+	},
+)
+
+func init() {
+	prometheus.MustRegister(ttlExtensionMtc)
+}
+
+// timingTuner measures how late the endorsements arrive after the ttls of their phases in each epoch, and extends
+// the endorsement ttls of the next epoch to accept the given percentile of them, bounded by the max extension
+type timingTuner struct {
+	mutex        sync.RWMutex
+	rp           *rolldpos.Protocol
+	percentile   int
+	maxExtension time.Duration
+	epochNum     uint64
+	lateness     []time.Duration
+	extension    time.Duration
+}
+
+func newTimingTuner(rp *rolldpos.Protocol, percentile int, maxExtension time.Duration) *timingTuner {
+	return &timingTuner{
+		rp:           rp,
+		percentile:   percentile,
+		maxExtension: maxExtension,
+	}
+}
+
+// Observe records the lateness of an endorsement of the block at height, which is negative if it arrives in time
+func (t *timingTuner) Observe(height uint64, lateness time.Duration) {
+	epochNum := t.rp.GetEpochNum(height)
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	switch {
+	case epochNum < t.epochNum:
+		return
+	case epochNum > t.epochNum:
+		if epochNum == t.epochNum+1 {
+			t.extension = t.tune()
+		} else {
+			// the measurement is too old to tell the latency of now
+			t.extension = 0
+		}
+		ttlExtensionMtc.Set(t.extension.Seconds())
+		t.epochNum = epochNum
+		t.lateness = t.lateness[:0]
+	}
+	t.lateness = append(t.lateness, lateness)
+}
+
+// Extension returns the time each endorsement ttl is extended by
+func (t *timingTuner) Extension() time.Duration {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+	return t.extension
+}
+
+func (t *timingTuner) tune() time.Duration {
+	if len(t.lateness) == 0 {
+		return 0
+	}
+	sort.Slice(t.lateness, func(i, j int) bool {
+		return t.lateness[i] < t.lateness[j]
+	})
+	ext := t.lateness[(len(t.lateness)*t.percentile+99)/100-1]
+	switch {
+	case ext < 0:
+		return 0
+	case ext > t.maxExtension:
+		return t.maxExtension
+	default:
+		return ext
+	}
+}
@@ -0,0 +1,52 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package rolldpos
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/action/protocol/rolldpos"
+)
+
+func TestTimingTuner(t *testing.T) {
+	require := require.New(t)
+
+	// 4 blocks per epoch
+	tuner := newTimingTuner(rolldpos.NewProtocol(4, 4, 1), 75, 500*time.Millisecond)
+	require.Zero(tuner.Extension())
+
+	// epoch 1, 3 of 4 endorsements are in time
+	for _, lateness := range []time.Duration{-time.Second, -200 * time.Millisecond, 0, 300 * time.Millisecond} {
+		tuner.Observe(1, lateness)
+	}
+	require.Zero(tuner.Extension())
+
+	// epoch 2 is not extended, as 75% of the endorsements in epoch 1 are in time
+	tuner.Observe(5, time.Second)
+	tuner.Observe(6, 2*time.Second)
+	tuner.Observe(7, 2*time.Second)
+	tuner.Observe(8, 3*time.Second)
+	require.Zero(tuner.Extension())
+	// stale observation is ignored
+	tuner.Observe(1, 10*time.Second)
+
+	// epoch 3 is extended by the max extension, rather than the 2s to accept 75% of the endorsements in epoch 2
+	tuner.Observe(9, -time.Second)
+	require.Equal(500*time.Millisecond, tuner.Extension())
+
+	// epoch 4 is not extended, as all endorsements in epoch 3 are in time
+	tuner.Observe(13, 0)
+	require.Zero(tuner.Extension())
+	tuner.Observe(13, 100*time.Millisecond)
+
+	// epoch 5 is not measured, so epoch 6 is not extended by the 100ms late in epoch 4
+	tuner.Observe(21, 0)
+	require.Zero(tuner.Extension())
+}
@@ -0,0 +1,397 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+// Package dashboard serves a lightweight web dashboard of the chain from the node itself: the recent blocks, the
+// delegates of the current epoch, the pending actions, and the blocks, actions and addresses found by search. It reads
+// everything from the api of the node, so that devnets and private chains could be browsed without an explorer.
+package dashboard
+
+import (
+	"context"
+	"fmt"
+	"html/template"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/iotexproject/iotex-address/address"
+	"github.com/iotexproject/iotex-proto/golang/iotexapi"
+	"github.com/iotexproject/iotex-proto/golang/iotextypes"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/iotexproject/iotex-core/action"
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/pkg/log"
+	"github.com/iotexproject/iotex-core/pkg/util/httputil"
+)
+
+// _maxActions is the max number of the actions listed on a page
+const _maxActions = 100
+
+var _templates = template.Must(template.New("dashboard").Parse(_pageTemplates))
+
+type (
+	// APIServer is the api of the node the dashboard reads from
+	APIServer interface {
+		GetChainMeta(context.Context, *iotexapi.GetChainMetaRequest) (*iotexapi.GetChainMetaResponse, error)
+		GetBlockMetas(context.Context, *iotexapi.GetBlockMetasRequest) (*iotexapi.GetBlockMetasResponse, error)
+		GetActions(context.Context, *iotexapi.GetActionsRequest) (*iotexapi.GetActionsResponse, error)
+		GetAccount(context.Context, *iotexapi.GetAccountRequest) (*iotexapi.GetAccountResponse, error)
+		GetEpochMeta(context.Context, *iotexapi.GetEpochMetaRequest) (*iotexapi.GetEpochMetaResponse, error)
+		GetActPoolActions(context.Context, *iotexapi.GetActPoolActionsRequest) (*iotexapi.GetActPoolActionsResponse, error)
+	}
+
+	// Dashboard is the http endpoint serving the pages of the dashboard
+	Dashboard struct {
+		cfg    config.Dashboard
+		api    APIServer
+		server http.Server
+	}
+
+	blockView struct {
+		Height     uint64
+		Hash       string
+		PrevHash   string
+		Producer   string
+		NumActions int64
+		Timestamp  time.Time
+	}
+
+	actionView struct {
+		Hash      string
+		Sender    string
+		Recipient string
+		Amount    string
+		Nonce     uint64
+		GasFee    string
+		BlkHeight uint64
+	}
+
+	overviewPage struct {
+		Height     uint64
+		Epoch      uint64
+		Blocks     []*blockView
+		Delegates  []*iotexapi.BlockProducerInfo
+		NumPending int
+	}
+
+	blockPage struct {
+		Block   *blockView
+		Actions []*actionView
+	}
+
+	addressPage struct {
+		Account *iotextypes.AccountMeta
+		Actions []*actionView
+	}
+
+	mempoolPage struct {
+		Actions []*actionView
+	}
+)
+
+// NewDashboard creates a dashboard reading from the api
+func NewDashboard(cfg config.Dashboard, api APIServer) *Dashboard {
+	d := &Dashboard{
+		cfg: cfg,
+		api: api,
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", d.handleOverview)
+	mux.HandleFunc("/block/", d.handleBlock)
+	mux.HandleFunc("/action/", d.handleAction)
+	mux.HandleFunc("/address/", d.handleAddress)
+	mux.HandleFunc("/mempool", d.handleMempool)
+	mux.HandleFunc("/search", d.handleSearch)
+	d.server = httputil.Server(fmt.Sprintf(":%d", cfg.Port), mux)
+	return d
+}
+
+// Start starts the http endpoint of the dashboard
+func (d *Dashboard) Start(_ context.Context) error {
+	ln, err := httputil.LimitListener(d.server.Addr)
+	if err != nil {
+		return errors.Wrap(err, "failed to listen on dashboard port")
+	}
+	go func() {
+		if err := d.server.Serve(ln); err != nil {
+			log.L().Info("Dashboard server stopped.", zap.Error(err))
+		}
+	}()
+	log.L().Info("Dashboard started.", zap.Int("port", d.cfg.Port))
+	return nil
+}
+
+// Stop stops the http endpoint of the dashboard
+func (d *Dashboard) Stop(ctx context.Context) error { return d.server.Shutdown(ctx) }
+
+func (d *Dashboard) handleOverview(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		d.renderError(w, http.StatusNotFound, errors.Errorf("page %s not found", r.URL.Path))
+		return
+	}
+	ctx := r.Context()
+	res, err := d.api.GetChainMeta(ctx, &iotexapi.GetChainMetaRequest{})
+	if err != nil {
+		d.renderError(w, http.StatusInternalServerError, err)
+		return
+	}
+	page := &overviewPage{
+		Height: res.GetChainMeta().GetHeight(),
+		Epoch:  res.GetChainMeta().GetEpoch().GetNum(),
+	}
+	if page.Height > 0 {
+		count := d.cfg.NumBlocks
+		if count > page.Height {
+			count = page.Height
+		}
+		if page.Blocks, err = d.blocks(ctx, &iotexapi.GetBlockMetasRequest{
+			Lookup: &iotexapi.GetBlockMetasRequest_ByIndex{
+				ByIndex: &iotexapi.GetBlockMetasByIndexRequest{Start: page.Height - count + 1, Count: count},
+			},
+		}); err != nil {
+			d.renderError(w, http.StatusInternalServerError, err)
+			return
+		}
+		// the latest block first
+		for i, j := 0, len(page.Blocks)-1; i < j; i, j = i+1, j-1 {
+			page.Blocks[i], page.Blocks[j] = page.Blocks[j], page.Blocks[i]
+		}
+	}
+	if page.Epoch > 0 {
+		// the delegates are not shown without the poll protocol, e.g. in standalone mode
+		if epochMeta, err := d.api.GetEpochMeta(ctx, &iotexapi.GetEpochMetaRequest{EpochNumber: page.Epoch}); err == nil {
+			page.Delegates = epochMeta.GetBlockProducersInfo()
+		}
+	}
+	pending, err := d.api.GetActPoolActions(ctx, &iotexapi.GetActPoolActionsRequest{})
+	if err != nil {
+		d.renderError(w, http.StatusInternalServerError, err)
+		return
+	}
+	page.NumPending = len(pending.GetActions())
+	d.render(w, "overview", page)
+}
+
+func (d *Dashboard) handleBlock(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := strings.TrimPrefix(r.URL.Path, "/block/")
+	req := &iotexapi.GetBlockMetasRequest{
+		Lookup: &iotexapi.GetBlockMetasRequest_ByHash{
+			ByHash: &iotexapi.GetBlockMetaByHashRequest{BlkHash: id},
+		},
+	}
+	if height, err := strconv.ParseUint(id, 10, 64); err == nil {
+		req.Lookup = &iotexapi.GetBlockMetasRequest_ByIndex{
+			ByIndex: &iotexapi.GetBlockMetasByIndexRequest{Start: height, Count: 1},
+		}
+	}
+	blocks, err := d.blocks(ctx, req)
+	if err != nil || len(blocks) == 0 {
+		d.renderError(w, http.StatusNotFound, errors.Errorf("block %s not found", id))
+		return
+	}
+	page := &blockPage{Block: blocks[0]}
+	if count := page.Block.NumActions; count > 0 {
+		if count > _maxActions {
+			count = _maxActions
+		}
+		if page.Actions, err = d.actions(ctx, &iotexapi.GetActionsRequest{
+			Lookup: &iotexapi.GetActionsRequest_ByBlk{
+				ByBlk: &iotexapi.GetActionsByBlockRequest{BlkHash: page.Block.Hash, Start: 0, Count: uint64(count)},
+			},
+		}); err != nil {
+			d.renderError(w, http.StatusInternalServerError, err)
+			return
+		}
+	}
+	d.render(w, "block", page)
+}
+
+func (d *Dashboard) handleAction(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimPrefix(r.URL.Path, "/action/")
+	acts, err := d.actions(r.Context(), &iotexapi.GetActionsRequest{
+		Lookup: &iotexapi.GetActionsRequest_ByHash{
+			ByHash: &iotexapi.GetActionByHashRequest{ActionHash: id, CheckPending: true},
+		},
+	})
+	if err != nil || len(acts) == 0 {
+		d.renderError(w, http.StatusNotFound, errors.Errorf("action %s not found", id))
+		return
+	}
+	d.render(w, "action", acts[:1])
+}
+
+func (d *Dashboard) handleAddress(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := strings.TrimPrefix(r.URL.Path, "/address/")
+	if _, err := address.FromString(id); err != nil {
+		d.renderError(w, http.StatusBadRequest, errors.Errorf("invalid address %s", id))
+		return
+	}
+	res, err := d.api.GetAccount(ctx, &iotexapi.GetAccountRequest{Address: id})
+	if err != nil {
+		d.renderError(w, http.StatusNotFound, err)
+		return
+	}
+	page := &addressPage{Account: res.GetAccountMeta()}
+	if num := uint64(page.Account.GetNumActions()); num > 0 {
+		// the latest actions of the address
+		count := uint64(_maxActions)
+		if count > num {
+			count = num
+		}
+		if page.Actions, err = d.actions(ctx, &iotexapi.GetActionsRequest{
+			Lookup: &iotexapi.GetActionsRequest_ByAddr{
+				ByAddr: &iotexapi.GetActionsByAddressRequest{Address: id, Start: num - count, Count: count},
+			},
+		}); err != nil {
+			d.renderError(w, http.StatusInternalServerError, err)
+			return
+		}
+	}
+	d.render(w, "address", page)
+}
+
+func (d *Dashboard) handleMempool(w http.ResponseWriter, r *http.Request) {
+	res, err := d.api.GetActPoolActions(r.Context(), &iotexapi.GetActPoolActionsRequest{})
+	if err != nil {
+		d.renderError(w, http.StatusInternalServerError, err)
+		return
+	}
+	page := &mempoolPage{}
+	for _, pb := range res.GetActions() {
+		if len(page.Actions) == _maxActions {
+			break
+		}
+		view, err := newActionView(pb)
+		if err != nil {
+			continue
+		}
+		page.Actions = append(page.Actions, view)
+	}
+	d.render(w, "mempool", page)
+}
+
+// handleSearch redirects to the page of the block height, the address, or the block or action hash
+func (d *Dashboard) handleSearch(w http.ResponseWriter, r *http.Request) {
+	q := strings.TrimSpace(r.URL.Query().Get("q"))
+	switch {
+	case q == "":
+		http.Redirect(w, r, "/", http.StatusFound)
+	case isNumber(q):
+		http.Redirect(w, r, "/block/"+q, http.StatusFound)
+	case isAddress(q):
+		http.Redirect(w, r, "/address/"+q, http.StatusFound)
+	default:
+		q = strings.TrimPrefix(q, "0x")
+		// a hash is either a block or an action
+		if _, err := d.blocks(r.Context(), &iotexapi.GetBlockMetasRequest{
+			Lookup: &iotexapi.GetBlockMetasRequest_ByHash{
+				ByHash: &iotexapi.GetBlockMetaByHashRequest{BlkHash: q},
+			},
+		}); err == nil {
+			http.Redirect(w, r, "/block/"+q, http.StatusFound)
+			return
+		}
+		http.Redirect(w, r, "/action/"+q, http.StatusFound)
+	}
+}
+
+func (d *Dashboard) blocks(ctx context.Context, req *iotexapi.GetBlockMetasRequest) ([]*blockView, error) {
+	res, err := d.api.GetBlockMetas(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	views := make([]*blockView, 0, len(res.GetBlkMetas()))
+	for _, meta := range res.GetBlkMetas() {
+		ts, err := ptypes.Timestamp(meta.GetTimestamp())
+		if err != nil {
+			return nil, err
+		}
+		views = append(views, &blockView{
+			Height:     meta.GetHeight(),
+			Hash:       meta.GetHash(),
+			PrevHash:   meta.GetPreviousBlockHash(),
+			Producer:   meta.GetProducerAddress(),
+			NumActions: meta.GetNumActions(),
+			Timestamp:  ts,
+		})
+	}
+	return views, nil
+}
+
+func (d *Dashboard) actions(ctx context.Context, req *iotexapi.GetActionsRequest) ([]*actionView, error) {
+	res, err := d.api.GetActions(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	views := make([]*actionView, 0, len(res.GetActionInfo()))
+	for _, info := range res.GetActionInfo() {
+		view, err := newActionView(info.GetAction())
+		if err != nil {
+			return nil, err
+		}
+		view.GasFee = info.GetGasFee()
+		view.BlkHeight = info.GetBlkHeight()
+		views = append(views, view)
+	}
+	return views, nil
+}
+
+func (d *Dashboard) render(w http.ResponseWriter, name string, page interface{}) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := _templates.ExecuteTemplate(w, name, page); err != nil {
+		log.L().Debug("Error when rendering dashboard page.", zap.String("page", name), zap.Error(err))
+	}
+}
+
+func (d *Dashboard) renderError(w http.ResponseWriter, code int, err error) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(code)
+	if err := _templates.ExecuteTemplate(w, "error", err.Error()); err != nil {
+		log.L().Debug("Error when rendering dashboard error page.", zap.Error(err))
+	}
+}
+
+func newActionView(pb *iotextypes.Action) (*actionView, error) {
+	selp := action.SealedEnvelope{}
+	if err := selp.LoadProto(pb); err != nil {
+		return nil, err
+	}
+	sender, err := address.FromBytes(selp.SrcPubkey().Hash())
+	if err != nil {
+		return nil, err
+	}
+	h := selp.Hash()
+	view := &actionView{
+		Hash:   fmt.Sprintf("%x", h[:]),
+		Sender: sender.String(),
+		Nonce:  selp.Nonce(),
+	}
+	switch act := selp.Action().(type) {
+	case *action.Transfer:
+		view.Recipient = act.Recipient()
+		view.Amount = act.Amount().String()
+	case *action.Execution:
+		view.Recipient = act.Contract()
+		view.Amount = act.Amount().String()
+	}
+	return view, nil
+}
+
+func isAddress(s string) bool {
+	_, err := address.FromString(s)
+	return err == nil
+}
+
+func isNumber(s string) bool {
+	_, err := strconv.ParseUint(s, 10, 64)
+	return err == nil
+}
@@ -0,0 +1,138 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package dashboard
+
+import (
+	"context"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-proto/golang/iotexapi"
+	"github.com/iotexproject/iotex-proto/golang/iotextypes"
+
+	"github.com/iotexproject/iotex-core/action"
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/test/identityset"
+)
+
+type fakeAPI struct {
+	height  uint64
+	pending []*iotextypes.Action
+}
+
+func (api *fakeAPI) GetChainMeta(context.Context, *iotexapi.GetChainMetaRequest) (*iotexapi.GetChainMetaResponse, error) {
+	return &iotexapi.GetChainMetaResponse{ChainMeta: &iotextypes.ChainMeta{
+		Height: api.height,
+		Epoch:  &iotextypes.EpochData{Num: 1},
+	}}, nil
+}
+
+func (api *fakeAPI) GetBlockMetas(_ context.Context, in *iotexapi.GetBlockMetasRequest) (*iotexapi.GetBlockMetasResponse, error) {
+	meta := func(height uint64) *iotextypes.BlockMeta {
+		return &iotextypes.BlockMeta{
+			Height:          height,
+			Hash:            "hash" + strconv.FormatUint(height, 10),
+			Timestamp:       ptypes.TimestampNow(),
+			ProducerAddress: identityset.Address(1).String(),
+		}
+	}
+	res := &iotexapi.GetBlockMetasResponse{}
+	if req := in.GetByIndex(); req != nil {
+		for h := req.Start; h < req.Start+req.Count && h <= api.height; h++ {
+			res.BlkMetas = append(res.BlkMetas, meta(h))
+		}
+		return res, nil
+	}
+	if in.GetByHash().GetBlkHash() == "hash1" {
+		res.BlkMetas = append(res.BlkMetas, meta(1))
+		return res, nil
+	}
+	return nil, errors.New("block not found")
+}
+
+func (api *fakeAPI) GetActions(_ context.Context, in *iotexapi.GetActionsRequest) (*iotexapi.GetActionsResponse, error) {
+	return nil, errors.New("action not found")
+}
+
+func (api *fakeAPI) GetAccount(_ context.Context, in *iotexapi.GetAccountRequest) (*iotexapi.GetAccountResponse, error) {
+	return &iotexapi.GetAccountResponse{AccountMeta: &iotextypes.AccountMeta{
+		Address: in.Address,
+		Balance: "12345",
+	}}, nil
+}
+
+func (api *fakeAPI) GetEpochMeta(context.Context, *iotexapi.GetEpochMetaRequest) (*iotexapi.GetEpochMetaResponse, error) {
+	return &iotexapi.GetEpochMetaResponse{BlockProducersInfo: []*iotexapi.BlockProducerInfo{
+		{Address: identityset.Address(2).String(), Votes: "100", Active: true, Production: 3},
+	}}, nil
+}
+
+func (api *fakeAPI) GetActPoolActions(context.Context, *iotexapi.GetActPoolActionsRequest) (*iotexapi.GetActPoolActionsResponse, error) {
+	return &iotexapi.GetActPoolActionsResponse{Actions: api.pending}, nil
+}
+
+func get(d *Dashboard, target string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	d.server.Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+	return rec
+}
+
+func TestDashboard(t *testing.T) {
+	require := require.New(t)
+
+	tsf, err := action.NewTransfer(1, big.NewInt(10), identityset.Address(3).String(), nil, 10000, big.NewInt(1))
+	require.NoError(err)
+	elp := (&action.EnvelopeBuilder{}).SetNonce(1).SetGasLimit(10000).SetGasPrice(big.NewInt(1)).SetAction(tsf).Build()
+	selp, err := action.Sign(elp, identityset.PrivateKey(4))
+	require.NoError(err)
+	api := &fakeAPI{height: 30, pending: []*iotextypes.Action{selp.Proto()}}
+	cfg := config.Default.Dashboard
+	cfg.NumBlocks = 5
+	d := NewDashboard(cfg, api)
+
+	rec := get(d, "/")
+	require.Equal(http.StatusOK, rec.Code)
+	body := rec.Body.String()
+	require.Contains(body, "hash30")
+	require.Contains(body, "hash26")
+	require.NotContains(body, "hash25")
+	require.Contains(body, identityset.Address(2).String())
+	require.Contains(body, "1 pending actions")
+
+	rec = get(d, "/mempool")
+	require.Equal(http.StatusOK, rec.Code)
+	require.Contains(rec.Body.String(), identityset.Address(3).String())
+
+	rec = get(d, "/address/"+identityset.Address(5).String())
+	require.Equal(http.StatusOK, rec.Code)
+	require.Contains(rec.Body.String(), "12345")
+	require.Equal(http.StatusBadRequest, get(d, "/address/abc").Code)
+
+	require.Equal(http.StatusOK, get(d, "/block/1").Code)
+	require.Equal(http.StatusNotFound, get(d, "/block/31").Code)
+	require.Equal(http.StatusNotFound, get(d, "/action/abc").Code)
+	require.Equal(http.StatusNotFound, get(d, "/unknown").Code)
+
+	for q, location := range map[string]string{
+		"":                              "/",
+		"12":                            "/block/12",
+		identityset.Address(5).String(): "/address/" + identityset.Address(5).String(),
+		"0xhash1":                       "/block/hash1",
+		"ffff":                          "/action/ffff",
+	} {
+		rec = get(d, "/search?q="+q)
+		require.Equal(http.StatusFound, rec.Code)
+		require.Equal(location, rec.Header().Get("Location"))
+	}
+}
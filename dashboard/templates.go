@@ -0,0 +1,114 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package dashboard
+
+// _pageTemplates are the html templates of the pages, kept in the binary so the dashboard needs no static files
+const _pageTemplates = `
+{{define "header"}}<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>IoTeX Dashboard</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; margin-bottom: 2em; }
+th, td { border: 1px solid #ccc; padding: 4px 8px; text-align: left; font-family: monospace; }
+nav a { margin-right: 1em; }
+</style>
+</head>
+<body>
+<nav>
+<a href="/">Overview</a>
+<a href="/mempool">Mempool</a>
+<form action="/search" method="get" style="display: inline">
+<input name="q" size="70" placeholder="block height or hash, action hash, address">
+<input type="submit" value="Search">
+</form>
+</nav>
+{{end}}
+
+{{define "footer"}}</body>
+</html>
+{{end}}
+
+{{define "actions"}}<table>
+<tr><th>Hash</th><th>Height</th><th>Sender</th><th>Recipient</th><th>Amount</th><th>Nonce</th><th>Gas Fee</th></tr>
+{{range .}}<tr>
+<td><a href="/action/{{.Hash}}">{{.Hash}}</a></td>
+<td>{{if .BlkHeight}}<a href="/block/{{.BlkHeight}}">{{.BlkHeight}}</a>{{else}}pending{{end}}</td>
+<td><a href="/address/{{.Sender}}">{{.Sender}}</a></td>
+<td>{{if .Recipient}}<a href="/address/{{.Recipient}}">{{.Recipient}}</a>{{end}}</td>
+<td>{{.Amount}}</td>
+<td>{{.Nonce}}</td>
+<td>{{.GasFee}}</td>
+</tr>{{end}}
+</table>
+{{end}}
+
+{{define "overview"}}{{template "header"}}
+<h2>Chain</h2>
+<p>Height {{.Height}}, epoch {{.Epoch}}, <a href="/mempool">{{.NumPending}} pending actions</a></p>
+<h2>Recent Blocks</h2>
+<table>
+<tr><th>Height</th><th>Hash</th><th>Producer</th><th>Actions</th><th>Time</th></tr>
+{{range .Blocks}}<tr>
+<td><a href="/block/{{.Height}}">{{.Height}}</a></td>
+<td><a href="/block/{{.Hash}}">{{.Hash}}</a></td>
+<td><a href="/address/{{.Producer}}">{{.Producer}}</a></td>
+<td>{{.NumActions}}</td>
+<td>{{.Timestamp}}</td>
+</tr>{{end}}
+</table>
+{{if .Delegates}}<h2>Delegates of Epoch {{.Epoch}}</h2>
+<table>
+<tr><th>Address</th><th>Votes</th><th>Active</th><th>Production</th></tr>
+{{range .Delegates}}<tr>
+<td><a href="/address/{{.Address}}">{{.Address}}</a></td>
+<td>{{.Votes}}</td>
+<td>{{.Active}}</td>
+<td>{{.Production}}</td>
+</tr>{{end}}
+</table>
+{{end}}{{template "footer"}}{{end}}
+
+{{define "block"}}{{template "header"}}
+<h2>Block {{.Block.Height}}</h2>
+<table>
+<tr><th>Hash</th><td>{{.Block.Hash}}</td></tr>
+<tr><th>Previous</th><td><a href="/block/{{.Block.PrevHash}}">{{.Block.PrevHash}}</a></td></tr>
+<tr><th>Producer</th><td><a href="/address/{{.Block.Producer}}">{{.Block.Producer}}</a></td></tr>
+<tr><th>Actions</th><td>{{.Block.NumActions}}</td></tr>
+<tr><th>Time</th><td>{{.Block.Timestamp}}</td></tr>
+</table>
+{{if .Actions}}<h2>Actions</h2>
+{{template "actions" .Actions}}{{end}}{{template "footer"}}{{end}}
+
+{{define "action"}}{{template "header"}}
+<h2>Action</h2>
+{{template "actions" .}}{{template "footer"}}{{end}}
+
+{{define "address"}}{{template "header"}}
+<h2>Address {{.Account.Address}}</h2>
+<table>
+<tr><th>Balance</th><td>{{.Account.Balance}}</td></tr>
+<tr><th>Nonce</th><td>{{.Account.Nonce}}</td></tr>
+<tr><th>Pending Nonce</th><td>{{.Account.PendingNonce}}</td></tr>
+<tr><th>Actions</th><td>{{.Account.NumActions}}</td></tr>
+<tr><th>Contract</th><td>{{.Account.IsContract}}</td></tr>
+</table>
+{{if .Actions}}<h2>Latest Actions</h2>
+{{template "actions" .Actions}}{{end}}{{template "footer"}}{{end}}
+
+{{define "mempool"}}{{template "header"}}
+<h2>Pending Actions</h2>
+{{template "actions" .Actions}}{{template "footer"}}{{end}}
+
+{{define "error"}}{{template "header"}}
+<h2>Error</h2>
+<p>{{.}}</p>
+{{template "footer"}}{{end}}
+`
@@ -9,6 +9,7 @@ package db
 import (
 	"bytes"
 	"context"
+	"io"
 
 	"github.com/pkg/errors"
 	bolt "go.etcd.io/bbolt"
@@ -56,6 +57,17 @@ func (b *BoltDB) Stop(_ context.Context) error {
 	return nil
 }
 
+// Backup writes a consistent copy of the db to w, concurrent writes are not blocked
+func (b *BoltDB) Backup(w io.Writer) error {
+	if err := b.db.View(func(tx *bolt.Tx) error {
+		_, err := tx.WriteTo(w)
+		return err
+	}); err != nil {
+		return errors.Wrap(ErrIO, err.Error())
+	}
+	return nil
+}
+
 // Put inserts a <key, value> record
 func (b *BoltDB) Put(namespace string, key, value []byte) (err error) {
 	for c := uint8(0); c < b.config.NumRetries; c++ {
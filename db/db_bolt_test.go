@@ -9,6 +9,7 @@ package db
 import (
 	"context"
 	"math/rand"
+	"os"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -36,6 +37,39 @@ func TestBucketExists(t *testing.T) {
 	r.True(kv.BucketExists("name"))
 }
 
+func TestBackup(t *testing.T) {
+	r := require.New(t)
+	testPath, err := testutil.PathOfTempFile("test-backup")
+	r.NoError(err)
+	backupPath, err := testutil.PathOfTempFile("test-backup-copy")
+	r.NoError(err)
+	defer func() {
+		testutil.CleanupPath(t, testPath)
+		testutil.CleanupPath(t, backupPath)
+	}()
+
+	cfg := config.Default.DB
+	cfg.DbPath = testPath
+	kv := NewBoltDB(cfg)
+	ctx := context.Background()
+	r.NoError(kv.Start(ctx))
+	defer kv.Stop(ctx)
+	r.NoError(kv.Put("ns", []byte("key"), []byte("value")))
+
+	f, err := os.Create(backupPath)
+	r.NoError(err)
+	r.NoError(kv.Backup(f))
+	r.NoError(f.Close())
+
+	cfg.DbPath = backupPath
+	backup := NewBoltDB(cfg)
+	r.NoError(backup.Start(ctx))
+	defer backup.Stop(ctx)
+	v, err := backup.Get("ns", []byte("key"))
+	r.NoError(err)
+	r.Equal([]byte("value"), v)
+}
+
 func BenchmarkBoltDB_Get(b *testing.B) {
 	runBenchmark := func(b *testing.B, size int) {
 		path, err := testutil.PathOfTempFile("boltdb")
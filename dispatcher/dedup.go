@@ -0,0 +1,71 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package dispatcher
+
+import (
+	"context"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	p2p "github.com/iotexproject/go-p2p"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/iotexproject/go-pkgs/cache"
+	"github.com/iotexproject/go-pkgs/hash"
+	"github.com/iotexproject/iotex-proto/golang/iotextypes"
+)
+
+const _unknownSource = "unknown"
+
+var duplicateActionMtc = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "iotex_dispatch_duplicate_action",
+		Help: "Number of duplicate broadcast actions dropped by the dispatcher per source peer",
+	},
+	[]string{"source"},
+)
+
+func init() {
+	prometheus.MustRegister(duplicateActionMtc)
+}
+
+// actionDedup is the cache of the hashes of the broadcast actions seen recently, which drops the duplicate actions
+// relayed by the peers before they are queued
+type actionDedup struct {
+	seen *cache.ThreadSafeLruCache
+	ttl  time.Duration
+}
+
+func newActionDedup(size int, ttl time.Duration) *actionDedup {
+	return &actionDedup{
+		seen: cache.NewThreadSafeLruCache(size),
+		ttl:  ttl,
+	}
+}
+
+// Duplicate returns true if the action has been seen within the ttl, otherwise records it as seen at now
+func (d *actionDedup) Duplicate(act *iotextypes.Action, now time.Time) bool {
+	b, err := proto.Marshal(act)
+	if err != nil {
+		return false
+	}
+	h := hash.Hash256b(b)
+	if v, ok := d.seen.Get(h); ok && now.Sub(v.(time.Time)) < d.ttl {
+		return true
+	}
+	d.seen.Add(h, now)
+	return false
+}
+
+// broadcastSource returns the peer which the broadcast message in the context is originated from
+func broadcastSource(ctx context.Context) string {
+	msg, ok := p2p.GetBroadcastMsg(ctx)
+	if !ok || msg == nil {
+		return _unknownSource
+	}
+	return msg.GetFrom().Pretty()
+}
@@ -11,6 +11,7 @@ import (
 	"fmt"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/golang/protobuf/proto"
 	peerstore "github.com/libp2p/go-libp2p-peerstore"
@@ -84,6 +85,16 @@ func (m blockSyncMsg) ChainID() uint32 {
 	return m.chainID
 }
 
+// consensusMsg packages a proto consensus message.
+type consensusMsg struct {
+	chainID uint32
+	msg     *iotextypes.ConsensusMessage
+}
+
+func (m consensusMsg) ChainID() uint32 {
+	return m.chainID
+}
+
 // actionMsg packages a proto action message.
 type actionMsg struct {
 	ctx     context.Context
@@ -95,11 +106,15 @@ func (m actionMsg) ChainID() uint32 {
 	return m.chainID
 }
 
-// IotxDispatcher is the request and event dispatcher for iotx node.
+// IotxDispatcher is the request and event dispatcher for iotx node. Consensus messages are handled by a dedicated
+// handler, and block messages take precedence over action messages in the news handler, so that a flood of actions
+// does not delay consensus and blocks.
 type IotxDispatcher struct {
 	started        int32
 	shutdown       int32
-	eventChan      chan interface{}
+	consensusQueue *msgQueue
+	blockQueue     *msgQueue
+	actionQueue    *msgQueue
 	syncChan       chan *blockSyncMsg
 	eventAudit     map[iotexrpc.MessageType]int
 	eventAuditLock sync.RWMutex
@@ -107,17 +122,37 @@ type IotxDispatcher struct {
 	quit           chan struct{}
 	subscribers    map[uint32]Subscriber
 	subscribersMU  sync.RWMutex
+	actionDedup    *actionDedup
 }
 
 // NewDispatcher creates a new Dispatcher
 func NewDispatcher(cfg config.Config) (Dispatcher, error) {
+	queueSize := func(size uint) uint {
+		if size == 0 {
+			return cfg.Dispatcher.EventChanSize
+		}
+		return size
+	}
 	d := &IotxDispatcher{
-		eventChan:   make(chan interface{}, cfg.Dispatcher.EventChanSize),
+		// stale consensus messages are useless, keep the latest ones
+		consensusQueue: newMsgQueue("consensus", queueSize(cfg.Dispatcher.ConsensusChanSize), dropOldest, 0),
+		// missing a block costs a block sync, so the sender is held back for a while before dropping
+		blockQueue: newMsgQueue(
+			"block",
+			queueSize(cfg.Dispatcher.BlockChanSize),
+			waitThenDrop,
+			cfg.Dispatcher.BlockEnqueueTimeout,
+		),
+		// actions are relayed again by peers or resubmitted by clients
+		actionQueue: newMsgQueue("action", queueSize(cfg.Dispatcher.ActionChanSize), dropNewest, 0),
 		syncChan:    make(chan *blockSyncMsg, cfg.Dispatcher.EventChanSize),
 		eventAudit:  make(map[iotexrpc.MessageType]int),
 		quit:        make(chan struct{}),
 		subscribers: make(map[uint32]Subscriber),
 	}
+	if cfg.Dispatcher.ActionDedupCacheSize > 0 {
+		d.actionDedup = newActionDedup(cfg.Dispatcher.ActionDedupCacheSize, cfg.Dispatcher.ActionDedupTTL)
+	}
 	return d, nil
 }
 
@@ -137,7 +172,8 @@ func (d *IotxDispatcher) Start(ctx context.Context) error {
 		return errors.New("Dispatcher already started")
 	}
 	log.L().Info("Starting dispatcher.")
-	d.wg.Add(2)
+	d.wg.Add(3)
+	go d.consensusHandler()
 	go d.newsHandler()
 	go d.syncHandler()
 
@@ -160,7 +196,7 @@ func (d *IotxDispatcher) Stop(ctx context.Context) error {
 func (d *IotxDispatcher) EventQueueSize() int {
 	d.eventAuditLock.RLock()
 	defer d.eventAuditLock.RUnlock()
-	return len(d.eventChan) + len(d.syncChan)
+	return d.consensusQueue.Len() + d.blockQueue.Len() + d.actionQueue.Len() + len(d.syncChan)
 }
 
 // EventAudit returns the event audit map
@@ -174,20 +210,43 @@ func (d *IotxDispatcher) EventAudit() map[iotexrpc.MessageType]int {
 	return snapshot
 }
 
-// newsHandler is the main handler for handling all news from peers.
+// consensusHandler handles the consensus messages from peers.
+func (d *IotxDispatcher) consensusHandler() {
+loop:
+	for {
+		select {
+		case m := <-d.consensusQueue.C():
+			d.consensusQueue.observe()
+			d.handleConsensusMsg(m.(*consensusMsg))
+		case <-d.quit:
+			break loop
+		}
+	}
+
+	d.wg.Done()
+	log.L().Info("consensus handler done.")
+}
+
+// newsHandler is the main handler for handling all news from peers, block messages are handled before actions.
 func (d *IotxDispatcher) newsHandler() {
 loop:
 	for {
 		select {
-		case m := <-d.eventChan:
-			switch msg := m.(type) {
-			case *actionMsg:
-				d.handleActionMsg(msg)
-			case *blockMsg:
-				d.handleBlockMsg(msg)
-			default:
-				log.L().Warn("Invalid message type in block handler.", zap.Any("msg", msg))
-			}
+		case m := <-d.blockQueue.C():
+			d.blockQueue.observe()
+			d.handleBlockMsg(m.(*blockMsg))
+			continue
+		case <-d.quit:
+			break loop
+		default:
+		}
+		select {
+		case m := <-d.blockQueue.C():
+			d.blockQueue.observe()
+			d.handleBlockMsg(m.(*blockMsg))
+		case m := <-d.actionQueue.C():
+			d.actionQueue.observe()
+			d.handleActionMsg(m.(*actionMsg))
 		case <-d.quit:
 			break loop
 		}
@@ -213,6 +272,21 @@ loop:
 	log.L().Info("block sync handler done.")
 }
 
+// handleConsensusMsg handles consensusMsg from peers.
+func (d *IotxDispatcher) handleConsensusMsg(m *consensusMsg) {
+	d.subscribersMU.RLock()
+	subscriber, ok := d.subscribers[m.ChainID()]
+	d.subscribersMU.RUnlock()
+	if !ok {
+		log.L().Info("No subscriber specified in the dispatcher.", zap.Uint32("chainID", m.ChainID()))
+		return
+	}
+	d.updateEventAudit(iotexrpc.MessageType_CONSENSUS)
+	if err := subscriber.HandleConsensusMsg(m.msg); err != nil {
+		log.L().Debug("Failed to handle consensus message.", zap.Error(err))
+	}
+}
+
 // handleActionMsg handles actionMsg from all peers.
 func (d *IotxDispatcher) handleActionMsg(m *actionMsg) {
 	log.L().Debug("receive actionMsg.")
@@ -269,15 +343,31 @@ func (d *IotxDispatcher) handleBlockSyncMsg(m *blockSyncMsg) {
 	}
 }
 
+// dispatchConsensus adds the passed consensus message to the consensus handling queue.
+func (d *IotxDispatcher) dispatchConsensus(chainID uint32, msg proto.Message) {
+	if atomic.LoadInt32(&d.shutdown) != 0 {
+		return
+	}
+	d.consensusQueue.Push(&consensusMsg{
+		chainID: chainID,
+		msg:     (msg).(*iotextypes.ConsensusMessage),
+	})
+}
+
 // dispatchAction adds the passed action message to the news handling queue.
 func (d *IotxDispatcher) dispatchAction(ctx context.Context, chainID uint32, msg proto.Message) {
 	if atomic.LoadInt32(&d.shutdown) != 0 {
 		return
 	}
-	d.enqueueEvent(&actionMsg{
+	act := (msg).(*iotextypes.Action)
+	if d.actionDedup != nil && d.actionDedup.Duplicate(act, time.Now()) {
+		duplicateActionMtc.WithLabelValues(broadcastSource(ctx)).Inc()
+		return
+	}
+	d.actionQueue.Push(&actionMsg{
 		ctx:     ctx,
 		chainID: chainID,
-		action:  (msg).(*iotextypes.Action),
+		action:  act,
 	})
 }
 
@@ -286,7 +376,7 @@ func (d *IotxDispatcher) dispatchBlockCommit(ctx context.Context, chainID uint32
 	if atomic.LoadInt32(&d.shutdown) != 0 {
 		return
 	}
-	d.enqueueEvent(&blockMsg{
+	d.blockQueue.Push(&blockMsg{
 		ctx:     ctx,
 		chainID: chainID,
 		block:   (msg).(*iotextypes.Block),
@@ -318,7 +408,7 @@ func (d *IotxDispatcher) HandleBroadcast(ctx context.Context, chainID uint32, me
 		log.L().Warn("Unexpected message handled by HandleBroadcast.", zap.Error(err))
 	}
 	d.subscribersMU.RLock()
-	subscriber, ok := d.subscribers[chainID]
+	_, ok := d.subscribers[chainID]
 	d.subscribersMU.RUnlock()
 	if !ok {
 		log.L().Warn("chainID has not been registered in dispatcher.", zap.Uint32("chainID", chainID))
@@ -327,9 +417,7 @@ func (d *IotxDispatcher) HandleBroadcast(ctx context.Context, chainID uint32, me
 
 	switch msgType {
 	case iotexrpc.MessageType_CONSENSUS:
-		if err := subscriber.HandleConsensusMsg(message.(*iotextypes.ConsensusMessage)); err != nil {
-			log.L().Debug("Failed to handle consensus message.", zap.Error(err))
-		}
+		d.dispatchConsensus(chainID, message)
 	case iotexrpc.MessageType_ACTION:
 		d.dispatchAction(ctx, chainID, message)
 	case iotexrpc.MessageType_BLOCK:
@@ -355,14 +443,6 @@ func (d *IotxDispatcher) HandleTell(ctx context.Context, chainID uint32, peer pe
 	}
 }
 
-func (d *IotxDispatcher) enqueueEvent(event interface{}) {
-	if len(d.eventChan) == cap(d.eventChan) {
-		log.L().Warn("dispatcher event chan is full, drop an event.")
-		return
-	}
-	d.eventChan <- event
-}
-
 func (d *IotxDispatcher) updateEventAudit(t iotexrpc.MessageType) {
 	d.eventAuditLock.Lock()
 	defer d.eventAuditLock.Unlock()
@@ -9,11 +9,13 @@ package dispatcher
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/golang/protobuf/proto"
 	peerstore "github.com/libp2p/go-libp2p-peerstore"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/iotexproject/iotex-core/config"
 	"github.com/iotexproject/iotex-proto/golang/iotexrpc"
@@ -86,6 +88,36 @@ func TestHandleTell(t *testing.T) {
 	}
 }
 
+func TestActionDedup(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Now()
+	d := newActionDedup(2, time.Minute)
+	act1 := &iotextypes.Action{Signature: []byte{1}}
+	act2 := &iotextypes.Action{Signature: []byte{2}}
+	require.False(d.Duplicate(act1, now))
+	require.True(d.Duplicate(act1, now.Add(time.Second)))
+	require.False(d.Duplicate(act2, now))
+	// seen again after ttl
+	require.False(d.Duplicate(act1, now.Add(time.Minute)))
+	require.True(d.Duplicate(act1, now.Add(time.Minute+time.Second)))
+
+	require.Equal(_unknownSource, broadcastSource(context.Background()))
+
+	dp, err := NewDispatcher(config.Config{
+		Dispatcher: config.Dispatcher{EventChanSize: 8, ActionDedupCacheSize: 8, ActionDedupTTL: time.Minute},
+	})
+	require.NoError(err)
+	for i := 0; i < 3; i++ {
+		dp.HandleBroadcast(context.Background(), 1, act1)
+	}
+	dp.AddSubscriber(1, &DummySubscriber{})
+	for i := 0; i < 3; i++ {
+		dp.HandleBroadcast(context.Background(), 1, act1)
+	}
+	require.Equal(1, dp.(*IotxDispatcher).EventQueueSize())
+}
+
 type DummySubscriber struct{}
 
 func (s *DummySubscriber) HandleBlock(context.Context, *iotextypes.Block) error { return nil }
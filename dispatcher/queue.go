@@ -0,0 +1,112 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package dispatcher
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// dropPolicy is the policy applied when a message is pushed into a full queue
+type dropPolicy int
+
+const (
+	// dropNewest drops the message being pushed
+	dropNewest dropPolicy = iota
+	// dropOldest drops the oldest message in the queue to make room for the message being pushed
+	dropOldest
+	// waitThenDrop blocks the sender until there is room or the timeout, then drops the message being pushed
+	waitThenDrop
+)
+
+var (
+	queueSaturationMtc = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "iotex_dispatch_queue_saturation",
+			Help: "Ratio of the length to the capacity of the dispatcher queue per message type",
+		},
+		[]string{"queue"},
+	)
+	queueDropMtc = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "iotex_dispatch_queue_drop",
+			Help: "Number of messages dropped by the dispatcher queue per message type",
+		},
+		[]string{"queue"},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(queueSaturationMtc)
+	prometheus.MustRegister(queueDropMtc)
+}
+
+// msgQueue is a bounded queue of a message type with a drop policy
+type msgQueue struct {
+	name    string
+	ch      chan interface{}
+	policy  dropPolicy
+	timeout time.Duration
+}
+
+func newMsgQueue(name string, size uint, policy dropPolicy, timeout time.Duration) *msgQueue {
+	return &msgQueue{
+		name:    name,
+		ch:      make(chan interface{}, size),
+		policy:  policy,
+		timeout: timeout,
+	}
+}
+
+// Push adds the message to the queue, and returns false if a message is dropped
+func (q *msgQueue) Push(m interface{}) bool {
+	defer q.observe()
+	select {
+	case q.ch <- m:
+		return true
+	default:
+	}
+	switch q.policy {
+	case dropOldest:
+		// the queue may be drained or filled concurrently, so the oldest is dropped at most once
+		select {
+		case <-q.ch:
+		default:
+		}
+		select {
+		case q.ch <- m:
+		default:
+		}
+	case waitThenDrop:
+		timer := time.NewTimer(q.timeout)
+		defer timer.Stop()
+		select {
+		case q.ch <- m:
+			return true
+		case <-timer.C:
+		}
+	}
+	queueDropMtc.WithLabelValues(q.name).Inc()
+	return false
+}
+
+// C returns the channel to receive the messages from
+func (q *msgQueue) C() <-chan interface{} {
+	return q.ch
+}
+
+// Len returns the number of messages in the queue
+func (q *msgQueue) Len() int {
+	return len(q.ch)
+}
+
+func (q *msgQueue) observe() {
+	if c := cap(q.ch); c > 0 {
+		queueSaturationMtc.WithLabelValues(q.name).Set(float64(len(q.ch)) / float64(c))
+	}
+}
@@ -0,0 +1,109 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package dispatcher
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-proto/golang/iotextypes"
+)
+
+func TestMsgQueue(t *testing.T) {
+	require := require.New(t)
+
+	drain := func(q *msgQueue) []interface{} {
+		var msgs []interface{}
+		for q.Len() > 0 {
+			msgs = append(msgs, <-q.C())
+		}
+		return msgs
+	}
+
+	q := newMsgQueue("test", 2, dropNewest, 0)
+	require.True(q.Push(1))
+	require.True(q.Push(2))
+	require.False(q.Push(3))
+	require.Equal([]interface{}{1, 2}, drain(q))
+
+	q = newMsgQueue("test", 2, dropOldest, 0)
+	require.True(q.Push(1))
+	require.True(q.Push(2))
+	require.False(q.Push(3))
+	require.Equal([]interface{}{2, 3}, drain(q))
+
+	q = newMsgQueue("test", 1, waitThenDrop, 10*time.Millisecond)
+	require.True(q.Push(1))
+	start := time.Now()
+	require.False(q.Push(2))
+	require.True(time.Since(start) >= 10*time.Millisecond)
+	go func() {
+		time.Sleep(5 * time.Millisecond)
+		<-q.C()
+	}()
+	q.timeout = time.Second
+	require.True(q.Push(3))
+	require.Equal([]interface{}{3}, drain(q))
+}
+
+type orderSubscriber struct {
+	DummySubscriber
+	mu    sync.Mutex
+	order []string
+}
+
+func (s *orderSubscriber) HandleBlock(context.Context, *iotextypes.Block) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.order = append(s.order, "block")
+	return nil
+}
+
+func (s *orderSubscriber) HandleAction(context.Context, *iotextypes.Action) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.order = append(s.order, "action")
+	return nil
+}
+
+func (s *orderSubscriber) handled() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string{}, s.order...)
+}
+
+func TestDispatcherPriority(t *testing.T) {
+	require := require.New(t)
+
+	dp, err := NewDispatcher(config.Config{
+		Dispatcher: config.Dispatcher{EventChanSize: 2},
+	})
+	require.NoError(err)
+	sub := &orderSubscriber{}
+	dp.AddSubscriber(1, sub)
+	ctx := context.Background()
+	for i := byte(0); i < 3; i++ {
+		dp.HandleBroadcast(ctx, 1, &iotextypes.Action{Signature: []byte{i}})
+	}
+	dp.HandleBroadcast(ctx, 1, &iotextypes.Block{})
+	// the action queue is full
+	require.Equal(3, dp.(*IotxDispatcher).EventQueueSize())
+
+	require.NoError(dp.Start(ctx))
+	defer func() {
+		require.NoError(dp.Stop(ctx))
+	}()
+	require.Eventually(func() bool {
+		return len(sub.handled()) == 3
+	}, time.Second, 10*time.Millisecond)
+	require.Equal([]string{"block", "action", "action"}, sub.handled())
+}
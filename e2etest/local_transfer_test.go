@@ -271,6 +271,8 @@ func TestLocalTransfer(t *testing.T) {
 	require.NoError(err)
 	testCandidateIndexPath, err := testutil.PathOfTempFile("candidateIndex")
 	require.NoError(err)
+	testBlockStatsIndexPath, err := testutil.PathOfTempFile("blockStatsIndex")
+	require.NoError(err)
 
 	defer func() {
 		testutil.CleanupPath(t, testTriePath)
@@ -279,6 +281,7 @@ func TestLocalTransfer(t *testing.T) {
 		testutil.CleanupPath(t, testSystemLogPath)
 		testutil.CleanupPath(t, testBloomfilterIndexPath)
 		testutil.CleanupPath(t, testCandidateIndexPath)
+		testutil.CleanupPath(t, testBlockStatsIndexPath)
 	}()
 
 	networkPort := 4689
@@ -288,6 +291,7 @@ func TestLocalTransfer(t *testing.T) {
 		delete(cfg.Plugins, config.GatewayPlugin)
 	}()
 	require.NoError(err)
+	cfg.Chain.BlockStatsIndexDBPath = testBlockStatsIndexPath
 
 	for i, tsfTest := range getSimpleTransferTests {
 		if tsfTest.senderAcntState == AcntCreate {
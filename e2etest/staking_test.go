@@ -182,6 +182,8 @@ func TestStakingContract(t *testing.T) {
 	require.NoError(err)
 	testCandidateIndexPath, err := testutil.PathOfTempFile("candidateindex")
 	require.NoError(err)
+	testBlockStatsIndexPath, err := testutil.PathOfTempFile("blockstatsindex")
+	require.NoError(err)
 	testSystemLogPath, err := testutil.PathOfTempFile("systemlog")
 	require.NoError(err)
 	testConsensusPath, err := testutil.PathOfTempFile("consensus")
@@ -192,6 +194,7 @@ func TestStakingContract(t *testing.T) {
 		testutil.CleanupPath(t, testIndexPath)
 		testutil.CleanupPath(t, testBloomfilterIndexPath)
 		testutil.CleanupPath(t, testCandidateIndexPath)
+		testutil.CleanupPath(t, testBlockStatsIndexPath)
 		testutil.CleanupPath(t, testSystemLogPath)
 		testutil.CleanupPath(t, testConsensusPath)
 		// clear the gateway
@@ -204,6 +207,7 @@ func TestStakingContract(t *testing.T) {
 	cfg.Chain.IndexDBPath = testIndexPath
 	cfg.Chain.BloomfilterIndexDBPath = testBloomfilterIndexPath
 	cfg.Chain.CandidateIndexDBPath = testCandidateIndexPath
+	cfg.Chain.BlockStatsIndexDBPath = testBlockStatsIndexPath
 	cfg.System.SystemLogDBPath = testSystemLogPath
 	cfg.Consensus.RollDPoS.ConsensusDBPath = testConsensusPath
 	cfg.Chain.ProducerPrivKey = "a000000000000000000000000000000000000000000000000000000000000000"
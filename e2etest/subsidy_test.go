@@ -0,0 +1,136 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package e2etest
+
+import (
+	"context"
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/iotexproject/go-pkgs/crypto"
+	"github.com/iotexproject/iotex-address/address"
+	"github.com/iotexproject/iotex-proto/golang/iotextypes"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/action"
+	"github.com/iotexproject/iotex-core/action/protocol"
+	"github.com/iotexproject/iotex-core/action/protocol/account"
+	accountutil "github.com/iotexproject/iotex-core/action/protocol/account/util"
+	"github.com/iotexproject/iotex-core/action/protocol/execution"
+	"github.com/iotexproject/iotex-core/action/protocol/rewarding"
+	"github.com/iotexproject/iotex-core/action/protocol/rolldpos"
+	"github.com/iotexproject/iotex-core/action/protocol/subsidy"
+	"github.com/iotexproject/iotex-core/actpool"
+	"github.com/iotexproject/iotex-core/blockchain"
+	"github.com/iotexproject/iotex-core/blockchain/block"
+	"github.com/iotexproject/iotex-core/blockchain/blockdao"
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/pkg/unit"
+	"github.com/iotexproject/iotex-core/state/factory"
+	"github.com/iotexproject/iotex-core/testutil"
+)
+
+func TestSubsidizedExecution(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+
+	cfg := config.Default
+	cfg.Chain.EnableAsyncIndexWrite = false
+	cfg.Genesis.EnableGravityChainVoting = false
+	cfg.Genesis.KamchatkaBlockHeight = 1
+	cfg.Genesis.InitBalanceMap[executor] = "1000000000000000000000000000"
+	registry := protocol.NewRegistry()
+	acc := account.NewProtocol(rewarding.DepositGas)
+	r.NoError(acc.Register(registry))
+	rp := rolldpos.NewProtocol(cfg.Genesis.NumCandidateDelegates, cfg.Genesis.NumDelegates, cfg.Genesis.NumSubEpochs)
+	r.NoError(rp.Register(registry))
+	sf, err := factory.NewFactory(cfg, factory.InMemTrieOption(), factory.RegistryOption(registry))
+	r.NoError(err)
+	sp := subsidy.NewProtocol(rewarding.DepositGas)
+	ap, err := actpool.NewActPool(sf, cfg.ActPool, actpool.GasSubsidyOption(
+		func(sr protocol.StateReader, selp action.SealedEnvelope) (*big.Int, error) {
+			return sp.PendingCredit(cfg.Genesis, sr, selp)
+		},
+	))
+	r.NoError(err)
+	dao := blockdao.NewBlockDAOInMemForTest([]blockdao.BlockIndexer{sf})
+	bc := blockchain.NewBlockchain(
+		cfg,
+		dao,
+		factory.NewMinter(sf, ap),
+		blockchain.BlockValidatorOption(block.NewValidator(
+			sf,
+			protocol.NewGenericValidator(sf, accountutil.AccountState),
+		)),
+	)
+	r.NotNil(bc)
+	reward := rewarding.NewProtocol(0, 0)
+	r.NoError(reward.Register(registry))
+	r.NoError(sp.Register(registry))
+	ep := execution.NewProtocol(dao.GetBlockHash, rewarding.DepositGas)
+	r.NoError(ep.Register(registry))
+	r.NoError(bc.Start(ctx))
+	defer func() {
+		r.NoError(bc.Stop(ctx))
+	}()
+
+	sponsorKey, err := crypto.HexStringToPrivateKey(executorPriKey)
+	r.NoError(err)
+	callerKey, err := crypto.GenerateKey()
+	r.NoError(err)
+	caller := callerKey.PublicKey().Address().String()
+	gasPrice := big.NewInt(unit.Qev)
+	commit := func(selp action.SealedEnvelope) *action.Receipt {
+		r.NoError(ap.Add(ctx, selp))
+		blk, err := bc.MintNewBlock(testutil.TimestampNow())
+		r.NoError(err)
+		r.Equal(2, len(blk.Receipts))
+		r.NoError(bc.CommitBlock(blk))
+		return blk.Receipts[0]
+	}
+
+	// the sponsor deploys the contract storing the call data at the key of the caller
+	data, err := hex.DecodeString("600680600b6000396000f3600035335500")
+	r.NoError(err)
+	selp, err := testutil.SignedExecution(action.EmptyAddress, sponsorKey, 1, big.NewInt(0), 1000000, gasPrice, data)
+	r.NoError(err)
+	receipt := commit(selp)
+	r.Equal(uint64(iotextypes.ReceiptStatus_Success), receipt.Status)
+	contract := receipt.ContractAddress
+
+	// the caller without balance is rejected before the contract is subsidized
+	selp, err = testutil.SignedExecution(contract, callerKey, 1, big.NewInt(0), 100000, gasPrice, []byte{1})
+	r.NoError(err)
+	r.Equal(action.ErrBalance, errors.Cause(ap.Add(ctx, selp)))
+
+	// the sponsor registers the subsidy of the contract
+	maxFee := new(big.Int).Mul(gasPrice, big.NewInt(100000))
+	op := subsidy.Operation{Op: subsidy.OpRegister, Amount: maxFee}
+	op.Contract, err = address.FromString(contract)
+	r.NoError(err)
+	selp, err = testutil.SignedExecution(
+		sp.Address().String(), sponsorKey, 2, new(big.Int).Mul(maxFee, big.NewInt(10)), 100000, gasPrice, op.Serialize(),
+	)
+	r.NoError(err)
+	r.Equal(uint64(iotextypes.ReceiptStatus_Success), commit(selp).Status)
+
+	// the caller without balance calls the contract at the expense of the subsidy
+	selp, err = testutil.SignedExecution(contract, callerKey, 1, big.NewInt(0), 100000, gasPrice, []byte{1})
+	r.NoError(err)
+	r.Equal(uint64(iotextypes.ReceiptStatus_Success), commit(selp).Status)
+	state, err := accountutil.AccountState(sf, caller)
+	r.NoError(err)
+	r.Equal(uint64(1), state.Nonce)
+	r.Equal(0, state.Balance.Sign())
+
+	// the per-user limit does not cover another call
+	selp, err = testutil.SignedExecution(contract, callerKey, 2, big.NewInt(0), 100000, gasPrice, []byte{2})
+	r.NoError(err)
+	r.Equal(action.ErrBalance, errors.Cause(ap.Add(ctx, selp)))
+}
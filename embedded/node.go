@@ -0,0 +1,361 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+// Package embedded runs a single-node chain in process for integration tests, e.g.
+//
+//	node, err := embedded.New()
+//	...
+//	if err := node.Start(ctx); err != nil { ... }
+//	defer node.Stop(ctx)
+//	_, err = node.Fund(ctx, addr, unit.ConvertIotxToRau(100))
+//	conn, err := grpc.Dial(node.APIEndpoint(), grpc.WithInsecure())
+//
+// Blocks are only produced when CommitBlock, CommitBlocks or AdvanceEpoch is called, so the tests are deterministic
+// and do not wait for the block interval.
+package embedded
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/iotexproject/go-pkgs/crypto"
+	"github.com/iotexproject/go-pkgs/hash"
+	"github.com/iotexproject/iotex-address/address"
+	"github.com/iotexproject/iotex-proto/golang/iotextypes"
+
+	"github.com/iotexproject/iotex-core/action"
+	"github.com/iotexproject/iotex-core/action/protocol"
+	accountutil "github.com/iotexproject/iotex-core/action/protocol/account/util"
+	"github.com/iotexproject/iotex-core/action/protocol/rolldpos"
+	"github.com/iotexproject/iotex-core/blockchain/block"
+	"github.com/iotexproject/iotex-core/chainservice"
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/pkg/unit"
+	"github.com/iotexproject/iotex-core/server/itx"
+	"github.com/iotexproject/iotex-core/testutil"
+)
+
+const _funderGasLimit = 10000
+
+// _funderBalance is the genesis balance of the funder, which is 10 billion IOTX
+var _funderBalance = unit.ConvertIotxToRau(10000000000)
+
+type (
+	// Option sets the config of the node
+	Option func(cfg *config.Config) error
+
+	// Node is a single-node chain running in process
+	Node struct {
+		cfg     config.Config
+		dir     string
+		keepDir bool
+		funder  crypto.PrivateKey
+		svr     *itx.Server
+		cs      *chainservice.ChainService
+
+		mutex   sync.Mutex
+		started bool
+	}
+)
+
+// WithConfig is an option to modify the config of the node, e.g. to set the fork heights of the genesis
+func WithConfig(f func(cfg *config.Config)) Option {
+	return func(cfg *config.Config) error {
+		f(cfg)
+		return nil
+	}
+}
+
+// WithGenesisBalance is an option to set the genesis balance of an address
+func WithGenesisBalance(addr string, balance *big.Int) Option {
+	return func(cfg *config.Config) error {
+		if _, err := address.FromString(addr); err != nil {
+			return errors.Wrapf(err, "invalid genesis address %s", addr)
+		}
+		cfg.Genesis.InitBalanceMap[addr] = balance.String()
+		return nil
+	}
+}
+
+// WithDataDir is an option to store the data of the node in the dir, which is kept after the node stops for
+// inspection. The keys of the producer and funder are generated for each node, so the data is not to be reused by
+// another node
+func WithDataDir(dir string) Option {
+	return func(cfg *config.Config) error {
+		setDataDir(cfg, dir)
+		return nil
+	}
+}
+
+// New creates a node, by default its data is stored in a temporary dir removed when the node stops, and its api
+// listens on a random port
+func New(opts ...Option) (*Node, error) {
+	dir, err := ioutil.TempDir("", "iotex-embedded")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create data dir")
+	}
+	n, err := newNode(dir, opts...)
+	if err != nil || n.keepDir {
+		// the temporary dir is not used if the data dir is set
+		os.RemoveAll(dir)
+	}
+	return n, err
+}
+
+func newNode(dir string, opts ...Option) (*Node, error) {
+	producer, err := crypto.GenerateKey()
+	if err != nil {
+		return nil, err
+	}
+	funder, err := crypto.GenerateKey()
+	if err != nil {
+		return nil, err
+	}
+	funderAddr, err := address.FromBytes(funder.PublicKey().Hash())
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := config.Default
+	cfg.Consensus.Scheme = config.NOOPScheme
+	cfg.Chain.ProducerPrivKey = producer.HexString()
+	cfg.Chain.EnableAsyncIndexWrite = false
+	cfg.ActPool.MinGasPriceStr = "0"
+	cfg.Network.Host = "127.0.0.1"
+	cfg.Network.Port = testutil.RandomPort()
+	cfg.Network.BootstrapNodes = nil
+	cfg.API.Port = testutil.RandomPort()
+	cfg.Genesis.EnableGravityChainVoting = false
+	// the gateway plugin indexes the actions to serve them by hash
+	cfg.Plugins = map[int]interface{}{config.GatewayPlugin: nil}
+	// the default balances are copied, not to modify the map shared with config.Default
+	balances := make(map[string]string, len(cfg.Genesis.InitBalanceMap)+1)
+	for addr, balance := range cfg.Genesis.InitBalanceMap {
+		balances[addr] = balance
+	}
+	balances[funderAddr.String()] = _funderBalance.String()
+	cfg.Genesis.InitBalanceMap = balances
+	setDataDir(&cfg, dir)
+
+	n := &Node{dir: dir, funder: funder}
+	for _, opt := range opts {
+		if err := opt(&cfg); err != nil {
+			return nil, err
+		}
+	}
+	n.keepDir = cfg.Chain.ChainDBPath != filepath.Join(dir, "chain.db")
+	n.cfg = cfg
+	return n, nil
+}
+
+func setDataDir(cfg *config.Config, dir string) {
+	cfg.Chain.ChainDBPath = filepath.Join(dir, "chain.db")
+	cfg.Chain.TrieDBPath = filepath.Join(dir, "trie.db")
+	cfg.Chain.IndexDBPath = filepath.Join(dir, "index.db")
+	cfg.Chain.BloomfilterIndexDBPath = filepath.Join(dir, "bloomfilter.index.db")
+	cfg.Chain.CandidateIndexDBPath = filepath.Join(dir, "candidate.index.db")
+	cfg.Chain.StakingIndexDBPath = filepath.Join(dir, "staking.index.db")
+	cfg.Chain.BlockStatsIndexDBPath = filepath.Join(dir, "blockstats.index.db")
+	cfg.Chain.CandidateHistoryIndexDBPath = filepath.Join(dir, "candidatehistory.index.db")
+	cfg.Chain.GravityChainDB.DbPath = filepath.Join(dir, "poll.db")
+	cfg.Consensus.RollDPoS.ConsensusDBPath = filepath.Join(dir, "consensus.db")
+	cfg.System.SystemLogDBPath = filepath.Join(dir, "systemlog.db")
+}
+
+// Start starts the node
+func (n *Node) Start(ctx context.Context) error {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	if n.started {
+		return errors.New("node has already started")
+	}
+	svr, err := itx.NewServer(n.cfg)
+	if err != nil {
+		return errors.Wrap(err, "failed to create node")
+	}
+	if err := svr.Start(ctx); err != nil {
+		return errors.Wrap(err, "failed to start node")
+	}
+	n.svr = svr
+	n.cs = svr.ChainService(n.cfg.Chain.ID)
+	n.started = true
+	return nil
+}
+
+// Stop stops the node, and removes its data unless stored in the dir of WithDataDir
+func (n *Node) Stop(ctx context.Context) error {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	if !n.started {
+		return nil
+	}
+	n.started = false
+	if err := n.svr.Stop(ctx); err != nil {
+		return err
+	}
+	if !n.keepDir {
+		return os.RemoveAll(n.dir)
+	}
+	return nil
+}
+
+// Config returns the config of the node
+func (n *Node) Config() config.Config { return n.cfg }
+
+// ChainService returns the chain service, to access the blockchain, state factory, actpool and api of the node
+func (n *Node) ChainService() *chainservice.ChainService { return n.cs }
+
+// APIEndpoint returns the endpoint of the grpc api
+func (n *Node) APIEndpoint() string { return fmt.Sprintf("127.0.0.1:%d", n.cfg.API.Port) }
+
+// Funder returns the private key of the account funded in genesis, which is used by Fund
+func (n *Node) Funder() crypto.PrivateKey { return n.funder }
+
+// TipHeight returns the height of the tip block
+func (n *Node) TipHeight() uint64 { return n.cs.Blockchain().TipHeight() }
+
+// SendAction adds the action to the actpool, it is included in the block committed next
+func (n *Node) SendAction(ctx context.Context, selp action.SealedEnvelope) error {
+	return n.cs.ActionPool().Add(protocol.WithRegistry(ctx, n.cs.Registry()), selp)
+}
+
+// CommitBlock mints a block with the actions in the actpool and commits it
+func (n *Node) CommitBlock() (*block.Block, error) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	if !n.started {
+		return nil, errors.New("node is not started")
+	}
+	return n.commitBlock()
+}
+
+// CommitBlocks commits n blocks, and returns the last one
+func (n *Node) CommitBlocks(num int) (*block.Block, error) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	if !n.started {
+		return nil, errors.New("node is not started")
+	}
+	var (
+		blk *block.Block
+		err error
+	)
+	for i := 0; i < num; i++ {
+		if blk, err = n.commitBlock(); err != nil {
+			return nil, err
+		}
+	}
+	return blk, nil
+}
+
+// AdvanceEpoch commits the blocks until the next epoch starts, and returns the number of the new epoch
+func (n *Node) AdvanceEpoch() (uint64, error) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	if !n.started {
+		return 0, errors.New("node is not started")
+	}
+	rp := rolldpos.FindProtocol(n.cs.Registry())
+	if rp == nil {
+		// the protocol is registered with the rolldpos scheme only, the epochs follow the genesis anyway
+		g := n.cfg.Genesis
+		rp = rolldpos.NewProtocol(
+			g.NumCandidateDelegates,
+			g.NumDelegates,
+			g.NumSubEpochs,
+			rolldpos.EnableDardanellesSubEpoch(g.DardanellesBlockHeight, g.DardanellesNumSubEpochs),
+		)
+	}
+	bc := n.cs.Blockchain()
+	epochNum := rp.GetEpochNum(bc.TipHeight()) + 1
+	for bc.TipHeight() < rp.GetEpochHeight(epochNum) {
+		if _, err := n.commitBlock(); err != nil {
+			return 0, err
+		}
+	}
+	return epochNum, nil
+}
+
+// Fund transfers the amount from the funder to the address in a new block, and returns the hash of the transfer
+func (n *Node) Fund(ctx context.Context, addr string, amount *big.Int) (hash.Hash256, error) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	if !n.started {
+		return hash.ZeroHash256, errors.New("node is not started")
+	}
+	funderAddr, err := address.FromBytes(n.funder.PublicKey().Hash())
+	if err != nil {
+		return hash.ZeroHash256, err
+	}
+	nonce, err := n.cs.ActionPool().GetPendingNonce(funderAddr.String())
+	if err != nil {
+		return hash.ZeroHash256, errors.Wrap(err, "failed to get the nonce of funder")
+	}
+	tsf, err := action.NewTransfer(nonce, amount, addr, nil, _funderGasLimit, big.NewInt(0))
+	if err != nil {
+		return hash.ZeroHash256, err
+	}
+	elp := (&action.EnvelopeBuilder{}).SetNonce(nonce).
+		SetGasLimit(_funderGasLimit).
+		SetGasPrice(big.NewInt(0)).
+		SetAction(tsf).Build()
+	selp, err := action.Sign(elp, n.funder)
+	if err != nil {
+		return hash.ZeroHash256, err
+	}
+	if err := n.cs.ActionPool().Add(protocol.WithRegistry(ctx, n.cs.Registry()), selp); err != nil {
+		return hash.ZeroHash256, errors.Wrap(err, "failed to add transfer to actpool")
+	}
+	blk, err := n.commitBlock()
+	if err != nil {
+		return hash.ZeroHash256, err
+	}
+	h := selp.Hash()
+	for _, r := range blk.Receipts {
+		if r.ActionHash != h {
+			continue
+		}
+		if r.Status != uint64(iotextypes.ReceiptStatus_Success) {
+			return h, errors.Errorf("transfer %x failed with status %d", h, r.Status)
+		}
+		return h, nil
+	}
+	return h, errors.Errorf("transfer %x is not included in block %d", h, blk.Height())
+}
+
+// Balance returns the balance of the address
+func (n *Node) Balance(addr string) (*big.Int, error) {
+	acct, err := accountutil.AccountState(n.cs.StateFactory(), addr)
+	if err != nil {
+		return nil, err
+	}
+	return acct.Balance, nil
+}
+
+func (n *Node) commitBlock() (*block.Block, error) {
+	bc := n.cs.Blockchain()
+	// the block is minted at least 1 second after the tip, so that each block has a distinct timestamp
+	ts := time.Now()
+	if tip, err := bc.BlockHeaderByHeight(bc.TipHeight()); err == nil && !ts.After(tip.Timestamp()) {
+		ts = tip.Timestamp().Add(time.Second)
+	}
+	blk, err := bc.MintNewBlock(ts)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to mint block")
+	}
+	if err := bc.CommitBlock(blk); err != nil {
+		return nil, errors.Wrap(err, "failed to commit block")
+	}
+	return blk, nil
+}
@@ -0,0 +1,98 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package embedded
+
+import (
+	"context"
+	"encoding/hex"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	"github.com/iotexproject/iotex-proto/golang/iotexapi"
+
+	"github.com/iotexproject/iotex-core/action"
+	"github.com/iotexproject/iotex-core/action/protocol/rolldpos"
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/pkg/unit"
+	"github.com/iotexproject/iotex-core/test/identityset"
+)
+
+func TestNode(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	alice := identityset.Address(28).String()
+	bob := identityset.Address(29).String()
+	node, err := New(
+		WithGenesisBalance(bob, unit.ConvertIotxToRau(100)),
+		WithConfig(func(cfg *config.Config) { cfg.API.RangeQueryLimit = 100 }),
+	)
+	require.NoError(err)
+	require.Equal(uint64(100), node.Config().API.RangeQueryLimit)
+	_, ok := config.Default.Genesis.InitBalanceMap[bob]
+	require.False(ok)
+	_, err = node.CommitBlock()
+	require.Error(err)
+
+	require.NoError(node.Start(ctx))
+	defer func() {
+		require.NoError(node.Stop(ctx))
+		_, err := os.Stat(node.dir)
+		require.True(os.IsNotExist(err))
+	}()
+	require.Error(node.Start(ctx))
+	require.Equal(uint64(0), node.TipHeight())
+
+	// fund
+	h, err := node.Fund(ctx, alice, unit.ConvertIotxToRau(10))
+	require.NoError(err)
+	require.Equal(uint64(1), node.TipHeight())
+	balance, err := node.Balance(alice)
+	require.NoError(err)
+	require.Equal(unit.ConvertIotxToRau(10), balance)
+	balance, err = node.Balance(bob)
+	require.NoError(err)
+	require.Equal(unit.ConvertIotxToRau(100), balance)
+
+	// send an action and commit
+	tsf, err := action.NewTransfer(1, big.NewInt(1), alice, nil, 10000, big.NewInt(0))
+	require.NoError(err)
+	elp := (&action.EnvelopeBuilder{}).SetNonce(1).SetGasLimit(10000).SetGasPrice(big.NewInt(0)).SetAction(tsf).Build()
+	selp, err := action.Sign(elp, identityset.PrivateKey(29))
+	require.NoError(err)
+	require.NoError(node.SendAction(ctx, selp))
+	blk, err := node.CommitBlock()
+	require.NoError(err)
+	// the transfer and the grant reward
+	require.Len(blk.Actions, 2)
+	require.Equal(selp.Hash(), blk.Receipts[0].ActionHash)
+	blk2, err := node.CommitBlocks(3)
+	require.NoError(err)
+	require.Equal(uint64(5), blk2.Height())
+	require.True(blk2.Timestamp().After(blk.Timestamp()))
+
+	// advance epoch
+	g := config.Default.Genesis
+	rp := rolldpos.NewProtocol(g.NumCandidateDelegates, g.NumDelegates, g.NumSubEpochs)
+	epochNum, err := node.AdvanceEpoch()
+	require.NoError(err)
+	require.Equal(rp.GetEpochNum(5)+1, epochNum)
+	require.Equal(rp.GetEpochHeight(epochNum), node.TipHeight())
+
+	// grpc api
+	conn, err := grpc.Dial(node.APIEndpoint(), grpc.WithInsecure())
+	require.NoError(err)
+	defer conn.Close()
+	cli := iotexapi.NewAPIServiceClient(conn)
+	res, err := cli.GetReceiptByAction(ctx, &iotexapi.GetReceiptByActionRequest{ActionHash: hex.EncodeToString(h[:])})
+	require.NoError(err)
+	require.Equal(uint64(1), res.ReceiptInfo.Receipt.BlkHeight)
+}
@@ -0,0 +1,120 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+// Package epochbus broadcasts the epoch changes and the candidate shifts of the committed blocks to the modules
+// caching the delegates of an epoch, so that the caches are invalidated consistently instead of each module
+// recomputing on its own. The events are delivered after the block is committed, so the bus only serves the caches
+// outside of the state transition, e.g. the api and the proposer schedule of consensus, never the protocols.
+package epochbus
+
+import (
+	"sync"
+
+	"github.com/iotexproject/iotex-core/action"
+	"github.com/iotexproject/iotex-core/action/protocol"
+	"github.com/iotexproject/iotex-core/action/protocol/rolldpos"
+	"github.com/iotexproject/iotex-core/blockchain/block"
+)
+
+const (
+	// EpochChanged is published on the first block of each epoch
+	EpochChanged EventType = iota
+	// CandidatesShifted is published when the delegates of the current or the next epoch may have changed, i.e., on
+	// the first block of each epoch where the next candidates are shifted to the current ones, and on the blocks
+	// putting the poll result of the next epoch
+	CandidatesShifted
+)
+
+type (
+	// EventType is the type of an event
+	EventType int
+
+	// Event is an event of a committed block
+	Event struct {
+		Type     EventType
+		EpochNum uint64
+		Height   uint64
+	}
+
+	// Handler handles the events
+	Handler func(Event)
+
+	// Bus publishes the events of the committed blocks to the subscribed handlers
+	Bus struct {
+		registry *protocol.Registry
+		mutex    sync.RWMutex
+		nextID   uint64
+		handlers map[uint64]Handler
+	}
+)
+
+func (t EventType) String() string {
+	switch t {
+	case EpochChanged:
+		return "epochChanged"
+	case CandidatesShifted:
+		return "candidatesShifted"
+	default:
+		return "unknown"
+	}
+}
+
+// NewBus creates a bus of the chain with the protocols in the registry
+func NewBus(registry *protocol.Registry) *Bus {
+	return &Bus{
+		registry: registry,
+		handlers: make(map[uint64]Handler),
+	}
+}
+
+// Subscribe adds the handler to the bus, and returns the function to unsubscribe it
+func (b *Bus) Subscribe(h Handler) func() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	id := b.nextID
+	b.nextID++
+	b.handlers[id] = h
+	return func() {
+		b.mutex.Lock()
+		defer b.mutex.Unlock()
+		delete(b.handlers, id)
+	}
+}
+
+// Publish calls the subscribed handlers with the event
+func (b *Bus) Publish(evt Event) {
+	b.mutex.RLock()
+	handlers := make([]Handler, 0, len(b.handlers))
+	for _, h := range b.handlers {
+		handlers = append(handlers, h)
+	}
+	b.mutex.RUnlock()
+	for _, h := range handlers {
+		h(evt)
+	}
+}
+
+// ReceiveBlock publishes the events of the committed block
+func (b *Bus) ReceiveBlock(blk *block.Block) error {
+	rp := rolldpos.FindProtocol(b.registry)
+	if rp == nil {
+		return nil
+	}
+	height := blk.Height()
+	epochNum := rp.GetEpochNum(height)
+	if height == rp.GetEpochHeight(epochNum) {
+		b.Publish(Event{Type: EpochChanged, EpochNum: epochNum, Height: height})
+		b.Publish(Event{Type: CandidatesShifted, EpochNum: epochNum, Height: height})
+		return nil
+	}
+	for _, selp := range blk.Actions {
+		if _, ok := selp.Action().(*action.PutPollResult); ok {
+			b.Publish(Event{Type: CandidatesShifted, EpochNum: epochNum, Height: height})
+			break
+		}
+	}
+	return nil
+}
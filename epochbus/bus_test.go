@@ -0,0 +1,121 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package epochbus
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/action"
+	"github.com/iotexproject/iotex-core/action/protocol"
+	"github.com/iotexproject/iotex-core/action/protocol/rolldpos"
+	"github.com/iotexproject/iotex-core/blockchain/block"
+	"github.com/iotexproject/iotex-core/test/identityset"
+)
+
+func TestBus(t *testing.T) {
+	require := require.New(t)
+
+	registry := protocol.NewRegistry()
+	require.NoError(rolldpos.NewProtocol(4, 4, 2).Register(registry))
+	bus := NewBus(registry)
+	var events []Event
+	unsubscribe := bus.Subscribe(func(evt Event) {
+		events = append(events, evt)
+	})
+
+	newBlock := func(height uint64, acts ...action.Action) *block.Block {
+		var selps []action.SealedEnvelope
+		for _, act := range acts {
+			elp := (&action.EnvelopeBuilder{}).SetNonce(0).SetGasPrice(big.NewInt(0)).SetAction(act).Build()
+			selp, err := action.Sign(elp, identityset.PrivateKey(0))
+			require.NoError(err)
+			selps = append(selps, selp)
+		}
+		blk, err := block.NewTestingBuilder().
+			SetHeight(height).
+			SetTimeStamp(time.Now()).
+			AddActions(selps...).
+			SignAndBuild(identityset.PrivateKey(0))
+		require.NoError(err)
+		return &blk
+	}
+	require.NoError(bus.ReceiveBlock(newBlock(1)))
+	require.NoError(bus.ReceiveBlock(newBlock(2)))
+	require.NoError(bus.ReceiveBlock(newBlock(6, action.NewPutPollResult(0, 6, nil))))
+	require.NoError(bus.ReceiveBlock(newBlock(9)))
+	require.Equal([]Event{
+		{Type: EpochChanged, EpochNum: 1, Height: 1},
+		{Type: CandidatesShifted, EpochNum: 1, Height: 1},
+		{Type: CandidatesShifted, EpochNum: 1, Height: 6},
+		{Type: EpochChanged, EpochNum: 2, Height: 9},
+		{Type: CandidatesShifted, EpochNum: 2, Height: 9},
+	}, events)
+
+	unsubscribe()
+	require.NoError(bus.ReceiveBlock(newBlock(17)))
+	require.Equal(5, len(events))
+}
+
+func TestCache(t *testing.T) {
+	require := require.New(t)
+
+	bus := NewBus(protocol.NewRegistry())
+	cache := NewCache(bus)
+	calls := 0
+	compute := func() (interface{}, error) {
+		calls++
+		return calls, nil
+	}
+	v, err := cache.Get(uint64(1), compute)
+	require.NoError(err)
+	require.Equal(1, v)
+	v, err = cache.Get(uint64(1), compute)
+	require.NoError(err)
+	require.Equal(1, v)
+
+	// errors are not cached
+	_, err = cache.Get(uint64(2), func() (interface{}, error) { return nil, errors.New("not ready") })
+	require.Error(err)
+	v, err = cache.Get(uint64(2), compute)
+	require.NoError(err)
+	require.Equal(2, v)
+
+	bus.Publish(Event{Type: EpochChanged})
+	v, err = cache.Get(uint64(1), compute)
+	require.NoError(err)
+	require.Equal(1, v)
+	bus.Publish(Event{Type: CandidatesShifted})
+	v, err = cache.Get(uint64(1), compute)
+	require.NoError(err)
+	require.Equal(3, v)
+
+	// the value computed across an invalidation is returned but not cached
+	v, err = cache.Get(uint64(3), func() (interface{}, error) {
+		bus.Publish(Event{Type: CandidatesShifted})
+		return compute()
+	})
+	require.NoError(err)
+	require.Equal(4, v)
+	v, err = cache.Get(uint64(3), compute)
+	require.NoError(err)
+	require.Equal(5, v)
+
+	// nothing is cached without the bus or after closed
+	for _, c := range []*Cache{NewCache(nil), cache} {
+		c.Close()
+		v1, err := c.Get(uint64(1), compute)
+		require.NoError(err)
+		v2, err := c.Get(uint64(1), compute)
+		require.NoError(err)
+		require.NotEqual(v1, v2)
+	}
+}
@@ -0,0 +1,77 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package epochbus
+
+import (
+	"sync"
+)
+
+// Cache caches the values computed from the delegates of epochs, and clears them when the candidates are shifted
+type Cache struct {
+	mutex sync.Mutex
+	// generation is increased on each invalidation, a value computed before the latest invalidation is not cached
+	generation  uint64
+	values      map[interface{}]interface{}
+	unsubscribe func()
+}
+
+// NewCache creates a cache invalidated by the events of the bus. A nil bus is allowed, where nothing is cached
+func NewCache(bus *Bus) *Cache {
+	c := &Cache{values: make(map[interface{}]interface{})}
+	if bus == nil {
+		return c
+	}
+	c.unsubscribe = bus.Subscribe(func(evt Event) {
+		if evt.Type == CandidatesShifted {
+			c.Invalidate()
+		}
+	})
+	return c
+}
+
+// Get returns the cached value of the key, or computes and caches it
+func (c *Cache) Get(key interface{}, compute func() (interface{}, error)) (interface{}, error) {
+	c.mutex.Lock()
+	if v, ok := c.values[key]; ok {
+		c.mutex.Unlock()
+		return v, nil
+	}
+	generation := c.generation
+	c.mutex.Unlock()
+
+	v, err := compute()
+	if err != nil {
+		return nil, err
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.unsubscribe != nil && c.generation == generation {
+		c.values[key] = v
+	}
+	return v, nil
+}
+
+// Invalidate clears the cached values
+func (c *Cache) Invalidate() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.generation++
+	c.values = make(map[interface{}]interface{})
+}
+
+// Close unsubscribes the cache from the bus, and stops caching since the values could no longer be invalidated
+func (c *Cache) Close() {
+	c.mutex.Lock()
+	unsubscribe := c.unsubscribe
+	c.unsubscribe = nil
+	c.generation++
+	c.values = make(map[interface{}]interface{})
+	c.mutex.Unlock()
+	if unsubscribe != nil {
+		unsubscribe()
+	}
+}
@@ -0,0 +1,179 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package epochevent
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+
+	"github.com/iotexproject/iotex-core/action"
+	"github.com/iotexproject/iotex-core/action/protocol"
+	"github.com/iotexproject/iotex-core/action/protocol/poll"
+	"github.com/iotexproject/iotex-core/action/protocol/protocolctx"
+	"github.com/iotexproject/iotex-core/action/protocol/rolldpos"
+	"github.com/iotexproject/iotex-core/action/protocol/vote"
+	"github.com/iotexproject/iotex-core/blockchain/block"
+	"github.com/iotexproject/iotex-core/blockchain/genesis"
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/pkg/log"
+	"github.com/iotexproject/iotex-core/state"
+)
+
+var epochEventMtc = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "iotex_epoch_event",
+		Help: "Number of epoch events published to sinks",
+	},
+	[]string{"sink", "result"},
+)
+
+func init() {
+	prometheus.MustRegister(epochEventMtc)
+}
+
+type (
+	// ReceiptReader reads the receipts of a block
+	ReceiptReader interface {
+		GetReceipts(uint64) ([]*action.Receipt, error)
+	}
+
+	// Bus publishes an event to the sinks on the first block of each epoch
+	Bus struct {
+		cfg      config.EpochEvent
+		genesis  genesis.Genesis
+		sr       protocol.StateReader
+		dao      ReceiptReader
+		registry *protocol.Registry
+		sinks    []Sink
+	}
+)
+
+// NewBus creates an epoch event bus publishing to the webhooks in config and the additional sinks
+func NewBus(
+	cfg config.EpochEvent,
+	g genesis.Genesis,
+	sr protocol.StateReader,
+	dao ReceiptReader,
+	registry *protocol.Registry,
+	sinks ...Sink,
+) *Bus {
+	for _, url := range cfg.WebhookURLs {
+		sinks = append(sinks, NewWebhookSink(url, cfg.Timeout))
+	}
+	return &Bus{
+		cfg:      cfg,
+		genesis:  g,
+		sr:       sr,
+		dao:      dao,
+		registry: registry,
+		sinks:    sinks,
+	}
+}
+
+// ReceiveBlock publishes the epoch event if the block is the first block of an epoch
+func (b *Bus) ReceiveBlock(blk *block.Block) error {
+	rp := rolldpos.FindProtocol(b.registry)
+	if rp == nil {
+		return nil
+	}
+	height := blk.Height()
+	epochNum := rp.GetEpochNum(height)
+	if height != rp.GetEpochHeight(epochNum) {
+		return nil
+	}
+	evt, err := b.newEvent(rp, epochNum, blk)
+	if err != nil {
+		log.L().Error("Failed to create epoch event.", zap.Uint64("epoch", epochNum), zap.Error(err))
+		return nil
+	}
+	b.Publish(context.Background(), evt)
+	return nil
+}
+
+// Publish publishes the event to all sinks, retrying each sink up to the configured times
+func (b *Bus) Publish(ctx context.Context, evt *Event) {
+	for _, sink := range b.sinks {
+		var err error
+		for i := 0; i <= b.cfg.MaxRetries; i++ {
+			if i > 0 {
+				time.Sleep(time.Duration(i) * time.Second)
+			}
+			if err = sink.Publish(ctx, evt); err == nil {
+				break
+			}
+		}
+		if err != nil {
+			epochEventMtc.WithLabelValues(sink.Name(), "failure").Inc()
+			log.L().Error(
+				"Failed to publish epoch event.",
+				zap.String("sink", sink.Name()),
+				zap.Uint64("epoch", evt.EpochNumber),
+				zap.Error(err),
+			)
+			continue
+		}
+		epochEventMtc.WithLabelValues(sink.Name(), "success").Inc()
+	}
+}
+
+func (b *Bus) newEvent(rp *rolldpos.Protocol, epochNum uint64, blk *block.Block) (*Event, error) {
+	evt := &Event{
+		Type:        EpochStartedEventType,
+		EpochNumber: epochNum,
+		Height:      blk.Height(),
+		Timestamp:   blk.Timestamp(),
+	}
+	if pp := poll.FindProtocol(b.registry); pp != nil {
+		ctx, err := protocolctx.NewBuilder().
+			SetRegistry(b.registry).
+			SetGenesis(b.genesis).
+			SetBlockHeight(blk.Height()).
+			Build(context.Background())
+		if err != nil {
+			return nil, err
+		}
+		epochArg := []byte(strconv.FormatUint(epochNum, 10))
+		data, _, err := pp.ReadState(ctx, b.sr, []byte("CandidatesByEpoch"), epochArg)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read candidates")
+		}
+		var candidates state.CandidateList
+		if err := candidates.Deserialize(data); err != nil {
+			return nil, err
+		}
+		evt.Candidates = NewCandidates(candidates)
+
+		data, _, err = pp.ReadState(ctx, b.sr, []byte("ProbationListByEpoch"), epochArg)
+		switch errors.Cause(err) {
+		case nil:
+			probationList := &vote.ProbationList{}
+			if err := probationList.Deserialize(data); err != nil {
+				return nil, err
+			}
+			evt.ProbationList = NewProbationList(probationList)
+		case protocol.ErrPreActivation:
+		default:
+			return nil, errors.Wrap(err, "failed to read probation list")
+		}
+	}
+	if epochNum > 1 && b.dao != nil {
+		// epoch reward of previous epoch is granted in its last block
+		receipts, err := b.dao.GetReceipts(blk.Height() - 1)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read receipts")
+		}
+		if evt.Rewards, err = NewRewardSummary(epochNum-1, receipts); err != nil {
+			return nil, err
+		}
+	}
+	return evt, nil
+}
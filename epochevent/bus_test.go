@@ -0,0 +1,111 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package epochevent
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/iotexproject/iotex-address/address"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/action"
+	"github.com/iotexproject/iotex-core/action/protocol/rewarding/rewardingpb"
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/test/identityset"
+)
+
+func TestNewRewardSummary(t *testing.T) {
+	require := require.New(t)
+
+	rewardingAddr, err := address.FromBytes(address.RewardingProtocolAddrHash[:])
+	require.NoError(err)
+	newLog := func(addr string, typ rewardingpb.RewardLog_RewardType, amount string) *action.Log {
+		data, err := proto.Marshal(&rewardingpb.RewardLog{
+			Type:   typ,
+			Addr:   identityset.Address(1).String(),
+			Amount: amount,
+		})
+		require.NoError(err)
+		return &action.Log{Address: addr, Data: data}
+	}
+	receipts := []*action.Receipt{
+		(&action.Receipt{}).AddLogs(newLog(rewardingAddr.String(), rewardingpb.RewardLog_BLOCK_REWARD, "16")),
+		(&action.Receipt{}).AddLogs(
+			newLog(rewardingAddr.String(), rewardingpb.RewardLog_EPOCH_REWARD, "100"),
+			newLog(rewardingAddr.String(), rewardingpb.RewardLog_EPOCH_REWARD, "50"),
+			newLog(rewardingAddr.String(), rewardingpb.RewardLog_FOUNDATION_BONUS, "80"),
+			newLog(identityset.Address(2).String(), rewardingpb.RewardLog_EPOCH_REWARD, "1000"),
+		),
+	}
+	summary, err := NewRewardSummary(3, receipts)
+	require.NoError(err)
+	require.Equal(uint64(3), summary.EpochNumber)
+	require.Equal("150", summary.TotalEpochReward)
+	require.Equal("80", summary.TotalFoundationBonus)
+	require.Equal(3, len(summary.Distributions))
+
+	// no epoch reward
+	summary, err = NewRewardSummary(3, receipts[:1])
+	require.NoError(err)
+	require.Nil(summary)
+}
+
+func TestPublish(t *testing.T) {
+	require := require.New(t)
+
+	var (
+		received []*Event
+		failures int
+	)
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failures > 0 {
+			failures--
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		evt := &Event{}
+		if err := json.NewDecoder(r.Body).Decode(evt); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		received = append(received, evt)
+	}))
+	defer svr.Close()
+
+	cfg := config.Default.EpochEvent
+	cfg.WebhookURLs = []string{svr.URL}
+	cfg.MaxRetries = 1
+	bus := NewBus(cfg, config.Default.Genesis, nil, nil, nil)
+	evt := &Event{
+		Type:        EpochStartedEventType,
+		EpochNumber: 2,
+		Height:      361,
+		Timestamp:   time.Unix(1600000000, 0).UTC(),
+		Candidates: []*Candidate{
+			{Address: identityset.Address(1).String(), Votes: "100"},
+		},
+	}
+	bus.Publish(context.Background(), evt)
+	require.Equal(1, len(received))
+	require.Equal(evt, received[0])
+
+	// succeed after retry
+	failures = 1
+	bus.Publish(context.Background(), evt)
+	require.Equal(2, len(received))
+
+	// give up after max retries
+	failures = 2
+	bus.Publish(context.Background(), evt)
+	require.Equal(2, len(received))
+}
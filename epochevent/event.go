@@ -0,0 +1,141 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package epochevent
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/iotexproject/iotex-address/address"
+
+	"github.com/iotexproject/iotex-core/action"
+	"github.com/iotexproject/iotex-core/action/protocol/rewarding/rewardingpb"
+	"github.com/iotexproject/iotex-core/action/protocol/vote"
+	"github.com/iotexproject/iotex-core/state"
+)
+
+// EpochStartedEventType is the type of the event published on the first block of an epoch
+const EpochStartedEventType = "epochStarted"
+
+type (
+	// Event is an epoch transition event published to the sinks
+	Event struct {
+		Type          string         `json:"type"`
+		EpochNumber   uint64         `json:"epochNumber"`
+		Height        uint64         `json:"height"`
+		Timestamp     time.Time      `json:"timestamp"`
+		Candidates    []*Candidate   `json:"candidates"`
+		ProbationList *ProbationList `json:"probationList,omitempty"`
+		Rewards       *RewardSummary `json:"rewards,omitempty"`
+	}
+
+	// Candidate is a delegate candidate of the epoch
+	Candidate struct {
+		Address       string `json:"address"`
+		Name          string `json:"name"`
+		RewardAddress string `json:"rewardAddress"`
+		Votes         string `json:"votes"`
+	}
+
+	// ProbationList is the probation list of the epoch
+	ProbationList struct {
+		IntensityRate uint32            `json:"intensityRate"`
+		Delegates     map[string]uint32 `json:"delegates"`
+	}
+
+	// RewardSummary summarizes the rewards distributed at the end of previous epoch
+	RewardSummary struct {
+		EpochNumber          uint64                `json:"epochNumber"`
+		TotalEpochReward     string                `json:"totalEpochReward"`
+		TotalFoundationBonus string                `json:"totalFoundationBonus"`
+		Distributions        []*RewardDistribution `json:"distributions"`
+	}
+
+	// RewardDistribution is a reward granted to an address
+	RewardDistribution struct {
+		Type    string `json:"type"`
+		Address string `json:"address"`
+		Amount  string `json:"amount"`
+	}
+)
+
+// NewCandidates converts the candidate list of the state
+func NewCandidates(list state.CandidateList) []*Candidate {
+	candidates := make([]*Candidate, 0, len(list))
+	for _, c := range list {
+		candidates = append(candidates, &Candidate{
+			Address:       c.Address,
+			Name:          string(c.CanName),
+			RewardAddress: c.RewardAddress,
+			Votes:         c.Votes.String(),
+		})
+	}
+	return candidates
+}
+
+// NewProbationList converts the probation list of the state
+func NewProbationList(pl *vote.ProbationList) *ProbationList {
+	delegates := make(map[string]uint32, len(pl.ProbationInfo))
+	for addr, count := range pl.ProbationInfo {
+		delegates[addr] = count
+	}
+	return &ProbationList{
+		IntensityRate: pl.IntensityRate,
+		Delegates:     delegates,
+	}
+}
+
+// NewRewardSummary summarizes the epoch reward and foundation bonus logs in the receipts of an epoch's last block
+func NewRewardSummary(epochNum uint64, receipts []*action.Receipt) (*RewardSummary, error) {
+	rewardingAddr, err := address.FromBytes(address.RewardingProtocolAddrHash[:])
+	if err != nil {
+		return nil, err
+	}
+	var (
+		epochReward     = big.NewInt(0)
+		foundationBonus = big.NewInt(0)
+		distributions   = make([]*RewardDistribution, 0)
+	)
+	for _, receipt := range receipts {
+		for _, l := range receipt.Logs() {
+			if l.Address != rewardingAddr.String() {
+				continue
+			}
+			rewardLog := rewardingpb.RewardLog{}
+			if err := proto.Unmarshal(l.Data, &rewardLog); err != nil {
+				return nil, err
+			}
+			amount, ok := new(big.Int).SetString(rewardLog.Amount, 10)
+			if !ok {
+				continue
+			}
+			switch rewardLog.Type {
+			case rewardingpb.RewardLog_EPOCH_REWARD:
+				epochReward.Add(epochReward, amount)
+			case rewardingpb.RewardLog_FOUNDATION_BONUS:
+				foundationBonus.Add(foundationBonus, amount)
+			default:
+				continue
+			}
+			distributions = append(distributions, &RewardDistribution{
+				Type:    rewardLog.Type.String(),
+				Address: rewardLog.Addr,
+				Amount:  rewardLog.Amount,
+			})
+		}
+	}
+	if len(distributions) == 0 {
+		return nil, nil
+	}
+	return &RewardSummary{
+		EpochNumber:          epochNum,
+		TotalEpochReward:     epochReward.String(),
+		TotalFoundationBonus: foundationBonus.String(),
+		Distributions:        distributions,
+	}, nil
+}
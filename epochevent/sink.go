@@ -0,0 +1,67 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package epochevent
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+type (
+	// Sink is an external system the epoch events are published to, such as webhook or message queue
+	Sink interface {
+		Name() string
+		Publish(context.Context, *Event) error
+	}
+
+	webhookSink struct {
+		url    string
+		client *http.Client
+	}
+)
+
+// NewWebhookSink creates a sink posting the events in json to the url
+func NewWebhookSink(url string, timeout time.Duration) Sink {
+	return &webhookSink{
+		url:    url,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+func (s *webhookSink) Name() string {
+	return "webhook:" + s.url
+}
+
+func (s *webhookSink) Publish(ctx context.Context, evt *Event) error {
+	data, err := json.Marshal(evt)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal event")
+	}
+	req, err := http.NewRequest(http.MethodPost, s.url, bytes.NewReader(data))
+	if err != nil {
+		return errors.Wrap(err, "failed to create request")
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return errors.Wrapf(err, "failed to post event to %s", s.url)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return errors.Errorf("webhook %s responded with status %d", s.url, resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,263 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package exporter
+
+import (
+	"context"
+	"encoding/hex"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+
+	"github.com/iotexproject/go-pkgs/hash"
+	"github.com/iotexproject/iotex-address/address"
+	"github.com/iotexproject/iotex-proto/golang/iotexapi"
+
+	"github.com/iotexproject/iotex-core/action"
+	"github.com/iotexproject/iotex-core/blockchain/block"
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/db"
+	"github.com/iotexproject/iotex-core/pkg/log"
+	"github.com/iotexproject/iotex-core/pkg/util/byteutil"
+)
+
+const (
+	// ExporterNamespace is the namespace to store the exported offset
+	ExporterNamespace = "Exporter"
+
+	// JSONEncoding encodes the messages in protobuf json format
+	JSONEncoding = "json"
+	// ProtobufEncoding encodes the messages in protobuf binary format
+	ProtobufEncoding = "protobuf"
+)
+
+var (
+	_offsetKey = []byte("offset")
+
+	exportedHeightMtc = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "iotex_exporter_height",
+			Help: "Height of the last block exported",
+		},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(exportedHeightMtc)
+}
+
+type (
+	// Publisher publishes messages to a message queue. Messages published before a successful Flush must be
+	// persisted by the message queue, so that the exporter can deliver every message at least once
+	Publisher interface {
+		Publish(topic string, data []byte) error
+		Flush() error
+		Close() error
+	}
+
+	// BlockReader reads the committed blocks and receipts
+	BlockReader interface {
+		Height() (uint64, error)
+		GetBlockByHeight(uint64) (*block.Block, error)
+		GetReceipts(uint64) ([]*action.Receipt, error)
+	}
+
+	// Exporter publishes every committed block, action and receipt to a message queue, in the order of height. The
+	// height of last exported block is stored, and exporting resumes from it after restart
+	Exporter struct {
+		cfg       config.Exporter
+		dao       BlockReader
+		kvStore   db.KVStore
+		publisher Publisher
+		marshal   func(proto.Message) ([]byte, error)
+		notify    chan struct{}
+		done      chan struct{}
+		wg        sync.WaitGroup
+		errMu     sync.RWMutex
+		lastErr   error
+	}
+)
+
+// NewExporter creates a new exporter
+func NewExporter(cfg config.Exporter, dao BlockReader, kv db.KVStore, publisher Publisher) (*Exporter, error) {
+	if kv == nil {
+		return nil, errors.New("empty kvStore")
+	}
+	if publisher == nil {
+		return nil, errors.New("empty publisher")
+	}
+	e := &Exporter{
+		cfg:       cfg,
+		dao:       dao,
+		kvStore:   kv,
+		publisher: publisher,
+		notify:    make(chan struct{}, 1),
+		done:      make(chan struct{}),
+	}
+	switch cfg.Encoding {
+	case ProtobufEncoding:
+		e.marshal = proto.Marshal
+	case JSONEncoding:
+		m := jsonpb.Marshaler{}
+		e.marshal = func(msg proto.Message) ([]byte, error) {
+			s, err := m.MarshalToString(msg)
+			return []byte(s), err
+		}
+	default:
+		return nil, errors.Errorf("unsupported encoding %s", cfg.Encoding)
+	}
+	return e, nil
+}
+
+// Start starts the exporter
+func (e *Exporter) Start(ctx context.Context) error {
+	if err := e.kvStore.Start(ctx); err != nil {
+		return err
+	}
+	e.wg.Add(1)
+	go e.run()
+	return nil
+}
+
+// Stop stops the exporter
+func (e *Exporter) Stop(ctx context.Context) error {
+	close(e.done)
+	e.wg.Wait()
+	if err := e.publisher.Close(); err != nil {
+		log.L().Warn("Failed to close publisher.", zap.Error(err))
+	}
+	return e.kvStore.Stop(ctx)
+}
+
+// ReceiveBlock notifies the exporter of the new block
+func (e *Exporter) ReceiveBlock(*block.Block) error {
+	select {
+	case e.notify <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Offset returns the height of the last exported block
+func (e *Exporter) Offset() (uint64, error) {
+	h, err := e.kvStore.Get(ExporterNamespace, _offsetKey)
+	switch errors.Cause(err) {
+	case nil:
+		return byteutil.BytesToUint64BigEndian(h), nil
+	case db.ErrNotExist, db.ErrBucketNotExist:
+		return 0, nil
+	default:
+		return 0, err
+	}
+}
+
+// Health returns the error of the last exporting attempt, nil if it succeeded
+func (e *Exporter) Health() error {
+	e.errMu.RLock()
+	defer e.errMu.RUnlock()
+	return e.lastErr
+}
+
+func (e *Exporter) run() {
+	defer e.wg.Done()
+	for {
+		err := e.catchUp()
+		if err != nil {
+			log.L().Error("Failed to export blocks.", zap.Error(err))
+		}
+		e.errMu.Lock()
+		e.lastErr = err
+		e.errMu.Unlock()
+		select {
+		case <-e.done:
+			return
+		case <-e.notify:
+		case <-time.After(e.cfg.RetryInterval):
+		}
+	}
+}
+
+func (e *Exporter) catchUp() error {
+	offset, err := e.Offset()
+	if err != nil {
+		return err
+	}
+	tipHeight, err := e.dao.Height()
+	if err != nil {
+		return err
+	}
+	for height := offset + 1; height <= tipHeight; height++ {
+		select {
+		case <-e.done:
+			return nil
+		default:
+		}
+		if err := e.exportBlock(height); err != nil {
+			return errors.Wrapf(err, "failed to export block %d", height)
+		}
+		if err := e.kvStore.Put(ExporterNamespace, _offsetKey, byteutil.Uint64ToBytesBigEndian(height)); err != nil {
+			return err
+		}
+		exportedHeightMtc.Set(float64(height))
+	}
+	return nil
+}
+
+func (e *Exporter) exportBlock(height uint64) error {
+	blk, err := e.dao.GetBlockByHeight(height)
+	if err != nil {
+		return err
+	}
+	receipts, err := e.dao.GetReceipts(height)
+	if err != nil {
+		return err
+	}
+	if err := e.publish("blocks", blk.ConvertToBlockPb()); err != nil {
+		return err
+	}
+	blkHash := blk.HashBlock()
+	gasConsumed := make(map[hash.Hash256]uint64, len(receipts))
+	for _, receipt := range receipts {
+		gasConsumed[receipt.ActionHash] = receipt.GasConsumed
+	}
+	for _, selp := range blk.Actions {
+		actHash := selp.Hash()
+		sender, _ := address.FromBytes(selp.SrcPubkey().Hash())
+		gasFee := new(big.Int).Mul(selp.GasPrice(), new(big.Int).SetUint64(gasConsumed[actHash]))
+		if err := e.publish("actions", &iotexapi.ActionInfo{
+			Action:    selp.Proto(),
+			ActHash:   hex.EncodeToString(actHash[:]),
+			BlkHash:   hex.EncodeToString(blkHash[:]),
+			BlkHeight: height,
+			Sender:    sender.String(),
+			GasFee:    gasFee.String(),
+			Timestamp: blk.Header.BlockHeaderCoreProto().Timestamp,
+		}); err != nil {
+			return err
+		}
+	}
+	for _, receipt := range receipts {
+		if err := e.publish("receipts", receipt.ConvertToReceiptPb()); err != nil {
+			return err
+		}
+	}
+	return e.publisher.Flush()
+}
+
+func (e *Exporter) publish(topic string, msg proto.Message) error {
+	data, err := e.marshal(msg)
+	if err != nil {
+		return err
+	}
+	return e.publisher.Publish(e.cfg.TopicPrefix+"."+topic, data)
+}
@@ -0,0 +1,110 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package exporter
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/action"
+	"github.com/iotexproject/iotex-core/blockchain/block"
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/db"
+	"github.com/iotexproject/iotex-core/test/identityset"
+	"github.com/iotexproject/iotex-core/testutil"
+)
+
+type (
+	testBlockReader struct {
+		blks []*block.Block
+	}
+
+	testPublisher struct {
+		pending   []string
+		published []string
+		failAt    int
+	}
+)
+
+func (r *testBlockReader) Height() (uint64, error) {
+	return uint64(len(r.blks)), nil
+}
+
+func (r *testBlockReader) GetBlockByHeight(height uint64) (*block.Block, error) {
+	return r.blks[height-1], nil
+}
+
+func (r *testBlockReader) GetReceipts(height uint64) ([]*action.Receipt, error) {
+	return r.blks[height-1].Receipts, nil
+}
+
+func (p *testPublisher) Publish(topic string, data []byte) error {
+	if p.failAt > 0 && len(p.published)+len(p.pending) == p.failAt {
+		p.failAt = 0
+		p.pending = nil
+		return errors.New("connection lost")
+	}
+	p.pending = append(p.pending, topic)
+	return nil
+}
+
+func (p *testPublisher) Flush() error {
+	p.published = append(p.published, p.pending...)
+	p.pending = nil
+	return nil
+}
+
+func (p *testPublisher) Close() error {
+	return nil
+}
+
+func TestExporter(t *testing.T) {
+	require := require.New(t)
+
+	reader := &testBlockReader{}
+	for i := 1; i <= 3; i++ {
+		tsf, err := testutil.SignedTransfer(identityset.Address(1).String(), identityset.PrivateKey(0), uint64(i), big.NewInt(1), nil, 10000, big.NewInt(1))
+		require.NoError(err)
+		blk, err := block.NewTestingBuilder().
+			SetHeight(uint64(i)).
+			SetTimeStamp(time.Now()).
+			AddActions(tsf).
+			SetReceipts([]*action.Receipt{{ActionHash: tsf.Hash(), GasConsumed: 10000}}).
+			SignAndBuild(identityset.PrivateKey(0))
+		require.NoError(err)
+		reader.blks = append(reader.blks, &blk)
+	}
+	cfg := config.Default.Exporter
+	cfg.Encoding = JSONEncoding
+	publisher := &testPublisher{failAt: 4}
+	e, err := NewExporter(cfg, reader, db.NewMemKVStore(), publisher)
+	require.NoError(err)
+	require.NoError(e.kvStore.Start(context.Background()))
+
+	// first block exported, fails in the middle of the second block
+	require.Error(e.catchUp())
+	offset, err := e.Offset()
+	require.NoError(err)
+	require.Equal(uint64(1), offset)
+	require.Equal([]string{"iotex.blocks", "iotex.actions", "iotex.receipts"}, publisher.published)
+
+	// resume from the second block
+	require.NoError(e.catchUp())
+	offset, err = e.Offset()
+	require.NoError(err)
+	require.Equal(uint64(3), offset)
+	require.Equal(9, len(publisher.published))
+
+	cfg.Encoding = "xml"
+	_, err = NewExporter(cfg, reader, db.NewMemKVStore(), publisher)
+	require.Error(err)
+}
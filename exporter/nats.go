@@ -0,0 +1,141 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package exporter
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const _natsConnect = `CONNECT {"verbose":false,"pedantic":false,"name":"iotex-core"}` + "\r\n"
+
+// natsPublisher publishes messages to a NATS server with the text protocol. Flush sends a PING and waits for the
+// PONG, which confirms the server has processed all messages published before
+type natsPublisher struct {
+	endpoint string
+	timeout  time.Duration
+	conn     net.Conn
+	r        *bufio.Reader
+	w        *bufio.Writer
+}
+
+// NewNATSPublisher creates a publisher to the NATS server at endpoint (host:port)
+func NewNATSPublisher(endpoint string, timeout time.Duration) Publisher {
+	return &natsPublisher{
+		endpoint: endpoint,
+		timeout:  timeout,
+	}
+}
+
+func (p *natsPublisher) Publish(topic string, data []byte) error {
+	if p.conn == nil {
+		if err := p.connect(); err != nil {
+			return err
+		}
+	}
+	if _, err := fmt.Fprintf(p.w, "PUB %s %d\r\n", topic, len(data)); err != nil {
+		return p.fail(err)
+	}
+	if _, err := p.w.Write(data); err != nil {
+		return p.fail(err)
+	}
+	if _, err := p.w.WriteString("\r\n"); err != nil {
+		return p.fail(err)
+	}
+	return nil
+}
+
+func (p *natsPublisher) Flush() error {
+	if p.conn == nil {
+		if err := p.connect(); err != nil {
+			return err
+		}
+	}
+	if err := p.ping(); err != nil {
+		return p.fail(err)
+	}
+	return nil
+}
+
+func (p *natsPublisher) Close() error {
+	if p.conn == nil {
+		return nil
+	}
+	err := p.conn.Close()
+	p.conn = nil
+	return err
+}
+
+func (p *natsPublisher) connect() error {
+	conn, err := net.DialTimeout("tcp", p.endpoint, p.timeout)
+	if err != nil {
+		return errors.Wrapf(err, "failed to connect to nats server %s", p.endpoint)
+	}
+	p.conn = conn
+	p.r = bufio.NewReader(conn)
+	p.w = bufio.NewWriter(conn)
+	if err := conn.SetReadDeadline(time.Now().Add(p.timeout)); err != nil {
+		return p.fail(err)
+	}
+	line, err := p.r.ReadString('\n')
+	if err != nil {
+		return p.fail(err)
+	}
+	if !strings.HasPrefix(line, "INFO") {
+		return p.fail(errors.Errorf("unexpected greeting from nats server: %s", strings.TrimSpace(line)))
+	}
+	if _, err := p.w.WriteString(_natsConnect); err != nil {
+		return p.fail(err)
+	}
+	if err := p.ping(); err != nil {
+		return p.fail(err)
+	}
+	return nil
+}
+
+func (p *natsPublisher) ping() error {
+	if _, err := p.w.WriteString("PING\r\n"); err != nil {
+		return err
+	}
+	if err := p.w.Flush(); err != nil {
+		return err
+	}
+	if err := p.conn.SetReadDeadline(time.Now().Add(p.timeout)); err != nil {
+		return err
+	}
+	for {
+		line, err := p.r.ReadString('\n')
+		if err != nil {
+			return err
+		}
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "PONG":
+			return nil
+		case line == "PING":
+			if _, err := p.w.WriteString("PONG\r\n"); err != nil {
+				return err
+			}
+			if err := p.w.Flush(); err != nil {
+				return err
+			}
+		case strings.HasPrefix(line, "-ERR"):
+			return errors.Errorf("nats server error: %s", line)
+		}
+	}
+}
+
+// fail closes the connection so that the next call reconnects
+func (p *natsPublisher) fail(err error) error {
+	p.Close()
+	return err
+}
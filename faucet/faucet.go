@@ -0,0 +1,325 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package faucet
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+
+	"github.com/iotexproject/go-pkgs/crypto"
+	"github.com/iotexproject/iotex-address/address"
+	"github.com/iotexproject/iotex-proto/golang/iotexapi"
+
+	"github.com/iotexproject/iotex-core/action"
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/pkg/log"
+	"github.com/iotexproject/iotex-core/pkg/util/httputil"
+)
+
+const _maxRequestSize = 4096
+
+var (
+	// ErrRateLimited indicates the address or ip has claimed recently
+	ErrRateLimited = errors.New("claimed too frequently")
+	// ErrUnauthorized indicates the claim fails the verification
+	ErrUnauthorized = errors.New("unauthorized claim")
+	// ErrInvalidAddress indicates the claiming address is invalid
+	ErrInvalidAddress = errors.New("invalid address")
+
+	_claimMtc = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "iotex_faucet_claims",
+			Help: "Number of faucet claims by result",
+		},
+		[]string{"result"},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(_claimMtc)
+}
+
+type (
+	// APIServer is the api of the node to query the nonce and send the transfer
+	APIServer interface {
+		GetAccount(context.Context, *iotexapi.GetAccountRequest) (*iotexapi.GetAccountResponse, error)
+		SendAction(context.Context, *iotexapi.SendActionRequest) (*iotexapi.SendActionResponse, error)
+	}
+
+	// ClaimRequest is the request to claim tokens
+	ClaimRequest struct {
+		Address string `json:"address"`
+		// Token is the access token, it could also be carried in the X-Faucet-Token header
+		Token string `json:"token,omitempty"`
+		// Captcha is the captcha response
+		Captcha string `json:"captcha,omitempty"`
+		// IP is the ip of the client, it's filled by the faucet
+		IP string `json:"-"`
+	}
+
+	// ClaimResponse is the response of a successful claim
+	ClaimResponse struct {
+		ActionHash string `json:"actionHash"`
+		Amount     string `json:"amount"`
+	}
+
+	// Verifier verifies a claim before the tokens are sent, e.g. checking the access token or captcha response
+	Verifier interface {
+		Verify(ctx context.Context, req *ClaimRequest) error
+	}
+
+	// VerifierFunc is an adapter to use a function as a verifier
+	VerifierFunc func(ctx context.Context, req *ClaimRequest) error
+
+	// Faucet sends tokens from a funded account to the claiming addresses, with the claims of an address or from an
+	// ip rate limited
+	Faucet struct {
+		cfg       config.Faucet
+		api       APIServer
+		sk        crypto.PrivateKey
+		sender    string
+		amount    *big.Int
+		gasPrice  *big.Int
+		verifiers []Verifier
+		server    http.Server
+		now       func() time.Time
+
+		mutex     sync.Mutex
+		addrClaim map[string]time.Time
+		ipClaim   map[string]time.Time
+	}
+)
+
+// Verify calls f(ctx, req)
+func (f VerifierFunc) Verify(ctx context.Context, req *ClaimRequest) error { return f(ctx, req) }
+
+// NewFaucet creates a faucet, the access token and captcha verifiers are added according to the config
+func NewFaucet(cfg config.Faucet, api APIServer, verifiers ...Verifier) (*Faucet, error) {
+	sk, err := crypto.HexStringToPrivateKey(cfg.PrivateKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load faucet private key")
+	}
+	sender, err := address.FromBytes(sk.PublicKey().Hash())
+	if err != nil {
+		return nil, err
+	}
+	amount, ok := new(big.Int).SetString(cfg.Amount, 10)
+	if !ok {
+		return nil, errors.Errorf("invalid amount %s", cfg.Amount)
+	}
+	gasPrice, ok := new(big.Int).SetString(cfg.GasPrice, 10)
+	if !ok {
+		return nil, errors.Errorf("invalid gas price %s", cfg.GasPrice)
+	}
+	f := &Faucet{
+		cfg:       cfg,
+		api:       api,
+		sk:        sk,
+		sender:    sender.String(),
+		amount:    amount,
+		gasPrice:  gasPrice,
+		now:       time.Now,
+		addrClaim: make(map[string]time.Time),
+		ipClaim:   make(map[string]time.Time),
+	}
+	if len(cfg.Tokens) > 0 {
+		f.verifiers = append(f.verifiers, NewTokenVerifier(cfg.Tokens))
+	}
+	if cfg.CaptchaVerifyURL != "" {
+		f.verifiers = append(f.verifiers, NewCaptchaVerifier(cfg.CaptchaVerifyURL, cfg.CaptchaSecret))
+	}
+	f.verifiers = append(f.verifiers, verifiers...)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/claim", f.handleClaim)
+	mux.HandleFunc("/info", f.handleInfo)
+	f.server = httputil.Server(fmt.Sprintf(":%d", cfg.Port), mux)
+	return f, nil
+}
+
+// AddVerifier adds a verifier of the claims
+func (f *Faucet) AddVerifier(v Verifier) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.verifiers = append(f.verifiers, v)
+}
+
+// Start starts the http endpoint of the faucet
+func (f *Faucet) Start(_ context.Context) error {
+	ln, err := httputil.LimitListener(f.server.Addr)
+	if err != nil {
+		return errors.Wrap(err, "failed to listen on faucet port")
+	}
+	go func() {
+		if err := f.server.Serve(ln); err != nil {
+			log.L().Info("Faucet server stopped.", zap.Error(err))
+		}
+	}()
+	log.L().Info("Faucet started.", zap.String("address", f.sender), zap.Int("port", f.cfg.Port))
+	return nil
+}
+
+// Stop stops the http endpoint of the faucet
+func (f *Faucet) Stop(ctx context.Context) error { return f.server.Shutdown(ctx) }
+
+// Claim verifies the claim and sends the tokens, it returns the hash of the transfer
+func (f *Faucet) Claim(ctx context.Context, req *ClaimRequest) (string, error) {
+	recipient, err := address.FromString(req.Address)
+	if err != nil {
+		return "", errors.Wrapf(ErrInvalidAddress, "%s: %v", req.Address, err)
+	}
+	f.mutex.Lock()
+	verifiers := f.verifiers
+	f.mutex.Unlock()
+	for _, v := range verifiers {
+		if err := v.Verify(ctx, req); err != nil {
+			return "", errors.Wrap(ErrUnauthorized, err.Error())
+		}
+	}
+
+	// the claim is sent under the lock, so that concurrent claims neither get around the rate limit nor reuse a nonce
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	now := f.now()
+	f.prune(now)
+	if _, ok := f.addrClaim[recipient.String()]; ok {
+		return "", errors.Wrapf(ErrRateLimited, "address %s", recipient.String())
+	}
+	if _, ok := f.ipClaim[req.IP]; ok && req.IP != "" {
+		return "", errors.Wrapf(ErrRateLimited, "ip %s", req.IP)
+	}
+	hash, err := f.send(ctx, recipient.String())
+	if err != nil {
+		return "", err
+	}
+	f.addrClaim[recipient.String()] = now
+	if req.IP != "" {
+		f.ipClaim[req.IP] = now
+	}
+	return hash, nil
+}
+
+func (f *Faucet) send(ctx context.Context, recipient string) (string, error) {
+	res, err := f.api.GetAccount(ctx, &iotexapi.GetAccountRequest{Address: f.sender})
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get the nonce of faucet")
+	}
+	nonce := res.GetAccountMeta().GetPendingNonce()
+	tsf, err := action.NewTransfer(nonce, f.amount, recipient, nil, f.cfg.GasLimit, f.gasPrice)
+	if err != nil {
+		return "", err
+	}
+	elp := (&action.EnvelopeBuilder{}).SetNonce(nonce).
+		SetGasLimit(f.cfg.GasLimit).
+		SetGasPrice(f.gasPrice).
+		SetAction(tsf).Build()
+	selp, err := action.Sign(elp, f.sk)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to sign transfer")
+	}
+	sent, err := f.api.SendAction(ctx, &iotexapi.SendActionRequest{Action: selp.Proto()})
+	if err != nil {
+		return "", errors.Wrap(err, "failed to send transfer")
+	}
+	return sent.ActionHash, nil
+}
+
+// prune removes the claims no longer limiting
+func (f *Faucet) prune(now time.Time) {
+	for addr, t := range f.addrClaim {
+		if now.Sub(t) >= f.cfg.AddressInterval {
+			delete(f.addrClaim, addr)
+		}
+	}
+	for ip, t := range f.ipClaim {
+		if now.Sub(t) >= f.cfg.IPInterval {
+			delete(f.ipClaim, ip)
+		}
+	}
+}
+
+func (f *Faucet) handleClaim(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+		return
+	}
+	req := &ClaimRequest{}
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, _maxRequestSize)).Decode(req); err != nil {
+		writeError(w, http.StatusBadRequest, errors.Wrap(err, "invalid request"))
+		return
+	}
+	if token := r.Header.Get("X-Faucet-Token"); token != "" {
+		req.Token = token
+	}
+	req.IP = f.clientIP(r)
+	hash, err := f.Claim(r.Context(), req)
+	switch errors.Cause(err) {
+	case nil:
+		_claimMtc.WithLabelValues("success").Inc()
+		writeJSON(w, http.StatusOK, &ClaimResponse{ActionHash: hash, Amount: f.amount.String()})
+	case ErrRateLimited:
+		_claimMtc.WithLabelValues("rateLimited").Inc()
+		writeError(w, http.StatusTooManyRequests, err)
+	case ErrUnauthorized:
+		_claimMtc.WithLabelValues("unauthorized").Inc()
+		writeError(w, http.StatusForbidden, err)
+	case ErrInvalidAddress:
+		_claimMtc.WithLabelValues("invalid").Inc()
+		writeError(w, http.StatusBadRequest, err)
+	default:
+		_claimMtc.WithLabelValues("failure").Inc()
+		log.L().Error("Failed to send faucet transfer.", zap.Error(err))
+		writeError(w, http.StatusInternalServerError, err)
+	}
+}
+
+func (f *Faucet) handleInfo(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"address":         f.sender,
+		"amount":          f.amount.String(),
+		"addressInterval": f.cfg.AddressInterval.String(),
+		"ipInterval":      f.cfg.IPInterval.String(),
+		"tokenRequired":   len(f.cfg.Tokens) > 0,
+		"captchaRequired": f.cfg.CaptchaVerifyURL != "",
+	})
+}
+
+func (f *Faucet) clientIP(r *http.Request) string {
+	if f.cfg.TrustForwardedFor {
+		if fwd := r.Header.Get("X-Forwarded-For"); fwd != "" {
+			return strings.TrimSpace(strings.Split(fwd, ",")[0])
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.L().Warn("Failed to send http response.", zap.Error(err))
+	}
+}
+
+func writeError(w http.ResponseWriter, code int, err error) {
+	writeJSON(w, code, map[string]string{"error": err.Error()})
+}
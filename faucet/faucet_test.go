@@ -0,0 +1,155 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package faucet
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-proto/golang/iotexapi"
+	"github.com/iotexproject/iotex-proto/golang/iotextypes"
+
+	"github.com/iotexproject/iotex-core/action"
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/test/identityset"
+)
+
+type fakeAPI struct {
+	nonce uint64
+	sent  []action.SealedEnvelope
+}
+
+func (api *fakeAPI) GetAccount(_ context.Context, in *iotexapi.GetAccountRequest) (*iotexapi.GetAccountResponse, error) {
+	return &iotexapi.GetAccountResponse{AccountMeta: &iotextypes.AccountMeta{
+		Address:      in.Address,
+		PendingNonce: api.nonce,
+	}}, nil
+}
+
+func (api *fakeAPI) SendAction(_ context.Context, in *iotexapi.SendActionRequest) (*iotexapi.SendActionResponse, error) {
+	selp := action.SealedEnvelope{}
+	if err := selp.LoadProto(in.Action); err != nil {
+		return nil, err
+	}
+	api.sent = append(api.sent, selp)
+	api.nonce++
+	h := selp.Hash()
+	return &iotexapi.SendActionResponse{ActionHash: hex.EncodeToString(h[:])}, nil
+}
+
+func claim(f *Faucet, body, ip string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/claim", strings.NewReader(body))
+	req.RemoteAddr = ip + ":1234"
+	rec := httptest.NewRecorder()
+	f.handleClaim(rec, req)
+	return rec
+}
+
+func TestFaucet(t *testing.T) {
+	require := require.New(t)
+
+	cfg := config.Default.Faucet
+	cfg.PrivateKey = identityset.PrivateKey(27).HexString()
+	api := &fakeAPI{nonce: 5}
+	f, err := NewFaucet(cfg, api)
+	require.NoError(err)
+	now := time.Now()
+	f.now = func() time.Time { return now }
+
+	rec := claim(f, `{"address":"`+identityset.Address(28).String()+`"}`, "1.1.1.1")
+	require.Equal(http.StatusOK, rec.Code)
+	res := ClaimResponse{}
+	require.NoError(json.NewDecoder(rec.Body).Decode(&res))
+	require.Equal(cfg.Amount, res.Amount)
+	require.Len(api.sent, 1)
+	h := api.sent[0].Hash()
+	require.Equal(hex.EncodeToString(h[:]), res.ActionHash)
+	tsf, ok := api.sent[0].Action().(*action.Transfer)
+	require.True(ok)
+	require.Equal(identityset.Address(28).String(), tsf.Recipient())
+	require.Equal(cfg.Amount, tsf.Amount().String())
+	require.Equal(uint64(5), api.sent[0].Nonce())
+	require.Equal(identityset.PrivateKey(27).PublicKey().HexString(), api.sent[0].SrcPubkey().HexString())
+
+	// rate limited by address and by ip
+	rec = claim(f, `{"address":"`+identityset.Address(28).String()+`"}`, "2.2.2.2")
+	require.Equal(http.StatusTooManyRequests, rec.Code)
+	rec = claim(f, `{"address":"`+identityset.Address(29).String()+`"}`, "1.1.1.1")
+	require.Equal(http.StatusTooManyRequests, rec.Code)
+	rec = claim(f, `{"address":"`+identityset.Address(29).String()+`"}`, "2.2.2.2")
+	require.Equal(http.StatusOK, rec.Code)
+	require.Equal(uint64(6), api.sent[1].Nonce())
+
+	now = now.Add(cfg.IPInterval)
+	rec = claim(f, `{"address":"`+identityset.Address(30).String()+`"}`, "1.1.1.1")
+	require.Equal(http.StatusOK, rec.Code)
+	rec = claim(f, `{"address":"`+identityset.Address(28).String()+`"}`, "3.3.3.3")
+	require.Equal(http.StatusTooManyRequests, rec.Code)
+	now = now.Add(cfg.AddressInterval)
+	rec = claim(f, `{"address":"`+identityset.Address(28).String()+`"}`, "3.3.3.3")
+	require.Equal(http.StatusOK, rec.Code)
+
+	rec = claim(f, `{"address":"io1invalid"}`, "4.4.4.4")
+	require.Equal(http.StatusBadRequest, rec.Code)
+	rec = claim(f, `not json`, "4.4.4.4")
+	require.Equal(http.StatusBadRequest, rec.Code)
+	require.Len(api.sent, 4)
+}
+
+func TestVerifiers(t *testing.T) {
+	require := require.New(t)
+
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(r.ParseForm())
+		if r.Form.Get("secret") == "secret" && r.Form.Get("response") == "human" {
+			_, _ = w.Write([]byte(`{"success":true}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"success":false,"error-codes":["invalid-input-response"]}`))
+	}))
+	defer svr.Close()
+
+	cfg := config.Default.Faucet
+	cfg.PrivateKey = identityset.PrivateKey(27).HexString()
+	cfg.Tokens = []string{"token"}
+	cfg.CaptchaVerifyURL = svr.URL
+	cfg.CaptchaSecret = "secret"
+	api := &fakeAPI{}
+	f, err := NewFaucet(cfg, api)
+	require.NoError(err)
+	f.AddVerifier(VerifierFunc(func(_ context.Context, req *ClaimRequest) error {
+		if req.Address == identityset.Address(31).String() {
+			return ErrUnauthorized
+		}
+		return nil
+	}))
+
+	rec := claim(f, `{"address":"`+identityset.Address(28).String()+`","captcha":"human"}`, "1.1.1.1")
+	require.Equal(http.StatusForbidden, rec.Code)
+	rec = claim(f, `{"address":"`+identityset.Address(28).String()+`","token":"token","captcha":"robot"}`, "1.1.1.1")
+	require.Equal(http.StatusForbidden, rec.Code)
+	require.Contains(rec.Body.String(), "invalid-input-response")
+	rec = claim(f, `{"address":"`+identityset.Address(31).String()+`","token":"token","captcha":"human"}`, "1.1.1.1")
+	require.Equal(http.StatusForbidden, rec.Code)
+	require.Empty(api.sent)
+
+	req := httptest.NewRequest(http.MethodPost, "/claim",
+		strings.NewReader(`{"address":"`+identityset.Address(28).String()+`","captcha":"human"}`))
+	req.Header.Set("X-Faucet-Token", "token")
+	rec = httptest.NewRecorder()
+	f.handleClaim(rec, req)
+	require.Equal(http.StatusOK, rec.Code)
+	require.Len(api.sent, 1)
+}
@@ -0,0 +1,94 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package faucet
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const _captchaTimeout = 10 * time.Second
+
+type (
+	tokenVerifier struct {
+		tokens []string
+	}
+
+	captchaVerifier struct {
+		verifyURL string
+		secret    string
+		client    *http.Client
+	}
+)
+
+// NewTokenVerifier creates a verifier accepting the claims carrying one of the tokens
+func NewTokenVerifier(tokens []string) Verifier {
+	return &tokenVerifier{tokens: tokens}
+}
+
+func (v *tokenVerifier) Verify(_ context.Context, req *ClaimRequest) error {
+	for _, token := range v.tokens {
+		if subtle.ConstantTimeCompare([]byte(token), []byte(req.Token)) == 1 {
+			return nil
+		}
+	}
+	return errors.New("invalid access token")
+}
+
+// NewCaptchaVerifier creates a verifier checking the captcha response with the siteverify api, which is shared by
+// recaptcha (https://www.google.com/recaptcha/api/siteverify) and hcaptcha (https://hcaptcha.com/siteverify)
+func NewCaptchaVerifier(verifyURL, secret string) Verifier {
+	return &captchaVerifier{
+		verifyURL: verifyURL,
+		secret:    secret,
+		client:    &http.Client{Timeout: _captchaTimeout},
+	}
+}
+
+func (v *captchaVerifier) Verify(ctx context.Context, req *ClaimRequest) error {
+	if req.Captcha == "" {
+		return errors.New("captcha response is empty")
+	}
+	form := url.Values{
+		"secret":   {v.secret},
+		"response": {req.Captcha},
+	}
+	if req.IP != "" {
+		form.Set("remoteip", req.IP)
+	}
+	httpReq, err := http.NewRequest(http.MethodPost, v.verifyURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	httpReq.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := v.client.Do(httpReq.WithContext(ctx))
+	if err != nil {
+		return errors.Wrap(err, "failed to verify captcha")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("failed to verify captcha, status %s", resp.Status)
+	}
+	var result struct {
+		Success    bool     `json:"success"`
+		ErrorCodes []string `json:"error-codes"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return errors.Wrap(err, "failed to decode captcha verification")
+	}
+	if !result.Success {
+		return errors.Errorf("captcha verification failed: %s", strings.Join(result.ErrorCodes, ", "))
+	}
+	return nil
+}
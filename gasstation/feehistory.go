@@ -0,0 +1,147 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package gasstation
+
+import (
+	"math/big"
+	"sort"
+
+	"github.com/iotexproject/go-pkgs/hash"
+	"github.com/pkg/errors"
+
+	"github.com/iotexproject/iotex-core/action/protocol"
+)
+
+// _maxFeeHistoryBlocks is the max number of blocks in a fee history, the same as eth_feeHistory of geth
+const _maxFeeHistoryBlocks = 1024
+
+type (
+	// FeeHistory is the fee history of a range of blocks in the form of eth_feeHistory. IoTeX has no base fee, so the
+	// base fee of every block is synthesized as the default gas price, and the rewards are the parts of the gas prices
+	// above it
+	FeeHistory struct {
+		OldestBlock uint64
+		// BaseFeePerGas has one more element than the blocks, which is the base fee of the block after the newest
+		BaseFeePerGas []*big.Int
+		// GasUsedRatio is the gas consumed of each block divided by the block gas limit
+		GasUsedRatio []float64
+		// Reward is the rewards of each block at the requested percentiles, weighted by the gas consumed
+		Reward [][]*big.Int
+	}
+
+	actionReward struct {
+		reward      *big.Int
+		gasConsumed uint64
+	}
+)
+
+// FeeHistory returns the fee history of at most blockCount blocks up to the newest block
+func (gs *GasStation) FeeHistory(blockCount, newestBlock uint64, rewardPercentiles []float64) (*FeeHistory, error) {
+	for i, p := range rewardPercentiles {
+		if p < 0 || p > 100 {
+			return nil, errors.Wrapf(protocol.ErrInvalidArgument, "reward percentile %f is out of [0, 100]", p)
+		}
+		if i > 0 && p < rewardPercentiles[i-1] {
+			return nil, errors.Wrapf(protocol.ErrInvalidArgument, "reward percentiles are not in ascending order")
+		}
+	}
+	if tip := gs.bc.TipHeight(); newestBlock > tip {
+		return nil, errors.Wrapf(protocol.ErrFutureHeight, "block %d is higher than tip height %d", newestBlock, tip)
+	}
+	if blockCount > _maxFeeHistoryBlocks {
+		blockCount = _maxFeeHistoryBlocks
+	}
+	// the genesis block has no action, so the history starts from block 1
+	if blockCount > newestBlock {
+		blockCount = newestBlock
+	}
+	baseFee := new(big.Int).SetUint64(gs.cfg.GasStation.DefaultGas)
+	gasLimit := gs.bc.Genesis().BlockGasLimit
+	ret := &FeeHistory{
+		OldestBlock:   newestBlock + 1 - blockCount,
+		BaseFeePerGas: make([]*big.Int, 0, blockCount+1),
+		GasUsedRatio:  make([]float64, 0, blockCount),
+	}
+	if len(rewardPercentiles) > 0 {
+		ret.Reward = make([][]*big.Int, 0, blockCount)
+	}
+	for height := ret.OldestBlock; height <= newestBlock; height++ {
+		rewards, gasConsumed, err := gs.blockRewards(height, baseFee)
+		if err != nil {
+			return nil, err
+		}
+		ret.BaseFeePerGas = append(ret.BaseFeePerGas, baseFee)
+		ret.GasUsedRatio = append(ret.GasUsedRatio, float64(gasConsumed)/float64(gasLimit))
+		if len(rewardPercentiles) > 0 {
+			ret.Reward = append(ret.Reward, rewardsAtPercentiles(rewards, gasConsumed, rewardPercentiles))
+		}
+	}
+	ret.BaseFeePerGas = append(ret.BaseFeePerGas, baseFee)
+	return ret, nil
+}
+
+// blockRewards returns the rewards of the user actions in the block sorted in ascending order, and the gas consumed
+// by the block
+func (gs *GasStation) blockRewards(height uint64, baseFee *big.Int) ([]actionReward, uint64, error) {
+	blk, err := gs.dao.GetBlockByHeight(height)
+	if err != nil {
+		return nil, 0, err
+	}
+	receipts, err := gs.dao.GetReceipts(height)
+	if err != nil {
+		return nil, 0, err
+	}
+	gasConsumed := make(map[hash.Hash256]uint64, len(receipts))
+	var total uint64
+	for _, r := range receipts {
+		gasConsumed[r.ActionHash] = r.GasConsumed
+		total += r.GasConsumed
+	}
+	rewards := make([]actionReward, 0, len(blk.Actions))
+	for _, act := range blk.Actions {
+		if gs.IsSystemAction(act) {
+			continue
+		}
+		reward := new(big.Int).Sub(act.GasPrice(), baseFee)
+		if reward.Sign() < 0 {
+			reward.SetUint64(0)
+		}
+		rewards = append(rewards, actionReward{
+			reward:      reward,
+			gasConsumed: gasConsumed[act.Hash()],
+		})
+	}
+	sort.Slice(rewards, func(i, j int) bool {
+		return rewards[i].reward.Cmp(rewards[j].reward) < 0
+	})
+	return rewards, total, nil
+}
+
+// rewardsAtPercentiles returns the reward of the first action at which the accumulated gas consumed reaches each
+// percentile of the gas consumed by the block, as geth does
+func rewardsAtPercentiles(rewards []actionReward, gasConsumed uint64, percentiles []float64) []*big.Int {
+	ret := make([]*big.Int, len(percentiles))
+	if len(rewards) == 0 {
+		for i := range ret {
+			ret[i] = big.NewInt(0)
+		}
+		return ret
+	}
+	var (
+		idx        int
+		sumGasUsed = rewards[0].gasConsumed
+	)
+	for i, p := range percentiles {
+		threshold := uint64(float64(gasConsumed) * p / 100)
+		for sumGasUsed < threshold && idx < len(rewards)-1 {
+			idx++
+			sumGasUsed += rewards[idx].gasConsumed
+		}
+		ret[i] = new(big.Int).Set(rewards[idx].reward)
+	}
+	return ret
+}
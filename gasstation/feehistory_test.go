@@ -0,0 +1,118 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package gasstation
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/action/protocol"
+	"github.com/iotexproject/iotex-core/action/protocol/account"
+	accountutil "github.com/iotexproject/iotex-core/action/protocol/account/util"
+	"github.com/iotexproject/iotex-core/action/protocol/rewarding"
+	"github.com/iotexproject/iotex-core/action/protocol/rolldpos"
+	"github.com/iotexproject/iotex-core/actpool"
+	"github.com/iotexproject/iotex-core/blockchain"
+	"github.com/iotexproject/iotex-core/blockchain/block"
+	"github.com/iotexproject/iotex-core/blockchain/blockdao"
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/pkg/unit"
+	"github.com/iotexproject/iotex-core/state/factory"
+	"github.com/iotexproject/iotex-core/test/identityset"
+	"github.com/iotexproject/iotex-core/testutil"
+)
+
+func TestFeeHistory(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+	cfg := config.Default
+	cfg.Genesis.BlockGasLimit = uint64(100000)
+	cfg.Genesis.EnableGravityChainVoting = false
+	registry := protocol.NewRegistry()
+	require.NoError(account.NewProtocol(rewarding.DepositGas).Register(registry))
+	rp := rolldpos.NewProtocol(cfg.Genesis.NumCandidateDelegates, cfg.Genesis.NumDelegates, cfg.Genesis.NumSubEpochs)
+	require.NoError(rp.Register(registry))
+	require.NoError(rewarding.NewProtocol(0, 0).Register(registry))
+	sf, err := factory.NewFactory(cfg, factory.InMemTrieOption(), factory.RegistryOption(registry))
+	require.NoError(err)
+	ap, err := actpool.NewActPool(sf, cfg.ActPool)
+	require.NoError(err)
+	blkMemDao := blockdao.NewBlockDAOInMemForTest([]blockdao.BlockIndexer{sf})
+	bc := blockchain.NewBlockchain(
+		cfg,
+		blkMemDao,
+		factory.NewMinter(sf, ap),
+		blockchain.BlockValidatorOption(block.NewValidator(
+			sf,
+			protocol.NewGenericValidator(sf, accountutil.AccountState),
+		)),
+	)
+	require.NoError(bc.Start(ctx))
+	defer func() {
+		require.NoError(bc.Stop(ctx))
+	}()
+
+	// block i has a transfer of gas price (i+10) Qev
+	for i := 1; i <= 5; i++ {
+		selp, err := testutil.SignedTransfer(identityset.Address(27).String(), identityset.PrivateKey(0), uint64(i),
+			big.NewInt(100), []byte{}, uint64(100000), new(big.Int).Mul(big.NewInt(int64(i)+10), big.NewInt(unit.Qev)))
+		require.NoError(err)
+		require.NoError(ap.Add(ctx, selp))
+		blk, err := bc.MintNewBlock(testutil.TimestampNow())
+		require.NoError(err)
+		require.NoError(bc.CommitBlock(blk))
+	}
+
+	gs := NewGasStation(bc, nil, blkMemDao, cfg.API)
+	history, err := gs.FeeHistory(3, 5, []float64{0, 50, 100})
+	require.NoError(err)
+	require.Equal(uint64(3), history.OldestBlock)
+	require.Len(history.BaseFeePerGas, 4)
+	for _, baseFee := range history.BaseFeePerGas {
+		require.Equal(big.NewInt(unit.Qev), baseFee)
+	}
+	require.Equal([]float64{0.1, 0.1, 0.1}, history.GasUsedRatio)
+	require.Len(history.Reward, 3)
+	for i, rewards := range history.Reward {
+		// the gas price above the default gas price of 1 Qev
+		reward := new(big.Int).Mul(big.NewInt(int64(i)+12), big.NewInt(unit.Qev))
+		require.Equal([]*big.Int{reward, reward, reward}, rewards)
+	}
+
+	// the history starts from block 1
+	history, err = gs.FeeHistory(10, 2, nil)
+	require.NoError(err)
+	require.Equal(uint64(1), history.OldestBlock)
+	require.Len(history.GasUsedRatio, 2)
+	require.Nil(history.Reward)
+
+	_, err = gs.FeeHistory(1, 6, nil)
+	require.Equal(protocol.ErrFutureHeight, errors.Cause(err))
+	_, err = gs.FeeHistory(1, 5, []float64{50, 10})
+	require.Equal(protocol.ErrInvalidArgument, errors.Cause(err))
+	_, err = gs.FeeHistory(1, 5, []float64{101})
+	require.Equal(protocol.ErrInvalidArgument, errors.Cause(err))
+}
+
+func TestRewardsAtPercentiles(t *testing.T) {
+	require := require.New(t)
+
+	require.Equal([]*big.Int{big.NewInt(0), big.NewInt(0)}, rewardsAtPercentiles(nil, 0, []float64{10, 90}))
+	rewards := []actionReward{
+		{reward: big.NewInt(1), gasConsumed: 10000},
+		{reward: big.NewInt(2), gasConsumed: 30000},
+		{reward: big.NewInt(3), gasConsumed: 60000},
+	}
+	require.Equal(
+		[]*big.Int{big.NewInt(1), big.NewInt(1), big.NewInt(2), big.NewInt(3), big.NewInt(3)},
+		rewardsAtPercentiles(rewards, 100000, []float64{0, 10, 40, 41, 100}),
+	)
+}
@@ -26,6 +26,7 @@ import (
 type BlockDAO interface {
 	GetBlockHash(uint64) (hash.Hash256, error)
 	GetBlockByHeight(uint64) (*block.Block, error)
+	GetReceipts(uint64) ([]*action.Receipt, error)
 }
 
 // SimulateFunc is function that simulate execution
@@ -0,0 +1,127 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package govreport
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/iotexproject/go-pkgs/crypto"
+	"github.com/iotexproject/go-pkgs/hash"
+	"github.com/iotexproject/iotex-address/address"
+
+	"github.com/iotexproject/iotex-core/epochevent"
+)
+
+// ErrInvalidSignature indicates the signature of the report does not match the report or the public key
+var ErrInvalidSignature = errors.New("invalid report signature")
+
+type (
+	// Report is the governance report of an epoch
+	Report struct {
+		EpochNumber uint64    `json:"epochNumber"`
+		StartHeight uint64    `json:"startHeight"`
+		EndHeight   uint64    `json:"endHeight"`
+		Timestamp   time.Time `json:"timestamp"`
+		// Delegates are the delegates elected as block producers of the epoch
+		Delegates []*epochevent.Candidate `json:"delegates"`
+		// ActiveDelegates are the addresses of the delegates actually producing blocks in the epoch
+		ActiveDelegates []string `json:"activeDelegates"`
+		// KickoutList is the probation list of the epoch, empty before the probation is activated
+		KickoutList *epochevent.ProbationList `json:"kickoutList,omitempty"`
+		// Rewards are the epoch reward and foundation bonus granted in the last block of the epoch
+		Rewards *epochevent.RewardSummary `json:"rewards,omitempty"`
+		// Parameters are the governance parameters in effect at the end of the epoch
+		Parameters Parameters `json:"parameters"`
+	}
+
+	// Parameters are the governance parameters of the chain
+	Parameters struct {
+		NumDelegates               uint64 `json:"numDelegates"`
+		NumCandidateDelegates      uint64 `json:"numCandidateDelegates"`
+		NumSubEpochs               uint64 `json:"numSubEpochs"`
+		NumDelegatesForEpochReward uint64 `json:"numDelegatesForEpochReward"`
+		ProductivityThreshold      uint64 `json:"productivityThreshold"`
+		ProbationEpochPeriod       uint64 `json:"probationEpochPeriod"`
+		ProbationIntensityRate     uint32 `json:"probationIntensityRate"`
+		// EpochReward and FoundationBonus are read from the rewarding protocol, empty if it is not registered
+		EpochReward     string `json:"epochReward,omitempty"`
+		FoundationBonus string `json:"foundationBonus,omitempty"`
+	}
+
+	// SignedReport is the report signed by the key of the node writing it
+	SignedReport struct {
+		Report    *Report `json:"report"`
+		Signer    string  `json:"signer"`
+		PublicKey string  `json:"publicKey"`
+		Signature string  `json:"signature"`
+	}
+)
+
+// Hash returns the hash of the json encoding of the report, which is signed
+func (r *Report) Hash() (hash.Hash256, error) {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return hash.ZeroHash256, err
+	}
+	return hash.Hash256b(data), nil
+}
+
+// Sign signs the report with the private key
+func Sign(r *Report, sk crypto.PrivateKey) (*SignedReport, error) {
+	h, err := r.Hash()
+	if err != nil {
+		return nil, err
+	}
+	sig, err := sk.Sign(h[:])
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to sign report")
+	}
+	signer, err := address.FromBytes(sk.PublicKey().Hash())
+	if err != nil {
+		return nil, err
+	}
+	return &SignedReport{
+		Report:    r,
+		Signer:    signer.String(),
+		PublicKey: sk.PublicKey().HexString(),
+		Signature: hex.EncodeToString(sig),
+	}, nil
+}
+
+// Verify verifies the signature of the report, and that the public key belongs to the signer
+func Verify(sr *SignedReport) error {
+	if sr.Report == nil {
+		return errors.Wrap(ErrInvalidSignature, "empty report")
+	}
+	pk, err := crypto.HexStringToPublicKey(sr.PublicKey)
+	if err != nil {
+		return errors.Wrap(ErrInvalidSignature, err.Error())
+	}
+	addr, err := address.FromBytes(pk.Hash())
+	if err != nil {
+		return err
+	}
+	if addr.String() != sr.Signer {
+		return errors.Wrapf(ErrInvalidSignature, "public key of %s does not belong to signer %s", addr, sr.Signer)
+	}
+	sig, err := hex.DecodeString(sr.Signature)
+	if err != nil {
+		return errors.Wrap(ErrInvalidSignature, err.Error())
+	}
+	h, err := sr.Report.Hash()
+	if err != nil {
+		return err
+	}
+	if !pk.Verify(h[:], sig) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
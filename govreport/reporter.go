@@ -0,0 +1,218 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+// Package govreport writes a signed governance report of each epoch, including the elected delegates, the kickout
+// list, the distributed rewards and the governance parameters, to a local directory or an object storage at the end
+// of the epoch, building an auditable governance archive.
+package govreport
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/iotexproject/go-pkgs/crypto"
+
+	"github.com/iotexproject/iotex-core/action"
+	"github.com/iotexproject/iotex-core/action/protocol"
+	"github.com/iotexproject/iotex-core/action/protocol/poll"
+	"github.com/iotexproject/iotex-core/action/protocol/protocolctx"
+	"github.com/iotexproject/iotex-core/action/protocol/rewarding"
+	"github.com/iotexproject/iotex-core/action/protocol/rolldpos"
+	"github.com/iotexproject/iotex-core/action/protocol/vote"
+	"github.com/iotexproject/iotex-core/archive"
+	"github.com/iotexproject/iotex-core/blockchain/block"
+	"github.com/iotexproject/iotex-core/blockchain/genesis"
+	"github.com/iotexproject/iotex-core/epochevent"
+	"github.com/iotexproject/iotex-core/pkg/log"
+	"github.com/iotexproject/iotex-core/state"
+)
+
+type (
+	// BlockReader reads the headers and receipts of the committed blocks
+	BlockReader interface {
+		HeaderByHeight(uint64) (*block.Header, error)
+		GetReceipts(uint64) ([]*action.Receipt, error)
+	}
+
+	// Reporter writes the governance report on the last block of each epoch
+	Reporter struct {
+		genesis  genesis.Genesis
+		sr       protocol.StateReader
+		dao      BlockReader
+		registry *protocol.Registry
+		sk       crypto.PrivateKey
+		store    archive.ObjectStore
+	}
+)
+
+// NewReporter creates a reporter signing the reports with the private key and writing them to the store
+func NewReporter(
+	g genesis.Genesis,
+	sr protocol.StateReader,
+	dao BlockReader,
+	registry *protocol.Registry,
+	sk crypto.PrivateKey,
+	store archive.ObjectStore,
+) (*Reporter, error) {
+	if sk == nil {
+		return nil, errors.New("empty private key")
+	}
+	if store == nil {
+		return nil, errors.New("empty object store")
+	}
+	return &Reporter{
+		genesis:  g,
+		sr:       sr,
+		dao:      dao,
+		registry: registry,
+		sk:       sk,
+		store:    store,
+	}, nil
+}
+
+// ReportKey returns the key of the report of the epoch in the object store
+func ReportKey(epochNum uint64) string {
+	return fmt.Sprintf("epoch-%d.json", epochNum)
+}
+
+// ReceiveBlock writes the report of the epoch if the block is the last block of the epoch
+func (r *Reporter) ReceiveBlock(blk *block.Block) error {
+	rp := rolldpos.FindProtocol(r.registry)
+	if rp == nil {
+		return nil
+	}
+	height := blk.Height()
+	epochNum := rp.GetEpochNum(height)
+	if height != rp.GetEpochLastBlockHeight(epochNum) {
+		return nil
+	}
+	if _, err := r.Generate(context.Background(), epochNum); err != nil {
+		log.L().Error("Failed to write governance report.", zap.Uint64("epoch", epochNum), zap.Error(err))
+	}
+	return nil
+}
+
+// Generate generates, signs and writes the report of the epoch, the epoch should have ended
+func (r *Reporter) Generate(ctx context.Context, epochNum uint64) (*SignedReport, error) {
+	rp := rolldpos.FindProtocol(r.registry)
+	if rp == nil {
+		return nil, errors.New("rolldpos protocol is not registered")
+	}
+	pp := poll.FindProtocol(r.registry)
+	if pp == nil {
+		return nil, errors.New("poll protocol is not registered")
+	}
+	report := &Report{
+		EpochNumber: epochNum,
+		StartHeight: rp.GetEpochHeight(epochNum),
+		EndHeight:   rp.GetEpochLastBlockHeight(epochNum),
+		Parameters: Parameters{
+			NumDelegates:               rp.NumDelegates(),
+			NumCandidateDelegates:      rp.NumCandidateDelegates(),
+			NumDelegatesForEpochReward: r.genesis.NumDelegatesForEpochReward,
+			ProductivityThreshold:      r.genesis.ProductivityThreshold,
+			ProbationEpochPeriod:       r.genesis.ProbationEpochPeriod,
+			ProbationIntensityRate:     r.genesis.ProbationIntensityRate,
+		},
+	}
+	report.Parameters.NumSubEpochs = rp.NumSubEpochs(report.EndHeight)
+	header, err := r.dao.HeaderByHeight(report.EndHeight)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read block header of height %d", report.EndHeight)
+	}
+	report.Timestamp = header.Timestamp().UTC()
+
+	readCtx, err := protocolctx.NewBuilder().
+		SetRegistry(r.registry).
+		SetGenesis(r.genesis).
+		SetBlockHeight(report.EndHeight).
+		Build(ctx)
+	if err != nil {
+		return nil, err
+	}
+	epochArg := []byte(strconv.FormatUint(epochNum, 10))
+	data, _, err := pp.ReadState(readCtx, r.sr, []byte("BlockProducersByEpoch"), epochArg)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read block producers")
+	}
+	var producers state.CandidateList
+	if err := producers.Deserialize(data); err != nil {
+		return nil, err
+	}
+	report.Delegates = epochevent.NewCandidates(producers)
+
+	data, _, err = pp.ReadState(readCtx, r.sr, []byte("ActiveBlockProducersByEpoch"), epochArg)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read active block producers")
+	}
+	var activeProducers state.CandidateList
+	if err := activeProducers.Deserialize(data); err != nil {
+		return nil, err
+	}
+	report.ActiveDelegates = make([]string, 0, len(activeProducers))
+	for _, p := range activeProducers {
+		report.ActiveDelegates = append(report.ActiveDelegates, p.Address)
+	}
+
+	data, _, err = pp.ReadState(readCtx, r.sr, []byte("ProbationListByEpoch"), epochArg)
+	switch errors.Cause(err) {
+	case nil:
+		probationList := &vote.ProbationList{}
+		if err := probationList.Deserialize(data); err != nil {
+			return nil, err
+		}
+		report.KickoutList = epochevent.NewProbationList(probationList)
+	case protocol.ErrPreActivation, protocol.ErrNotFound:
+	default:
+		return nil, errors.Wrap(err, "failed to read kickout list")
+	}
+
+	if rwp := rewarding.FindProtocol(r.registry); rwp != nil {
+		epochReward, err := rwp.EpochReward(readCtx, r.sr)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read epoch reward")
+		}
+		foundationBonus, err := rwp.FoundationBonus(readCtx, r.sr)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read foundation bonus")
+		}
+		report.Parameters.EpochReward = epochReward.String()
+		report.Parameters.FoundationBonus = foundationBonus.String()
+	}
+
+	// epoch reward is granted in the last block of the epoch
+	receipts, err := r.dao.GetReceipts(report.EndHeight)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read receipts")
+	}
+	if report.Rewards, err = epochevent.NewRewardSummary(epochNum, receipts); err != nil {
+		return nil, err
+	}
+
+	signed, err := Sign(report, r.sk)
+	if err != nil {
+		return nil, err
+	}
+	data, err = json.MarshalIndent(signed, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := r.store.Put(ctx, ReportKey(epochNum), bytes.NewReader(data), int64(len(data))); err != nil {
+		return nil, errors.Wrap(err, "failed to write report")
+	}
+	log.L().Info(
+		"Wrote governance report.",
+		zap.Uint64("epoch", epochNum),
+		zap.Int("delegates", len(report.Delegates)),
+	)
+	return signed, nil
+}
@@ -0,0 +1,168 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package govreport
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/golang/protobuf/proto"
+	"github.com/iotexproject/iotex-address/address"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/action"
+	"github.com/iotexproject/iotex-core/action/protocol"
+	"github.com/iotexproject/iotex-core/action/protocol/poll"
+	"github.com/iotexproject/iotex-core/action/protocol/rewarding/rewardingpb"
+	"github.com/iotexproject/iotex-core/action/protocol/rolldpos"
+	"github.com/iotexproject/iotex-core/archive"
+	"github.com/iotexproject/iotex-core/blockchain/block"
+	"github.com/iotexproject/iotex-core/blockchain/genesis"
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/db"
+	"github.com/iotexproject/iotex-core/test/identityset"
+	"github.com/iotexproject/iotex-core/test/mock/mock_chainmanager"
+)
+
+type fakeChain struct {
+	blocks   map[uint64]*block.Block
+	receipts map[uint64][]*action.Receipt
+}
+
+func (c *fakeChain) HeaderByHeight(height uint64) (*block.Header, error) {
+	blk, ok := c.blocks[height]
+	if !ok {
+		return nil, db.ErrNotExist
+	}
+	return &blk.Header, nil
+}
+
+func (c *fakeChain) GetReceipts(height uint64) ([]*action.Receipt, error) {
+	if _, ok := c.blocks[height]; !ok {
+		return nil, db.ErrNotExist
+	}
+	return c.receipts[height], nil
+}
+
+func TestSignAndVerify(t *testing.T) {
+	require := require.New(t)
+
+	report := &Report{EpochNumber: 3, ActiveDelegates: []string{identityset.Address(1).String()}}
+	signed, err := Sign(report, identityset.PrivateKey(0))
+	require.NoError(err)
+	require.Equal(identityset.Address(0).String(), signed.Signer)
+	require.NoError(Verify(signed))
+
+	data, err := json.Marshal(signed)
+	require.NoError(err)
+	decoded := &SignedReport{}
+	require.NoError(json.Unmarshal(data, decoded))
+	require.NoError(Verify(decoded))
+
+	decoded.Report.EpochNumber = 4
+	require.Equal(ErrInvalidSignature, errors.Cause(Verify(decoded)))
+	decoded.Report.EpochNumber = 3
+	decoded.Signer = identityset.Address(1).String()
+	require.Equal(ErrInvalidSignature, errors.Cause(Verify(decoded)))
+}
+
+func TestReporter(t *testing.T) {
+	require := require.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var delegates []genesis.Delegate
+	for i := 0; i < 4; i++ {
+		delegates = append(delegates, genesis.Delegate{
+			OperatorAddrStr: identityset.Address(i).String(),
+			RewardAddrStr:   identityset.Address(i).String(),
+			VotesStr:        "10",
+		})
+	}
+	registry := protocol.NewRegistry()
+	require.NoError(rolldpos.NewProtocol(4, 4, 2).Register(registry))
+	require.NoError(poll.NewLifeLongDelegatesProtocol(delegates).Register(registry))
+	sr := mock_chainmanager.NewMockStateReader(ctrl)
+	sr.EXPECT().Height().Return(uint64(8), nil).AnyTimes()
+
+	rewardingAddr, err := address.FromBytes(address.RewardingProtocolAddrHash[:])
+	require.NoError(err)
+	rewardLog, err := proto.Marshal(&rewardingpb.RewardLog{
+		Type:   rewardingpb.RewardLog_EPOCH_REWARD,
+		Addr:   identityset.Address(2).String(),
+		Amount: "100",
+	})
+	require.NoError(err)
+	chain := &fakeChain{
+		blocks: make(map[uint64]*block.Block),
+		receipts: map[uint64][]*action.Receipt{
+			8: {(&action.Receipt{}).AddLogs(&action.Log{Address: rewardingAddr.String(), Data: rewardLog})},
+		},
+	}
+	ts := time.Unix(1612345678, 0)
+	for height := uint64(1); height <= 8; height++ {
+		blk, err := block.NewTestingBuilder().
+			SetHeight(height).
+			SetTimeStamp(ts.Add(time.Duration(height) * 5 * time.Second)).
+			SignAndBuild(identityset.PrivateKey(int(height % 4)))
+		require.NoError(err)
+		chain.blocks[height] = &blk
+	}
+
+	dir, err := ioutil.TempDir("", "govreport")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+	store := archive.NewFileStore(dir)
+	g := config.Default.Genesis
+	r, err := NewReporter(g, sr, chain, registry, identityset.PrivateKey(0), store)
+	require.NoError(err)
+	ctx := context.Background()
+
+	// not the last block of the epoch
+	require.NoError(r.ReceiveBlock(chain.blocks[7]))
+	_, err = store.Get(ctx, ReportKey(1))
+	require.Equal(archive.ErrObjectNotExist, errors.Cause(err))
+
+	require.NoError(r.ReceiveBlock(chain.blocks[8]))
+	rc, err := store.Get(ctx, ReportKey(1))
+	require.NoError(err)
+	defer rc.Close()
+	signed := &SignedReport{}
+	require.NoError(json.NewDecoder(rc).Decode(signed))
+	require.NoError(Verify(signed))
+
+	report := signed.Report
+	require.Equal(uint64(1), report.EpochNumber)
+	require.Equal(uint64(1), report.StartHeight)
+	require.Equal(uint64(8), report.EndHeight)
+	require.True(ts.Add(40 * time.Second).Equal(report.Timestamp))
+	require.Equal(4, len(report.Delegates))
+	require.Equal(identityset.Address(0).String(), report.Delegates[0].Address)
+	require.Equal(4, len(report.ActiveDelegates))
+	require.Nil(report.KickoutList)
+	require.Equal("100", report.Rewards.TotalEpochReward)
+	require.Equal(1, len(report.Rewards.Distributions))
+	require.Equal(Parameters{
+		NumDelegates:               4,
+		NumCandidateDelegates:      4,
+		NumSubEpochs:               2,
+		NumDelegatesForEpochReward: g.NumDelegatesForEpochReward,
+		ProductivityThreshold:      g.ProductivityThreshold,
+		ProbationEpochPeriod:       g.ProbationEpochPeriod,
+		ProbationIntensityRate:     g.ProbationIntensityRate,
+	}, report.Parameters)
+
+	// missing blocks
+	_, err = r.Generate(ctx, 2)
+	require.Error(err)
+}
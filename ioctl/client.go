@@ -192,6 +192,7 @@ func (c *client) GetAliasMap() map[string]string {
 }
 
 func (c *client) WriteConfig(cfg config.Config) error {
+	cfg.SaveProfile()
 	out, err := yaml.Marshal(&cfg)
 	if err != nil {
 		return output.NewError(output.SerializationError, "failed to marshal config", err)
@@ -63,29 +63,37 @@ func accountCreateAdd(args []string) error {
 		}
 	}
 
+	if _, err := CreateAccount(alias); err != nil {
+		return err
+	}
+	output.PrintResult(fmt.Sprintf("New account \"%s\" is created.\n"+
+		"Please Keep your password, or you will lose your private key.", alias))
+	return nil
+}
+
+// CreateAccount creates a new account with the password read from stdin, binds the alias to it and returns its address
+func CreateAccount(alias string) (string, error) {
 	var addr string
 	var err error
 	if CryptoSm2 {
 		addr, err = newAccountSm2(alias)
 		if err != nil {
-			return output.NewError(0, "", err)
+			return "", output.NewError(0, "", err)
 		}
 	} else {
 		addr, err = newAccount(alias)
 		if err != nil {
-			return output.NewError(0, "", err)
+			return "", output.NewError(0, "", err)
 		}
 	}
 	config.ReadConfig.Aliases[alias] = addr
 	out, err := yaml.Marshal(&config.ReadConfig)
 	if err != nil {
-		return output.NewError(output.SerializationError, "failed to marshal config", err)
+		return "", output.NewError(output.SerializationError, "failed to marshal config", err)
 	}
 	if err := ioutil.WriteFile(config.DefaultConfigFile, out, 0600); err != nil {
-		return output.NewError(output.WriteFileError,
+		return "", output.NewError(output.WriteFileError,
 			fmt.Sprintf("failed to write to config file %s", config.DefaultConfigFile), err)
 	}
-	output.PrintResult(fmt.Sprintf("New account \"%s\" is created.\n"+
-		"Please Keep your password, or you will lose your private key.", alias))
-	return nil
+	return addr, nil
 }
@@ -98,6 +98,7 @@ func init() {
 	ActionCmd.AddCommand(actionClaimCmd)
 	ActionCmd.AddCommand(actionDepositCmd)
 	ActionCmd.AddCommand(actionSendRawCmd)
+	ActionCmd.AddCommand(actionDecodeCmd)
 	ActionCmd.PersistentFlags().StringVar(&config.ReadConfig.Endpoint, "endpoint",
 		config.ReadConfig.Endpoint, config.TranslateInLang(flagActionEndPointUsages,
 			config.UILanguage))
@@ -231,6 +232,7 @@ func SendRaw(selp *iotextypes.Action) error {
 	if err == nil {
 		ctx = metautils.NiceMD(jwtMD).ToOutgoing(ctx)
 	}
+	ctx = util.WithChainID(ctx)
 
 	request := &iotexapi.SendActionRequest{Action: selp}
 	if _, err = cli.SendAction(ctx, request); err != nil {
@@ -307,6 +309,13 @@ func PrivateKeyFromSigner(signer string) (crypto.PrivateKey, error) {
 
 // SendAction sends signed action to blockchain
 func SendAction(elp action.Envelope, signer string) error {
+	// warn about unusual fields before signing
+	if message, err := decodeAction(&iotextypes.Action{Core: elp.Proto()}, &xrc20ABI); err == nil {
+		for _, w := range message.Warnings {
+			fmt.Println(output.StringMessage(w).Warn())
+		}
+	}
+
 	prvKey, err := PrivateKeyFromSigner(signer)
 	if err != nil {
 		return err
@@ -0,0 +1,443 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package action
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"reflect"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/golang/protobuf/proto"
+	"github.com/grpc-ecosystem/go-grpc-middleware/util/metautils"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/status"
+
+	"github.com/iotexproject/go-pkgs/crypto"
+	"github.com/iotexproject/iotex-address/address"
+	"github.com/iotexproject/iotex-proto/golang/iotexapi"
+	"github.com/iotexproject/iotex-proto/golang/iotextypes"
+
+	"github.com/iotexproject/iotex-core/action"
+	"github.com/iotexproject/iotex-core/ioctl/config"
+	"github.com/iotexproject/iotex-core/ioctl/flag"
+	"github.com/iotexproject/iotex-core/ioctl/output"
+	"github.com/iotexproject/iotex-core/ioctl/util"
+)
+
+// Multi-language support
+var (
+	decodeCmdShorts = map[config.Language]string{
+		config.English: "Decode an action into human-readable form",
+		config.Chinese: "将行为解码为可读形式",
+	}
+	decodeCmdUses = map[config.Language]string{
+		config.English: "decode (DATA|ACTION_HASH) [--abi ABI_FILE]",
+		config.Chinese: "decode (数据|行动_哈希) [--abi ABI文件]",
+	}
+)
+
+const (
+	// _unusualGasPriceMultiple is the multiple of the default gas price, above which the gas price is unusual
+	_unusualGasPriceMultiple = 100
+	// _unusualFee is the max fee in rau, above which the fee is unusual
+	_unusualFee = "10000000000000000000"
+)
+
+var abiFlag = flag.NewStringVar("abi", "", "set the abi file to decode execution data")
+
+// actionDecodeCmd represents the action decode command
+var actionDecodeCmd = &cobra.Command{
+	Use:   config.TranslateInLang(decodeCmdUses, config.UILanguage),
+	Short: config.TranslateInLang(decodeCmdShorts, config.UILanguage),
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		err := decode(args[0])
+		return output.PrintError(err)
+	},
+}
+
+type (
+	decodedField struct {
+		Name  string `json:"name"`
+		Value string `json:"value"`
+	}
+
+	decodeMessage struct {
+		Type      string         `json:"type"`
+		Hash      string         `json:"hash,omitempty"`
+		Sender    string         `json:"sender,omitempty"`
+		Signed    bool           `json:"signed"`
+		Version   uint32         `json:"version"`
+		Nonce     uint64         `json:"nonce"`
+		GasLimit  uint64         `json:"gasLimit"`
+		GasPrice  string         `json:"gasPrice"`
+		MaxFee    string         `json:"maxFee"`
+		Fields    []decodedField `json:"fields"`
+		Method    string         `json:"method,omitempty"`
+		Arguments []decodedField `json:"arguments,omitempty"`
+		Warnings  []string       `json:"warnings,omitempty"`
+	}
+)
+
+func init() {
+	abiFlag.RegisterCommand(actionDecodeCmd)
+}
+
+func (m *decodeMessage) String() string {
+	if output.Format == "" {
+		lines := []string{fmt.Sprintf("type: %s", m.Type)}
+		if m.Hash != "" {
+			lines = append(lines, fmt.Sprintf("hash: %s", m.Hash))
+		}
+		if m.Sender != "" {
+			lines = append(lines, fmt.Sprintf("sender: %s %s", m.Sender, Match(m.Sender, "address")))
+		}
+		lines = append(lines,
+			fmt.Sprintf("signed: %t", m.Signed),
+			fmt.Sprintf("version: %d  nonce: %d  gasLimit: %d  gasPrice: %s IOTX  maxFee: %s IOTX",
+				m.Version, m.Nonce, m.GasLimit, m.GasPrice, m.MaxFee))
+		for _, f := range m.Fields {
+			lines = append(lines, fmt.Sprintf("%s: %s", f.Name, f.Value))
+		}
+		if m.Method != "" {
+			lines = append(lines, fmt.Sprintf("method: %s", m.Method))
+			for _, arg := range m.Arguments {
+				lines = append(lines, fmt.Sprintf("  %s: %s", arg.Name, arg.Value))
+			}
+		}
+		for _, w := range m.Warnings {
+			lines = append(lines, output.StringMessage(w).Warn())
+		}
+		return strings.Join(lines, "\n")
+	}
+	return output.FormatString(output.Result, m)
+}
+
+func decode(arg string) error {
+	var abis []*abi.ABI
+	if abiFile := abiFlag.Value().(string); abiFile != "" {
+		abiBytes, err := ioutil.ReadFile(abiFile)
+		if err != nil {
+			return output.NewError(output.ReadFileError, "failed to read abi file", err)
+		}
+		parsed, err := abi.JSON(strings.NewReader(string(abiBytes)))
+		if err != nil {
+			return output.NewError(output.SerializationError, "failed to unmarshal abi", err)
+		}
+		abis = append(abis, &parsed)
+	}
+	abis = append(abis, &xrc20ABI)
+
+	data, err := hex.DecodeString(util.TrimHexPrefix(arg))
+	if err != nil {
+		return output.NewError(output.ConvertError, "failed to decode data", err)
+	}
+	var selp *iotextypes.Action
+	if len(data) == 32 {
+		if selp, err = actionByHash(hex.EncodeToString(data)); err != nil {
+			return err
+		}
+	} else if selp, err = unmarshalAction(data); err != nil {
+		return err
+	}
+	message, err := decodeAction(selp, abis...)
+	if err != nil {
+		return err
+	}
+	fmt.Println(message.String())
+	return nil
+}
+
+// unmarshalAction unmarshals a signed action, or an action core to be signed
+func unmarshalAction(data []byte) (*iotextypes.Action, error) {
+	selp := &iotextypes.Action{}
+	if err := proto.Unmarshal(data, selp); err == nil && selp.Core != nil {
+		return selp, nil
+	}
+	core := &iotextypes.ActionCore{}
+	if err := proto.Unmarshal(data, core); err != nil {
+		return nil, output.NewError(output.SerializationError, "failed to unmarshal data bytes", err)
+	}
+	return &iotextypes.Action{Core: core}, nil
+}
+
+func actionByHash(hash string) (*iotextypes.Action, error) {
+	conn, err := util.ConnectToEndpoint(config.ReadConfig.SecureConnect && !config.Insecure)
+	if err != nil {
+		return nil, output.NewError(output.NetworkError, "failed to connect to endpoint", err)
+	}
+	defer conn.Close()
+	cli := iotexapi.NewAPIServiceClient(conn)
+	ctx := context.Background()
+
+	jwtMD, err := util.JwtAuth()
+	if err == nil {
+		ctx = metautils.NiceMD(jwtMD).ToOutgoing(ctx)
+	}
+
+	response, err := cli.GetActions(ctx, &iotexapi.GetActionsRequest{
+		Lookup: &iotexapi.GetActionsRequest_ByHash{
+			ByHash: &iotexapi.GetActionByHashRequest{
+				ActionHash:   hash,
+				CheckPending: true,
+			},
+		},
+	})
+	if err != nil {
+		sta, ok := status.FromError(err)
+		if ok {
+			return nil, output.NewError(output.APIError, sta.Message(), nil)
+		}
+		return nil, output.NewError(output.NetworkError, "failed to invoke GetActions api", err)
+	}
+	if len(response.ActionInfo) == 0 {
+		return nil, output.NewError(output.APIError, "no action info returned", nil)
+	}
+	return response.ActionInfo[0].Action, nil
+}
+
+// decodeAction decodes the action with the abis to decode execution data, and warns about unusual fields
+func decodeAction(selp *iotextypes.Action, abis ...*abi.ABI) (*decodeMessage, error) {
+	elp := action.Envelope{}
+	if err := elp.LoadProto(selp.GetCore()); err != nil {
+		return nil, output.NewError(output.SerializationError, "failed to load action", err)
+	}
+	message := &decodeMessage{
+		Version:  elp.Version(),
+		Nonce:    elp.Nonce(),
+		GasLimit: elp.GasLimit(),
+		GasPrice: util.RauToString(elp.GasPrice(), util.IotxDecimalNum),
+		MaxFee:   util.RauToString(new(big.Int).Mul(elp.GasPrice(), new(big.Int).SetUint64(elp.GasLimit())), util.IotxDecimalNum),
+	}
+	var sender string
+	if len(selp.SenderPubKey) > 0 {
+		pubKey, err := crypto.BytesToPublicKey(selp.SenderPubKey)
+		if err != nil {
+			return nil, output.NewError(output.ConvertError, "failed to convert public key from bytes", err)
+		}
+		addr, err := address.FromBytes(pubKey.Hash())
+		if err != nil {
+			return nil, output.NewError(output.ConvertError, "failed to convert bytes into address", err)
+		}
+		sender = addr.String()
+		message.Sender = sender
+		message.Signed = len(selp.Signature) > 0
+		sealed := action.AssembleSealedEnvelope(elp, pubKey, selp.Signature)
+		h := sealed.Hash()
+		message.Hash = hex.EncodeToString(h[:])
+		if message.Signed {
+			if err := action.Verify(sealed); err != nil {
+				message.Warnings = append(message.Warnings, "signature does not match the sender")
+			}
+		}
+	}
+	message.Warnings = append(message.Warnings, envelopeWarnings(elp)...)
+
+	addField := func(name string, value interface{}) {
+		message.Fields = append(message.Fields, decodedField{Name: name, Value: fmt.Sprint(value)})
+	}
+	addAddress := func(name, addr string) {
+		addField(name, strings.TrimSpace(addr+" "+Match(addr, "address")))
+	}
+	addAmount := func(name string, amount *big.Int) {
+		addField(name, util.RauToString(amount, util.IotxDecimalNum)+" IOTX")
+	}
+	addPayload := func(payload []byte) {
+		if len(payload) > 0 {
+			addField("payload", fmt.Sprintf("%x", payload))
+		}
+	}
+	switch act := elp.Action().(type) {
+	case *action.Transfer:
+		message.Type = "transfer"
+		addAddress("recipient", act.Recipient())
+		addAmount("amount", act.Amount())
+		addPayload(act.Payload())
+		if act.Amount().Sign() == 0 && len(act.Payload()) == 0 {
+			message.Warnings = append(message.Warnings, "transfer of zero amount without payload")
+		}
+		if act.Recipient() == sender {
+			message.Warnings = append(message.Warnings, "transfer to the sender itself")
+		}
+	case *action.Execution:
+		if act.Contract() == action.EmptyAddress {
+			message.Type = "deployment"
+			addField("bytecodeSize", len(act.Data()))
+		} else {
+			message.Type = "execution"
+			addAddress("contract", act.Contract())
+		}
+		addAmount("amount", act.Amount())
+		if act.Contract() != action.EmptyAddress {
+			decodeCalldata(message, act.Data(), abis)
+		}
+	case *action.CreateStake:
+		message.Type = "stakeCreate"
+		addField("candidate", act.Candidate())
+		addAmount("amount", act.Amount())
+		addField("duration", fmt.Sprintf("%d days", act.Duration()))
+		addField("autoStake", act.AutoStake())
+		addPayload(act.Payload())
+	case *action.DepositToStake:
+		message.Type = "stakeAddDeposit"
+		addField("bucketIndex", act.BucketIndex())
+		addAmount("amount", act.Amount())
+		addPayload(act.Payload())
+	case *action.Unstake:
+		message.Type = "stakeUnstake"
+		addField("bucketIndex", act.BucketIndex())
+		addPayload(act.Payload())
+	case *action.WithdrawStake:
+		message.Type = "stakeWithdraw"
+		addField("bucketIndex", act.BucketIndex())
+		addPayload(act.Payload())
+	case *action.Restake:
+		message.Type = "stakeRestake"
+		addField("bucketIndex", act.BucketIndex())
+		addField("duration", fmt.Sprintf("%d days", act.Duration()))
+		addField("autoStake", act.AutoStake())
+		addPayload(act.Payload())
+	case *action.ChangeCandidate:
+		message.Type = "stakeChangeCandidate"
+		addField("bucketIndex", act.BucketIndex())
+		addField("candidate", act.Candidate())
+		addPayload(act.Payload())
+	case *action.TransferStake:
+		message.Type = "stakeTransferOwnership"
+		addField("bucketIndex", act.BucketIndex())
+		addAddress("newOwner", act.VoterAddress().String())
+		addPayload(act.Payload())
+		message.Warnings = append(message.Warnings, "the ownership of the bucket is transferred and can't be taken back")
+	case *action.CandidateRegister:
+		message.Type = "candidateRegister"
+		addField("name", act.Name())
+		addAddress("operatorAddress", act.OperatorAddress().String())
+		addAddress("rewardAddress", act.RewardAddress().String())
+		if act.OwnerAddress() != nil {
+			addAddress("ownerAddress", act.OwnerAddress().String())
+		}
+		addAmount("amount", act.Amount())
+		addField("duration", fmt.Sprintf("%d days", act.Duration()))
+		addField("autoStake", act.AutoStake())
+		addPayload(act.Payload())
+	case *action.CandidateUpdate:
+		message.Type = "candidateUpdate"
+		addField("name", act.Name())
+		if act.OperatorAddress() != nil {
+			addAddress("operatorAddress", act.OperatorAddress().String())
+		}
+		if act.RewardAddress() != nil {
+			addAddress("rewardAddress", act.RewardAddress().String())
+		}
+	case *action.PutPollResult:
+		message.Type = "putPollResult"
+		addField("height", act.Height())
+		for _, cand := range act.Candidates() {
+			addField("candidate", fmt.Sprintf("%s votes: %s reward address: %s", cand.Address, cand.Votes, cand.RewardAddress))
+		}
+		message.Warnings = append(message.Warnings, "system action, only accepted from block producers")
+	case *action.GrantReward:
+		message.Type = "grantReward"
+		rewardType := "block"
+		if act.RewardType() == action.EpochReward {
+			rewardType = "epoch"
+		}
+		addField("rewardType", rewardType)
+		addField("height", act.Height())
+		message.Warnings = append(message.Warnings, "system action, only accepted from block producers")
+	case *action.ClaimFromRewardingFund:
+		message.Type = "claimReward"
+		addAmount("amount", act.Amount())
+		addPayload(act.Data())
+	case *action.DepositToRewardingFund:
+		message.Type = "depositReward"
+		addAmount("amount", act.Amount())
+		addPayload(act.Data())
+		message.Warnings = append(message.Warnings, "the deposit goes to the rewarding fund and can't be withdrawn")
+	default:
+		message.Type = fmt.Sprintf("%T", act)
+	}
+	return message, nil
+}
+
+func envelopeWarnings(elp action.Envelope) []string {
+	var warnings []string
+	if elp.GasPrice().Sign() == 0 {
+		warnings = append(warnings, "zero gas price, the action is likely to be rejected")
+	} else if elp.GasPrice().Cmp(new(big.Int).Mul(defaultGasPrice, big.NewInt(_unusualGasPriceMultiple))) > 0 {
+		warnings = append(warnings, fmt.Sprintf("gas price is over %d times of the default", _unusualGasPriceMultiple))
+	}
+	if intrinsicGas, err := elp.IntrinsicGas(); err == nil && elp.GasLimit() < intrinsicGas {
+		warnings = append(warnings, fmt.Sprintf("gas limit is lower than the intrinsic gas %d", intrinsicGas))
+	}
+	unusualFee, _ := new(big.Int).SetString(_unusualFee, 10)
+	if fee := new(big.Int).Mul(elp.GasPrice(), new(big.Int).SetUint64(elp.GasLimit())); fee.Cmp(unusualFee) > 0 {
+		warnings = append(warnings, fmt.Sprintf("max fee is over %s IOTX", util.RauToString(unusualFee, util.IotxDecimalNum)))
+	}
+	return warnings
+}
+
+// decodeCalldata decodes the execution data with the first abi having the method
+func decodeCalldata(message *decodeMessage, data []byte, abis []*abi.ABI) {
+	if len(data) == 0 {
+		return
+	}
+	if len(data) < 4 {
+		message.Fields = append(message.Fields, decodedField{Name: "data", Value: hex.EncodeToString(data)})
+		return
+	}
+	for _, a := range abis {
+		method, err := a.MethodById(data[:4])
+		if err != nil {
+			continue
+		}
+		values, err := method.Inputs.UnpackValues(data[4:])
+		if err != nil {
+			continue
+		}
+		message.Method = method.Sig()
+		for i, input := range method.Inputs {
+			message.Arguments = append(message.Arguments, decodedField{Name: input.Name, Value: formatArgument(values[i])})
+		}
+		if method.Name == "approve" && len(values) == 2 {
+			if amount, ok := values[1].(*big.Int); ok && amount.Cmp(math.MaxBig256) == 0 {
+				message.Warnings = append(message.Warnings, "unlimited approval, the spender can transfer all of the tokens")
+			}
+		}
+		return
+	}
+	message.Fields = append(message.Fields, decodedField{Name: "data", Value: hex.EncodeToString(data)})
+	message.Warnings = append(message.Warnings, "execution data can't be decoded without the abi of the contract")
+}
+
+func formatArgument(v interface{}) string {
+	switch value := v.(type) {
+	case common.Address:
+		addr, err := address.FromBytes(value.Bytes())
+		if err != nil {
+			return value.Hex()
+		}
+		return strings.TrimSpace(addr.String() + " " + Match(addr.String(), "address"))
+	case []byte:
+		return "0x" + hex.EncodeToString(value)
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Array && rv.Type().Elem().Kind() == reflect.Uint8 {
+		b := make([]byte, rv.Len())
+		reflect.Copy(reflect.ValueOf(b), rv)
+		return "0x" + hex.EncodeToString(b)
+	}
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,91 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package action
+
+import (
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/action"
+	"github.com/iotexproject/iotex-core/test/identityset"
+)
+
+func TestDecodeAction(t *testing.T) {
+	require := require.New(t)
+
+	sign := func(act *action.Transfer, gasLimit uint64, gasPrice *big.Int) *action.SealedEnvelope {
+		elp := (&action.EnvelopeBuilder{}).SetNonce(1).SetGasLimit(gasLimit).SetGasPrice(gasPrice).SetAction(act).Build()
+		selp, err := action.Sign(elp, identityset.PrivateKey(27))
+		require.NoError(err)
+		return &selp
+	}
+
+	// transfer
+	tsf, err := action.NewTransfer(1, big.NewInt(1000), identityset.Address(28).String(), nil, 10000, defaultGasPrice)
+	require.NoError(err)
+	selp := sign(tsf, 10000, defaultGasPrice)
+	data, err := proto.Marshal(selp.Proto())
+	require.NoError(err)
+	pb, err := unmarshalAction(data)
+	require.NoError(err)
+	message, err := decodeAction(pb)
+	require.NoError(err)
+	require.Equal("transfer", message.Type)
+	require.Equal(identityset.Address(27).String(), message.Sender)
+	require.True(message.Signed)
+	h := selp.Hash()
+	require.Equal(hex.EncodeToString(h[:]), message.Hash)
+	require.Equal("0.01", message.MaxFee)
+	require.Equal(decodedField{Name: "recipient", Value: identityset.Address(28).String()}, message.Fields[0])
+	require.Equal(decodedField{Name: "amount", Value: "0.000000000000001 IOTX"}, message.Fields[1])
+	require.Empty(message.Warnings)
+
+	// tampered signature and unusual fields
+	pb = selp.Proto()
+	pb.Signature[0]++
+	pb.Core.GasPrice = "0"
+	pb.Core.GasLimit = 100
+	message, err = decodeAction(pb)
+	require.NoError(err)
+	require.Equal([]string{
+		"signature does not match the sender",
+		"zero gas price, the action is likely to be rejected",
+		"gas limit is lower than the intrinsic gas 10000",
+	}, message.Warnings)
+
+	// unsigned execution with xrc20 calldata
+	calldata, err := xrc20ABI.Pack("approve", common.BytesToAddress(identityset.Address(28).Bytes()), math.MaxBig256)
+	require.NoError(err)
+	exec, err := action.NewExecution(identityset.Address(29).String(), 1, big.NewInt(0), 100000, defaultGasPrice, calldata)
+	require.NoError(err)
+	elp := (&action.EnvelopeBuilder{}).SetNonce(1).SetGasLimit(100000).SetGasPrice(defaultGasPrice).SetAction(exec).Build()
+	data, err = proto.Marshal(elp.Proto())
+	require.NoError(err)
+	pb, err = unmarshalAction(data)
+	require.NoError(err)
+	message, err = decodeAction(pb, &xrc20ABI)
+	require.NoError(err)
+	require.Equal("execution", message.Type)
+	require.False(message.Signed)
+	require.Empty(message.Sender)
+	require.Equal("approve(address,uint256)", message.Method)
+	require.Equal(identityset.Address(28).String(), message.Arguments[0].Value)
+	require.Equal(math.MaxBig256.String(), message.Arguments[1].Value)
+	require.Equal([]string{"unlimited approval, the spender can transfer all of the tokens"}, message.Warnings)
+
+	// calldata without abi
+	message, err = decodeAction(pb)
+	require.NoError(err)
+	require.Empty(message.Method)
+	require.Equal([]string{"execution data can't be decoded without the abi of the contract"}, message.Warnings)
+}
@@ -8,6 +8,7 @@ package action
 
 import (
 	"encoding/hex"
+	"math/big"
 
 	"github.com/spf13/cobra"
 
@@ -90,7 +91,18 @@ func register(args []string) error {
 	if err != nil {
 		return output.NewError(output.AddressError, "failed to get signed address", err)
 	}
+	return Register(sender, name, operatorAddrStr, rewardAddrStr, ownerAddrStr, amountInRau, duration, stake2AutoStake, payload)
+}
 
+// Register sends the action signed by the sender to register a candidate, which creates the self-stake bucket of the
+// amount and the duration in days
+func Register(
+	sender, name, operatorAddrStr, rewardAddrStr, ownerAddrStr string,
+	amountInRau *big.Int,
+	duration uint32,
+	autoStake bool,
+	payload []byte,
+) error {
 	gasLimit := gasLimitFlag.Value().(uint64)
 	if gasLimit == 0 {
 		gasLimit = action.CandidateRegisterBaseIntrinsicGas +
@@ -105,7 +117,7 @@ func register(args []string) error {
 	if err != nil {
 		return output.NewError(0, "failed to get nonce ", err)
 	}
-	cr, err := action.NewCandidateRegister(nonce, name, operatorAddrStr, rewardAddrStr, ownerAddrStr, amountInRau.String(), duration, stake2AutoStake, payload, gasLimit, gasPriceRau)
+	cr, err := action.NewCandidateRegister(nonce, name, operatorAddrStr, rewardAddrStr, ownerAddrStr, amountInRau.String(), duration, autoStake, payload, gasLimit, gasPriceRau)
 
 	if err != nil {
 		return output.NewError(output.InstantiationError, "failed to make a candidateRegister instance", err)
@@ -140,7 +140,8 @@ func GetProbationList(epochNum uint64, epochStartHeight uint64) (*iotexapi.ReadS
 	response, err := cli.ReadState(ctx, request)
 	if err != nil {
 		sta, ok := status.FromError(err)
-		if ok && sta.Code() == codes.NotFound {
+		if ok && (sta.Code() == codes.NotFound || sta.Code() == codes.FailedPrecondition) {
+			// no probation list before it is activated
 			return nil, nil
 		} else if ok {
 			return nil, output.NewError(output.APIError, sta.Message(), nil)
@@ -0,0 +1,129 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package faucet
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/iotexproject/iotex-core/ioctl/config"
+	"github.com/iotexproject/iotex-core/ioctl/output"
+	"github.com/iotexproject/iotex-core/ioctl/util"
+)
+
+// Multi-language support
+var (
+	faucetCmdShorts = map[config.Language]string{
+		config.English: "Claim testnet tokens from a faucet",
+		config.Chinese: "从水龙头领取测试网代币",
+	}
+	faucetCmdUses = map[config.Language]string{
+		config.English: "faucet (ALIAS|ADDRESS) --url URL [--token TOKEN] [--captcha CAPTCHA]",
+		config.Chinese: "faucet (别名|地址) --url URL [--token 令牌] [--captcha 验证码]",
+	}
+	flagURLUsages = map[config.Language]string{
+		config.English: "url of the faucet",
+		config.Chinese: "水龙头的url",
+	}
+	flagTokenUsages = map[config.Language]string{
+		config.English: "access token of the faucet",
+		config.Chinese: "水龙头的访问令牌",
+	}
+	flagCaptchaUsages = map[config.Language]string{
+		config.English: "captcha response",
+		config.Chinese: "验证码响应",
+	}
+)
+
+const _claimTimeout = 30 * time.Second
+
+var (
+	faucetURL string
+	token     string
+	captcha   string
+)
+
+// FaucetCmd represents the faucet command
+var FaucetCmd = &cobra.Command{
+	Use:   config.TranslateInLang(faucetCmdUses, config.UILanguage),
+	Short: config.TranslateInLang(faucetCmdShorts, config.UILanguage),
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		err := claim(args[0])
+		return output.PrintError(err)
+	},
+}
+
+type claimMessage struct {
+	Address    string `json:"address"`
+	ActionHash string `json:"actionHash"`
+	Amount     string `json:"amount"`
+}
+
+func (m *claimMessage) String() string {
+	if output.Format == "" {
+		amount := m.Amount
+		if iotx, err := util.StringToIOTX(m.Amount); err == nil {
+			amount = iotx
+		}
+		return fmt.Sprintf("Claimed %s IOTX to %s\nWait for several seconds and query this action by hash: %s",
+			amount, m.Address, m.ActionHash)
+	}
+	return output.FormatString(output.Result, m)
+}
+
+func init() {
+	FaucetCmd.Flags().StringVar(&faucetURL, "url", "", config.TranslateInLang(flagURLUsages, config.UILanguage))
+	FaucetCmd.Flags().StringVar(&token, "token", "", config.TranslateInLang(flagTokenUsages, config.UILanguage))
+	FaucetCmd.Flags().StringVar(&captcha, "captcha", "", config.TranslateInLang(flagCaptchaUsages, config.UILanguage))
+}
+
+func claim(arg string) error {
+	if faucetURL == "" {
+		return output.NewError(output.FlagError, "faucet url is not specified", nil)
+	}
+	addr, err := util.Address(arg)
+	if err != nil {
+		return output.NewError(output.AddressError, "failed to get address", err)
+	}
+	body, err := json.Marshal(map[string]string{
+		"address": addr,
+		"token":   token,
+		"captcha": captcha,
+	})
+	if err != nil {
+		return output.NewError(output.SerializationError, "failed to marshal claim request", err)
+	}
+	client := &http.Client{Timeout: _claimTimeout}
+	resp, err := client.Post(strings.TrimSuffix(faucetURL, "/")+"/claim", "application/json", bytes.NewReader(body))
+	if err != nil {
+		return output.NewError(output.NetworkError, "failed to send claim request", err)
+	}
+	defer resp.Body.Close()
+
+	var res struct {
+		ActionHash string `json:"actionHash"`
+		Amount     string `json:"amount"`
+		Error      string `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return output.NewError(output.SerializationError, "failed to decode claim response", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return output.NewError(output.APIError, fmt.Sprintf("failed to claim (%s): %s", resp.Status, res.Error), nil)
+	}
+	message := claimMessage{Address: addr, ActionHash: res.ActionHash, Amount: res.Amount}
+	fmt.Println(message.String())
+	return nil
+}
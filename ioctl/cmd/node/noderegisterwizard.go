@@ -0,0 +1,300 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package node
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/grpc-ecosystem/go-grpc-middleware/util/metautils"
+	"github.com/spf13/cobra"
+
+	"github.com/iotexproject/iotex-proto/golang/iotexapi"
+	"github.com/iotexproject/iotex-proto/golang/iotextypes"
+
+	"github.com/iotexproject/iotex-core/ioctl/cmd/account"
+	"github.com/iotexproject/iotex-core/ioctl/cmd/action"
+	"github.com/iotexproject/iotex-core/ioctl/cmd/bc"
+	"github.com/iotexproject/iotex-core/ioctl/config"
+	"github.com/iotexproject/iotex-core/ioctl/output"
+	"github.com/iotexproject/iotex-core/ioctl/util"
+	"github.com/iotexproject/iotex-core/ioctl/validator"
+)
+
+const (
+	_verifyRetries  = 6
+	_verifyInterval = 5 * time.Second
+)
+
+// Multi-language support
+var (
+	registerWizardCmdUses = map[config.Language]string{
+		config.English: "register-wizard",
+		config.Chinese: "register-wizard",
+	}
+	registerWizardCmdShorts = map[config.Language]string{
+		config.English: "Guide through registering a new delegate step by step",
+		config.Chinese: "逐步引导注册新的代表",
+	}
+)
+
+// nodeRegisterWizardCmd represents the node register wizard command
+var nodeRegisterWizardCmd = &cobra.Command{
+	Use:   config.TranslateInLang(registerWizardCmdUses, config.UILanguage),
+	Short: config.TranslateInLang(registerWizardCmdShorts, config.UILanguage),
+	Args:  cobra.ExactArgs(0),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		err := registerWizard(bufio.NewReader(os.Stdin))
+		return output.PrintError(err)
+	},
+}
+
+type registration struct {
+	name      string
+	owner     string
+	operator  string
+	reward    string
+	amount    string
+	duration  uint32
+	autoStake bool
+}
+
+func registerWizard(in *bufio.Reader) error {
+	r := &registration{}
+
+	fmt.Println("Step 1/5: keys\n" +
+		"The owner account signs the registration and owns the self-stake bucket, the operator account is used by the " +
+		"node to produce blocks, and the reward account receives the rewards. Keep the owner key offline if possible.")
+	var err error
+	if r.owner, err = askAccount(in, "owner", ""); err != nil {
+		return err
+	}
+	if r.operator, err = askAccount(in, "operator", r.owner); err != nil {
+		return err
+	}
+	if r.reward, err = askAccount(in, "reward", r.owner); err != nil {
+		return err
+	}
+
+	fmt.Println("\nStep 2/5: candidate")
+	for {
+		if r.name, err = ask(in, "Candidate name (1 to 12 lowercase letters and digits)", ""); err != nil {
+			return err
+		}
+		if err = validator.ValidateCandidateNameForStake2(r.name); err == nil {
+			break
+		}
+		fmt.Println(output.StringMessage(err.Error()).Warn())
+	}
+
+	fmt.Println("\nStep 3/5: self-stake bucket\n" +
+		"The registration creates a bucket owned by the owner account to self-stake the amount for the duration. The " +
+		"amount must meet the minimum self-stake of the chain, and is deducted from the balance of the owner account.")
+	for {
+		if r.amount, err = ask(in, "Self-stake amount in IOTX", ""); err != nil {
+			return err
+		}
+		if _, err = util.StringToRau(r.amount, util.IotxDecimalNum); err == nil {
+			break
+		}
+		fmt.Println(output.StringMessage("invalid amount").Warn())
+	}
+	for {
+		d, err := ask(in, "Stake duration in days, a multiple of 7 up to 1050", "91")
+		if err != nil {
+			return err
+		}
+		duration, ok := new(big.Int).SetString(d, 10)
+		if !ok {
+			fmt.Println(output.StringMessage("invalid stake duration").Warn())
+			continue
+		}
+		if err := validator.ValidateStakeDuration(duration); err != nil {
+			fmt.Println(output.StringMessage(err.Error()).Warn())
+			continue
+		}
+		r.duration = uint32(duration.Uint64())
+		break
+	}
+	autoStake, err := ask(in, "Enable auto-stake to keep the bonus of the full duration, yes or no", "yes")
+	if err != nil {
+		return err
+	}
+	r.autoStake = strings.EqualFold(autoStake, "yes")
+
+	fmt.Println("\nStep 4/5: probation")
+	explainProbation()
+
+	fmt.Println("\nStep 5/5: register and verify")
+	amountInRau, err := util.StringToRau(r.amount, util.IotxDecimalNum)
+	if err != nil {
+		return output.NewError(output.ConvertError, "invalid amount", err)
+	}
+	if err := action.Register(r.owner, r.name, r.operator, r.reward, r.owner, amountInRau, r.duration, r.autoStake, nil); err != nil {
+		return err
+	}
+	return verifyRegistration(r)
+}
+
+// askAccount returns the address of an existing account or alias, or a new account, defaulting to the given address
+func askAccount(in *bufio.Reader, role, defaultAddr string) (string, error) {
+	hint := "address or alias, or \"new\" to create an account"
+	if defaultAddr != "" {
+		hint += ", defaults to the owner"
+	}
+	for {
+		answer, err := ask(in, fmt.Sprintf("The %s %s", role, hint), defaultAddr)
+		if err != nil {
+			return "", err
+		}
+		if strings.EqualFold(answer, "new") {
+			alias, err := ask(in, fmt.Sprintf("Alias of the new %s account", role), role)
+			if err != nil {
+				return "", err
+			}
+			if err := validator.ValidateAlias(alias); err != nil {
+				fmt.Println(output.StringMessage(err.Error()).Warn())
+				continue
+			}
+			if _, ok := config.ReadConfig.Aliases[alias]; ok {
+				fmt.Println(output.StringMessage(fmt.Sprintf("alias %s is already used", alias)).Warn())
+				continue
+			}
+			return account.CreateAccount(alias)
+		}
+		addr, err := util.Address(answer)
+		if err == nil {
+			return addr, nil
+		}
+		fmt.Println(output.StringMessage(err.Error()).Warn())
+	}
+}
+
+func ask(in *bufio.Reader, question, defaultValue string) (string, error) {
+	if defaultValue != "" {
+		question = fmt.Sprintf("%s [%s]", question, defaultValue)
+	}
+	output.PrintQuery(question + ":")
+	answer, err := in.ReadString('\n')
+	if err != nil {
+		return "", output.NewError(output.InputError, "failed to read input", err)
+	}
+	answer = strings.TrimSpace(answer)
+	if answer == "" {
+		return defaultValue, nil
+	}
+	return answer, nil
+}
+
+func explainProbation() {
+	fmt.Println("A delegate producing fewer blocks than the productivity threshold in an epoch is put on probation for " +
+		"the following epochs, when its votes are reduced by the intensity rate in selecting the active block " +
+		"producers and splitting the epoch reward. Keep the node of the operator " +
+		"account running and synced before the delegate is elected, and use \"ioctl node probationlist\" to check.")
+	chainMeta, err := bc.GetChainMeta()
+	if err != nil || chainMeta.GetEpoch() == nil {
+		return
+	}
+	epoch := chainMeta.GetEpoch()
+	pl, err := getProbationList(epoch.Num, epoch.Height)
+	if err != nil || pl.IntensityRate == 0 {
+		return
+	}
+	fmt.Printf("The intensity rate of epoch %d is %d%%, and %d delegates are on probation.\n",
+		epoch.Num, pl.IntensityRate, len(pl.ProbationInfo))
+}
+
+// verifyRegistration waits for the candidate to be registered and checks it against the registration
+func verifyRegistration(r *registration) error {
+	conn, err := util.ConnectToEndpoint(config.ReadConfig.SecureConnect && !config.Insecure)
+	if err != nil {
+		return output.NewError(output.NetworkError, "failed to connect to endpoint", err)
+	}
+	defer conn.Close()
+	cli := iotexapi.NewAPIServiceClient(conn)
+
+	var candidate *iotextypes.CandidateV2
+	for i := 0; i < _verifyRetries; i++ {
+		time.Sleep(_verifyInterval)
+		if candidate, err = getStakingCandidateByName(cli, r.name); err != nil {
+			return output.NewError(output.APIError, "failed to get candidate", err)
+		}
+		if candidate.GetName() != "" {
+			break
+		}
+	}
+	if candidate.GetName() == "" {
+		return output.NewError(output.APIError, fmt.Sprintf("candidate %s is not registered yet, check the action "+
+			"with \"ioctl action hash\" and run \"ioctl node delegate --all\" later", r.name), nil)
+	}
+	var mismatches []string
+	for _, f := range []struct{ field, expected, actual string }{
+		{"owner", r.owner, candidate.OwnerAddress},
+		{"operator", r.operator, candidate.OperatorAddress},
+		{"reward", r.reward, candidate.RewardAddress},
+	} {
+		if f.expected != f.actual {
+			mismatches = append(mismatches, fmt.Sprintf("%s address is %s instead of %s", f.field, f.actual, f.expected))
+		}
+	}
+	if len(mismatches) > 0 {
+		return output.NewError(output.ValidationError, fmt.Sprintf("candidate %s is registered by others: %s", r.name,
+			strings.Join(mismatches, ", ")), nil)
+	}
+	selfStake, ok := new(big.Int).SetString(candidate.SelfStakingTokens, 10)
+	if !ok {
+		selfStake = big.NewInt(0)
+	}
+	output.PrintResult(fmt.Sprintf("Candidate %s is registered with self-stake bucket %d of %s IOTX.\n"+
+		"Start the node with the operator key, and it joins the delegates once it is elected with enough votes.",
+		r.name, candidate.SelfStakeBucketIdx, util.RauToString(selfStake, util.IotxDecimalNum)))
+	return nil
+}
+
+func getStakingCandidateByName(chainClient iotexapi.APIServiceClient, name string) (*iotextypes.CandidateV2, error) {
+	methodName, err := proto.Marshal(&iotexapi.ReadStakingDataMethod{
+		Method: iotexapi.ReadStakingDataMethod_CANDIDATE_BY_NAME,
+	})
+	if err != nil {
+		return nil, err
+	}
+	arg, err := proto.Marshal(&iotexapi.ReadStakingDataRequest{
+		Request: &iotexapi.ReadStakingDataRequest_CandidateByName_{
+			CandidateByName: &iotexapi.ReadStakingDataRequest_CandidateByName{
+				CandName: name,
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	ctx := context.Background()
+	jwtMD, err := util.JwtAuth()
+	if err == nil {
+		ctx = metautils.NiceMD(jwtMD).ToOutgoing(ctx)
+	}
+	res, err := chainClient.ReadState(ctx, &iotexapi.ReadStateRequest{
+		ProtocolID: []byte(protocolID),
+		MethodName: methodName,
+		Arguments:  [][]byte{arg},
+	})
+	if err != nil {
+		return nil, err
+	}
+	candidate := &iotextypes.CandidateV2{}
+	if err := proto.Unmarshal(res.GetData(), candidate); err != nil {
+		return nil, err
+	}
+	return candidate, nil
+}
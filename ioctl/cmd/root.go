@@ -15,11 +15,13 @@ import (
 	"github.com/iotexproject/iotex-core/ioctl/cmd/bc"
 	"github.com/iotexproject/iotex-core/ioctl/cmd/contract"
 	"github.com/iotexproject/iotex-core/ioctl/cmd/did"
+	"github.com/iotexproject/iotex-core/ioctl/cmd/faucet"
 	"github.com/iotexproject/iotex-core/ioctl/cmd/hdwallet"
 	"github.com/iotexproject/iotex-core/ioctl/cmd/jwt"
 	"github.com/iotexproject/iotex-core/ioctl/cmd/node"
 	"github.com/iotexproject/iotex-core/ioctl/cmd/update"
 	"github.com/iotexproject/iotex-core/ioctl/cmd/version"
+	"github.com/iotexproject/iotex-core/ioctl/cmd/watch"
 	"github.com/iotexproject/iotex-core/ioctl/config"
 	"github.com/iotexproject/iotex-core/ioctl/output"
 )
@@ -78,6 +80,8 @@ func NewIoctl() *cobra.Command {
 	rootCmd.AddCommand(did.DIDCmd)
 	rootCmd.AddCommand(hdwallet.HdwalletCmd)
 	rootCmd.AddCommand(jwt.JwtCmd)
+	rootCmd.AddCommand(watch.WatchCmd)
+	rootCmd.AddCommand(faucet.FaucetCmd)
 	rootCmd.PersistentFlags().StringVarP(&output.Format, "output-format", "o", "",
 		config.TranslateInLang(flagOutputFormatUsages, config.UILanguage))
 
@@ -0,0 +1,287 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package watch
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/grpc-ecosystem/go-grpc-middleware/util/metautils"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/status"
+
+	"github.com/iotexproject/go-pkgs/crypto"
+	"github.com/iotexproject/go-pkgs/hash"
+	"github.com/iotexproject/iotex-address/address"
+	"github.com/iotexproject/iotex-proto/golang/iotexapi"
+	"github.com/iotexproject/iotex-proto/golang/iotextypes"
+
+	"github.com/iotexproject/iotex-core/ioctl/config"
+	"github.com/iotexproject/iotex-core/ioctl/output"
+	"github.com/iotexproject/iotex-core/ioctl/util"
+)
+
+// Multi-language support
+var (
+	watchCmdShorts = map[config.Language]string{
+		config.English: "Watch transfers, executions and logs involving an address in real time",
+		config.Chinese: "实时监视与地址相关的转账、执行和日志",
+	}
+	watchCmdUses = map[config.Language]string{
+		config.English: "watch (ALIAS|ADDRESS) [--webhook URL]",
+		config.Chinese: "watch (别名|地址) [--webhook URL]",
+	}
+	flagWebhookUsages = map[config.Language]string{
+		config.English: "post the events in json to the url",
+		config.Chinese: "将事件以json格式发送到该url",
+	}
+	flagEndpointUsages = map[config.Language]string{
+		config.English: "set endpoint for once",
+		config.Chinese: "一次设置端点",
+	}
+	flagInsecureUsages = map[config.Language]string{
+		config.English: "insecure connection for once",
+		config.Chinese: "一次不安全的连接",
+	}
+)
+
+const _webhookTimeout = 10 * time.Second
+
+// Event types
+const (
+	TransferEvent  = "transfer"
+	ExecutionEvent = "execution"
+	ActionEvent    = "action"
+	LogEvent       = "log"
+)
+
+var webhook string
+
+// WatchCmd represents the watch command
+var WatchCmd = &cobra.Command{
+	Use:   config.TranslateInLang(watchCmdUses, config.UILanguage),
+	Short: config.TranslateInLang(watchCmdShorts, config.UILanguage),
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		err := watch(args[0])
+		return output.PrintError(err)
+	},
+}
+
+// Event is an event involving the watched address
+type Event struct {
+	Type      string   `json:"type"`
+	Height    uint64   `json:"height"`
+	ActHash   string   `json:"actHash"`
+	From      string   `json:"from,omitempty"`
+	To        string   `json:"to,omitempty"`
+	Amount    string   `json:"amount,omitempty"`
+	Status    uint64   `json:"status"`
+	Contract  string   `json:"contract,omitempty"`
+	Topics    []string `json:"topics,omitempty"`
+	Data      string   `json:"data,omitempty"`
+	Timestamp int64    `json:"timestamp"`
+}
+
+func (m *Event) String() string {
+	if output.Format == "" {
+		switch m.Type {
+		case LogEvent:
+			return fmt.Sprintf("[%d] log of %s by action %s, topics: %v, data: %s",
+				m.Height, m.Contract, m.ActHash, m.Topics, m.Data)
+		default:
+			message := fmt.Sprintf("[%d] %s %s from %s to %s", m.Height, m.Type, m.ActHash, m.From, m.To)
+			if m.Amount != "" {
+				message += ", amount: " + m.Amount + " IOTX"
+			}
+			return message + fmt.Sprintf(", status: %d", m.Status)
+		}
+	}
+	return output.FormatString(output.Result, m)
+}
+
+func init() {
+	WatchCmd.Flags().StringVar(&webhook, "webhook", "", config.TranslateInLang(flagWebhookUsages, config.UILanguage))
+	WatchCmd.PersistentFlags().StringVar(&config.ReadConfig.Endpoint, "endpoint",
+		config.ReadConfig.Endpoint, config.TranslateInLang(flagEndpointUsages, config.UILanguage))
+	WatchCmd.PersistentFlags().BoolVar(&config.Insecure, "insecure", config.Insecure,
+		config.TranslateInLang(flagInsecureUsages, config.UILanguage))
+}
+
+func watch(arg string) error {
+	addrStr, err := util.Address(arg)
+	if err != nil {
+		return output.NewError(output.AddressError, "failed to get address", err)
+	}
+	addr, err := address.FromString(addrStr)
+	if err != nil {
+		return output.NewError(output.AddressError, "invalid address", err)
+	}
+	conn, err := util.ConnectToEndpoint(config.ReadConfig.SecureConnect && !config.Insecure)
+	if err != nil {
+		return output.NewError(output.NetworkError, "failed to connect to endpoint", err)
+	}
+	defer conn.Close()
+	cli := iotexapi.NewAPIServiceClient(conn)
+	ctx := context.Background()
+
+	jwtMD, err := util.JwtAuth()
+	if err == nil {
+		ctx = metautils.NiceMD(jwtMD).ToOutgoing(ctx)
+	}
+
+	stream, err := cli.StreamBlocks(ctx, &iotexapi.StreamBlocksRequest{})
+	if err != nil {
+		return output.NewError(output.NetworkError, "failed to invoke StreamBlocks api", err)
+	}
+	output.PrintResult(fmt.Sprintf("Watching %s at %s", addr.String(), config.ReadConfig.Endpoint))
+	client := &http.Client{Timeout: _webhookTimeout}
+	for {
+		res, err := stream.Recv()
+		if err != nil {
+			if sta, ok := status.FromError(err); ok {
+				return output.NewError(output.APIError, sta.Message(), nil)
+			}
+			return output.NewError(output.NetworkError, "failed to receive block", err)
+		}
+		for _, event := range Match(res.GetBlock(), addr) {
+			fmt.Println(event.String())
+			if webhook == "" {
+				continue
+			}
+			if err := post(client, webhook, event); err != nil {
+				fmt.Println(output.StringMessage(fmt.Sprintf("failed to post event to webhook: %v", err)).Warn())
+			}
+		}
+	}
+}
+
+// Match returns the events in the block involving the address, that is the actions sent by or to the address, and the
+// logs emitted by the address or having the address as a topic
+func Match(blk *iotexapi.BlockInfo, addr address.Address) []*Event {
+	if blk == nil || blk.Block == nil {
+		return nil
+	}
+	height := blk.Block.GetHeader().GetCore().GetHeight()
+	var ts int64
+	if t := blk.Block.GetHeader().GetCore().GetTimestamp(); t != nil {
+		ts = t.Seconds
+	}
+	receipts := make(map[hash.Hash256]*iotextypes.Receipt, len(blk.Receipts))
+	for _, r := range blk.Receipts {
+		receipts[hash.BytesToHash256(r.ActHash)] = r
+	}
+	addrStr := addr.String()
+	topic := append(make([]byte, 12), addr.Bytes()...)
+
+	var events []*Event
+	for _, selp := range blk.Block.GetBody().GetActions() {
+		data, err := proto.Marshal(selp)
+		if err != nil {
+			continue
+		}
+		h := hash.Hash256b(data)
+		receipt := receipts[h]
+		event := &Event{
+			Height:    height,
+			ActHash:   hex.EncodeToString(h[:]),
+			Timestamp: ts,
+		}
+		if pk, err := crypto.BytesToPublicKey(selp.SenderPubKey); err == nil {
+			if sender, err := address.FromBytes(pk.Hash()); err == nil {
+				event.From = sender.String()
+			}
+		}
+		core := selp.GetCore()
+		switch {
+		case core.GetTransfer() != nil:
+			event.Type = TransferEvent
+			event.To = core.GetTransfer().GetRecipient()
+			event.Amount = rauToIOTX(core.GetTransfer().GetAmount())
+		case core.GetExecution() != nil:
+			event.Type = ExecutionEvent
+			event.To = core.GetExecution().GetContract()
+			if event.To == "" && receipt != nil {
+				event.To = receipt.ContractAddress
+			}
+			event.Amount = rauToIOTX(core.GetExecution().GetAmount())
+		default:
+			event.Type = ActionEvent
+		}
+		if receipt != nil {
+			event.Status = receipt.Status
+		}
+		if event.From == addrStr || event.To == addrStr {
+			events = append(events, event)
+		}
+		if receipt == nil {
+			continue
+		}
+		for _, l := range receipt.Logs {
+			if !logInvolves(l, addrStr, topic) {
+				continue
+			}
+			logEvent := &Event{
+				Type:      LogEvent,
+				Height:    height,
+				ActHash:   event.ActHash,
+				Status:    receipt.Status,
+				Contract:  l.ContractAddress,
+				Data:      hex.EncodeToString(l.Data),
+				Timestamp: ts,
+			}
+			for _, t := range l.Topics {
+				logEvent.Topics = append(logEvent.Topics, hex.EncodeToString(t))
+			}
+			events = append(events, logEvent)
+		}
+	}
+	return events
+}
+
+func logInvolves(l *iotextypes.Log, addr string, topic []byte) bool {
+	if l.ContractAddress == addr {
+		return true
+	}
+	for _, t := range l.Topics {
+		if bytes.Equal(t, topic) {
+			return true
+		}
+	}
+	return false
+}
+
+func rauToIOTX(amount string) string {
+	iotx, err := util.StringToIOTX(amount)
+	if err != nil {
+		return amount
+	}
+	return iotx
+}
+
+func post(client *http.Client, url string, event *Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("status %s", resp.Status)
+	}
+	return nil
+}
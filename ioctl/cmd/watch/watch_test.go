@@ -0,0 +1,80 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package watch
+
+import (
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-proto/golang/iotexapi"
+	"github.com/iotexproject/iotex-proto/golang/iotextypes"
+
+	"github.com/iotexproject/iotex-core/action"
+	"github.com/iotexproject/iotex-core/test/identityset"
+)
+
+func TestMatch(t *testing.T) {
+	require := require.New(t)
+
+	sign := func(elp action.Envelope, i int) *iotextypes.Action {
+		selp, err := action.Sign(elp, identityset.PrivateKey(i))
+		require.NoError(err)
+		return selp.Proto()
+	}
+	hashOf := func(pb *iotextypes.Action) []byte {
+		selp := action.SealedEnvelope{}
+		require.NoError(selp.LoadProto(pb))
+		h := selp.Hash()
+		return h[:]
+	}
+
+	tsf, err := action.NewTransfer(1, big.NewInt(1000000000000000000), identityset.Address(28).String(), nil, 100000, big.NewInt(1))
+	require.NoError(err)
+	tsfPb := sign((&action.EnvelopeBuilder{}).SetNonce(1).SetGasLimit(100000).SetGasPrice(big.NewInt(1)).SetAction(tsf).Build(), 27)
+	exec, err := action.NewExecution(identityset.Address(29).String(), 1, big.NewInt(0), 100000, big.NewInt(1), []byte{1})
+	require.NoError(err)
+	execPb := sign((&action.EnvelopeBuilder{}).SetNonce(1).SetGasLimit(100000).SetGasPrice(big.NewInt(1)).SetAction(exec).Build(), 30)
+	topic := append(make([]byte, 12), identityset.Address(28).Bytes()...)
+	blk := &iotexapi.BlockInfo{
+		Block: &iotextypes.Block{
+			Header: &iotextypes.BlockHeader{Core: &iotextypes.BlockHeaderCore{Height: 10}},
+			Body:   &iotextypes.BlockBody{Actions: []*iotextypes.Action{tsfPb, execPb}},
+		},
+		Receipts: []*iotextypes.Receipt{
+			{ActHash: hashOf(tsfPb), Status: 1},
+			{ActHash: hashOf(execPb), Status: 1, Logs: []*iotextypes.Log{
+				{ContractAddress: identityset.Address(29).String(), Topics: [][]byte{make([]byte, 32), topic}, Data: []byte{2}},
+			}},
+		},
+	}
+
+	events := Match(blk, identityset.Address(28))
+	require.Len(events, 2)
+	require.Equal(TransferEvent, events[0].Type)
+	require.Equal(uint64(10), events[0].Height)
+	require.Equal(hex.EncodeToString(hashOf(tsfPb)), events[0].ActHash)
+	require.Equal(identityset.Address(27).String(), events[0].From)
+	require.Equal(identityset.Address(28).String(), events[0].To)
+	require.Equal("1", events[0].Amount)
+	require.Equal(uint64(1), events[0].Status)
+	require.Equal(LogEvent, events[1].Type)
+	require.Equal(identityset.Address(29).String(), events[1].Contract)
+	require.Equal(hex.EncodeToString(topic), events[1].Topics[1])
+	require.Equal("02", events[1].Data)
+
+	events = Match(blk, identityset.Address(29))
+	require.Len(events, 2)
+	require.Equal(ExecutionEvent, events[0].Type)
+	require.Equal(identityset.Address(30).String(), events[0].From)
+	require.Equal(LogEvent, events[1].Type)
+
+	require.Empty(Match(blk, identityset.Address(31)))
+	require.Empty(Match(&iotexapi.BlockInfo{}, identityset.Address(31)))
+}
@@ -57,14 +57,17 @@ type Context struct {
 
 // Config defines the config schema
 type Config struct {
-	Wallet         string            `json:"wallet" yaml:"wallet"`
-	Endpoint       string            `json:"endpoint" yaml:"endpoint"`
-	SecureConnect  bool              `json:"secureConnect" yaml:"secureConnect"`
-	Aliases        map[string]string `json:"aliases" yaml:"aliases"`
-	DefaultAccount Context           `json:"defaultAccount" yaml:"defaultAccount"`
-	Explorer       string            `json:"explorer" yaml:"explorer"`
-	Language       string            `json:"language" yaml:"language"`
-	Nsv2height     uint64            `json:"nsv2height" yaml:"nsv2height"`
+	Wallet         string              `json:"wallet" yaml:"wallet"`
+	Endpoint       string              `json:"endpoint" yaml:"endpoint"`
+	SecureConnect  bool                `json:"secureConnect" yaml:"secureConnect"`
+	Aliases        map[string]string   `json:"aliases" yaml:"aliases"`
+	DefaultAccount Context             `json:"defaultAccount" yaml:"defaultAccount"`
+	Explorer       string              `json:"explorer" yaml:"explorer"`
+	Language       string              `json:"language" yaml:"language"`
+	Nsv2height     uint64              `json:"nsv2height" yaml:"nsv2height"`
+	ChainID        uint32              `json:"chainID" yaml:"chainID"`
+	CurrentProfile string              `json:"currentProfile" yaml:"currentProfile"`
+	Profiles       map[string]*Profile `json:"profiles" yaml:"profiles"`
 }
 
 var (
@@ -126,6 +129,8 @@ func init() {
 	ConfigCmd.AddCommand(configGetCmd)
 	ConfigCmd.AddCommand(configSetCmd)
 	ConfigCmd.AddCommand(configResetCmd)
+	ConfigCmd.AddCommand(configSwitchCmd)
+	ConfigCmd.AddCommand(configProfileCmd)
 }
 
 // LoadConfig loads config file in yaml format
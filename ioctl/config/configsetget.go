@@ -31,8 +31,8 @@ const (
 
 var (
 	supportedLanguage = []string{"English", "中文"}
-	validArgs         = []string{"endpoint", "wallet", "explorer", "defaultacc", "language", "nsv2height"}
-	validGetArgs      = []string{"endpoint", "wallet", "explorer", "defaultacc", "language", "nsv2height", "all"}
+	validArgs         = []string{"endpoint", "wallet", "explorer", "defaultacc", "language", "nsv2height", "chainid"}
+	validGetArgs      = []string{"endpoint", "wallet", "explorer", "defaultacc", "language", "nsv2height", "chainid", "profile", "all"}
 	validExpl         = []string{"iotexscan", "iotxplorer"}
 	endpointCompile   = regexp.MustCompile("^" + endpointPattern + "$")
 )
@@ -152,6 +152,15 @@ func Get(arg string) error {
 	case "nsv2height":
 		fmt.Println(ReadConfig.Nsv2height)
 		return nil
+	case "chainid":
+		fmt.Println(ReadConfig.ChainID)
+		return nil
+	case "profile":
+		if ReadConfig.CurrentProfile == "" {
+			return output.NewError(output.ConfigError, `use "ioctl config switch PROFILE" to switch to a profile first`, nil)
+		}
+		output.PrintResult(ReadConfig.CurrentProfile)
+		return nil
 	case "all":
 		fmt.Println(ReadConfig.String())
 		return nil
@@ -208,6 +217,7 @@ func isSupportedLanguage(arg string) Language {
 
 // writeConfig writes to config file
 func writeConfig() error {
+	ReadConfig.SaveProfile()
 	out, err := yaml.Marshal(&ReadConfig)
 	if err != nil {
 		return output.NewError(output.SerializationError, "failed to marshal config", err)
@@ -276,6 +286,12 @@ func set(args []string) error {
 			return output.NewError(output.ValidationError, "invalid height", nil)
 		}
 		ReadConfig.Nsv2height = height
+	case "chainid":
+		chainID, err := strconv.ParseUint(args[1], 10, 32)
+		if err != nil {
+			return output.NewError(output.ValidationError, "invalid chain id", nil)
+		}
+		ReadConfig.ChainID = uint32(chainID)
 	}
 	err := writeConfig()
 	if err != nil {
@@ -293,6 +309,8 @@ func reset() error {
 	ReadConfig.DefaultAccount = *new(Context)
 	ReadConfig.Explorer = "iotexscan"
 	ReadConfig.Language = "English"
+	ReadConfig.ChainID = 0
+	ReadConfig.CurrentProfile = ""
 
 	err := writeConfig()
 	if err != nil {
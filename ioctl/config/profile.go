@@ -0,0 +1,259 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/iotexproject/iotex-core/ioctl/output"
+)
+
+// Profile is a named set of endpoint, chain id and address book
+type Profile struct {
+	Endpoint      string            `json:"endpoint" yaml:"endpoint"`
+	SecureConnect bool              `json:"secureConnect" yaml:"secureConnect"`
+	ChainID       uint32            `json:"chainID" yaml:"chainID"`
+	Explorer      string            `json:"explorer" yaml:"explorer"`
+	Aliases       map[string]string `json:"aliases" yaml:"aliases"`
+}
+
+// _builtinProfiles are the profiles available without being added
+var _builtinProfiles = map[string]Profile{
+	"mainnet": {
+		Endpoint:      "api.iotex.one:443",
+		SecureConnect: true,
+		ChainID:       1,
+		Explorer:      "iotexscan",
+	},
+	"testnet": {
+		Endpoint:      "api.testnet.iotex.one:443",
+		SecureConnect: true,
+		ChainID:       2,
+		Explorer:      "iotexscan",
+	},
+}
+
+// configSwitchCmd represents the config switch command
+var configSwitchCmd = &cobra.Command{
+	Use:   "switch PROFILE",
+	Short: "Switch to another profile of endpoint, chain id and address book",
+	Long: "Switch to another profile of endpoint, chain id and address book\n" +
+		"Built-in profiles: [mainnet, testnet]",
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		err := switchProfile(args[0])
+		return output.PrintError(err)
+	},
+}
+
+// configProfileCmd represents the config profile command
+var configProfileCmd = &cobra.Command{
+	Use:   "profile",
+	Short: "Manage profiles of ioctl",
+}
+
+// configProfileAddCmd represents the config profile add command
+var configProfileAddCmd = &cobra.Command{
+	Use:   "add NAME ENDPOINT CHAIN_ID",
+	Short: "Add a profile with an empty address book",
+	Args:  cobra.ExactArgs(3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		err := addProfile(args[0], args[1], args[2])
+		return output.PrintError(err)
+	},
+}
+
+// configProfileListCmd represents the config profile list command
+var configProfileListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List profiles",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		listProfiles()
+		return nil
+	},
+}
+
+// configProfileRemoveCmd represents the config profile remove command
+var configProfileRemoveCmd = &cobra.Command{
+	Use:   "remove NAME",
+	Short: "Remove a profile",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		err := removeProfile(args[0])
+		return output.PrintError(err)
+	},
+}
+
+type profileMessage struct {
+	Name    string `json:"name"`
+	Current bool   `json:"current"`
+	Profile
+}
+
+type profileListMessage struct {
+	Profiles []profileMessage `json:"profiles"`
+}
+
+func (m *profileListMessage) String() string {
+	if output.Format == "" {
+		lines := make([]string, 0, len(m.Profiles))
+		for _, p := range m.Profiles {
+			mark := " "
+			if p.Current {
+				mark = "*"
+			}
+			lines = append(lines, fmt.Sprintf("%s %s    %s    chain id: %d    aliases: %d",
+				mark, p.Name, p.Endpoint, p.ChainID, len(p.Aliases)))
+		}
+		return strings.Join(lines, "\n")
+	}
+	return output.FormatString(output.Result, m)
+}
+
+func init() {
+	configProfileAddCmd.Flags().BoolVar(&Insecure, "insecure", false,
+		"connect to the endpoint of the profile insecurely")
+	configProfileCmd.AddCommand(configProfileAddCmd)
+	configProfileCmd.AddCommand(configProfileListCmd)
+	configProfileCmd.AddCommand(configProfileRemoveCmd)
+}
+
+// SaveProfile stores the endpoint, chain id and address book in use into the current profile
+func (c *Config) SaveProfile() {
+	if c.CurrentProfile == "" {
+		return
+	}
+	if c.Profiles == nil {
+		c.Profiles = make(map[string]*Profile)
+	}
+	c.Profiles[c.CurrentProfile] = &Profile{
+		Endpoint:      c.Endpoint,
+		SecureConnect: c.SecureConnect,
+		ChainID:       c.ChainID,
+		Explorer:      c.Explorer,
+		Aliases:       c.Aliases,
+	}
+}
+
+// SwitchProfile saves the current profile and puts the endpoint, chain id and address book of the named profile in
+// use. The address book of the config is left as is when switching from an unnamed profile for the first time, so
+// that aliases set up before profiles existed are kept in the first profile switched to
+func (c *Config) SwitchProfile(name string) error {
+	p, ok := c.Profiles[name]
+	if !ok {
+		builtin, ok := _builtinProfiles[name]
+		if !ok {
+			return output.NewError(output.ConfigError, fmt.Sprintf("profile %s does not exist", name), nil)
+		}
+		p = &builtin
+		if c.CurrentProfile == "" {
+			p.Aliases = c.Aliases
+		}
+	}
+	c.SaveProfile()
+	c.CurrentProfile = name
+	c.Endpoint = p.Endpoint
+	c.SecureConnect = p.SecureConnect
+	c.ChainID = p.ChainID
+	c.Explorer = p.Explorer
+	c.Aliases = make(map[string]string, len(p.Aliases))
+	for k, v := range p.Aliases {
+		c.Aliases[k] = v
+	}
+	return nil
+}
+
+func switchProfile(name string) error {
+	if err := ReadConfig.SwitchProfile(name); err != nil {
+		return err
+	}
+	if err := writeConfig(); err != nil {
+		return err
+	}
+	output.PrintResult(fmt.Sprintf("Switched to profile %s, endpoint %s, chain id %d",
+		name, ReadConfig.Endpoint, ReadConfig.ChainID))
+	return nil
+}
+
+func addProfile(name, endpoint, chainID string) error {
+	if name == "" {
+		return output.NewError(output.ValidationError, "empty profile name", nil)
+	}
+	if _, ok := ReadConfig.Profiles[name]; ok || name == ReadConfig.CurrentProfile {
+		return output.NewError(output.ConfigError, fmt.Sprintf("profile %s already exists", name), nil)
+	}
+	if !isValidEndpoint(endpoint) {
+		return output.NewError(output.ConfigError, fmt.Sprintf("endpoint %s is not valid", endpoint), nil)
+	}
+	id, err := strconv.ParseUint(chainID, 10, 32)
+	if err != nil {
+		return output.NewError(output.ValidationError, "invalid chain id", err)
+	}
+	if ReadConfig.Profiles == nil {
+		ReadConfig.Profiles = make(map[string]*Profile)
+	}
+	ReadConfig.Profiles[name] = &Profile{
+		Endpoint:      endpoint,
+		SecureConnect: !Insecure,
+		ChainID:       uint32(id),
+		Explorer:      "iotexscan",
+		Aliases:       make(map[string]string),
+	}
+	if err := writeConfig(); err != nil {
+		return err
+	}
+	output.PrintResult(fmt.Sprintf("Profile %s is added", name))
+	return nil
+}
+
+func listProfiles() {
+	ReadConfig.SaveProfile()
+	names := make(map[string]Profile)
+	for name, p := range _builtinProfiles {
+		names[name] = p
+	}
+	for name, p := range ReadConfig.Profiles {
+		names[name] = *p
+	}
+	message := profileListMessage{}
+	for name, p := range names {
+		message.Profiles = append(message.Profiles, profileMessage{
+			Name:    name,
+			Current: name == ReadConfig.CurrentProfile,
+			Profile: p,
+		})
+	}
+	sort.Slice(message.Profiles, func(i, j int) bool {
+		return message.Profiles[i].Name < message.Profiles[j].Name
+	})
+	fmt.Println(message.String())
+}
+
+func removeProfile(name string) error {
+	if name == ReadConfig.CurrentProfile {
+		return output.NewError(output.ConfigError, "cannot remove the profile in use", nil)
+	}
+	if _, ok := ReadConfig.Profiles[name]; !ok {
+		return output.NewError(output.ConfigError, fmt.Sprintf("profile %s does not exist", name), nil)
+	}
+	delete(ReadConfig.Profiles, name)
+	if err := writeConfig(); err != nil {
+		return err
+	}
+	output.PrintResult(fmt.Sprintf("Profile %s is removed", name))
+	return nil
+}
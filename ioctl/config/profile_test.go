@@ -0,0 +1,54 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSwitchProfile(t *testing.T) {
+	require := require.New(t)
+
+	cfg := Config{
+		Endpoint:      "localhost:14014",
+		SecureConnect: false,
+		Aliases:       map[string]string{"alice": "io1alice"},
+	}
+	require.Error(cfg.SwitchProfile("devnet"))
+
+	// aliases set up before profiles existed go into the first profile
+	require.NoError(cfg.SwitchProfile("testnet"))
+	require.Equal("testnet", cfg.CurrentProfile)
+	require.Equal("api.testnet.iotex.one:443", cfg.Endpoint)
+	require.True(cfg.SecureConnect)
+	require.Equal(uint32(2), cfg.ChainID)
+	require.Equal("io1alice", cfg.Aliases["alice"])
+
+	// address books are kept per profile
+	require.NoError(cfg.SwitchProfile("mainnet"))
+	require.Equal(uint32(1), cfg.ChainID)
+	require.Empty(cfg.Aliases)
+	cfg.Aliases["bob"] = "io1bob"
+	cfg.Endpoint = "api.mainnet.iotex.one:443"
+
+	require.NoError(cfg.SwitchProfile("testnet"))
+	require.Equal(map[string]string{"alice": "io1alice"}, cfg.Aliases)
+	require.NoError(cfg.SwitchProfile("mainnet"))
+	require.Equal(map[string]string{"bob": "io1bob"}, cfg.Aliases)
+	require.Equal("api.mainnet.iotex.one:443", cfg.Endpoint)
+	require.Len(cfg.Profiles, 2)
+
+	cfg.Profiles["devnet"] = &Profile{Endpoint: "localhost:14014", ChainID: 4689}
+	require.NoError(cfg.SwitchProfile("devnet"))
+	require.Equal("localhost:14014", cfg.Endpoint)
+	require.False(cfg.SecureConnect)
+	require.Equal(uint32(4689), cfg.ChainID)
+	require.Empty(cfg.Aliases)
+	require.NotNil(cfg.Aliases)
+}
@@ -139,7 +139,8 @@ func GetProbationList(epochNum uint64, client ioctl.Client) (*iotexapi.ReadState
 	response, err := apiServiceClient.ReadState(context.Background(), request)
 	if err != nil {
 		sta, ok := status.FromError(err)
-		if ok && sta.Code() == codes.NotFound {
+		if ok && (sta.Code() == codes.NotFound || sta.Code() == codes.FailedPrecondition) {
+			// no probation list before it is activated
 			return nil, nil
 		} else if ok {
 			return nil, output.NewError(output.APIError, sta.Message(), nil)
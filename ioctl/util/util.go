@@ -8,6 +8,7 @@ package util
 
 import (
 	"bytes"
+	"context"
 	"crypto/tls"
 	"fmt"
 	"io/ioutil"
@@ -36,6 +37,9 @@ import (
 )
 
 const (
+	// ChainIDMetadataKey is the key of the grpc metadata claiming the chain an action is meant for, endpoints on other
+	// chains reject the action. It's the same as api.ChainIDMetadataKey
+	ChainIDMetadataKey = "x-iotex-chain-id"
 	// IotxDecimalNum defines the number of decimal digits for IoTeX
 	IotxDecimalNum = 18
 	// GasPriceDecimalNum defines the number of decimal digits for gas price
@@ -64,6 +68,14 @@ func ConnectToEndpoint(secure bool) (*grpc.ClientConn, error) {
 	return grpc.Dial(endpoint, grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{})))
 }
 
+// WithChainID claims the chain id of the config in the outgoing context, if it's set
+func WithChainID(ctx context.Context) context.Context {
+	if config.ReadConfig.ChainID == 0 {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, ChainIDMetadataKey, strconv.FormatUint(uint64(config.ReadConfig.ChainID), 10))
+}
+
 // StringToRau converts different unit string into Rau big int
 func StringToRau(amount string, numDecimals int) (*big.Int, error) {
 	amountStrings := strings.Split(amount, ".")
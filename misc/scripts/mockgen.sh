@@ -69,6 +69,12 @@ mockgen -destination=./action/protocol/mock_protocol_test.go  \
         -package=protocol \
         Protocol
 
+mkdir -p ./test/mock/mock_protocol
+mockgen -destination=./test/mock/mock_protocol/mock_protocol.go  \
+        -source=./action/protocol/protocol.go \
+        -package=mock_protocol \
+        Protocol
+
 mkdir -p ./test/mock/mock_poll
 mockgen -destination=./test/mock/mock_poll/mock_poll.go  \
         -source=./action/protocol/poll/protocol.go \
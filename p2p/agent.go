@@ -73,6 +73,9 @@ type (
 
 	// HandleUnicastInboundAsync handles unicast message when agent listens it from the network
 	HandleUnicastInboundAsync func(context.Context, uint32, peerstore.PeerInfo, proto.Message)
+
+	// HandlePeerTime handles the sending time reported by a peer in its unicast message
+	HandlePeerTime func(string, time.Time)
 )
 
 // Agent is the agent to help the blockchain node connect into the P2P networks and send/receive messages
@@ -81,13 +84,20 @@ type Agent struct {
 	topicSuffix                string
 	broadcastInboundHandler    HandleBroadcastInbound
 	unicastInboundAsyncHandler HandleUnicastInboundAsync
+	peerTimeHandler            HandlePeerTime
 	host                       *p2p.Host
 	unicastBlocklist           *BlockList
+	latencyTracker             *LatencyTracker
+	ipGuard                    *ipGuard
 }
 
 // NewAgent instantiates a local P2P agent instance
 func NewAgent(cfg config.Config, broadcastHandler HandleBroadcastInbound, unicastHandler HandleUnicastInboundAsync) *Agent {
 	gh := cfg.Genesis.Hash()
+	var latencyTracker *LatencyTracker
+	if cfg.Network.EnableLatencyReport {
+		latencyTracker = NewLatencyTracker()
+	}
 	return &Agent{
 		cfg: cfg.Network,
 		// Make sure the honest node only care the messages related the chain from the same genesis
@@ -95,6 +105,8 @@ func NewAgent(cfg config.Config, broadcastHandler HandleBroadcastInbound, unicas
 		broadcastInboundHandler:    broadcastHandler,
 		unicastInboundAsyncHandler: unicastHandler,
 		unicastBlocklist:           NewBlockList(blockListLen),
+		latencyTracker:             latencyTracker,
+		ipGuard:                    newIPGuard(cfg.Network.MaxPeersPerIP, cfg.Network.NewPeerRatePerIP),
 	}
 }
 
@@ -146,6 +158,11 @@ func (p *Agent) Start(ctx context.Context) error {
 			p2pMsgCounter.WithLabelValues("broadcast", strconv.Itoa(int(broadcast.MsgType)), "in", peerID, status).Inc()
 			p2pMsgLatency.WithLabelValues("broadcast", strconv.Itoa(int(broadcast.MsgType)), status).Observe(float64(latency))
 		}()
+		if p.oversized(data) {
+			p2pDropCounter.WithLabelValues("broadcast", dropOversized).Inc()
+			skip = true
+			return
+		}
 		if err = proto.Unmarshal(data, &broadcast); err != nil {
 			err = errors.Wrap(err, "error when marshaling broadcast message")
 			return
@@ -162,8 +179,11 @@ func (p *Agent) Start(ctx context.Context) error {
 			return
 		}
 
-		t, _ := ptypes.Timestamp(broadcast.GetTimestamp())
+		t, tErr := ptypes.Timestamp(broadcast.GetTimestamp())
 		latency = time.Since(t).Nanoseconds() / time.Millisecond.Nanoseconds()
+		if tErr == nil && p.latencyTracker != nil {
+			p.latencyTracker.Observe(peerID, broadcast.MsgType, t, time.Now())
+		}
 
 		msg, err := goproto.TypifyRPCMsg(broadcast.MsgType, broadcast.MsgBody)
 		if err != nil {
@@ -184,6 +204,21 @@ func (p *Agent) Start(ctx context.Context) error {
 			peerID  string
 			latency int64
 		)
+		stream, ok := p2p.GetUnicastStream(ctx)
+		if !ok {
+			err = errors.New("error when asserting unicast stream context")
+			return
+		}
+		// drop before accounting and decoding to be cheap under connection floods
+		peerID = stream.Conn().RemotePeer().Pretty()
+		if allowed, reason := p.ipGuard.Allow(remoteIP(stream.Conn().RemoteMultiaddr()), peerID, time.Now()); !allowed {
+			p2pDropCounter.WithLabelValues("unicast", reason).Inc()
+			return stream.Conn().Close()
+		}
+		if p.oversized(data) {
+			p2pDropCounter.WithLabelValues("unicast", dropOversized).Inc()
+			return nil
+		}
 		defer func() {
 			status := successStr
 			if err != nil {
@@ -202,15 +237,14 @@ func (p *Agent) Start(ctx context.Context) error {
 			return
 		}
 
-		t, _ := ptypes.Timestamp(unicast.GetTimestamp())
+		t, tErr := ptypes.Timestamp(unicast.GetTimestamp())
 		latency = time.Since(t).Nanoseconds() / time.Millisecond.Nanoseconds()
 
-		stream, ok := p2p.GetUnicastStream(ctx)
-		if !ok {
-			err = errors.Wrap(err, "error when typifying unicast message")
-			return
+		// only the direct messages are sampled for the clock offset, the gossip delay of the broadcast messages would
+		// be taken as the offset of the local clock
+		if tErr == nil {
+			p.observePeerTime(peerID, t)
 		}
-		peerID = stream.Conn().RemotePeer().Pretty()
 		peerInfo := peerstore.PeerInfo{
 			ID:    stream.Conn().RemotePeer(),
 			Addrs: []multiaddr.Multiaddr{stream.Conn().RemoteMultiaddr()},
@@ -277,6 +311,27 @@ func (p *Agent) Start(ctx context.Context) error {
 	return nil
 }
 
+// oversized returns true if the size of the inbound message exceeds the limit
+func (p *Agent) oversized(data []byte) bool {
+	return p.cfg.MaxMessageSize > 0 && len(data) > p.cfg.MaxMessageSize
+}
+
+// SetPeerTimeHandler sets the handler of the sending time reported by peers, it should be set before the agent starts
+func (p *Agent) SetPeerTimeHandler(handler HandlePeerTime) {
+	p.peerTimeHandler = handler
+}
+
+func (p *Agent) observePeerTime(peerID string, t time.Time) {
+	if p.peerTimeHandler != nil {
+		p.peerTimeHandler(peerID, t)
+	}
+}
+
+// LatencyTracker returns the tracker of the gossip propagation latency, or nil if the latency report is disabled
+func (p *Agent) LatencyTracker() *LatencyTracker {
+	return p.latencyTracker
+}
+
 // Stop disconnects from P2P network
 func (p *Agent) Stop(ctx context.Context) error {
 	if p.host == nil {
@@ -409,6 +464,34 @@ func (p *Agent) Neighbors(ctx context.Context) ([]peerstore.PeerInfo, error) {
 	return res, nil
 }
 
+// Rebootstrap dials the bootstrap nodes and the fallback nodes again, which recovers the agent from a network partition
+// where all the neighbors are stale
+func (p *Agent) Rebootstrap(ctx context.Context) error {
+	var tryNum, connNum int
+	nodes := append(append([]string{}, p.cfg.BootstrapNodes...), p.cfg.FallbackNodes...)
+	for _, node := range nodes {
+		addr, err := multiaddr.NewMultiaddr(node)
+		if err != nil {
+			log.L().Warn("Invalid node address.", zap.String("address", node), zap.Error(err))
+			continue
+		}
+		if strings.Contains(addr.String(), p.host.HostIdentity()) {
+			continue
+		}
+		tryNum++
+		if err := p.host.ConnectWithMultiaddr(ctx, addr); err != nil {
+			log.L().Info("Connection failed.", zap.String("address", node), zap.Error(err))
+			continue
+		}
+		connNum++
+		log.L().Info("Reconnected node.", zap.String("address", node))
+	}
+	if tryNum > 0 && connNum == 0 {
+		return errors.New("failed to connect to any bootstrap or fallback node")
+	}
+	return nil
+}
+
 func convertAppMsg(msg proto.Message) (iotexrpc.MessageType, []byte, error) {
 	msgType, err := goproto.GetTypeFromRPCMsg(msg)
 	if err != nil {
@@ -0,0 +1,144 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package p2p
+
+import (
+	"sync"
+	"time"
+
+	multiaddr "github.com/multiformats/go-multiaddr"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// _ipPeerTTL is the duration after which a peer not heard from is no longer counted for its IP
+	_ipPeerTTL = 10 * time.Minute
+
+	dropOversized   = "oversized"
+	dropIPPeerCap   = "ip_peer_cap"
+	dropIPRateLimit = "ip_rate_limit"
+	unknownIP       = "unknown"
+)
+
+var p2pDropCounter = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "iotex_p2p_drop_counter",
+		Help: "Number of inbound messages and connections dropped",
+	},
+	[]string{"protocol", "reason"},
+)
+
+func init() {
+	prometheus.MustRegister(p2pDropCounter)
+}
+
+type (
+	// ipGuard caps the number of peers connecting from the same IP, and limits the rate of new peers from the same
+	// IP with a token bucket of which the burst is the cap
+	ipGuard struct {
+		mu        sync.Mutex
+		maxPeers  int
+		rate      float64
+		ips       map[string]*ipPeers
+		lastPrune time.Time
+	}
+
+	ipPeers struct {
+		peers      map[string]time.Time
+		tokens     float64
+		lastRefill time.Time
+	}
+)
+
+func newIPGuard(maxPeers int, rate float64) *ipGuard {
+	return &ipGuard{
+		maxPeers: maxPeers,
+		rate:     rate,
+		ips:      map[string]*ipPeers{},
+	}
+}
+
+// Allow returns whether the peer connecting from the ip is allowed, and the reason if not
+func (g *ipGuard) Allow(ip, peer string, now time.Time) (bool, string) {
+	if g.maxPeers <= 0 && g.rate <= 0 {
+		return true, ""
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if now.Sub(g.lastPrune) > _ipPeerTTL {
+		g.prune(now)
+	}
+	p, ok := g.ips[ip]
+	if !ok {
+		p = &ipPeers{
+			peers:      map[string]time.Time{},
+			tokens:     g.burst(),
+			lastRefill: now,
+		}
+		g.ips[ip] = p
+	}
+	if _, ok := p.peers[peer]; ok {
+		p.peers[peer] = now
+		return true, ""
+	}
+	for id, lastSeen := range p.peers {
+		if now.Sub(lastSeen) > _ipPeerTTL {
+			delete(p.peers, id)
+		}
+	}
+	if g.maxPeers > 0 && len(p.peers) >= g.maxPeers {
+		return false, dropIPPeerCap
+	}
+	if g.rate > 0 {
+		p.tokens += now.Sub(p.lastRefill).Seconds() * g.rate
+		if burst := g.burst(); p.tokens > burst {
+			p.tokens = burst
+		}
+		p.lastRefill = now
+		if p.tokens < 1 {
+			return false, dropIPRateLimit
+		}
+		p.tokens--
+	}
+	p.peers[peer] = now
+	return true, ""
+}
+
+// prune removes the ips without any peer heard from recently
+func (g *ipGuard) prune(now time.Time) {
+	g.lastPrune = now
+	for ip, p := range g.ips {
+		for id, lastSeen := range p.peers {
+			if now.Sub(lastSeen) > _ipPeerTTL {
+				delete(p.peers, id)
+			}
+		}
+		if len(p.peers) == 0 {
+			delete(g.ips, ip)
+		}
+	}
+}
+
+func (g *ipGuard) burst() float64 {
+	if g.maxPeers > 0 {
+		return float64(g.maxPeers)
+	}
+	return 1
+}
+
+// remoteIP returns the ip in the multiaddr
+func remoteIP(addr multiaddr.Multiaddr) string {
+	if addr == nil {
+		return unknownIP
+	}
+	for _, code := range []int{multiaddr.P_IP4, multiaddr.P_IP6} {
+		if ip, err := addr.ValueForProtocol(code); err == nil {
+			return ip
+		}
+	}
+	return unknownIP
+}
@@ -0,0 +1,68 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package p2p
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	multiaddr "github.com/multiformats/go-multiaddr"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIPGuard(t *testing.T) {
+	r := require.New(t)
+
+	now := time.Unix(1612345678, 0)
+	// disabled
+	g := newIPGuard(0, 0)
+	for i := 0; i < 10; i++ {
+		allowed, _ := g.Allow("1.2.3.4", strconv.Itoa(i), now)
+		r.True(allowed)
+	}
+
+	// peer cap
+	g = newIPGuard(2, 0)
+	for i, expected := range []bool{true, true, false} {
+		allowed, reason := g.Allow("1.2.3.4", strconv.Itoa(i), now)
+		r.Equal(expected, allowed)
+		if !expected {
+			r.Equal(dropIPPeerCap, reason)
+		}
+	}
+	// known peers and other ips are not affected
+	allowed, _ := g.Allow("1.2.3.4", "0", now)
+	r.True(allowed)
+	allowed, _ = g.Allow("5.6.7.8", "2", now)
+	r.True(allowed)
+	// idle peers are not counted
+	allowed, _ = g.Allow("1.2.3.4", "2", now.Add(_ipPeerTTL+time.Second))
+	r.True(allowed)
+
+	// rate limit of new peers
+	g = newIPGuard(0, 0.5)
+	allowed, _ = g.Allow("1.2.3.4", "0", now)
+	r.True(allowed)
+	allowed, reason := g.Allow("1.2.3.4", "1", now.Add(time.Second))
+	r.False(allowed)
+	r.Equal(dropIPRateLimit, reason)
+	allowed, _ = g.Allow("1.2.3.4", "1", now.Add(2*time.Second))
+	r.True(allowed)
+
+	// idle ips are pruned
+	g.Allow("5.6.7.8", "0", now.Add(2*_ipPeerTTL))
+	r.Len(g.ips, 1)
+}
+
+func TestRemoteIP(t *testing.T) {
+	r := require.New(t)
+
+	r.Equal("1.2.3.4", remoteIP(multiaddr.StringCast("/ip4/1.2.3.4/tcp/4689")))
+	r.Equal("::1", remoteIP(multiaddr.StringCast("/ip6/::1/tcp/4689")))
+	r.Equal(unknownIP, remoteIP(nil))
+}
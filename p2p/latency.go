@@ -0,0 +1,124 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package p2p
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/iotexproject/iotex-proto/golang/iotexrpc"
+)
+
+// _latencyPeerTTL is the duration after which a peer not heard from is dropped from the report
+const _latencyPeerTTL = time.Hour
+
+var propagationLatency = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "iotex_p2p_propagation_latency",
+		Help: "Average latency in milliseconds from the origin peer sending a block or consensus message to receiving it",
+	},
+	[]string{"peer", "message"},
+)
+
+func init() {
+	prometheus.MustRegister(propagationLatency)
+}
+
+type (
+	// PeerLatency is the propagation latency of a type of gossip messages sent by a peer. The latency is measured
+	// against the send timestamp in the message, so it includes the clock drift between the peer and the node
+	PeerLatency struct {
+		Peer     string        `json:"peer"`
+		Message  string        `json:"message"`
+		Count    uint64        `json:"count"`
+		Mean     time.Duration `json:"mean"`
+		Min      time.Duration `json:"min"`
+		Max      time.Duration `json:"max"`
+		Last     time.Duration `json:"last"`
+		LastSeen time.Time     `json:"lastSeen"`
+	}
+
+	// LatencyTracker aggregates the propagation latency of the block and consensus messages per origin peer
+	LatencyTracker struct {
+		mu    sync.Mutex
+		peers map[latencyKey]*PeerLatency
+	}
+
+	latencyKey struct {
+		peer    string
+		message iotexrpc.MessageType
+	}
+)
+
+// NewLatencyTracker creates a new latency tracker
+func NewLatencyTracker() *LatencyTracker {
+	return &LatencyTracker{
+		peers: map[latencyKey]*PeerLatency{},
+	}
+}
+
+// Observe records a message of the type sent by the peer at sentAt and received at receivedAt, messages other than
+// block and consensus are ignored
+func (lt *LatencyTracker) Observe(peer string, msgType iotexrpc.MessageType, sentAt, receivedAt time.Time) {
+	if msgType != iotexrpc.MessageType_BLOCK && msgType != iotexrpc.MessageType_CONSENSUS {
+		return
+	}
+	latency := receivedAt.Sub(sentAt)
+	key := latencyKey{peer, msgType}
+
+	lt.mu.Lock()
+	defer lt.mu.Unlock()
+	pl, ok := lt.peers[key]
+	if !ok {
+		pl = &PeerLatency{
+			Peer:    peer,
+			Message: msgType.String(),
+			Min:     latency,
+			Max:     latency,
+		}
+		lt.peers[key] = pl
+	}
+	pl.Count++
+	pl.Mean += (latency - pl.Mean) / time.Duration(pl.Count)
+	if latency < pl.Min {
+		pl.Min = latency
+	}
+	if latency > pl.Max {
+		pl.Max = latency
+	}
+	pl.Last = latency
+	pl.LastSeen = receivedAt
+	propagationLatency.WithLabelValues(peer, pl.Message).Set(float64(pl.Mean / time.Millisecond))
+}
+
+// Report returns the latency of the peers heard from recently, the slowest first
+func (lt *LatencyTracker) Report(now time.Time) []PeerLatency {
+	lt.mu.Lock()
+	defer lt.mu.Unlock()
+	report := make([]PeerLatency, 0, len(lt.peers))
+	for key, pl := range lt.peers {
+		if now.Sub(pl.LastSeen) > _latencyPeerTTL {
+			propagationLatency.DeleteLabelValues(pl.Peer, pl.Message)
+			delete(lt.peers, key)
+			continue
+		}
+		report = append(report, *pl)
+	}
+	sort.Slice(report, func(i, j int) bool {
+		if report[i].Mean != report[j].Mean {
+			return report[i].Mean > report[j].Mean
+		}
+		if report[i].Peer != report[j].Peer {
+			return report[i].Peer < report[j].Peer
+		}
+		return report[i].Message < report[j].Message
+	})
+	return report
+}
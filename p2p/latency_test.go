@@ -0,0 +1,63 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package p2p
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-proto/golang/iotexrpc"
+)
+
+func TestLatencyTracker(t *testing.T) {
+	require := require.New(t)
+
+	lt := NewLatencyTracker()
+	start := time.Unix(1612345678, 0)
+	for i, latency := range []time.Duration{100, 300, 200} {
+		sentAt := start.Add(time.Duration(i) * time.Second)
+		lt.Observe("peer1", iotexrpc.MessageType_BLOCK, sentAt, sentAt.Add(latency*time.Millisecond))
+	}
+	lt.Observe("peer1", iotexrpc.MessageType_CONSENSUS, start, start.Add(50*time.Millisecond))
+	lt.Observe("peer2", iotexrpc.MessageType_CONSENSUS, start, start.Add(500*time.Millisecond))
+	// only block and consensus messages are tracked
+	lt.Observe("peer3", iotexrpc.MessageType_ACTION, start, start.Add(time.Second))
+
+	report := lt.Report(start.Add(time.Minute))
+	require.Equal(3, len(report))
+	require.Equal(PeerLatency{
+		Peer:     "peer2",
+		Message:  iotexrpc.MessageType_CONSENSUS.String(),
+		Count:    1,
+		Mean:     500 * time.Millisecond,
+		Min:      500 * time.Millisecond,
+		Max:      500 * time.Millisecond,
+		Last:     500 * time.Millisecond,
+		LastSeen: start.Add(500 * time.Millisecond),
+	}, report[0])
+	require.Equal(PeerLatency{
+		Peer:     "peer1",
+		Message:  iotexrpc.MessageType_BLOCK.String(),
+		Count:    3,
+		Mean:     200 * time.Millisecond,
+		Min:      100 * time.Millisecond,
+		Max:      300 * time.Millisecond,
+		Last:     200 * time.Millisecond,
+		LastSeen: start.Add(2*time.Second + 200*time.Millisecond),
+	}, report[1])
+	require.Equal("peer1", report[2].Peer)
+	require.Equal(50*time.Millisecond, report[2].Mean)
+
+	// peers not heard from for long are dropped
+	lt.Observe("peer2", iotexrpc.MessageType_BLOCK, start.Add(2*time.Hour), start.Add(2*time.Hour+time.Second))
+	report = lt.Report(start.Add(2 * time.Hour))
+	require.Equal(1, len(report))
+	require.Equal("peer2", report[0].Peer)
+	require.Equal(iotexrpc.MessageType_BLOCK.String(), report[0].Message)
+}
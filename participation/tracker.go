@@ -0,0 +1,309 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+// Package participation tracks the endorsement participation of the delegates. Productivity only counts the blocks
+// proposed, so a delegate whose endorsements chronically arrive too late to be included in the blocks goes unnoticed.
+// The tracker records, for each committed block, which active delegates' commit endorsements are included, and how
+// late their endorsements arrive at the local node, and aggregates them per epoch.
+package participation
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/iotexproject/iotex-address/address"
+
+	"github.com/iotexproject/iotex-core/action/protocol"
+	"github.com/iotexproject/iotex-core/action/protocol/poll"
+	"github.com/iotexproject/iotex-core/action/protocol/protocolctx"
+	"github.com/iotexproject/iotex-core/action/protocol/rolldpos"
+	"github.com/iotexproject/iotex-core/blockchain/block"
+	"github.com/iotexproject/iotex-core/blockchain/genesis"
+	"github.com/iotexproject/iotex-core/db"
+	"github.com/iotexproject/iotex-core/pkg/log"
+	"github.com/iotexproject/iotex-core/pkg/util/byteutil"
+	"github.com/iotexproject/iotex-core/state"
+)
+
+// ParticipationNamespace is the namespace to store the participation of each epoch
+const ParticipationNamespace = "Participation"
+
+// _maxPendingHeights bounds the heights whose endorsement arrivals are kept before the block is committed
+const _maxPendingHeights = 16
+
+type (
+	// DelegateParticipation is the endorsement participation of a delegate in an epoch
+	DelegateParticipation struct {
+		// Included is the number of blocks whose footer includes the delegate's commit endorsement
+		Included uint64 `json:"included"`
+		// NearMisses is the number of blocks for which the delegate's commit endorsement arrived locally but is not
+		// included in the block, e.g., it arrived after the block had been committed with enough endorsements
+		NearMisses uint64 `json:"nearMisses"`
+		// Missed is the number of blocks for which the delegate's commit endorsement is neither included nor arrived
+		Missed uint64 `json:"missed"`
+		// Observed is the number of commit endorsements of the delegate arrived locally
+		Observed uint64 `json:"observed"`
+		// TotalDelayMs is the sum of delays in milliseconds of the arrived endorsements after their timestamps
+		TotalDelayMs int64 `json:"totalDelayMs"`
+		// MaxDelayMs is the max delay in milliseconds of the arrived endorsements after their timestamps
+		MaxDelayMs int64 `json:"maxDelayMs"`
+	}
+
+	// EpochParticipation is the endorsement participation of the active delegates in an epoch
+	EpochParticipation struct {
+		EpochNumber uint64 `json:"epochNumber"`
+		// Blocks is the number of blocks of the epoch tracked
+		Blocks    uint64                            `json:"blocks"`
+		Delegates map[string]*DelegateParticipation `json:"delegates"`
+	}
+
+	// Tracker tracks the endorsement participation
+	Tracker struct {
+		genesis  genesis.Genesis
+		sr       protocol.StateReader
+		registry *protocol.Registry
+		kvStore  db.KVStore
+
+		mutex   sync.Mutex
+		current *EpochParticipation
+		active  []string
+		// arrivals are the delays of the commit endorsements arrived before the block committed, by height and endorser
+		arrivals map[uint64]map[string]time.Duration
+		// lastHeight is the height of the last block tracked, and lastArrived are the endorsers whose commit
+		// endorsements of it are included or have arrived
+		lastHeight  uint64
+		lastArrived map[string]bool
+	}
+)
+
+// AvgDelayMs returns the average delay in milliseconds of the arrived endorsements
+func (dp *DelegateParticipation) AvgDelayMs() int64 {
+	if dp.Observed == 0 {
+		return 0
+	}
+	return dp.TotalDelayMs / int64(dp.Observed)
+}
+
+func (dp *DelegateParticipation) observe(delay time.Duration) {
+	dp.Observed++
+	ms := delay.Milliseconds()
+	dp.TotalDelayMs += ms
+	if ms > dp.MaxDelayMs {
+		dp.MaxDelayMs = ms
+	}
+}
+
+// NewTracker creates an endorsement participation tracker
+func NewTracker(g genesis.Genesis, sr protocol.StateReader, registry *protocol.Registry, kv db.KVStore) (*Tracker, error) {
+	if kv == nil {
+		return nil, errors.New("empty kvStore")
+	}
+	return &Tracker{
+		genesis:  g,
+		sr:       sr,
+		registry: registry,
+		kvStore:  kv,
+		arrivals: make(map[uint64]map[string]time.Duration),
+	}, nil
+}
+
+// Start starts the tracker
+func (t *Tracker) Start(ctx context.Context) error {
+	return t.kvStore.Start(ctx)
+}
+
+// Stop stops the tracker
+func (t *Tracker) Stop(ctx context.Context) error {
+	return t.kvStore.Stop(ctx)
+}
+
+// ObserveEndorsement records the arrival of a commit endorsement of the block at height, the delay is the time of
+// arrival after the timestamp of the endorsement
+func (t *Tracker) ObserveEndorsement(height uint64, endorser string, delay time.Duration) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if height < t.lastHeight {
+		return
+	}
+	if height == t.lastHeight {
+		// the endorsement arrives after the block is committed
+		if err := t.addLateArrival(endorser, delay); err != nil {
+			log.L().Error("Failed to track endorsement participation.", zap.Uint64("height", height), zap.Error(err))
+		}
+		return
+	}
+	arrivals, ok := t.arrivals[height]
+	if !ok {
+		if len(t.arrivals) >= _maxPendingHeights {
+			return
+		}
+		arrivals = make(map[string]time.Duration)
+		t.arrivals[height] = arrivals
+	}
+	if d, ok := arrivals[endorser]; !ok || delay < d {
+		arrivals[endorser] = delay
+	}
+}
+
+// ReceiveBlock adds the participation of the block to its epoch
+func (t *Tracker) ReceiveBlock(blk *block.Block) error {
+	rp := rolldpos.FindProtocol(t.registry)
+	if rp == nil {
+		return nil
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	height := blk.Height()
+	arrivals := t.arrivals[height]
+	for h := range t.arrivals {
+		if h <= height {
+			delete(t.arrivals, h)
+		}
+	}
+	if err := t.addBlock(rp, blk, arrivals); err != nil {
+		log.L().Error("Failed to track endorsement participation.", zap.Uint64("height", height), zap.Error(err))
+	}
+	return nil
+}
+
+// Participation returns the endorsement participation of the epoch
+func (t *Tracker) Participation(epochNum uint64) (*EpochParticipation, error) {
+	data, err := t.kvStore.Get(ParticipationNamespace, byteutil.Uint64ToBytesBigEndian(epochNum))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get participation of epoch %d", epochNum)
+	}
+	ep := &EpochParticipation{}
+	if err := json.Unmarshal(data, ep); err != nil {
+		return nil, err
+	}
+	return ep, nil
+}
+
+func (t *Tracker) addBlock(rp *rolldpos.Protocol, blk *block.Block, arrivals map[string]time.Duration) error {
+	epochNum := rp.GetEpochNum(blk.Height())
+	if t.current == nil || t.current.EpochNumber != epochNum {
+		if err := t.loadEpoch(epochNum, blk.Height()); err != nil {
+			return err
+		}
+	}
+	included := make(map[string]bool)
+	for _, en := range blk.Endorsements() {
+		endorser, err := address.FromBytes(en.Endorser().Hash())
+		if err != nil {
+			return err
+		}
+		included[endorser.String()] = true
+	}
+	t.lastHeight = blk.Height()
+	t.lastArrived = make(map[string]bool, len(included)+len(arrivals))
+	for delegate := range included {
+		t.lastArrived[delegate] = true
+	}
+	for delegate := range arrivals {
+		t.lastArrived[delegate] = true
+	}
+	t.current.Blocks++
+	for _, delegate := range t.active {
+		dp, ok := t.current.Delegates[delegate]
+		if !ok {
+			dp = &DelegateParticipation{}
+			t.current.Delegates[delegate] = dp
+		}
+		delay, arrived := arrivals[delegate]
+		switch {
+		case included[delegate]:
+			dp.Included++
+		case arrived:
+			dp.NearMisses++
+		default:
+			dp.Missed++
+		}
+		if arrived {
+			dp.observe(delay)
+		}
+	}
+	return t.putCurrent()
+}
+
+// addLateArrival turns the miss of the last block into a near miss
+func (t *Tracker) addLateArrival(endorser string, delay time.Duration) error {
+	if t.current == nil || t.lastArrived[endorser] {
+		return nil
+	}
+	dp, ok := t.current.Delegates[endorser]
+	if !ok || dp.Missed == 0 {
+		return nil
+	}
+	t.lastArrived[endorser] = true
+	dp.Missed--
+	dp.NearMisses++
+	dp.observe(delay)
+	return t.putCurrent()
+}
+
+func (t *Tracker) putCurrent() error {
+	data, err := json.Marshal(t.current)
+	if err != nil {
+		return err
+	}
+	return t.kvStore.Put(ParticipationNamespace, byteutil.Uint64ToBytesBigEndian(t.current.EpochNumber), data)
+}
+
+// loadEpoch loads the participation stored of the epoch and its active delegates
+func (t *Tracker) loadEpoch(epochNum uint64, height uint64) error {
+	active, err := t.activeDelegates(epochNum, height)
+	if err != nil {
+		return err
+	}
+	ep, err := t.Participation(epochNum)
+	switch errors.Cause(err) {
+	case nil:
+	case db.ErrNotExist:
+		ep = &EpochParticipation{
+			EpochNumber: epochNum,
+			Delegates:   make(map[string]*DelegateParticipation),
+		}
+	default:
+		return err
+	}
+	t.current = ep
+	t.active = active
+	return nil
+}
+
+func (t *Tracker) activeDelegates(epochNum uint64, height uint64) ([]string, error) {
+	pp := poll.FindProtocol(t.registry)
+	if pp == nil {
+		return nil, errors.New("poll protocol is not registered")
+	}
+	ctx, err := protocolctx.NewBuilder().
+		SetRegistry(t.registry).
+		SetGenesis(t.genesis).
+		SetBlockHeight(height).
+		Build(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	data, _, err := pp.ReadState(ctx, t.sr, []byte("ActiveBlockProducersByEpoch"), []byte(strconv.FormatUint(epochNum, 10)))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read active block producers")
+	}
+	var producers state.CandidateList
+	if err := producers.Deserialize(data); err != nil {
+		return nil, err
+	}
+	delegates := make([]string, 0, len(producers))
+	for _, p := range producers {
+		delegates = append(delegates, p.Address)
+	}
+	return delegates, nil
+}
@@ -0,0 +1,107 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package participation
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/action/protocol"
+	"github.com/iotexproject/iotex-core/action/protocol/poll"
+	"github.com/iotexproject/iotex-core/action/protocol/rolldpos"
+	"github.com/iotexproject/iotex-core/blockchain/block"
+	"github.com/iotexproject/iotex-core/blockchain/genesis"
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/db"
+	"github.com/iotexproject/iotex-core/endorsement"
+	"github.com/iotexproject/iotex-core/test/identityset"
+	"github.com/iotexproject/iotex-core/test/mock/mock_chainmanager"
+)
+
+func TestTracker(t *testing.T) {
+	require := require.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var delegates []genesis.Delegate
+	for i := 0; i < 4; i++ {
+		delegates = append(delegates, genesis.Delegate{
+			OperatorAddrStr: identityset.Address(i).String(),
+			RewardAddrStr:   identityset.Address(i).String(),
+			VotesStr:        "10",
+		})
+	}
+	registry := protocol.NewRegistry()
+	require.NoError(rolldpos.NewProtocol(4, 4, 2).Register(registry))
+	require.NoError(poll.NewLifeLongDelegatesProtocol(delegates).Register(registry))
+	sr := mock_chainmanager.NewMockStateReader(ctrl)
+	sr.EXPECT().Height().Return(uint64(8), nil).AnyTimes()
+
+	tracker, err := NewTracker(config.Default.Genesis, sr, registry, db.NewMemKVStore())
+	require.NoError(err)
+	ctx := context.Background()
+	require.NoError(tracker.Start(ctx))
+	defer tracker.Stop(ctx)
+
+	_, err = tracker.Participation(1)
+	require.Equal(db.ErrNotExist, errors.Cause(err))
+
+	// delegate 0 and 1 endorse the blocks, the endorsement of delegate 2 arrives but is not included, and the one of
+	// delegate 3 arrives after the block of height 2 is committed
+	ts := time.Unix(1612345678, 0)
+	for height := uint64(1); height <= 2; height++ {
+		blk, err := block.NewTestingBuilder().
+			SetHeight(height).
+			SetTimeStamp(ts).
+			SignAndBuild(identityset.PrivateKey(0))
+		require.NoError(err)
+		require.NoError(blk.Finalize([]*endorsement.Endorsement{
+			endorsement.NewEndorsement(ts, identityset.PrivateKey(0).PublicKey(), nil),
+			endorsement.NewEndorsement(ts, identityset.PrivateKey(1).PublicKey(), nil),
+		}, ts))
+		tracker.ObserveEndorsement(height, identityset.Address(1).String(), 300*time.Millisecond)
+		tracker.ObserveEndorsement(height, identityset.Address(1).String(), 100*time.Millisecond)
+		tracker.ObserveEndorsement(height, identityset.Address(2).String(), 2*time.Second)
+		require.NoError(tracker.ReceiveBlock(&blk))
+		ts = ts.Add(5 * time.Second)
+	}
+	tracker.ObserveEndorsement(2, identityset.Address(3).String(), 4*time.Second)
+	tracker.ObserveEndorsement(2, identityset.Address(3).String(), 4*time.Second)
+	// too old
+	tracker.ObserveEndorsement(1, identityset.Address(3).String(), 9*time.Second)
+
+	ep, err := tracker.Participation(1)
+	require.NoError(err)
+	require.Equal(&EpochParticipation{
+		EpochNumber: 1,
+		Blocks:      2,
+		Delegates: map[string]*DelegateParticipation{
+			identityset.Address(0).String(): {Included: 2},
+			identityset.Address(1).String(): {Included: 2, Observed: 2, TotalDelayMs: 200, MaxDelayMs: 100},
+			identityset.Address(2).String(): {NearMisses: 2, Observed: 2, TotalDelayMs: 4000, MaxDelayMs: 2000},
+			identityset.Address(3).String(): {NearMisses: 1, Missed: 1, Observed: 1, TotalDelayMs: 4000, MaxDelayMs: 4000},
+		},
+	}, ep)
+	require.EqualValues(100, ep.Delegates[identityset.Address(1).String()].AvgDelayMs())
+	require.Zero(ep.Delegates[identityset.Address(0).String()].AvgDelayMs())
+
+	// the aggregate is reloaded from the store
+	tracker, err = NewTracker(config.Default.Genesis, sr, registry, tracker.kvStore)
+	require.NoError(err)
+	blk, err := block.NewTestingBuilder().SetHeight(3).SetTimeStamp(ts).SignAndBuild(identityset.PrivateKey(0))
+	require.NoError(err)
+	require.NoError(tracker.ReceiveBlock(&blk))
+	ep, err = tracker.Participation(1)
+	require.NoError(err)
+	require.EqualValues(3, ep.Blocks)
+	require.EqualValues(1, ep.Delegates[identityset.Address(0).String()].Missed)
+}
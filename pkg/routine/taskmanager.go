@@ -0,0 +1,257 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package routine
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/iotexproject/iotex-core/pkg/lifecycle"
+	"github.com/iotexproject/iotex-core/pkg/log"
+)
+
+// States of the subsystems managed by the task manager
+const (
+	StatePending    = "pending"
+	StateRunning    = "running"
+	StateUnhealthy  = "unhealthy"
+	StateRestarting = "restarting"
+	StateFailed     = "failed"
+	StateStopped    = "stopped"
+)
+
+type (
+	// HealthChecker is implemented by a service which reports its health, nil means healthy
+	HealthChecker interface {
+		Health() error
+	}
+
+	// LongRunningTask runs until the context is canceled, and returns an error on failure
+	LongRunningTask func(context.Context) error
+
+	// RestartPolicy is the policy to restart a failed task
+	RestartPolicy struct {
+		// MaxRestarts is the max number of restarts, 0 means never restarted and negative means unlimited
+		MaxRestarts int
+		// Backoff is the delay before the first restart, which is doubled for each further restart up to MaxBackoff
+		Backoff    time.Duration
+		MaxBackoff time.Duration
+	}
+
+	// SubsystemStatus is the status of a subsystem managed by the task manager
+	SubsystemStatus struct {
+		Name     string    `json:"name"`
+		State    string    `json:"state"`
+		Restarts int       `json:"restarts"`
+		Error    string    `json:"error,omitempty"`
+		Since    time.Time `json:"since"`
+	}
+
+	// TaskManager owns the services and the long running tasks of the background subsystems. They are started in the
+	// order of being added and stopped in the reverse order, and failed tasks are restarted per their policies
+	TaskManager struct {
+		mu      sync.RWMutex
+		started bool
+		entries []*managedTask
+	}
+
+	managedTask struct {
+		name    string
+		svc     lifecycle.StartStopper
+		run     LongRunningTask
+		policy  RestartPolicy
+		cancel  context.CancelFunc
+		done    chan struct{}
+		status  SubsystemStatus
+		started bool
+	}
+)
+
+// NewTaskManager creates a new task manager
+func NewTaskManager() *TaskManager {
+	return &TaskManager{}
+}
+
+// AddService adds a service, which is started and stopped with the task manager
+func (tm *TaskManager) AddService(name string, svc lifecycle.StartStopper) error {
+	return tm.add(&managedTask{name: name, svc: svc})
+}
+
+// AddTask adds a long running task, which is run in a goroutine and restarted per the policy if it fails or panics
+func (tm *TaskManager) AddTask(name string, run LongRunningTask, policy RestartPolicy) error {
+	return tm.add(&managedTask{name: name, run: run, policy: policy})
+}
+
+func (tm *TaskManager) add(t *managedTask) error {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	if tm.started {
+		return errors.Errorf("cannot add %s after the task manager is started", t.name)
+	}
+	for _, e := range tm.entries {
+		if e.name == t.name {
+			return errors.Errorf("%s already exists", t.name)
+		}
+	}
+	t.status = SubsystemStatus{Name: t.name, State: StatePending}
+	tm.entries = append(tm.entries, t)
+	return nil
+}
+
+// Start starts the services and the tasks in order, the started ones are stopped if any service fails to start
+func (tm *TaskManager) Start(ctx context.Context) error {
+	tm.mu.Lock()
+	if tm.started {
+		tm.mu.Unlock()
+		return errors.New("task manager already started")
+	}
+	tm.started = true
+	entries := tm.entries
+	tm.mu.Unlock()
+
+	for i, t := range entries {
+		if t.svc != nil {
+			if err := t.svc.Start(ctx); err != nil {
+				tm.setStatus(t, StateFailed, err)
+				for j := i - 1; j >= 0; j-- {
+					tm.stop(ctx, entries[j])
+				}
+				return errors.Wrapf(err, "failed to start %s", t.name)
+			}
+			tm.setStatus(t, StateRunning, nil)
+			t.started = true
+			continue
+		}
+		taskCtx, cancel := context.WithCancel(context.Background())
+		t.cancel = cancel
+		t.done = make(chan struct{})
+		t.started = true
+		tm.setStatus(t, StateRunning, nil)
+		go tm.runTask(taskCtx, t)
+	}
+	return nil
+}
+
+// Stop stops the services and the tasks in the reverse order
+func (tm *TaskManager) Stop(ctx context.Context) error {
+	tm.mu.RLock()
+	entries := tm.entries
+	tm.mu.RUnlock()
+
+	var errs []error
+	for i := len(entries) - 1; i >= 0; i-- {
+		if err := tm.stop(ctx, entries[i]); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return errors.Errorf("failed to stop subsystems: %v", errs)
+	}
+	return nil
+}
+
+// Status returns the status of the subsystems in the order of being added
+func (tm *TaskManager) Status() []SubsystemStatus {
+	tm.mu.RLock()
+	statuses := make([]SubsystemStatus, len(tm.entries))
+	checkers := make([]HealthChecker, len(tm.entries))
+	for i, t := range tm.entries {
+		statuses[i] = t.status
+		if hc, ok := t.svc.(HealthChecker); ok && t.status.State == StateRunning {
+			checkers[i] = hc
+		}
+	}
+	tm.mu.RUnlock()
+
+	// health is checked out of the lock in case it is slow
+	for i, hc := range checkers {
+		if hc == nil {
+			continue
+		}
+		if err := hc.Health(); err != nil {
+			statuses[i].State = StateUnhealthy
+			statuses[i].Error = err.Error()
+		}
+	}
+	return statuses
+}
+
+func (tm *TaskManager) stop(ctx context.Context, t *managedTask) error {
+	if !t.started {
+		return nil
+	}
+	t.started = false
+	if t.svc != nil {
+		err := t.svc.Stop(ctx)
+		tm.setStatus(t, StateStopped, err)
+		return errors.Wrapf(err, "failed to stop %s", t.name)
+	}
+	t.cancel()
+	select {
+	case <-t.done:
+		return nil
+	case <-ctx.Done():
+		return errors.Wrapf(ctx.Err(), "failed to wait for %s to stop", t.name)
+	}
+}
+
+func (tm *TaskManager) runTask(ctx context.Context, t *managedTask) {
+	defer close(t.done)
+	backoff := t.policy.Backoff
+	for restarts := 0; ; restarts++ {
+		err := runSafely(ctx, t.run)
+		if ctx.Err() != nil || err == nil {
+			tm.setStatus(t, StateStopped, nil)
+			return
+		}
+		log.L().Error("Background task failed.", zap.String("task", t.name), zap.Error(err))
+		if t.policy.MaxRestarts >= 0 && restarts >= t.policy.MaxRestarts {
+			tm.setStatus(t, StateFailed, err)
+			return
+		}
+		tm.setStatus(t, StateRestarting, err)
+		select {
+		case <-ctx.Done():
+			tm.setStatus(t, StateStopped, nil)
+			return
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+		if t.policy.MaxBackoff > 0 && backoff > t.policy.MaxBackoff {
+			backoff = t.policy.MaxBackoff
+		}
+		tm.mu.Lock()
+		t.status.Restarts++
+		tm.mu.Unlock()
+		tm.setStatus(t, StateRunning, nil)
+	}
+}
+
+func (tm *TaskManager) setStatus(t *managedTask, state string, err error) {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	t.status.State = state
+	t.status.Since = time.Now()
+	t.status.Error = ""
+	if err != nil {
+		t.status.Error = err.Error()
+	}
+}
+
+func runSafely(ctx context.Context, run LongRunningTask) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return run(ctx)
+}
@@ -0,0 +1,131 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package routine_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/pkg/routine"
+)
+
+type mockService struct {
+	name     string
+	mu       *sync.Mutex
+	events   *[]string
+	startErr error
+	health   error
+}
+
+func (s *mockService) Start(context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	*s.events = append(*s.events, "start "+s.name)
+	return s.startErr
+}
+
+func (s *mockService) Stop(context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	*s.events = append(*s.events, "stop "+s.name)
+	return nil
+}
+
+func (s *mockService) Health() error {
+	return s.health
+}
+
+func TestTaskManager_Services(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	var (
+		mu     sync.Mutex
+		events []string
+	)
+	newService := func(name string) *mockService {
+		return &mockService{name: name, mu: &mu, events: &events}
+	}
+	tm := routine.NewTaskManager()
+	a, b := newService("a"), newService("b")
+	require.NoError(tm.AddService("a", a))
+	require.NoError(tm.AddService("b", b))
+	require.Error(tm.AddService("a", a))
+	require.NoError(tm.Start(ctx))
+	require.Error(tm.AddService("c", newService("c")))
+
+	b.health = errors.New("publisher is down")
+	status := tm.Status()
+	require.Len(status, 2)
+	require.Equal(routine.StateRunning, status[0].State)
+	require.Equal(routine.StateUnhealthy, status[1].State)
+	require.Equal("publisher is down", status[1].Error)
+
+	require.NoError(tm.Stop(ctx))
+	require.Equal([]string{"start a", "start b", "stop b", "stop a"}, events)
+	require.Equal(routine.StateStopped, tm.Status()[1].State)
+
+	// the started services are stopped if any fails to start
+	events = nil
+	tm = routine.NewTaskManager()
+	c := newService("c")
+	c.startErr = errors.New("no db")
+	require.NoError(tm.AddService("a", newService("a")))
+	require.NoError(tm.AddService("c", c))
+	require.NoError(tm.AddService("b", newService("b")))
+	require.Error(tm.Start(ctx))
+	require.Equal([]string{"start a", "start c", "stop a"}, events)
+	status = tm.Status()
+	require.Equal(routine.StateFailed, status[1].State)
+	require.Equal(routine.StatePending, status[2].State)
+}
+
+func TestTaskManager_Tasks(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	tm := routine.NewTaskManager()
+	var (
+		mu   sync.Mutex
+		runs int
+	)
+	require.NoError(tm.AddTask("flaky", func(ctx context.Context) error {
+		mu.Lock()
+		runs++
+		n := runs
+		mu.Unlock()
+		switch n {
+		case 1:
+			return errors.New("failed")
+		case 2:
+			panic("crashed")
+		}
+		<-ctx.Done()
+		return nil
+	}, routine.RestartPolicy{MaxRestarts: -1, Backoff: time.Millisecond}))
+	require.NoError(tm.AddTask("once", func(context.Context) error {
+		return errors.New("failed")
+	}, routine.RestartPolicy{}))
+	require.NoError(tm.Start(ctx))
+
+	require.Eventually(func() bool {
+		status := tm.Status()
+		return status[0].State == routine.StateRunning && status[0].Restarts == 2 &&
+			status[1].State == routine.StateFailed
+	}, time.Second, 5*time.Millisecond)
+	require.Equal("failed", tm.Status()[1].Error)
+
+	require.NoError(tm.Stop(ctx))
+	status := tm.Status()
+	require.Equal(routine.StateStopped, status[0].State)
+	require.Equal(routine.StateFailed, status[1].State)
+}
@@ -0,0 +1,165 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package secret
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/iotexproject/iotex-core/pkg/util/sigv4"
+)
+
+const (
+	_gcpKMSEndpoint   = "https://cloudkms.googleapis.com"
+	_gcpTokenEndpoint = "http://metadata.google.internal/computeMetadata/v1/instance/service-accounts/default/token"
+)
+
+type (
+	// AWSKMSProvider decrypts secrets with aws kms. The region and credentials are read from AWS_REGION (or
+	// AWS_DEFAULT_REGION), AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and AWS_SESSION_TOKEN
+	AWSKMSProvider struct {
+		// Endpoint overrides the regional endpoint of kms
+		Endpoint string
+		client   *http.Client
+		now      func() time.Time
+	}
+
+	// GCPKMSProvider decrypts secrets with gcp cloud kms. The access token is read from GOOGLE_OAUTH_ACCESS_TOKEN, or
+	// fetched from the metadata server of the instance
+	GCPKMSProvider struct {
+		// Endpoint overrides the endpoint of cloud kms
+		Endpoint string
+		// TokenEndpoint overrides the endpoint of the metadata server to fetch the access token
+		TokenEndpoint string
+		client        *http.Client
+	}
+)
+
+// NewAWSKMSProvider creates an aws kms provider
+func NewAWSKMSProvider() *AWSKMSProvider {
+	return &AWSKMSProvider{
+		client: &http.Client{Timeout: _requestTimeout},
+		now:    time.Now,
+	}
+}
+
+// Resolve decrypts the base64 encoded ciphertext
+func (p *AWSKMSProvider) Resolve(ctx context.Context, ciphertext string) (string, error) {
+	region := os.Getenv("AWS_REGION")
+	if region == "" {
+		region = os.Getenv("AWS_DEFAULT_REGION")
+	}
+	signer := &sigv4.Signer{
+		AccessKey:    os.Getenv("AWS_ACCESS_KEY_ID"),
+		SecretKey:    os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		SessionToken: os.Getenv("AWS_SESSION_TOKEN"),
+		Region:       region,
+		Service:      "kms",
+	}
+	if signer.AccessKey == "" || signer.SecretKey == "" || region == "" {
+		return "", errors.New("aws region or credentials are not set")
+	}
+	endpoint := p.Endpoint
+	if endpoint == "" {
+		endpoint = "https://kms." + region + ".amazonaws.com"
+	}
+	body, err := json.Marshal(map[string]string{"CiphertextBlob": ciphertext})
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequest(http.MethodPost, strings.TrimSuffix(endpoint, "/")+"/", bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "TrentService.Decrypt")
+	signer.Sign(req, sigv4.PayloadHash(body), p.now())
+	var resp struct {
+		Plaintext string `json:"Plaintext"`
+	}
+	if err := doJSON(ctx, p.client, req, &resp); err != nil {
+		return "", err
+	}
+	plaintext, err := base64.StdEncoding.DecodeString(resp.Plaintext)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+// NewGCPKMSProvider creates a gcp kms provider
+func NewGCPKMSProvider() *GCPKMSProvider {
+	return &GCPKMSProvider{client: &http.Client{Timeout: _requestTimeout}}
+}
+
+// Resolve decrypts the ciphertext with the crypto key, ref is in the form of <crypto key name>#<base64 ciphertext>
+func (p *GCPKMSProvider) Resolve(ctx context.Context, ref string) (string, error) {
+	i := strings.Index(ref, "#")
+	if i < 0 {
+		return "", errors.New("gcp kms reference should be in the form of <crypto key name>#<ciphertext>")
+	}
+	name, ciphertext := strings.Trim(ref[:i], "/"), ref[i+1:]
+	token, err := p.accessToken(ctx)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get gcp access token")
+	}
+	endpoint := p.Endpoint
+	if endpoint == "" {
+		endpoint = _gcpKMSEndpoint
+	}
+	body, err := json.Marshal(map[string]string{"ciphertext": ciphertext})
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequest(http.MethodPost, strings.TrimSuffix(endpoint, "/")+"/v1/"+name+":decrypt", bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	var resp struct {
+		Plaintext string `json:"plaintext"`
+	}
+	if err := doJSON(ctx, p.client, req, &resp); err != nil {
+		return "", err
+	}
+	plaintext, err := base64.StdEncoding.DecodeString(resp.Plaintext)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+func (p *GCPKMSProvider) accessToken(ctx context.Context) (string, error) {
+	if token := os.Getenv("GOOGLE_OAUTH_ACCESS_TOKEN"); token != "" {
+		return token, nil
+	}
+	endpoint := p.TokenEndpoint
+	if endpoint == "" {
+		endpoint = _gcpTokenEndpoint
+	}
+	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Metadata-Flavor", "Google")
+	var resp struct {
+		AccessToken string `json:"access_token"`
+	}
+	if err := doJSON(ctx, p.client, req, &resp); err != nil {
+		return "", err
+	}
+	return resp.AccessToken, nil
+}
@@ -0,0 +1,89 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+// Package secret resolves references to secrets kept outside of config files. A reference has the form of
+// <scheme>://<ref>, the supported schemes are "file" (file://<path>), "vault" (vault://<path>#<key>), "awskms"
+// (awskms://<base64 ciphertext>) and "gcpkms" (gcpkms://<crypto key name>#<base64 ciphertext>)
+package secret
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+type (
+	// Provider resolves the reference of its scheme, the scheme part is stripped from ref
+	Provider interface {
+		Resolve(ctx context.Context, ref string) (string, error)
+	}
+
+	fileProvider struct{}
+)
+
+var (
+	_mutex     sync.RWMutex
+	_providers = map[string]Provider{
+		"file":   fileProvider{},
+		"vault":  NewVaultProvider(),
+		"awskms": NewAWSKMSProvider(),
+		"gcpkms": NewGCPKMSProvider(),
+	}
+)
+
+// Register registers the provider of the scheme, replacing the existing one
+func Register(scheme string, p Provider) {
+	_mutex.Lock()
+	defer _mutex.Unlock()
+	_providers[scheme] = p
+}
+
+// IsReference returns true if the value is a reference of a registered scheme
+func IsReference(value string) bool {
+	_, _, ok := provider(value)
+	return ok
+}
+
+// Resolve returns the secret the value references, or the value itself if it isn't a reference
+func Resolve(ctx context.Context, value string) (string, error) {
+	p, ref, ok := provider(value)
+	if !ok {
+		return value, nil
+	}
+	secret, err := p.Resolve(ctx, ref)
+	if err != nil {
+		// the reference is not included in the error in case it's sensitive by itself
+		return "", errors.Wrapf(err, "failed to resolve %s secret", value[:strings.Index(value, "://")])
+	}
+	return secret, nil
+}
+
+func provider(value string) (Provider, string, bool) {
+	i := strings.Index(value, "://")
+	if i <= 0 {
+		return nil, "", false
+	}
+	_mutex.RLock()
+	defer _mutex.RUnlock()
+	p, ok := _providers[value[:i]]
+	return p, value[i+3:], ok
+}
+
+func (fileProvider) Resolve(_ context.Context, path string) (string, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		if pathErr, ok := err.(*os.PathError); ok {
+			// strip the path
+			return "", errors.Wrap(pathErr.Err, pathErr.Op)
+		}
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
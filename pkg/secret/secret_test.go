@@ -0,0 +1,162 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package secret
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/testutil"
+)
+
+func setenv(t *testing.T, kv map[string]string) func() {
+	old := make(map[string]*string)
+	for k, v := range kv {
+		if o, ok := os.LookupEnv(k); ok {
+			old[k] = &o
+		} else {
+			old[k] = nil
+		}
+		require.NoError(t, os.Setenv(k, v))
+	}
+	return func() {
+		for k, v := range old {
+			if v == nil {
+				os.Unsetenv(k)
+			} else {
+				os.Setenv(k, *v)
+			}
+		}
+	}
+}
+
+func TestResolve(t *testing.T) {
+	require := require.New(t)
+
+	ctx := context.Background()
+	v, err := Resolve(ctx, "https://api.iotex.one")
+	require.NoError(err)
+	require.Equal("https://api.iotex.one", v)
+	require.False(IsReference("plain"))
+	require.True(IsReference("vault://secret#key"))
+
+	path, err := testutil.PathOfTempFile("secret")
+	require.NoError(err)
+	defer testutil.CleanupPath(t, path)
+	require.NoError(ioutil.WriteFile(path, []byte("private key\n"), 0600))
+	v, err = Resolve(ctx, "file://"+path)
+	require.NoError(err)
+	require.Equal("private key", v)
+
+	_, err = Resolve(ctx, "file:///not/exist")
+	require.Error(err)
+	require.False(strings.Contains(err.Error(), "/not/exist"))
+}
+
+func TestVaultProvider(t *testing.T) {
+	require := require.New(t)
+
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/secret/data/iotex":
+			_, _ = w.Write([]byte(`{"data":{"data":{"key":"v2"},"metadata":{"version":1}}}`))
+		case "/v1/kv/iotex":
+			_, _ = w.Write([]byte(`{"data":{"key":"v1"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer svr.Close()
+
+	p := NewVaultProvider()
+	p.Address = svr.URL
+	p.Token = "token"
+	ctx := context.Background()
+	v, err := p.Resolve(ctx, "secret/data/iotex#key")
+	require.NoError(err)
+	require.Equal("v2", v)
+	v, err = p.Resolve(ctx, "/kv/iotex#key")
+	require.NoError(err)
+	require.Equal("v1", v)
+	_, err = p.Resolve(ctx, "kv/iotex#missing")
+	require.Error(err)
+	_, err = p.Resolve(ctx, "kv/iotex")
+	require.Error(err)
+	p.Token = "wrong"
+	_, err = p.Resolve(ctx, "kv/iotex#key")
+	require.Error(err)
+}
+
+func TestKMSProviders(t *testing.T) {
+	require := require.New(t)
+
+	plaintext := base64.StdEncoding.EncodeToString([]byte("private key"))
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]string
+		switch r.URL.Path {
+		case "/":
+			if r.Header.Get("X-Amz-Target") != "TrentService.Decrypt" ||
+				!strings.Contains(r.Header.Get("Authorization"), "Credential=id/") ||
+				!strings.Contains(r.Header.Get("Authorization"), "/us-west-2/kms/aws4_request") {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			require.NoError(json.NewDecoder(r.Body).Decode(&req))
+			require.Equal("Y2lwaGVy", req["CiphertextBlob"])
+			_, _ = w.Write([]byte(`{"Plaintext":"` + plaintext + `"}`))
+		case "/token":
+			_, _ = w.Write([]byte(`{"access_token":"token"}`))
+		case "/v1/projects/p/locations/global/keyRings/r/cryptoKeys/k:decrypt":
+			if r.Header.Get("Authorization") != "Bearer token" {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			require.NoError(json.NewDecoder(r.Body).Decode(&req))
+			require.Equal("Y2lwaGVy", req["ciphertext"])
+			_, _ = w.Write([]byte(`{"plaintext":"` + plaintext + `"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer svr.Close()
+
+	ctx := context.Background()
+	aws := NewAWSKMSProvider()
+	aws.Endpoint = svr.URL
+	_, err := aws.Resolve(ctx, "Y2lwaGVy")
+	require.Error(err)
+	defer setenv(t, map[string]string{
+		"AWS_REGION":            "us-west-2",
+		"AWS_ACCESS_KEY_ID":     "id",
+		"AWS_SECRET_ACCESS_KEY": "secret",
+	})()
+	v, err := aws.Resolve(ctx, "Y2lwaGVy")
+	require.NoError(err)
+	require.Equal("private key", v)
+
+	gcp := NewGCPKMSProvider()
+	gcp.Endpoint = svr.URL
+	gcp.TokenEndpoint = svr.URL + "/token"
+	v, err = gcp.Resolve(ctx, "projects/p/locations/global/keyRings/r/cryptoKeys/k#Y2lwaGVy")
+	require.NoError(err)
+	require.Equal("private key", v)
+	_, err = gcp.Resolve(ctx, "projects/p/locations/global/keyRings/r/cryptoKeys/k")
+	require.Error(err)
+}
@@ -0,0 +1,91 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package secret
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const _requestTimeout = 30 * time.Second
+
+// VaultProvider reads secrets from hashicorp vault. The address and token are read from VAULT_ADDR and VAULT_TOKEN
+// if not set
+type VaultProvider struct {
+	Address string
+	Token   string
+	client  *http.Client
+}
+
+// NewVaultProvider creates a vault provider
+func NewVaultProvider() *VaultProvider {
+	return &VaultProvider{client: &http.Client{Timeout: _requestTimeout}}
+}
+
+// Resolve reads the key of the secret at path, ref is in the form of <path>#<key>
+func (p *VaultProvider) Resolve(ctx context.Context, ref string) (string, error) {
+	i := strings.LastIndex(ref, "#")
+	if i < 0 {
+		return "", errors.New("vault reference should be in the form of <path>#<key>")
+	}
+	path, key := strings.Trim(ref[:i], "/"), ref[i+1:]
+	addr, token := p.Address, p.Token
+	if addr == "" {
+		addr = os.Getenv("VAULT_ADDR")
+	}
+	if token == "" {
+		token = os.Getenv("VAULT_TOKEN")
+	}
+	if addr == "" || token == "" {
+		return "", errors.New("vault address or token is not set")
+	}
+	req, err := http.NewRequest(http.MethodGet, strings.TrimSuffix(addr, "/")+"/v1/"+path, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("X-Vault-Token", token)
+	var resp struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	if err := doJSON(ctx, p.client, req, &resp); err != nil {
+		return "", err
+	}
+	data := resp.Data
+	// kv v2 nests the secret in data.data along with data.metadata
+	if inner, ok := data["data"].(map[string]interface{}); ok {
+		if _, ok := data["metadata"]; ok {
+			data = inner
+		}
+	}
+	v, ok := data[key].(string)
+	if !ok {
+		return "", errors.Errorf("key %s doesn't exist in vault secret", key)
+	}
+	return v, nil
+}
+
+// doJSON sends the request and decodes the json response
+func doJSON(ctx context.Context, client *http.Client, req *http.Request, out interface{}) error {
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+		return errors.Errorf("status %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
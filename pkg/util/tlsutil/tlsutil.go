@@ -0,0 +1,191 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package tlsutil
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/iotexproject/iotex-core/pkg/log"
+)
+
+// ErrClientNotAllowed indicates that the client certificate is not in the allowlist
+var ErrClientNotAllowed = errors.New("client certificate is not allowed")
+
+// serverCerts holds the server certificate and the client CAs, which are reloaded from the files after the interval
+type serverCerts struct {
+	mu             sync.Mutex
+	certFile       string
+	keyFile        string
+	clientCAFile   string
+	allowedClients map[string]bool
+	reloadInterval time.Duration
+	now            func() time.Time
+	loadedAt       time.Time
+	cert           *tls.Certificate
+	clientCAs      *x509.CertPool
+}
+
+// NewServerConfig creates the tls config of a server with the certificate and key files. If clientCAFile is not
+// empty, the clients are required to present a certificate signed by the CAs in it, and if allowedClients is not
+// empty, the common name or one of the DNS names of the client certificate must be in it. The files are reloaded
+// after reloadInterval for certificate rotation, the previously loaded ones are kept if failed to reload
+func NewServerConfig(
+	certFile, keyFile, clientCAFile string,
+	allowedClients []string,
+	reloadInterval time.Duration,
+) (*tls.Config, error) {
+	sc, err := newServerCerts(certFile, keyFile, clientCAFile, allowedClients, reloadInterval, time.Now)
+	if err != nil {
+		return nil, err
+	}
+	cfg := &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		GetCertificate: sc.getCertificate,
+	}
+	if clientCAFile != "" {
+		// the client certificate is verified in VerifyPeerCertificate against the latest loaded CAs
+		cfg.ClientAuth = tls.RequireAnyClientCert
+		cfg.VerifyPeerCertificate = sc.verifyClient
+	}
+	return cfg, nil
+}
+
+// NewClientConfig creates the tls config of a client, which verifies the server certificate with the CAs in caFile,
+// or the system CAs if it is empty, and presents the client certificate if certFile is not empty
+func NewClientConfig(caFile, certFile, keyFile, serverName string) (*tls.Config, error) {
+	cfg := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: serverName,
+	}
+	if caFile != "" {
+		pool, err := loadCertPool(caFile)
+		if err != nil {
+			return nil, err
+		}
+		cfg.RootCAs = pool
+	}
+	if certFile != "" {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to load client certificate")
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	return cfg, nil
+}
+
+func newServerCerts(
+	certFile, keyFile, clientCAFile string,
+	allowedClients []string,
+	reloadInterval time.Duration,
+	now func() time.Time,
+) (*serverCerts, error) {
+	sc := &serverCerts{
+		certFile:       certFile,
+		keyFile:        keyFile,
+		clientCAFile:   clientCAFile,
+		allowedClients: make(map[string]bool, len(allowedClients)),
+		reloadInterval: reloadInterval,
+		now:            now,
+	}
+	for _, name := range allowedClients {
+		sc.allowedClients[name] = true
+	}
+	if err := sc.load(); err != nil {
+		return nil, err
+	}
+	return sc, nil
+}
+
+func (sc *serverCerts) load() error {
+	cert, err := tls.LoadX509KeyPair(sc.certFile, sc.keyFile)
+	if err != nil {
+		return errors.Wrap(err, "failed to load server certificate")
+	}
+	var pool *x509.CertPool
+	if sc.clientCAFile != "" {
+		if pool, err = loadCertPool(sc.clientCAFile); err != nil {
+			return err
+		}
+	}
+	sc.cert = &cert
+	sc.clientCAs = pool
+	sc.loadedAt = sc.now()
+	return nil
+}
+
+func (sc *serverCerts) current() (*tls.Certificate, *x509.CertPool) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	if sc.reloadInterval > 0 && sc.now().Sub(sc.loadedAt) >= sc.reloadInterval {
+		if err := sc.load(); err != nil {
+			log.L().Error("Failed to reload tls certificates.", zap.String("cert", sc.certFile), zap.Error(err))
+			// retry after another interval
+			sc.loadedAt = sc.now()
+		}
+	}
+	return sc.cert, sc.clientCAs
+}
+
+func (sc *serverCerts) getCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	cert, _ := sc.current()
+	return cert, nil
+}
+
+func (sc *serverCerts) verifyClient(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+	if len(rawCerts) == 0 {
+		return errors.New("no client certificate")
+	}
+	certs := make([]*x509.Certificate, len(rawCerts))
+	for i, raw := range rawCerts {
+		cert, err := x509.ParseCertificate(raw)
+		if err != nil {
+			return errors.Wrap(err, "failed to parse client certificate")
+		}
+		certs[i] = cert
+	}
+	_, roots := sc.current()
+	intermediates := x509.NewCertPool()
+	for _, cert := range certs[1:] {
+		intermediates.AddCert(cert)
+	}
+	if _, err := certs[0].Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}); err != nil {
+		return errors.Wrap(err, "failed to verify client certificate")
+	}
+	if len(sc.allowedClients) == 0 || sc.allowedClients[certs[0].Subject.CommonName] {
+		return nil
+	}
+	for _, name := range certs[0].DNSNames {
+		if sc.allowedClients[name] {
+			return nil
+		}
+	}
+	return errors.Wrapf(ErrClientNotAllowed, "client %s", certs[0].Subject.CommonName)
+}
+
+func loadCertPool(file string) (*x509.CertPool, error) {
+	pem, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read ca file %s", file)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, errors.Errorf("no valid certificate in ca file %s", file)
+	}
+	return pool, nil
+}
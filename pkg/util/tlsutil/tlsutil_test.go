@@ -0,0 +1,179 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package tlsutil
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+type testCert struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+func newTestCert(t *testing.T, cn string, parent *testCert, usage x509.ExtKeyUsage) *testCert {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	serial, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: cn},
+		DNSNames:     []string{cn + ".iotex.local"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+	}
+	signer, signerKey := tmpl, key
+	if parent == nil {
+		tmpl.IsCA = true
+		tmpl.BasicConstraintsValid = true
+		tmpl.KeyUsage |= x509.KeyUsageCertSign
+		tmpl.ExtKeyUsage = nil
+	} else {
+		signer, signerKey = parent.cert, parent.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, signer, &key.PublicKey, signerKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return &testCert{cert: cert, key: key}
+}
+
+func (c *testCert) write(t *testing.T, dir, name string) (string, string) {
+	certFile := filepath.Join(dir, name+".pem")
+	keyFile := filepath.Join(dir, name+".key")
+	require.NoError(t, ioutil.WriteFile(
+		certFile,
+		pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: c.cert.Raw}),
+		0600,
+	))
+	der, err := x509.MarshalECPrivateKey(c.key)
+	require.NoError(t, err)
+	require.NoError(t, ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}), 0600))
+	return certFile, keyFile
+}
+
+func handshake(serverCfg, clientCfg *tls.Config) error {
+	// TLS 1.2 to get the client certificate rejection on the client side during handshake
+	clientCfg.MaxVersion = tls.VersionTLS12
+	sc, cc := net.Pipe()
+	defer sc.Close()
+	defer cc.Close()
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- tls.Server(sc, serverCfg).Handshake()
+		sc.Close()
+	}()
+	clientErr := tls.Client(cc, clientCfg).Handshake()
+	if serverErr := <-errCh; serverErr != nil {
+		return serverErr
+	}
+	return clientErr
+}
+
+func TestServerConfig(t *testing.T) {
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir(os.TempDir(), "tlsutil")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	ca := newTestCert(t, "ca", nil, 0)
+	caFile, _ := ca.write(t, dir, "ca")
+	certFile, keyFile := newTestCert(t, "server", ca, x509.ExtKeyUsageServerAuth).write(t, dir, "server")
+	adminCert, adminKey := newTestCert(t, "admin", ca, x509.ExtKeyUsageClientAuth).write(t, dir, "admin")
+	userCert, userKey := newTestCert(t, "user", ca, x509.ExtKeyUsageClientAuth).write(t, dir, "user")
+	otherCA := newTestCert(t, "other", nil, 0)
+	otherCert, otherKey := newTestCert(t, "admin", otherCA, x509.ExtKeyUsageClientAuth).write(t, dir, "other")
+
+	_, err = NewServerConfig(certFile, "", "", nil, 0)
+	require.Error(err)
+	_, err = NewServerConfig(certFile, keyFile, keyFile, nil, 0)
+	require.Error(err)
+
+	// tls without client certificates
+	serverCfg, err := NewServerConfig(certFile, keyFile, "", nil, 0)
+	require.NoError(err)
+	clientCfg, err := NewClientConfig(caFile, "", "", "server.iotex.local")
+	require.NoError(err)
+	require.NoError(handshake(serverCfg, clientCfg))
+
+	// mutual tls with allowlist
+	serverCfg, err = NewServerConfig(certFile, keyFile, caFile, []string{"admin.iotex.local"}, 0)
+	require.NoError(err)
+	for _, test := range []struct {
+		cert, key string
+		ok        bool
+	}{
+		{"", "", false},
+		{adminCert, adminKey, true},
+		{userCert, userKey, false},
+		{otherCert, otherKey, false},
+	} {
+		clientCfg, err = NewClientConfig(caFile, test.cert, test.key, "server.iotex.local")
+		require.NoError(err)
+		err = handshake(serverCfg, clientCfg)
+		if test.ok {
+			require.NoError(err)
+		} else {
+			require.Error(err)
+		}
+	}
+	clientCfg, err = NewClientConfig(caFile, userCert, userKey, "server.iotex.local")
+	require.NoError(err)
+	require.Equal(ErrClientNotAllowed, errors.Cause(handshake(serverCfg, clientCfg)))
+}
+
+func TestServerCertsReload(t *testing.T) {
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir(os.TempDir(), "tlsutil")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	ca := newTestCert(t, "ca", nil, 0)
+	caFile, _ := ca.write(t, dir, "ca")
+	certFile, keyFile := newTestCert(t, "server", ca, x509.ExtKeyUsageServerAuth).write(t, dir, "server")
+
+	now := time.Unix(1612345678, 0)
+	sc, err := newServerCerts(certFile, keyFile, caFile, nil, time.Hour, func() time.Time { return now })
+	require.NoError(err)
+	cert, _ := sc.getCertificate(nil)
+
+	// rotate the certificate
+	newTestCert(t, "server", ca, x509.ExtKeyUsageServerAuth).write(t, dir, "server")
+	now = now.Add(time.Minute)
+	rotated, _ := sc.getCertificate(nil)
+	require.Equal(cert, rotated)
+	now = now.Add(time.Hour)
+	rotated, _ = sc.getCertificate(nil)
+	require.NotEqual(cert.Certificate[0], rotated.Certificate[0])
+
+	// keep the loaded certificate if failed to reload
+	require.NoError(ioutil.WriteFile(certFile, []byte("invalid"), 0600))
+	now = now.Add(time.Hour)
+	cert, _ = sc.getCertificate(nil)
+	require.Equal(rotated, cert)
+}
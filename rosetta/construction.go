@@ -0,0 +1,462 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package rosetta
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"strconv"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/iotexproject/go-pkgs/crypto"
+	"github.com/iotexproject/iotex-address/address"
+	"github.com/iotexproject/iotex-proto/golang/iotexapi"
+	"github.com/iotexproject/iotex-proto/golang/iotextypes"
+	"github.com/pkg/errors"
+
+	"github.com/iotexproject/iotex-core/action"
+)
+
+const (
+	_curveType     = "secp256k1"
+	_signatureType = "ecdsa_recovery"
+)
+
+// _transferType is the type of the operations of a native transfer, the only action could be constructed
+var _transferType = iotextypes.TransactionLogType_NATIVE_TRANSFER.String()
+
+type (
+	// PublicKey is a public key in hex
+	PublicKey struct {
+		HexBytes  string `json:"hex_bytes"`
+		CurveType string `json:"curve_type"`
+	}
+
+	// SigningPayload is the bytes to sign by the account
+	SigningPayload struct {
+		AccountIdentifier *AccountIdentifier `json:"account_identifier"`
+		HexBytes          string             `json:"hex_bytes"`
+		SignatureType     string             `json:"signature_type"`
+	}
+
+	// Signature is the signature of a signing payload
+	Signature struct {
+		SigningPayload *SigningPayload `json:"signing_payload"`
+		PublicKey      *PublicKey      `json:"public_key"`
+		SignatureType  string          `json:"signature_type"`
+		HexBytes       string          `json:"hex_bytes"`
+	}
+
+	// ConstructionDeriveRequest is the request of /construction/derive
+	ConstructionDeriveRequest struct {
+		NetworkIdentifier *NetworkIdentifier `json:"network_identifier"`
+		PublicKey         *PublicKey         `json:"public_key"`
+	}
+
+	// ConstructionPreprocessRequest is the request of /construction/preprocess
+	ConstructionPreprocessRequest struct {
+		NetworkIdentifier *NetworkIdentifier `json:"network_identifier"`
+		Operations        []*Operation       `json:"operations"`
+	}
+
+	// ConstructionMetadataRequest is the request of /construction/metadata
+	ConstructionMetadataRequest struct {
+		NetworkIdentifier *NetworkIdentifier `json:"network_identifier"`
+		Options           *TransferOptions   `json:"options"`
+	}
+
+	// ConstructionPayloadsRequest is the request of /construction/payloads
+	ConstructionPayloadsRequest struct {
+		NetworkIdentifier *NetworkIdentifier `json:"network_identifier"`
+		Operations        []*Operation       `json:"operations"`
+		Metadata          *TransferMetadata  `json:"metadata"`
+	}
+
+	// ConstructionCombineRequest is the request of /construction/combine
+	ConstructionCombineRequest struct {
+		NetworkIdentifier   *NetworkIdentifier `json:"network_identifier"`
+		UnsignedTransaction string             `json:"unsigned_transaction"`
+		Signatures          []*Signature       `json:"signatures"`
+	}
+
+	// ConstructionParseRequest is the request of /construction/parse
+	ConstructionParseRequest struct {
+		NetworkIdentifier *NetworkIdentifier `json:"network_identifier"`
+		Signed            bool               `json:"signed"`
+		Transaction       string             `json:"transaction"`
+	}
+
+	// ConstructionSignedRequest is the request of /construction/hash and /construction/submit
+	ConstructionSignedRequest struct {
+		NetworkIdentifier *NetworkIdentifier `json:"network_identifier"`
+		SignedTransaction string             `json:"signed_transaction"`
+	}
+
+	// TransferOptions is the native transfer parsed from the operations by /construction/preprocess
+	TransferOptions struct {
+		Sender    string `json:"sender"`
+		Recipient string `json:"recipient"`
+		Amount    string `json:"amount"`
+	}
+
+	// TransferMetadata is the chain data to construct the native transfer
+	TransferMetadata struct {
+		Nonce    uint64 `json:"nonce"`
+		GasLimit uint64 `json:"gas_limit"`
+		GasPrice string `json:"gas_price"`
+	}
+
+	// unsignedTransaction is the action core in hex with its sender, who is unknown from the core
+	unsignedTransaction struct {
+		Sender string `json:"sender"`
+		Core   string `json:"core"`
+	}
+
+	constructionDeriveResponse struct {
+		AccountIdentifier *AccountIdentifier `json:"account_identifier"`
+	}
+
+	constructionPreprocessResponse struct {
+		Options *TransferOptions `json:"options"`
+	}
+
+	constructionMetadataResponse struct {
+		Metadata     *TransferMetadata `json:"metadata"`
+		SuggestedFee []*Amount         `json:"suggested_fee"`
+	}
+
+	constructionPayloadsResponse struct {
+		UnsignedTransaction string            `json:"unsigned_transaction"`
+		Payloads            []*SigningPayload `json:"payloads"`
+	}
+
+	constructionCombineResponse struct {
+		SignedTransaction string `json:"signed_transaction"`
+	}
+
+	constructionParseResponse struct {
+		Operations               []*Operation         `json:"operations"`
+		AccountIdentifierSigners []*AccountIdentifier `json:"account_identifier_signers,omitempty"`
+	}
+
+	transactionIdentifierResponse struct {
+		TransactionIdentifier *TransactionIdentifier `json:"transaction_identifier"`
+	}
+)
+
+func (s *Server) constructionDerive(_ context.Context, body []byte) (interface{}, *Error) {
+	req := &ConstructionDeriveRequest{}
+	if err := s.decode(body, req, &req.NetworkIdentifier); err != nil {
+		return nil, err
+	}
+	if req.PublicKey == nil || req.PublicKey.CurveType != _curveType {
+		return nil, ErrInvalidRequest.withDetails(errors.Errorf("public key of curve %s is required", _curveType))
+	}
+	pk, err := publicKey(req.PublicKey.HexBytes)
+	if err != nil {
+		return nil, ErrInvalidRequest.withDetails(err)
+	}
+	addr, err := address.FromBytes(pk.Hash())
+	if err != nil {
+		return nil, ErrInvalidRequest.withDetails(err)
+	}
+	return &constructionDeriveResponse{AccountIdentifier: &AccountIdentifier{Address: addr.String()}}, nil
+}
+
+func (s *Server) constructionPreprocess(_ context.Context, body []byte) (interface{}, *Error) {
+	req := &ConstructionPreprocessRequest{}
+	if err := s.decode(body, req, &req.NetworkIdentifier); err != nil {
+		return nil, err
+	}
+	opts, rerr := transferOptions(req.Operations)
+	if rerr != nil {
+		return nil, rerr
+	}
+	return &constructionPreprocessResponse{Options: opts}, nil
+}
+
+func (s *Server) constructionMetadata(ctx context.Context, body []byte) (interface{}, *Error) {
+	req := &ConstructionMetadataRequest{}
+	if err := s.decode(body, req, &req.NetworkIdentifier); err != nil {
+		return nil, err
+	}
+	if req.Options == nil {
+		return nil, ErrInvalidRequest.withDetails(errors.New("missing options"))
+	}
+	acc, err := s.api.GetAccount(ctx, &iotexapi.GetAccountRequest{Address: req.Options.Sender})
+	if err != nil {
+		return nil, apiError(err)
+	}
+	gp, err := s.api.SuggestGasPrice(ctx, &iotexapi.SuggestGasPriceRequest{})
+	if err != nil {
+		return nil, apiError(err)
+	}
+	gasPrice := new(big.Int).SetUint64(gp.GetGasPrice())
+	fee := new(big.Int).Mul(gasPrice, new(big.Int).SetUint64(action.TransferBaseIntrinsicGas))
+	return &constructionMetadataResponse{
+		Metadata: &TransferMetadata{
+			Nonce:    acc.GetAccountMeta().GetPendingNonce(),
+			GasLimit: action.TransferBaseIntrinsicGas,
+			GasPrice: gasPrice.String(),
+		},
+		SuggestedFee: []*Amount{{Value: fee.String(), Currency: _currency}},
+	}, nil
+}
+
+func (s *Server) constructionPayloads(_ context.Context, body []byte) (interface{}, *Error) {
+	req := &ConstructionPayloadsRequest{}
+	if err := s.decode(body, req, &req.NetworkIdentifier); err != nil {
+		return nil, err
+	}
+	if req.Metadata == nil {
+		return nil, ErrInvalidRequest.withDetails(errors.New("missing metadata"))
+	}
+	opts, rerr := transferOptions(req.Operations)
+	if rerr != nil {
+		return nil, rerr
+	}
+	amount, _ := new(big.Int).SetString(opts.Amount, 10)
+	gasPrice, ok := new(big.Int).SetString(req.Metadata.GasPrice, 10)
+	if !ok {
+		return nil, ErrInvalidRequest.withDetails(errors.Errorf("invalid gas price %s", req.Metadata.GasPrice))
+	}
+	tsf, err := action.NewTransfer(req.Metadata.Nonce, amount, opts.Recipient, nil, req.Metadata.GasLimit, gasPrice)
+	if err != nil {
+		return nil, ErrInvalidTransaction.withDetails(err)
+	}
+	elp := (&action.EnvelopeBuilder{}).SetNonce(req.Metadata.Nonce).
+		SetGasLimit(req.Metadata.GasLimit).
+		SetGasPrice(gasPrice).
+		SetAction(tsf).Build()
+	unsigned, err := json.Marshal(&unsignedTransaction{
+		Sender: opts.Sender,
+		Core:   hex.EncodeToString(elp.Serialize()),
+	})
+	if err != nil {
+		return nil, ErrInvalidTransaction.withDetails(err)
+	}
+	h := elp.Hash()
+	return &constructionPayloadsResponse{
+		UnsignedTransaction: string(unsigned),
+		Payloads: []*SigningPayload{{
+			AccountIdentifier: &AccountIdentifier{Address: opts.Sender},
+			HexBytes:          hex.EncodeToString(h[:]),
+			SignatureType:     _signatureType,
+		}},
+	}, nil
+}
+
+func (s *Server) constructionCombine(_ context.Context, body []byte) (interface{}, *Error) {
+	req := &ConstructionCombineRequest{}
+	if err := s.decode(body, req, &req.NetworkIdentifier); err != nil {
+		return nil, err
+	}
+	elp, sender, err := loadUnsigned(req.UnsignedTransaction)
+	if err != nil {
+		return nil, ErrInvalidTransaction.withDetails(err)
+	}
+	if len(req.Signatures) != 1 || req.Signatures[0].PublicKey == nil {
+		return nil, ErrInvalidRequest.withDetails(errors.New("one signature of the sender is required"))
+	}
+	sig := req.Signatures[0]
+	pk, err := publicKey(sig.PublicKey.HexBytes)
+	if err != nil {
+		return nil, ErrInvalidRequest.withDetails(err)
+	}
+	if addr, err := address.FromBytes(pk.Hash()); err != nil || addr.String() != sender {
+		return nil, ErrInvalidRequest.withDetails(errors.Errorf("signer is not the sender %s", sender))
+	}
+	sigBytes, err := hex.DecodeString(sig.HexBytes)
+	if err != nil {
+		return nil, ErrInvalidRequest.withDetails(err)
+	}
+	h := elp.Hash()
+	if !pk.Verify(h[:], sigBytes) {
+		return nil, ErrInvalidRequest.withDetails(errors.New("invalid signature"))
+	}
+	signed, err := proto.Marshal(&iotextypes.Action{
+		Core:         elp.Proto(),
+		SenderPubKey: pk.Bytes(),
+		Signature:    sigBytes,
+	})
+	if err != nil {
+		return nil, ErrInvalidTransaction.withDetails(err)
+	}
+	return &constructionCombineResponse{SignedTransaction: hex.EncodeToString(signed)}, nil
+}
+
+func (s *Server) constructionParse(_ context.Context, body []byte) (interface{}, *Error) {
+	req := &ConstructionParseRequest{}
+	if err := s.decode(body, req, &req.NetworkIdentifier); err != nil {
+		return nil, err
+	}
+	if !req.Signed {
+		elp, sender, err := loadUnsigned(req.Transaction)
+		if err != nil {
+			return nil, ErrInvalidTransaction.withDetails(err)
+		}
+		ops, err := transferOperations(elp, sender, "")
+		if err != nil {
+			return nil, ErrUnsupportedOperation.withDetails(err)
+		}
+		return &constructionParseResponse{Operations: ops}, nil
+	}
+	selp, err := loadSigned(req.Transaction)
+	if err != nil {
+		return nil, ErrInvalidTransaction.withDetails(err)
+	}
+	sender, err := senderAddress(selp)
+	if err != nil {
+		return nil, ErrInvalidTransaction.withDetails(err)
+	}
+	ops, err := transferOperations(&selp.Envelope, sender, "")
+	if err != nil {
+		return nil, ErrUnsupportedOperation.withDetails(err)
+	}
+	return &constructionParseResponse{
+		Operations:               ops,
+		AccountIdentifierSigners: []*AccountIdentifier{{Address: sender}},
+	}, nil
+}
+
+func (s *Server) constructionHash(_ context.Context, body []byte) (interface{}, *Error) {
+	req := &ConstructionSignedRequest{}
+	if err := s.decode(body, req, &req.NetworkIdentifier); err != nil {
+		return nil, err
+	}
+	selp, err := loadSigned(req.SignedTransaction)
+	if err != nil {
+		return nil, ErrInvalidTransaction.withDetails(err)
+	}
+	h := selp.Hash()
+	return &transactionIdentifierResponse{
+		TransactionIdentifier: &TransactionIdentifier{Hash: hex.EncodeToString(h[:])},
+	}, nil
+}
+
+func (s *Server) constructionSubmit(ctx context.Context, body []byte) (interface{}, *Error) {
+	req := &ConstructionSignedRequest{}
+	if err := s.decode(body, req, &req.NetworkIdentifier); err != nil {
+		return nil, err
+	}
+	selp, err := loadSigned(req.SignedTransaction)
+	if err != nil {
+		return nil, ErrInvalidTransaction.withDetails(err)
+	}
+	res, err := s.api.SendAction(ctx, &iotexapi.SendActionRequest{Action: selp.Proto()})
+	if err != nil {
+		return nil, ErrSubmitFailed.withDetails(err)
+	}
+	return &transactionIdentifierResponse{
+		TransactionIdentifier: &TransactionIdentifier{Hash: res.GetActionHash()},
+	}, nil
+}
+
+// transferOptions parses the native transfer from the operations, which are the debit of the sender and the credit of
+// the recipient of the same amount
+func transferOptions(ops []*Operation) (*TransferOptions, *Error) {
+	if len(ops) != 2 {
+		return nil, ErrUnsupportedOperation.withDetails(errors.Errorf("a transfer has 2 operations, got %d", len(ops)))
+	}
+	var (
+		amounts [2]*big.Int
+		addrs   [2]string
+	)
+	for i, op := range ops {
+		if op == nil || op.Type != _transferType || op.Account == nil || op.Amount == nil {
+			return nil, ErrUnsupportedOperation.withDetails(errors.Errorf("operation %d is not a %s", i, _transferType))
+		}
+		if op.Amount.Currency == nil || *op.Amount.Currency != *_currency {
+			return nil, ErrUnsupportedOperation.withDetails(errors.Errorf("operation %d is not in %s", i, _symbol))
+		}
+		amount, ok := new(big.Int).SetString(op.Amount.Value, 10)
+		if !ok {
+			return nil, ErrInvalidRequest.withDetails(errors.Errorf("invalid amount %s", op.Amount.Value))
+		}
+		if _, err := address.FromString(op.Account.Address); err != nil {
+			return nil, ErrInvalidRequest.withDetails(err)
+		}
+		amounts[i], addrs[i] = amount, op.Account.Address
+	}
+	if amounts[0].Sign() > 0 {
+		amounts[0], amounts[1] = amounts[1], amounts[0]
+		addrs[0], addrs[1] = addrs[1], addrs[0]
+	}
+	if amounts[0].Sign() >= 0 || new(big.Int).Add(amounts[0], amounts[1]).Sign() != 0 {
+		return nil, ErrUnsupportedOperation.withDetails(errors.New("the debit and the credit of a transfer must match"))
+	}
+	return &TransferOptions{
+		Sender:    addrs[0],
+		Recipient: addrs[1],
+		Amount:    amounts[1].String(),
+	}, nil
+}
+
+// transferOperations returns the operations of the native transfer in the envelope
+func transferOperations(elp *action.Envelope, sender, status string) ([]*Operation, error) {
+	tsf, ok := elp.Action().(*action.Transfer)
+	if !ok {
+		return nil, errors.Errorf("action of nonce %d is not a transfer", elp.Nonce())
+	}
+	return debitAndCredit(0, _transferType, status, sender, tsf.Recipient(), tsf.Amount()), nil
+}
+
+// loadUnsigned loads the envelope and its sender from the unsigned transaction
+func loadUnsigned(s string) (*action.Envelope, string, error) {
+	tx := &unsignedTransaction{}
+	if err := json.Unmarshal([]byte(s), tx); err != nil {
+		return nil, "", err
+	}
+	data, err := hex.DecodeString(tx.Core)
+	if err != nil {
+		return nil, "", err
+	}
+	core := &iotextypes.ActionCore{}
+	if err := proto.Unmarshal(data, core); err != nil {
+		return nil, "", err
+	}
+	elp := &action.Envelope{}
+	if err := elp.LoadProto(core); err != nil {
+		return nil, "", err
+	}
+	return elp, tx.Sender, nil
+}
+
+// loadSigned loads the action from the signed transaction, which is the action proto in hex
+func loadSigned(s string) (*action.SealedEnvelope, error) {
+	data, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	act := &iotextypes.Action{}
+	if err := proto.Unmarshal(data, act); err != nil {
+		return nil, err
+	}
+	selp := &action.SealedEnvelope{}
+	if err := selp.LoadProto(act); err != nil {
+		return nil, err
+	}
+	return selp, nil
+}
+
+func senderAddress(selp *action.SealedEnvelope) (string, error) {
+	addr, err := address.FromBytes(selp.SrcPubkey().Hash())
+	if err != nil {
+		return "", err
+	}
+	return addr.String(), nil
+}
+
+func publicKey(s string) (crypto.PublicKey, error) {
+	data, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid public key "+strconv.Quote(s))
+	}
+	return crypto.BytesToPublicKey(data)
+}
@@ -0,0 +1,380 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package rosetta
+
+import (
+	"context"
+	"encoding/hex"
+	"math/big"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/iotexproject/iotex-proto/golang/iotexapi"
+	"github.com/iotexproject/iotex-proto/golang/iotextypes"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/iotexproject/iotex-core/action"
+)
+
+type (
+	// BlockRequest is the request of /block
+	BlockRequest struct {
+		NetworkIdentifier *NetworkIdentifier      `json:"network_identifier"`
+		BlockIdentifier   *PartialBlockIdentifier `json:"block_identifier"`
+	}
+
+	// BlockTransactionRequest is the request of /block/transaction
+	BlockTransactionRequest struct {
+		NetworkIdentifier     *NetworkIdentifier     `json:"network_identifier"`
+		BlockIdentifier       *BlockIdentifier       `json:"block_identifier"`
+		TransactionIdentifier *TransactionIdentifier `json:"transaction_identifier"`
+	}
+
+	// AccountBalanceRequest is the request of /account/balance
+	AccountBalanceRequest struct {
+		NetworkIdentifier *NetworkIdentifier      `json:"network_identifier"`
+		AccountIdentifier *AccountIdentifier      `json:"account_identifier"`
+		BlockIdentifier   *PartialBlockIdentifier `json:"block_identifier,omitempty"`
+	}
+
+	// MempoolTransactionRequest is the request of /mempool/transaction
+	MempoolTransactionRequest struct {
+		NetworkIdentifier     *NetworkIdentifier     `json:"network_identifier"`
+		TransactionIdentifier *TransactionIdentifier `json:"transaction_identifier"`
+	}
+
+	blockResponse struct {
+		Block *Block `json:"block"`
+	}
+
+	blockTransactionResponse struct {
+		Transaction *Transaction `json:"transaction"`
+	}
+
+	accountBalanceResponse struct {
+		BlockIdentifier *BlockIdentifier       `json:"block_identifier"`
+		Balances        []*Amount              `json:"balances"`
+		Metadata        map[string]interface{} `json:"metadata,omitempty"`
+	}
+
+	mempoolResponse struct {
+		TransactionIdentifiers []*TransactionIdentifier `json:"transaction_identifiers"`
+	}
+
+	mempoolTransactionResponse struct {
+		Transaction *Transaction `json:"transaction"`
+	}
+)
+
+func (s *Server) block(ctx context.Context, body []byte) (interface{}, *Error) {
+	req := &BlockRequest{}
+	if err := s.decode(body, req, &req.NetworkIdentifier); err != nil {
+		return nil, err
+	}
+	meta, rerr := s.partialBlockMeta(ctx, req.BlockIdentifier)
+	if rerr != nil {
+		return nil, rerr
+	}
+	blk, rerr := s.rosettaBlock(ctx, meta)
+	if rerr != nil {
+		return nil, rerr
+	}
+	return &blockResponse{Block: blk}, nil
+}
+
+func (s *Server) blockTransaction(ctx context.Context, body []byte) (interface{}, *Error) {
+	req := &BlockTransactionRequest{}
+	if err := s.decode(body, req, &req.NetworkIdentifier); err != nil {
+		return nil, err
+	}
+	if req.BlockIdentifier == nil || req.TransactionIdentifier == nil {
+		return nil, ErrInvalidRequest.withDetails(errors.New("missing block or transaction identifier"))
+	}
+	index := req.BlockIdentifier.Index
+	meta, rerr := s.partialBlockMeta(ctx, &PartialBlockIdentifier{Index: &index})
+	if rerr != nil {
+		return nil, rerr
+	}
+	if meta.Hash != req.BlockIdentifier.Hash {
+		return nil, ErrNotFound.withDetails(errors.Errorf("block %d is not %s", index, req.BlockIdentifier.Hash))
+	}
+	blk, rerr := s.rosettaBlock(ctx, meta)
+	if rerr != nil {
+		return nil, rerr
+	}
+	for _, tx := range blk.Transactions {
+		if tx.TransactionIdentifier.Hash == req.TransactionIdentifier.Hash {
+			return &blockTransactionResponse{Transaction: tx}, nil
+		}
+	}
+	return nil, ErrNotFound.withDetails(errors.Errorf("transaction %s is not in block %d", req.TransactionIdentifier.Hash, index))
+}
+
+func (s *Server) accountBalance(ctx context.Context, body []byte) (interface{}, *Error) {
+	req := &AccountBalanceRequest{}
+	if err := s.decode(body, req, &req.NetworkIdentifier); err != nil {
+		return nil, err
+	}
+	if req.AccountIdentifier == nil {
+		return nil, ErrInvalidRequest.withDetails(errors.New("missing account identifier"))
+	}
+	res, err := s.api.GetAccount(ctx, &iotexapi.GetAccountRequest{Address: req.AccountIdentifier.Address})
+	if err != nil {
+		return nil, apiError(err)
+	}
+	blkID := res.GetBlockIdentifier()
+	if bid := req.BlockIdentifier; bid != nil {
+		// the balance is read at the tip only
+		if (bid.Index != nil && uint64(*bid.Index) != blkID.GetHeight()) || (bid.Hash != nil && *bid.Hash != blkID.GetHash()) {
+			return nil, ErrInvalidRequest.withDetails(errors.New("historical balance lookup is not supported"))
+		}
+	}
+	return &accountBalanceResponse{
+		BlockIdentifier: &BlockIdentifier{
+			Index: int64(blkID.GetHeight()),
+			Hash:  blkID.GetHash(),
+		},
+		Balances: []*Amount{{Value: res.GetAccountMeta().GetBalance(), Currency: _currency}},
+		Metadata: map[string]interface{}{"nonce": res.GetAccountMeta().GetNonce()},
+	}, nil
+}
+
+func (s *Server) mempool(ctx context.Context, body []byte) (interface{}, *Error) {
+	req := &NetworkRequest{}
+	if err := s.decode(body, req, &req.NetworkIdentifier); err != nil {
+		return nil, err
+	}
+	res, err := s.api.GetActPoolActions(ctx, &iotexapi.GetActPoolActionsRequest{})
+	if err != nil {
+		return nil, apiError(err)
+	}
+	ret := &mempoolResponse{TransactionIdentifiers: []*TransactionIdentifier{}}
+	for _, act := range res.GetActions() {
+		h, err := actionHash(act)
+		if err != nil {
+			return nil, ErrInvalidTransaction.withDetails(err)
+		}
+		ret.TransactionIdentifiers = append(ret.TransactionIdentifiers, &TransactionIdentifier{Hash: h})
+	}
+	return ret, nil
+}
+
+func (s *Server) mempoolTransaction(ctx context.Context, body []byte) (interface{}, *Error) {
+	req := &MempoolTransactionRequest{}
+	if err := s.decode(body, req, &req.NetworkIdentifier); err != nil {
+		return nil, err
+	}
+	if req.TransactionIdentifier == nil {
+		return nil, ErrInvalidRequest.withDetails(errors.New("missing transaction identifier"))
+	}
+	res, err := s.api.GetActPoolActions(ctx, &iotexapi.GetActPoolActionsRequest{
+		ActionHashes: []string{req.TransactionIdentifier.Hash},
+	})
+	if err != nil {
+		return nil, apiError(err)
+	}
+	if len(res.GetActions()) == 0 {
+		return nil, ErrNotFound
+	}
+	selp := action.SealedEnvelope{}
+	if err := selp.LoadProto(res.GetActions()[0]); err != nil {
+		return nil, ErrInvalidTransaction.withDetails(err)
+	}
+	sender, err := senderAddress(&selp)
+	if err != nil {
+		return nil, ErrInvalidTransaction.withDetails(err)
+	}
+	// the balance changes of a pending action are not known yet, except the native transfer
+	ops, err := transferOperations(&selp.Envelope, sender, "")
+	if err != nil {
+		ops = []*Operation{}
+	}
+	return &mempoolTransactionResponse{Transaction: &Transaction{
+		TransactionIdentifier: req.TransactionIdentifier,
+		Operations:            ops,
+	}}, nil
+}
+
+// partialBlockMeta returns the meta of the block by the index or the hash, or the tip block if neither is set
+func (s *Server) partialBlockMeta(ctx context.Context, bid *PartialBlockIdentifier) (*iotextypes.BlockMeta, *Error) {
+	switch {
+	case bid != nil && bid.Index != nil:
+		if *bid.Index < _genesisHeight {
+			return nil, ErrNotFound.withDetails(errors.Errorf("block %d does not exist", *bid.Index))
+		}
+		meta, rerr := s.blockMeta(ctx, uint64(*bid.Index))
+		if rerr != nil {
+			return nil, rerr
+		}
+		if bid.Hash != nil && *bid.Hash != meta.Hash {
+			return nil, ErrNotFound.withDetails(errors.Errorf("block %d is not %s", *bid.Index, *bid.Hash))
+		}
+		return meta, nil
+	case bid != nil && bid.Hash != nil:
+		res, err := s.api.GetBlockMetas(ctx, &iotexapi.GetBlockMetasRequest{
+			Lookup: &iotexapi.GetBlockMetasRequest_ByHash{
+				ByHash: &iotexapi.GetBlockMetaByHashRequest{BlkHash: *bid.Hash},
+			},
+		})
+		if err != nil {
+			return nil, apiError(err)
+		}
+		if len(res.GetBlkMetas()) == 0 {
+			return nil, ErrNotFound
+		}
+		return res.GetBlkMetas()[0], nil
+	default:
+		res, err := s.api.GetChainMeta(ctx, &iotexapi.GetChainMetaRequest{})
+		if err != nil {
+			return nil, ErrUnavailable.withDetails(err)
+		}
+		return s.blockMeta(ctx, res.GetChainMeta().GetHeight())
+	}
+}
+
+func (s *Server) blockMeta(ctx context.Context, height uint64) (*iotextypes.BlockMeta, *Error) {
+	res, err := s.api.GetBlockMetas(ctx, &iotexapi.GetBlockMetasRequest{
+		Lookup: &iotexapi.GetBlockMetasRequest_ByIndex{
+			ByIndex: &iotexapi.GetBlockMetasByIndexRequest{Start: height, Count: 1},
+		},
+	})
+	if err != nil {
+		return nil, apiError(err)
+	}
+	if len(res.GetBlkMetas()) == 0 {
+		return nil, ErrNotFound.withDetails(errors.Errorf("block %d does not exist", height))
+	}
+	return res.GetBlkMetas()[0], nil
+}
+
+// rosettaBlock returns the block with the operations of each action mapped from its transaction logs
+func (s *Server) rosettaBlock(ctx context.Context, meta *iotextypes.BlockMeta) (*Block, *Error) {
+	res, err := s.api.GetRawBlocks(ctx, &iotexapi.GetRawBlocksRequest{
+		StartHeight:         meta.Height,
+		Count:               1,
+		WithReceipts:        true,
+		WithTransactionLogs: true,
+	})
+	if err != nil {
+		return nil, apiError(err)
+	}
+	if len(res.GetBlocks()) == 0 {
+		return nil, ErrNotFound.withDetails(errors.Errorf("block %d does not exist", meta.Height))
+	}
+	info := res.GetBlocks()[0]
+	if info.GetTransactionLogs() == nil && len(info.GetBlock().GetBody().GetActions()) > 0 {
+		return nil, ErrUnavailable.withDetails(errors.New("transaction logs are not available on the node"))
+	}
+	logs := make(map[string][]*iotextypes.TransactionLog_Transaction)
+	for _, l := range info.GetTransactionLogs().GetLogs() {
+		logs[hex.EncodeToString(l.GetActionHash())] = l.GetTransactions()
+	}
+	receipts := make(map[string]*iotextypes.Receipt)
+	for _, r := range info.GetReceipts() {
+		receipts[hex.EncodeToString(r.GetActHash())] = r
+	}
+	blk := &Block{
+		BlockIdentifier:       blockIdentifier(meta),
+		ParentBlockIdentifier: &BlockIdentifier{Index: int64(meta.Height) - 1, Hash: meta.PreviousBlockHash},
+		Timestamp:             timestamp(meta),
+		Transactions:          []*Transaction{},
+	}
+	if meta.Height == _genesisHeight {
+		// the parent of the genesis block is itself
+		blk.ParentBlockIdentifier = blk.BlockIdentifier
+	}
+	for _, act := range info.GetBlock().GetBody().GetActions() {
+		h, err := actionHash(act)
+		if err != nil {
+			return nil, ErrInvalidTransaction.withDetails(err)
+		}
+		tx := &Transaction{
+			TransactionIdentifier: &TransactionIdentifier{Hash: h},
+			Operations:            transactionLogOperations(logs[h]),
+		}
+		if r, ok := receipts[h]; ok {
+			tx.Metadata = map[string]interface{}{
+				"receipt_status": r.GetStatus(),
+				"gas_consumed":   r.GetGasConsumed(),
+			}
+		}
+		blk.Transactions = append(blk.Transactions, tx)
+	}
+	return blk, nil
+}
+
+// transactionLogOperations maps each transaction log to a debit of the sender and a credit of the recipient
+func transactionLogOperations(txs []*iotextypes.TransactionLog_Transaction) []*Operation {
+	ops := make([]*Operation, 0, 2*len(txs))
+	for _, tx := range txs {
+		amount, ok := new(big.Int).SetString(tx.GetAmount(), 10)
+		if !ok {
+			amount = big.NewInt(0)
+		}
+		ops = append(ops, debitAndCredit(int64(len(ops)), tx.GetType().String(), _statusSuccess, tx.GetSender(), tx.GetRecipient(), amount)...)
+	}
+	return ops
+}
+
+// debitAndCredit returns the operations moving the amount from the sender to the recipient, starting at the index
+func debitAndCredit(index int64, typ, status, sender, recipient string, amount *big.Int) []*Operation {
+	return []*Operation{
+		{
+			OperationIdentifier: &OperationIdentifier{Index: index},
+			Type:                typ,
+			Status:              status,
+			Account:             &AccountIdentifier{Address: sender},
+			Amount:              &Amount{Value: new(big.Int).Neg(amount).String(), Currency: _currency},
+		},
+		{
+			OperationIdentifier: &OperationIdentifier{Index: index + 1},
+			RelatedOperations:   []*OperationIdentifier{{Index: index}},
+			Type:                typ,
+			Status:              status,
+			Account:             &AccountIdentifier{Address: recipient},
+			Amount:              &Amount{Value: amount.String(), Currency: _currency},
+		},
+	}
+}
+
+func blockIdentifier(meta *iotextypes.BlockMeta) *BlockIdentifier {
+	return &BlockIdentifier{
+		Index: int64(meta.Height),
+		Hash:  meta.Hash,
+	}
+}
+
+// timestamp returns the timestamp of the block in milliseconds
+func timestamp(meta *iotextypes.BlockMeta) int64 {
+	ts, err := ptypes.Timestamp(meta.Timestamp)
+	if err != nil {
+		return 0
+	}
+	return ts.UnixNano() / 1e6
+}
+
+func actionHash(act *iotextypes.Action) (string, error) {
+	selp := action.SealedEnvelope{}
+	if err := selp.LoadProto(act); err != nil {
+		return "", err
+	}
+	h := selp.Hash()
+	return hex.EncodeToString(h[:]), nil
+}
+
+// apiError converts the error of the api server to the Rosetta error
+func apiError(err error) *Error {
+	switch status.Code(err) {
+	case codes.NotFound:
+		return ErrNotFound.withDetails(err)
+	case codes.InvalidArgument:
+		return ErrInvalidRequest.withDetails(err)
+	default:
+		return ErrUnavailable.withDetails(err)
+	}
+}
@@ -0,0 +1,368 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+// Package rosetta serves the Rosetta data and construction api (https://www.rosetta-api.org) from the node, so that
+// exchanges could integrate the chain with their standard Rosetta tooling. The balance changes of an action are the
+// operations of the transaction, which are mapped from the transaction logs of the action, including the native
+// transfers, the gas fees and the staking operations. So the transaction logs must be enabled on the node. The genesis
+// balances are not in any block, so they have to be bootstrapped from the genesis config by the integrators.
+package rosetta
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/iotexproject/iotex-proto/golang/iotexapi"
+	"github.com/iotexproject/iotex-proto/golang/iotextypes"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/pkg/log"
+	"github.com/iotexproject/iotex-core/pkg/util/httputil"
+	"github.com/iotexproject/iotex-core/pkg/version"
+)
+
+const (
+	_rosettaVersion = "1.4.10"
+	_blockchain     = "IoTeX"
+	_symbol         = "IOTX"
+	_decimals       = 18
+	// _statusSuccess is the status of the operations, which are the balance changes already applied
+	_statusSuccess = "SUCCESS"
+	// _genesisHeight is the height of the first block, since the genesis block is not stored in the chain
+	_genesisHeight = 1
+)
+
+// errors of the Rosetta api
+var (
+	ErrInvalidRequest       = &Error{Code: 1, Message: "invalid request"}
+	ErrUnsupportedNetwork   = &Error{Code: 2, Message: "unsupported network"}
+	ErrNotFound             = &Error{Code: 3, Message: "not found"}
+	ErrUnavailable          = &Error{Code: 4, Message: "node is unavailable", Retriable: true}
+	ErrUnsupportedOperation = &Error{Code: 5, Message: "unsupported operations"}
+	ErrInvalidTransaction   = &Error{Code: 6, Message: "invalid transaction"}
+	ErrSubmitFailed         = &Error{Code: 7, Message: "failed to submit transaction"}
+
+	_errors = []*Error{
+		ErrInvalidRequest,
+		ErrUnsupportedNetwork,
+		ErrNotFound,
+		ErrUnavailable,
+		ErrUnsupportedOperation,
+		ErrInvalidTransaction,
+		ErrSubmitFailed,
+	}
+
+	_currency = &Currency{Symbol: _symbol, Decimals: _decimals}
+)
+
+type (
+	// APIServer is the api of the node the Rosetta api reads from and submits to
+	APIServer interface {
+		GetChainMeta(context.Context, *iotexapi.GetChainMetaRequest) (*iotexapi.GetChainMetaResponse, error)
+		GetBlockMetas(context.Context, *iotexapi.GetBlockMetasRequest) (*iotexapi.GetBlockMetasResponse, error)
+		GetRawBlocks(context.Context, *iotexapi.GetRawBlocksRequest) (*iotexapi.GetRawBlocksResponse, error)
+		GetAccount(context.Context, *iotexapi.GetAccountRequest) (*iotexapi.GetAccountResponse, error)
+		GetActPoolActions(context.Context, *iotexapi.GetActPoolActionsRequest) (*iotexapi.GetActPoolActionsResponse, error)
+		SuggestGasPrice(context.Context, *iotexapi.SuggestGasPriceRequest) (*iotexapi.SuggestGasPriceResponse, error)
+		SendAction(context.Context, *iotexapi.SendActionRequest) (*iotexapi.SendActionResponse, error)
+	}
+
+	// Server is the http endpoint of the Rosetta api
+	Server struct {
+		cfg    config.Rosetta
+		api    APIServer
+		server http.Server
+	}
+
+	// Error is the error of the Rosetta api
+	Error struct {
+		Code      int32                  `json:"code"`
+		Message   string                 `json:"message"`
+		Retriable bool                   `json:"retriable"`
+		Details   map[string]interface{} `json:"details,omitempty"`
+	}
+
+	// NetworkIdentifier identifies the network
+	NetworkIdentifier struct {
+		Blockchain string `json:"blockchain"`
+		Network    string `json:"network"`
+	}
+
+	// BlockIdentifier identifies a block
+	BlockIdentifier struct {
+		Index int64  `json:"index"`
+		Hash  string `json:"hash"`
+	}
+
+	// PartialBlockIdentifier identifies a block by either the index or the hash, or the tip if neither is set
+	PartialBlockIdentifier struct {
+		Index *int64  `json:"index,omitempty"`
+		Hash  *string `json:"hash,omitempty"`
+	}
+
+	// TransactionIdentifier identifies a transaction, which is an action
+	TransactionIdentifier struct {
+		Hash string `json:"hash"`
+	}
+
+	// AccountIdentifier identifies an account
+	AccountIdentifier struct {
+		Address string `json:"address"`
+	}
+
+	// Currency is the currency of the amounts
+	Currency struct {
+		Symbol   string `json:"symbol"`
+		Decimals int32  `json:"decimals"`
+	}
+
+	// Amount is an amount of the currency in the smallest unit, negative for debits
+	Amount struct {
+		Value    string    `json:"value"`
+		Currency *Currency `json:"currency"`
+	}
+
+	// OperationIdentifier identifies an operation in a transaction
+	OperationIdentifier struct {
+		Index int64 `json:"index"`
+	}
+
+	// Operation is a balance change of an account
+	Operation struct {
+		OperationIdentifier *OperationIdentifier   `json:"operation_identifier"`
+		RelatedOperations   []*OperationIdentifier `json:"related_operations,omitempty"`
+		Type                string                 `json:"type"`
+		Status              string                 `json:"status,omitempty"`
+		Account             *AccountIdentifier     `json:"account,omitempty"`
+		Amount              *Amount                `json:"amount,omitempty"`
+	}
+
+	// Transaction is an action with its balance changes
+	Transaction struct {
+		TransactionIdentifier *TransactionIdentifier `json:"transaction_identifier"`
+		Operations            []*Operation           `json:"operations"`
+		Metadata              map[string]interface{} `json:"metadata,omitempty"`
+	}
+
+	// Block is a block with its transactions
+	Block struct {
+		BlockIdentifier       *BlockIdentifier `json:"block_identifier"`
+		ParentBlockIdentifier *BlockIdentifier `json:"parent_block_identifier"`
+		Timestamp             int64            `json:"timestamp"`
+		Transactions          []*Transaction   `json:"transactions"`
+	}
+
+	// NetworkRequest is the request of the network endpoints
+	NetworkRequest struct {
+		NetworkIdentifier *NetworkIdentifier `json:"network_identifier"`
+	}
+
+	networkListResponse struct {
+		NetworkIdentifiers []*NetworkIdentifier `json:"network_identifiers"`
+	}
+
+	networkStatusResponse struct {
+		CurrentBlockIdentifier *BlockIdentifier `json:"current_block_identifier"`
+		CurrentBlockTimestamp  int64            `json:"current_block_timestamp"`
+		GenesisBlockIdentifier *BlockIdentifier `json:"genesis_block_identifier"`
+		Peers                  []interface{}    `json:"peers"`
+	}
+
+	versionInfo struct {
+		RosettaVersion string `json:"rosetta_version"`
+		NodeVersion    string `json:"node_version"`
+	}
+
+	operationStatus struct {
+		Status     string `json:"status"`
+		Successful bool   `json:"successful"`
+	}
+
+	allow struct {
+		OperationStatuses       []*operationStatus `json:"operation_statuses"`
+		OperationTypes          []string           `json:"operation_types"`
+		Errors                  []*Error           `json:"errors"`
+		HistoricalBalanceLookup bool               `json:"historical_balance_lookup"`
+	}
+
+	networkOptionsResponse struct {
+		Version *versionInfo `json:"version"`
+		Allow   *allow       `json:"allow"`
+	}
+)
+
+// Error returns the message of the error
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// withDetails returns a copy of the error with the cause in the details
+func (e *Error) withDetails(err error) *Error {
+	ret := *e
+	ret.Details = map[string]interface{}{"error": err.Error()}
+	return &ret
+}
+
+// NewServer creates the Rosetta api reading from the api of the node
+func NewServer(cfg config.Rosetta, api APIServer) *Server {
+	s := &Server{
+		cfg: cfg,
+		api: api,
+	}
+	mux := http.NewServeMux()
+	handlers := map[string]func(context.Context, []byte) (interface{}, *Error){
+		"/network/list":            s.networkList,
+		"/network/status":          s.networkStatus,
+		"/network/options":         s.networkOptions,
+		"/block":                   s.block,
+		"/block/transaction":       s.blockTransaction,
+		"/account/balance":         s.accountBalance,
+		"/mempool":                 s.mempool,
+		"/mempool/transaction":     s.mempoolTransaction,
+		"/construction/derive":     s.constructionDerive,
+		"/construction/preprocess": s.constructionPreprocess,
+		"/construction/metadata":   s.constructionMetadata,
+		"/construction/payloads":   s.constructionPayloads,
+		"/construction/combine":    s.constructionCombine,
+		"/construction/parse":      s.constructionParse,
+		"/construction/hash":       s.constructionHash,
+		"/construction/submit":     s.constructionSubmit,
+	}
+	for path, h := range handlers {
+		mux.Handle(path, handler(h))
+	}
+	s.server = httputil.Server(fmt.Sprintf(":%d", cfg.Port), mux)
+	return s
+}
+
+// Start starts the http endpoint of the Rosetta api
+func (s *Server) Start(_ context.Context) error {
+	ln, err := httputil.LimitListener(s.server.Addr)
+	if err != nil {
+		return errors.Wrap(err, "failed to listen on rosetta port")
+	}
+	go func() {
+		if err := s.server.Serve(ln); err != nil {
+			log.L().Info("Rosetta server stopped.", zap.Error(err))
+		}
+	}()
+	log.L().Info("Rosetta server started.", zap.Int("port", s.cfg.Port))
+	return nil
+}
+
+// Stop stops the http endpoint of the Rosetta api
+func (s *Server) Stop(ctx context.Context) error { return s.server.Shutdown(ctx) }
+
+// handler serves a Rosetta endpoint, which takes the request json in the body by POST and responds json, with status
+// 500 and the error json on failure
+func handler(h func(context.Context, []byte) (interface{}, *Error)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		var body json.RawMessage
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			writeJSON(w, http.StatusInternalServerError, ErrInvalidRequest.withDetails(err))
+			return
+		}
+		res, rerr := h(r.Context(), body)
+		if rerr != nil {
+			writeJSON(w, http.StatusInternalServerError, rerr)
+			return
+		}
+		writeJSON(w, http.StatusOK, res)
+	})
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.L().Debug("Error when writing rosetta response.", zap.Error(err))
+	}
+}
+
+// decode decodes the request and checks its network identifier
+func (s *Server) decode(body []byte, req interface{}, network **NetworkIdentifier) *Error {
+	if err := json.Unmarshal(body, req); err != nil {
+		return ErrInvalidRequest.withDetails(err)
+	}
+	if *network == nil || **network != *s.network() {
+		return ErrUnsupportedNetwork
+	}
+	return nil
+}
+
+func (s *Server) network() *NetworkIdentifier {
+	return &NetworkIdentifier{
+		Blockchain: _blockchain,
+		Network:    s.cfg.Network,
+	}
+}
+
+func (s *Server) networkList(_ context.Context, _ []byte) (interface{}, *Error) {
+	return &networkListResponse{NetworkIdentifiers: []*NetworkIdentifier{s.network()}}, nil
+}
+
+func (s *Server) networkStatus(ctx context.Context, body []byte) (interface{}, *Error) {
+	req := &NetworkRequest{}
+	if err := s.decode(body, req, &req.NetworkIdentifier); err != nil {
+		return nil, err
+	}
+	res, err := s.api.GetChainMeta(ctx, &iotexapi.GetChainMetaRequest{})
+	if err != nil {
+		return nil, ErrUnavailable.withDetails(err)
+	}
+	tip, rerr := s.blockMeta(ctx, res.GetChainMeta().GetHeight())
+	if rerr != nil {
+		return nil, rerr
+	}
+	genesis, rerr := s.blockMeta(ctx, _genesisHeight)
+	if rerr != nil {
+		return nil, rerr
+	}
+	return &networkStatusResponse{
+		CurrentBlockIdentifier: blockIdentifier(tip),
+		CurrentBlockTimestamp:  timestamp(tip),
+		GenesisBlockIdentifier: blockIdentifier(genesis),
+		Peers:                  []interface{}{},
+	}, nil
+}
+
+func (s *Server) networkOptions(_ context.Context, body []byte) (interface{}, *Error) {
+	req := &NetworkRequest{}
+	if err := s.decode(body, req, &req.NetworkIdentifier); err != nil {
+		return nil, err
+	}
+	return &networkOptionsResponse{
+		Version: &versionInfo{
+			RosettaVersion: _rosettaVersion,
+			NodeVersion:    version.PackageVersion,
+		},
+		Allow: &allow{
+			OperationStatuses:       []*operationStatus{{Status: _statusSuccess, Successful: true}},
+			OperationTypes:          operationTypes(),
+			Errors:                  _errors,
+			HistoricalBalanceLookup: false,
+		},
+	}, nil
+}
+
+// operationTypes returns the types of the transaction logs, which are the types of the operations
+func operationTypes() []string {
+	types := make([]string, 0, len(iotextypes.TransactionLogType_name))
+	for _, name := range iotextypes.TransactionLogType_name {
+		types = append(types, name)
+	}
+	sort.Strings(types)
+	return types
+}
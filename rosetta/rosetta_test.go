@@ -0,0 +1,294 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package rosetta
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/iotexproject/iotex-address/address"
+	"github.com/iotexproject/iotex-proto/golang/iotexapi"
+	"github.com/iotexproject/iotex-proto/golang/iotextypes"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/action"
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/test/identityset"
+)
+
+type fakeAPI struct {
+	height  uint64
+	actions []*iotextypes.Action
+	sent    *iotextypes.Action
+}
+
+func (api *fakeAPI) GetChainMeta(context.Context, *iotexapi.GetChainMetaRequest) (*iotexapi.GetChainMetaResponse, error) {
+	return &iotexapi.GetChainMetaResponse{ChainMeta: &iotextypes.ChainMeta{Height: api.height}}, nil
+}
+
+func (api *fakeAPI) GetBlockMetas(_ context.Context, in *iotexapi.GetBlockMetasRequest) (*iotexapi.GetBlockMetasResponse, error) {
+	meta := func(height uint64) *iotextypes.BlockMeta {
+		return &iotextypes.BlockMeta{
+			Height:            height,
+			Hash:              "hash" + strconv.FormatUint(height, 10),
+			Timestamp:         ptypes.TimestampNow(),
+			PreviousBlockHash: "hash" + strconv.FormatUint(height-1, 10),
+		}
+	}
+	res := &iotexapi.GetBlockMetasResponse{}
+	if req := in.GetByIndex(); req != nil {
+		for h := req.Start; h < req.Start+req.Count && h <= api.height; h++ {
+			res.BlkMetas = append(res.BlkMetas, meta(h))
+		}
+		return res, nil
+	}
+	if in.GetByHash().GetBlkHash() == "hash2" {
+		res.BlkMetas = append(res.BlkMetas, meta(2))
+		return res, nil
+	}
+	return nil, errors.New("block not found")
+}
+
+func (api *fakeAPI) GetRawBlocks(_ context.Context, in *iotexapi.GetRawBlocksRequest) (*iotexapi.GetRawBlocksResponse, error) {
+	blk := &iotexapi.BlockInfo{
+		Block:           &iotextypes.Block{Body: &iotextypes.BlockBody{}},
+		TransactionLogs: &iotextypes.TransactionLogs{},
+	}
+	if in.StartHeight == 2 {
+		blk.Block.Body.Actions = api.actions
+		for _, act := range api.actions {
+			selp := action.SealedEnvelope{}
+			if err := selp.LoadProto(act); err != nil {
+				return nil, err
+			}
+			h := selp.Hash()
+			blk.Receipts = append(blk.Receipts, &iotextypes.Receipt{Status: 1, ActHash: h[:], GasConsumed: 10000})
+			blk.TransactionLogs.Logs = append(blk.TransactionLogs.Logs, &iotextypes.TransactionLog{
+				ActionHash: h[:],
+				Transactions: []*iotextypes.TransactionLog_Transaction{
+					{
+						Type:      iotextypes.TransactionLogType_GAS_FEE,
+						Amount:    "10000",
+						Sender:    identityset.Address(4).String(),
+						Recipient: address.RewardingPoolAddr,
+					},
+					{
+						Type:      iotextypes.TransactionLogType_NATIVE_TRANSFER,
+						Amount:    "10",
+						Sender:    identityset.Address(4).String(),
+						Recipient: identityset.Address(3).String(),
+					},
+				},
+			})
+		}
+	}
+	return &iotexapi.GetRawBlocksResponse{Blocks: []*iotexapi.BlockInfo{blk}}, nil
+}
+
+func (api *fakeAPI) GetAccount(_ context.Context, in *iotexapi.GetAccountRequest) (*iotexapi.GetAccountResponse, error) {
+	return &iotexapi.GetAccountResponse{
+		AccountMeta: &iotextypes.AccountMeta{
+			Address:      in.Address,
+			Balance:      "12345",
+			Nonce:        4,
+			PendingNonce: 5,
+		},
+		BlockIdentifier: &iotextypes.BlockIdentifier{Hash: "hash" + strconv.FormatUint(api.height, 10), Height: api.height},
+	}, nil
+}
+
+func (api *fakeAPI) GetActPoolActions(context.Context, *iotexapi.GetActPoolActionsRequest) (*iotexapi.GetActPoolActionsResponse, error) {
+	return &iotexapi.GetActPoolActionsResponse{Actions: api.actions}, nil
+}
+
+func (api *fakeAPI) SuggestGasPrice(context.Context, *iotexapi.SuggestGasPriceRequest) (*iotexapi.SuggestGasPriceResponse, error) {
+	return &iotexapi.SuggestGasPriceResponse{GasPrice: 2}, nil
+}
+
+func (api *fakeAPI) SendAction(_ context.Context, in *iotexapi.SendActionRequest) (*iotexapi.SendActionResponse, error) {
+	api.sent = in.Action
+	selp := action.SealedEnvelope{}
+	if err := selp.LoadProto(in.Action); err != nil {
+		return nil, err
+	}
+	h := selp.Hash()
+	return &iotexapi.SendActionResponse{ActionHash: hex.EncodeToString(h[:])}, nil
+}
+
+// post posts the request to the endpoint and decodes the response into res, returning the status code
+func post(t *testing.T, s *Server, path string, req map[string]interface{}, res interface{}) int {
+	if _, ok := req["network_identifier"]; !ok {
+		req["network_identifier"] = s.network()
+	}
+	body, err := json.Marshal(req)
+	require.NoError(t, err)
+	rec := httptest.NewRecorder()
+	s.server.Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, path, strings.NewReader(string(body))))
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), res))
+	return rec.Code
+}
+
+func TestDataAPI(t *testing.T) {
+	require := require.New(t)
+
+	tsf, err := action.NewTransfer(1, big.NewInt(10), identityset.Address(3).String(), nil, 10000, big.NewInt(1))
+	require.NoError(err)
+	elp := (&action.EnvelopeBuilder{}).SetNonce(1).SetGasLimit(10000).SetGasPrice(big.NewInt(1)).SetAction(tsf).Build()
+	selp, err := action.Sign(elp, identityset.PrivateKey(4))
+	require.NoError(err)
+	h := selp.Hash()
+	actHash := hex.EncodeToString(h[:])
+	api := &fakeAPI{height: 3, actions: []*iotextypes.Action{selp.Proto()}}
+	s := NewServer(config.Default.Rosetta, api)
+
+	status := &networkStatusResponse{}
+	require.Equal(http.StatusOK, post(t, s, "/network/status", map[string]interface{}{}, status))
+	require.Equal(int64(3), status.CurrentBlockIdentifier.Index)
+	require.Equal("hash1", status.GenesisBlockIdentifier.Hash)
+
+	e := &Error{}
+	require.Equal(http.StatusInternalServerError, post(t, s, "/network/status", map[string]interface{}{
+		"network_identifier": &NetworkIdentifier{Blockchain: _blockchain, Network: "testnet"},
+	}, e))
+	require.Equal(ErrUnsupportedNetwork.Code, e.Code)
+
+	blk := &blockResponse{}
+	require.Equal(http.StatusOK, post(t, s, "/block", map[string]interface{}{
+		"block_identifier": map[string]interface{}{"hash": "hash2"},
+	}, blk))
+	require.Equal(int64(2), blk.Block.BlockIdentifier.Index)
+	require.Equal("hash1", blk.Block.ParentBlockIdentifier.Hash)
+	require.Len(blk.Block.Transactions, 1)
+	tx := blk.Block.Transactions[0]
+	require.Equal(actHash, tx.TransactionIdentifier.Hash)
+	require.Len(tx.Operations, 4)
+	require.Equal("GAS_FEE", tx.Operations[0].Type)
+	require.Equal("-10000", tx.Operations[0].Amount.Value)
+	require.Equal(address.RewardingPoolAddr, tx.Operations[1].Account.Address)
+	require.Equal("NATIVE_TRANSFER", tx.Operations[3].Type)
+	require.Equal(identityset.Address(3).String(), tx.Operations[3].Account.Address)
+	require.Equal(int64(2), tx.Operations[3].RelatedOperations[0].Index)
+
+	blkTx := &blockTransactionResponse{}
+	require.Equal(http.StatusOK, post(t, s, "/block/transaction", map[string]interface{}{
+		"block_identifier":       &BlockIdentifier{Index: 2, Hash: "hash2"},
+		"transaction_identifier": &TransactionIdentifier{Hash: actHash},
+	}, blkTx))
+	require.Equal(tx, blkTx.Transaction)
+
+	balance := &accountBalanceResponse{}
+	require.Equal(http.StatusOK, post(t, s, "/account/balance", map[string]interface{}{
+		"account_identifier": &AccountIdentifier{Address: identityset.Address(4).String()},
+	}, balance))
+	require.Equal("12345", balance.Balances[0].Value)
+	require.Equal(int64(3), balance.BlockIdentifier.Index)
+	require.Equal(http.StatusInternalServerError, post(t, s, "/account/balance", map[string]interface{}{
+		"account_identifier": &AccountIdentifier{Address: identityset.Address(4).String()},
+		"block_identifier":   map[string]interface{}{"index": 2},
+	}, e))
+
+	mempool := &mempoolResponse{}
+	require.Equal(http.StatusOK, post(t, s, "/mempool", map[string]interface{}{}, mempool))
+	require.Equal(actHash, mempool.TransactionIdentifiers[0].Hash)
+	mempoolTx := &mempoolTransactionResponse{}
+	require.Equal(http.StatusOK, post(t, s, "/mempool/transaction", map[string]interface{}{
+		"transaction_identifier": &TransactionIdentifier{Hash: actHash},
+	}, mempoolTx))
+	require.Len(mempoolTx.Transaction.Operations, 2)
+	require.Equal(identityset.Address(4).String(), mempoolTx.Transaction.Operations[0].Account.Address)
+}
+
+func TestConstructionAPI(t *testing.T) {
+	require := require.New(t)
+
+	api := &fakeAPI{height: 3}
+	s := NewServer(config.Default.Rosetta, api)
+	sk := identityset.PrivateKey(4)
+	sender, recipient := identityset.Address(4).String(), identityset.Address(3).String()
+	pk := &PublicKey{HexBytes: hex.EncodeToString(sk.PublicKey().Bytes()), CurveType: _curveType}
+
+	derived := &constructionDeriveResponse{}
+	require.Equal(http.StatusOK, post(t, s, "/construction/derive", map[string]interface{}{"public_key": pk}, derived))
+	require.Equal(sender, derived.AccountIdentifier.Address)
+
+	ops := debitAndCredit(0, _transferType, "", sender, recipient, big.NewInt(10))
+	preprocessed := &constructionPreprocessResponse{}
+	require.Equal(http.StatusOK, post(t, s, "/construction/preprocess", map[string]interface{}{"operations": ops}, preprocessed))
+	require.Equal(&TransferOptions{Sender: sender, Recipient: recipient, Amount: "10"}, preprocessed.Options)
+	e := &Error{}
+	require.Equal(http.StatusInternalServerError, post(t, s, "/construction/preprocess", map[string]interface{}{
+		"operations": debitAndCredit(0, "GAS_FEE", "", sender, recipient, big.NewInt(10)),
+	}, e))
+	require.Equal(ErrUnsupportedOperation.Code, e.Code)
+
+	metadata := &constructionMetadataResponse{}
+	require.Equal(http.StatusOK, post(t, s, "/construction/metadata", map[string]interface{}{"options": preprocessed.Options}, metadata))
+	require.Equal(&TransferMetadata{Nonce: 5, GasLimit: action.TransferBaseIntrinsicGas, GasPrice: "2"}, metadata.Metadata)
+	require.Equal("20000", metadata.SuggestedFee[0].Value)
+
+	payloads := &constructionPayloadsResponse{}
+	require.Equal(http.StatusOK, post(t, s, "/construction/payloads", map[string]interface{}{
+		"operations": ops,
+		"metadata":   metadata.Metadata,
+	}, payloads))
+	require.Len(payloads.Payloads, 1)
+	parsed := &constructionParseResponse{}
+	require.Equal(http.StatusOK, post(t, s, "/construction/parse", map[string]interface{}{
+		"transaction": payloads.UnsignedTransaction,
+	}, parsed))
+	require.Equal(ops, parsed.Operations)
+
+	h, err := hex.DecodeString(payloads.Payloads[0].HexBytes)
+	require.NoError(err)
+	sig, err := sk.Sign(h)
+	require.NoError(err)
+	signature := &Signature{
+		SigningPayload: payloads.Payloads[0],
+		PublicKey:      pk,
+		SignatureType:  _signatureType,
+		HexBytes:       hex.EncodeToString(sig),
+	}
+	combined := &constructionCombineResponse{}
+	require.Equal(http.StatusOK, post(t, s, "/construction/combine", map[string]interface{}{
+		"unsigned_transaction": payloads.UnsignedTransaction,
+		"signatures":           []*Signature{signature},
+	}, combined))
+	signature.HexBytes = hex.EncodeToString(make([]byte, len(sig)))
+	require.Equal(http.StatusInternalServerError, post(t, s, "/construction/combine", map[string]interface{}{
+		"unsigned_transaction": payloads.UnsignedTransaction,
+		"signatures":           []*Signature{signature},
+	}, e))
+
+	parsed = &constructionParseResponse{}
+	require.Equal(http.StatusOK, post(t, s, "/construction/parse", map[string]interface{}{
+		"signed":      true,
+		"transaction": combined.SignedTransaction,
+	}, parsed))
+	require.Equal(ops, parsed.Operations)
+	require.Equal(sender, parsed.AccountIdentifierSigners[0].Address)
+
+	hashed := &transactionIdentifierResponse{}
+	require.Equal(http.StatusOK, post(t, s, "/construction/hash", map[string]interface{}{
+		"signed_transaction": combined.SignedTransaction,
+	}, hashed))
+	submitted := &transactionIdentifierResponse{}
+	require.Equal(http.StatusOK, post(t, s, "/construction/submit", map[string]interface{}{
+		"signed_transaction": combined.SignedTransaction,
+	}, submitted))
+	require.Equal(hashed, submitted)
+	require.NotNil(api.sent)
+	require.Equal(uint64(5), api.sent.GetCore().GetNonce())
+}
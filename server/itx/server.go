@@ -8,11 +8,16 @@ package itx
 
 import (
 	"context"
+	"crypto/tls"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/pprof"
+	"os"
 	"runtime"
+	"strconv"
 	"sync"
+	"syscall"
 
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
@@ -26,6 +31,8 @@ import (
 	"github.com/iotexproject/iotex-core/pkg/probe"
 	"github.com/iotexproject/iotex-core/pkg/routine"
 	"github.com/iotexproject/iotex-core/pkg/util/httputil"
+	"github.com/iotexproject/iotex-core/pkg/util/tlsutil"
+	"github.com/iotexproject/iotex-core/updater"
 )
 
 // Server is the iotex server instance containing all components.
@@ -193,18 +200,80 @@ func StartServer(ctx context.Context, svr *Server, probeSvr *probe.Server, cfg c
 		}()
 	}
 
+	if cfg.Updater.ManifestURL != "" {
+		bc := svr.rootChainService.Blockchain()
+		u, err := updater.NewUpdater(cfg.Updater, cfg.Genesis.Blockchain, bc.TipHeight, exit)
+		if err != nil {
+			log.L().Panic("Failed to create updater.", zap.Error(err))
+		}
+		task := routine.NewRecurringTask(u.Check, cfg.Updater.CheckInterval)
+		if err := task.Start(ctx); err != nil {
+			log.L().Panic("Failed to start updater routine.", zap.Error(err))
+		}
+		defer func() {
+			if err := task.Stop(ctx); err != nil {
+				log.L().Panic("Failed to stop updater routine.", zap.Error(err))
+			}
+		}()
+	}
+
 	var adminserv http.Server
 	if cfg.System.HTTPAdminPort > 0 {
 		mux := http.NewServeMux()
 		log.RegisterLevelConfigMux(mux)
 		haCtl := ha.New(svr.rootChainService.Consensus())
 		mux.Handle("/ha", http.HandlerFunc(haCtl.Handle))
+		mux.Handle("/indexer", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			name := r.URL.Query().Get("materialize")
+			if err := svr.rootChainService.MaterializeIndexer(r.Context(), name); err != nil {
+				log.L().Error("Failed to materialize indexer.", zap.String("indexer", name), zap.Error(err))
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		}))
+		if snapshots := svr.rootChainService.StateSnapshots(); snapshots != nil {
+			mux.Handle("/snapshot", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if restore := r.URL.Query().Get("restore"); restore != "" {
+					// the snapshot is restored when the node restarts
+					height, err := strconv.ParseUint(restore, 10, 64)
+					if err == nil {
+						err = snapshots.StageRestore(height)
+					}
+					if err != nil {
+						log.L().Error("Failed to stage state snapshot.", zap.String("height", restore), zap.Error(err))
+						w.WriteHeader(http.StatusBadRequest)
+						return
+					}
+					w.WriteHeader(http.StatusOK)
+					return
+				}
+				list, err := snapshots.List()
+				if err != nil {
+					log.L().Error("Failed to list state snapshots.", zap.Error(err))
+					w.WriteHeader(http.StatusInternalServerError)
+					return
+				}
+				w.Header().Set("Content-Type", "application/json")
+				if err := json.NewEncoder(w).Encode(list); err != nil {
+					log.L().Error("Failed to write state snapshots.", zap.Error(err))
+				}
+			}))
+		}
 		mux.Handle("/debug/pprof/", http.HandlerFunc(pprof.Index))
 		mux.Handle("/debug/pprof/cmdline", http.HandlerFunc(pprof.Cmdline))
 		mux.Handle("/debug/pprof/profile", http.HandlerFunc(pprof.Profile))
 		mux.Handle("/debug/pprof/symbol", http.HandlerFunc(pprof.Symbol))
 		mux.Handle("/debug/pprof/trace", http.HandlerFunc(pprof.Trace))
 
+		var tlsCfg *tls.Config
+		if c := cfg.System.HTTPAdminTLS; c.CertFile != "" {
+			var err error
+			tlsCfg, err = tlsutil.NewServerConfig(c.CertFile, c.KeyFile, c.ClientCAFile, c.AllowedClients, c.ReloadInterval)
+			if err != nil {
+				log.L().Panic("Failed to create tls config of admin server.", zap.Error(err))
+			}
+		}
 		port := fmt.Sprintf(":%d", cfg.System.HTTPAdminPort)
 		adminserv = httputil.Server(port, mux)
 		defer func() {
@@ -220,6 +289,9 @@ func StartServer(ctx context.Context, svr *Server, probeSvr *probe.Server, cfg c
 				log.L().Error("Error when listen to profiling port.", zap.Error(err))
 				return
 			}
+			if tlsCfg != nil {
+				ln = tls.NewListener(ln, tlsCfg)
+			}
 			if err := adminserv.Serve(ln); err != nil {
 				log.L().Error("Error when serving performance profiling data.", zap.Error(err))
 			}
@@ -229,3 +301,14 @@ func StartServer(ctx context.Context, svr *Server, probeSvr *probe.Server, cfg c
 	<-ctx.Done()
 	probeSvr.NotReady()
 }
+
+// exit stops the server gracefully as if it receives SIGTERM
+func exit() {
+	p, err := os.FindProcess(os.Getpid())
+	if err == nil {
+		err = p.Signal(syscall.SIGTERM)
+	}
+	if err != nil {
+		log.L().Error("Failed to exit.", zap.Error(err))
+	}
+}
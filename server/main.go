@@ -13,6 +13,7 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"flag"
 	"fmt"
 	glog "log"
@@ -23,6 +24,8 @@ import (
 	_ "go.uber.org/automaxprocs"
 	"go.uber.org/zap"
 
+	"github.com/iotexproject/iotex-core/api"
+	"github.com/iotexproject/iotex-core/archive"
 	"github.com/iotexproject/iotex-core/blockchain/genesis"
 	"github.com/iotexproject/iotex-core/config"
 	"github.com/iotexproject/iotex-core/pkg/log"
@@ -30,7 +33,19 @@ import (
 	"github.com/iotexproject/iotex-core/server/itx"
 )
 
+var (
+	// restoreFromArchive restores the chain and state db from the archive before starting the node
+	restoreFromArchive bool
+	// checkConfig checks the config, prints the report and exits without starting the node
+	checkConfig bool
+	// restoreSnapshot restores the state db from the local snapshot at the height before starting the node
+	restoreSnapshot uint64
+)
+
 func init() {
+	flag.BoolVar(&restoreFromArchive, "restore-from-archive", false, "Restore the chain and state db from the archive")
+	flag.BoolVar(&checkConfig, "check-config", false, "Check the config and exit")
+	flag.Uint64Var(&restoreSnapshot, "restore-state-snapshot", 0, "Restore the state db from the local snapshot at the height")
 	flag.Usage = func() {
 		_, _ = fmt.Fprintf(os.Stderr,
 			"usage: server -config-path=[string]\n")
@@ -41,6 +56,10 @@ func init() {
 }
 
 func main() {
+	if checkConfig {
+		os.Exit(runCheckConfig())
+	}
+
 	stop := make(chan os.Signal, 1)
 	signal.Notify(stop, os.Interrupt)
 	signal.Notify(stop, syscall.SIGTERM)
@@ -60,6 +79,19 @@ func main() {
 	initLogger(cfg)
 
 	cfg.Genesis = genesisCfg
+	report := config.CheckConfig(cfg)
+	if !report.Valid {
+		log.L().Fatal("Invalid config.", zap.Strings("errors", report.Errors))
+	}
+	for _, w := range report.Warnings {
+		log.L().Warn("Suspicious config.", zap.String("warning", w))
+	}
+	for _, d := range report.Deprecations {
+		log.L().Warn("Deprecated config field is set.",
+			zap.String("field", d.Field),
+			zap.String("replacement", d.Replacement),
+			zap.String("note", d.Note))
+	}
 	cfgToLog := cfg
 	cfgToLog.Chain.ProducerPrivKey = ""
 	log.S().Infof("Config in use: %+v", cfgToLog)
@@ -82,6 +114,21 @@ func main() {
 		livenessCancel()
 	}()
 
+	if _, ok := cfg.Plugins[config.APIProxyPlugin]; ok {
+		// run the node as a stateless api gateway only
+		startAPIProxy(ctx, probeSvr, cfg)
+		close(stopped)
+		<-livenessCtx.Done()
+		return
+	}
+
+	if restoreFromArchive {
+		restoreArchive(ctx, cfg)
+	}
+	if cfg.StateSnapshot.Dir != "" {
+		restoreStateSnapshot(cfg)
+	}
+
 	// create and start the node
 	svr, err := itx.NewServer(cfg)
 	if err != nil {
@@ -103,6 +150,73 @@ func main() {
 	<-livenessCtx.Done()
 }
 
+func startAPIProxy(ctx context.Context, probeSvr *probe.Server, cfg config.Config) {
+	svr, err := api.NewProxyServer(cfg.APIProxy, cfg.API.Port, cfg.API.TLS)
+	if err != nil {
+		log.L().Fatal("Failed to create api proxy server.", zap.Error(err))
+	}
+	if err := svr.Start(ctx); err != nil {
+		log.L().Fatal("Failed to start api proxy server.", zap.Error(err))
+	}
+	defer func() {
+		if err := svr.Stop(context.Background()); err != nil {
+			log.L().Panic("Failed to stop api proxy server.", zap.Error(err))
+		}
+	}()
+	probeSvr.Ready()
+	<-ctx.Done()
+	probeSvr.NotReady()
+}
+
+// runCheckConfig prints the config check report in json, and returns the exit code
+func runCheckConfig() int {
+	report := &config.Report{}
+	genesisCfg, err := genesis.New()
+	if err == nil {
+		var cfg config.Config
+		if cfg, err = config.New(config.DoNotValidate); err == nil {
+			cfg.Genesis = genesisCfg
+			report = config.CheckConfig(cfg)
+		}
+	}
+	if err != nil {
+		report.Errors = []string{err.Error()}
+	}
+	out, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		glog.Println("Failed to encode config check report: ", err)
+		return 2
+	}
+	fmt.Println(string(out))
+	if !report.Valid {
+		return 1
+	}
+	return 0
+}
+
+func restoreArchive(ctx context.Context, cfg config.Config) {
+	store, err := archive.NewObjectStore(cfg.Archive)
+	if err != nil {
+		log.L().Fatal("Failed to create archive store.", zap.Error(err))
+	}
+	height, err := archive.Restore(ctx, cfg, store)
+	if err != nil {
+		log.L().Fatal("Failed to restore from archive.", zap.Uint64("height", height), zap.Error(err))
+	}
+	log.L().Info("Restored from archive.", zap.Uint64("height", height))
+}
+
+// restoreStateSnapshot restores the state db from the snapshot of the flag, or the one staged by the admin endpoint
+func restoreStateSnapshot(cfg config.Config) {
+	height, err := archive.RestoreStateSnapshot(cfg, restoreSnapshot)
+	if err != nil {
+		log.L().Fatal("Failed to restore state snapshot.", zap.Error(err))
+	}
+	if height > 0 {
+		log.L().Info("Restored state snapshot.", zap.Uint64("height", height))
+	}
+}
+
 func initLogger(cfg config.Config) {
 	addr := cfg.ProducerAddress()
 	if err := log.InitLoggers(cfg.Log, cfg.SubLogs, zap.Fields(
@@ -0,0 +1,138 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package slareport
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/iotexproject/go-pkgs/crypto"
+	"github.com/iotexproject/go-pkgs/hash"
+	"github.com/iotexproject/iotex-address/address"
+)
+
+// ErrInvalidSignature indicates the signature of the report does not match the report or the public key
+var ErrInvalidSignature = errors.New("invalid report signature")
+
+type (
+	// Report is the productivity report of a delegate in an epoch
+	Report struct {
+		Delegate    string `json:"delegate"`
+		EpochNumber uint64 `json:"epochNumber"`
+		StartHeight uint64 `json:"startHeight"`
+		EndHeight   uint64 `json:"endHeight"`
+		// ActiveDelegates is the number of active block producers of the epoch
+		ActiveDelegates uint64 `json:"activeDelegates"`
+		// Active is false if the delegate is not an active block producer of the epoch
+		Active bool `json:"active"`
+		// ExpectedBlocks is the number of blocks of the epoch divided by the number of active block producers, the same
+		// as the probation calculation
+		ExpectedBlocks uint64 `json:"expectedBlocks"`
+		ProducedBlocks uint64 `json:"producedBlocks"`
+		// Productivity is the percentage of produced blocks to the expected ones
+		Productivity uint64 `json:"productivity"`
+		// EndorsedBlocks is the number of blocks of the epoch whose commit endorsements include the delegate's
+		EndorsedBlocks uint64 `json:"endorsedBlocks"`
+		// EndorsementParticipation is the percentage of endorsed blocks to all blocks of the epoch
+		EndorsementParticipation uint64 `json:"endorsementParticipation"`
+		// Latency is the latency from the timestamp to the commit time of the blocks produced by the delegate
+		Latency Latency `json:"latency"`
+	}
+
+	// Latency is the percentiles of block latencies in milliseconds
+	Latency struct {
+		Samples int   `json:"samples"`
+		P50     int64 `json:"p50"`
+		P90     int64 `json:"p90"`
+		P99     int64 `json:"p99"`
+		Max     int64 `json:"max"`
+	}
+
+	// SignedReport is the report signed by the delegate's producer key
+	SignedReport struct {
+		Report    *Report `json:"report"`
+		PublicKey string  `json:"publicKey"`
+		Signature string  `json:"signature"`
+	}
+)
+
+// Hash returns the hash of the json encoding of the report, which is signed
+func (r *Report) Hash() (hash.Hash256, error) {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return hash.ZeroHash256, err
+	}
+	return hash.Hash256b(data), nil
+}
+
+// Sign signs the report with the private key
+func Sign(r *Report, sk crypto.PrivateKey) (*SignedReport, error) {
+	h, err := r.Hash()
+	if err != nil {
+		return nil, err
+	}
+	sig, err := sk.Sign(h[:])
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to sign report")
+	}
+	return &SignedReport{
+		Report:    r,
+		PublicKey: sk.PublicKey().HexString(),
+		Signature: hex.EncodeToString(sig),
+	}, nil
+}
+
+// Verify verifies the signature of the report, and that the public key belongs to the delegate of the report
+func Verify(sr *SignedReport) error {
+	if sr.Report == nil {
+		return errors.Wrap(ErrInvalidSignature, "empty report")
+	}
+	pk, err := crypto.HexStringToPublicKey(sr.PublicKey)
+	if err != nil {
+		return errors.Wrap(ErrInvalidSignature, err.Error())
+	}
+	addr, err := address.FromBytes(pk.Hash())
+	if err != nil {
+		return err
+	}
+	if addr.String() != sr.Report.Delegate {
+		return errors.Wrapf(ErrInvalidSignature, "public key of %s does not belong to delegate %s", addr, sr.Report.Delegate)
+	}
+	sig, err := hex.DecodeString(sr.Signature)
+	if err != nil {
+		return errors.Wrap(ErrInvalidSignature, err.Error())
+	}
+	h, err := sr.Report.Hash()
+	if err != nil {
+		return err
+	}
+	if !pk.Verify(h[:], sig) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+func newLatency(latencies []time.Duration) Latency {
+	if len(latencies) == 0 {
+		return Latency{}
+	}
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	percentile := func(p int) int64 {
+		return latencies[(len(latencies)-1)*p/100].Milliseconds()
+	}
+	return Latency{
+		Samples: len(latencies),
+		P50:     percentile(50),
+		P90:     percentile(90),
+		P99:     percentile(99),
+		Max:     latencies[len(latencies)-1].Milliseconds(),
+	}
+}
@@ -0,0 +1,324 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+// Package slareport generates a signed productivity report of the local delegate at the end of each epoch, which
+// could be used as evidence in probation disputes. The reports are stored and served over http.
+package slareport
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/iotexproject/go-pkgs/crypto"
+	"github.com/iotexproject/iotex-address/address"
+
+	"github.com/iotexproject/iotex-core/action/protocol"
+	"github.com/iotexproject/iotex-core/action/protocol/poll"
+	"github.com/iotexproject/iotex-core/action/protocol/protocolctx"
+	"github.com/iotexproject/iotex-core/action/protocol/rolldpos"
+	"github.com/iotexproject/iotex-core/blockchain/block"
+	"github.com/iotexproject/iotex-core/blockchain/genesis"
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/db"
+	"github.com/iotexproject/iotex-core/db/batch"
+	"github.com/iotexproject/iotex-core/pkg/log"
+	"github.com/iotexproject/iotex-core/pkg/util/byteutil"
+	"github.com/iotexproject/iotex-core/pkg/util/httputil"
+	"github.com/iotexproject/iotex-core/state"
+)
+
+// SLAReportNamespace is the namespace to store the reports
+const SLAReportNamespace = "SLAReport"
+
+var _latestKey = []byte("latest")
+
+type (
+	// BlockReader reads the headers and footers of the committed blocks
+	BlockReader interface {
+		BlockHeaderByHeight(uint64) (*block.Header, error)
+		BlockFooterByHeight(uint64) (*block.Footer, error)
+	}
+
+	// Reporter generates the report of the local delegate on the last block of each epoch
+	Reporter struct {
+		cfg      config.SLAReport
+		genesis  genesis.Genesis
+		sr       protocol.StateReader
+		bc       BlockReader
+		registry *protocol.Registry
+		sk       crypto.PrivateKey
+		delegate string
+		kvStore  db.KVStore
+		server   *http.Server
+	}
+)
+
+// NewReporter creates a reporter of the delegate owning the private key
+func NewReporter(
+	cfg config.SLAReport,
+	g genesis.Genesis,
+	sr protocol.StateReader,
+	bc BlockReader,
+	registry *protocol.Registry,
+	sk crypto.PrivateKey,
+	kv db.KVStore,
+) (*Reporter, error) {
+	if sk == nil {
+		return nil, errors.New("empty private key")
+	}
+	if kv == nil {
+		return nil, errors.New("empty kvStore")
+	}
+	delegate, err := address.FromBytes(sk.PublicKey().Hash())
+	if err != nil {
+		return nil, err
+	}
+	r := &Reporter{
+		cfg:      cfg,
+		genesis:  g,
+		sr:       sr,
+		bc:       bc,
+		registry: registry,
+		sk:       sk,
+		delegate: delegate.String(),
+		kvStore:  kv,
+	}
+	if cfg.Port != 0 {
+		mux := http.NewServeMux()
+		mux.HandleFunc("/report", r.handleReport)
+		server := httputil.Server(fmt.Sprintf(":%d", cfg.Port), mux)
+		r.server = &server
+	}
+	return r, nil
+}
+
+// Start starts the reporter
+func (r *Reporter) Start(ctx context.Context) error {
+	if err := r.kvStore.Start(ctx); err != nil {
+		return err
+	}
+	if r.server == nil {
+		return nil
+	}
+	ln, err := httputil.LimitListener(r.server.Addr)
+	if err != nil {
+		return errors.Wrap(err, "failed to listen on sla report port")
+	}
+	go func() {
+		if err := r.server.Serve(ln); err != nil {
+			log.L().Info("SLA report server stopped.", zap.Error(err))
+		}
+	}()
+	return nil
+}
+
+// Stop stops the reporter
+func (r *Reporter) Stop(ctx context.Context) error {
+	if r.server != nil {
+		if err := r.server.Shutdown(ctx); err != nil {
+			return err
+		}
+	}
+	return r.kvStore.Stop(ctx)
+}
+
+// ReceiveBlock generates the report of the epoch if the block is the last block of the epoch
+func (r *Reporter) ReceiveBlock(blk *block.Block) error {
+	rp := rolldpos.FindProtocol(r.registry)
+	if rp == nil {
+		return nil
+	}
+	height := blk.Height()
+	epochNum := rp.GetEpochNum(height)
+	if height != rp.GetEpochLastBlockHeight(epochNum) {
+		return nil
+	}
+	if _, err := r.Generate(epochNum); err != nil {
+		log.L().Error("Failed to generate sla report.", zap.Uint64("epoch", epochNum), zap.Error(err))
+	}
+	return nil
+}
+
+// Generate generates, signs and stores the report of the epoch, the epoch should have ended
+func (r *Reporter) Generate(epochNum uint64) (*SignedReport, error) {
+	rp := rolldpos.FindProtocol(r.registry)
+	if rp == nil {
+		return nil, errors.New("rolldpos protocol is not registered")
+	}
+	pp := poll.FindProtocol(r.registry)
+	if pp == nil {
+		return nil, errors.New("poll protocol is not registered")
+	}
+	report := &Report{
+		Delegate:    r.delegate,
+		EpochNumber: epochNum,
+		StartHeight: rp.GetEpochHeight(epochNum),
+		EndHeight:   rp.GetEpochLastBlockHeight(epochNum),
+	}
+
+	ctx, err := protocolctx.NewBuilder().
+		SetRegistry(r.registry).
+		SetGenesis(r.genesis).
+		SetBlockHeight(report.EndHeight).
+		Build(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	data, _, err := pp.ReadState(ctx, r.sr, []byte("ActiveBlockProducersByEpoch"), []byte(strconv.FormatUint(epochNum, 10)))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read active block producers")
+	}
+	var producers state.CandidateList
+	if err := producers.Deserialize(data); err != nil {
+		return nil, err
+	}
+	report.ActiveDelegates = uint64(len(producers))
+	for _, p := range producers {
+		if p.Address == r.delegate {
+			report.Active = true
+			break
+		}
+	}
+
+	var latencies []time.Duration
+	for height := report.StartHeight; height <= report.EndHeight; height++ {
+		header, err := r.bc.BlockHeaderByHeight(height)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read block header of height %d", height)
+		}
+		footer, err := r.bc.BlockFooterByHeight(height)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read block footer of height %d", height)
+		}
+		if header.ProducerAddress() == r.delegate {
+			report.ProducedBlocks++
+			latencies = append(latencies, footer.CommitTime().Sub(header.Timestamp()))
+		}
+		for _, en := range footer.Endorsements() {
+			endorser, err := address.FromBytes(en.Endorser().Hash())
+			if err != nil {
+				return nil, err
+			}
+			if endorser.String() == r.delegate {
+				report.EndorsedBlocks++
+				break
+			}
+		}
+	}
+	numBlocks := report.EndHeight - report.StartHeight + 1
+	if report.Active && report.ActiveDelegates > 0 {
+		report.ExpectedBlocks = numBlocks / report.ActiveDelegates
+	}
+	if report.ExpectedBlocks > 0 {
+		report.Productivity = report.ProducedBlocks * 100 / report.ExpectedBlocks
+	}
+	report.EndorsementParticipation = report.EndorsedBlocks * 100 / numBlocks
+	report.Latency = newLatency(latencies)
+
+	signed, err := Sign(report, r.sk)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.put(signed); err != nil {
+		return nil, err
+	}
+	log.L().Info(
+		"Generated sla report.",
+		zap.Uint64("epoch", epochNum),
+		zap.Uint64("expected", report.ExpectedBlocks),
+		zap.Uint64("produced", report.ProducedBlocks),
+		zap.Uint64("endorsed", report.EndorsedBlocks),
+	)
+	return signed, nil
+}
+
+// Report returns the stored report of the epoch
+func (r *Reporter) Report(epochNum uint64) (*SignedReport, error) {
+	data, err := r.kvStore.Get(SLAReportNamespace, byteutil.Uint64ToBytesBigEndian(epochNum))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get report of epoch %d", epochNum)
+	}
+	report := &SignedReport{}
+	if err := json.Unmarshal(data, report); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// LatestReport returns the report of the latest epoch
+func (r *Reporter) LatestReport() (*SignedReport, error) {
+	data, err := r.kvStore.Get(SLAReportNamespace, _latestKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get latest report")
+	}
+	return r.Report(byteutil.BytesToUint64BigEndian(data))
+}
+
+func (r *Reporter) put(report *SignedReport) error {
+	data, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+	epoch := byteutil.Uint64ToBytesBigEndian(report.Report.EpochNumber)
+	b := batch.NewBatch()
+	b.Put(SLAReportNamespace, epoch, data, "failed to put report")
+	latest, err := r.kvStore.Get(SLAReportNamespace, _latestKey)
+	switch errors.Cause(err) {
+	case nil:
+		if byteutil.BytesToUint64BigEndian(latest) < report.Report.EpochNumber {
+			b.Put(SLAReportNamespace, _latestKey, epoch, "failed to put latest epoch")
+		}
+	case db.ErrNotExist:
+		b.Put(SLAReportNamespace, _latestKey, epoch, "failed to put latest epoch")
+	default:
+		return err
+	}
+	return r.kvStore.WriteBatch(b)
+}
+
+func (r *Reporter) handleReport(w http.ResponseWriter, req *http.Request) {
+	var (
+		report *SignedReport
+		err    error
+	)
+	if epoch := req.URL.Query().Get("epoch"); epoch != "" {
+		epochNum, perr := strconv.ParseUint(epoch, 10, 64)
+		if perr != nil {
+			writeError(w, http.StatusBadRequest, errors.Errorf("invalid epoch %s", epoch))
+			return
+		}
+		report, err = r.Report(epochNum)
+	} else {
+		report, err = r.LatestReport()
+	}
+	switch errors.Cause(err) {
+	case nil:
+		writeJSON(w, http.StatusOK, report)
+	case db.ErrNotExist:
+		writeError(w, http.StatusNotFound, err)
+	default:
+		writeError(w, http.StatusInternalServerError, err)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.L().Warn("Failed to send http response.", zap.Error(err))
+	}
+}
+
+func writeError(w http.ResponseWriter, code int, err error) {
+	writeJSON(w, code, map[string]string{"error": err.Error()})
+}
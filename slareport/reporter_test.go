@@ -0,0 +1,174 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package slareport
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/action/protocol"
+	"github.com/iotexproject/iotex-core/action/protocol/poll"
+	"github.com/iotexproject/iotex-core/action/protocol/rolldpos"
+	"github.com/iotexproject/iotex-core/blockchain/block"
+	"github.com/iotexproject/iotex-core/blockchain/genesis"
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/db"
+	"github.com/iotexproject/iotex-core/endorsement"
+	"github.com/iotexproject/iotex-core/test/identityset"
+	"github.com/iotexproject/iotex-core/test/mock/mock_chainmanager"
+)
+
+type fakeChain map[uint64]*block.Block
+
+func (c fakeChain) BlockHeaderByHeight(height uint64) (*block.Header, error) {
+	blk, ok := c[height]
+	if !ok {
+		return nil, db.ErrNotExist
+	}
+	return &blk.Header, nil
+}
+
+func (c fakeChain) BlockFooterByHeight(height uint64) (*block.Footer, error) {
+	blk, ok := c[height]
+	if !ok {
+		return nil, db.ErrNotExist
+	}
+	return &blk.Footer, nil
+}
+
+func TestSignAndVerify(t *testing.T) {
+	require := require.New(t)
+
+	sk := identityset.PrivateKey(0)
+	report := &Report{Delegate: identityset.Address(0).String(), EpochNumber: 3, ProducedBlocks: 30}
+	signed, err := Sign(report, sk)
+	require.NoError(err)
+	require.NoError(Verify(signed))
+
+	data, err := json.Marshal(signed)
+	require.NoError(err)
+	decoded := &SignedReport{}
+	require.NoError(json.Unmarshal(data, decoded))
+	require.NoError(Verify(decoded))
+
+	decoded.Report.ProducedBlocks = 31
+	require.Equal(ErrInvalidSignature, errors.Cause(Verify(decoded)))
+	decoded.Report.ProducedBlocks = 30
+	decoded.Report.Delegate = identityset.Address(1).String()
+	require.Equal(ErrInvalidSignature, errors.Cause(Verify(decoded)))
+	signed.PublicKey = identityset.PrivateKey(1).PublicKey().HexString()
+	require.Equal(ErrInvalidSignature, errors.Cause(Verify(signed)))
+}
+
+func TestLatency(t *testing.T) {
+	require := require.New(t)
+
+	require.Equal(Latency{}, newLatency(nil))
+	var latencies []time.Duration
+	for i := 100; i > 0; i-- {
+		latencies = append(latencies, time.Duration(i)*time.Millisecond)
+	}
+	require.Equal(Latency{Samples: 100, P50: 50, P90: 90, P99: 99, Max: 100}, newLatency(latencies))
+}
+
+func TestReporter(t *testing.T) {
+	require := require.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var delegates []genesis.Delegate
+	for i := 0; i < 4; i++ {
+		delegates = append(delegates, genesis.Delegate{
+			OperatorAddrStr: identityset.Address(i).String(),
+			RewardAddrStr:   identityset.Address(i).String(),
+			VotesStr:        "10",
+		})
+	}
+	registry := protocol.NewRegistry()
+	require.NoError(rolldpos.NewProtocol(4, 4, 2).Register(registry))
+	require.NoError(poll.NewLifeLongDelegatesProtocol(delegates).Register(registry))
+	sr := mock_chainmanager.NewMockStateReader(ctrl)
+	sr.EXPECT().Height().Return(uint64(8), nil).AnyTimes()
+
+	// delegate 0 produces the blocks of height 4 and 8, and endorses the blocks of height 1 to 6
+	chain := fakeChain{}
+	ts := time.Unix(1612345678, 0)
+	for height := uint64(1); height <= 8; height++ {
+		blk, err := block.NewTestingBuilder().
+			SetHeight(height).
+			SetTimeStamp(ts).
+			SignAndBuild(identityset.PrivateKey(int(height % 4)))
+		require.NoError(err)
+		var ens []*endorsement.Endorsement
+		if height <= 6 {
+			ens = append(ens, endorsement.NewEndorsement(ts, identityset.PrivateKey(0).PublicKey(), nil))
+		}
+		ens = append(ens, endorsement.NewEndorsement(ts, identityset.PrivateKey(1).PublicKey(), nil))
+		require.NoError(blk.Finalize(ens, ts.Add(time.Duration(height)*100*time.Millisecond)))
+		chain[height] = &blk
+		ts = ts.Add(5 * time.Second)
+	}
+
+	cfg := config.Default.SLAReport
+	r, err := NewReporter(cfg, config.Default.Genesis, sr, chain, registry, identityset.PrivateKey(0), db.NewMemKVStore())
+	require.NoError(err)
+	ctx := context.Background()
+	require.NoError(r.Start(ctx))
+	defer r.Stop(ctx)
+
+	_, err = r.LatestReport()
+	require.Equal(db.ErrNotExist, errors.Cause(err))
+
+	// not the last block of the epoch
+	require.NoError(r.ReceiveBlock(chain[7]))
+	_, err = r.Report(1)
+	require.Equal(db.ErrNotExist, errors.Cause(err))
+
+	require.NoError(r.ReceiveBlock(chain[8]))
+	signed, err := r.LatestReport()
+	require.NoError(err)
+	require.NoError(Verify(signed))
+	require.Equal(&Report{
+		Delegate:                 identityset.Address(0).String(),
+		EpochNumber:              1,
+		StartHeight:              1,
+		EndHeight:                8,
+		ActiveDelegates:          4,
+		Active:                   true,
+		ExpectedBlocks:           2,
+		ProducedBlocks:           2,
+		Productivity:             100,
+		EndorsedBlocks:           6,
+		EndorsementParticipation: 75,
+		Latency:                  Latency{Samples: 2, P50: 400, P90: 400, P99: 400, Max: 800},
+	}, signed.Report)
+
+	// served over http
+	w := httptest.NewRecorder()
+	r.handleReport(w, httptest.NewRequest("GET", "/report?epoch=1", nil))
+	require.Equal(200, w.Code)
+	served := &SignedReport{}
+	require.NoError(json.Unmarshal(w.Body.Bytes(), served))
+	require.Equal(signed, served)
+	w = httptest.NewRecorder()
+	r.handleReport(w, httptest.NewRequest("GET", "/report?epoch=2", nil))
+	require.Equal(404, w.Code)
+	w = httptest.NewRecorder()
+	r.handleReport(w, httptest.NewRequest("GET", "/report?epoch=x", nil))
+	require.Equal(400, w.Code)
+
+	// missing blocks
+	_, err = r.Generate(2)
+	require.Error(err)
+}
@@ -29,6 +29,9 @@ var (
 	ErrCandidateList = errors.New("invalid candidate list")
 )
 
+// CandidateListVersion is the current schema version of versioned candidate list encoding
+const CandidateListVersion uint32 = 1
+
 type (
 	// Candidate indicates the structure of a candidate
 	Candidate struct {
@@ -108,6 +111,15 @@ func (l *CandidateList) Serialize() ([]byte, error) {
 	return proto.Marshal(l.Proto())
 }
 
+// SerializeVersioned serializes a list of Candidates to bytes with schema version
+func (l *CandidateList) SerializeVersioned() ([]byte, error) {
+	payload, err := l.Serialize()
+	if err != nil {
+		return nil, err
+	}
+	return EncodeVersioned(CandidateListVersion, payload), nil
+}
+
 // Proto converts the candidate list to a protobuf message
 func (l *CandidateList) Proto() *iotextypes.CandidateList {
 	candidatesPb := make([]*iotextypes.Candidate, 0, len(*l))
@@ -117,10 +129,17 @@ func (l *CandidateList) Proto() *iotextypes.CandidateList {
 	return &iotextypes.CandidateList{Candidates: candidatesPb}
 }
 
-// Deserialize deserializes bytes to list of Candidates
+// Deserialize deserializes bytes in either legacy or versioned encoding to list of Candidates
 func (l *CandidateList) Deserialize(buf []byte) error {
+	version, payload, err := DecodeVersioned(buf)
+	if err != nil {
+		return errors.Wrap(err, "failed to decode candidate list")
+	}
+	if version > CandidateListVersion {
+		return errors.Wrapf(ErrUnsupportedVersion, "candidate list version %d", version)
+	}
 	candList := &iotextypes.CandidateList{}
-	if err := proto.Unmarshal(buf, candList); err != nil {
+	if err := proto.Unmarshal(payload, candList); err != nil {
 		return errors.Wrap(err, "failed to unmarshal candidate list")
 	}
 	return l.LoadProto(candList)
@@ -11,6 +11,7 @@ import (
 	"sort"
 	"testing"
 
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/require"
 
 	"github.com/iotexproject/go-pkgs/hash"
@@ -83,6 +84,21 @@ func TestCandidateListSerializeAndDeserialize(t *testing.T) {
 	for i, c := range list2 {
 		r.True(c.Equal(list1[i]))
 	}
+
+	// versioned encoding
+	vbytes, err := list1.SerializeVersioned()
+	r.NoError(err)
+	r.True(IsVersioned(vbytes))
+	r.False(IsVersioned(sbytes))
+	list3 := CandidateList{}
+	r.NoError(list3.Deserialize(vbytes))
+	r.Equal(list2, list3)
+
+	// unsupported version
+	_, payload, err := DecodeVersioned(vbytes)
+	r.NoError(err)
+	r.Equal(sbytes, payload)
+	r.Equal(ErrUnsupportedVersion, errors.Cause(list3.Deserialize(EncodeVersioned(CandidateListVersion+1, payload))))
 }
 
 func TestCandidate(t *testing.T) {
@@ -0,0 +1,57 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package factory
+
+import (
+	"encoding/hex"
+
+	"github.com/iotexproject/go-pkgs/hash"
+)
+
+type (
+	// StateKey is the key of a state in the namespace. An empty key stands for iterating the states of the namespace
+	StateKey struct {
+		Namespace string `json:"namespace"`
+		Key       string `json:"key"`
+	}
+
+	// AccessList is the keys of the states read and written by an action, in the order of the first access
+	AccessList struct {
+		ActionHash string     `json:"actionHash"`
+		Reads      []StateKey `json:"reads"`
+		Writes     []StateKey `json:"writes"`
+
+		reads  map[StateKey]bool
+		writes map[StateKey]bool
+	}
+)
+
+func newAccessList(actionHash hash.Hash256) *AccessList {
+	return &AccessList{
+		ActionHash: hex.EncodeToString(actionHash[:]),
+		Reads:      []StateKey{},
+		Writes:     []StateKey{},
+		reads:      make(map[StateKey]bool),
+		writes:     make(map[StateKey]bool),
+	}
+}
+
+func (al *AccessList) addRead(ns string, key []byte) {
+	k := StateKey{Namespace: ns, Key: hex.EncodeToString(key)}
+	if !al.reads[k] {
+		al.reads[k] = true
+		al.Reads = append(al.Reads, k)
+	}
+}
+
+func (al *AccessList) addWrite(ns string, key []byte) {
+	k := StateKey{Namespace: ns, Key: hex.EncodeToString(key)}
+	if !al.writes[k] {
+		al.writes[k] = true
+		al.Writes = append(al.Writes, k)
+	}
+}
@@ -99,6 +99,8 @@ type (
 		dao                      db.KVStore        // the underlying DB for account/contract storage
 		timerFactory             *prometheustimer.TimerFactory
 		workingsets              *cache.ThreadSafeLruCache // lru cache for workingsets
+		executionStats           *cache.ThreadSafeLruCache // lru cache for execution stats of committed blocks
+		accessLists              *cache.ThreadSafeLruCache // lru cache for state access lists of committed blocks
 		protocolView             protocol.View
 		skipBlockValidationOnPut bool
 	}
@@ -183,6 +185,8 @@ func NewFactory(cfg config.Config, opts ...Option) (Factory, error) {
 		saveHistory:        cfg.Chain.EnableArchiveMode,
 		protocolView:       protocol.View{},
 		workingsets:        cache.NewThreadSafeLruCache(int(cfg.Chain.WorkingSetCacheSize)),
+		executionStats:     cache.NewThreadSafeLruCache(int(cfg.Chain.WorkingSetCacheSize)),
+		accessLists:        cache.NewThreadSafeLruCache(int(cfg.Chain.WorkingSetCacheSize)),
 	}
 
 	for _, opt := range opts {
@@ -274,14 +278,29 @@ func (sf *factory) Height() (uint64, error) {
 }
 
 func (sf *factory) newWorkingSet(ctx context.Context, height uint64) (*workingSet, error) {
-	flusher, err := db.NewKVStoreFlusher(sf.dao, batch.NewCachedBatch(), sf.flusherOptions(ctx, height)...)
+	return sf.newWorkingSetOnDB(ctx, height, sf.dao, nil)
+}
+
+// newWorkingSetOnDB creates a working set on top of the states in dao, the trie nodes are read and written through the
+// kvstore returned by wrapTrieDB if it is not nil
+func (sf *factory) newWorkingSetOnDB(
+	ctx context.Context,
+	height uint64,
+	dao db.KVStore,
+	wrapTrieDB func(trie.KVStore) trie.KVStore,
+) (*workingSet, error) {
+	flusher, err := db.NewKVStoreFlusher(dao, batch.NewCachedBatch(), sf.flusherOptions(ctx, height)...)
 	if err != nil {
 		return nil, err
 	}
-	tlt, err := newTwoLayerTrie(ArchiveTrieNamespace, flusher.KVStoreWithBuffer(), ArchiveTrieRootKey, true)
+	dbForTrie, err := trie.NewKVStore(ArchiveTrieNamespace, flusher.KVStoreWithBuffer())
 	if err != nil {
-		return nil, err
+		return nil, errors.Wrap(err, "failed to create db for trie")
+	}
+	if wrapTrieDB != nil {
+		dbForTrie = wrapTrieDB(dbForTrie)
 	}
+	tlt := mptrie.NewTwoLayerTrie(dbForTrie, ArchiveTrieRootKey)
 	if err := tlt.Start(ctx); err != nil {
 		return nil, err
 	}
@@ -289,9 +308,12 @@ func (sf *factory) newWorkingSet(ctx context.Context, height uint64) (*workingSe
 	trieRoots := make(map[int][]byte)
 
 	return &workingSet{
-		height:    height,
-		finalized: false,
-		dock:      protocol.NewDock(),
+		height:          height,
+		finalized:       false,
+		dock:            protocol.NewDock(),
+		executionBudget: sf.cfg.Chain.ActionExecutionBudget,
+		parallelWorkers: sf.cfg.Chain.ParallelExecutionWorkers,
+		recordAccess:    sf.cfg.Chain.StateAccessIndexDBPath != "",
 		getStateFunc: func(ns string, key []byte, s interface{}) error {
 			return readState(tlt, ns, key, s)
 		},
@@ -564,8 +586,33 @@ func (sf *factory) PutBlock(ctx context.Context, blk *block.Block) error {
 			sf.currentChainHeight, h,
 		)
 	}
+	stats, accessLists := ws.stats, ws.accessLists
+	if err := ws.Commit(ctx); err != nil {
+		return err
+	}
+	sf.executionStats.Add(h, stats)
+	if ws.recordAccess {
+		sf.accessLists.Add(h, accessLists)
+	}
+	return nil
+}
 
-	return ws.Commit(ctx)
+// ExecutionStats returns the execution stats of a recently committed block
+func (sf *factory) ExecutionStats(height uint64) (ExecutionStats, bool) {
+	data, ok := sf.executionStats.Get(height)
+	if !ok {
+		return ExecutionStats{}, false
+	}
+	return data.(ExecutionStats), true
+}
+
+// AccessLists returns the state access lists of the actions of a recently committed block
+func (sf *factory) AccessLists(height uint64) ([]*AccessList, bool) {
+	data, ok := sf.accessLists.Get(height)
+	if !ok {
+		return nil, false
+	}
+	return data.([]*AccessList), true
 }
 
 func (sf *factory) DeleteTipBlock(_ *block.Block) error {
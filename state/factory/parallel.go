@@ -0,0 +1,350 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package factory
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/iotexproject/iotex-core/action"
+	"github.com/iotexproject/iotex-core/action/protocol"
+	"github.com/iotexproject/iotex-core/state"
+)
+
+var (
+	parallelExecutionMtc = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "iotex_parallel_execution",
+			Help: "Number of executions accepted from speculative run or re-executed serially",
+		},
+		[]string{"result"},
+	)
+
+	errSpeculationAborted = errors.New("operation not supported in speculative execution")
+)
+
+func init() {
+	prometheus.MustRegister(parallelExecutionMtc)
+}
+
+type (
+	// rawState holds the serialized bytes of a state
+	rawState []byte
+
+	stateEntry struct {
+		ns    string
+		key   []byte
+		value []byte
+		exist bool
+	}
+
+	// speculativeStateManager runs an action on top of a working set which is read-only during the speculation. It
+	// records the states read from the working set and buffers the states written, so that the action can be
+	// validated and applied in block order afterwards. The deposits into the rewarding fund are deferred as well,
+	// otherwise every execution would conflict with the one before it on the fund. The views and the dock of the
+	// working set are not tracked, so accessing them aborts the speculation
+	speculativeStateManager struct {
+		base     *workingSet
+		baseLock *sync.Mutex
+		reads    []stateEntry
+		writes   []stateEntry
+		shots    []int
+		aborted  bool
+		deposits protocol.DeferredDeposits
+		// stats counts the states accessed in the same way as the working set
+		stats ExecutionStats
+		// access records the keys accessed in the same way as the working set, if recording is enabled
+		access *AccessList
+	}
+
+	speculativeResult struct {
+		sm      *speculativeStateManager
+		ctx     context.Context
+		receipt *action.Receipt
+		elapsed time.Duration
+		err     error
+	}
+)
+
+func (s *rawState) Serialize() ([]byte, error) {
+	return *s, nil
+}
+
+func (s *rawState) Deserialize(data []byte) error {
+	*s = append((*s)[:0], data...)
+	return nil
+}
+
+// runActionsInParallel runs the actions in block order, consecutive executions are run speculatively in parallel
+// and then validated one by one. An execution which read any state changed by the executions before it, is run
+// again on the working set, so the result is identical to serial execution
+func (ws *workingSet) runActionsInParallel(
+	ctx context.Context,
+	elps []action.SealedEnvelope,
+) ([]*action.Receipt, error) {
+	receipts := make([]*action.Receipt, 0)
+	for i := 0; i < len(elps); {
+		j := i
+		for ; j < len(elps); j++ {
+			if _, ok := elps[j].Action().(*action.Execution); !ok {
+				break
+			}
+		}
+		if j == i {
+			receipt, err := ws.runActionWithCtx(ctx, elps[i])
+			if err != nil {
+				return nil, err
+			}
+			if receipt != nil {
+				receipts = append(receipts, receipt)
+			}
+			i++
+			continue
+		}
+		rs, err := ws.runExecutionsInParallel(ctx, elps[i:j])
+		if err != nil {
+			return nil, err
+		}
+		receipts = append(receipts, rs...)
+		i = j
+	}
+	return receipts, nil
+}
+
+func (ws *workingSet) runExecutionsInParallel(
+	ctx context.Context,
+	elps []action.SealedEnvelope,
+) ([]*action.Receipt, error) {
+	var (
+		baseLock sync.Mutex
+		wg       sync.WaitGroup
+		results  = make([]speculativeResult, len(elps))
+		workers  = make(chan struct{}, ws.parallelWorkers)
+	)
+	for i := range elps {
+		wg.Add(1)
+		workers <- struct{}{}
+		go func(i int) {
+			defer func() {
+				<-workers
+				wg.Done()
+			}()
+			sm := &speculativeStateManager{base: ws, baseLock: &baseLock}
+			if ws.recordAccess {
+				sm.access = newAccessList(elps[i].Hash())
+			}
+			results[i].sm = sm
+			actCtx, err := withActionCtx(ctx, elps[i])
+			if err != nil {
+				results[i].err = err
+				return
+			}
+			actCtx = protocol.WithDeferredDeposits(actCtx, &sm.deposits)
+			results[i].ctx = actCtx
+			start := time.Now()
+			results[i].receipt, results[i].err = handleAction(actCtx, elps[i], sm)
+			results[i].elapsed = time.Since(start)
+		}(i)
+	}
+	wg.Wait()
+
+	receipts := make([]*action.Receipt, 0, len(elps))
+	for i, r := range results {
+		valid, err := r.valid(ws)
+		if err != nil {
+			return nil, err
+		}
+		var receipt *action.Receipt
+		if valid {
+			parallelExecutionMtc.WithLabelValues("accepted").Inc()
+			if err := r.accept(ws); err != nil {
+				return nil, err
+			}
+			ws.recordExecutionTime(elps[i], r.elapsed)
+			receipt = r.receipt
+		} else {
+			parallelExecutionMtc.WithLabelValues("reexecuted").Inc()
+			if receipt, err = ws.runActionWithCtx(ctx, elps[i]); err != nil {
+				return nil, err
+			}
+		}
+		if receipt != nil {
+			receipts = append(receipts, receipt)
+		}
+	}
+	return receipts, nil
+}
+
+// valid returns whether the speculative result equals to running the action on the current working set
+func (r *speculativeResult) valid(ws *workingSet) (bool, error) {
+	if r.err != nil || r.sm.aborted {
+		return false, nil
+	}
+	for _, read := range r.sm.reads {
+		value, exist, err := readRawState(ws, read.ns, read.key)
+		if err != nil {
+			return false, err
+		}
+		if exist != read.exist || !bytes.Equal(value, read.value) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// accept applies the speculative result to the working set, and credits the deferred deposits into the fund on top
+// of the states changed by the actions before it
+func (r *speculativeResult) accept(ws *workingSet) error {
+	if err := r.sm.apply(ws); err != nil {
+		return err
+	}
+	ws.stats.StateReads += r.sm.stats.StateReads
+	ws.stats.StateWrites += r.sm.stats.StateWrites
+	ws.accessList = r.sm.access
+	defer func() {
+		ws.accessList = nil
+	}()
+	if err := r.sm.deposits.Settle(r.ctx, ws); err != nil {
+		return errors.Wrap(err, "failed to credit the deferred deposits")
+	}
+	if r.sm.access != nil {
+		ws.accessLists = append(ws.accessLists, r.sm.access)
+	}
+	return nil
+}
+
+func readRawState(ws *workingSet, ns string, key []byte) ([]byte, bool, error) {
+	var value rawState
+	err := ws.getStateFunc(ns, key, &value)
+	switch errors.Cause(err) {
+	case nil:
+		return value, true, nil
+	case state.ErrStateNotExist:
+		return nil, false, nil
+	default:
+		return nil, false, err
+	}
+}
+
+func (sm *speculativeStateManager) abort() error {
+	sm.aborted = true
+	return errSpeculationAborted
+}
+
+// apply writes the buffered states into the working set in the same order as they are written. The writes have
+// been counted when they are buffered
+func (sm *speculativeStateManager) apply(ws *workingSet) error {
+	for _, w := range sm.writes {
+		value := rawState(w.value)
+		if err := ws.putStateFunc(w.ns, w.key, &value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (sm *speculativeStateManager) Height() (uint64, error) {
+	return sm.base.height, nil
+}
+
+func (sm *speculativeStateManager) State(s interface{}, opts ...protocol.StateOption) (uint64, error) {
+	stateDBMtc.WithLabelValues("get").Inc()
+	sm.stats.StateReads++
+	cfg, err := processOptions(opts...)
+	if err != nil {
+		return sm.base.height, err
+	}
+	if sm.access != nil {
+		sm.access.addRead(cfg.Namespace, cfg.Key)
+	}
+	for i := len(sm.writes) - 1; i >= 0; i-- {
+		if w := sm.writes[i]; w.ns == cfg.Namespace && bytes.Equal(w.key, cfg.Key) {
+			return sm.base.height, state.Deserialize(s, w.value)
+		}
+	}
+	sm.baseLock.Lock()
+	value, exist, err := readRawState(sm.base, cfg.Namespace, cfg.Key)
+	sm.baseLock.Unlock()
+	if err != nil {
+		return sm.base.height, err
+	}
+	sm.reads = append(sm.reads, stateEntry{ns: cfg.Namespace, key: cfg.Key, value: value, exist: exist})
+	if !exist {
+		return sm.base.height, errors.Wrapf(state.ErrStateNotExist, "failed to get state of ns = %x and key = %x", cfg.Namespace, cfg.Key)
+	}
+	return sm.base.height, state.Deserialize(s, value)
+}
+
+func (sm *speculativeStateManager) States(...protocol.StateOption) (uint64, state.Iterator, error) {
+	return sm.base.height, nil, sm.abort()
+}
+
+func (sm *speculativeStateManager) ReadView(string) (interface{}, error) {
+	return nil, sm.abort()
+}
+
+func (sm *speculativeStateManager) Snapshot() int {
+	sm.shots = append(sm.shots, len(sm.writes))
+	return len(sm.shots) - 1
+}
+
+func (sm *speculativeStateManager) Revert(snapshot int) error {
+	if snapshot < 0 || snapshot >= len(sm.shots) {
+		return errors.Errorf("invalid snapshot number = %d", snapshot)
+	}
+	sm.writes = sm.writes[:sm.shots[snapshot]]
+	sm.shots = sm.shots[:snapshot+1]
+	return nil
+}
+
+func (sm *speculativeStateManager) PutState(s interface{}, opts ...protocol.StateOption) (uint64, error) {
+	stateDBMtc.WithLabelValues("put").Inc()
+	sm.stats.StateWrites++
+	cfg, err := processOptions(opts...)
+	if err != nil {
+		return sm.base.height, err
+	}
+	ss, err := state.Serialize(s)
+	if err != nil {
+		return sm.base.height, errors.Wrapf(err, "failed to convert account %v to bytes", s)
+	}
+	if sm.access != nil {
+		sm.access.addWrite(cfg.Namespace, cfg.Key)
+	}
+	sm.writes = append(sm.writes, stateEntry{ns: cfg.Namespace, key: cfg.Key, value: ss, exist: true})
+	return sm.base.height, nil
+}
+
+func (sm *speculativeStateManager) DelState(...protocol.StateOption) (uint64, error) {
+	return sm.base.height, sm.abort()
+}
+
+func (sm *speculativeStateManager) WriteView(string, interface{}) error {
+	return sm.abort()
+}
+
+func (sm *speculativeStateManager) ProtocolDirty(string) bool {
+	sm.aborted = true
+	return false
+}
+
+func (sm *speculativeStateManager) Load(string, string, interface{}) error {
+	return sm.abort()
+}
+
+func (sm *speculativeStateManager) Unload(string, string, interface{}) error {
+	return sm.abort()
+}
+
+func (sm *speculativeStateManager) Reset() {
+	sm.aborted = true
+}
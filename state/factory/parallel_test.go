@@ -0,0 +1,255 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package factory
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"sync"
+	"testing"
+
+	"github.com/iotexproject/go-pkgs/hash"
+	"github.com/iotexproject/iotex-proto/golang/iotextypes"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/action"
+	"github.com/iotexproject/iotex-core/action/protocol"
+	"github.com/iotexproject/iotex-core/action/protocol/account"
+	"github.com/iotexproject/iotex-core/action/protocol/execution"
+	"github.com/iotexproject/iotex-core/action/protocol/rewarding"
+	"github.com/iotexproject/iotex-core/blockchain/block"
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/pkg/unit"
+	"github.com/iotexproject/iotex-core/state"
+	"github.com/iotexproject/iotex-core/test/identityset"
+	"github.com/iotexproject/iotex-core/testutil"
+)
+
+func newTestMapWorkingSet(kv map[string][]byte) *workingSet {
+	return &workingSet{
+		height: 1,
+		getStateFunc: func(ns string, key []byte, s interface{}) error {
+			data, ok := kv[ns+string(key)]
+			if !ok {
+				return errors.Wrapf(state.ErrStateNotExist, "key %x doesn't exist", key)
+			}
+			return state.Deserialize(s, data)
+		},
+		putStateFunc: func(ns string, key []byte, s interface{}) error {
+			data, err := state.Serialize(s)
+			if err != nil {
+				return err
+			}
+			kv[ns+string(key)] = data
+			return nil
+		},
+	}
+}
+
+func TestSpeculativeStateManager(t *testing.T) {
+	require := require.New(t)
+
+	kv := map[string][]byte{
+		AccountKVNamespace + "a": []byte("1"),
+		AccountKVNamespace + "b": []byte("2"),
+	}
+	ws := newTestMapWorkingSet(kv)
+	sm := &speculativeStateManager{base: ws, baseLock: &sync.Mutex{}}
+
+	var v rawState
+	_, err := sm.State(&v, protocol.KeyOption([]byte("a")))
+	require.NoError(err)
+	require.Equal([]byte("1"), []byte(v))
+	_, err = sm.State(&v, protocol.KeyOption([]byte("c")))
+	require.Equal(state.ErrStateNotExist, errors.Cause(err))
+
+	// reverted writes are not applied
+	w1, w2 := rawState("3"), rawState("4")
+	_, err = sm.PutState(&w1, protocol.KeyOption([]byte("b")))
+	require.NoError(err)
+	snapshot := sm.Snapshot()
+	_, err = sm.PutState(&w2, protocol.KeyOption([]byte("b")))
+	require.NoError(err)
+	_, err = sm.State(&v, protocol.KeyOption([]byte("b")))
+	require.NoError(err)
+	require.Equal([]byte("4"), []byte(v))
+	require.NoError(sm.Revert(snapshot))
+	_, err = sm.State(&v, protocol.KeyOption([]byte("b")))
+	require.NoError(err)
+	require.Equal([]byte("3"), []byte(v))
+	require.Equal([]byte("2"), kv[AccountKVNamespace+"b"])
+
+	r := &speculativeResult{sm: sm}
+	valid, err := r.valid(ws)
+	require.NoError(err)
+	require.True(valid)
+	require.NoError(sm.apply(ws))
+	require.Equal([]byte("3"), kv[AccountKVNamespace+"b"])
+
+	// state read by speculation is changed
+	kv[AccountKVNamespace+"a"] = []byte("5")
+	valid, err = r.valid(ws)
+	require.NoError(err)
+	require.False(valid)
+
+	// unsupported operation aborts the speculation
+	sm = &speculativeStateManager{base: ws, baseLock: &sync.Mutex{}}
+	_, err = sm.DelState(protocol.KeyOption([]byte("a")))
+	require.Equal(errSpeculationAborted, err)
+	valid, err = (&speculativeResult{sm: sm}).valid(ws)
+	require.NoError(err)
+	require.False(valid)
+	// so does accessing the dock or the views, which are not tracked
+	sm = &speculativeStateManager{base: ws, baseLock: &sync.Mutex{}}
+	require.Equal(errSpeculationAborted, sm.Unload("poll", "key", &v))
+	require.True(sm.aborted)
+	sm = &speculativeStateManager{base: ws, baseLock: &sync.Mutex{}}
+	_, err = sm.ReadView("staking")
+	require.Equal(errSpeculationAborted, err)
+	require.True(sm.aborted)
+}
+
+func TestSpeculativeResultAccept(t *testing.T) {
+	require := require.New(t)
+
+	kv := map[string][]byte{
+		AccountKVNamespace + "a":    []byte("1"),
+		AccountKVNamespace + "fund": []byte("10"),
+	}
+	ws := newTestMapWorkingSet(kv)
+	ws.recordAccess = true
+	sm := &speculativeStateManager{base: ws, baseLock: &sync.Mutex{}, access: newAccessList(hash.ZeroHash256)}
+
+	var v rawState
+	_, err := sm.State(&v, protocol.KeyOption([]byte("a")))
+	require.NoError(err)
+	w := rawState("2")
+	_, err = sm.PutState(&w, protocol.KeyOption([]byte("a")))
+	require.NoError(err)
+	// the deposit is credited into the fund as of the time it is accepted
+	sm.deposits.Defer(big.NewInt(1), func(_ context.Context, sm protocol.StateManager, amount *big.Int) error {
+		var f rawState
+		if _, err := sm.State(&f, protocol.KeyOption([]byte("fund"))); err != nil {
+			return err
+		}
+		f = append(f, []byte(amount.String())...)
+		_, err := sm.PutState(&f, protocol.KeyOption([]byte("fund")))
+		return err
+	})
+	kv[AccountKVNamespace+"fund"] = []byte("20")
+
+	r := &speculativeResult{sm: sm, ctx: context.Background()}
+	valid, err := r.valid(ws)
+	require.NoError(err)
+	require.True(valid)
+	require.NoError(r.accept(ws))
+	require.Equal([]byte("2"), kv[AccountKVNamespace+"a"])
+	require.Equal([]byte("201"), kv[AccountKVNamespace+"fund"])
+	require.Equal(ExecutionStats{StateReads: 2, StateWrites: 2}, ws.stats)
+	require.Equal(1, len(ws.accessLists))
+	require.Equal(2, len(ws.accessLists[0].Reads))
+	require.Equal(2, len(ws.accessLists[0].Writes))
+	require.Nil(ws.accessList)
+}
+
+func BenchmarkRunExecutionsInParallel(b *testing.B) {
+	for _, workers := range []int{1, 4, 8} {
+		b.Run(fmt.Sprintf("workers-%d", workers), func(b *testing.B) {
+			benchRunExecutions(b, workers)
+		})
+	}
+}
+
+// benchRunExecutions runs blocks of executions from different senders, each of which calls a contract deployed
+// before and deposits the gas fee into the rewarding fund
+func benchRunExecutions(b *testing.B, workers int) {
+	cfg := config.Default
+	cfg.Chain.ParallelExecutionWorkers = workers
+	ge := cfg.Genesis
+	ge.InitBalanceMap = make(map[string]string)
+	for i := 0; i < identityset.Size(); i++ {
+		ge.InitBalanceMap[identityset.Address(i).String()] = unit.ConvertIotxToRau(1000000).String()
+	}
+	cfg.Genesis = ge
+	registry := protocol.NewRegistry()
+	sdb, err := NewStateDB(cfg, InMemStateDBOption(), RegistryStateDBOption(registry), SkipBlockValidationStateDBOption())
+	if err != nil {
+		b.Fatal(err)
+	}
+	for _, p := range []protocol.Protocol{
+		account.NewProtocol(rewarding.DepositGas),
+		rewarding.NewProtocol(0, 0),
+		execution.NewProtocol(func(uint64) (hash.Hash256, error) { return hash.ZeroHash256, nil }, rewarding.DepositGas),
+	} {
+		if err := p.Register(registry); err != nil {
+			b.Fatal(err)
+		}
+	}
+	ctx := protocol.WithBlockchainCtx(context.Background(), protocol.BlockchainCtx{Genesis: ge})
+	if err := sdb.Start(ctx); err != nil {
+		b.Fatal(err)
+	}
+	defer func() {
+		if err := sdb.Stop(ctx); err != nil {
+			b.Fatal(err)
+		}
+	}()
+
+	nonces := make([]uint64, identityset.Size())
+	sign := func(sender int, contract string, data []byte) action.SealedEnvelope {
+		nonces[sender]++
+		exec, err := action.NewExecution(contract, nonces[sender], big.NewInt(0), 1000000, big.NewInt(1), data)
+		if err != nil {
+			b.Fatal(err)
+		}
+		elp := (&action.EnvelopeBuilder{}).SetNonce(nonces[sender]).SetGasLimit(1000000).SetGasPrice(big.NewInt(1)).
+			SetAction(exec).Build()
+		return action.FakeSeal(elp, identityset.PrivateKey(sender).PublicKey())
+	}
+	prevHash := ge.Hash()
+	putBlock := func(height uint64, acts []action.SealedEnvelope) []*action.Receipt {
+		blk, err := block.NewTestingBuilder().
+			SetHeight(height).
+			SetPrevBlockHash(prevHash).
+			SetTimeStamp(testutil.TimestampNow()).
+			AddActions(acts...).
+			SignAndBuild(identityset.PrivateKey(27))
+		if err != nil {
+			b.Fatal(err)
+		}
+		if err := sdb.PutBlock(ctx, &blk); err != nil {
+			b.Fatal(err)
+		}
+		prevHash = blk.HashBlock()
+		return blk.Receipts
+	}
+
+	// the contract stores the calldata into the slot of the caller: PUSH1 0 CALLDATALOAD CALLER SSTORE STOP
+	deploy, err := hex.DecodeString("600680600b6000396000f3" + "600035335500")
+	if err != nil {
+		b.Fatal(err)
+	}
+	receipts := putBlock(1, []action.SealedEnvelope{sign(0, action.EmptyAddress, deploy)})
+	if len(receipts) != 1 || receipts[0].Status != uint64(iotextypes.ReceiptStatus_Success) {
+		b.Fatal("failed to deploy the contract")
+	}
+	contract := receipts[0].ContractAddress
+
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		b.StopTimer()
+		acts := make([]action.SealedEnvelope, 0, identityset.Size())
+		for i := 0; i < identityset.Size(); i++ {
+			acts = append(acts, sign(i, contract, []byte{byte(n), byte(i)}))
+		}
+		b.StartTimer()
+		putBlock(uint64(n+2), acts)
+	}
+}
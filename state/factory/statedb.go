@@ -43,6 +43,8 @@ type stateDB struct {
 	dao                      db.KVStore // the underlying DB for account/contract storage
 	timerFactory             *prometheustimer.TimerFactory
 	workingsets              *cache.ThreadSafeLruCache // lru cache for workingsets
+	executionStats           *cache.ThreadSafeLruCache // lru cache for execution stats of committed blocks
+	accessLists              *cache.ThreadSafeLruCache // lru cache for state access lists of committed blocks
 	protocolView             protocol.View
 	skipBlockValidationOnPut bool
 }
@@ -121,6 +123,8 @@ func NewStateDB(cfg config.Config, opts ...StateDBOption) (Factory, error) {
 		registry:           protocol.NewRegistry(),
 		protocolView:       protocol.View{},
 		workingsets:        cache.NewThreadSafeLruCache(int(cfg.Chain.WorkingSetCacheSize)),
+		executionStats:     cache.NewThreadSafeLruCache(int(cfg.Chain.WorkingSetCacheSize)),
+		accessLists:        cache.NewThreadSafeLruCache(int(cfg.Chain.WorkingSetCacheSize)),
 	}
 	for _, opt := range opts {
 		if err := opt(&sdb, cfg); err != nil {
@@ -207,9 +211,12 @@ func (sdb *stateDB) newWorkingSet(ctx context.Context, height uint64) (*workingS
 	}
 
 	return &workingSet{
-		height:    height,
-		finalized: false,
-		dock:      protocol.NewDock(),
+		height:          height,
+		finalized:       false,
+		dock:            protocol.NewDock(),
+		executionBudget: sdb.cfg.Chain.ActionExecutionBudget,
+		parallelWorkers: sdb.cfg.Chain.ParallelExecutionWorkers,
+		recordAccess:    sdb.cfg.Chain.StateAccessIndexDBPath != "",
 		getStateFunc: func(ns string, key []byte, s interface{}) error {
 			data, err := flusher.KVStoreWithBuffer().Get(ns, key)
 			if err != nil {
@@ -402,8 +409,33 @@ func (sdb *stateDB) PutBlock(ctx context.Context, blk *block.Block) error {
 			sdb.currentChainHeight, h,
 		)
 	}
+	stats, accessLists := ws.stats, ws.accessLists
+	if err := ws.Commit(ctx); err != nil {
+		return err
+	}
+	sdb.executionStats.Add(h, stats)
+	if ws.recordAccess {
+		sdb.accessLists.Add(h, accessLists)
+	}
+	return nil
+}
 
-	return ws.Commit(ctx)
+// ExecutionStats returns the execution stats of a recently committed block
+func (sdb *stateDB) ExecutionStats(height uint64) (ExecutionStats, bool) {
+	data, ok := sdb.executionStats.Get(height)
+	if !ok {
+		return ExecutionStats{}, false
+	}
+	return data.(ExecutionStats), true
+}
+
+// AccessLists returns the state access lists of the actions of a recently committed block
+func (sdb *stateDB) AccessLists(height uint64) ([]*AccessList, bool) {
+	data, ok := sdb.accessLists.Get(height)
+	if !ok {
+		return nil, false
+	}
+	return data.([]*AccessList), true
 }
 
 func (sdb *stateDB) DeleteTipBlock(_ *block.Block) error {
@@ -0,0 +1,327 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package factory
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/iotexproject/go-pkgs/hash"
+	"github.com/pkg/errors"
+
+	"github.com/iotexproject/iotex-core/action/protocol"
+	"github.com/iotexproject/iotex-core/action/protocol/execution/evm"
+	"github.com/iotexproject/iotex-core/action/protocol/insurance"
+	"github.com/iotexproject/iotex-core/action/protocol/paymentchannel"
+	"github.com/iotexproject/iotex-core/action/protocol/recovery"
+	"github.com/iotexproject/iotex-core/action/protocol/rewarding"
+	"github.com/iotexproject/iotex-core/action/protocol/sanction"
+	"github.com/iotexproject/iotex-core/action/protocol/staking"
+	"github.com/iotexproject/iotex-core/action/protocol/subsidy"
+	"github.com/iotexproject/iotex-core/action/protocol/vesting"
+	"github.com/iotexproject/iotex-core/db"
+	"github.com/iotexproject/iotex-core/db/trie"
+	"github.com/iotexproject/iotex-core/db/trie/mptrie"
+	"github.com/iotexproject/iotex-core/state"
+)
+
+// StateNamespaces are the namespaces of the states saved by the protocols, to label the state changes
+var StateNamespaces = []string{
+	AccountKVNamespace,
+	evm.CodeKVNameSpace,
+	evm.PreimageKVNameSpace,
+	protocol.SystemNamespace,
+	insurance.Namespace,
+	paymentchannel.Namespace,
+	recovery.Namespace,
+	rewarding.V2Namespace,
+	sanction.Namespace,
+	staking.StakingNameSpace,
+	staking.CandidateNameSpace,
+	subsidy.Namespace,
+	vesting.Namespace,
+}
+
+type (
+	// StateDiffer streams the differences between the states at two heights
+	StateDiffer interface {
+		DiffStates(from, to uint64, names []string, fn func(*StateChange) error) error
+	}
+
+	// StateChange is a key-level difference between the states at two heights. The value is nil if the state does not
+	// exist at the height
+	StateChange struct {
+		// Namespace is the name of the namespace, or the hex of the hashed namespace if the name is unknown
+		Namespace string
+		// Key is the hash160 of the key of the state, or the slot if the change is of the contract storage
+		Key []byte
+		// Contract is the hashed key of the contract account if the change is of the contract storage
+		Contract []byte
+		From     []byte
+		To       []byte
+	}
+
+	// archiveKVStore is the kv store of the contract storage trie, whose nodes are saved as the states
+	archiveKVStore struct {
+		tlt trie.TwoLayerTrie
+		ns  []byte
+	}
+)
+
+// DiffStates streams the differences between the states at the two heights to fn, in archive mode
+func (sf *factory) DiffStates(from, to uint64, names []string, fn func(*StateChange) error) error {
+	sf.mutex.RLock()
+	defer sf.mutex.RUnlock()
+	if !sf.saveHistory {
+		return ErrNoArchiveData
+	}
+	if from > sf.currentChainHeight || to > sf.currentChainHeight {
+		return errors.Errorf("query height %d or %d is higher than tip height %d", from, to, sf.currentChainHeight)
+	}
+	return DiffStates(sf.dao, from, to, names, fn)
+}
+
+// DiffStates streams the differences between the states at the two heights saved in the archive trie db to fn. The
+// namespaces are labeled by the given names, and the changes of the contract storage follow the change of the
+// contract account
+func DiffStates(kv db.KVStore, from, to uint64, names []string, fn func(*StateChange) error) error {
+	labels := make(map[string]string, len(names))
+	for _, name := range names {
+		labels[string(namespaceKey(name))] = name
+	}
+	trieKV, err := trie.NewKVStore(ArchiveTrieNamespace, kv)
+	if err != nil {
+		return err
+	}
+	fromTrie, err := archiveTrieAt(kv, trieKV, from)
+	if err != nil {
+		return err
+	}
+	toTrie, err := archiveTrieAt(kv, trieKV, to)
+	if err != nil {
+		return err
+	}
+	contractsKey := namespaceKey(evm.ContractKVNameSpace)
+	var fromContracts, toContracts *archiveKVStore
+	return diffTries(fromTrie, toTrie, func(nsKey, fromRoot, toRoot []byte) error {
+		if bytes.Equal(nsKey, contractsKey) {
+			// the nodes of the contract storage tries are diffed by the slots following the contract accounts
+			return nil
+		}
+		ns, ok := labels[string(nsKey)]
+		if !ok {
+			ns = hex.EncodeToString(nsKey)
+		}
+		fromLayerTwo, err := newTrieAt(trieKV, fromRoot, len(hash.Hash160{}))
+		if err != nil {
+			return err
+		}
+		toLayerTwo, err := newTrieAt(trieKV, toRoot, len(hash.Hash160{}))
+		if err != nil {
+			return err
+		}
+		return diffTries(fromLayerTwo, toLayerTwo, func(key, fromValue, toValue []byte) error {
+			if err := fn(&StateChange{Namespace: ns, Key: key, From: fromValue, To: toValue}); err != nil {
+				return err
+			}
+			if ns != AccountKVNamespace {
+				return nil
+			}
+			fromStorage, toStorage := storageRoot(fromValue), storageRoot(toValue)
+			if fromStorage == toStorage {
+				return nil
+			}
+			if fromContracts == nil {
+				if fromContracts, err = newArchiveKVStore(kv, from, evm.ContractKVNameSpace); err != nil {
+					return err
+				}
+				if toContracts, err = newArchiveKVStore(kv, to, evm.ContractKVNameSpace); err != nil {
+					return err
+				}
+			}
+			return diffStorage(fromContracts, toContracts, key, fromStorage, toStorage, fn)
+		})
+	})
+}
+
+func diffStorage(
+	fromKV, toKV trie.KVStore,
+	contract []byte,
+	fromRoot, toRoot hash.Hash256,
+	fn func(*StateChange) error,
+) error {
+	newStorageTrie := func(kvStore trie.KVStore, root hash.Hash256) (trie.Trie, error) {
+		if root == hash.ZeroHash256 {
+			return newTrieAt(kvStore, nil, len(hash.Hash256{}))
+		}
+		return newTrieAt(kvStore, root[:], len(hash.Hash256{}))
+	}
+	fromTrie, err := newStorageTrie(fromKV, fromRoot)
+	if err != nil {
+		return err
+	}
+	toTrie, err := newStorageTrie(toKV, toRoot)
+	if err != nil {
+		return err
+	}
+	return diffTries(fromTrie, toTrie, func(slot, fromValue, toValue []byte) error {
+		return fn(&StateChange{
+			Namespace: evm.ContractKVNameSpace,
+			Key:       slot,
+			Contract:  contract,
+			From:      fromValue,
+			To:        toValue,
+		})
+	})
+}
+
+// storageRoot returns the root of the contract storage trie if the value is a contract account
+func storageRoot(value []byte) hash.Hash256 {
+	if value == nil {
+		return hash.ZeroHash256
+	}
+	var account state.Account
+	if err := account.Deserialize(value); err != nil {
+		return hash.ZeroHash256
+	}
+	return account.Root
+}
+
+// diffTries calls fn with the keys whose values are different in the two tries, the value is nil if the key does not
+// exist in the trie. The leaf iterator returns the leaves in the descending order of the keys, so the tries are merged
+// in a single pass
+func diffTries(fromTrie, toTrie trie.Trie, fn func(key, fromValue, toValue []byte) error) error {
+	fromRoot, err := fromTrie.RootHash()
+	if err != nil {
+		return err
+	}
+	toRoot, err := toTrie.RootHash()
+	if err != nil {
+		return err
+	}
+	if bytes.Equal(fromRoot, toRoot) {
+		return nil
+	}
+	fromIter, err := mptrie.NewLeafIterator(fromTrie)
+	if err != nil {
+		return err
+	}
+	toIter, err := mptrie.NewLeafIterator(toTrie)
+	if err != nil {
+		return err
+	}
+	next := func(iter trie.Iterator) ([]byte, []byte, error) {
+		key, value, err := iter.Next()
+		if err == trie.ErrEndOfIterator {
+			return nil, nil, nil
+		}
+		return key, value, err
+	}
+	fromKey, fromValue, err := next(fromIter)
+	if err != nil {
+		return err
+	}
+	toKey, toValue, err := next(toIter)
+	if err != nil {
+		return err
+	}
+	for fromKey != nil || toKey != nil {
+		switch c := bytes.Compare(fromKey, toKey); {
+		case toKey == nil || (fromKey != nil && c > 0):
+			if err := fn(fromKey, fromValue, nil); err != nil {
+				return err
+			}
+			if fromKey, fromValue, err = next(fromIter); err != nil {
+				return err
+			}
+		case fromKey == nil || c < 0:
+			if err := fn(toKey, nil, toValue); err != nil {
+				return err
+			}
+			if toKey, toValue, err = next(toIter); err != nil {
+				return err
+			}
+		default:
+			if !bytes.Equal(fromValue, toValue) {
+				if err := fn(fromKey, fromValue, toValue); err != nil {
+					return err
+				}
+			}
+			if fromKey, fromValue, err = next(fromIter); err != nil {
+				return err
+			}
+			if toKey, toValue, err = next(toIter); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func archiveTrieAt(kv db.KVStore, trieKV trie.KVStore, height uint64) (trie.Trie, error) {
+	root, err := kv.Get(ArchiveTrieNamespace, []byte(fmt.Sprintf("%s-%d", ArchiveTrieRootKey, height)))
+	if err != nil {
+		return nil, errors.Wrapf(ErrNoArchiveData, "failed to get the state root at height %d", height)
+	}
+	return newTrieAt(trieKV, root, len(hash.Hash160{}))
+}
+
+func newTrieAt(kvStore trie.KVStore, root []byte, keyLength int) (trie.Trie, error) {
+	tr, err := mptrie.New(
+		mptrie.KVStoreOption(kvStore),
+		mptrie.KeyLengthOption(keyLength),
+		mptrie.RootHashOption(root),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := tr.Start(context.Background()); err != nil {
+		return nil, err
+	}
+	return tr, nil
+}
+
+func newArchiveKVStore(kv db.KVStore, height uint64, ns string) (*archiveKVStore, error) {
+	tlt, err := newTwoLayerTrie(ArchiveTrieNamespace, kv, fmt.Sprintf("%s-%d", ArchiveTrieRootKey, height), false)
+	if err != nil {
+		return nil, err
+	}
+	if err := tlt.Start(context.Background()); err != nil {
+		return nil, err
+	}
+	return &archiveKVStore{tlt: tlt, ns: namespaceKey(ns)}, nil
+}
+
+func (s *archiveKVStore) Start(context.Context) error {
+	return nil
+}
+
+func (s *archiveKVStore) Stop(context.Context) error {
+	return nil
+}
+
+func (s *archiveKVStore) Put(key []byte, value []byte) error {
+	return s.tlt.Upsert(s.ns, toLegacyKey(key), value)
+}
+
+func (s *archiveKVStore) Delete(key []byte) error {
+	err := s.tlt.Delete(s.ns, toLegacyKey(key))
+	if errors.Cause(err) == trie.ErrNotExist {
+		return nil
+	}
+	return err
+}
+
+func (s *archiveKVStore) Get(key []byte) ([]byte, error) {
+	value, err := s.tlt.Get(s.ns, toLegacyKey(key))
+	if errors.Cause(err) == trie.ErrNotExist {
+		return nil, errors.Wrapf(db.ErrNotExist, "failed to find key %x", key)
+	}
+	return value, err
+}
@@ -0,0 +1,128 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package factory
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/iotexproject/go-pkgs/hash"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/action/protocol/execution/evm"
+	"github.com/iotexproject/iotex-core/db"
+	"github.com/iotexproject/iotex-core/db/trie/mptrie"
+	"github.com/iotexproject/iotex-core/state"
+)
+
+// archiveOnlyKVStore keeps the deleted trie nodes as the archive mode does
+type archiveOnlyKVStore struct {
+	db.KVStore
+}
+
+func (kv *archiveOnlyKVStore) Delete(string, []byte) error {
+	return nil
+}
+
+func TestDiffStates(t *testing.T) {
+	require := require.New(t)
+	kv := &archiveOnlyKVStore{db.NewMemKVStore()}
+	require.NoError(kv.Start(context.Background()))
+	tlt, err := newTwoLayerTrie(ArchiveTrieNamespace, kv, ArchiveTrieRootKey, true)
+	require.NoError(err)
+	require.NoError(tlt.Start(context.Background()))
+	storage, err := mptrie.New(
+		mptrie.KVStoreOption(&archiveKVStore{tlt: tlt, ns: namespaceKey(evm.ContractKVNameSpace)}),
+		mptrie.KeyLengthOption(len(hash.Hash256{})),
+		// the storage root is 32 bytes as the contract storage trie
+		mptrie.HashFuncOption(func(data []byte) []byte {
+			h := hash.Hash256b(data)
+			return h[:]
+		}),
+		mptrie.AsyncOption(),
+	)
+	require.NoError(err)
+	require.NoError(storage.Start(context.Background()))
+
+	accountKey := func(name string) []byte {
+		return toLegacyKey([]byte(name))
+	}
+	putAccount := func(name string, balance int64, root []byte) []byte {
+		acct := state.EmptyAccount()
+		acct.Balance = big.NewInt(balance)
+		if root != nil {
+			acct.Root = hash.BytesToHash256(root)
+		}
+		ss, err := acct.Serialize()
+		require.NoError(err)
+		require.NoError(tlt.Upsert(namespaceKey(AccountKVNamespace), accountKey(name), ss))
+		return ss
+	}
+	commit := func(height uint64) {
+		rh, err := tlt.RootHash()
+		require.NoError(err)
+		require.NoError(kv.Put(ArchiveTrieNamespace, []byte(fmt.Sprintf("%s-%d", ArchiveTrieRootKey, height)), rh))
+	}
+	slot1, slot2 := hash.Hash256b([]byte("slot1")), hash.Hash256b([]byte("slot2"))
+
+	// height 1
+	require.NoError(storage.Upsert(slot1[:], []byte("v1")))
+	root, err := storage.RootHash()
+	require.NoError(err)
+	alice1 := putAccount("alice", 10, nil)
+	contract1 := putAccount("contract", 0, root)
+	require.NoError(tlt.Upsert(namespaceKey("Staking"), accountKey("bucket"), []byte("bucket")))
+	commit(1)
+
+	// height 2
+	require.NoError(storage.Upsert(slot1[:], []byte("v2")))
+	require.NoError(storage.Upsert(slot2[:], []byte("v3")))
+	root, err = storage.RootHash()
+	require.NoError(err)
+	alice2 := putAccount("alice", 20, nil)
+	contract2 := putAccount("contract", 0, root)
+	bob2 := putAccount("bob", 5, nil)
+	require.NoError(tlt.Delete(namespaceKey("Staking"), accountKey("bucket")))
+	commit(2)
+
+	changes := make(map[string]*StateChange)
+	require.NoError(DiffStates(kv, 1, 2, []string{AccountKVNamespace}, func(c *StateChange) error {
+		changes[c.Namespace+"/"+hex.EncodeToString(c.Key)] = c
+		return nil
+	}))
+	for _, e := range []struct {
+		ns       string
+		key      []byte
+		contract []byte
+		from, to []byte
+	}{
+		{AccountKVNamespace, accountKey("alice"), nil, alice1, alice2},
+		{AccountKVNamespace, accountKey("contract"), nil, contract1, contract2},
+		{AccountKVNamespace, accountKey("bob"), nil, nil, bob2},
+		{hex.EncodeToString(namespaceKey("Staking")), accountKey("bucket"), nil, []byte("bucket"), nil},
+		{evm.ContractKVNameSpace, slot1[:], accountKey("contract"), []byte("v1"), []byte("v2")},
+		{evm.ContractKVNameSpace, slot2[:], accountKey("contract"), nil, []byte("v3")},
+	} {
+		c, ok := changes[e.ns+"/"+hex.EncodeToString(e.key)]
+		require.True(ok, "missing change of %s/%x", e.ns, e.key)
+		require.Equal(e.contract, c.Contract)
+		require.Equal(e.from, c.From)
+		require.Equal(e.to, c.To)
+	}
+	require.Len(changes, 6)
+
+	// no change between the same heights
+	require.NoError(DiffStates(kv, 2, 2, nil, func(c *StateChange) error {
+		return fmt.Errorf("unexpected change %+v", c)
+	}))
+	_, err = newArchiveKVStore(kv, 3, evm.ContractKVNameSpace)
+	require.Error(err)
+	require.Error(DiffStates(kv, 1, 3, nil, func(*StateChange) error { return nil }))
+}
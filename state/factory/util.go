@@ -77,7 +77,11 @@ func generateWorkingSetCacheKey(blkHeader block.Header, producerAddr string) has
 
 func protocolCommit(ctx context.Context, sr protocol.StateManager) error {
 	if reg, ok := protocol.GetRegistry(ctx); ok {
-		for _, p := range reg.All() {
+		protocols, err := reg.Sorted()
+		if err != nil {
+			return err
+		}
+		for _, p := range protocols {
 			post, ok := p.(protocol.Committer)
 			if ok && sr.ProtocolDirty(p.Name()) {
 				if err := post.Commit(ctx, sr); err != nil {
@@ -0,0 +1,193 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package factory
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"sort"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/iotexproject/iotex-core/action/protocol"
+	"github.com/iotexproject/iotex-core/blockchain/block"
+	"github.com/iotexproject/iotex-core/db"
+	"github.com/iotexproject/iotex-core/db/trie"
+	"github.com/iotexproject/iotex-core/db/trie/mptrie"
+	"github.com/iotexproject/iotex-core/state"
+)
+
+// ErrNotInWitness is the error that the block reads the states not covered by the witness
+var ErrNotInWitness = errors.New("state is not in witness")
+
+type (
+	// Witness is the trie nodes of the parent state touched when running a block. Together with the root hash of the
+	// parent state, which the nodes are authenticated against, it is enough to run and verify the block without the
+	// state. Iterating the states of a namespace is not covered, so the blocks doing so cannot have a witness
+	Witness struct {
+		Height uint64   `json:"height"`
+		Nodes  [][]byte `json:"nodes"`
+	}
+
+	// WitnessManager generates the witness of the block on top of the tip, and verifies such a block with its witness
+	WitnessManager interface {
+		// RootHash returns the root hash of the state at the tip
+		RootHash() ([]byte, error)
+		// GenerateWitness runs the block and returns the trie nodes touched
+		GenerateWitness(context.Context, *block.Block) (*Witness, error)
+		// VerifyWithWitness runs the block with the state in the witness only, and verifies the delta state digest and
+		// the receipt root of the block
+		VerifyWithWitness(context.Context, *block.Block, []byte, *Witness) error
+	}
+
+	// witnessRecorder records the trie nodes of the parent state read through it
+	witnessRecorder struct {
+		trie.KVStore
+		mutex   sync.Mutex
+		nodes   map[string][]byte
+		written map[string]bool
+	}
+
+	// witnessReader reads the trie nodes from the witness, a missing node means the witness is incomplete rather than
+	// the state does not exist
+	witnessReader struct {
+		trie.KVStore
+	}
+)
+
+// Serialize serializes the witness
+func (w *Witness) Serialize() ([]byte, error) {
+	return json.Marshal(w)
+}
+
+// Deserialize deserializes the witness
+func (w *Witness) Deserialize(data []byte) error {
+	return json.Unmarshal(data, w)
+}
+
+func newWitnessRecorder(kv trie.KVStore) *witnessRecorder {
+	return &witnessRecorder{
+		KVStore: kv,
+		nodes:   make(map[string][]byte),
+		written: make(map[string]bool),
+	}
+}
+
+func (wr *witnessRecorder) Get(key []byte) ([]byte, error) {
+	value, err := wr.KVStore.Get(key)
+	if err != nil || bytes.Equal(key, []byte(ArchiveTrieRootKey)) {
+		return value, err
+	}
+	wr.mutex.Lock()
+	defer wr.mutex.Unlock()
+	if k := string(key); !wr.written[k] {
+		wr.nodes[k] = value
+	}
+	return value, nil
+}
+
+func (wr *witnessRecorder) Put(key, value []byte) error {
+	wr.mutex.Lock()
+	wr.written[string(key)] = true
+	wr.mutex.Unlock()
+	return wr.KVStore.Put(key, value)
+}
+
+func (wr *witnessRecorder) Delete(key []byte) error {
+	wr.mutex.Lock()
+	wr.written[string(key)] = true
+	wr.mutex.Unlock()
+	return wr.KVStore.Delete(key)
+}
+
+// witness returns the recorded nodes in the order of key
+func (wr *witnessRecorder) witness(height uint64) *Witness {
+	wr.mutex.Lock()
+	defer wr.mutex.Unlock()
+	keys := make([]string, 0, len(wr.nodes))
+	for k := range wr.nodes {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	w := &Witness{Height: height, Nodes: make([][]byte, 0, len(keys))}
+	for _, k := range keys {
+		w.Nodes = append(w.Nodes, wr.nodes[k])
+	}
+	return w
+}
+
+func (wr *witnessReader) Get(key []byte) ([]byte, error) {
+	value, err := wr.KVStore.Get(key)
+	if errors.Cause(err) == trie.ErrNotExist {
+		return nil, errors.Wrapf(ErrNotInWitness, "failed to get trie node %x", key)
+	}
+	return value, err
+}
+
+// RootHash returns the root hash of the state at the tip
+func (sf *factory) RootHash() ([]byte, error) {
+	sf.mutex.RLock()
+	defer sf.mutex.RUnlock()
+	return sf.rootHash()
+}
+
+// GenerateWitness runs the block on top of the tip, and returns the trie nodes of the tip state touched
+func (sf *factory) GenerateWitness(ctx context.Context, blk *block.Block) (*Witness, error) {
+	ctx = protocol.WithRegistry(ctx, sf.registry)
+	var recorder *witnessRecorder
+	sf.mutex.RLock()
+	ws, err := sf.newWorkingSetOnDB(ctx, sf.currentChainHeight+1, sf.dao, func(kv trie.KVStore) trie.KVStore {
+		recorder = newWitnessRecorder(kv)
+		return recorder
+	})
+	sf.mutex.RUnlock()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to obtain working set from state factory")
+	}
+	ws.statesFunc = statesNotInWitness
+	if err := ws.ValidateBlock(ctx, blk); err != nil {
+		return nil, errors.Wrapf(err, "failed to generate witness of block %d", blk.Height())
+	}
+	return recorder.witness(blk.Height()), nil
+}
+
+// VerifyWithWitness runs the block on top of the parent state of the root hash, with nothing but the trie nodes in the
+// witness. The nodes are stored by their hashes, so a forged node is never reachable from the parent root. The
+// protocol views are still read from the factory, which is left for the stateless validators to replace
+func (sf *factory) VerifyWithWitness(ctx context.Context, blk *block.Block, parentRoot []byte, w *Witness) error {
+	if w == nil || w.Height != blk.Height() {
+		return errors.Errorf("witness is not for block %d", blk.Height())
+	}
+	ctx = protocol.WithRegistry(ctx, sf.registry)
+	kv := db.NewMemKVStore()
+	if err := kv.Put(ArchiveTrieNamespace, []byte(ArchiveTrieRootKey), parentRoot); err != nil {
+		return err
+	}
+	for _, node := range w.Nodes {
+		if err := kv.Put(ArchiveTrieNamespace, mptrie.DefaultHashFunc(node), node); err != nil {
+			return err
+		}
+	}
+	ws, err := sf.newWorkingSetOnDB(ctx, blk.Height(), kv, func(kv trie.KVStore) trie.KVStore {
+		return &witnessReader{kv}
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to obtain working set from witness")
+	}
+	ws.statesFunc = statesNotInWitness
+	return ws.ValidateBlock(ctx, blk)
+}
+
+func statesNotInWitness(opts ...protocol.StateOption) (uint64, state.Iterator, error) {
+	cfg, err := processOptions(opts...)
+	if err != nil {
+		return 0, nil, err
+	}
+	return 0, nil, errors.Wrapf(ErrNotInWitness, "failed to iterate namespace %s", cfg.Namespace)
+}
@@ -0,0 +1,92 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package factory
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/action"
+	"github.com/iotexproject/iotex-core/action/protocol"
+	"github.com/iotexproject/iotex-core/action/protocol/account"
+	"github.com/iotexproject/iotex-core/action/protocol/rewarding"
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/test/identityset"
+	"github.com/iotexproject/iotex-core/test/mock/mock_actpool"
+	"github.com/iotexproject/iotex-core/testutil"
+)
+
+func TestWitness(t *testing.T) {
+	require := require.New(t)
+
+	cfg := config.Default
+	cfg.Genesis.InitBalanceMap[identityset.Address(28).String()] = "100"
+	cfg.Genesis.InitBalanceMap[identityset.Address(29).String()] = "200"
+	registry := protocol.NewRegistry()
+	sf, err := NewFactory(cfg, InMemTrieOption(), RegistryOption(registry))
+	require.NoError(err)
+	require.NoError(account.NewProtocol(rewarding.DepositGas).Register(registry))
+	ctx := protocol.WithBlockCtx(
+		protocol.WithBlockchainCtx(context.Background(), protocol.BlockchainCtx{Genesis: cfg.Genesis}),
+		protocol.BlockCtx{},
+	)
+	require.NoError(sf.Start(ctx))
+	defer func() {
+		require.NoError(sf.Stop(ctx))
+	}()
+
+	tsf, err := testutil.SignedTransfer(identityset.Address(29).String(), identityset.PrivateKey(28), 1, big.NewInt(10), nil, 100000, big.NewInt(0))
+	require.NoError(err)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ap := mock_actpool.NewMockActPool(ctrl)
+	ap.EXPECT().PendingActionMap().Return(map[string][]action.SealedEnvelope{
+		identityset.Address(28).String(): {tsf},
+	}).Times(1)
+	ctx = protocol.WithBlockchainCtx(
+		protocol.WithBlockCtx(context.Background(), protocol.BlockCtx{
+			BlockHeight: 1,
+			Producer:    identityset.Address(27),
+			GasLimit:    1000000,
+		}),
+		protocol.BlockchainCtx{Genesis: cfg.Genesis},
+	)
+	blkBuilder, err := sf.NewBlockBuilder(ctx, ap, nil)
+	require.NoError(err)
+	blk, err := blkBuilder.SignAndBuild(identityset.PrivateKey(27))
+	require.NoError(err)
+
+	wm, ok := sf.(WitnessManager)
+	require.True(ok)
+	parentRoot, err := wm.RootHash()
+	require.NoError(err)
+	w, err := wm.GenerateWitness(ctx, &blk)
+	require.NoError(err)
+	require.Equal(uint64(1), w.Height)
+	require.NotEmpty(w.Nodes)
+	data, err := w.Serialize()
+	require.NoError(err)
+	w = &Witness{}
+	require.NoError(w.Deserialize(data))
+	require.NoError(wm.VerifyWithWitness(ctx, &blk, parentRoot, w))
+
+	// incomplete witness
+	require.Error(wm.VerifyWithWitness(ctx, &blk, parentRoot, &Witness{Height: 1, Nodes: w.Nodes[1:]}))
+	// wrong parent state
+	require.Error(wm.VerifyWithWitness(ctx, &blk, []byte("wrong root"), w))
+	// witness of another block
+	require.Error(wm.VerifyWithWitness(ctx, &blk, parentRoot, &Witness{Height: 2, Nodes: w.Nodes}))
+
+	require.NoError(sf.PutBlock(ctx, &blk))
+	root, err := wm.RootHash()
+	require.NoError(err)
+	require.NotEqual(parentRoot, root)
+}
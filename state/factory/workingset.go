@@ -8,7 +8,11 @@ package factory
 
 import (
 	"context"
+	"encoding/hex"
+	"fmt"
 	"sort"
+	"strings"
+	"time"
 
 	"github.com/iotexproject/go-pkgs/hash"
 	"github.com/iotexproject/iotex-address/address"
@@ -35,10 +39,18 @@ var (
 		},
 		[]string{"type"},
 	)
+	actionOverBudgetMtc = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "iotex_action_over_budget",
+			Help: "Number of actions exceeding the execution time budget",
+		},
+		[]string{"type"},
+	)
 )
 
 func init() {
 	prometheus.MustRegister(stateDBMtc)
+	prometheus.MustRegister(actionOverBudgetMtc)
 }
 
 type (
@@ -59,6 +71,22 @@ type (
 		putStateFunc  func(string, []byte, interface{}) error
 		revertFunc    func(int) error
 		snapshotFunc  func() int
+		stats         ExecutionStats
+		// executionBudget is the soft limit of time to run an action, 0 means no limit
+		executionBudget time.Duration
+		// parallelWorkers is the number of workers to run executions in parallel, 0 or 1 means serial execution
+		parallelWorkers int
+		// recordAccess enables recording the keys of the states accessed by each action into accessLists
+		recordAccess bool
+		accessList   *AccessList
+		accessLists  []*AccessList
+	}
+
+	// ExecutionStats is the stats collected when running the actions of a block
+	ExecutionStats struct {
+		ExecutionTime time.Duration
+		StateReads    uint64
+		StateWrites   uint64
 	}
 
 	workingSetCreator interface {
@@ -107,17 +135,16 @@ func (ws *workingSet) runActions(
 	if err := ws.validate(ctx); err != nil {
 		return nil, err
 	}
+	if ws.parallelWorkers > 1 {
+		return ws.runActionsInParallel(ctx, elps)
+	}
 	// Handle actions
 	receipts := make([]*action.Receipt, 0)
 	for _, elp := range elps {
-		ctx, err := withActionCtx(ctx, elp)
+		receipt, err := ws.runActionWithCtx(ctx, elp)
 		if err != nil {
 			return nil, err
 		}
-		receipt, err := ws.runAction(ctx, elp)
-		if err != nil {
-			return nil, errors.Wrap(err, "error when run action")
-		}
 		if receipt != nil {
 			receipts = append(receipts, receipt)
 		}
@@ -126,6 +153,18 @@ func (ws *workingSet) runActions(
 	return receipts, nil
 }
 
+func (ws *workingSet) runActionWithCtx(ctx context.Context, elp action.SealedEnvelope) (*action.Receipt, error) {
+	ctx, err := withActionCtx(ctx, elp)
+	if err != nil {
+		return nil, err
+	}
+	receipt, err := ws.runAction(ctx, elp)
+	if err != nil {
+		return nil, errors.Wrap(err, "error when run action")
+	}
+	return receipt, nil
+}
+
 func withActionCtx(ctx context.Context, selp action.SealedEnvelope) (context.Context, error) {
 	var actionCtx protocol.ActionCtx
 	caller, err := address.FromBytes(selp.SrcPubkey().Hash())
@@ -148,6 +187,29 @@ func withActionCtx(ctx context.Context, selp action.SealedEnvelope) (context.Con
 func (ws *workingSet) runAction(
 	ctx context.Context,
 	elp action.SealedEnvelope,
+) (*action.Receipt, error) {
+	start := time.Now()
+	defer func() {
+		ws.recordExecutionTime(elp, time.Since(start))
+	}()
+	if !ws.recordAccess {
+		return handleAction(ctx, elp, ws)
+	}
+	ws.accessList = newAccessList(elp.Hash())
+	defer func() {
+		ws.accessList = nil
+	}()
+	receipt, err := handleAction(ctx, elp, ws)
+	if err == nil {
+		ws.accessLists = append(ws.accessLists, ws.accessList)
+	}
+	return receipt, err
+}
+
+func handleAction(
+	ctx context.Context,
+	elp action.SealedEnvelope,
+	sm protocol.StateManager,
 ) (*action.Receipt, error) {
 	if protocol.MustGetBlockCtx(ctx).GasLimit < protocol.MustGetActionCtx(ctx).IntrinsicGas {
 		return nil, errors.Wrap(action.ErrHitGasLimit, "block gas limit exceeded")
@@ -158,7 +220,7 @@ func (ws *workingSet) runAction(
 		return nil, nil
 	}
 	for _, actionHandler := range reg.All() {
-		receipt, err := actionHandler.Handle(ctx, elp.Action(), ws)
+		receipt, err := actionHandler.Handle(ctx, elp.Action(), sm)
 		if err != nil {
 			return nil, errors.Wrapf(
 				err,
@@ -174,6 +236,33 @@ func (ws *workingSet) runAction(
 	return nil, nil
 }
 
+func (ws *workingSet) recordExecutionTime(elp action.SealedEnvelope, elapsed time.Duration) {
+	ws.stats.ExecutionTime += elapsed
+	if ws.executionBudget > 0 && elapsed > ws.executionBudget {
+		ws.logOverBudgetAction(elp, elapsed)
+	}
+}
+
+func (ws *workingSet) logOverBudgetAction(elp action.SealedEnvelope, elapsed time.Duration) {
+	actType := strings.TrimPrefix(fmt.Sprintf("%T", elp.Action()), "*action.")
+	actionOverBudgetMtc.WithLabelValues(actType).Inc()
+	h := elp.Hash()
+	fields := []zap.Field{
+		zap.Uint64("height", ws.height),
+		zap.String("actionHash", hex.EncodeToString(h[:])),
+		zap.String("type", actType),
+		zap.Duration("elapsed", elapsed),
+		zap.Duration("budget", ws.executionBudget),
+	}
+	if exec, ok := elp.Action().(*action.Execution); ok {
+		fields = append(fields, zap.String("contract", exec.Contract()))
+		if data := exec.Data(); len(data) >= 4 {
+			fields = append(fields, zap.String("selector", hex.EncodeToString(data[:4])))
+		}
+	}
+	log.L().Warn("Action exceeds execution time budget.", fields...)
+}
+
 func (ws *workingSet) finalize() error {
 	if ws.finalized {
 		return errors.New("Cannot finalize a working set twice")
@@ -214,34 +303,53 @@ func (ws *workingSet) GetDB() db.KVStore {
 // State pulls a state from DB
 func (ws *workingSet) State(s interface{}, opts ...protocol.StateOption) (uint64, error) {
 	stateDBMtc.WithLabelValues("get").Inc()
+	ws.stats.StateReads++
 	cfg, err := processOptions(opts...)
 	if err != nil {
 		return ws.height, err
 	}
+	if ws.accessList != nil {
+		ws.accessList.addRead(cfg.Namespace, cfg.Key)
+	}
 	return ws.height, ws.getStateFunc(cfg.Namespace, cfg.Key, s)
 }
 
 func (ws *workingSet) States(opts ...protocol.StateOption) (uint64, state.Iterator, error) {
+	if ws.accessList != nil {
+		cfg, err := processOptions(opts...)
+		if err != nil {
+			return ws.height, nil, err
+		}
+		ws.accessList.addRead(cfg.Namespace, nil)
+	}
 	return ws.statesFunc(opts...)
 }
 
 // PutState puts a state into DB
 func (ws *workingSet) PutState(s interface{}, opts ...protocol.StateOption) (uint64, error) {
 	stateDBMtc.WithLabelValues("put").Inc()
+	ws.stats.StateWrites++
 	cfg, err := processOptions(opts...)
 	if err != nil {
 		return ws.height, err
 	}
+	if ws.accessList != nil {
+		ws.accessList.addWrite(cfg.Namespace, cfg.Key)
+	}
 	return ws.height, ws.putStateFunc(cfg.Namespace, cfg.Key, s)
 }
 
 // DelState deletes a state from DB
 func (ws *workingSet) DelState(opts ...protocol.StateOption) (uint64, error) {
 	stateDBMtc.WithLabelValues("delete").Inc()
+	ws.stats.StateWrites++
 	cfg, err := processOptions(opts...)
 	if err != nil {
 		return ws.height, err
 	}
+	if ws.accessList != nil {
+		ws.accessList.addWrite(cfg.Namespace, cfg.Key)
+	}
 	return ws.height, ws.delStateFunc(cfg.Namespace, cfg.Key)
 }
 
@@ -356,6 +464,9 @@ func (ws *workingSet) process(ctx context.Context, actions []action.SealedEnvelo
 	if err != nil {
 		return err
 	}
+	if err := ws.appendSystemLogs(ctx, receipts); err != nil {
+		return err
+	}
 	ws.receipts = receipts
 	return ws.finalize()
 }
@@ -395,6 +506,11 @@ func (ws *workingSet) pickAndRunActions(
 				actionIterator.PopAccount()
 				continue
 			}
+			// skip the action past its deadline, and the following actions of the account
+			if nextAction.Expired(blkCtx.BlockTimeStamp) {
+				actionIterator.PopAccount()
+				continue
+			}
 			if ctx, err = withActionCtx(ctx, nextAction); err == nil {
 				for _, p := range reg.All() {
 					if validator, ok := p.(protocol.ActionValidator); ok {
@@ -451,11 +567,43 @@ func (ws *workingSet) pickAndRunActions(
 		}
 		executedActions = append(executedActions, selp)
 	}
+	if err := ws.appendSystemLogs(ctx, receipts); err != nil {
+		return nil, err
+	}
 	ws.receipts = receipts
 
 	return executedActions, ws.finalize()
 }
 
+// appendSystemLogs appends the logs created by the protocols outside the actions to the receipt of the last action,
+// which is a post system action of the block
+func (ws *workingSet) appendSystemLogs(ctx context.Context, receipts []*action.Receipt) error {
+	for _, p := range protocol.MustGetRegistry(ctx).All() {
+		slc, ok := p.(protocol.SystemLogsCreator)
+		if !ok {
+			continue
+		}
+		logs, err := slc.CreateSystemLogs(ctx, ws)
+		if err != nil {
+			return err
+		}
+		if len(logs) == 0 {
+			continue
+		}
+		if len(receipts) == 0 {
+			log.L().Warn("No receipt to append the system logs.", zap.Int("logs", len(logs)))
+			return nil
+		}
+		r := receipts[len(receipts)-1]
+		for _, l := range logs {
+			l.ActionHash = r.ActionHash
+			l.Index = uint(len(r.Logs()))
+			r.AddLogs(l)
+		}
+	}
+	return nil
+}
+
 func (ws *workingSet) ValidateBlock(ctx context.Context, blk *block.Block) error {
 	if err := ws.validateNonce(blk); err != nil {
 		return errors.Wrap(err, "failed to validate nonce")
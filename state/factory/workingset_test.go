@@ -112,6 +112,30 @@ func TestWorkingSet_ReadWriteView(t *testing.T) {
 	}
 }
 
+func TestWorkingSet_AccessList(t *testing.T) {
+	r := require.New(t)
+	for _, ws := range []*workingSet{
+		newFactoryWorkingSet(t),
+		newStateDBWorkingSet(t),
+	} {
+		// not recorded outside of running an action
+		_, err := ws.PutState(&testString{"v"}, protocol.NamespaceOption("ns"), protocol.KeyOption([]byte{1}))
+		r.NoError(err)
+
+		ws.accessList = newAccessList(hash.Hash256b([]byte("action")))
+		_, err = ws.State(&testString{}, protocol.NamespaceOption("ns"), protocol.KeyOption([]byte{1}))
+		r.NoError(err)
+		_, err = ws.State(&testString{}, protocol.NamespaceOption("ns"), protocol.KeyOption([]byte{1}))
+		r.NoError(err)
+		_, err = ws.PutState(&testString{"v"}, protocol.NamespaceOption("ns"), protocol.KeyOption([]byte{2}))
+		r.NoError(err)
+		_, err = ws.DelState(protocol.NamespaceOption("ns"), protocol.KeyOption([]byte{1}))
+		r.NoError(err)
+		r.Equal([]StateKey{{Namespace: "ns", Key: "01"}}, ws.accessList.Reads)
+		r.Equal([]StateKey{{Namespace: "ns", Key: "02"}, {Namespace: "ns", Key: "01"}}, ws.accessList.Writes)
+	}
+}
+
 func TestWorkingSet_Dock(t *testing.T) {
 	var (
 		r   = require.New(t)
@@ -0,0 +1,47 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package state
+
+import (
+	"encoding/binary"
+
+	"github.com/pkg/errors"
+)
+
+// _versionedPrefix marks a versioned encoding. A protobuf message never starts with a zero byte since field number 0
+// is invalid, so data without the prefix is a legacy (version 0) encoding
+const _versionedPrefix byte = 0x00
+
+var (
+	// ErrUnsupportedVersion indicates the serialization version is not supported
+	ErrUnsupportedVersion = errors.New("unsupported serialization version")
+)
+
+// EncodeVersioned wraps the protobuf payload with its schema version
+func EncodeVersioned(version uint32, payload []byte) []byte {
+	buf := make([]byte, 1+binary.MaxVarintLen32, 1+binary.MaxVarintLen32+len(payload))
+	buf[0] = _versionedPrefix
+	n := binary.PutUvarint(buf[1:], uint64(version))
+	return append(buf[:1+n], payload...)
+}
+
+// DecodeVersioned returns the schema version and protobuf payload of the data, legacy encoding is returned as version 0
+func DecodeVersioned(buf []byte) (uint32, []byte, error) {
+	if len(buf) == 0 || buf[0] != _versionedPrefix {
+		return 0, buf, nil
+	}
+	version, n := binary.Uvarint(buf[1:])
+	if n <= 0 || version == 0 || version > uint64(^uint32(0)) {
+		return 0, nil, errors.New("invalid version header")
+	}
+	return uint32(version), buf[1+n:], nil
+}
+
+// IsVersioned returns true if the data is in versioned encoding
+func IsVersioned(buf []byte) bool {
+	return len(buf) > 0 && buf[0] == _versionedPrefix
+}
@@ -77,6 +77,20 @@ func (mr *MockActPoolMockRecorder) PendingActionMap() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PendingActionMap", reflect.TypeOf((*MockActPool)(nil).PendingActionMap))
 }
 
+// QueuedActionMap mocks base method
+func (m *MockActPool) QueuedActionMap() map[string][]action.SealedEnvelope {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueuedActionMap")
+	ret0, _ := ret[0].(map[string][]action.SealedEnvelope)
+	return ret0
+}
+
+// QueuedActionMap indicates an expected call of QueuedActionMap
+func (mr *MockActPoolMockRecorder) QueuedActionMap() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueuedActionMap", reflect.TypeOf((*MockActPool)(nil).QueuedActionMap))
+}
+
 // Add mocks base method
 func (m *MockActPool) Add(ctx context.Context, act action.SealedEnvelope) error {
 	m.ctrl.T.Helper()
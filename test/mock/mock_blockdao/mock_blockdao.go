@@ -10,6 +10,7 @@ import (
 	hash "github.com/iotexproject/go-pkgs/hash"
 	action "github.com/iotexproject/iotex-core/action"
 	block "github.com/iotexproject/iotex-core/blockchain/block"
+	blockdao "github.com/iotexproject/iotex-core/blockchain/blockdao"
 	iotextypes "github.com/iotexproject/iotex-proto/golang/iotextypes"
 	reflect "reflect"
 )
@@ -301,6 +302,20 @@ func (mr *MockBlockDAOMockRecorder) DeleteBlockToTarget(arg0 interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBlockToTarget", reflect.TypeOf((*MockBlockDAO)(nil).DeleteBlockToTarget), arg0)
 }
 
+// AddIndexer mocks base method
+func (m *MockBlockDAO) AddIndexer(arg0 context.Context, arg1 blockdao.BlockIndexer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddIndexer", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddIndexer indicates an expected call of AddIndexer
+func (mr *MockBlockDAOMockRecorder) AddIndexer(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddIndexer", reflect.TypeOf((*MockBlockDAO)(nil).AddIndexer), arg0, arg1)
+}
+
 // MockBlockIndexer is a mock of BlockIndexer interface
 type MockBlockIndexer struct {
 	ctrl     *gomock.Controller
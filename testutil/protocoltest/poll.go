@@ -0,0 +1,97 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package protocoltest
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/iotexproject/iotex-core/action/protocol"
+	"github.com/iotexproject/iotex-core/action/protocol/vote"
+	"github.com/iotexproject/iotex-core/state"
+)
+
+type (
+	// Candidates are the candidates of the epochs by the epoch start height, its GetCandidates is a poll.GetCandidates
+	Candidates map[uint64][]*state.Candidate
+
+	// Producers are the producers of the blocks by height, its Productivity is a poll.Productivity
+	Producers map[uint64]string
+
+	// BlockTimes are the timestamps of the blocks by height, its GetBlockTime is a poll.GetBlockTime
+	BlockTimes map[uint64]time.Time
+
+	// ProbationLists are the probation lists of the current and the next epoch, its GetProbationList is a
+	// poll.GetProbationList
+	ProbationLists struct {
+		Current *vote.ProbationList
+		Next    *vote.ProbationList
+		// Height is the height of the state returned along with the lists
+		Height uint64
+	}
+
+	// UnproductiveDelegate holds the unproductive delegates of the recent epochs, its GetUnproductiveDelegate is a
+	// poll.GetUnproductiveDelegate
+	UnproductiveDelegate struct {
+		*vote.UnproductiveDelegate
+	}
+)
+
+// GetCandidates returns the candidates of the epoch starting at the height
+func (c Candidates) GetCandidates(_ protocol.StateReader, height uint64, _ bool, _ bool) ([]*state.Candidate, uint64, error) {
+	candidates, ok := c[height]
+	if !ok {
+		return nil, 0, errors.Wrapf(state.ErrStateNotExist, "candidates of epoch start height %d", height)
+	}
+	return candidates, height, nil
+}
+
+// Productivity returns the number of blocks produced by each producer from the start to the end height
+func (p Producers) Productivity(start, end uint64) (map[string]uint64, error) {
+	if start > end {
+		return nil, errors.Errorf("invalid range [%d, %d]", start, end)
+	}
+	stats := make(map[string]uint64)
+	for height := start; height <= end; height++ {
+		producer, ok := p[height]
+		if !ok {
+			return nil, errors.Errorf("block %d is not found", height)
+		}
+		stats[producer]++
+	}
+	return stats, nil
+}
+
+// GetBlockTime returns the timestamp of the block at the height
+func (t BlockTimes) GetBlockTime(height uint64) (time.Time, error) {
+	ts, ok := t[height]
+	if !ok {
+		return time.Time{}, errors.Errorf("block %d is not found", height)
+	}
+	return ts, nil
+}
+
+// GetProbationList returns the probation list of the current or the next epoch
+func (l *ProbationLists) GetProbationList(_ protocol.StateReader, readFromNext bool) (*vote.ProbationList, uint64, error) {
+	pl := l.Current
+	if readFromNext {
+		pl = l.Next
+	}
+	if pl == nil {
+		return nil, 0, errors.Wrap(state.ErrStateNotExist, "probation list")
+	}
+	return pl, l.Height, nil
+}
+
+// GetUnproductiveDelegate returns the unproductive delegates of the recent epochs
+func (u *UnproductiveDelegate) GetUnproductiveDelegate(protocol.StateReader) (*vote.UnproductiveDelegate, error) {
+	if u.UnproductiveDelegate == nil {
+		return nil, errors.Wrap(state.ErrStateNotExist, "unproductive delegate")
+	}
+	return u.UnproductiveDelegate, nil
+}
@@ -0,0 +1,109 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package protocoltest
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/action/protocol"
+	"github.com/iotexproject/iotex-core/action/protocol/poll"
+	"github.com/iotexproject/iotex-core/action/protocol/rolldpos"
+	"github.com/iotexproject/iotex-core/action/protocol/vote"
+	"github.com/iotexproject/iotex-core/blockchain/genesis"
+	"github.com/iotexproject/iotex-core/state"
+	"github.com/iotexproject/iotex-core/test/identityset"
+)
+
+func TestStateManager(t *testing.T) {
+	require := require.New(t)
+
+	newList := func(votes int64) *state.CandidateList {
+		return &state.CandidateList{{Address: identityset.Address(0).String(), Votes: big.NewInt(votes)}}
+	}
+	sm := NewStateManager()
+	var _ protocol.StateManager = sm
+	_, err := sm.State(newList(0), protocol.NamespaceOption("ns"), protocol.KeyOption([]byte("a")))
+	require.Equal(state.ErrStateNotExist, errors.Cause(err))
+	_, err = sm.PutState(newList(1), protocol.NamespaceOption("ns"), protocol.KeyOption([]byte("a")))
+	require.NoError(err)
+	snapshot := sm.Snapshot()
+	_, err = sm.PutState(newList(2), protocol.NamespaceOption("ns"), protocol.KeyOption([]byte("b")))
+	require.NoError(err)
+	_, err = sm.DelState(protocol.NamespaceOption("ns"), protocol.KeyOption([]byte("a")))
+	require.NoError(err)
+	_, iter, err := sm.States(protocol.NamespaceOption("ns"))
+	require.NoError(err)
+	require.Equal(1, iter.Size())
+
+	require.NoError(sm.Revert(snapshot))
+	l := newList(0)
+	_, err = sm.State(l, protocol.NamespaceOption("ns"), protocol.KeyOption([]byte("a")))
+	require.NoError(err)
+	require.Equal("1", (*l)[0].Votes.String())
+	_, err = sm.State(l, protocol.NamespaceOption("ns"), protocol.KeyOption([]byte("b")))
+	require.Equal(state.ErrStateNotExist, errors.Cause(err))
+	require.Error(sm.Revert(snapshot + 1))
+
+	_, err = sm.ReadView("poll")
+	require.Equal(protocol.ErrNoName, errors.Cause(err))
+	require.NoError(sm.WriteView("poll", 1))
+	v, err := sm.ReadView("poll")
+	require.NoError(err)
+	require.Equal(1, v)
+}
+
+func TestSlasher(t *testing.T) {
+	require := require.New(t)
+
+	g := genesis.Default
+	g.EasterBlockHeight = 1
+	a, b, c := identityset.Address(1).String(), identityset.Address(2).String(), identityset.Address(3).String()
+	candidates := Candidates{
+		5: {
+			{Address: a, Votes: big.NewInt(300)},
+			{Address: b, Votes: big.NewInt(200)},
+			{Address: c, Votes: big.NewInt(100)},
+		},
+	}
+	probation := &ProbationLists{
+		Current: &vote.ProbationList{ProbationInfo: map[string]uint32{b: 1}, IntensityRate: 90},
+		Height:  5,
+	}
+	producers := Producers{1: a, 2: b, 3: a, 4: c}
+	upd := &UnproductiveDelegate{}
+	sh, err := poll.NewSlasher(&g, producers.Productivity, candidates.GetCandidates, probation.GetProbationList,
+		upd.GetUnproductiveDelegate, nil, 4, 4, 1, 85, 2, 4, 90)
+	require.NoError(err)
+	registry := protocol.NewRegistry()
+	require.NoError(rolldpos.NewProtocol(4, 4, 1).Register(registry))
+	ctx := protocol.WithRegistry(context.Background(), registry)
+
+	sm := NewStateManager()
+	sm.SetHeight(6)
+	cands, height, err := sh.GetCandidates(ctx, sm, false)
+	require.NoError(err)
+	require.Equal(uint64(5), height)
+	require.Equal(3, len(cands))
+	require.Equal([]string{a, c, b}, []string{cands[0].Address, cands[1].Address, cands[2].Address})
+	require.Equal("20", cands[2].Votes.String())
+	// the candidates of next epoch are not there
+	_, _, err = sh.GetCandidates(ctx, sm, true)
+	require.Equal(state.ErrStateNotExist, errors.Cause(err))
+
+	produce, err := producers.Productivity(1, 4)
+	require.NoError(err)
+	require.Equal(map[string]uint64{a: 2, b: 1, c: 1}, produce)
+	_, err = producers.Productivity(1, 5)
+	require.Error(err)
+	_, err = upd.GetUnproductiveDelegate(sm)
+	require.Equal(state.ErrStateNotExist, errors.Cause(err))
+}
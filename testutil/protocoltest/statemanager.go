@@ -0,0 +1,170 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+// Package protocoltest provides the in memory fakes of the interfaces and the functions the protocols depend on, so
+// that the protocols such as poll and its slasher can be unit tested without a full chain. The generated mocks of the
+// protocol interfaces are in test/mock/mock_protocol and test/mock/mock_chainmanager.
+package protocoltest
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/pkg/errors"
+
+	"github.com/iotexproject/iotex-core/action/protocol"
+	"github.com/iotexproject/iotex-core/state"
+)
+
+type (
+	// StateManager is an in memory protocol.StateManager, which also serves as a protocol.StateReader
+	StateManager struct {
+		protocol.Dock
+		height    uint64
+		states    map[string]map[string][]byte
+		snapshots []map[string]map[string][]byte
+		view      protocol.View
+	}
+)
+
+// NewStateManager creates an empty in memory state manager at height 0
+func NewStateManager() *StateManager {
+	return &StateManager{
+		Dock:   protocol.NewDock(),
+		states: make(map[string]map[string][]byte),
+		view:   make(protocol.View),
+	}
+}
+
+// SetHeight sets the height of the state
+func (sm *StateManager) SetHeight(height uint64) {
+	sm.height = height
+}
+
+// Height returns the height of the state
+func (sm *StateManager) Height() (uint64, error) {
+	return sm.height, nil
+}
+
+// State reads the state of the key in the namespace
+func (sm *StateManager) State(s interface{}, opts ...protocol.StateOption) (uint64, error) {
+	cfg, err := protocol.CreateStateConfig(opts...)
+	if err != nil {
+		return 0, err
+	}
+	value, ok := sm.states[cfg.Namespace][string(cfg.Key)]
+	if !ok {
+		return sm.height, errors.Wrapf(state.ErrStateNotExist, "key %x in namespace %s", cfg.Key, cfg.Namespace)
+	}
+	ss, ok := s.(state.Deserializer)
+	if !ok {
+		return sm.height, errors.New("state is not a deserializer")
+	}
+	return sm.height, ss.Deserialize(value)
+}
+
+// States reads the states in the namespace matching the filter, in the order of key
+func (sm *StateManager) States(opts ...protocol.StateOption) (uint64, state.Iterator, error) {
+	cfg, err := protocol.CreateStateConfig(opts...)
+	if err != nil {
+		return 0, nil, err
+	}
+	ns, ok := sm.states[cfg.Namespace]
+	if !ok {
+		return sm.height, nil, errors.Wrapf(state.ErrStateNotExist, "namespace %s", cfg.Namespace)
+	}
+	keys := make([]string, 0, len(ns))
+	for k := range ns {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var values [][]byte
+	for _, k := range keys {
+		key, value := []byte(k), ns[k]
+		if len(cfg.MinKey) > 0 && bytes.Compare(key, cfg.MinKey) < 0 {
+			continue
+		}
+		if len(cfg.MaxKey) > 0 && bytes.Compare(key, cfg.MaxKey) > 0 {
+			continue
+		}
+		if cfg.Cond != nil && !cfg.Cond(key, value) {
+			continue
+		}
+		values = append(values, value)
+	}
+	return sm.height, state.NewIterator(values), nil
+}
+
+// ReadView reads the view of the protocol
+func (sm *StateManager) ReadView(name string) (interface{}, error) {
+	return sm.view.Read(name)
+}
+
+// WriteView writes the view of the protocol
+func (sm *StateManager) WriteView(name string, v interface{}) error {
+	return sm.view.Write(name, v)
+}
+
+// PutState writes the state of the key in the namespace
+func (sm *StateManager) PutState(s interface{}, opts ...protocol.StateOption) (uint64, error) {
+	cfg, err := protocol.CreateStateConfig(opts...)
+	if err != nil {
+		return 0, err
+	}
+	ss, ok := s.(state.Serializer)
+	if !ok {
+		return sm.height, errors.New("state is not a serializer")
+	}
+	value, err := ss.Serialize()
+	if err != nil {
+		return sm.height, err
+	}
+	ns, ok := sm.states[cfg.Namespace]
+	if !ok {
+		ns = make(map[string][]byte)
+		sm.states[cfg.Namespace] = ns
+	}
+	ns[string(cfg.Key)] = value
+	return sm.height, nil
+}
+
+// DelState deletes the state of the key in the namespace
+func (sm *StateManager) DelState(opts ...protocol.StateOption) (uint64, error) {
+	cfg, err := protocol.CreateStateConfig(opts...)
+	if err != nil {
+		return 0, err
+	}
+	delete(sm.states[cfg.Namespace], string(cfg.Key))
+	return sm.height, nil
+}
+
+// Snapshot takes a snapshot of the states, and returns the ID to revert to
+func (sm *StateManager) Snapshot() int {
+	sm.snapshots = append(sm.snapshots, copyStates(sm.states))
+	return len(sm.snapshots) - 1
+}
+
+// Revert reverts the states to the snapshot, the snapshots taken afterwards are dropped
+func (sm *StateManager) Revert(snapshot int) error {
+	if snapshot < 0 || snapshot >= len(sm.snapshots) {
+		return errors.Errorf("invalid snapshot %d", snapshot)
+	}
+	sm.states = copyStates(sm.snapshots[snapshot])
+	sm.snapshots = sm.snapshots[:snapshot+1]
+	return nil
+}
+
+func copyStates(states map[string]map[string][]byte) map[string]map[string][]byte {
+	c := make(map[string]map[string][]byte, len(states))
+	for name, ns := range states {
+		cns := make(map[string][]byte, len(ns))
+		for k, v := range ns {
+			cns[k] = v
+		}
+		c[name] = cns
+	}
+	return c
+}
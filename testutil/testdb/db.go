@@ -217,6 +217,8 @@ func NewMockStateManager(ctrl *gomock.Controller) protocol.StateManager {
 			return 0
 		},
 	).AnyTimes()
+	// states are not versioned, Revert() is a no-op
+	sm.EXPECT().Revert(gomock.Any()).Return(nil).AnyTimes()
 
 	return sm
 }
@@ -0,0 +1,242 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+// This is a tool that saves the db of a stopped devnet node as a named snapshot, and resets the node to the genesis
+// or to a saved snapshot in seconds, instead of deleting the data dir and initializing the chain again.
+// To use, run "make build-devreset"
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	glog "log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/pkg/log"
+	"github.com/iotexproject/iotex-core/pkg/util/fileutil"
+)
+
+// genesisSnapshot is the reserved name to reset the node to the genesis
+const genesisSnapshot = "genesis"
+
+var (
+	// snapshotDir is the dir of the saved snapshots, the "snapshots" dir next to the chain db if empty
+	snapshotDir string
+	// save is the name of the snapshot to save the db as
+	save string
+	// reset is the name of the snapshot to reset the db to
+	reset string
+	// list lists the saved snapshots
+	list bool
+)
+
+func init() {
+	flag.StringVar(&snapshotDir, "snapshot-dir", "", "Dir of the saved snapshots, the snapshots dir next to the chain db if empty")
+	flag.StringVar(&save, "save", "", "Name of the snapshot to save the db as")
+	flag.StringVar(&reset, "reset", "", "Name of the snapshot to reset the db to, \""+genesisSnapshot+"\" to reset to the genesis")
+	flag.BoolVar(&list, "list", false, "List the saved snapshots")
+	flag.Usage = func() {
+		_, _ = fmt.Fprintf(os.Stderr,
+			"usage: devreset -config-path=[string]\n -save=[string] | -reset=[string] | -list\n -snapshot-dir=[string]\n")
+		flag.PrintDefaults()
+		os.Exit(2)
+	}
+	flag.Parse()
+}
+
+func main() {
+	ops := 0
+	for _, set := range []bool{save != "", reset != "", list} {
+		if set {
+			ops++
+		}
+	}
+	if ops != 1 {
+		flag.Usage()
+	}
+	cfg, err := config.New()
+	if err != nil {
+		glog.Fatalln("Failed to new config.", zap.Error(err))
+	}
+	if snapshotDir == "" {
+		snapshotDir = filepath.Join(filepath.Dir(cfg.Chain.ChainDBPath), "snapshots")
+	}
+
+	switch {
+	case list:
+		names, err := snapshots()
+		if err != nil {
+			log.L().Fatal("Failed to list the snapshots.", zap.Error(err))
+		}
+		for _, name := range names {
+			fmt.Println(name)
+		}
+	case save != "":
+		if err := saveSnapshot(dbFiles(cfg), save); err != nil {
+			log.L().Fatal("Failed to save the snapshot.", zap.String("name", save), zap.Error(err))
+		}
+		fmt.Printf("Saved the db as snapshot %s in %s.\n", save, snapshotDir)
+	default:
+		if err := resetToSnapshot(dbFiles(cfg), reset); err != nil {
+			log.L().Fatal("Failed to reset the db.", zap.String("name", reset), zap.Error(err))
+		}
+		fmt.Printf("Reset the db to %s, start the node to continue from there.\n", reset)
+	}
+}
+
+// dbFiles returns the db files of the node, which are all saved and reset together
+func dbFiles(cfg config.Config) []string {
+	var files []string
+	for _, file := range []string{
+		cfg.Chain.ChainDBPath,
+		cfg.Chain.TrieDBPath,
+		cfg.Chain.IndexDBPath,
+		cfg.Chain.BloomfilterIndexDBPath,
+		cfg.Chain.CandidateIndexDBPath,
+		cfg.Chain.StakingIndexDBPath,
+		cfg.Chain.BlockStatsIndexDBPath,
+		cfg.Chain.CandidateHistoryIndexDBPath,
+		cfg.Chain.StateAccessIndexDBPath,
+		cfg.Chain.ParticipationDBPath,
+		cfg.Consensus.RollDPoS.ConsensusDBPath,
+		cfg.System.SystemLogDBPath,
+	} {
+		if file != "" {
+			files = append(files, file)
+		}
+	}
+	return files
+}
+
+func snapshots() ([]string, error) {
+	infos, err := ioutil.ReadDir(snapshotDir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, info := range infos {
+		if info.IsDir() && !strings.HasSuffix(info.Name(), ".tmp") {
+			names = append(names, info.Name())
+		}
+	}
+	return names, nil
+}
+
+// saveSnapshot copies the db files into a temp dir, and renames it to the snapshot once all files are copied, so a
+// failed save never leaves a partial snapshot
+func saveSnapshot(files []string, name string) error {
+	if err := validateName(name); err != nil {
+		return err
+	}
+	dir := filepath.Join(snapshotDir, name)
+	if fileutil.FileExists(dir) {
+		return errors.Errorf("snapshot %s already exists", name)
+	}
+	tmp := dir + ".tmp"
+	if err := os.RemoveAll(tmp); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(tmp, 0700); err != nil {
+		return err
+	}
+	for _, file := range files {
+		if !fileutil.FileExists(file) {
+			continue
+		}
+		if err := copyFile(file, filepath.Join(tmp, filepath.Base(file))); err != nil {
+			return err
+		}
+	}
+	return os.Rename(tmp, dir)
+}
+
+// resetToSnapshot stages the db files of the snapshot next to the current ones, and swaps them in by renaming once
+// all of them are staged. The db files not in the snapshot are removed, the node creates them from the genesis or
+// rebuilds them from the chain db at startup
+func resetToSnapshot(files []string, name string) error {
+	dir := ""
+	if name != genesisSnapshot {
+		if err := validateName(name); err != nil {
+			return err
+		}
+		dir = filepath.Join(snapshotDir, name)
+		if !fileutil.FileExists(dir) {
+			return errors.Errorf("snapshot %s does not exist", name)
+		}
+	}
+	staged := make(map[string]string)
+	defer func() {
+		for _, file := range staged {
+			os.Remove(file)
+		}
+	}()
+	for _, file := range files {
+		if dir == "" {
+			// resetting to the genesis removes all db files
+			break
+		}
+		src := filepath.Join(dir, filepath.Base(file))
+		if !fileutil.FileExists(src) {
+			continue
+		}
+		dst := file + ".devreset"
+		if err := os.Remove(dst); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		if err := copyFile(src, dst); err != nil {
+			return err
+		}
+		staged[file] = dst
+	}
+	for _, file := range files {
+		if dst, ok := staged[file]; ok {
+			if err := os.Rename(dst, file); err != nil {
+				return errors.Wrapf(err, "failed to swap in %s", file)
+			}
+			delete(staged, file)
+			continue
+		}
+		if err := os.Remove(file); err != nil && !os.IsNotExist(err) {
+			return errors.Wrapf(err, "failed to remove %s", file)
+		}
+	}
+	return nil
+}
+
+func validateName(name string) error {
+	if name == genesisSnapshot || name == "." || name == ".." || name != filepath.Base(name) || strings.HasSuffix(name, ".tmp") {
+		return errors.Errorf("invalid snapshot name %s", name)
+	}
+	return nil
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return errors.Wrapf(err, "failed to open %s", src)
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create %s", dst)
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return errors.Wrapf(err, "failed to copy %s", src)
+	}
+	return out.Close()
+}
@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/iotexproject/iotex-core/action/protocol/poll"
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/db"
+	"github.com/iotexproject/iotex-core/tools/iomigrater/common"
+)
+
+// Multi-language support
+var (
+	exportCandidatesCmdShorts = map[string]string{
+		"english": "Sub-Command for export IoTeX candidate index db file.",
+		"chinese": "导出IoTeX候选人索引 db 文件的子命令",
+	}
+	exportCandidatesCmdLongs = map[string]string{
+		"english": "Sub-Command for export the candidate and probation lists of all epochs in IoTeX candidate index db file to a file with checksums.",
+		"chinese": "将IoTeX候选人索引 db 文件中所有纪元的候选人和观察名单导出到带校验和的文件的子命令",
+	}
+	exportCandidatesCmdUse = map[string]string{
+		"english": "export-candidates",
+		"chinese": "export-candidates",
+	}
+	importCandidatesCmdShorts = map[string]string{
+		"english": "Sub-Command for import IoTeX candidate index db file.",
+		"chinese": "导入IoTeX候选人索引 db 文件的子命令",
+	}
+	importCandidatesCmdLongs = map[string]string{
+		"english": "Sub-Command for verify the file exported by export-candidates and import it into IoTeX candidate index db file.",
+		"chinese": "校验 export-candidates 导出的文件并将其导入IoTeX候选人索引 db 文件的子命令",
+	}
+	importCandidatesCmdUse = map[string]string{
+		"english": "import-candidates",
+		"chinese": "import-candidates",
+	}
+	candidatesFlagDbFileUse = map[string]string{
+		"english": "The candidate index db file.",
+		"chinese": "候选人索引 db 文件。",
+	}
+	candidatesFlagFileUse = map[string]string{
+		"english": "The file to export to or import from.",
+		"chinese": "导出或导入的文件。",
+	}
+)
+
+var (
+	// ExportCandidates Used to Sub command.
+	ExportCandidates = &cobra.Command{
+		Use:   common.TranslateInLang(exportCandidatesCmdUse),
+		Short: common.TranslateInLang(exportCandidatesCmdShorts),
+		Long:  common.TranslateInLang(exportCandidatesCmdLongs),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return exportCandidates()
+		},
+	}
+
+	// ImportCandidates Used to Sub command.
+	ImportCandidates = &cobra.Command{
+		Use:   common.TranslateInLang(importCandidatesCmdUse),
+		Short: common.TranslateInLang(importCandidatesCmdShorts),
+		Long:  common.TranslateInLang(importCandidatesCmdLongs),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return importCandidates()
+		},
+	}
+)
+
+var (
+	candidateIndexFile = ""
+	candidatesFile     = ""
+)
+
+func init() {
+	for _, c := range []*cobra.Command{ExportCandidates, ImportCandidates} {
+		c.PersistentFlags().StringVarP(&candidateIndexFile, "db-file", "d", config.Default.Chain.CandidateIndexDBPath, common.TranslateInLang(candidatesFlagDbFileUse))
+		c.PersistentFlags().StringVarP(&candidatesFile, "file", "f", "", common.TranslateInLang(candidatesFlagFileUse))
+	}
+}
+
+func exportCandidates() error {
+	if candidatesFile == "" {
+		return fmt.Errorf("--file is empty")
+	}
+	if _, err := os.Stat(candidateIndexFile); err != nil {
+		return fmt.Errorf("Failed to open the candidate index db file: %v", err)
+	}
+	f, err := os.Create(candidatesFile)
+	if err != nil {
+		return fmt.Errorf("Failed to create %s: %v", candidatesFile, err)
+	}
+	defer f.Close()
+
+	var n int
+	if err := withCandidateIndexer(func(indexer *poll.CandidateIndexer) error {
+		n, err = indexer.Export(f)
+		return err
+	}); err != nil {
+		return fmt.Errorf("Failed to export candidate index: %v", err)
+	}
+	if err := f.Sync(); err != nil {
+		return err
+	}
+	fmt.Printf("Export %d entries to %s.\n", n, candidatesFile)
+	return nil
+}
+
+func importCandidates() error {
+	if candidatesFile == "" {
+		return fmt.Errorf("--file is empty")
+	}
+	f, err := os.Open(candidatesFile)
+	if err != nil {
+		return fmt.Errorf("Failed to open %s: %v", candidatesFile, err)
+	}
+	defer f.Close()
+
+	var n int
+	if err := withCandidateIndexer(func(indexer *poll.CandidateIndexer) error {
+		n, err = indexer.Import(f)
+		return err
+	}); err != nil {
+		return fmt.Errorf("Failed to import candidate index: %v", err)
+	}
+	fmt.Printf("Import %d entries from %s.\n", n, candidatesFile)
+	return nil
+}
+
+func withCandidateIndexer(f func(*poll.CandidateIndexer) error) error {
+	cfg := config.Default.DB
+	cfg.DbPath = candidateIndexFile
+	indexer, err := poll.NewCandidateIndexer(db.NewBoltDB(cfg))
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+	if err := indexer.Start(ctx); err != nil {
+		return err
+	}
+	defer indexer.Stop(ctx)
+
+	return f(indexer)
+}
@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/db"
+	"github.com/iotexproject/iotex-core/state/factory"
+	"github.com/iotexproject/iotex-core/tools/iomigrater/common"
+)
+
+// Multi-language support
+var (
+	stateDiffCmdShorts = map[string]string{
+		"english": "Sub-Command for diff the states of IoTeX trie db file at two heights.",
+		"chinese": "比较IoTeX状态 db 文件在两个高度的状态的子命令",
+	}
+	stateDiffCmdLongs = map[string]string{
+		"english": "Sub-Command for output the changed accounts, buckets and contract storage slots between two heights in IoTeX trie db file of the archive mode, one json per line.",
+		"chinese": "输出归档模式的IoTeX状态 db 文件中两个高度之间变化的账户、投票桶和合约存储槽的子命令，每行一个json",
+	}
+	stateDiffCmdUse = map[string]string{
+		"english": "state-diff",
+		"chinese": "state-diff",
+	}
+	stateDiffFlagDbFileUse = map[string]string{
+		"english": "The trie db file of the archive mode.",
+		"chinese": "归档模式的状态 db 文件。",
+	}
+	stateDiffFlagFromUse = map[string]string{
+		"english": "The height to diff from.",
+		"chinese": "比较的起始高度。",
+	}
+	stateDiffFlagToUse = map[string]string{
+		"english": "The height to diff to.",
+		"chinese": "比较的目标高度。",
+	}
+	stateDiffFlagFileUse = map[string]string{
+		"english": "The file to output to, stdout if empty.",
+		"chinese": "输出的文件，为空时输出到标准输出。",
+	}
+)
+
+var (
+	// StateDiff Used to Sub command.
+	StateDiff = &cobra.Command{
+		Use:   common.TranslateInLang(stateDiffCmdUse),
+		Short: common.TranslateInLang(stateDiffCmdShorts),
+		Long:  common.TranslateInLang(stateDiffCmdLongs),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return stateDiff()
+		},
+	}
+)
+
+var (
+	trieDBFile    = ""
+	stateDiffFrom = uint64(0)
+	stateDiffTo   = uint64(0)
+	stateDiffFile = ""
+)
+
+type stateChange struct {
+	Namespace string `json:"namespace"`
+	Key       string `json:"key"`
+	Contract  string `json:"contract,omitempty"`
+	From      string `json:"from,omitempty"`
+	To        string `json:"to,omitempty"`
+}
+
+func init() {
+	StateDiff.PersistentFlags().StringVarP(&trieDBFile, "db-file", "d", config.Default.Chain.TrieDBPath, common.TranslateInLang(stateDiffFlagDbFileUse))
+	StateDiff.PersistentFlags().Uint64VarP(&stateDiffFrom, "from", "", 0, common.TranslateInLang(stateDiffFlagFromUse))
+	StateDiff.PersistentFlags().Uint64VarP(&stateDiffTo, "to", "", 0, common.TranslateInLang(stateDiffFlagToUse))
+	StateDiff.PersistentFlags().StringVarP(&stateDiffFile, "file", "f", "", common.TranslateInLang(stateDiffFlagFileUse))
+}
+
+func stateDiff() error {
+	if _, err := os.Stat(trieDBFile); err != nil {
+		return fmt.Errorf("Failed to open the trie db file: %v", err)
+	}
+	var out io.Writer = os.Stdout
+	if stateDiffFile != "" {
+		f, err := os.Create(stateDiffFile)
+		if err != nil {
+			return fmt.Errorf("Failed to create %s: %v", stateDiffFile, err)
+		}
+		defer f.Close()
+		out = f
+	}
+	w := bufio.NewWriter(out)
+	enc := json.NewEncoder(w)
+
+	cfg := config.Default.DB
+	cfg.DbPath = trieDBFile
+	kv := db.NewBoltDB(cfg)
+	ctx := context.Background()
+	if err := kv.Start(ctx); err != nil {
+		return err
+	}
+	defer kv.Stop(ctx)
+
+	var n int
+	if err := factory.DiffStates(kv, stateDiffFrom, stateDiffTo, factory.StateNamespaces, func(c *factory.StateChange) error {
+		n++
+		return enc.Encode(&stateChange{
+			Namespace: c.Namespace,
+			Key:       hex.EncodeToString(c.Key),
+			Contract:  hex.EncodeToString(c.Contract),
+			From:      hex.EncodeToString(c.From),
+			To:        hex.EncodeToString(c.To),
+		})
+	}); err != nil {
+		return fmt.Errorf("Failed to diff states: %v", err)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Found %d changes between height %d and %d.\n", n, stateDiffFrom, stateDiffTo)
+	return nil
+}
@@ -48,6 +48,9 @@ func Execute() {
 func init() {
 	RootCmd.AddCommand(cmd.CheckHeight)
 	RootCmd.AddCommand(cmd.MigrateDb)
+	RootCmd.AddCommand(cmd.ExportCandidates)
+	RootCmd.AddCommand(cmd.ImportCandidates)
+	RootCmd.AddCommand(cmd.StateDiff)
 
 	RootCmd.HelpFunc()
 }
@@ -0,0 +1,259 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+// This is a tool that forks the chain at a chosen height into a local devnet run by the local keys, keeping all the
+// balances and contracts, to test the protocol changes against the realistic state.
+// To use, run "make build-shadowfork"
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	glog "log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/iotexproject/go-pkgs/crypto"
+	"github.com/iotexproject/iotex-address/address"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v2"
+
+	"github.com/iotexproject/iotex-core/blockchain/blockdao"
+	"github.com/iotexproject/iotex-core/blockchain/genesis"
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/pkg/log"
+	"github.com/iotexproject/iotex-core/pkg/util/fileutil"
+)
+
+var (
+	// forkHeight is the height to fork at, the tip of the chain if 0
+	forkHeight int
+	// outputDir is the dir to write the db and the config of the devnet
+	outputDir string
+	// numProducers is the number of the local block producers
+	numProducers int
+	// votes is the votes of each local delegate
+	votes string
+)
+
+type (
+	producer struct {
+		Address    string `yaml:"address"`
+		PrivateKey string `yaml:"privateKey"`
+	}
+
+	// overlay is merged on top of the config of the source chain by the "-secret-path" flag of the server
+	overlay struct {
+		Network struct {
+			BootstrapNodes []string `yaml:"bootstrapNodes"`
+		} `yaml:"network"`
+		Chain struct {
+			ChainDBPath                 string            `yaml:"chainDBPath"`
+			TrieDBPath                  string            `yaml:"trieDBPath"`
+			IndexDBPath                 string            `yaml:"indexDBPath"`
+			BloomfilterIndexDBPath      string            `yaml:"bloomfilterIndexDBPath"`
+			CandidateIndexDBPath        string            `yaml:"candidateIndexDBPath"`
+			StakingIndexDBPath          string            `yaml:"stakingIndexDBPath"`
+			BlockStatsIndexDBPath       string            `yaml:"blockStatsIndexDBPath"`
+			CandidateHistoryIndexDBPath string            `yaml:"candidateHistoryIndexDBPath"`
+			StateAccessIndexDBPath      string            `yaml:"stateAccessIndexDBPath,omitempty"`
+			ProducerPrivKey             string            `yaml:"producerPrivKey"`
+			ShadowFork                  config.ShadowFork `yaml:"shadowFork"`
+		} `yaml:"chain"`
+	}
+)
+
+func init() {
+	flag.IntVar(&forkHeight, "fork-height", 0, "Height to fork at, the tip of the chain if 0")
+	flag.StringVar(&outputDir, "output-dir", "", "Dir to write the db and the config of the devnet")
+	flag.IntVar(&numProducers, "num-producers", 1, "Number of the local block producers")
+	flag.StringVar(&votes, "votes", "1000000000000000000000000000", "Votes of each local delegate")
+	flag.Usage = func() {
+		_, _ = fmt.Fprintf(os.Stderr,
+			"usage: shadowfork -config-path=[string]\n -output-dir=[string]\n -fork-height=[int]\n -num-producers=[int]\n")
+		flag.PrintDefaults()
+		os.Exit(2)
+	}
+	flag.Parse()
+}
+
+func main() {
+	if outputDir == "" || numProducers <= 0 || forkHeight < 0 {
+		flag.Usage()
+	}
+	genesisCfg, err := genesis.New()
+	if err != nil {
+		glog.Fatalln("Failed to new genesis config.", zap.Error(err))
+	}
+	cfg, err := config.New()
+	if err != nil {
+		glog.Fatalln("Failed to new config.", zap.Error(err))
+	}
+	cfg.Genesis = genesisCfg
+
+	if err := os.MkdirAll(outputDir, 0700); err != nil {
+		log.L().Fatal("Failed to create the output dir.", zap.Error(err))
+	}
+	height, err := forkChainDB(cfg, uint64(forkHeight))
+	if err != nil {
+		log.L().Fatal("Failed to fork the chain db.", zap.Error(err))
+	}
+	producers, err := generateProducers(numProducers)
+	if err != nil {
+		log.L().Fatal("Failed to generate the block producers.", zap.Error(err))
+	}
+	configPath, err := writeOverlay(cfg, height, producers)
+	if err != nil {
+		log.L().Fatal("Failed to write the config of the devnet.", zap.Error(err))
+	}
+	fmt.Printf("Forked the chain at height %d into %s.\n"+
+		"Start the devnet with the genesis and the config of the source chain, and the config of the devnet:\n"+
+		"  server -config-path=<config of the source chain> -genesis-path=<genesis> -secret-path=%s\n"+
+		"The keys of all %d producers are in %s, start a node with each of the other keys to run the devnet with "+
+		"multiple nodes.\n", height, outputDir, configPath, len(producers), filepath.Join(outputDir, "producers.yaml"))
+}
+
+// forkChainDB copies the chain db into the output dir and truncates it to the fork height. The state and the indexes
+// are copied too if forking at the tip, otherwise they are rebuilt by replaying the blocks when the devnet starts
+func forkChainDB(cfg config.Config, height uint64) (uint64, error) {
+	src := cfg.Chain.ChainDBPath
+	files, err := filepath.Glob(strings.TrimSuffix(src, filepath.Ext(src)) + "-*" + filepath.Ext(src))
+	if err != nil {
+		return 0, err
+	}
+	for _, file := range append(files, src) {
+		if err := copyFile(file, filepath.Join(outputDir, filepath.Base(file))); err != nil {
+			return 0, err
+		}
+	}
+
+	dbCfg := cfg.DB
+	dbCfg.DbPath = filepath.Join(outputDir, filepath.Base(src))
+	// no indexer is attached, as the state factory does not support deleting blocks
+	dao := blockdao.NewBlockDAO(nil, dbCfg)
+	ctx := context.Background()
+	if err := dao.Start(ctx); err != nil {
+		return 0, errors.Wrap(err, "failed to start the forked chain db")
+	}
+	defer func() {
+		if err := dao.Stop(ctx); err != nil {
+			log.L().Error("Failed to stop the forked chain db.", zap.Error(err))
+		}
+	}()
+	tip, err := dao.Height()
+	if err != nil {
+		return 0, err
+	}
+	if height == 0 || height == tip {
+		for _, file := range []string{
+			cfg.Chain.TrieDBPath,
+			cfg.Chain.IndexDBPath,
+			cfg.Chain.BloomfilterIndexDBPath,
+			cfg.Chain.CandidateIndexDBPath,
+			cfg.Chain.StakingIndexDBPath,
+			cfg.Chain.BlockStatsIndexDBPath,
+			cfg.Chain.CandidateHistoryIndexDBPath,
+			cfg.Chain.StateAccessIndexDBPath,
+		} {
+			if file == "" || !fileutil.FileExists(file) {
+				continue
+			}
+			if err := copyFile(file, filepath.Join(outputDir, filepath.Base(file))); err != nil {
+				return 0, err
+			}
+		}
+		return tip, nil
+	}
+	if height > tip {
+		return 0, errors.Errorf("fork height %d is higher than the tip %d", height, tip)
+	}
+	if err := dao.DeleteBlockToTarget(height); err != nil {
+		return 0, errors.Wrapf(err, "failed to truncate the chain db to height %d", height)
+	}
+	log.L().Warn("The state is rebuilt from the genesis when the devnet starts, which may take long.",
+		zap.Uint64("forkHeight", height))
+	return height, nil
+}
+
+func generateProducers(n int) ([]producer, error) {
+	producers := make([]producer, 0, n)
+	for i := 0; i < n; i++ {
+		sk, err := crypto.GenerateKey()
+		if err != nil {
+			return nil, err
+		}
+		addr, err := address.FromBytes(sk.PublicKey().Hash())
+		if err != nil {
+			return nil, err
+		}
+		producers = append(producers, producer{
+			Address:    addr.String(),
+			PrivateKey: sk.HexString(),
+		})
+	}
+	return producers, nil
+}
+
+// writeOverlay writes the config of the devnet and the keys of the producers, and returns the path of the config
+func writeOverlay(cfg config.Config, height uint64, producers []producer) (string, error) {
+	o := overlay{}
+	o.Network.BootstrapNodes = []string{}
+	o.Chain.ChainDBPath = filepath.Join(outputDir, filepath.Base(cfg.Chain.ChainDBPath))
+	o.Chain.TrieDBPath = filepath.Join(outputDir, filepath.Base(cfg.Chain.TrieDBPath))
+	o.Chain.IndexDBPath = filepath.Join(outputDir, filepath.Base(cfg.Chain.IndexDBPath))
+	o.Chain.BloomfilterIndexDBPath = filepath.Join(outputDir, filepath.Base(cfg.Chain.BloomfilterIndexDBPath))
+	o.Chain.CandidateIndexDBPath = filepath.Join(outputDir, filepath.Base(cfg.Chain.CandidateIndexDBPath))
+	o.Chain.StakingIndexDBPath = filepath.Join(outputDir, filepath.Base(cfg.Chain.StakingIndexDBPath))
+	o.Chain.BlockStatsIndexDBPath = filepath.Join(outputDir, filepath.Base(cfg.Chain.BlockStatsIndexDBPath))
+	o.Chain.CandidateHistoryIndexDBPath = filepath.Join(outputDir, filepath.Base(cfg.Chain.CandidateHistoryIndexDBPath))
+	if cfg.Chain.StateAccessIndexDBPath != "" {
+		o.Chain.StateAccessIndexDBPath = filepath.Join(outputDir, filepath.Base(cfg.Chain.StateAccessIndexDBPath))
+	}
+	o.Chain.ProducerPrivKey = producers[0].PrivateKey
+	o.Chain.ShadowFork.Height = height
+	for _, p := range producers {
+		o.Chain.ShadowFork.Delegates = append(o.Chain.ShadowFork.Delegates, genesis.Delegate{
+			OperatorAddrStr: p.Address,
+			RewardAddrStr:   p.Address,
+			VotesStr:        votes,
+		})
+	}
+	configPath := filepath.Join(outputDir, "config.yaml")
+	if err := writeYaml(configPath, &o); err != nil {
+		return "", err
+	}
+	return configPath, writeYaml(filepath.Join(outputDir, "producers.yaml"), producers)
+}
+
+func writeYaml(path string, v interface{}) error {
+	out, err := yaml.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, out, 0600)
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return errors.Wrapf(err, "failed to open %s", src)
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create %s", dst)
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return errors.Wrapf(err, "failed to copy %s", src)
+	}
+	return out.Close()
+}
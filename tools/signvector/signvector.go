@@ -0,0 +1,73 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+// This is a tool to generate the signing test vectors of actions in json, for sdks to validate their compatibility
+// To use, run "make build-signvector" and "./bin/signvector -output vectors.json",
+// or "./bin/signvector -check vectors.json" to check the vectors against this version
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"go.uber.org/zap"
+
+	"github.com/iotexproject/iotex-core/pkg/log"
+	"github.com/iotexproject/iotex-core/tools/signvector/vector"
+)
+
+func main() {
+	var (
+		output string
+		check  string
+	)
+	flag.StringVar(&output, "output", "", "file to write the vectors to, stdout if not set")
+	flag.StringVar(&check, "check", "", "file of the vectors to check")
+	flag.Parse()
+
+	if check != "" {
+		data, err := ioutil.ReadFile(check)
+		if err != nil {
+			log.L().Fatal("Failed to read vectors.", zap.Error(err))
+		}
+		var vectors []*vector.Vector
+		if err := json.Unmarshal(data, &vectors); err != nil {
+			log.L().Fatal("Failed to decode vectors.", zap.Error(err))
+		}
+		failed := 0
+		for _, vec := range vectors {
+			if err := vector.Check(vec); err != nil {
+				log.L().Error("Vector mismatch.", zap.String("name", vec.Name), zap.Error(err))
+				failed++
+			}
+		}
+		if failed > 0 {
+			os.Exit(1)
+		}
+		fmt.Printf("%d vectors checked\n", len(vectors))
+		return
+	}
+
+	vectors, err := vector.Generate()
+	if err != nil {
+		log.L().Fatal("Failed to generate vectors.", zap.Error(err))
+	}
+	data, err := json.MarshalIndent(vectors, "", "  ")
+	if err != nil {
+		log.L().Fatal("Failed to encode vectors.", zap.Error(err))
+	}
+	data = append(data, '\n')
+	if output == "" {
+		fmt.Print(string(data))
+		return
+	}
+	if err := ioutil.WriteFile(output, data, 0644); err != nil {
+		log.L().Fatal("Failed to write vectors.", zap.Error(err))
+	}
+}
@@ -0,0 +1,275 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package vector
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+
+	"github.com/iotexproject/go-pkgs/crypto"
+	"github.com/iotexproject/go-pkgs/hash"
+	"github.com/iotexproject/iotex-address/address"
+	"github.com/iotexproject/iotex-proto/golang/iotextypes"
+
+	"github.com/iotexproject/iotex-core/action"
+	"github.com/iotexproject/iotex-core/pkg/version"
+	"github.com/iotexproject/iotex-core/state"
+)
+
+// Versions are the envelope versions the vectors are generated for
+var Versions = []uint32{version.ProtocolVersion}
+
+// Vector is a signing test vector of an action
+type Vector struct {
+	Name    string `json:"name"`
+	Type    string `json:"type"`
+	Version uint32 `json:"version"`
+	// PrivateKey and PublicKey are the hex encoded keys of the signer
+	PrivateKey string `json:"privateKey"`
+	PublicKey  string `json:"publicKey"`
+	// Core is the action core in protobuf json, as the input to sdks
+	Core json.RawMessage `json:"core"`
+	// Envelope is the serialized action core, whose hash is signed
+	Envelope    string `json:"envelope"`
+	SigningHash string `json:"signingHash"`
+	Signature   string `json:"signature"`
+	// Action is the serialized signed action, whose hash is the action hash
+	Action     string `json:"action"`
+	ActionHash string `json:"actionHash"`
+	// Sender is the address recovered from the signing hash and signature
+	Sender string `json:"sender"`
+}
+
+type (
+	// payload is the method set of the action payloads accepted by the envelope builder
+	payload interface {
+		action.Action
+		Serialize() []byte
+		Cost() (*big.Int, error)
+		IntrinsicGas() (uint64, error)
+	}
+
+	testCase struct {
+		name    string
+		payload func(nonce uint64) (payload, error)
+	}
+)
+
+// Generate generates the signing test vectors of every action type and envelope version, the keys and actions are
+// fixed so that the vectors are the same every time
+func Generate() ([]*Vector, error) {
+	var vectors []*Vector
+	for _, v := range Versions {
+		for i, c := range testCases() {
+			sk, err := signer(i)
+			if err != nil {
+				return nil, err
+			}
+			nonce := uint64(i + 1)
+			act, err := c.payload(nonce)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to create %s", c.name)
+			}
+			vec, err := newVector(c.name, v, nonce, act, sk)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to generate vector of %s", c.name)
+			}
+			vectors = append(vectors, vec)
+		}
+	}
+	return vectors, nil
+}
+
+// Check checks the vector against the signing of this node, that is the envelope hashes to the signing hash, the
+// signature is valid and recovers the sender, and the signed action hashes to the action hash
+func Check(vec *Vector) error {
+	envelope, err := hex.DecodeString(vec.Envelope)
+	if err != nil {
+		return errors.Wrap(err, "invalid envelope")
+	}
+	signingHash := hash.Hash256b(envelope)
+	if hex.EncodeToString(signingHash[:]) != vec.SigningHash {
+		return errors.Errorf("signing hash mismatch, expecting %s, got %x", vec.SigningHash, signingHash)
+	}
+	actBytes, err := hex.DecodeString(vec.Action)
+	if err != nil {
+		return errors.Wrap(err, "invalid action")
+	}
+	actHash := hash.Hash256b(actBytes)
+	if hex.EncodeToString(actHash[:]) != vec.ActionHash {
+		return errors.Errorf("action hash mismatch, expecting %s, got %x", vec.ActionHash, actHash)
+	}
+	sig, err := hex.DecodeString(vec.Signature)
+	if err != nil {
+		return errors.Wrap(err, "invalid signature")
+	}
+	pk, err := crypto.RecoverPubkey(signingHash[:], sig)
+	if err != nil {
+		return errors.Wrap(err, "failed to recover public key")
+	}
+	if pk.HexString() != vec.PublicKey {
+		return errors.Errorf("public key mismatch, expecting %s, got %s", vec.PublicKey, pk.HexString())
+	}
+	sender, err := address.FromBytes(pk.Hash())
+	if err != nil {
+		return err
+	}
+	if sender.String() != vec.Sender {
+		return errors.Errorf("sender mismatch, expecting %s, got %s", vec.Sender, sender.String())
+	}
+
+	// the signed action decodes to the same envelope and verifies
+	pb := &iotextypes.Action{}
+	if err := proto.Unmarshal(actBytes, pb); err != nil {
+		return errors.Wrap(err, "failed to unmarshal action")
+	}
+	selp := action.SealedEnvelope{}
+	if err := selp.LoadProto(pb); err != nil {
+		return errors.Wrap(err, "failed to load action")
+	}
+	if h := selp.Envelope.Hash(); h != signingHash {
+		return errors.Errorf("envelope of action mismatch, expecting %x, got %x", signingHash, h)
+	}
+	return action.Verify(selp)
+}
+
+func newVector(name string, v uint32, nonce uint64, act payload, sk crypto.PrivateKey) (*Vector, error) {
+	elp := (&action.EnvelopeBuilder{}).SetVersion(v).
+		SetNonce(nonce).
+		SetGasLimit(gasLimit(act)).
+		SetGasPrice(big.NewInt(1000000000000)).
+		SetAction(act).Build()
+	selp, err := action.Sign(elp, sk)
+	if err != nil {
+		return nil, err
+	}
+	core, err := (&jsonpb.Marshaler{}).MarshalToString(elp.Proto())
+	if err != nil {
+		return nil, err
+	}
+	actBytes, err := proto.Marshal(selp.Proto())
+	if err != nil {
+		return nil, err
+	}
+	sender, err := address.FromBytes(sk.PublicKey().Hash())
+	if err != nil {
+		return nil, err
+	}
+	signingHash := elp.Hash()
+	actHash := selp.Hash()
+	return &Vector{
+		Name:        fmt.Sprintf("%s/v%d", name, v),
+		Type:        strings.TrimPrefix(fmt.Sprintf("%T", act), "*action."),
+		Version:     v,
+		PrivateKey:  sk.HexString(),
+		PublicKey:   sk.PublicKey().HexString(),
+		Core:        json.RawMessage(core),
+		Envelope:    hex.EncodeToString(elp.Serialize()),
+		SigningHash: hex.EncodeToString(signingHash[:]),
+		Signature:   hex.EncodeToString(selp.Signature()),
+		Action:      hex.EncodeToString(actBytes),
+		ActionHash:  hex.EncodeToString(actHash[:]),
+		Sender:      sender.String(),
+	}, nil
+}
+
+// signer derives the i-th private key from a fixed seed
+func signer(i int) (crypto.PrivateKey, error) {
+	h := hash.Hash256b([]byte(fmt.Sprintf("iotex signing test vector %d", i)))
+	return crypto.HexStringToPrivateKey(hex.EncodeToString(h[:]))
+}
+
+func recipient(i int) string {
+	h := hash.Hash160b([]byte(fmt.Sprintf("iotex signing test vector recipient %d", i)))
+	addr, _ := address.FromBytes(h[:])
+	return addr.String()
+}
+
+func gasLimit(act payload) uint64 {
+	if _, ok := act.(*action.PutPollResult); ok {
+		return 0
+	}
+	return 1000000
+}
+
+func testCases() []testCase {
+	amount, _ := new(big.Int).SetString("1234567890000000000", 10)
+	return []testCase{
+		{"transfer", func(n uint64) (payload, error) {
+			return action.NewTransfer(n, amount, recipient(0), nil, 0, nil)
+		}},
+		{"transferWithPayload", func(n uint64) (payload, error) {
+			return action.NewTransfer(n, big.NewInt(0), recipient(1), []byte("hello iotex"), 0, nil)
+		}},
+		{"execution", func(n uint64) (payload, error) {
+			data, _ := hex.DecodeString("a9059cbb000000000000000000000000" +
+				"0000000000000000000000000000000000000000" +
+				"00000000000000000000000000000000000000000000000000000000000003e8")
+			return action.NewExecution(recipient(2), n, big.NewInt(0), 0, nil, data)
+		}},
+		{"deployment", func(n uint64) (payload, error) {
+			data, _ := hex.DecodeString("6080604052348015600f57600080fd5b50603f80601d6000396000f3fe")
+			return action.NewExecution(action.EmptyAddress, n, amount, 0, nil, data)
+		}},
+		{"grantReward", func(n uint64) (payload, error) {
+			gb := action.GrantRewardBuilder{}
+			act := gb.SetRewardType(action.BlockReward).SetHeight(100).Build()
+			return &act, nil
+		}},
+		{"claimFromRewardingFund", func(n uint64) (payload, error) {
+			cb := action.ClaimFromRewardingFundBuilder{}
+			act := cb.SetAmount(amount).SetData([]byte("claim")).Build()
+			return &act, nil
+		}},
+		{"depositToRewardingFund", func(n uint64) (payload, error) {
+			db := action.DepositToRewardingFundBuilder{}
+			act := db.SetAmount(amount).SetData([]byte("deposit")).Build()
+			return &act, nil
+		}},
+		{"putPollResult", func(n uint64) (payload, error) {
+			return action.NewPutPollResult(n, 720, state.CandidateList{
+				{Address: recipient(3), Votes: amount, RewardAddress: recipient(4)},
+				{Address: recipient(5), Votes: big.NewInt(1), RewardAddress: recipient(6)},
+			}), nil
+		}},
+		{"stakeCreate", func(n uint64) (payload, error) {
+			return action.NewCreateStake(n, "robotbp00001", amount.String(), 91, true, []byte("create"), 0, nil)
+		}},
+		{"stakeUnstake", func(n uint64) (payload, error) {
+			return action.NewUnstake(n, 7, []byte("unstake"), 0, nil)
+		}},
+		{"stakeWithdraw", func(n uint64) (payload, error) {
+			return action.NewWithdrawStake(n, 7, nil, 0, nil)
+		}},
+		{"stakeAddDeposit", func(n uint64) (payload, error) {
+			return action.NewDepositToStake(n, 7, amount.String(), nil, 0, nil)
+		}},
+		{"stakeRestake", func(n uint64) (payload, error) {
+			return action.NewRestake(n, 7, 14, false, nil, 0, nil)
+		}},
+		{"stakeChangeCandidate", func(n uint64) (payload, error) {
+			return action.NewChangeCandidate(n, "robotbp00002", 7, nil, 0, nil)
+		}},
+		{"stakeTransferOwnership", func(n uint64) (payload, error) {
+			return action.NewTransferStake(n, recipient(7), 7, nil, 0, nil)
+		}},
+		{"candidateRegister", func(n uint64) (payload, error) {
+			return action.NewCandidateRegister(n, "robotbp00003", recipient(8), recipient(9), recipient(10),
+				"1200000000000000000000000", 91, true, nil, 0, nil)
+		}},
+		{"candidateUpdate", func(n uint64) (payload, error) {
+			return action.NewCandidateUpdate(n, "robotbp00003", recipient(11), recipient(12), 0, nil)
+		}},
+	}
+}
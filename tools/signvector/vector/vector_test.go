@@ -0,0 +1,52 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package vector
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerate(t *testing.T) {
+	require := require.New(t)
+
+	vectors, err := Generate()
+	require.NoError(err)
+	require.Len(vectors, len(Versions)*len(testCases()))
+	types := make(map[string]bool)
+	for _, vec := range vectors {
+		require.NoError(Check(vec), vec.Name)
+		types[vec.Type] = true
+	}
+	// every action type supported by the envelope is covered
+	require.Len(types, 15)
+
+	// vectors are deterministic and survive json round trip
+	again, err := Generate()
+	require.NoError(err)
+	data, err := json.Marshal(vectors)
+	require.NoError(err)
+	dataAgain, err := json.Marshal(again)
+	require.NoError(err)
+	require.Equal(data, dataAgain)
+	var decoded []*Vector
+	require.NoError(json.Unmarshal(data, &decoded))
+	require.Equal(vectors, decoded)
+
+	// tampering fails the check
+	vec := *vectors[0]
+	vec.Signature = vectors[1].Signature
+	require.Error(Check(&vec))
+	vec = *vectors[0]
+	vec.Sender = vectors[1].Sender
+	require.Error(Check(&vec))
+	vec = *vectors[0]
+	vec.Envelope = vectors[1].Envelope
+	require.Error(Check(&vec))
+}
@@ -0,0 +1,118 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package updater
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"reflect"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/iotexproject/go-pkgs/crypto"
+	"github.com/iotexproject/go-pkgs/hash"
+
+	"github.com/iotexproject/iotex-core/blockchain/genesis"
+)
+
+// ErrInvalidSignature indicates the release manifest is not signed by the release key
+var ErrInvalidSignature = errors.New("invalid release manifest signature")
+
+type (
+	// Release describes a release of the node
+	Release struct {
+		// Version is the version of the release, in the same format as the version of the build
+		Version string `json:"version"`
+		// Activations are the activation heights the release supports
+		Activations []*Activation `json:"activations"`
+		// Binaries are the binaries of the release, keyed by <GOOS>-<GOARCH>
+		Binaries map[string]*Binary `json:"binaries"`
+	}
+
+	// Activation is an activation height of a hard fork
+	Activation struct {
+		// Name is the yaml key of the height in genesis blockchain config, e.g. "icelandHeight"
+		Name   string `json:"name"`
+		Height uint64 `json:"height"`
+	}
+
+	// Binary is a downloadable binary
+	Binary struct {
+		URL    string `json:"url"`
+		SHA256 string `json:"sha256"`
+	}
+
+	// SignedManifest is the release manifest signed by the release key. The signature is over the exact bytes of
+	// the release, so that no canonical json encoding is needed
+	SignedManifest struct {
+		Release   json.RawMessage `json:"release"`
+		Signature string          `json:"signature"`
+	}
+)
+
+// SignManifest signs the release with the release key
+func SignManifest(release *Release, sk crypto.PrivateKey) (*SignedManifest, error) {
+	data, err := json.Marshal(release)
+	if err != nil {
+		return nil, err
+	}
+	h := hash.Hash256b(data)
+	sig, err := sk.Sign(h[:])
+	if err != nil {
+		return nil, err
+	}
+	return &SignedManifest{
+		Release:   data,
+		Signature: hex.EncodeToString(sig),
+	}, nil
+}
+
+// Verify verifies the signature and returns the release
+func (m *SignedManifest) Verify(pk crypto.PublicKey) (*Release, error) {
+	sig, err := hex.DecodeString(m.Signature)
+	if err != nil {
+		return nil, errors.Wrap(ErrInvalidSignature, err.Error())
+	}
+	h := hash.Hash256b(m.Release)
+	if !pk.Verify(h[:], sig) {
+		return nil, ErrInvalidSignature
+	}
+	release := &Release{}
+	if err := json.Unmarshal(m.Release, release); err != nil {
+		return nil, errors.Wrap(err, "failed to decode release")
+	}
+	return release, nil
+}
+
+// MissingActivations returns the activations of the release which the genesis doesn't have or has at a different
+// height
+func (r *Release) MissingActivations(g genesis.Blockchain) []*Activation {
+	heights := activationHeights(g)
+	var missing []*Activation
+	for _, a := range r.Activations {
+		if h, ok := heights[a.Name]; !ok || h != a.Height {
+			missing = append(missing, a)
+		}
+	}
+	return missing
+}
+
+// activationHeights returns the activation heights in genesis keyed by the yaml key
+func activationHeights(g genesis.Blockchain) map[string]uint64 {
+	heights := make(map[string]uint64)
+	v := reflect.ValueOf(g)
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		name := t.Field(i).Tag.Get("yaml")
+		if !strings.HasSuffix(name, "Height") || t.Field(i).Type.Kind() != reflect.Uint64 {
+			continue
+		}
+		heights[name] = v.Field(i).Uint()
+	}
+	return heights
+}
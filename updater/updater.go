@@ -0,0 +1,223 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package updater
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+
+	"github.com/iotexproject/go-pkgs/crypto"
+
+	"github.com/iotexproject/iotex-core/blockchain/genesis"
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/pkg/log"
+	"github.com/iotexproject/iotex-core/pkg/version"
+)
+
+// _maxManifestSize is the max size of the release manifest
+const _maxManifestSize = 1 << 20
+
+var missingActivationMtc = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "iotex_updater_missing_activation",
+		Help: "Upcoming activation heights of the latest release missing in local genesis",
+	},
+	[]string{"name"},
+)
+
+func init() {
+	prometheus.MustRegister(missingActivationMtc)
+}
+
+type (
+	// Updater checks the signed release manifest, and warns if the local genesis misses an upcoming activation
+	// height of the latest release. If enabled, it downloads the binary of the release, and swaps the running
+	// executable at a safe height before the earliest missing activation, then exits for the supervisor to restart
+	// the node with the new binary
+	Updater struct {
+		cfg        config.Updater
+		genesis    genesis.Blockchain
+		tipHeight  func() uint64
+		exit       func()
+		pk         crypto.PublicKey
+		client     *http.Client
+		executable string
+		staged     *stagedRelease
+	}
+
+	stagedRelease struct {
+		version    string
+		path       string
+		swapHeight uint64
+	}
+)
+
+// NewUpdater creates a new updater, exit is called after the executable is swapped
+func NewUpdater(cfg config.Updater, g genesis.Blockchain, tipHeight func() uint64, exit func()) (*Updater, error) {
+	pk, err := crypto.HexStringToPublicKey(cfg.PublicKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid release public key")
+	}
+	executable, err := os.Executable()
+	if err != nil {
+		return nil, err
+	}
+	if executable, err = filepath.EvalSymlinks(executable); err != nil {
+		return nil, err
+	}
+	return &Updater{
+		cfg:        cfg,
+		genesis:    g,
+		tipHeight:  tipHeight,
+		exit:       exit,
+		pk:         pk,
+		client:     &http.Client{Timeout: cfg.Timeout},
+		executable: executable,
+	}, nil
+}
+
+// Check checks the latest release, it's called periodically
+func (u *Updater) Check() {
+	if err := u.check(); err != nil {
+		log.L().Error("Failed to check release.", zap.Error(err))
+	}
+}
+
+func (u *Updater) check() error {
+	release, err := u.fetchRelease()
+	if err != nil {
+		return err
+	}
+	tip := u.tipHeight()
+	var (
+		upcoming []*Activation
+		earliest uint64
+	)
+	missingActivationMtc.Reset()
+	for _, a := range release.MissingActivations(u.genesis) {
+		if a.Height <= tip {
+			continue
+		}
+		upcoming = append(upcoming, a)
+		missingActivationMtc.WithLabelValues(a.Name).Set(float64(a.Height))
+		log.L().Warn("Local genesis lacks an upcoming activation height, please upgrade.",
+			zap.String("name", a.Name),
+			zap.Uint64("height", a.Height),
+			zap.Uint64("tipHeight", tip),
+			zap.String("localVersion", version.PackageVersion),
+			zap.String("releaseVersion", release.Version))
+		if earliest == 0 || a.Height < earliest {
+			earliest = a.Height
+		}
+	}
+	if len(upcoming) == 0 || !u.cfg.StageBinary || release.Version == version.PackageVersion {
+		return nil
+	}
+	if u.staged == nil || u.staged.version != release.Version {
+		if err := u.stage(release); err != nil {
+			return errors.Wrapf(err, "failed to stage release %s", release.Version)
+		}
+	}
+	if earliest > u.cfg.SwapLeadBlocks {
+		u.staged.swapHeight = earliest - u.cfg.SwapLeadBlocks
+	} else {
+		u.staged.swapHeight = 0
+	}
+	if tip < u.staged.swapHeight {
+		log.L().Info("Release staged.",
+			zap.String("version", u.staged.version),
+			zap.Uint64("swapHeight", u.staged.swapHeight))
+		return nil
+	}
+	return u.swap()
+}
+
+func (u *Updater) fetchRelease() (*Release, error) {
+	resp, err := u.client.Get(u.cfg.ManifestURL)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch release manifest")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("failed to fetch release manifest, status %s", resp.Status)
+	}
+	m := &SignedManifest{}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, _maxManifestSize)).Decode(m); err != nil {
+		return nil, errors.Wrap(err, "failed to decode release manifest")
+	}
+	return m.Verify(u.pk)
+}
+
+// stage downloads the binary of the release next to the executable and verifies its checksum
+func (u *Updater) stage(release *Release) error {
+	bin, ok := release.Binaries[runtime.GOOS+"-"+runtime.GOARCH]
+	if !ok {
+		return errors.Errorf("no binary for %s-%s", runtime.GOOS, runtime.GOARCH)
+	}
+	resp, err := u.client.Get(bin.URL)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("failed to download %s, status %s", bin.URL, resp.Status)
+	}
+	f, err := ioutil.TempFile(filepath.Dir(u.executable), ".staged-")
+	if err != nil {
+		return err
+	}
+	h := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(f, h), resp.Body); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	if hex.EncodeToString(h.Sum(nil)) != bin.SHA256 {
+		os.Remove(f.Name())
+		return errors.Errorf("checksum of %s mismatches", bin.URL)
+	}
+	if err := os.Chmod(f.Name(), 0755); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	if u.staged != nil {
+		os.Remove(u.staged.path)
+	}
+	u.staged = &stagedRelease{
+		version: release.Version,
+		path:    f.Name(),
+	}
+	return nil
+}
+
+// swap replaces the executable with the staged binary and exits
+func (u *Updater) swap() error {
+	if err := os.Rename(u.staged.path, u.executable); err != nil {
+		return errors.Wrap(err, "failed to swap executable")
+	}
+	log.L().Warn("Executable swapped, exiting to restart with the new release.",
+		zap.String("version", u.staged.version),
+		zap.Uint64("tipHeight", u.tipHeight()))
+	u.staged = nil
+	u.exit()
+	return nil
+}
@@ -0,0 +1,133 @@
+// Copyright (c) 2021 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package updater
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/blockchain/genesis"
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/test/identityset"
+	"github.com/iotexproject/iotex-core/testutil"
+)
+
+func TestSignedManifest(t *testing.T) {
+	require := require.New(t)
+
+	release := &Release{
+		Version: "v1.3.0",
+		Activations: []*Activation{
+			{Name: "icelandHeight", Height: genesis.Default.IcelandBlockHeight},
+			{Name: "jutlandHeight", Height: 13000000},
+			{Name: "hawaiiHeight", Height: 1},
+		},
+	}
+	m, err := SignManifest(release, identityset.PrivateKey(0))
+	require.NoError(err)
+	r, err := m.Verify(identityset.PrivateKey(0).PublicKey())
+	require.NoError(err)
+	require.Equal(release, r)
+	_, err = m.Verify(identityset.PrivateKey(1).PublicKey())
+	require.Equal(ErrInvalidSignature, errors.Cause(err))
+	m.Release = []byte(`{"version":"v1.3.1"}`)
+	_, err = m.Verify(identityset.PrivateKey(0).PublicKey())
+	require.Equal(ErrInvalidSignature, errors.Cause(err))
+
+	missing := release.MissingActivations(genesis.Default.Blockchain)
+	require.Equal(2, len(missing))
+	require.Equal("jutlandHeight", missing[0].Name)
+	require.Equal("hawaiiHeight", missing[1].Name)
+}
+
+func TestUpdater(t *testing.T) {
+	require := require.New(t)
+
+	binary := []byte("new binary")
+	checksum := sha256.Sum256(binary)
+	release := &Release{
+		Version:     "v1.3.0",
+		Activations: []*Activation{{Name: "jutlandHeight", Height: 13000000}},
+		Binaries: map[string]*Binary{
+			runtime.GOOS + "-" + runtime.GOARCH: {SHA256: hex.EncodeToString(checksum[:])},
+		},
+	}
+	var svr *httptest.Server
+	svr = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/manifest.json":
+			release.Binaries[runtime.GOOS+"-"+runtime.GOARCH].URL = svr.URL + "/server"
+			m, err := SignManifest(release, identityset.PrivateKey(0))
+			require.NoError(err)
+			require.NoError(json.NewEncoder(w).Encode(m))
+		case "/server":
+			_, _ = w.Write(binary)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer svr.Close()
+
+	executable, err := testutil.PathOfTempFile("updater")
+	require.NoError(err)
+	defer testutil.CleanupPath(t, executable)
+
+	cfg := config.Default.Updater
+	cfg.ManifestURL = svr.URL + "/manifest.json"
+	cfg.PublicKey = identityset.PrivateKey(0).PublicKey().HexString()
+	cfg.StageBinary = true
+	var (
+		tip    uint64 = 12000000
+		exited bool
+	)
+	u, err := NewUpdater(cfg, genesis.Default.Blockchain, func() uint64 { return tip }, func() { exited = true })
+	require.NoError(err)
+	u.executable = executable
+
+	// staged but not swapped
+	require.NoError(u.check())
+	require.NotNil(u.staged)
+	require.Equal(uint64(13000000-cfg.SwapLeadBlocks), u.staged.swapHeight)
+	staged, err := ioutil.ReadFile(u.staged.path)
+	require.NoError(err)
+	require.Equal(binary, staged)
+	require.False(exited)
+
+	// swapped at safe height
+	tip = 13000000 - cfg.SwapLeadBlocks
+	require.NoError(u.check())
+	require.True(exited)
+	require.Nil(u.staged)
+	swapped, err := ioutil.ReadFile(executable)
+	require.NoError(err)
+	require.Equal(binary, swapped)
+
+	// corrupted binary is not staged
+	u.staged, exited = nil, false
+	tip = 12000000
+	binary = []byte("corrupted")
+	require.Error(u.check())
+	require.Nil(u.staged)
+
+	// activation passed
+	tip = 13000001
+	require.NoError(u.check())
+	require.False(exited)
+
+	cfg.PublicKey = "invalid"
+	_, err = NewUpdater(cfg, genesis.Default.Blockchain, func() uint64 { return tip }, func() {})
+	require.Error(err)
+}